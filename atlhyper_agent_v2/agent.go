@@ -43,6 +43,7 @@ var log = logger.Module("Agent")
 // 封装调度器，提供启动/运行/停止接口
 type Agent struct {
 	scheduler *scheduler.Scheduler
+	k8sClient sdkpkg.K8sClient
 	chClient  sdkpkg.ClickHouseClient // 可选，nil 时不启动
 }
 
@@ -82,7 +83,17 @@ func New() (*Agent, error) {
 	}
 
 	// 2. 初始化 Gateway (Master 通信)
-	masterGw := gateway.NewMasterGateway(cfg.Master.URL, cfg.Agent.ClusterID, cfg.Timeout.HTTPClient)
+	masterGw, err := gateway.NewMasterGateway(cfg.Master.URL, cfg.Agent.ClusterID, cfg.Timeout.HTTPClient, gateway.SecurityConfig{
+		Token:         cfg.Master.Token,
+		TokenFile:     cfg.Master.TokenFile,
+		TLSCAFile:     cfg.Master.TLSCAFile,
+		TLSCertFile:   cfg.Master.TLSCertFile,
+		TLSKeyFile:    cfg.Master.TLSKeyFile,
+		TLSServerName: cfg.Master.TLSServerName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to init master gateway: %w", err)
+	}
 
 	// 3. 初始化 Repository (数据访问层)
 	repos := initRepositories(k8sClient)
@@ -124,7 +135,7 @@ func New() (*Agent, error) {
 		repos.statefulSet, repos.daemonSet, repos.replicaSet,
 		repos.service, repos.ingress, repos.configMap,
 		repos.secret, repos.namespace, repos.event,
-		repos.job, repos.cronJob, repos.pv, repos.pvc,
+		repos.job, repos.cronJob, repos.hpa, repos.pv, repos.pvc,
 		repos.resourceQuota, repos.limitRange,
 		repos.networkPolicy, repos.serviceAccount,
 		otelSummaryRepo,
@@ -136,6 +147,7 @@ func New() (*Agent, error) {
 		k8sClient,
 		repos.pod, repos.generic,
 		traceQueryRepo, logQueryRepo, metricsQueryRepo, sloQueryRepo,
+		masterGw,
 	)
 
 	// 5. 初始化 Scheduler (调度层)
@@ -147,11 +159,14 @@ func New() (*Agent, error) {
 		SnapshotPushTimeout: cfg.Timeout.HTTPClient,
 		CommandPollTimeout:  cfg.Timeout.CommandPoll,
 		HeartbeatTimeout:    cfg.Timeout.Heartbeat,
+
+		SnapshotFullResyncInterval: cfg.Scheduler.SnapshotFullResync,
 	}
 	sched := scheduler.New(schedCfg, snapshotSvc, commandSvc, masterGw)
 
 	return &Agent{
 		scheduler: sched,
+		k8sClient: k8sClient,
 		chClient:  chClient,
 	}, nil
 }
@@ -167,6 +182,15 @@ func New() (*Agent, error) {
 // 返回:
 //   - error: 调度器停止时的错误
 func (a *Agent) Run(ctx context.Context) error {
+	// 后台启动 Informer 缓存；同步完成前快照仍直连 API Server List
+	informerCtx, stopInformers := context.WithCancel(ctx)
+	defer stopInformers()
+	go func() {
+		if err := a.k8sClient.StartInformers(informerCtx); err != nil {
+			log.Warn("Informer 缓存启动失败，快照继续直连 API Server", "err", err)
+		}
+	}()
+
 	if err := a.scheduler.Start(ctx); err != nil {
 		return err
 	}
@@ -231,6 +255,7 @@ type repositories struct {
 	event          repository.EventRepository
 	job            repository.JobRepository
 	cronJob        repository.CronJobRepository
+	hpa            repository.HPARepository
 	pv             repository.PersistentVolumeRepository
 	pvc            repository.PersistentVolumeClaimRepository
 	resourceQuota  repository.ResourceQuotaRepository
@@ -258,6 +283,7 @@ func initRepositories(client sdkpkg.K8sClient) *repositories {
 		event:          k8srepo.NewEventRepository(client),
 		job:            k8srepo.NewJobRepository(client),
 		cronJob:        k8srepo.NewCronJobRepository(client),
+		hpa:            k8srepo.NewHPARepository(client),
 		pv:             k8srepo.NewPersistentVolumeRepository(client),
 		pvc:            k8srepo.NewPersistentVolumeClaimRepository(client),
 		resourceQuota:  k8srepo.NewResourceQuotaRepository(client),
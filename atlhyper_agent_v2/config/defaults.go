@@ -9,6 +9,7 @@ package config
 var defaultDurations = map[string]string{
 	// -------------------- 调度器配置 --------------------
 	"AGENT_SNAPSHOT_INTERVAL":     "10s", // 快照采集间隔
+	"AGENT_SNAPSHOT_FULL_RESYNC":  "5m",  // 全量快照同步间隔（其余周期只推送增量，0 禁用增量）
 	"AGENT_COMMAND_POLL_INTERVAL": "100ms", // 指令轮询间隔（Dashboard 端点走快照直读后，Command 仅用于 Detail 查询，缩短以降低延迟）
 	"AGENT_HEARTBEAT_INTERVAL":    "15s", // 心跳发送间隔
 	"AGENT_OTEL_CACHE_TTL":        "10s", // OTel 概览缓存 TTL（与快照间隔一致）
@@ -35,7 +36,13 @@ var defaultStrings = map[string]string{
 	"AGENT_CLUSTER_ID": "", // 集群唯一标识，空则自动获取集群 UID
 
 	// -------------------- Master 通信 --------------------
-	"AGENT_MASTER_URL": "http://localhost:8081", // Master AgentSDK 端口（非 Gateway 端口）
+	"AGENT_MASTER_URL":             "http://localhost:8081", // Master AgentSDK 端口（非 Gateway 端口）
+	"AGENT_MASTER_TOKEN":           "",                      // Agent 认证 Token
+	"AGENT_MASTER_TOKEN_FILE":      "",                      // Token 文件路径（如挂载的 Secret），优先于 AGENT_MASTER_TOKEN
+	"AGENT_MASTER_TLS_CA":          "",                      // 校验 Master 证书的 CA 文件
+	"AGENT_MASTER_TLS_CERT":        "",                      // mTLS 客户端证书
+	"AGENT_MASTER_TLS_KEY":         "",                      // mTLS 客户端私钥
+	"AGENT_MASTER_TLS_SERVER_NAME": "",                      // 覆盖 TLS 校验的服务器名
 
 	// -------------------- Kubernetes 配置 --------------------
 	"AGENT_KUBECONFIG": "", // kubeconfig 文件路径，空则使用 InCluster 模式
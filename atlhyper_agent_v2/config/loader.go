@@ -24,7 +24,13 @@ func LoadConfig() {
 	}
 
 	GlobalConfig.Master = MasterConfig{
-		URL: getString("AGENT_MASTER_URL"),
+		URL:           getString("AGENT_MASTER_URL"),
+		Token:         getString("AGENT_MASTER_TOKEN"),
+		TokenFile:     getString("AGENT_MASTER_TOKEN_FILE"),
+		TLSCAFile:     getString("AGENT_MASTER_TLS_CA"),
+		TLSCertFile:   getString("AGENT_MASTER_TLS_CERT"),
+		TLSKeyFile:    getString("AGENT_MASTER_TLS_KEY"),
+		TLSServerName: getString("AGENT_MASTER_TLS_SERVER_NAME"),
 	}
 
 	GlobalConfig.Kubernetes = KubernetesConfig{
@@ -33,6 +39,7 @@ func LoadConfig() {
 
 	GlobalConfig.Scheduler = SchedulerConfig{
 		SnapshotInterval:    getDuration("AGENT_SNAPSHOT_INTERVAL"),
+		SnapshotFullResync:  getDuration("AGENT_SNAPSHOT_FULL_RESYNC"),
 		CommandPollInterval: getDuration("AGENT_COMMAND_POLL_INTERVAL"),
 		HeartbeatInterval:   getDuration("AGENT_HEARTBEAT_INTERVAL"),
 		OTelCacheTTL:        getDuration("AGENT_OTEL_CACHE_TTL"),
@@ -7,6 +7,7 @@ import "time"
 // SchedulerConfig 调度器配置
 type SchedulerConfig struct {
 	SnapshotInterval    time.Duration // 快照采集间隔
+	SnapshotFullResync  time.Duration // 全量快照同步间隔 (其余周期推送增量，0 禁用增量)
 	CommandPollInterval time.Duration // 指令轮询间隔
 	HeartbeatInterval   time.Duration // 心跳间隔
 	OTelCacheTTL        time.Duration // OTel 概览缓存 TTL (默认 5m)
@@ -22,7 +23,15 @@ type TimeoutConfig struct {
 
 // MasterConfig Master 通信配置
 type MasterConfig struct {
-	URL string // Master 服务地址
+	URL       string // Master 服务地址
+	Token     string // Agent 认证 Token（由 Master 管理员签发）
+	TokenFile string // Token 文件路径（优先于 Token，变更后自动重新读取，便于轮换）
+
+	// mTLS（Master 启用 TLS 时配置）
+	TLSCAFile     string // 校验 Master 证书的 CA
+	TLSCertFile   string // 客户端证书
+	TLSKeyFile    string // 客户端私钥
+	TLSServerName string // 覆盖证书校验的服务器名
 }
 
 // KubernetesConfig Kubernetes 连接配置
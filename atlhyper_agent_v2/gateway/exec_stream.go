@@ -0,0 +1,86 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"AtlHyper/model_v3/command"
+
+	"github.com/gorilla/websocket"
+)
+
+// execHandshakeTimeout exec 流 WebSocket 握手超时
+const execHandshakeTimeout = 10 * time.Second
+
+// OpenExecStream 打开交互式 exec 会话的双向流
+//
+// 复用 HTTP 请求相同的认证头 (X-Cluster-ID + Authorization) 和 TLS 配置。
+func (g *masterGateway) OpenExecStream(ctx context.Context, sessionID string) (ExecStream, error) {
+	u, err := url.Parse(g.masterURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master url: %w", err)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/agent/exec/stream"
+	u.RawQuery = url.Values{"session": {sessionID}}.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if err := g.setHeaders(req); err != nil {
+		return nil, err
+	}
+
+	dialer := websocket.Dialer{
+		HandshakeTimeout: execHandshakeTimeout,
+		TLSClientConfig:  g.tlsConfig,
+		Proxy:            http.ProxyFromEnvironment,
+	}
+	conn, resp, err := dialer.DialContext(ctx, u.String(), req.Header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("failed to open exec stream: status %d: %w", resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("failed to open exec stream: %w", err)
+	}
+	return &wsExecStream{conn: conn}, nil
+}
+
+// wsExecStream 基于 WebSocket 的 ExecStream 实现
+type wsExecStream struct {
+	conn *websocket.Conn
+	mu   sync.Mutex // gorilla/websocket 不允许并发写
+}
+
+func (s *wsExecStream) Recv() (*command.ExecFrame, error) {
+	var frame command.ExecFrame
+	if err := s.conn.ReadJSON(&frame); err != nil {
+		return nil, err
+	}
+	return &frame, nil
+}
+
+func (s *wsExecStream) Send(frame *command.ExecFrame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn.WriteJSON(frame)
+}
+
+func (s *wsExecStream) Close() error {
+	s.mu.Lock()
+	_ = s.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	s.mu.Unlock()
+	return s.conn.Close()
+}
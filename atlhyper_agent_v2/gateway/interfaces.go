@@ -7,6 +7,7 @@
 //   - Agent 主动发起所有请求 (推送快照、拉取指令、上报结果)
 //   - Master 被动响应
 //   - 长轮询获取指令 (减少轮询频率)
+//   - 交互式 exec 会话由 Agent 主动建立 WebSocket 双向流
 //
 // 架构位置:
 //
@@ -29,7 +30,7 @@ import (
 // MasterGateway Master 通信接口
 //
 // 封装所有与 Master 的通信，提供以下功能:
-//   - 推送集群快照
+//   - 推送集群快照（全量 / 增量）
 //   - 拉取待执行指令
 //   - 上报执行结果
 //   - 心跳保活
@@ -43,6 +44,16 @@ type MasterGateway interface {
 	// Header: X-Cluster-ID, Content-Encoding: gzip
 	PushSnapshot(ctx context.Context, snapshot *cluster.ClusterSnapshot) error
 
+	// PushSnapshotDelta 推送增量快照到 Master
+	//
+	// 只包含相对上一次已推送快照的变更对象。
+	// Master 当前快照版本与 delta.BaseVersion 不一致时返回 409，
+	// 此方法将其包装为 cluster.ErrDeltaBaseMismatch。
+	//
+	// HTTP: POST /agent/snapshot/delta
+	// Header: X-Cluster-ID, Content-Encoding: gzip
+	PushSnapshotDelta(ctx context.Context, delta *cluster.SnapshotDelta) error
+
 	// PollCommands 从 Master 拉取待执行指令
 	//
 	// 使用长轮询方式，Master 会 hold 请求直到有指令或超时。
@@ -65,4 +76,20 @@ type MasterGateway interface {
 	//
 	// HTTP: POST /agent/heartbeat
 	Heartbeat(ctx context.Context) error
+
+	// OpenExecStream 打开交互式 exec 会话的双向流
+	//
+	// 收到 exec 指令后调用，Master 将该连接与 Web UI 的 WebSocket 配对转发。
+	//
+	// HTTP: GET /agent/exec/stream?session=xxx (WebSocket)
+	OpenExecStream(ctx context.Context, sessionID string) (ExecStream, error)
+}
+
+// ExecStream 交互式会话的双向帧流
+//
+// Send 可被多个 goroutine 并发调用；Recv 只应由单个 goroutine 调用。
+type ExecStream interface {
+	Recv() (*command.ExecFrame, error)
+	Send(frame *command.ExecFrame) error
+	Close() error
 }
@@ -3,13 +3,18 @@ package gateway
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"AtlHyper/common"
+	"AtlHyper/common/crypto"
 	"AtlHyper/model_v3/cluster"
 	"AtlHyper/model_v3/command"
 )
@@ -20,10 +25,29 @@ import (
 //   - 快照推送使用 Gzip 压缩 (减少带宽)
 //   - 指令拉取使用长轮询 (减少请求频率)
 //   - 所有请求带 X-Cluster-ID 头标识集群
+//   - 配置 Token 时带 Authorization: Bearer 头认证身份
 type masterGateway struct {
 	masterURL  string       // Master 服务地址
 	clusterID  string       // 集群标识
 	httpClient *http.Client // HTTP 客户端 (复用连接)
+	token      *tokenSource // 认证 Token (nil 表示不认证)
+	tlsConfig  *tls.Config  // 自定义 TLS 配置 (nil 使用默认)，exec 流复用
+}
+
+// SecurityConfig Master 通信安全配置
+type SecurityConfig struct {
+	Token     string // 静态 Token
+	TokenFile string // Token 文件（优先于 Token，文件变更后自动重新读取）
+
+	TLSCAFile     string // 校验 Master 证书的 CA
+	TLSCertFile   string // mTLS 客户端证书
+	TLSKeyFile    string // mTLS 客户端私钥
+	TLSServerName string // 覆盖证书校验的服务器名
+}
+
+// tlsEnabled 是否需要自定义 TLS 配置
+func (c SecurityConfig) tlsEnabled() bool {
+	return c.TLSCAFile != "" || c.TLSCertFile != "" || c.TLSServerName != ""
 }
 
 // NewMasterGateway 创建 Master 网关
@@ -32,20 +56,112 @@ type masterGateway struct {
 //   - masterURL: Master 服务地址，如 "http://master:8080"
 //   - clusterID: 集群标识
 //   - httpTimeout: HTTP 客户端超时时间 (长轮询需要较长超时)
-func NewMasterGateway(masterURL, clusterID string, httpTimeout time.Duration) MasterGateway {
-	return &masterGateway{
-		masterURL: masterURL,
-		clusterID: clusterID,
-		httpClient: &http.Client{
-			Timeout: httpTimeout,
-		},
+//   - sec: 认证与 TLS 配置 (零值表示不认证、使用默认 TLS)
+func NewMasterGateway(masterURL, clusterID string, httpTimeout time.Duration, sec SecurityConfig) (MasterGateway, error) {
+	client := &http.Client{
+		Timeout: httpTimeout,
 	}
+
+	var tlsConfig *tls.Config
+	if sec.tlsEnabled() {
+		tlsCfg, err := crypto.NewClientTLSConfig(sec.TLSCAFile, sec.TLSCertFile, sec.TLSKeyFile, sec.TLSServerName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: %w", err)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsCfg
+		client.Transport = transport
+		tlsConfig = tlsCfg
+	}
+
+	g := &masterGateway{
+		masterURL:  masterURL,
+		clusterID:  clusterID,
+		httpClient: client,
+		tlsConfig:  tlsConfig,
+	}
+	if sec.Token != "" || sec.TokenFile != "" {
+		g.token = &tokenSource{static: sec.Token, file: sec.TokenFile}
+		if _, err := g.token.get(); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// setHeaders 设置公共请求头（集群标识 + 认证）
+func (g *masterGateway) setHeaders(req *http.Request) error {
+	req.Header.Set("X-Cluster-ID", g.clusterID)
+	if g.token == nil {
+		return nil
+	}
+	token, err := g.token.get()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// tokenSource Token 提供者
+//
+// 配置文件路径时按 mtime 缓存，Secret 更新（Token 轮换）后无需重启 Agent
+type tokenSource struct {
+	static string
+	file   string
+
+	mu      sync.Mutex
+	cached  string
+	modTime time.Time
+}
+
+func (t *tokenSource) get() (string, error) {
+	if t.file == "" {
+		return t.static, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info, err := os.Stat(t.file)
+	if err != nil {
+		if t.cached != "" {
+			return t.cached, nil // 文件短暂不可见（Secret 原子替换）时沿用缓存
+		}
+		return "", fmt.Errorf("failed to stat token file: %w", err)
+	}
+	if t.cached != "" && info.ModTime().Equal(t.modTime) {
+		return t.cached, nil
+	}
+
+	data, err := os.ReadFile(t.file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", t.file)
+	}
+	t.cached = token
+	t.modTime = info.ModTime()
+	return token, nil
 }
 
 // PushSnapshot 推送快照
 func (g *masterGateway) PushSnapshot(ctx context.Context, snapshot *cluster.ClusterSnapshot) error {
+	return g.postSnapshot(ctx, "/agent/snapshot", snapshot)
+}
+
+// PushSnapshotDelta 推送增量快照
+// Master 返回 409 时包装为 cluster.ErrDeltaBaseMismatch，调用方应改为全量推送
+func (g *masterGateway) PushSnapshotDelta(ctx context.Context, delta *cluster.SnapshotDelta) error {
+	return g.postSnapshot(ctx, "/agent/snapshot/delta", delta)
+}
+
+// postSnapshot 以 JSON + Gzip 推送快照数据
+func (g *masterGateway) postSnapshot(ctx context.Context, path string, payload interface{}) error {
 	// 1. JSON 序列化
-	data, err := json.Marshal(snapshot)
+	data, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal snapshot: %w", err)
 	}
@@ -57,7 +173,7 @@ func (g *masterGateway) PushSnapshot(ctx context.Context, snapshot *cluster.Clus
 	}
 
 	// 3. 构建请求
-	url := fmt.Sprintf("%s/agent/snapshot", g.masterURL)
+	url := g.masterURL + path
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(compressed))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
@@ -65,7 +181,9 @@ func (g *masterGateway) PushSnapshot(ctx context.Context, snapshot *cluster.Clus
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Content-Encoding", "gzip")
-	req.Header.Set("X-Cluster-ID", g.clusterID)
+	if err := g.setHeaders(req); err != nil {
+		return err
+	}
 
 	// 4. 发送请求
 	resp, err := g.httpClient.Do(req)
@@ -74,6 +192,10 @@ func (g *masterGateway) PushSnapshot(ctx context.Context, snapshot *cluster.Clus
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusConflict {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %s", cluster.ErrDeltaBaseMismatch, strings.TrimSpace(string(body)))
+	}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
@@ -98,7 +220,9 @@ func (g *masterGateway) PollCommands(ctx context.Context, topic string) ([]comma
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("X-Cluster-ID", g.clusterID)
+	if err := g.setHeaders(req); err != nil {
+		return nil, err
+	}
 
 	resp, err := g.httpClient.Do(req)
 	if err != nil {
@@ -149,7 +273,9 @@ func (g *masterGateway) ReportResult(ctx context.Context, result *command.Result
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Cluster-ID", g.clusterID)
+	if err := g.setHeaders(req); err != nil {
+		return err
+	}
 
 	resp, err := g.httpClient.Do(req)
 	if err != nil {
@@ -173,7 +299,9 @@ func (g *masterGateway) Heartbeat(ctx context.Context) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("X-Cluster-ID", g.clusterID)
+	if err := g.setHeaders(req); err != nil {
+		return err
+	}
 
 	resp, err := g.httpClient.Do(req)
 	if err != nil {
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -398,3 +400,98 @@ func TestHeartbeat_ServerError(t *testing.T) {
 		t.Errorf("error should contain status code 503, got: %v", err)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Authentication
+// ---------------------------------------------------------------------------
+
+func TestAuthHeader_StaticToken(t *testing.T) {
+	var capturedAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	gw, err := NewMasterGateway(server.URL, "test-cluster", 5*time.Second, SecurityConfig{Token: "ath_static"})
+	if err != nil {
+		t.Fatalf("NewMasterGateway returned error: %v", err)
+	}
+
+	if err := gw.Heartbeat(context.Background()); err != nil {
+		t.Fatalf("Heartbeat returned error: %v", err)
+	}
+	if capturedAuth != "Bearer ath_static" {
+		t.Errorf("Authorization: got %q, want %q", capturedAuth, "Bearer ath_static")
+	}
+}
+
+func TestAuthHeader_NoToken(t *testing.T) {
+	var capturedAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	gw := newTestGateway(server.URL)
+	if err := gw.Heartbeat(context.Background()); err != nil {
+		t.Fatalf("Heartbeat returned error: %v", err)
+	}
+	if capturedAuth != "" {
+		t.Errorf("Authorization: got %q, want empty", capturedAuth)
+	}
+}
+
+func TestAuthHeader_TokenFileReload(t *testing.T) {
+	var capturedAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("ath_first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	gw, err := NewMasterGateway(server.URL, "test-cluster", 5*time.Second, SecurityConfig{TokenFile: tokenFile})
+	if err != nil {
+		t.Fatalf("NewMasterGateway returned error: %v", err)
+	}
+	if err := gw.Heartbeat(context.Background()); err != nil {
+		t.Fatalf("Heartbeat returned error: %v", err)
+	}
+	if capturedAuth != "Bearer ath_first" {
+		t.Errorf("Authorization: got %q, want %q", capturedAuth, "Bearer ath_first")
+	}
+
+	// 模拟 Secret 轮换：写入新 Token 并推进 mtime
+	if err := os.WriteFile(tokenFile, []byte("ath_second"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(tokenFile, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gw.Heartbeat(context.Background()); err != nil {
+		t.Fatalf("Heartbeat returned error: %v", err)
+	}
+	if capturedAuth != "Bearer ath_second" {
+		t.Errorf("Authorization after rotation: got %q, want %q", capturedAuth, "Bearer ath_second")
+	}
+}
+
+func TestNewMasterGateway_MissingTokenFile(t *testing.T) {
+	_, err := NewMasterGateway("http://localhost", "test-cluster", time.Second, SecurityConfig{
+		TokenFile: filepath.Join(t.TempDir(), "missing"),
+	})
+	if err == nil {
+		t.Fatal("expected error for missing token file")
+	}
+}
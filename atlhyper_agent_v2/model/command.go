@@ -1,5 +1,7 @@
 package model
 
+import "errors"
+
 // =============================================================================
 // Agent 内部类型（不跨项目共享）
 // =============================================================================
@@ -15,3 +17,27 @@ type DynamicResponse struct {
 	StatusCode int    `json:"status_code"`
 	Body       []byte `json:"body"`
 }
+
+// APIResource 可查询的资源类型（API 发现结果，含 CRD）
+type APIResource struct {
+	Group      string   `json:"group"`
+	Version    string   `json:"version"`
+	Kind       string   `json:"kind"`
+	Resource   string   `json:"resource"`
+	Namespaced bool     `json:"namespaced"`
+	ShortNames []string `json:"shortNames,omitempty"`
+}
+
+// NodePod 节点上的 Pod 摘要（drain 分类所需的最小信息）
+type NodePod struct {
+	Namespace   string
+	Name        string
+	UID         string
+	OwnerKind   string // 控制器类型，如 ReplicaSet / DaemonSet / StatefulSet，空表示无控制器
+	Mirror      bool   // 静态 Pod 的 Mirror Pod（无法通过 API 驱逐）
+	HasEmptyDir bool   // 使用 emptyDir 卷（驱逐会丢失本地数据）
+	Finished    bool   // 已处于 Succeeded / Failed 阶段
+}
+
+// ErrEvictionBlocked 驱逐被 PodDisruptionBudget 拒绝（可稍后重试）
+var ErrEvictionBlocked = errors.New("eviction blocked by PodDisruptionBudget")
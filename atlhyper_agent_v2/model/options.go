@@ -1,5 +1,7 @@
 package model
 
+import "io"
+
 // ListOptions 列表查询选项
 //
 // 用于 Repository 和 Service 层的资源列表查询
@@ -43,7 +45,7 @@ type LogOptions struct {
 	Timestamps bool
 
 	// Follow 是否跟踪日志 (流式)
-	// 目前不支持
+	// 仅 PodRepository.StreamLogs 使用
 	Follow bool
 
 	// Previous 是否获取之前容器的日志
@@ -68,3 +70,38 @@ type DeleteOptions struct {
 	PropagationPolicy string
 }
 
+
+// EvictOptions 驱逐选项
+type EvictOptions struct {
+	// GracePeriodSeconds 优雅终止时间 (秒)
+	// nil 使用 Pod 自身的 terminationGracePeriodSeconds
+	GracePeriodSeconds *int64
+}
+
+// ExecOptions 容器内执行命令选项
+type ExecOptions struct {
+	// Container 容器名称
+	// 空表示由 API Server 选择默认容器
+	Container string
+
+	// Command 执行的命令，如 ["/bin/sh"]
+	Command []string
+
+	// TTY 是否分配终端
+	// TTY 模式下 stderr 合并到 stdout
+	TTY bool
+
+	// Stdin / Stdout / Stderr 标准流
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Resize 终端尺寸变化通知（可选）
+	Resize <-chan TerminalSize
+}
+
+// TerminalSize 终端尺寸
+type TerminalSize struct {
+	Width  uint16
+	Height uint16
+}
@@ -9,6 +9,7 @@ package repository
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"AtlHyper/atlhyper_agent_v2/model"
@@ -28,6 +29,8 @@ type PodRepository interface {
 	List(ctx context.Context, namespace string, opts model.ListOptions) ([]cluster.Pod, error)
 	Get(ctx context.Context, namespace, name string) (*cluster.Pod, error)
 	GetLogs(ctx context.Context, namespace, name string, opts model.LogOptions) (string, error)
+	StreamLogs(ctx context.Context, namespace, name string, opts model.LogOptions) (io.ReadCloser, error)
+	Exec(ctx context.Context, namespace, name string, opts model.ExecOptions) error
 }
 
 // NodeRepository Node 数据访问接口
@@ -106,6 +109,11 @@ type CronJobRepository interface {
 	Get(ctx context.Context, namespace, name string) (*cluster.CronJob, error)
 }
 
+// HPARepository HorizontalPodAutoscaler 数据访问接口
+type HPARepository interface {
+	List(ctx context.Context, namespace string, opts model.ListOptions) ([]cluster.HorizontalPodAutoscaler, error)
+}
+
 // PersistentVolumeRepository PV 数据访问接口
 type PersistentVolumeRepository interface {
 	List(ctx context.Context, opts model.ListOptions) ([]cluster.PersistentVolume, error)
@@ -145,9 +153,14 @@ type GenericRepository interface {
 	UpdateDeploymentImage(ctx context.Context, namespace, name, container, image string) error
 	CordonNode(ctx context.Context, name string) error
 	UncordonNode(ctx context.Context, name string) error
+	ListNodePods(ctx context.Context, nodeName string) ([]model.NodePod, error)
+	EvictPod(ctx context.Context, namespace, name string, opts model.EvictOptions) error
+	PodGone(ctx context.Context, namespace, name, uid string) (bool, error)
 	GetConfigMapData(ctx context.Context, namespace, name string) (map[string]string, error)
 	GetSecretData(ctx context.Context, namespace, name string) (map[string]string, error)
+	GetDeploymentSelector(ctx context.Context, namespace, name string) (string, error)
 	Execute(ctx context.Context, req *model.DynamicRequest) (*model.DynamicResponse, error)
+	ListAPIResources(ctx context.Context) ([]model.APIResource, error)
 }
 
 // =============================================================================
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -2514,3 +2515,106 @@ func TestIsPodReady(t *testing.T) {
 		})
 	}
 }
+
+// =============================================================================
+// ConvertHPA
+// =============================================================================
+
+func TestConvertHPA_TargetsAndConditions(t *testing.T) {
+	minReplicas := int32(2)
+	targetUtil := int32(80)
+	currentUtil := int32(95)
+	avgValue := resource.MustParse("100")
+	currentValue := resource.MustParse("150")
+
+	k8sHPA := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "web",
+			Namespace:         "default",
+			UID:               types.UID("hpa-uid"),
+			CreationTimestamp: metav1.Time{Time: stableTime()},
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "web"},
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    5,
+			Metrics: []autoscalingv2.MetricSpec{
+				{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name:   corev1.ResourceCPU,
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: &targetUtil},
+					},
+				},
+				{
+					Type: autoscalingv2.PodsMetricSourceType,
+					Pods: &autoscalingv2.PodsMetricSource{
+						Metric: autoscalingv2.MetricIdentifier{Name: "http_requests_per_second"},
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &avgValue},
+					},
+				},
+			},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 5,
+			DesiredReplicas: 5,
+			// 当前指标顺序与 spec 不同，按类型+名称匹配
+			CurrentMetrics: []autoscalingv2.MetricStatus{
+				{
+					Type: autoscalingv2.PodsMetricSourceType,
+					Pods: &autoscalingv2.PodsMetricStatus{
+						Metric:  autoscalingv2.MetricIdentifier{Name: "http_requests_per_second"},
+						Current: autoscalingv2.MetricValueStatus{AverageValue: &currentValue},
+					},
+				},
+				{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricStatus{
+						Name:    corev1.ResourceCPU,
+						Current: autoscalingv2.MetricValueStatus{AverageUtilization: &currentUtil},
+					},
+				},
+			},
+			Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+				{Type: autoscalingv2.ScalingLimited, Status: corev1.ConditionTrue, Reason: "TooManyReplicas"},
+			},
+		},
+	}
+
+	hpa := ConvertHPA(k8sHPA)
+
+	if hpa.Name != "web" || hpa.Kind != "HorizontalPodAutoscaler" || hpa.UID != "hpa-uid" {
+		t.Errorf("unexpected meta: %+v", hpa.CommonMeta)
+	}
+	if hpa.ScaleTargetKind != "Deployment" || hpa.ScaleTargetName != "web" {
+		t.Errorf("scale target = %s/%s", hpa.ScaleTargetKind, hpa.ScaleTargetName)
+	}
+	if hpa.MinReplicas != 2 || hpa.MaxReplicas != 5 || hpa.CurrentReplicas != 5 {
+		t.Errorf("replicas = min %d max %d current %d", hpa.MinReplicas, hpa.MaxReplicas, hpa.CurrentReplicas)
+	}
+	if len(hpa.Metrics) != 2 {
+		t.Fatalf("metrics len = %d, want 2", len(hpa.Metrics))
+	}
+	if m := hpa.Metrics[0]; m.Name != "cpu" || m.Target != "80%" || m.Current != "95%" || m.TargetType != "Utilization" {
+		t.Errorf("cpu metric = %+v", m)
+	}
+	if m := hpa.Metrics[1]; m.Name != "http_requests_per_second" || m.Target != "100" || m.Current != "150" {
+		t.Errorf("pods metric = %+v", m)
+	}
+	if !hpa.IsAtMaxReplicas() || !hpa.IsScalingLimited() {
+		t.Error("expected HPA to be at max replicas and scaling limited")
+	}
+}
+
+func TestConvertHPA_DefaultMinReplicas(t *testing.T) {
+	hpa := ConvertHPA(&autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{MaxReplicas: 3},
+	})
+	if hpa.MinReplicas != 1 {
+		t.Errorf("MinReplicas = %d, want 1", hpa.MinReplicas)
+	}
+	if hpa.IsAtMaxReplicas() {
+		t.Error("HPA with 0 current replicas should not be at max")
+	}
+}
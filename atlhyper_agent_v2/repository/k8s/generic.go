@@ -2,10 +2,15 @@ package k8s
 
 import (
 	"context"
+	"fmt"
 
 	"AtlHyper/atlhyper_agent_v2/model"
 	"AtlHyper/atlhyper_agent_v2/sdk"
 	"AtlHyper/atlhyper_agent_v2/repository"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // genericRepository 通用操作仓库实现
@@ -18,6 +23,19 @@ func NewGenericRepository(client sdk.K8sClient) repository.GenericRepository {
 	return &genericRepository{client: client}
 }
 
+// GetDeploymentSelector 获取 Deployment 的 Pod 标签选择器（字符串形式）
+func (r *genericRepository) GetDeploymentSelector(ctx context.Context, namespace, name string) (string, error) {
+	deploy, err := r.client.GetDeployment(ctx, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid selector: %w", err)
+	}
+	return selector.String(), nil
+}
+
 // =============================================================================
 // 删除操作
 // =============================================================================
@@ -72,6 +90,75 @@ func (r *genericRepository) UncordonNode(ctx context.Context, name string) error
 	return r.client.UncordonNode(ctx, name)
 }
 
+// ListNodePods 列出节点上的所有 Pod（drain 使用）
+func (r *genericRepository) ListNodePods(ctx context.Context, nodeName string) ([]model.NodePod, error) {
+	pods, err := r.client.ListPods(ctx, "", sdk.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.NodePod, 0, len(pods))
+	for i := range pods {
+		result = append(result, toNodePod(&pods[i]))
+	}
+	return result, nil
+}
+
+// EvictPod 驱逐 Pod（PDB 拒绝时返回 model.ErrEvictionBlocked）
+func (r *genericRepository) EvictPod(ctx context.Context, namespace, name string, opts model.EvictOptions) error {
+	err := r.client.EvictPod(ctx, namespace, name, sdk.EvictOptions{
+		GracePeriodSeconds: opts.GracePeriodSeconds,
+	})
+	if apierrors.IsTooManyRequests(err) {
+		return fmt.Errorf("%w: %v", model.ErrEvictionBlocked, err)
+	}
+	if apierrors.IsNotFound(err) {
+		return nil // 已不存在视为驱逐完成
+	}
+	return err
+}
+
+// PodGone 判断 Pod 是否已删除
+// 同名 Pod 被重建（UID 变化，如 StatefulSet）也视为原 Pod 已删除
+func (r *genericRepository) PodGone(ctx context.Context, namespace, name, uid string) (bool, error) {
+	pod, err := r.client.GetPod(ctx, namespace, name)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return uid != "" && string(pod.UID) != uid, nil
+}
+
+// toNodePod 提取 drain 分类所需的 Pod 信息
+func toNodePod(pod *corev1.Pod) model.NodePod {
+	np := model.NodePod{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		UID:       string(pod.UID),
+		Finished:  pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed,
+	}
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		np.Mirror = true
+	}
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			np.OwnerKind = ref.Kind
+			break
+		}
+	}
+	for _, v := range pod.Spec.Volumes {
+		if v.EmptyDir != nil {
+			np.HasEmptyDir = true
+			break
+		}
+	}
+	return np
+}
+
 // =============================================================================
 // 配置数据获取
 // =============================================================================
@@ -120,3 +207,23 @@ func (r *genericRepository) Execute(ctx context.Context, req *model.DynamicReque
 		Body:       sdkResp.Body,
 	}, nil
 }
+
+// ListAPIResources 列出可查询的资源类型（API 发现，含 CRD）
+func (r *genericRepository) ListAPIResources(ctx context.Context) ([]model.APIResource, error) {
+	resources, err := r.client.ServerResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.APIResource, 0, len(resources))
+	for _, res := range resources {
+		result = append(result, model.APIResource{
+			Group:      res.Group,
+			Version:    res.Version,
+			Kind:       res.Kind,
+			Resource:   res.Resource,
+			Namespaced: res.Namespaced,
+			ShortNames: res.ShortNames,
+		})
+	}
+	return result, nil
+}
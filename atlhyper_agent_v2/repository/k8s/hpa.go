@@ -0,0 +1,211 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"AtlHyper/atlhyper_agent_v2/model"
+	"AtlHyper/atlhyper_agent_v2/repository"
+	"AtlHyper/atlhyper_agent_v2/sdk"
+	"AtlHyper/model_v3/cluster"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+// =============================================================================
+// HorizontalPodAutoscaler 仓库
+// =============================================================================
+
+type hpaRepository struct {
+	client sdk.K8sClient
+}
+
+// NewHPARepository 创建 HPA 仓库
+func NewHPARepository(client sdk.K8sClient) repository.HPARepository {
+	return &hpaRepository{client: client}
+}
+
+func (r *hpaRepository) List(ctx context.Context, namespace string, opts model.ListOptions) ([]cluster.HorizontalPodAutoscaler, error) {
+	k8sHPAs, err := r.client.ListHPAs(ctx, namespace, sdk.ListOptions{
+		LabelSelector: opts.LabelSelector,
+		FieldSelector: opts.FieldSelector,
+		Limit:         opts.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hpas := make([]cluster.HorizontalPodAutoscaler, 0, len(k8sHPAs))
+	for i := range k8sHPAs {
+		hpas = append(hpas, ConvertHPA(&k8sHPAs[i]))
+	}
+	return hpas, nil
+}
+
+// =============================================================================
+// HorizontalPodAutoscaler 转换
+// =============================================================================
+
+// ConvertHPA 转换 K8s HPA 到 model_v3
+func ConvertHPA(k8sHPA *autoscalingv2.HorizontalPodAutoscaler) cluster.HorizontalPodAutoscaler {
+	hpa := cluster.HorizontalPodAutoscaler{
+		CommonMeta: buildCommonMeta(
+			string(k8sHPA.UID),
+			k8sHPA.Name,
+			k8sHPA.Namespace,
+			"HorizontalPodAutoscaler",
+			k8sHPA.Labels,
+			k8sHPA.CreationTimestamp.Time,
+		),
+		ScaleTargetKind: k8sHPA.Spec.ScaleTargetRef.Kind,
+		ScaleTargetName: k8sHPA.Spec.ScaleTargetRef.Name,
+		MinReplicas:     1,
+		MaxReplicas:     k8sHPA.Spec.MaxReplicas,
+		CurrentReplicas: k8sHPA.Status.CurrentReplicas,
+		DesiredReplicas: k8sHPA.Status.DesiredReplicas,
+	}
+	if k8sHPA.Spec.MinReplicas != nil {
+		hpa.MinReplicas = *k8sHPA.Spec.MinReplicas
+	}
+
+	// 指标: spec 给出目标，status 给出当前值（按 类型/名称 匹配）
+	for _, spec := range k8sHPA.Spec.Metrics {
+		m := convertHPAMetricSpec(spec)
+		for _, st := range k8sHPA.Status.CurrentMetrics {
+			if hpaMetricKey(st) == m.Type+"/"+m.Name {
+				m.Current = hpaMetricCurrent(st)
+				break
+			}
+		}
+		hpa.Metrics = append(hpa.Metrics, m)
+	}
+
+	// Conditions
+	for _, c := range k8sHPA.Status.Conditions {
+		hpa.Conditions = append(hpa.Conditions, cluster.WorkloadCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Format(time.RFC3339),
+		})
+	}
+
+	if k8sHPA.Status.LastScaleTime != nil {
+		t := k8sHPA.Status.LastScaleTime.Time
+		hpa.LastScaleTime = &t
+	}
+
+	return hpa
+}
+
+// convertHPAMetricSpec 提取指标类型、名称与目标值
+func convertHPAMetricSpec(spec autoscalingv2.MetricSpec) cluster.HPAMetric {
+	m := cluster.HPAMetric{Type: string(spec.Type)}
+	var target autoscalingv2.MetricTarget
+	switch spec.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if spec.Resource != nil {
+			m.Name = string(spec.Resource.Name)
+			target = spec.Resource.Target
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if spec.ContainerResource != nil {
+			m.Name = string(spec.ContainerResource.Name)
+			m.Container = spec.ContainerResource.Container
+			target = spec.ContainerResource.Target
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if spec.Pods != nil {
+			m.Name = spec.Pods.Metric.Name
+			target = spec.Pods.Target
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if spec.Object != nil {
+			m.Name = spec.Object.Metric.Name
+			target = spec.Object.Target
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if spec.External != nil {
+			m.Name = spec.External.Metric.Name
+			target = spec.External.Target
+		}
+	}
+
+	m.TargetType = string(target.Type)
+	switch {
+	case target.AverageUtilization != nil:
+		m.Target = fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		m.Target = target.AverageValue.String()
+	case target.Value != nil:
+		m.Target = target.Value.String()
+	}
+	return m
+}
+
+// hpaMetricKey 当前指标的 "类型/名称"，用于与 spec 匹配
+func hpaMetricKey(st autoscalingv2.MetricStatus) string {
+	name := ""
+	switch st.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if st.Resource != nil {
+			name = string(st.Resource.Name)
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if st.ContainerResource != nil {
+			name = string(st.ContainerResource.Name)
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if st.Pods != nil {
+			name = st.Pods.Metric.Name
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if st.Object != nil {
+			name = st.Object.Metric.Name
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if st.External != nil {
+			name = st.External.Metric.Name
+		}
+	}
+	return string(st.Type) + "/" + name
+}
+
+// hpaMetricCurrent 格式化当前指标值（利用率优先）
+func hpaMetricCurrent(st autoscalingv2.MetricStatus) string {
+	var cur autoscalingv2.MetricValueStatus
+	switch st.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if st.Resource != nil {
+			cur = st.Resource.Current
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if st.ContainerResource != nil {
+			cur = st.ContainerResource.Current
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if st.Pods != nil {
+			cur = st.Pods.Current
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if st.Object != nil {
+			cur = st.Object.Current
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if st.External != nil {
+			cur = st.External.Current
+		}
+	}
+
+	switch {
+	case cur.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *cur.AverageUtilization)
+	case cur.AverageValue != nil:
+		return cur.AverageValue.String()
+	case cur.Value != nil:
+		return cur.Value.String()
+	}
+	return ""
+}
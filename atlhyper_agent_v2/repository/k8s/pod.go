@@ -3,6 +3,7 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"AtlHyper/atlhyper_agent_v2/model"
 	"AtlHyper/atlhyper_agent_v2/sdk"
@@ -92,3 +93,43 @@ func (r *podRepository) GetLogs(ctx context.Context, namespace, name string, opt
 		Previous:     opts.Previous,
 	})
 }
+
+// StreamLogs 打开 Pod 日志流（调用方负责 Close）
+func (r *podRepository) StreamLogs(ctx context.Context, namespace, name string, opts model.LogOptions) (io.ReadCloser, error) {
+	return r.client.StreamPodLogs(ctx, namespace, name, sdk.LogOptions{
+		Container:    opts.Container,
+		TailLines:    opts.TailLines,
+		SinceSeconds: opts.SinceSeconds,
+		Timestamps:   opts.Timestamps,
+		Previous:     opts.Previous,
+		Follow:       opts.Follow,
+	})
+}
+
+// Exec 在容器内执行命令（交互式会话）
+func (r *podRepository) Exec(ctx context.Context, namespace, name string, opts model.ExecOptions) error {
+	var resize chan sdk.TerminalSize
+	if opts.Resize != nil {
+		resize = make(chan sdk.TerminalSize)
+		go func() {
+			defer close(resize)
+			for size := range opts.Resize {
+				select {
+				case resize <- sdk.TerminalSize{Width: size.Width, Height: size.Height}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	return r.client.ExecPod(ctx, namespace, name, sdk.ExecOptions{
+		Container: opts.Container,
+		Command:   opts.Command,
+		TTY:       opts.TTY,
+		Stdin:     opts.Stdin,
+		Stdout:    opts.Stdout,
+		Stderr:    opts.Stderr,
+		Resize:    resize,
+	})
+}
@@ -2,12 +2,15 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
 	"AtlHyper/atlhyper_agent_v2/gateway"
 	"AtlHyper/atlhyper_agent_v2/service"
 	"AtlHyper/common/logger"
+	"AtlHyper/model_v3/cluster"
+	"AtlHyper/model_v3/command"
 )
 
 var log = logger.Module("Scheduler")
@@ -33,6 +36,10 @@ type Config struct {
 	// 独立于 SnapshotTimeout，避免 Collect 慢耗尽 ctx 预算后连累 Push
 	SnapshotPushTimeout time.Duration
 
+	// SnapshotFullResyncInterval 全量快照同步间隔
+	// 两次全量之间只推送增量；为 0 时禁用增量，每次都推送全量
+	SnapshotFullResyncInterval time.Duration
+
 	// CommandPollTimeout 指令轮询操作超时
 	CommandPollTimeout time.Duration
 
@@ -63,6 +70,11 @@ type Scheduler struct {
 	lastPodCount        int
 	lastNodeCount       int
 	lastDeploymentCount int
+
+	// 增量同步状态（仅快照循环访问）
+	lastIndex    cluster.SnapshotIndex // 上一次成功推送的快照内容索引，nil 表示下次推送全量
+	version      uint64                // 上一次成功推送的快照版本
+	lastFullSync time.Time             // 上一次全量推送时间
 }
 
 // New 创建调度器
@@ -158,7 +170,7 @@ func (s *Scheduler) collectAndPushSnapshot() {
 	// 推送到 Master（独立超时，不受 Collect 耗时影响）
 	pushCtx, cancelPush := context.WithTimeout(s.ctx, s.config.SnapshotPushTimeout)
 	defer cancelPush()
-	if err := s.masterGw.PushSnapshot(pushCtx, snapshot); err != nil {
+	if err := s.pushSnapshot(pushCtx, snapshot); err != nil {
 		log.Error("推送快照失败", "err", err)
 		return
 	}
@@ -188,6 +200,76 @@ func (s *Scheduler) collectAndPushSnapshot() {
 	}
 }
 
+// pushSnapshot 推送快照（增量优先）
+//
+// 推送策略:
+//   - 首次、上次推送失败、或距上次全量超过 SnapshotFullResyncInterval: 推送全量
+//   - 其余周期: 推送相对上一次已推送快照的增量
+//   - Master 返回基准版本不一致（Master 重启等）: 立即改为全量
+func (s *Scheduler) pushSnapshot(ctx context.Context, snapshot *cluster.ClusterSnapshot) error {
+	if s.lastIndex != nil && time.Since(s.lastFullSync) < s.config.SnapshotFullResyncInterval {
+		err := s.pushDelta(ctx, snapshot)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, cluster.ErrDeltaBaseMismatch) {
+			// Master 是否已应用未知，下次改为全量
+			s.lastIndex = nil
+			return err
+		}
+		log.Info("Master 快照版本不一致，改为全量同步", "err", err)
+	}
+	return s.pushFull(ctx, snapshot)
+}
+
+// pushDelta 计算并推送增量
+func (s *Scheduler) pushDelta(ctx context.Context, snapshot *cluster.ClusterSnapshot) error {
+	changes, index, err := cluster.DiffSnapshot(s.lastIndex, snapshot)
+	if err != nil {
+		return err
+	}
+
+	delta := &cluster.SnapshotDelta{
+		ClusterID:   snapshot.ClusterID,
+		BaseVersion: s.version,
+		Version:     s.version + 1,
+		FetchedAt:   snapshot.FetchedAt,
+		Changes:     changes,
+		OTel:        snapshot.OTel,
+	}
+	if err := s.masterGw.PushSnapshotDelta(ctx, delta); err != nil {
+		return err
+	}
+
+	s.lastIndex = index
+	s.version = delta.Version
+	log.Debug("增量快照已推送", "version", delta.Version, "changes", len(changes))
+	return nil
+}
+
+// pushFull 推送全量快照，成功后重建内容索引作为后续增量的基准
+func (s *Scheduler) pushFull(ctx context.Context, snapshot *cluster.ClusterSnapshot) error {
+	snapshot.Version = s.version + 1
+	if err := s.masterGw.PushSnapshot(ctx, snapshot); err != nil {
+		s.lastIndex = nil
+		return err
+	}
+	s.version = snapshot.Version
+	s.lastFullSync = time.Now()
+
+	if s.config.SnapshotFullResyncInterval <= 0 {
+		return nil
+	}
+	index, err := cluster.IndexSnapshot(snapshot)
+	if err != nil {
+		log.Warn("快照索引失败，下次继续全量推送", "err", err)
+		s.lastIndex = nil
+		return nil
+	}
+	s.lastIndex = index
+	return nil
+}
+
 // =============================================================================
 // 指令轮询循环
 // =============================================================================
@@ -263,34 +345,74 @@ func (s *Scheduler) pollAndExecuteCommands(topic string) (bool, bool) {
 	}
 
 	// 并发执行所有指令
+	// 长耗时指令（drain、exec 会话）脱离本轮轮询独立运行，避免阻塞同一 topic 的后续指令
 	var wg sync.WaitGroup
 	for i := range commands {
 		cmd := &commands[i]
+		if longRunningActions[cmd.Action] {
+			// 自行控制超时，不受长轮询超时限制；中间进度作为 partial 结果上报
+			execCtx := service.WithProgress(s.ctx, s.progressReporter(cmd.ID))
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.executeCommand(execCtx, cmd)
+			}()
+			continue
+		}
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			start := time.Now()
-			result := s.commandSvc.Execute(ctx, cmd)
-			elapsed := time.Since(start)
-
-			// 构建可读的 action 标识
-			action := cmd.Action
-			if sub, ok := cmd.Params["sub_action"].(string); ok && sub != "" {
-				action += "/" + sub
-			}
-
-			// 上报结果
-			if err := s.masterGw.ReportResult(ctx, result); err != nil {
-				log.Error("指令失败", "action", action, "elapsed", elapsed.Round(time.Millisecond), "err", err)
-			} else {
-				log.Info("指令完成", "action", action, "elapsed", elapsed.Round(time.Millisecond), "success", result.Success)
-			}
+			s.executeCommand(ctx, cmd)
 		}()
 	}
 	wg.Wait()
 	return true, false
 }
 
+// executeCommand 执行单条指令并上报结果
+func (s *Scheduler) executeCommand(ctx context.Context, cmd *command.Command) {
+	start := time.Now()
+	result := s.commandSvc.Execute(ctx, cmd)
+	elapsed := time.Since(start)
+
+	// 构建可读的 action 标识
+	action := cmd.Action
+	if sub, ok := cmd.Params["sub_action"].(string); ok && sub != "" {
+		action += "/" + sub
+	}
+
+	// 上报结果
+	if err := s.masterGw.ReportResult(ctx, result); err != nil {
+		log.Error("指令失败", "action", action, "elapsed", elapsed.Round(time.Millisecond), "err", err)
+	} else {
+		log.Info("指令完成", "action", action, "elapsed", elapsed.Round(time.Millisecond), "success", result.Success)
+	}
+}
+
+// longRunningActions 执行时间可能超过长轮询超时的指令
+var longRunningActions = map[string]bool{
+	command.ActionDrain:      true,
+	command.ActionExec:       true,
+	command.ActionStreamLogs: true,
+}
+
+// progressReporter 将指令进度作为 partial 结果上报 Master
+func (s *Scheduler) progressReporter(cmdID string) service.ProgressFunc {
+	return func(progress []command.ProgressEntry) {
+		ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+		defer cancel()
+		err := s.masterGw.ReportResult(ctx, &command.Result{
+			CommandID:  cmdID,
+			Partial:    true,
+			Progress:   progress,
+			ExecutedAt: time.Now(),
+		})
+		if err != nil {
+			log.Warn("进度上报失败", "cmd", cmdID, "err", err)
+		}
+	}
+}
+
 // =============================================================================
 // 心跳循环
 // =============================================================================
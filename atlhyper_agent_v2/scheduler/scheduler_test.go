@@ -131,6 +131,117 @@ func TestCollectAndPushSnapshot_PushError(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// 增量快照测试
+// =============================================================================
+
+func namedPod(name, phase string) cluster.Pod {
+	return cluster.Pod{
+		Summary: cluster.PodSummary{Name: name, Namespace: "default"},
+		Status:  cluster.PodStatus{Phase: phase},
+	}
+}
+
+// newDeltaScheduler 创建启用增量的调度器，每次 Collect 依次返回 snapshots 中的快照
+func newDeltaScheduler(gw *mock.MasterGateway, snapshots ...*cluster.ClusterSnapshot) *Scheduler {
+	i := 0
+	snapshotSvc := &mock.SnapshotService{
+		CollectFn: func(ctx context.Context) (*cluster.ClusterSnapshot, error) {
+			snap := snapshots[i]
+			if i < len(snapshots)-1 {
+				i++
+			}
+			return snap, nil
+		},
+	}
+	s := newTestScheduler(snapshotSvc, &mock.CommandService{}, gw)
+	s.config.SnapshotPushTimeout = 5 * time.Second
+	s.config.SnapshotFullResyncInterval = time.Hour
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+func TestCollectAndPushSnapshot_DeltaAfterFull(t *testing.T) {
+	gw := &mock.MasterGateway{}
+	s := newDeltaScheduler(gw,
+		&cluster.ClusterSnapshot{Pods: []cluster.Pod{namedPod("a", "Running"), namedPod("b", "Pending")}},
+		&cluster.ClusterSnapshot{Pods: []cluster.Pod{namedPod("a", "Running"), namedPod("b", "Running")}},
+	)
+	defer s.cancel()
+
+	s.collectAndPushSnapshot()
+	if gw.PushSnapshotCalls != 1 || gw.LastSnapshot.Version != 1 {
+		t.Fatalf("first push should be full v1, got calls=%d", gw.PushSnapshotCalls)
+	}
+
+	s.collectAndPushSnapshot()
+	if gw.PushSnapshotCalls != 1 || gw.PushDeltaCalls != 1 {
+		t.Fatalf("second push should be delta, got full=%d delta=%d", gw.PushSnapshotCalls, gw.PushDeltaCalls)
+	}
+	d := gw.LastDelta
+	if d.BaseVersion != 1 || d.Version != 2 {
+		t.Errorf("delta versions = %d→%d, want 1→2", d.BaseVersion, d.Version)
+	}
+	if len(d.Changes) != 1 || d.Changes[0].Name != "b" || d.Changes[0].Op != cluster.ChangeUpdated {
+		t.Errorf("unexpected changes: %+v", d.Changes)
+	}
+}
+
+func TestCollectAndPushSnapshot_DeltaConflictFallsBackToFull(t *testing.T) {
+	gw := &mock.MasterGateway{
+		PushDeltaFn: func(ctx context.Context, delta *cluster.SnapshotDelta) error {
+			return cluster.ErrDeltaBaseMismatch
+		},
+	}
+	s := newDeltaScheduler(gw, &cluster.ClusterSnapshot{Pods: []cluster.Pod{namedPod("a", "Running")}})
+	defer s.cancel()
+
+	s.collectAndPushSnapshot()
+	s.collectAndPushSnapshot()
+
+	if gw.PushDeltaCalls != 1 {
+		t.Errorf("PushDeltaCalls = %d, want 1", gw.PushDeltaCalls)
+	}
+	if gw.PushSnapshotCalls != 2 {
+		t.Errorf("PushSnapshotCalls = %d, want 2 (full resync after conflict)", gw.PushSnapshotCalls)
+	}
+	if gw.LastSnapshot.Version != 2 {
+		t.Errorf("resync version = %d, want 2", gw.LastSnapshot.Version)
+	}
+}
+
+func TestCollectAndPushSnapshot_DeltaErrorForcesFullNext(t *testing.T) {
+	gw := &mock.MasterGateway{
+		PushDeltaFn: func(ctx context.Context, delta *cluster.SnapshotDelta) error {
+			return errors.New("connection reset")
+		},
+	}
+	s := newDeltaScheduler(gw, &cluster.ClusterSnapshot{Pods: []cluster.Pod{namedPod("a", "Running")}})
+	defer s.cancel()
+
+	s.collectAndPushSnapshot() // full
+	s.collectAndPushSnapshot() // delta 失败
+	s.collectAndPushSnapshot() // 结果未知，改为全量
+
+	if gw.PushDeltaCalls != 1 || gw.PushSnapshotCalls != 2 {
+		t.Errorf("got full=%d delta=%d, want full=2 delta=1", gw.PushSnapshotCalls, gw.PushDeltaCalls)
+	}
+}
+
+func TestCollectAndPushSnapshot_DeltaDisabled(t *testing.T) {
+	gw := &mock.MasterGateway{}
+	s := newDeltaScheduler(gw, &cluster.ClusterSnapshot{Pods: []cluster.Pod{namedPod("a", "Running")}})
+	s.config.SnapshotFullResyncInterval = 0
+	defer s.cancel()
+
+	s.collectAndPushSnapshot()
+	s.collectAndPushSnapshot()
+
+	if gw.PushSnapshotCalls != 2 || gw.PushDeltaCalls != 0 {
+		t.Errorf("got full=%d delta=%d, want full=2 delta=0", gw.PushSnapshotCalls, gw.PushDeltaCalls)
+	}
+}
+
 // =============================================================================
 // pollAndExecuteCommands 测试
 // =============================================================================
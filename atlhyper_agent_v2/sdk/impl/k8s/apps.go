@@ -19,6 +19,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -27,6 +28,14 @@ import (
 // =============================================================================
 
 func (c *Client) ListDeployments(ctx context.Context, namespace string, opts sdk.ListOptions) ([]appsv1.Deployment, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*appsv1.Deployment, error) {
+			if namespace == "" {
+				return l.deployments.List(sel)
+			}
+			return l.deployments.Deployments(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -115,6 +124,14 @@ func (c *Client) UpdateDeploymentImage(ctx context.Context, namespace, name, con
 // =============================================================================
 
 func (c *Client) ListStatefulSets(ctx context.Context, namespace string, opts sdk.ListOptions) ([]appsv1.StatefulSet, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*appsv1.StatefulSet, error) {
+			if namespace == "" {
+				return l.statefulSets.List(sel)
+			}
+			return l.statefulSets.StatefulSets(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -136,6 +153,14 @@ func (c *Client) GetStatefulSet(ctx context.Context, namespace, name string) (*a
 // =============================================================================
 
 func (c *Client) ListDaemonSets(ctx context.Context, namespace string, opts sdk.ListOptions) ([]appsv1.DaemonSet, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*appsv1.DaemonSet, error) {
+			if namespace == "" {
+				return l.daemonSets.List(sel)
+			}
+			return l.daemonSets.DaemonSets(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -157,6 +182,14 @@ func (c *Client) GetDaemonSet(ctx context.Context, namespace, name string) (*app
 // =============================================================================
 
 func (c *Client) ListReplicaSets(ctx context.Context, namespace string, opts sdk.ListOptions) ([]appsv1.ReplicaSet, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*appsv1.ReplicaSet, error) {
+			if namespace == "" {
+				return l.replicaSets.List(sel)
+			}
+			return l.replicaSets.ReplicaSets(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
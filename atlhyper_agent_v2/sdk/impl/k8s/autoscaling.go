@@ -0,0 +1,42 @@
+// Package k8s K8sClient 接口的具体实现
+//
+// autoscaling.go - autoscaling/v2 资源操作
+//
+// 本文件实现 autoscaling API 组的资源操作：
+//   - HorizontalPodAutoscaler: List
+package k8s
+
+import (
+	"context"
+
+	"AtlHyper/atlhyper_agent_v2/sdk"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// =============================================================================
+// HorizontalPodAutoscaler 操作
+// =============================================================================
+
+func (c *Client) ListHPAs(ctx context.Context, namespace string, opts sdk.ListOptions) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*autoscalingv2.HorizontalPodAutoscaler, error) {
+			if namespace == "" {
+				return l.hpas.List(sel)
+			}
+			return l.hpas.HorizontalPodAutoscalers(namespace).List(sel)
+		})
+	}
+	listOpts := metav1.ListOptions{
+		LabelSelector: opts.LabelSelector,
+		FieldSelector: opts.FieldSelector,
+		Limit:         opts.Limit,
+	}
+	list, err := c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
@@ -14,6 +14,7 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // =============================================================================
@@ -21,6 +22,14 @@ import (
 // =============================================================================
 
 func (c *Client) ListJobs(ctx context.Context, namespace string, opts sdk.ListOptions) ([]batchv1.Job, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*batchv1.Job, error) {
+			if namespace == "" {
+				return l.jobs.List(sel)
+			}
+			return l.jobs.Jobs(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -42,6 +51,14 @@ func (c *Client) GetJob(ctx context.Context, namespace, name string) (*batchv1.J
 // =============================================================================
 
 func (c *Client) ListCronJobs(ctx context.Context, namespace string, opts sdk.ListOptions) ([]batchv1.CronJob, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*batchv1.CronJob, error) {
+			if namespace == "" {
+				return l.cronJobs.List(sel)
+			}
+			return l.cronJobs.CronJobs(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -10,11 +10,13 @@
 //   - networking.go: networkingv1 资源 (Ingress)
 //   - metrics.go: metrics 资源 (NodeMetrics)
 //   - generic.go: 通用操作 (Delete, Dynamic)
+//   - informer.go: SharedInformer 本地缓存 (List 优先读缓存)
 package k8s
 
 import (
 	"fmt"
 	"net/http"
+	"sync/atomic"
 
 	"AtlHyper/atlhyper_agent_v2/sdk"
 	"AtlHyper/common/logger"
@@ -40,6 +42,8 @@ type Client struct {
 	metricsClient *metricsv.Clientset // 可能为 nil (集群未安装 metrics-server)
 	config        *rest.Config
 	httpClient    *http.Client // Dynamic 查询用 (已配置 TLS/Auth)
+
+	listers atomic.Pointer[informerListers] // Informer 缓存，未启动或同步失败时为 nil
 }
 
 // NewClient 创建 K8s 客户端实现
@@ -3,7 +3,7 @@
 // core.go - corev1 资源操作
 //
 // 本文件实现 corev1 API 组的资源操作：
-//   - Pod: List, Get, Delete, GetLogs
+//   - Pod: List, Get, Delete, Evict, GetLogs
 //   - Node: List, Get, Cordon, Uncordon
 //   - Service: List, Get
 //   - ConfigMap: List, Get
@@ -22,7 +22,9 @@ import (
 	"AtlHyper/atlhyper_agent_v2/sdk"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -31,6 +33,14 @@ import (
 // =============================================================================
 
 func (c *Client) ListPods(ctx context.Context, namespace string, opts sdk.ListOptions) ([]corev1.Pod, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*corev1.Pod, error) {
+			if namespace == "" {
+				return l.pods.List(sel)
+			}
+			return l.pods.Pods(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -55,14 +65,51 @@ func (c *Client) DeletePod(ctx context.Context, namespace, name string, opts sdk
 	return c.clientset.CoreV1().Pods(namespace).Delete(ctx, name, deleteOpts)
 }
 
+// EvictPod 驱逐 Pod
+//
+// 使用 policy/v1 Eviction 子资源，API Server 会检查 PodDisruptionBudget，
+// 违反 PDB 时返回 429 TooManyRequests
+func (c *Client) EvictPod(ctx context.Context, namespace, name string, opts sdk.EvictOptions) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	if opts.GracePeriodSeconds != nil {
+		eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: opts.GracePeriodSeconds}
+	}
+	return c.clientset.CoreV1().Pods(namespace).EvictV1(ctx, eviction)
+}
+
 // GetPodLogs 获取 Pod 日志
 //
 // 通过流式读取获取 Pod 容器日志
 func (c *Client) GetPodLogs(ctx context.Context, namespace, name string, opts sdk.LogOptions) (string, error) {
+	opts.Follow = false
+	stream, err := c.StreamPodLogs(ctx, namespace, name, opts)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	buf := new(bytes.Buffer)
+	_, err = io.Copy(buf, stream)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// StreamPodLogs 打开 Pod 日志流
+//
+// Follow 为 true 时流在容器运行期间保持打开，ctx 取消后结束。
+func (c *Client) StreamPodLogs(ctx context.Context, namespace, name string, opts sdk.LogOptions) (io.ReadCloser, error) {
 	podLogOpts := &corev1.PodLogOptions{
 		Container:  opts.Container,
 		Timestamps: opts.Timestamps,
 		Previous:   opts.Previous,
+		Follow:     opts.Follow,
 	}
 	if opts.TailLines > 0 {
 		podLogOpts.TailLines = &opts.TailLines
@@ -71,19 +118,7 @@ func (c *Client) GetPodLogs(ctx context.Context, namespace, name string, opts sd
 		podLogOpts.SinceSeconds = &opts.SinceSeconds
 	}
 
-	req := c.clientset.CoreV1().Pods(namespace).GetLogs(name, podLogOpts)
-	stream, err := req.Stream(ctx)
-	if err != nil {
-		return "", err
-	}
-	defer stream.Close()
-
-	buf := new(bytes.Buffer)
-	_, err = io.Copy(buf, stream)
-	if err != nil {
-		return "", err
-	}
-	return buf.String(), nil
+	return c.clientset.CoreV1().Pods(namespace).GetLogs(name, podLogOpts).Stream(ctx)
 }
 
 // =============================================================================
@@ -91,6 +126,9 @@ func (c *Client) GetPodLogs(ctx context.Context, namespace, name string, opts sd
 // =============================================================================
 
 func (c *Client) ListNodes(ctx context.Context, opts sdk.ListOptions) ([]corev1.Node, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, l.nodes.List)
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -130,6 +168,14 @@ func (c *Client) UncordonNode(ctx context.Context, name string) error {
 // =============================================================================
 
 func (c *Client) ListServices(ctx context.Context, namespace string, opts sdk.ListOptions) ([]corev1.Service, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*corev1.Service, error) {
+			if namespace == "" {
+				return l.services.List(sel)
+			}
+			return l.services.Services(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -151,6 +197,14 @@ func (c *Client) GetService(ctx context.Context, namespace, name string) (*corev
 // =============================================================================
 
 func (c *Client) ListConfigMaps(ctx context.Context, namespace string, opts sdk.ListOptions) ([]corev1.ConfigMap, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*corev1.ConfigMap, error) {
+			if namespace == "" {
+				return l.configMaps.List(sel)
+			}
+			return l.configMaps.ConfigMaps(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -172,6 +226,14 @@ func (c *Client) GetConfigMap(ctx context.Context, namespace, name string) (*cor
 // =============================================================================
 
 func (c *Client) ListSecrets(ctx context.Context, namespace string, opts sdk.ListOptions) ([]corev1.Secret, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*corev1.Secret, error) {
+			if namespace == "" {
+				return l.secrets.List(sel)
+			}
+			return l.secrets.Secrets(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -193,6 +255,9 @@ func (c *Client) GetSecret(ctx context.Context, namespace, name string) (*corev1
 // =============================================================================
 
 func (c *Client) ListNamespaces(ctx context.Context, opts sdk.ListOptions) ([]corev1.Namespace, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, l.namespaces.List)
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -214,6 +279,14 @@ func (c *Client) GetNamespace(ctx context.Context, name string) (*corev1.Namespa
 // =============================================================================
 
 func (c *Client) ListEvents(ctx context.Context, namespace string, opts sdk.ListOptions) ([]corev1.Event, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*corev1.Event, error) {
+			if namespace == "" {
+				return l.events.List(sel)
+			}
+			return l.events.Events(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -231,6 +304,9 @@ func (c *Client) ListEvents(ctx context.Context, namespace string, opts sdk.List
 // =============================================================================
 
 func (c *Client) ListPersistentVolumes(ctx context.Context, opts sdk.ListOptions) ([]corev1.PersistentVolume, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, l.persistentVolumes.List)
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -244,6 +320,14 @@ func (c *Client) ListPersistentVolumes(ctx context.Context, opts sdk.ListOptions
 }
 
 func (c *Client) ListPersistentVolumeClaims(ctx context.Context, namespace string, opts sdk.ListOptions) ([]corev1.PersistentVolumeClaim, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*corev1.PersistentVolumeClaim, error) {
+			if namespace == "" {
+				return l.persistentVolumeClaims.List(sel)
+			}
+			return l.persistentVolumeClaims.PersistentVolumeClaims(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -261,6 +345,14 @@ func (c *Client) ListPersistentVolumeClaims(ctx context.Context, namespace strin
 // =============================================================================
 
 func (c *Client) ListResourceQuotas(ctx context.Context, namespace string, opts sdk.ListOptions) ([]corev1.ResourceQuota, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*corev1.ResourceQuota, error) {
+			if namespace == "" {
+				return l.resourceQuotas.List(sel)
+			}
+			return l.resourceQuotas.ResourceQuotas(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -278,6 +370,14 @@ func (c *Client) ListResourceQuotas(ctx context.Context, namespace string, opts
 // =============================================================================
 
 func (c *Client) ListLimitRanges(ctx context.Context, namespace string, opts sdk.ListOptions) ([]corev1.LimitRange, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*corev1.LimitRange, error) {
+			if namespace == "" {
+				return l.limitRanges.List(sel)
+			}
+			return l.limitRanges.LimitRanges(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -295,6 +395,14 @@ func (c *Client) ListLimitRanges(ctx context.Context, namespace string, opts sdk
 // =============================================================================
 
 func (c *Client) ListServiceAccounts(ctx context.Context, namespace string, opts sdk.ListOptions) ([]corev1.ServiceAccount, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*corev1.ServiceAccount, error) {
+			if namespace == "" {
+				return l.serviceAccounts.List(sel)
+			}
+			return l.serviceAccounts.ServiceAccounts(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -0,0 +1,89 @@
+// Package k8s K8sClient 接口的具体实现
+//
+// exec.go - Pod exec 交互式会话
+//
+// 使用 remotecommand 与 kubelet 建立流式连接，
+// 优先 WebSocket 协议，API Server 不支持时回退到 SPDY。
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"AtlHyper/atlhyper_agent_v2/sdk"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// ExecPod 在容器内执行命令
+func (c *Client) ExecPod(ctx context.Context, namespace, name string, opts sdk.ExecOptions) error {
+	if len(opts.Command) == 0 {
+		return fmt.Errorf("command is required")
+	}
+
+	req := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: opts.Container,
+			Command:   opts.Command,
+			Stdin:     opts.Stdin != nil,
+			Stdout:    opts.Stdout != nil,
+			Stderr:    opts.Stderr != nil && !opts.TTY,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+
+	spdyExec, err := remotecommand.NewSPDYExecutor(c.config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("create spdy executor: %w", err)
+	}
+	wsExec, err := remotecommand.NewWebSocketExecutor(c.config, "GET", req.URL().String())
+	if err != nil {
+		return fmt.Errorf("create websocket executor: %w", err)
+	}
+	// 与 kubectl 一致：WebSocket 升级失败时回退 SPDY
+	executor, err := remotecommand.NewFallbackExecutor(wsExec, spdyExec, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return fmt.Errorf("create executor: %w", err)
+	}
+
+	streamOpts := remotecommand.StreamOptions{
+		Stdin:  opts.Stdin,
+		Stdout: opts.Stdout,
+		Tty:    opts.TTY,
+	}
+	if !opts.TTY {
+		streamOpts.Stderr = opts.Stderr
+	}
+	if opts.Resize != nil {
+		streamOpts.TerminalSizeQueue = &sizeQueue{ctx: ctx, ch: opts.Resize}
+	}
+
+	return executor.StreamWithContext(ctx, streamOpts)
+}
+
+// sizeQueue 将 channel 适配为 remotecommand.TerminalSizeQueue
+type sizeQueue struct {
+	ctx context.Context
+	ch  <-chan sdk.TerminalSize
+}
+
+// Next 返回下一个终端尺寸，返回 nil 表示结束
+func (q *sizeQueue) Next() *remotecommand.TerminalSize {
+	select {
+	case <-q.ctx.Done():
+		return nil
+	case size, ok := <-q.ch:
+		if !ok {
+			return nil
+		}
+		return &remotecommand.TerminalSize{Width: size.Width, Height: size.Height}
+	}
+}
@@ -5,6 +5,7 @@
 // 本文件实现通用的资源操作：
 //   - Delete: 通用删除
 //   - Dynamic: 动态 API 查询 (仅 GET，AI 专用)
+//   - ServerResources: API 发现 (含 CRD)
 package k8s
 
 import (
@@ -16,6 +17,9 @@ import (
 	"strings"
 
 	"AtlHyper/atlhyper_agent_v2/sdk"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 )
 
 // =============================================================================
@@ -85,3 +89,51 @@ func (c *Client) Dynamic(ctx context.Context, req sdk.DynamicRequest) (*sdk.Dyna
 		Body:       body,
 	}, nil
 }
+
+// ServerResources 列出 API Server 可用资源
+//
+// 使用 ServerPreferredResources，每个 API 组只返回首选版本；
+// 跳过子资源 (如 pods/log) 和不支持 get 的资源。
+// 部分聚合 API 不可用 (如 metrics-server 故障) 时返回其余组的结果，不视为错误。
+func (c *Client) ServerResources(ctx context.Context) ([]sdk.APIResource, error) {
+	lists, err := c.clientset.Discovery().ServerPreferredResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, fmt.Errorf("discover server resources: %w", err)
+		}
+		log.Warn("部分 API 组发现失败", "err", err)
+	}
+
+	var result []sdk.APIResource
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || !hasVerb(r.Verbs, "get") {
+				continue
+			}
+			result = append(result, sdk.APIResource{
+				Group:      gv.Group,
+				Version:    gv.Version,
+				Kind:       r.Kind,
+				Resource:   r.Name,
+				Namespaced: r.Namespaced,
+				ShortNames: r.ShortNames,
+				Verbs:      r.Verbs,
+			})
+		}
+	}
+	return result, nil
+}
+
+// hasVerb 判断动词列表是否包含指定动词
+func hasVerb(verbs []string, verb string) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
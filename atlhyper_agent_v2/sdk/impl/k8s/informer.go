@@ -0,0 +1,161 @@
+// Package k8s K8sClient 接口的具体实现
+//
+// informer.go - SharedInformer 本地缓存
+//
+// 快照每个周期都需要全部资源。启动 Informer 后，List* 直接读取 watch 维护的
+// 本地缓存，不再每个周期对 API Server 全量 List。
+// 带 FieldSelector / Limit 的查询缓存无法满足，仍然直连 API Server。
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"AtlHyper/atlhyper_agent_v2/sdk"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	autoscalinglisters "k8s.io/client-go/listers/autoscaling/v2"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	networkinglisters "k8s.io/client-go/listers/networking/v1"
+)
+
+// informerSyncTimeout 等待 Informer 首次同步的超时
+// 超时通常意味着缺少 watch 权限，此时回退为直连 API Server
+const informerSyncTimeout = 2 * time.Minute
+
+// informerListers 已同步的 Lister 集合
+type informerListers struct {
+	pods                   corelisters.PodLister
+	nodes                  corelisters.NodeLister
+	services               corelisters.ServiceLister
+	configMaps             corelisters.ConfigMapLister
+	secrets                corelisters.SecretLister
+	namespaces             corelisters.NamespaceLister
+	events                 corelisters.EventLister
+	persistentVolumes      corelisters.PersistentVolumeLister
+	persistentVolumeClaims corelisters.PersistentVolumeClaimLister
+	resourceQuotas         corelisters.ResourceQuotaLister
+	limitRanges            corelisters.LimitRangeLister
+	serviceAccounts        corelisters.ServiceAccountLister
+	deployments            appslisters.DeploymentLister
+	statefulSets           appslisters.StatefulSetLister
+	daemonSets             appslisters.DaemonSetLister
+	replicaSets            appslisters.ReplicaSetLister
+	jobs                   batchlisters.JobLister
+	cronJobs               batchlisters.CronJobLister
+	hpas                   autoscalinglisters.HorizontalPodAutoscalerLister
+	ingresses              networkinglisters.IngressLister
+	networkPolicies        networkinglisters.NetworkPolicyLister
+}
+
+// StartInformers 启动 SharedInformer 并等待缓存同步
+//
+// Informer 随 ctx 运行；同步失败时停止 Informer 并返回错误，List* 保持直连 API Server。
+// resync 为 0 表示不做周期性 resync（快照差异由上层按内容计算，无需重放事件）。
+func (c *Client) StartInformers(ctx context.Context) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(c.clientset, 0,
+		informers.WithTransform(stripManagedFields))
+
+	core := factory.Core().V1()
+	apps := factory.Apps().V1()
+	batch := factory.Batch().V1()
+	networking := factory.Networking().V1()
+	l := &informerListers{
+		pods:                   core.Pods().Lister(),
+		nodes:                  core.Nodes().Lister(),
+		services:               core.Services().Lister(),
+		configMaps:             core.ConfigMaps().Lister(),
+		secrets:                core.Secrets().Lister(),
+		namespaces:             core.Namespaces().Lister(),
+		events:                 core.Events().Lister(),
+		persistentVolumes:      core.PersistentVolumes().Lister(),
+		persistentVolumeClaims: core.PersistentVolumeClaims().Lister(),
+		resourceQuotas:         core.ResourceQuotas().Lister(),
+		limitRanges:            core.LimitRanges().Lister(),
+		serviceAccounts:        core.ServiceAccounts().Lister(),
+		deployments:            apps.Deployments().Lister(),
+		statefulSets:           apps.StatefulSets().Lister(),
+		daemonSets:             apps.DaemonSets().Lister(),
+		replicaSets:            apps.ReplicaSets().Lister(),
+		jobs:                   batch.Jobs().Lister(),
+		cronJobs:               batch.CronJobs().Lister(),
+		hpas:                   factory.Autoscaling().V2().HorizontalPodAutoscalers().Lister(),
+		ingresses:              networking.Ingresses().Lister(),
+		networkPolicies:        networking.NetworkPolicies().Lister(),
+	}
+
+	stopCh := make(chan struct{})
+	factory.Start(stopCh)
+
+	syncCtx, cancelSync := context.WithTimeout(ctx, informerSyncTimeout)
+	defer cancelSync()
+	for typ, ok := range factory.WaitForCacheSync(syncCtx.Done()) {
+		if !ok {
+			close(stopCh)
+			factory.Shutdown()
+			return fmt.Errorf("informer cache sync failed: %v", typ)
+		}
+	}
+
+	c.listers.Store(l)
+	go func() {
+		<-ctx.Done()
+		c.listers.Store(nil)
+		close(stopCh)
+		factory.Shutdown()
+	}()
+
+	log.Info("Informer 缓存已同步，资源列表改为读取本地缓存")
+	return nil
+}
+
+// cachedListers 返回已同步的 Lister；opts 无法由缓存满足时返回 nil
+func (c *Client) cachedListers(opts sdk.ListOptions) *informerListers {
+	if opts.FieldSelector != "" || opts.Limit > 0 {
+		return nil
+	}
+	return c.listers.Load()
+}
+
+// listCached 从 Lister 读取对象并按 namespace/name 排序（与 API Server List 顺序一致）
+//
+// 缓存中的对象为共享只读副本，这里复制一层结构体后返回，调用方只做读取和转换。
+func listCached[T any, PT interface {
+	*T
+	metav1.Object
+}](opts sdk.ListOptions, list func(labels.Selector) ([]PT, error)) ([]T, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+	objs, err := list(selector)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].GetNamespace() != objs[j].GetNamespace() {
+			return objs[i].GetNamespace() < objs[j].GetNamespace()
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+	items := make([]T, len(objs))
+	for i, o := range objs {
+		items[i] = *o
+	}
+	return items, nil
+}
+
+// stripManagedFields 丢弃 managedFields，减少缓存内存占用
+func stripManagedFields(obj interface{}) (interface{}, error) {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	return obj, nil
+}
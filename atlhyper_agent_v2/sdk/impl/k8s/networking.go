@@ -13,6 +13,7 @@ import (
 
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // =============================================================================
@@ -20,6 +21,14 @@ import (
 // =============================================================================
 
 func (c *Client) ListIngresses(ctx context.Context, namespace string, opts sdk.ListOptions) ([]networkingv1.Ingress, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*networkingv1.Ingress, error) {
+			if namespace == "" {
+				return l.ingresses.List(sel)
+			}
+			return l.ingresses.Ingresses(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
@@ -41,6 +50,14 @@ func (c *Client) GetIngress(ctx context.Context, namespace, name string) (*netwo
 // =============================================================================
 
 func (c *Client) ListNetworkPolicies(ctx context.Context, namespace string, opts sdk.ListOptions) ([]networkingv1.NetworkPolicy, error) {
+	if l := c.cachedListers(opts); l != nil {
+		return listCached(opts, func(sel labels.Selector) ([]*networkingv1.NetworkPolicy, error) {
+			if namespace == "" {
+				return l.networkPolicies.List(sel)
+			}
+			return l.networkPolicies.NetworkPolicies(namespace).List(sel)
+		})
+	}
 	listOpts := metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 		FieldSelector: opts.FieldSelector,
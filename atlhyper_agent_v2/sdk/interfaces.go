@@ -23,8 +23,10 @@ package sdk
 import (
 	"context"
 	"database/sql"
+	"io"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -43,7 +45,13 @@ type K8sClient interface {
 	ListPods(ctx context.Context, namespace string, opts ListOptions) ([]corev1.Pod, error)
 	GetPod(ctx context.Context, namespace, name string) (*corev1.Pod, error)
 	DeletePod(ctx context.Context, namespace, name string, opts DeleteOptions) error
+	// EvictPod 通过 Eviction API 驱逐 Pod（受 PodDisruptionBudget 约束，被拒绝时返回 429 错误）
+	EvictPod(ctx context.Context, namespace, name string, opts EvictOptions) error
 	GetPodLogs(ctx context.Context, namespace, name string, opts LogOptions) (string, error)
+	// StreamPodLogs 打开 Pod 日志流（Follow 时持续输出，调用方负责 Close）
+	StreamPodLogs(ctx context.Context, namespace, name string, opts LogOptions) (io.ReadCloser, error)
+	// ExecPod 在容器内执行命令并双向流式传输（阻塞直到命令退出或 ctx 取消）
+	ExecPod(ctx context.Context, namespace, name string, opts ExecOptions) error
 
 	// =========================================================================
 	// Node 操作
@@ -141,6 +149,12 @@ type K8sClient interface {
 	ListCronJobs(ctx context.Context, namespace string, opts ListOptions) ([]batchv1.CronJob, error)
 	GetCronJob(ctx context.Context, namespace, name string) (*batchv1.CronJob, error)
 
+	// =========================================================================
+	// HorizontalPodAutoscaler 操作
+	// =========================================================================
+
+	ListHPAs(ctx context.Context, namespace string, opts ListOptions) ([]autoscalingv2.HorizontalPodAutoscaler, error)
+
 	// =========================================================================
 	// PV/PVC 操作
 	// =========================================================================
@@ -179,8 +193,20 @@ type K8sClient interface {
 	Delete(ctx context.Context, gvk GroupVersionKind, namespace, name string, opts DeleteOptions) error
 	Dynamic(ctx context.Context, req DynamicRequest) (*DynamicResponse, error)
 
+	// ServerResources 通过 Discovery API 列出 API Server 可用资源（每个组的首选版本，含 CRD）
+	ServerResources(ctx context.Context) ([]APIResource, error)
+
 	// RestConfig 返回 REST 配置（用于构建 dynamic/discovery 客户端）
 	RestConfig() *rest.Config
+
+	// =========================================================================
+	// Informer 缓存
+	// =========================================================================
+
+	// StartInformers 启动 SharedInformer 并等待缓存同步
+	// 同步后 List* 读取 watch 维护的本地缓存（FieldSelector / Limit 查询除外），
+	// 避免快照每个周期对 API Server 全量 List
+	StartInformers(ctx context.Context) error
 }
 
 // =============================================================================
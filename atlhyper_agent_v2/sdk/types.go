@@ -6,6 +6,8 @@
 // 这些类型被接口方法使用，也被实现层使用。
 package sdk
 
+import "io"
+
 // =============================================================================
 // 查询选项
 // =============================================================================
@@ -23,6 +25,28 @@ type DeleteOptions struct {
 	Force              bool   // 是否强制删除
 }
 
+// EvictOptions 驱逐选项
+type EvictOptions struct {
+	GracePeriodSeconds *int64 // 优雅终止时间 (秒)，nil 使用 Pod 自身配置
+}
+
+// ExecOptions 容器内执行命令选项
+type ExecOptions struct {
+	Container string              // 容器名称 (多容器 Pod 需指定)
+	Command   []string            // 执行的命令
+	TTY       bool                // 是否分配终端 (TTY 模式下 stderr 合并到 stdout)
+	Stdin     io.Reader           // 标准输入 (nil 表示不附加)
+	Stdout    io.Writer           // 标准输出
+	Stderr    io.Writer           // 标准错误 (TTY 模式下忽略)
+	Resize    <-chan TerminalSize // 终端尺寸变化 (可选)
+}
+
+// TerminalSize 终端尺寸
+type TerminalSize struct {
+	Width  uint16
+	Height uint16
+}
+
 // LogOptions 日志选项
 type LogOptions struct {
 	Container    string // 容器名称 (多容器 Pod 需指定)
@@ -30,6 +54,7 @@ type LogOptions struct {
 	SinceSeconds int64  // 返回最近 N 秒的日志
 	Timestamps   bool   // 是否包含时间戳
 	Previous     bool   // 是否获取之前容器的日志
+	Follow       bool   // 是否持续跟踪 (仅 StreamPodLogs 使用)
 }
 
 // =============================================================================
@@ -59,6 +84,21 @@ type DynamicResponse struct {
 	Body       []byte // 响应体
 }
 
+// =============================================================================
+// API 发现
+// =============================================================================
+
+// APIResource API Server 提供的资源类型 (含 CRD)
+type APIResource struct {
+	Group      string   // API 组，core 组为空字符串
+	Version    string   // 首选版本，如 "v1"
+	Kind       string   // 资源类型，如 "IngressRoute"
+	Resource   string   // 复数小写资源名，如 "ingressroutes"
+	Namespaced bool     // 是否命名空间级资源
+	ShortNames []string // 简称，如 ["cert"]
+	Verbs      []string // 支持的动词
+}
+
 // =============================================================================
 // Metrics 数据
 // =============================================================================
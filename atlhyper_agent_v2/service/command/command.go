@@ -10,7 +10,9 @@
 //   - get_logs: 获取 Pod 日志
 //   - cordon: 封锁节点
 //   - uncordon: 解封节点
-//   - dynamic: 动态 API 调用 (AI 只读查询)
+//   - drain: 排空节点 (cordon + Eviction API，遵守 PDB，逐 Pod 上报进度)
+//   - exec: 交互式容器会话 (经 Master 中转的 WebSocket 双向流)
+//   - dynamic: 动态 API 调用 (只读查询，支持 API 发现与 CRD)
 //   - apply_manifests: 应用多文档 YAML (Server-Side Apply)
 //
 // 执行流程:
@@ -27,6 +29,7 @@ import (
 	"strings"
 	"time"
 
+	"AtlHyper/atlhyper_agent_v2/gateway"
 	"AtlHyper/atlhyper_agent_v2/model"
 	"AtlHyper/atlhyper_agent_v2/repository"
 	"AtlHyper/atlhyper_agent_v2/sdk"
@@ -44,6 +47,8 @@ import (
 //   - logQueryRepo: Log 按需查询 (ClickHouse, 可选)
 //   - metricsQueryRepo: Metrics 按需查询 (ClickHouse, 可选)
 //   - sloQueryRepo: SLO 按需查询 (ClickHouse, 可选)
+//   - execStreams: exec 会话流 (Master 通信, 可选)
+//   - discovery: API 发现结果缓存 (dynamic 查询 CRD 时使用)
 type commandService struct {
 	k8sClient   sdk.K8sClient
 	podRepo     repository.PodRepository
@@ -54,6 +59,17 @@ type commandService struct {
 	logQueryRepo     repository.LogQueryRepository
 	metricsQueryRepo repository.MetricsQueryRepository
 	sloQueryRepo     repository.SLOQueryRepository
+
+	// exec 会话流 (可选)
+	execStreams ExecStreamOpener
+
+	// API 发现缓存
+	discovery apiResourceCache
+}
+
+// ExecStreamOpener 打开与 Master 的 exec 会话流（由 gateway.MasterGateway 实现）
+type ExecStreamOpener interface {
+	OpenExecStream(ctx context.Context, sessionID string) (gateway.ExecStream, error)
 }
 
 // NewCommandService 创建指令服务
@@ -65,6 +81,7 @@ func NewCommandService(
 	logQueryRepo repository.LogQueryRepository,
 	metricsQueryRepo repository.MetricsQueryRepository,
 	sloQueryRepo repository.SLOQueryRepository,
+	execStreams ExecStreamOpener,
 ) service.CommandService {
 	return &commandService{
 		k8sClient:        k8sClient,
@@ -74,6 +91,7 @@ func NewCommandService(
 		logQueryRepo:     logQueryRepo,
 		metricsQueryRepo: metricsQueryRepo,
 		sloQueryRepo:     sloQueryRepo,
+		execStreams:      execStreams,
 	}
 }
 
@@ -117,6 +135,17 @@ func (s *commandService) Execute(ctx context.Context, cmd *command.Command) *com
 		err = s.handleCordon(ctx, cmd)
 	case command.ActionUncordon:
 		err = s.handleUncordon(ctx, cmd)
+	case command.ActionDrain:
+		var summary *drainSummary
+		summary, err = s.handleDrain(ctx, cmd)
+		if summary != nil {
+			data = summary
+			result.Progress = summary.Pods
+		}
+	case command.ActionExec:
+		data, err = s.handleExec(ctx, cmd)
+	case command.ActionStreamLogs:
+		data, err = s.handleStreamLogs(ctx, cmd)
 	case command.ActionQueryTraces:
 		data, err = s.handleQueryTraces(ctx, cmd)
 	case command.ActionQueryTraceDetail:
@@ -138,6 +167,12 @@ func (s *commandService) Execute(ctx context.Context, cmd *command.Command) *com
 	if err != nil {
 		result.Success = false
 		result.Error = err.Error()
+		// 部分完成的指令（如 drain）失败时也保留详细结果
+		if summary, ok := data.(*drainSummary); ok {
+			if b, e := json.Marshal(summary); e == nil {
+				result.Output = string(b)
+			}
+		}
 	} else {
 		result.Success = true
 		if data != nil {
@@ -269,20 +304,30 @@ func (s *commandService) handleGetSecret(ctx context.Context, cmd *command.Comma
 //
 // 将 AI 的高级语义 (command + kind) 翻译为 K8s API 路径，
 // 通过 GenericRepository.Execute 执行只读 GET 请求。
+// 内置 Kind 走静态映射表，其余 Kind (CRD 等) 通过 API 发现解析。
 //
 // Params 格式:
-//   - command: get / list / describe / get_events
-//   - kind: Pod / Deployment / Node / ...
+//   - command: get / list / describe / get_events / api_resources
+//   - kind: Pod / Deployment / Node / IngressRoute / ... (支持复数名、简称)
+//   - group: API 组 (可选，同名 Kind 存在于多个组时必填)
+//   - version: API 版本 (可选，默认首选版本)
 //   - label_selector: 标签过滤 (list 时使用)
 //   - involved_kind: 事件关联资源类型 (get_events 时使用)
 //   - involved_name: 事件关联资源名称 (get_events 时使用)
+//   - all: api_resources 时包含内置资源
+//   - namespaced_only: 仅允许指定命名空间内的资源（Master 对仅有命名空间级授权的用户设置）
 func (s *commandService) handleDynamic(ctx context.Context, cmd *command.Command) (string, error) {
 	var params struct {
-		Command       string `json:"command"`
-		Kind          string `json:"kind"`
-		LabelSelector string `json:"label_selector"`
-		InvolvedKind  string `json:"involved_kind"`
-		InvolvedName  string `json:"involved_name"`
+		Command        string `json:"command"`
+		Kind           string `json:"kind"`
+		Group          string `json:"group"`
+		Version        string `json:"version"`
+		LabelSelector  string `json:"label_selector"`
+		InvolvedKind   string `json:"involved_kind"`
+		InvolvedName   string `json:"involved_name"`
+		All            bool   `json:"all"`
+		CustomOnly     bool   `json:"custom_only"`     // 仅允许自定义资源（CRD / 聚合 API）
+		NamespacedOnly bool   `json:"namespaced_only"` // 仅允许指定命名空间内的资源
 	}
 	if err := s.parseParams(cmd.Params, &params); err != nil {
 		return "", fmt.Errorf("invalid dynamic params: %w", err)
@@ -291,15 +336,31 @@ func (s *commandService) handleDynamic(ctx context.Context, cmd *command.Command
 	if params.Command == "" {
 		return "", fmt.Errorf("command is required in params")
 	}
+	if params.Command == "api_resources" {
+		return s.handleAPIResources(ctx, params.Group, params.All, cmd.Source == "ai")
+	}
 	if params.Kind == "" && params.Command != "get_events" {
 		return "", fmt.Errorf("kind is required in params")
 	}
 
-	// 构建 API 路径
-	path, err := buildAPIPath(params.Command, params.Kind, cmd.Namespace, cmd.Name)
+	// 解析 Kind 并构建 API 路径
+	kind := params.Kind
+	if params.Command == "get_events" {
+		kind = "Event"
+	}
+	target, err := s.resolveDynamicPath(ctx, params.Command, params.Group, params.Version, kind, cmd.Namespace, cmd.Name)
 	if err != nil {
 		return "", fmt.Errorf("build API path: %w", err)
 	}
+	if forbiddenDynamicKinds[target.Kind] {
+		return "", fmt.Errorf("kind %s is not allowed for dynamic queries", target.Kind)
+	}
+	if params.CustomOnly && target.Builtin {
+		return "", fmt.Errorf("kind %s is a built-in resource, only custom resources can be queried", target.Kind)
+	}
+	if params.NamespacedOnly && (target.ClusterScope || cmd.Namespace == "") {
+		return "", fmt.Errorf("kind %s is not allowed for dynamic queries outside a namespace", target.Kind)
+	}
 
 	// 构建查询参数
 	query := map[string]string{}
@@ -321,7 +382,7 @@ func (s *commandService) handleDynamic(ctx context.Context, cmd *command.Command
 
 	// 执行查询
 	resp, err := s.genericRepo.Execute(ctx, &model.DynamicRequest{
-		Path:  path,
+		Path:  target.Path,
 		Query: query,
 	})
 	if err != nil {
@@ -430,7 +491,11 @@ func buildAPIPath(command, kind, namespace, name string) (string, error) {
 	if !ok {
 		return "", fmt.Errorf("unsupported kind: %s", kind)
 	}
+	return buildResourcePath(command, kind, info, namespace, name)
+}
 
+// buildResourcePath 根据已解析的资源信息构建 K8s API 路径
+func buildResourcePath(command, kind string, info resourceInfo, namespace, name string) (string, error) {
 	switch command {
 	case "list", "get_events":
 		if info.ClusterScope || namespace == "" {
@@ -0,0 +1,237 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_agent_v2/model"
+)
+
+// apiDiscoveryTTL API 发现结果缓存时长
+// 查询的 Kind 未命中缓存时会立即刷新一次，新安装的 CRD 无需等待过期
+var apiDiscoveryTTL = 5 * time.Minute
+
+// builtinAPIGroups Kubernetes 内置 API 组，其余组视为自定义资源 (CRD / 聚合 API)
+var builtinAPIGroups = map[string]bool{
+	"":                             true,
+	"apps":                         true,
+	"batch":                        true,
+	"autoscaling":                  true,
+	"policy":                       true,
+	"extensions":                   true,
+	"networking.k8s.io":            true,
+	"rbac.authorization.k8s.io":    true,
+	"storage.k8s.io":               true,
+	"admissionregistration.k8s.io": true,
+	"apiextensions.k8s.io":         true,
+	"apiregistration.k8s.io":       true,
+	"authentication.k8s.io":        true,
+	"authorization.k8s.io":         true,
+	"certificates.k8s.io":          true,
+	"coordination.k8s.io":          true,
+	"discovery.k8s.io":             true,
+	"events.k8s.io":                true,
+	"flowcontrol.apiserver.k8s.io": true,
+	"node.k8s.io":                  true,
+	"scheduling.k8s.io":            true,
+	"resource.k8s.io":              true,
+	"metrics.k8s.io":               true,
+}
+
+// forbiddenDynamicKinds dynamic 指令禁止查询的资源类型（任何来源；Secret 数据须走带审计的 get_secret 指令）
+// 在 Kind 解析之后校验，防止通过复数名/简称 (secrets、小写 secret) 绕过 Master 黑名单
+var forbiddenDynamicKinds = map[string]bool{
+	"Secret": true,
+}
+
+// apiResourceCache API 发现结果缓存
+type apiResourceCache struct {
+	mu        sync.Mutex
+	resources []model.APIResource
+	fetchedAt time.Time
+}
+
+// listAPIResources 获取可查询的资源类型（带缓存），refresh=true 时忽略缓存
+func (s *commandService) listAPIResources(ctx context.Context, refresh bool) ([]model.APIResource, error) {
+	s.discovery.mu.Lock()
+	defer s.discovery.mu.Unlock()
+
+	if !refresh && s.discovery.resources != nil && time.Since(s.discovery.fetchedAt) < apiDiscoveryTTL {
+		return s.discovery.resources, nil
+	}
+
+	resources, err := s.genericRepo.ListAPIResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("discover api resources: %w", err)
+	}
+	s.discovery.resources = resources
+	s.discovery.fetchedAt = time.Now()
+	return resources, nil
+}
+
+// dynamicTarget 动态查询解析结果
+type dynamicTarget struct {
+	Kind         string // 规范 Kind 名
+	Builtin      bool   // 是否为内置资源
+	ClusterScope bool   // 是否集群级资源
+	Path         string
+}
+
+// resolveDynamicPath 解析 Kind 并构建 API 路径
+//
+// 内置 Kind 且未指定 group/version 时直接使用静态映射表；
+// 其余情况通过 API 发现解析，支持 CRD、复数名、简称以及 "resource.group" 写法。
+func (s *commandService) resolveDynamicPath(ctx context.Context, command, group, version, kind, namespace, name string) (*dynamicTarget, error) {
+	if group == "" && version == "" {
+		if info, ok := kindToResource[kind]; ok {
+			path, err := buildAPIPath(command, kind, namespace, name)
+			if err != nil {
+				return nil, err
+			}
+			return &dynamicTarget{Kind: kind, Builtin: true, ClusterScope: info.ClusterScope, Path: path}, nil
+		}
+	}
+
+	res, err := s.resolveAPIResource(ctx, group, kind)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" {
+		version = res.Version
+	}
+	info := resourceInfo{
+		APIPrefix:    apiPrefix(res.Group, version),
+		Resource:     res.Resource,
+		ClusterScope: !res.Namespaced,
+	}
+	path, err := buildResourcePath(command, res.Kind, info, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return &dynamicTarget{Kind: res.Kind, Builtin: builtinAPIGroups[res.Group], ClusterScope: info.ClusterScope, Path: path}, nil
+}
+
+// resolveAPIResource 在 API 发现结果中查找资源类型
+// 未命中时强制刷新一次缓存（新安装的 CRD）
+func (s *commandService) resolveAPIResource(ctx context.Context, group, kind string) (*model.APIResource, error) {
+	// "certificates.cert-manager.io" → resource=certificates, group=cert-manager.io
+	if group == "" {
+		if res, g, ok := strings.Cut(kind, "."); ok {
+			kind, group = res, g
+		}
+	}
+
+	for _, refresh := range []bool{false, true} {
+		resources, err := s.listAPIResources(ctx, refresh)
+		if err != nil {
+			return nil, err
+		}
+		matches := matchAPIResources(resources, group, kind)
+		switch {
+		case len(matches) == 1:
+			return &matches[0], nil
+		case len(matches) > 1:
+			return pickAPIResource(matches, kind)
+		}
+	}
+
+	if group != "" {
+		return nil, fmt.Errorf("unsupported kind: %s in group %s", kind, group)
+	}
+	return nil, fmt.Errorf("unsupported kind: %s (use api_resources to list available kinds)", kind)
+}
+
+// matchAPIResources 按 Kind / 复数名 / 简称匹配（不区分大小写），group 非空时限定 API 组
+func matchAPIResources(resources []model.APIResource, group, kind string) []model.APIResource {
+	var matches []model.APIResource
+	for _, r := range resources {
+		if group != "" && r.Group != group {
+			continue
+		}
+		if strings.EqualFold(r.Kind, kind) || strings.EqualFold(r.Resource, kind) || containsFold(r.ShortNames, kind) {
+			matches = append(matches, r)
+		}
+	}
+	return matches
+}
+
+// pickAPIResource 多个 API 组存在同名资源时优先 core 组，否则要求指定 group
+func pickAPIResource(matches []model.APIResource, kind string) (*model.APIResource, error) {
+	groups := make([]string, 0, len(matches))
+	for i := range matches {
+		if matches[i].Group == "" {
+			return &matches[i], nil
+		}
+		groups = append(groups, matches[i].Group)
+	}
+	sort.Strings(groups)
+	return nil, fmt.Errorf("kind %s is ambiguous, specify group: %s", kind, strings.Join(groups, ", "))
+}
+
+// handleAPIResources 列出可查询的资源类型
+// 默认只返回自定义资源；all=true 时包含内置资源
+func (s *commandService) handleAPIResources(ctx context.Context, group string, all, forAI bool) (string, error) {
+	resources, err := s.listAPIResources(ctx, true)
+	if err != nil {
+		return "", err
+	}
+
+	result := make([]model.APIResource, 0)
+	for _, r := range resources {
+		if group != "" && r.Group != group {
+			continue
+		}
+		if !all && group == "" && builtinAPIGroups[r.Group] {
+			continue
+		}
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Group != result[j].Group {
+			return result[i].Group < result[j].Group
+		}
+		return result[i].Kind < result[j].Kind
+	})
+
+	if forAI {
+		return summarizeAPIResources(result), nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// summarizeAPIResources 将资源类型列表转为表格文本 (AI 专用)
+func summarizeAPIResources(resources []model.APIResource) string {
+	columns := []column{{Header: "KIND"}, {Header: "GROUP"}, {Header: "VERSION"}, {Header: "RESOURCE"}, {Header: "NAMESPACED"}}
+	rows := make([][]string, 0, len(resources))
+	for _, r := range resources {
+		rows = append(rows, []string{r.Kind, r.Group, r.Version, r.Resource, fmt.Sprintf("%t", r.Namespaced)})
+	}
+	return formatTable("APIResources", columns, rows)
+}
+
+// apiPrefix 根据 group/version 构建 API 路径前缀
+func apiPrefix(group, version string) string {
+	if group == "" {
+		return "/api/" + version
+	}
+	return "/apis/" + group + "/" + version
+}
+
+// containsFold 不区分大小写判断字符串切片是否包含指定值
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,279 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"AtlHyper/atlhyper_agent_v2/model"
+	"AtlHyper/atlhyper_agent_v2/testutil/mock"
+	"AtlHyper/model_v3/command"
+)
+
+// testAPIResources 模拟 API 发现结果：内置资源 + Traefik / cert-manager / Linkerd CRD
+func testAPIResources() []model.APIResource {
+	return []model.APIResource{
+		{Group: "", Version: "v1", Kind: "Pod", Resource: "pods", Namespaced: true, ShortNames: []string{"po"}},
+		{Group: "", Version: "v1", Kind: "Secret", Resource: "secrets", Namespaced: true},
+		{Group: "apps", Version: "v1", Kind: "Deployment", Resource: "deployments", Namespaced: true, ShortNames: []string{"deploy"}},
+		{Group: "traefik.io", Version: "v1alpha1", Kind: "IngressRoute", Resource: "ingressroutes", Namespaced: true},
+		{Group: "cert-manager.io", Version: "v1", Kind: "Certificate", Resource: "certificates", Namespaced: true, ShortNames: []string{"cert", "certs"}},
+		{Group: "cert-manager.io", Version: "v1", Kind: "ClusterIssuer", Resource: "clusterissuers", Namespaced: false},
+		{Group: "linkerd.io", Version: "v1alpha2", Kind: "ServiceProfile", Resource: "serviceprofiles", Namespaced: true, ShortNames: []string{"sp"}},
+		{Group: "acme.cert-manager.io", Version: "v1", Kind: "Certificate", Resource: "certificates", Namespaced: true},
+	}
+}
+
+// newDiscoveryService 创建带 API 发现结果的服务，返回最近一次请求的路径
+func newDiscoveryService(resources []model.APIResource, discoveryCalls *int) (*commandService, *string) {
+	var lastPath string
+	svc := &commandService{
+		podRepo: &mock.PodRepository{},
+		genericRepo: &mock.GenericRepository{
+			ListAPIResourcesFn: func(ctx context.Context) ([]model.APIResource, error) {
+				if discoveryCalls != nil {
+					*discoveryCalls++
+				}
+				return resources, nil
+			},
+			ExecuteFn: func(ctx context.Context, req *model.DynamicRequest) (*model.DynamicResponse, error) {
+				lastPath = req.Path
+				return &model.DynamicResponse{StatusCode: 200, Body: []byte(`{"kind":"IngressRouteList","items":[]}`)}, nil
+			},
+		},
+	}
+	return svc, &lastPath
+}
+
+func dynamicCmd(source, namespace, name string, params map[string]any) *command.Command {
+	return &command.Command{
+		ID:        "cmd-dynamic-crd",
+		Action:    command.ActionDynamic,
+		Namespace: namespace,
+		Name:      name,
+		Source:    source,
+		Params:    params,
+	}
+}
+
+func TestExecute_Dynamic_CustomResourcePaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		resName   string
+		params    map[string]any
+		want      string
+	}{
+		{
+			name:      "list CRD by kind",
+			namespace: "default",
+			params:    map[string]any{"command": "list", "kind": "IngressRoute"},
+			want:      "/apis/traefik.io/v1alpha1/namespaces/default/ingressroutes",
+		},
+		{
+			name:      "get CRD by short name with group",
+			namespace: "default",
+			resName:   "web-tls",
+			params:    map[string]any{"command": "get", "kind": "cert", "group": "cert-manager.io"},
+			want:      "/apis/cert-manager.io/v1/namespaces/default/certificates/web-tls",
+		},
+		{
+			name:   "cluster scoped CRD ignores namespace",
+			params: map[string]any{"command": "list", "kind": "clusterissuers"},
+			want:   "/apis/cert-manager.io/v1/clusterissuers",
+		},
+		{
+			name:      "resource.group notation",
+			namespace: "default",
+			params:    map[string]any{"command": "list", "kind": "serviceprofiles.linkerd.io"},
+			want:      "/apis/linkerd.io/v1alpha2/namespaces/default/serviceprofiles",
+		},
+		{
+			name:      "explicit version overrides preferred version",
+			namespace: "default",
+			params:    map[string]any{"command": "list", "kind": "IngressRoute", "version": "v1alpha2"},
+			want:      "/apis/traefik.io/v1alpha2/namespaces/default/ingressroutes",
+		},
+		{
+			name:      "builtin kind by short name",
+			namespace: "default",
+			params:    map[string]any{"command": "list", "kind": "deploy"},
+			want:      "/apis/apps/v1/namespaces/default/deployments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, lastPath := newDiscoveryService(testAPIResources(), nil)
+			result := svc.Execute(context.Background(), dynamicCmd("web", tt.namespace, tt.resName, tt.params))
+			if !result.Success {
+				t.Fatalf("expected success, got error: %s", result.Error)
+			}
+			if *lastPath != tt.want {
+				t.Errorf("path = %q, want %q", *lastPath, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecute_Dynamic_AmbiguousKindRequiresGroup(t *testing.T) {
+	svc, _ := newDiscoveryService(testAPIResources(), nil)
+
+	result := svc.Execute(context.Background(), dynamicCmd("web", "default", "", map[string]any{"command": "list", "kind": "Certificate"}))
+	if result.Success {
+		t.Fatal("expected failure for ambiguous kind")
+	}
+	if !strings.Contains(result.Error, "acme.cert-manager.io, cert-manager.io") {
+		t.Errorf("expected candidate groups in error, got %q", result.Error)
+	}
+}
+
+func TestExecute_Dynamic_BuiltinKindSkipsDiscovery(t *testing.T) {
+	calls := 0
+	svc, lastPath := newDiscoveryService(testAPIResources(), &calls)
+
+	result := svc.Execute(context.Background(), dynamicCmd("web", "default", "", map[string]any{"command": "list", "kind": "Pod"}))
+	if !result.Success {
+		t.Fatalf("expected success, got error: %s", result.Error)
+	}
+	if calls != 0 {
+		t.Errorf("builtin kind should not trigger discovery, got %d calls", calls)
+	}
+	if *lastPath != "/api/v1/namespaces/default/pods" {
+		t.Errorf("path = %q", *lastPath)
+	}
+}
+
+func TestExecute_Dynamic_DiscoveryCacheRefreshOnMiss(t *testing.T) {
+	calls := 0
+	resources := testAPIResources()
+	svc, _ := newDiscoveryService(resources, &calls)
+
+	// 首次查询填充缓存，再次查询命中缓存
+	for i := 0; i < 2; i++ {
+		result := svc.Execute(context.Background(), dynamicCmd("web", "default", "", map[string]any{"command": "list", "kind": "IngressRoute"}))
+		if !result.Success {
+			t.Fatalf("expected success, got error: %s", result.Error)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected cached discovery, got %d calls", calls)
+	}
+
+	// 未知 Kind 触发一次强制刷新
+	result := svc.Execute(context.Background(), dynamicCmd("web", "default", "", map[string]any{"command": "list", "kind": "Middleware"}))
+	if result.Success {
+		t.Fatal("expected failure for unknown kind")
+	}
+	if calls != 2 {
+		t.Errorf("expected refresh on miss, got %d calls", calls)
+	}
+	if !strings.Contains(result.Error, "api_resources") {
+		t.Errorf("expected hint to use api_resources, got %q", result.Error)
+	}
+}
+
+func TestExecute_Dynamic_AIForbiddenAfterResolution(t *testing.T) {
+	svc, lastPath := newDiscoveryService(testAPIResources(), nil)
+
+	result := svc.Execute(context.Background(), dynamicCmd("ai", "default", "", map[string]any{"command": "list", "kind": "secrets"}))
+	if result.Success {
+		t.Fatal("expected AI query for secrets to be rejected")
+	}
+	if *lastPath != "" {
+		t.Errorf("forbidden query should not reach API server, got path %q", *lastPath)
+	}
+}
+
+func TestExecute_Dynamic_SecretForbiddenForAllSources(t *testing.T) {
+	for _, source := range []string{"web", "ai"} {
+		svc, lastPath := newDiscoveryService(testAPIResources(), nil)
+
+		result := svc.Execute(context.Background(), dynamicCmd(source, "default", "", map[string]any{"command": "list", "kind": "Secret"}))
+		if result.Success {
+			t.Fatalf("expected %s query for Secret to be rejected", source)
+		}
+		if *lastPath != "" {
+			t.Errorf("forbidden query should not reach API server, got path %q", *lastPath)
+		}
+	}
+}
+
+func TestExecute_Dynamic_CustomOnly(t *testing.T) {
+	svc, lastPath := newDiscoveryService(testAPIResources(), nil)
+
+	for _, kind := range []string{"Pod", "deployments", "po"} {
+		*lastPath = ""
+		result := svc.Execute(context.Background(), dynamicCmd("web", "default", "", map[string]any{"command": "list", "kind": kind, "custom_only": true}))
+		if result.Success {
+			t.Errorf("expected built-in kind %q to be rejected", kind)
+		}
+		if *lastPath != "" {
+			t.Errorf("kind %q: rejected query should not reach API server, got path %q", kind, *lastPath)
+		}
+	}
+
+	result := svc.Execute(context.Background(), dynamicCmd("web", "default", "", map[string]any{"command": "list", "kind": "IngressRoute", "custom_only": true}))
+	if !result.Success {
+		t.Fatalf("expected custom resource query to succeed, got error: %s", result.Error)
+	}
+}
+
+func TestExecute_Dynamic_NamespacedOnly(t *testing.T) {
+	svc, lastPath := newDiscoveryService(testAPIResources(), nil)
+
+	rejected := []struct{ namespace, kind string }{
+		{"default", "Node"},          // 内置集群级资源
+		{"default", "ClusterIssuer"}, // 集群级 CRD
+		{"", "IngressRoute"},         // 未指定命名空间即跨命名空间列表
+	}
+	for _, c := range rejected {
+		*lastPath = ""
+		result := svc.Execute(context.Background(), dynamicCmd("web", c.namespace, "", map[string]any{"command": "list", "kind": c.kind, "namespaced_only": true}))
+		if result.Success {
+			t.Errorf("%s in %q: expected rejection", c.kind, c.namespace)
+		}
+		if *lastPath != "" {
+			t.Errorf("%s in %q: rejected query should not reach API server, got path %q", c.kind, c.namespace, *lastPath)
+		}
+	}
+
+	result := svc.Execute(context.Background(), dynamicCmd("web", "default", "", map[string]any{"command": "list", "kind": "IngressRoute", "namespaced_only": true}))
+	if !result.Success {
+		t.Fatalf("expected namespaced query to succeed, got error: %s", result.Error)
+	}
+}
+
+func TestExecute_Dynamic_APIResources(t *testing.T) {
+	svc, _ := newDiscoveryService(testAPIResources(), nil)
+
+	result := svc.Execute(context.Background(), dynamicCmd("web", "", "", map[string]any{"command": "api_resources"}))
+	if !result.Success {
+		t.Fatalf("expected success, got error: %s", result.Error)
+	}
+	var got []model.APIResource
+	if err := json.Unmarshal([]byte(result.Output), &got); err != nil {
+		t.Fatalf("expected JSON output: %v", err)
+	}
+	if len(got) != 5 {
+		t.Fatalf("expected 5 custom resources, got %d: %+v", len(got), got)
+	}
+	for _, r := range got {
+		if builtinAPIGroups[r.Group] {
+			t.Errorf("builtin resource %s should be filtered", r.Kind)
+		}
+	}
+
+	// all=true 包含内置资源
+	result = svc.Execute(context.Background(), dynamicCmd("web", "", "", map[string]any{"command": "api_resources", "all": true}))
+	if err := json.Unmarshal([]byte(result.Output), &got); err != nil || len(got) != len(testAPIResources()) {
+		t.Errorf("all=true: got %d resources, err=%v", len(got), err)
+	}
+
+	// AI 来源返回表格
+	result = svc.Execute(context.Background(), dynamicCmd("ai", "", "", map[string]any{"command": "api_resources", "group": "traefik.io"}))
+	if !strings.Contains(result.Output, "APIResources (1):") || !strings.Contains(result.Output, "IngressRoute") {
+		t.Errorf("unexpected AI output: %q", result.Output)
+	}
+}
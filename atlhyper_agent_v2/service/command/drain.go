@@ -0,0 +1,282 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_agent_v2/model"
+	"AtlHyper/atlhyper_agent_v2/service"
+	"AtlHyper/model_v3/command"
+)
+
+// drain 参数默认值与上限
+const (
+	defaultDrainTimeout = 5 * time.Minute
+	maxDrainTimeout     = time.Hour
+)
+
+// 轮询间隔（变量便于测试覆盖）
+var (
+	drainEvictRetryInterval = 5 * time.Second // PDB 拒绝后的重试间隔
+	drainPollInterval       = 2 * time.Second // 等待 Pod 退出的轮询间隔
+)
+
+// drainSummary drain 执行结果（序列化为 Result.Output）
+type drainSummary struct {
+	Node     string                  `json:"node"`
+	Total    int                     `json:"total"`
+	Evicted  int                     `json:"evicted"`
+	Skipped  int                     `json:"skipped"`
+	Blocked  int                     `json:"blocked"`
+	Failed   int                     `json:"failed"`
+	Pods     []command.ProgressEntry `json:"pods"`
+	Duration string                  `json:"duration"`
+}
+
+// handleDrain 处理节点排空指令
+//
+// 流程与 kubectl drain 一致:
+//  1. 封锁节点 (cordon)
+//  2. 列出节点上的 Pod 并分类: Mirror / DaemonSet Pod 跳过；
+//     使用 emptyDir（未允许 deleteEmptyDirData）或无控制器（未 force）的 Pod 阻止整个 drain
+//  3. 通过 Eviction API 并发驱逐，PDB 拒绝 (429) 时按间隔重试直到超时
+//  4. 等待 Pod 真正退出
+//
+// 每个 Pod 的状态变化通过 service.ReportProgress 中间上报。
+// 失败时节点保持封锁状态，由操作者决定是否 uncordon。
+//
+// Params 格式:
+//   - gracePeriodSeconds: 覆盖 Pod 的优雅终止时间 (可选)
+//   - timeoutSeconds: 整体超时 (默认 300，最大 3600)
+//   - deleteEmptyDirData: 允许驱逐使用 emptyDir 的 Pod
+//   - force: 允许驱逐无控制器管理的 Pod
+func (s *commandService) handleDrain(ctx context.Context, cmd *command.Command) (*drainSummary, error) {
+	var params struct {
+		GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+		TimeoutSeconds     int    `json:"timeoutSeconds,omitempty"`
+		DeleteEmptyDirData bool   `json:"deleteEmptyDirData,omitempty"`
+		Force              bool   `json:"force,omitempty"`
+	}
+	if cmd.Params != nil {
+		if err := s.parseParams(cmd.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid drain params: %w", err)
+		}
+	}
+	if cmd.Name == "" {
+		return nil, fmt.Errorf("node name is required")
+	}
+
+	timeout := defaultDrainTimeout
+	if params.TimeoutSeconds > 0 {
+		timeout = time.Duration(params.TimeoutSeconds) * time.Second
+	}
+	if timeout > maxDrainTimeout {
+		timeout = maxDrainTimeout
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// 1. 封锁节点
+	if err := s.genericRepo.CordonNode(ctx, cmd.Name); err != nil {
+		return nil, fmt.Errorf("cordon node: %w", err)
+	}
+
+	// 2. 列出并分类 Pod
+	pods, err := s.genericRepo.ListNodePods(ctx, cmd.Name)
+	if err != nil {
+		return nil, fmt.Errorf("list pods on node: %w", err)
+	}
+
+	tracker := newDrainTracker(ctx, pods)
+	var toEvict []int
+	for i, pod := range pods {
+		switch {
+		case pod.Mirror:
+			tracker.set(i, command.ProgressSkipped, "mirror pod")
+		case pod.OwnerKind == "DaemonSet" && !pod.Finished:
+			tracker.set(i, command.ProgressSkipped, "managed by DaemonSet")
+		case pod.HasEmptyDir && !params.DeleteEmptyDirData && !pod.Finished:
+			tracker.set(i, command.ProgressBlocked, "uses emptyDir (set deleteEmptyDirData to evict)")
+		case pod.OwnerKind == "" && !params.Force && !pod.Finished:
+			tracker.set(i, command.ProgressBlocked, "not managed by a controller (set force to evict)")
+		default:
+			toEvict = append(toEvict, i)
+		}
+	}
+	tracker.flush()
+
+	summary := func() *drainSummary {
+		sum := tracker.summary(cmd.Name)
+		sum.Duration = time.Since(start).Round(time.Millisecond).String()
+		return sum
+	}
+
+	if blocked := tracker.count(command.ProgressBlocked); blocked > 0 {
+		return summary(), fmt.Errorf("drain blocked: %d pod(s) cannot be evicted safely", blocked)
+	}
+
+	// 3. 并发驱逐并等待退出
+	evictOpts := model.EvictOptions{GracePeriodSeconds: params.GracePeriodSeconds}
+	var wg sync.WaitGroup
+	for _, i := range toEvict {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.evictAndWait(ctx, tracker, i, pods[i], evictOpts)
+		}(i)
+	}
+	wg.Wait()
+
+	sum := summary()
+	if sum.Failed > 0 {
+		return sum, fmt.Errorf("drain incomplete: %d pod(s) failed", sum.Failed)
+	}
+	return sum, nil
+}
+
+// evictAndWait 驱逐单个 Pod 并等待其退出
+func (s *commandService) evictAndWait(ctx context.Context, tracker *drainTracker, i int, pod model.NodePod, opts model.EvictOptions) {
+	tracker.set(i, command.ProgressEvicting, "")
+	tracker.flush()
+	defer tracker.flush()
+
+	// 驱逐（PDB 拒绝时重试）
+	for {
+		err := s.genericRepo.EvictPod(ctx, pod.Namespace, pod.Name, opts)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, model.ErrEvictionBlocked) {
+			tracker.set(i, command.ProgressFailed, err.Error())
+			return
+		}
+		if tracker.set(i, command.ProgressEvicting, "waiting for PodDisruptionBudget") {
+			tracker.flush()
+		}
+		select {
+		case <-ctx.Done():
+			tracker.set(i, command.ProgressFailed, "timed out waiting for PodDisruptionBudget")
+			return
+		case <-time.After(drainEvictRetryInterval):
+		}
+	}
+
+	if tracker.set(i, command.ProgressEvicting, "waiting for pod termination") {
+		tracker.flush()
+	}
+
+	// 等待退出
+	for {
+		gone, err := s.genericRepo.PodGone(ctx, pod.Namespace, pod.Name, pod.UID)
+		if err == nil && gone {
+			tracker.set(i, command.ProgressEvicted, "")
+			return
+		}
+		select {
+		case <-ctx.Done():
+			tracker.set(i, command.ProgressFailed, "timed out waiting for pod termination")
+			return
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
+
+// =============================================================================
+// 进度跟踪
+// =============================================================================
+
+// drainTracker 维护每个 Pod 的进度，变化时通过 service.ReportProgress 上报
+type drainTracker struct {
+	ctx     context.Context
+	mu      sync.Mutex
+	entries []command.ProgressEntry
+	dirty   bool
+}
+
+func newDrainTracker(ctx context.Context, pods []model.NodePod) *drainTracker {
+	entries := make([]command.ProgressEntry, len(pods))
+	now := time.Now()
+	for i, p := range pods {
+		entries[i] = command.ProgressEntry{
+			Target: p.Namespace + "/" + p.Name,
+			Status: command.ProgressPending,
+			Time:   now,
+		}
+	}
+	return &drainTracker{ctx: ctx, entries: entries, dirty: true}
+}
+
+// set 更新进度，返回是否有变化
+func (t *drainTracker) set(i int, status, message string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := &t.entries[i]
+	if e.Status == status && e.Message == message {
+		return false
+	}
+	e.Status = status
+	e.Message = message
+	e.Time = time.Now()
+	t.dirty = true
+	return true
+}
+
+// flush 有变化时上报当前进度
+func (t *drainTracker) flush() {
+	t.mu.Lock()
+	if !t.dirty {
+		t.mu.Unlock()
+		return
+	}
+	t.dirty = false
+	snapshot := t.snapshotLocked()
+	t.mu.Unlock()
+
+	service.ReportProgress(t.ctx, snapshot)
+}
+
+func (t *drainTracker) count(status string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := 0
+	for _, e := range t.entries {
+		if e.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (t *drainTracker) summary(node string) *drainSummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sum := &drainSummary{
+		Node:  node,
+		Total: len(t.entries),
+		Pods:  t.snapshotLocked(),
+	}
+	for _, e := range t.entries {
+		switch e.Status {
+		case command.ProgressEvicted:
+			sum.Evicted++
+		case command.ProgressSkipped:
+			sum.Skipped++
+		case command.ProgressBlocked:
+			sum.Blocked++
+		case command.ProgressFailed:
+			sum.Failed++
+		}
+	}
+	return sum
+}
+
+func (t *drainTracker) snapshotLocked() []command.ProgressEntry {
+	out := make([]command.ProgressEntry, len(t.entries))
+	copy(out, t.entries)
+	return out
+}
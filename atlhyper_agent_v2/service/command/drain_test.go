@@ -0,0 +1,232 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_agent_v2/model"
+	"AtlHyper/atlhyper_agent_v2/service"
+	"AtlHyper/atlhyper_agent_v2/testutil/mock"
+	"AtlHyper/model_v3/command"
+)
+
+func init() {
+	drainEvictRetryInterval = 5 * time.Millisecond
+	drainPollInterval = 5 * time.Millisecond
+}
+
+func drainCmd(params map[string]any) *command.Command {
+	return &command.Command{
+		ID:     "cmd-drain-1",
+		Action: command.ActionDrain,
+		Kind:   "Node",
+		Name:   "raspi-1",
+		Params: params,
+	}
+}
+
+func progressOf(result *command.Result, target string) command.ProgressEntry {
+	for _, p := range result.Progress {
+		if p.Target == target {
+			return p
+		}
+	}
+	return command.ProgressEntry{}
+}
+
+func TestExecute_Drain_SkipsAndEvicts(t *testing.T) {
+	var cordoned string
+	var mu sync.Mutex
+	evicted := map[string]bool{}
+
+	genericRepo := &mock.GenericRepository{
+		CordonNodeFn: func(ctx context.Context, name string) error {
+			cordoned = name
+			return nil
+		},
+		ListNodePodsFn: func(ctx context.Context, nodeName string) ([]model.NodePod, error) {
+			return []model.NodePod{
+				{Namespace: "default", Name: "web-1", OwnerKind: "ReplicaSet"},
+				{Namespace: "kube-system", Name: "kube-proxy-x", OwnerKind: "DaemonSet"},
+				{Namespace: "kube-system", Name: "etcd-raspi-1", Mirror: true},
+			}, nil
+		},
+		EvictPodFn: func(ctx context.Context, namespace, name string, opts model.EvictOptions) error {
+			mu.Lock()
+			evicted[namespace+"/"+name] = true
+			mu.Unlock()
+			return nil
+		},
+	}
+
+	svc := &commandService{genericRepo: genericRepo}
+	result := svc.Execute(context.Background(), drainCmd(nil))
+
+	if !result.Success {
+		t.Fatalf("expected success, got error: %s", result.Error)
+	}
+	if cordoned != "raspi-1" {
+		t.Errorf("expected node cordoned, got %q", cordoned)
+	}
+	if len(evicted) != 1 || !evicted["default/web-1"] {
+		t.Errorf("expected only default/web-1 evicted, got %v", evicted)
+	}
+	if p := progressOf(result, "default/web-1"); p.Status != command.ProgressEvicted {
+		t.Errorf("web-1 status: got %q, want evicted", p.Status)
+	}
+	if p := progressOf(result, "kube-system/kube-proxy-x"); p.Status != command.ProgressSkipped {
+		t.Errorf("daemonset pod status: got %q, want skipped", p.Status)
+	}
+	if p := progressOf(result, "kube-system/etcd-raspi-1"); p.Status != command.ProgressSkipped {
+		t.Errorf("mirror pod status: got %q, want skipped", p.Status)
+	}
+
+	var summary drainSummary
+	if err := json.Unmarshal([]byte(result.Output), &summary); err != nil {
+		t.Fatalf("output is not a drain summary: %v", err)
+	}
+	if summary.Evicted != 1 || summary.Skipped != 2 {
+		t.Errorf("summary: evicted=%d skipped=%d, want 1/2", summary.Evicted, summary.Skipped)
+	}
+}
+
+func TestExecute_Drain_EmptyDirBlocked(t *testing.T) {
+	evictCalled := false
+	genericRepo := &mock.GenericRepository{
+		ListNodePodsFn: func(ctx context.Context, nodeName string) ([]model.NodePod, error) {
+			return []model.NodePod{
+				{Namespace: "default", Name: "cache-1", OwnerKind: "ReplicaSet", HasEmptyDir: true},
+				{Namespace: "default", Name: "web-1", OwnerKind: "ReplicaSet"},
+			}, nil
+		},
+		EvictPodFn: func(ctx context.Context, namespace, name string, opts model.EvictOptions) error {
+			evictCalled = true
+			return nil
+		},
+	}
+
+	svc := &commandService{genericRepo: genericRepo}
+	result := svc.Execute(context.Background(), drainCmd(nil))
+
+	if result.Success {
+		t.Fatal("expected failure when emptyDir pod present without deleteEmptyDirData")
+	}
+	if evictCalled {
+		t.Error("no pod should be evicted when drain is blocked")
+	}
+	if p := progressOf(result, "default/cache-1"); p.Status != command.ProgressBlocked {
+		t.Errorf("cache-1 status: got %q, want blocked", p.Status)
+	}
+
+	// 允许 deleteEmptyDirData 后可以驱逐
+	result = svc.Execute(context.Background(), drainCmd(map[string]any{"deleteEmptyDirData": true}))
+	if !result.Success {
+		t.Fatalf("expected success with deleteEmptyDirData, got error: %s", result.Error)
+	}
+}
+
+func TestExecute_Drain_UnmanagedRequiresForce(t *testing.T) {
+	genericRepo := &mock.GenericRepository{
+		ListNodePodsFn: func(ctx context.Context, nodeName string) ([]model.NodePod, error) {
+			return []model.NodePod{{Namespace: "default", Name: "bare"}}, nil
+		},
+	}
+	svc := &commandService{genericRepo: genericRepo}
+
+	if result := svc.Execute(context.Background(), drainCmd(nil)); result.Success {
+		t.Error("expected failure for unmanaged pod without force")
+	}
+	if result := svc.Execute(context.Background(), drainCmd(map[string]any{"force": true})); !result.Success {
+		t.Errorf("expected success with force, got error: %s", result.Error)
+	}
+}
+
+func TestExecute_Drain_PDBRetry(t *testing.T) {
+	attempts := 0
+	var grace *int64
+	genericRepo := &mock.GenericRepository{
+		ListNodePodsFn: func(ctx context.Context, nodeName string) ([]model.NodePod, error) {
+			return []model.NodePod{{Namespace: "default", Name: "db-0", OwnerKind: "StatefulSet"}}, nil
+		},
+		EvictPodFn: func(ctx context.Context, namespace, name string, opts model.EvictOptions) error {
+			attempts++
+			grace = opts.GracePeriodSeconds
+			if attempts < 3 {
+				return fmt.Errorf("%w: too many requests", model.ErrEvictionBlocked)
+			}
+			return nil
+		},
+	}
+
+	var mu sync.Mutex
+	var reports [][]command.ProgressEntry
+	ctx := service.WithProgress(context.Background(), func(p []command.ProgressEntry) {
+		mu.Lock()
+		reports = append(reports, p)
+		mu.Unlock()
+	})
+
+	svc := &commandService{genericRepo: genericRepo}
+	result := svc.Execute(ctx, drainCmd(map[string]any{"gracePeriodSeconds": float64(10)}))
+
+	if !result.Success {
+		t.Fatalf("expected success after PDB retries, got error: %s", result.Error)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 eviction attempts, got %d", attempts)
+	}
+	if grace == nil || *grace != 10 {
+		t.Errorf("expected grace period 10 passed through, got %v", grace)
+	}
+
+	sawPDBWait := false
+	for _, r := range reports {
+		for _, e := range r {
+			if strings.Contains(e.Message, "PodDisruptionBudget") {
+				sawPDBWait = true
+			}
+		}
+	}
+	if !sawPDBWait {
+		t.Error("expected a progress report mentioning PodDisruptionBudget")
+	}
+}
+
+func TestExecute_Drain_Timeout(t *testing.T) {
+	genericRepo := &mock.GenericRepository{
+		ListNodePodsFn: func(ctx context.Context, nodeName string) ([]model.NodePod, error) {
+			return []model.NodePod{{Namespace: "default", Name: "web-1", OwnerKind: "ReplicaSet"}}, nil
+		},
+		PodGoneFn: func(ctx context.Context, namespace, name, uid string) (bool, error) {
+			return false, nil // Pod 一直不退出
+		},
+	}
+
+	svc := &commandService{genericRepo: genericRepo}
+	result := svc.Execute(context.Background(), drainCmd(map[string]any{"timeoutSeconds": float64(1)}))
+
+	if result.Success {
+		t.Fatal("expected failure on timeout")
+	}
+	if p := progressOf(result, "default/web-1"); p.Status != command.ProgressFailed {
+		t.Errorf("web-1 status: got %q, want failed", p.Status)
+	}
+	if result.Output == "" {
+		t.Error("expected drain summary in output even on failure")
+	}
+}
+
+func TestExecute_Drain_MissingNode(t *testing.T) {
+	svc := &commandService{genericRepo: &mock.GenericRepository{}}
+	cmd := drainCmd(nil)
+	cmd.Name = ""
+
+	if result := svc.Execute(context.Background(), cmd); result.Success {
+		t.Error("expected failure when node name is empty")
+	}
+}
@@ -0,0 +1,182 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"AtlHyper/atlhyper_agent_v2/gateway"
+	"AtlHyper/atlhyper_agent_v2/model"
+	"AtlHyper/model_v3/command"
+)
+
+// exec 会话时长默认值与上限（Master 侧另有独立限制）
+const (
+	defaultExecTimeout = 30 * time.Minute
+	maxExecTimeout     = 2 * time.Hour
+)
+
+// defaultExecCommand 未指定命令时启动的 shell
+var defaultExecCommand = []string{"/bin/sh"}
+
+// handleExec 处理交互式 exec 指令
+//
+// 流程:
+//  1. 按 sessionId 主动连接 Master 的 exec 流 (WebSocket)
+//  2. 通过 remotecommand 在容器内启动命令
+//  3. 双向转发: stdin/resize 帧 → 容器，stdout/stderr → 帧
+//  4. 命令退出、客户端关闭或超时后发送 exit 帧结束会话
+//
+// 返回值仅用于指令历史记录，会话数据全部经流传输。
+func (s *commandService) handleExec(ctx context.Context, cmd *command.Command) (string, error) {
+	var params command.ExecParams
+	if err := s.parseParams(cmd.Params, &params); err != nil {
+		return "", fmt.Errorf("invalid exec params: %w", err)
+	}
+	if params.SessionID == "" {
+		return "", fmt.Errorf("sessionId is required")
+	}
+	if cmd.Namespace == "" || cmd.Name == "" {
+		return "", fmt.Errorf("namespace and name are required")
+	}
+	if s.execStreams == nil {
+		return "", fmt.Errorf("exec streaming is not available")
+	}
+	if len(params.Command) == 0 {
+		params.Command = defaultExecCommand
+	}
+
+	timeout := defaultExecTimeout
+	if params.TimeoutSeconds > 0 {
+		timeout = time.Duration(params.TimeoutSeconds) * time.Second
+	}
+	if timeout > maxExecTimeout {
+		timeout = maxExecTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stream, err := s.execStreams.OpenExecStream(ctx, params.SessionID)
+	if err != nil {
+		return "", fmt.Errorf("open exec stream: %w", err)
+	}
+
+	stdinR, stdinW := io.Pipe()
+	resize := make(chan model.TerminalSize, 4)
+
+	// 客户端 → 容器
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(resize)
+		defer stdinW.Close()
+		for {
+			frame, err := stream.Recv()
+			if err != nil {
+				cancel()
+				return
+			}
+			switch frame.Type {
+			case command.FrameStdin:
+				if _, err := stdinW.Write([]byte(frame.Data)); err != nil {
+					return
+				}
+			case command.FrameResize:
+				select {
+				case resize <- model.TerminalSize{Width: frame.Cols, Height: frame.Rows}:
+				default: // 丢弃积压的尺寸变化，只需最终尺寸
+				}
+			case command.FrameClose:
+				cancel()
+				return
+			}
+		}
+	}()
+
+	// 容器 → 客户端
+	stdout := &frameWriter{stream: stream, frameType: command.FrameStdout}
+	stderr := &frameWriter{stream: stream, frameType: command.FrameStderr}
+	execErr := s.podRepo.Exec(ctx, cmd.Namespace, cmd.Name, model.ExecOptions{
+		Container: params.Container,
+		Command:   params.Command,
+		TTY:       params.TTY,
+		Stdin:     stdinR,
+		Stdout:    stdout,
+		Stderr:    stderr,
+		Resize:    resize,
+	})
+	stdinR.Close()
+	stdout.Flush()
+	stderr.Flush()
+
+	exit := &command.ExecFrame{Type: command.FrameExit}
+	var exitStatus interface{ ExitStatus() int }
+	switch {
+	case execErr == nil:
+	case errors.As(execErr, &exitStatus):
+		exit.Code = exitStatus.ExitStatus()
+	case ctx.Err() == context.DeadlineExceeded:
+		exit.Code = -1
+		exit.Error = "session timed out"
+	case ctx.Err() != nil:
+		// 客户端关闭会话
+	default:
+		exit.Type = command.FrameError
+		exit.Error = execErr.Error()
+	}
+	_ = stream.Send(exit)
+
+	// 关闭流以结束读取 goroutine
+	stream.Close()
+	wg.Wait()
+
+	if exit.Type == command.FrameError {
+		return "", fmt.Errorf("exec: %w", execErr)
+	}
+	return fmt.Sprintf("session %s closed, exit code %d", params.SessionID, exit.Code), nil
+}
+
+// frameWriter 将容器输出封装为帧
+//
+// 帧以 JSON 字符串传输，末尾不完整的 UTF-8 字符暂存到下次写入，
+// 避免多字节字符被拆分到两帧后被替换为乱码。
+type frameWriter struct {
+	stream    gateway.ExecStream
+	frameType string
+	pending   []byte
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	data := append(w.pending, p...)
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	w.pending = append([]byte(nil), data[cut:]...)
+
+	if cut > 0 {
+		if err := w.stream.Send(&command.ExecFrame{Type: w.frameType, Data: string(data[:cut])}); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush 发送暂存的剩余字节
+func (w *frameWriter) Flush() {
+	if len(w.pending) > 0 {
+		_ = w.stream.Send(&command.ExecFrame{Type: w.frameType, Data: string(w.pending)})
+		w.pending = nil
+	}
+}
@@ -0,0 +1,214 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"AtlHyper/atlhyper_agent_v2/gateway"
+	"AtlHyper/atlhyper_agent_v2/model"
+	"AtlHyper/atlhyper_agent_v2/testutil/mock"
+	"AtlHyper/model_v3/command"
+)
+
+// fakeExecStream 内存 ExecStream，in 为客户端发来的帧
+type fakeExecStream struct {
+	in     chan *command.ExecFrame
+	mu     sync.Mutex
+	sent   []*command.ExecFrame
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newFakeExecStream(frames ...*command.ExecFrame) *fakeExecStream {
+	s := &fakeExecStream{in: make(chan *command.ExecFrame, len(frames)+1), closed: make(chan struct{})}
+	for _, f := range frames {
+		s.in <- f
+	}
+	return s
+}
+
+func (s *fakeExecStream) Recv() (*command.ExecFrame, error) {
+	select {
+	case f := <-s.in:
+		return f, nil
+	case <-s.closed:
+		return nil, io.EOF
+	}
+}
+
+func (s *fakeExecStream) Send(f *command.ExecFrame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, f)
+	return nil
+}
+
+func (s *fakeExecStream) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
+
+func (s *fakeExecStream) output(frameType string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var b strings.Builder
+	for _, f := range s.sent {
+		if f.Type == frameType {
+			b.WriteString(f.Data)
+		}
+	}
+	return b.String()
+}
+
+func (s *fakeExecStream) last() *command.ExecFrame {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.sent) == 0 {
+		return nil
+	}
+	return s.sent[len(s.sent)-1]
+}
+
+func execCmd(params map[string]any) *command.Command {
+	return &command.Command{
+		ID:        "cmd-exec-1",
+		Action:    command.ActionExec,
+		Kind:      "Pod",
+		Namespace: "default",
+		Name:      "web-1",
+		Params:    params,
+	}
+}
+
+type exitError int
+
+func (e exitError) Error() string   { return "command terminated with non-zero exit code" }
+func (e exitError) ExitStatus() int { return int(e) }
+
+func TestExecute_Exec_EchoAndExitCode(t *testing.T) {
+	stream := newFakeExecStream(
+		&command.ExecFrame{Type: command.FrameResize, Cols: 120, Rows: 40},
+		&command.ExecFrame{Type: command.FrameStdin, Data: "ls\n"},
+	)
+	var openedSession string
+	var gotOpts model.ExecOptions
+
+	podRepo := &mock.PodRepository{
+		ExecFn: func(ctx context.Context, namespace, name string, opts model.ExecOptions) error {
+			gotOpts = opts
+			size := <-opts.Resize
+			if size.Width != 120 || size.Height != 40 {
+				t.Errorf("resize: got %dx%d, want 120x40", size.Width, size.Height)
+			}
+			buf := make([]byte, 3)
+			if _, err := io.ReadFull(opts.Stdin, buf); err != nil {
+				return err
+			}
+			opts.Stdout.Write([]byte("echo:" + string(buf)))
+			opts.Stderr.Write([]byte("warn"))
+			return exitError(2)
+		},
+	}
+	svc := &commandService{
+		podRepo: podRepo,
+		execStreams: &mock.MasterGateway{
+			OpenExecStreamFn: func(ctx context.Context, sessionID string) (gateway.ExecStream, error) {
+				openedSession = sessionID
+				return stream, nil
+			},
+		},
+	}
+
+	result := svc.Execute(context.Background(), execCmd(map[string]any{
+		"sessionId": "sess-1",
+		"container": "app",
+		"tty":       true,
+	}))
+
+	if !result.Success {
+		t.Fatalf("expected success, got error: %s", result.Error)
+	}
+	if openedSession != "sess-1" {
+		t.Errorf("session: got %q, want sess-1", openedSession)
+	}
+	if gotOpts.Container != "app" || !gotOpts.TTY || gotOpts.Command[0] != "/bin/sh" {
+		t.Errorf("unexpected exec options: %+v", gotOpts)
+	}
+	if out := stream.output(command.FrameStdout); out != "echo:ls\n" {
+		t.Errorf("stdout: got %q", out)
+	}
+	if out := stream.output(command.FrameStderr); out != "warn" {
+		t.Errorf("stderr: got %q", out)
+	}
+	last := stream.last()
+	if last == nil || last.Type != command.FrameExit || last.Code != 2 {
+		t.Errorf("expected exit frame with code 2, got %+v", last)
+	}
+}
+
+func TestExecute_Exec_ClientClose(t *testing.T) {
+	stream := newFakeExecStream(&command.ExecFrame{Type: command.FrameClose})
+	podRepo := &mock.PodRepository{
+		ExecFn: func(ctx context.Context, namespace, name string, opts model.ExecOptions) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	svc := &commandService{
+		podRepo: podRepo,
+		execStreams: &mock.MasterGateway{
+			OpenExecStreamFn: func(ctx context.Context, sessionID string) (gateway.ExecStream, error) {
+				return stream, nil
+			},
+		},
+	}
+
+	result := svc.Execute(context.Background(), execCmd(map[string]any{"sessionId": "sess-2"}))
+	if !result.Success {
+		t.Fatalf("expected success on client close, got error: %s", result.Error)
+	}
+	if last := stream.last(); last == nil || last.Type != command.FrameExit {
+		t.Errorf("expected exit frame, got %+v", last)
+	}
+}
+
+func TestExecute_Exec_Errors(t *testing.T) {
+	svc := &commandService{
+		podRepo: &mock.PodRepository{},
+		execStreams: &mock.MasterGateway{
+			OpenExecStreamFn: func(ctx context.Context, sessionID string) (gateway.ExecStream, error) {
+				return nil, errors.New("dial failed")
+			},
+		},
+	}
+
+	if result := svc.Execute(context.Background(), execCmd(nil)); result.Success {
+		t.Error("expected failure without sessionId")
+	}
+	if result := svc.Execute(context.Background(), execCmd(map[string]any{"sessionId": "s"})); result.Success {
+		t.Error("expected failure when stream cannot be opened")
+	}
+}
+
+func TestFrameWriter_SplitsOnRuneBoundary(t *testing.T) {
+	stream := newFakeExecStream()
+	w := &frameWriter{stream: stream, frameType: command.FrameStdout}
+
+	text := []byte("你好")
+	w.Write(text[:4]) // "你" + 半个 "好"
+	w.Write(text[4:])
+	w.Flush()
+
+	for _, f := range stream.sent {
+		if !strings.HasPrefix(f.Data, "你") && !strings.HasPrefix(f.Data, "好") {
+			t.Errorf("frame contains a split rune: %q", f.Data)
+		}
+	}
+	if out := stream.output(command.FrameStdout); out != "你好" {
+		t.Errorf("output: got %q, want 你好", out)
+	}
+}
@@ -0,0 +1,282 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_agent_v2/gateway"
+	"AtlHyper/atlhyper_agent_v2/model"
+	"AtlHyper/model_v3/command"
+)
+
+// 日志跟踪参数默认值与上限
+const (
+	defaultLogStreamTail int64 = 50
+	maxLogStreamTail     int64 = 500
+	maxLogStreamSources        = 20
+	maxLogLineBytes            = 1024 * 1024
+)
+
+// logReorderWindow 多来源合并时的重排窗口
+// 窗口内到达的日志按时间戳排序后再发送（测试中可调小）
+var logReorderWindow = 500 * time.Millisecond
+
+// logSource 一个日志来源（Pod 的一个容器）
+type logSource struct {
+	pod       string
+	container string
+}
+
+func (src logSource) String() string {
+	return src.pod + "/" + src.container
+}
+
+// logLine 带时间戳的一行日志
+type logLine struct {
+	time   time.Time
+	stamp  string
+	source string
+	text   string
+	err    string
+}
+
+// handleStreamLogs 处理日志跟踪指令
+//
+// 流程:
+//  1. 解析日志来源: Pod 的容器，或 Deployment 下所有 Pod 的容器
+//  2. 按 sessionId 主动连接 Master 的流通道 (与 exec 共用)
+//  3. 每个来源以 follow + timestamps 方式读取日志
+//  4. 按时间戳合并为 log 帧发送，所有来源结束后发送 exit 帧
+//
+// 客户端关闭 (close 帧或连接断开) 或超时后结束全部来源。
+func (s *commandService) handleStreamLogs(ctx context.Context, cmd *command.Command) (string, error) {
+	var params command.LogStreamParams
+	if err := s.parseParams(cmd.Params, &params); err != nil {
+		return "", fmt.Errorf("invalid log stream params: %w", err)
+	}
+	if params.SessionID == "" {
+		return "", fmt.Errorf("sessionId is required")
+	}
+	if cmd.Namespace == "" || cmd.Name == "" {
+		return "", fmt.Errorf("namespace and name are required")
+	}
+	if s.execStreams == nil {
+		return "", fmt.Errorf("log streaming is not available")
+	}
+	if params.TailLines <= 0 {
+		params.TailLines = defaultLogStreamTail
+	}
+	if params.TailLines > maxLogStreamTail {
+		params.TailLines = maxLogStreamTail
+	}
+
+	timeout := defaultExecTimeout
+	if params.TimeoutSeconds > 0 {
+		timeout = time.Duration(params.TimeoutSeconds) * time.Second
+	}
+	if timeout > maxExecTimeout {
+		timeout = maxExecTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	sources, err := s.resolveLogSources(ctx, cmd, params.Container)
+	if err != nil {
+		return "", err
+	}
+
+	stream, err := s.execStreams.OpenExecStream(ctx, params.SessionID)
+	if err != nil {
+		return "", fmt.Errorf("open log stream: %w", err)
+	}
+
+	// 客户端 → Agent: 只关心关闭
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			frame, err := stream.Recv()
+			if err != nil || frame.Type == command.FrameClose {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	lines := make(chan logLine, 256)
+	var readers sync.WaitGroup
+	for _, src := range sources {
+		readers.Add(1)
+		go func(src logSource) {
+			defer readers.Done()
+			s.followLogSource(ctx, cmd.Namespace, src, params, lines)
+		}(src)
+	}
+	go func() {
+		readers.Wait()
+		close(lines)
+	}()
+
+	sent := mergeLogLines(lines, stream, logReorderWindow)
+
+	exit := &command.ExecFrame{Type: command.FrameExit}
+	if ctx.Err() == context.DeadlineExceeded {
+		exit.Error = "session timed out"
+	}
+	_ = stream.Send(exit)
+
+	stream.Close()
+	wg.Wait()
+
+	return fmt.Sprintf("session %s closed, %d lines from %d sources", params.SessionID, sent, len(sources)), nil
+}
+
+// resolveLogSources 解析日志来源
+func (s *commandService) resolveLogSources(ctx context.Context, cmd *command.Command, container string) ([]logSource, error) {
+	var podNames []string
+	containers := map[string][]string{}
+
+	switch cmd.Kind {
+	case "Deployment":
+		selector, err := s.genericRepo.GetDeploymentSelector(ctx, cmd.Namespace, cmd.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get deployment: %w", err)
+		}
+		pods, err := s.podRepo.List(ctx, cmd.Namespace, model.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("list pods: %w", err)
+		}
+		for _, pod := range pods {
+			podNames = append(podNames, pod.Summary.Name)
+			for _, c := range pod.Containers {
+				containers[pod.Summary.Name] = append(containers[pod.Summary.Name], c.Name)
+			}
+		}
+	case "", "Pod":
+		podNames = []string{cmd.Name}
+		if container == "" {
+			pod, err := s.podRepo.Get(ctx, cmd.Namespace, cmd.Name)
+			if err != nil {
+				return nil, fmt.Errorf("get pod: %w", err)
+			}
+			for _, c := range pod.Containers {
+				containers[cmd.Name] = append(containers[cmd.Name], c.Name)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported kind for log streaming: %s", cmd.Kind)
+	}
+
+	var sources []logSource
+	for _, pod := range podNames {
+		if container != "" {
+			sources = append(sources, logSource{pod: pod, container: container})
+			continue
+		}
+		for _, c := range containers[pod] {
+			sources = append(sources, logSource{pod: pod, container: c})
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no containers found for %s/%s", cmd.Namespace, cmd.Name)
+	}
+	if len(sources) > maxLogStreamSources {
+		return nil, fmt.Errorf("too many log sources (%d), limit is %d; specify a container", len(sources), maxLogStreamSources)
+	}
+	return sources, nil
+}
+
+// followLogSource 持续读取单个来源的日志并写入 lines
+func (s *commandService) followLogSource(ctx context.Context, namespace string, src logSource, params command.LogStreamParams, lines chan<- logLine) {
+	rc, err := s.podRepo.StreamLogs(ctx, namespace, src.pod, model.LogOptions{
+		Container:    src.container,
+		TailLines:    params.TailLines,
+		SinceSeconds: params.SinceSeconds,
+		Timestamps:   true,
+		Follow:       true,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			lines <- logLine{time: time.Now(), source: src.String(), err: err.Error()}
+		}
+		return
+	}
+	stop := context.AfterFunc(ctx, func() { rc.Close() })
+	defer stop()
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 64*1024), maxLogLineBytes)
+	for scanner.Scan() {
+		line := parseLogLine(scanner.Text())
+		line.source = src.String()
+		select {
+		case lines <- line:
+		case <-ctx.Done():
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		lines <- logLine{time: time.Now(), source: src.String(), err: err.Error()}
+	}
+}
+
+// parseLogLine 拆分 kubelet 添加的 RFC3339Nano 时间戳前缀
+// 无法解析时使用当前时间，整行作为内容
+func parseLogLine(raw string) logLine {
+	if stamp, text, ok := strings.Cut(raw, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+			return logLine{time: t, stamp: stamp, text: text}
+		}
+	}
+	now := time.Now()
+	return logLine{time: now, stamp: now.UTC().Format(time.RFC3339Nano), text: raw}
+}
+
+// mergeLogLines 按重排窗口合并多来源日志并发送，返回发送的行数
+//
+// 每个窗口内收到的日志按时间戳稳定排序后发送；
+// lines 关闭后发送剩余日志并返回。发送失败 (客户端已断开) 时丢弃后续日志。
+func mergeLogLines(lines <-chan logLine, stream gateway.ExecStream, window time.Duration) int {
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+
+	var buf []logLine
+	sent := 0
+	broken := false
+	flush := func() {
+		sort.SliceStable(buf, func(i, j int) bool { return buf[i].time.Before(buf[j].time) })
+		for _, l := range buf {
+			if broken {
+				break
+			}
+			frame := &command.ExecFrame{Type: command.FrameLog, Source: l.source, Time: l.stamp, Data: l.text, Error: l.err}
+			if err := stream.Send(frame); err != nil {
+				broken = true
+				break
+			}
+			sent++
+		}
+		buf = buf[:0]
+	}
+
+	for {
+		select {
+		case l, ok := <-lines:
+			if !ok {
+				flush()
+				return sent
+			}
+			buf = append(buf, l)
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package command
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_agent_v2/gateway"
+	"AtlHyper/atlhyper_agent_v2/model"
+	"AtlHyper/atlhyper_agent_v2/testutil/mock"
+	"AtlHyper/model_v3/cluster"
+	"AtlHyper/model_v3/command"
+)
+
+func init() {
+	logReorderWindow = 20 * time.Millisecond
+}
+
+func logStreamCmd(kind, name string, params map[string]any) *command.Command {
+	return &command.Command{
+		ID:        "cmd-logs-1",
+		Action:    command.ActionStreamLogs,
+		Kind:      kind,
+		Namespace: "default",
+		Name:      name,
+		Params:    params,
+	}
+}
+
+func podWithContainers(name string, containers ...string) cluster.Pod {
+	pod := cluster.Pod{Summary: cluster.PodSummary{Name: name, Namespace: "default"}}
+	for _, c := range containers {
+		pod.Containers = append(pod.Containers, cluster.PodContainerDetail{Name: c})
+	}
+	return pod
+}
+
+func TestExecute_StreamLogs_MergesDeploymentPods(t *testing.T) {
+	// 来源读完即结束，整段日志在一个窗口内排序
+	defer func(w time.Duration) { logReorderWindow = w }(logReorderWindow)
+	logReorderWindow = time.Hour
+
+	stream := newFakeExecStream()
+	var selector string
+	var mu sync.Mutex
+	var gotOpts []model.LogOptions
+
+	logs := map[string]string{
+		"web-1/app": "2026-01-01T00:00:01Z first\n2026-01-01T00:00:03Z third\n",
+		"web-2/app": "2026-01-01T00:00:02Z second\n",
+	}
+	svc := &commandService{
+		genericRepo: &mock.GenericRepository{
+			GetDeploymentSelectorFn: func(ctx context.Context, namespace, name string) (string, error) {
+				return "app=web", nil
+			},
+		},
+		podRepo: &mock.PodRepository{
+			ListFn: func(ctx context.Context, namespace string, opts model.ListOptions) ([]cluster.Pod, error) {
+				selector = opts.LabelSelector
+				return []cluster.Pod{podWithContainers("web-1", "app"), podWithContainers("web-2", "app")}, nil
+			},
+			StreamLogsFn: func(ctx context.Context, namespace, name string, opts model.LogOptions) (io.ReadCloser, error) {
+				mu.Lock()
+				gotOpts = append(gotOpts, opts)
+				mu.Unlock()
+				return io.NopCloser(strings.NewReader(logs[name+"/"+opts.Container])), nil
+			},
+		},
+		execStreams: &mock.MasterGateway{
+			OpenExecStreamFn: func(ctx context.Context, sessionID string) (gateway.ExecStream, error) {
+				return stream, nil
+			},
+		},
+	}
+
+	result := svc.Execute(context.Background(), logStreamCmd("Deployment", "web", map[string]any{"sessionId": "sess-logs"}))
+	if !result.Success {
+		t.Fatalf("expected success, got error: %s", result.Error)
+	}
+	if selector != "app=web" {
+		t.Errorf("selector: got %q, want app=web", selector)
+	}
+	for _, opts := range gotOpts {
+		if !opts.Follow || !opts.Timestamps {
+			t.Errorf("expected follow+timestamps, got %+v", opts)
+		}
+	}
+
+	var texts, sources []string
+	for _, f := range stream.sent {
+		if f.Type == command.FrameLog {
+			texts = append(texts, f.Data)
+			sources = append(sources, f.Source)
+		}
+	}
+	if got := strings.Join(texts, ","); got != "first,second,third" {
+		t.Errorf("merged order: got %s", got)
+	}
+	if got := strings.Join(sources, ","); got != "web-1/app,web-2/app,web-1/app" {
+		t.Errorf("sources: got %s", got)
+	}
+	if last := stream.last(); last == nil || last.Type != command.FrameExit {
+		t.Errorf("expected exit frame, got %+v", last)
+	}
+}
+
+func TestExecute_StreamLogs_ClientCloseStopsSources(t *testing.T) {
+	stream := newFakeExecStream()
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	svc := &commandService{
+		podRepo: &mock.PodRepository{
+			GetFn: func(ctx context.Context, namespace, name string) (*cluster.Pod, error) {
+				pod := podWithContainers("web-1", "app")
+				return &pod, nil
+			},
+			StreamLogsFn: func(ctx context.Context, namespace, name string, opts model.LogOptions) (io.ReadCloser, error) {
+				return pr, nil
+			},
+		},
+		execStreams: &mock.MasterGateway{
+			OpenExecStreamFn: func(ctx context.Context, sessionID string) (gateway.ExecStream, error) {
+				return stream, nil
+			},
+		},
+	}
+
+	go func() {
+		pw.Write([]byte("2026-01-01T00:00:01Z hello\n"))
+		stream.in <- &command.ExecFrame{Type: command.FrameClose}
+	}()
+
+	done := make(chan *command.Result, 1)
+	go func() {
+		done <- svc.Execute(context.Background(), logStreamCmd("Pod", "web-1", map[string]any{"sessionId": "s"}))
+	}()
+
+	select {
+	case result := <-done:
+		if !result.Success {
+			t.Fatalf("expected success, got error: %s", result.Error)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("log stream did not stop after client close")
+	}
+}
+
+func TestExecute_StreamLogs_Errors(t *testing.T) {
+	svc := &commandService{
+		podRepo: &mock.PodRepository{},
+		execStreams: &mock.MasterGateway{
+			OpenExecStreamFn: func(ctx context.Context, sessionID string) (gateway.ExecStream, error) {
+				return newFakeExecStream(), nil
+			},
+		},
+	}
+
+	if result := svc.Execute(context.Background(), logStreamCmd("Pod", "web-1", nil)); result.Success {
+		t.Error("expected failure without sessionId")
+	}
+	if result := svc.Execute(context.Background(), logStreamCmd("Service", "web", map[string]any{"sessionId": "s"})); result.Success {
+		t.Error("expected failure for unsupported kind")
+	}
+}
+
+func TestParseLogLine(t *testing.T) {
+	line := parseLogLine("2026-01-01T00:00:01.123456789Z GET /healthz 200")
+	if line.text != "GET /healthz 200" || line.stamp != "2026-01-01T00:00:01.123456789Z" {
+		t.Errorf("unexpected parse: %+v", line)
+	}
+
+	raw := parseLogLine("no timestamp here")
+	if raw.text != "no timestamp here" || raw.stamp == "" {
+		t.Errorf("expected raw line with generated stamp, got %+v", raw)
+	}
+}
@@ -24,3 +24,23 @@ type SnapshotService interface {
 type CommandService interface {
 	Execute(ctx context.Context, cmd *command.Command) *command.Result
 }
+
+// ProgressFunc 长耗时指令的进度回调
+//
+// 由调用方 (Scheduler) 通过 WithProgress 注入 context，
+// 指令处理函数在进度变化时调用，用于向 Master 中间上报。
+type ProgressFunc func(progress []command.ProgressEntry)
+
+type progressCtxKey struct{}
+
+// WithProgress 在 context 中注入进度回调
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressCtxKey{}, fn)
+}
+
+// ReportProgress 上报进度（未注入回调时忽略）
+func ReportProgress(ctx context.Context, progress []command.ProgressEntry) {
+	if fn, ok := ctx.Value(progressCtxKey{}).(ProgressFunc); ok && fn != nil {
+		fn(progress)
+	}
+}
@@ -1,7 +1,7 @@
 // Package snapshot 集群快照采集服务
 //
 // 本包实现 service.SnapshotService 接口，负责:
-//   - 并发采集集群中的所有资源 (20 种类型)
+//   - 并发采集集群中的所有资源 (21 种类型)
 //   - 生成集群统计摘要 (ClusterSummary)
 //   - 组装完整的 ClusterSnapshot 对象
 //
@@ -36,7 +36,7 @@ var log = logger.Module("Snapshot")
 
 // snapshotService 快照采集服务实现
 //
-// 依赖 20 个 K8s Repository + 可选的 OTel 概览仓库。
+// 依赖 21 个 K8s Repository + 可选的 OTel 概览仓库。
 // 所有 Repository 在创建时注入，支持测试时 mock。
 type snapshotService struct {
 	clusterID string
@@ -55,6 +55,7 @@ type snapshotService struct {
 	eventRepo          repository.EventRepository
 	jobRepo            repository.JobRepository
 	cronJobRepo        repository.CronJobRepository
+	hpaRepo            repository.HPARepository
 	pvRepo             repository.PersistentVolumeRepository
 	pvcRepo            repository.PersistentVolumeClaimRepository
 	resourceQuotaRepo  repository.ResourceQuotaRepository
@@ -107,6 +108,7 @@ func NewSnapshotService(
 	eventRepo repository.EventRepository,
 	jobRepo repository.JobRepository,
 	cronJobRepo repository.CronJobRepository,
+	hpaRepo repository.HPARepository,
 	pvRepo repository.PersistentVolumeRepository,
 	pvcRepo repository.PersistentVolumeClaimRepository,
 	resourceQuotaRepo repository.ResourceQuotaRepository,
@@ -133,6 +135,7 @@ func NewSnapshotService(
 		eventRepo:          eventRepo,
 		jobRepo:            jobRepo,
 		cronJobRepo:        cronJobRepo,
+		hpaRepo:            hpaRepo,
 		pvRepo:             pvRepo,
 		pvcRepo:            pvcRepo,
 		resourceQuotaRepo:  resourceQuotaRepo,
@@ -147,11 +150,11 @@ func NewSnapshotService(
 
 // Collect 采集集群快照
 //
-// 并发采集 20 种 K8s 资源，组装为完整的 ClusterSnapshot。
+// 并发采集 21 种 K8s 资源，组装为完整的 ClusterSnapshot。
 // 采集完成后生成统计摘要，用于仪表盘快速展示。
 //
 // 并发策略:
-//   - 启动 20 个 goroutine 同时采集
+//   - 启动 21 个 goroutine 同时采集
 //   - 使用 WaitGroup 等待全部完成
 //   - 使用 Mutex 保护 snapshot 写入
 //
@@ -181,8 +184,8 @@ func (s *snapshotService) Collect(ctx context.Context) (*cluster.ClusterSnapshot
 
 	opts := model.ListOptions{}
 
-	// 20 个 K8s 资源并发采集
-	wg.Add(20)
+	// 21 个 K8s 资源并发采集
+	wg.Add(21)
 
 	// Pods
 	go func() {
@@ -352,6 +355,18 @@ func (s *snapshotService) Collect(ctx context.Context) (*cluster.ClusterSnapshot
 		}
 	}()
 
+	// HorizontalPodAutoscalers
+	go func() {
+		defer wg.Done()
+		hpas, err := s.hpaRepo.List(ctx, "", opts)
+		recordErr(err)
+		if err == nil {
+			mu.Lock()
+			snapshot.HPAs = hpas
+			mu.Unlock()
+		}
+	}()
+
 	// PersistentVolumes
 	go func() {
 		defer wg.Done()
@@ -33,6 +33,7 @@ func newTestService(opts ...func(*snapshotService)) *snapshotService {
 		eventRepo:          &mock.EventRepository{},
 		jobRepo:            &mock.JobRepository{},
 		cronJobRepo:        &mock.CronJobRepository{},
+		hpaRepo:            &mock.HPARepository{},
 		pvRepo:             &mock.PersistentVolumeRepository{},
 		pvcRepo:            &mock.PersistentVolumeClaimRepository{},
 		resourceQuotaRepo:  &mock.ResourceQuotaRepository{},
@@ -121,6 +122,11 @@ func TestCollect_AllResourcesPopulated(t *testing.T) {
 			return []cluster.CronJob{{CommonMeta: commonMeta("cj-1", "default")}}, nil
 		},
 	}
+	hpaRepo := &mock.HPARepository{
+		ListFn: func(_ context.Context, _ string, _ model.ListOptions) ([]cluster.HorizontalPodAutoscaler, error) {
+			return []cluster.HorizontalPodAutoscaler{{CommonMeta: commonMeta("hpa-1", "default")}}, nil
+		},
+	}
 	pvRepo := &mock.PersistentVolumeRepository{
 		ListFn: func(_ context.Context, _ model.ListOptions) ([]cluster.PersistentVolume, error) {
 			return []cluster.PersistentVolume{{CommonMeta: commonMeta("pv-1", "")}}, nil
@@ -167,6 +173,7 @@ func TestCollect_AllResourcesPopulated(t *testing.T) {
 		s.eventRepo = eventRepo
 		s.jobRepo = jobRepo
 		s.cronJobRepo = cronJobRepo
+		s.hpaRepo = hpaRepo
 		s.pvRepo = pvRepo
 		s.pvcRepo = pvcRepo
 		s.resourceQuotaRepo = rqRepo
@@ -187,7 +194,7 @@ func TestCollect_AllResourcesPopulated(t *testing.T) {
 		t.Errorf("FetchedAt is too old: %v", snapshot.FetchedAt)
 	}
 
-	// Assert all 20 resource slices are populated
+	// Assert all 21 resource slices are populated
 	assertions := []struct {
 		name  string
 		count int
@@ -206,6 +213,7 @@ func TestCollect_AllResourcesPopulated(t *testing.T) {
 		{"Events", len(snapshot.Events)},
 		{"Jobs", len(snapshot.Jobs)},
 		{"CronJobs", len(snapshot.CronJobs)},
+		{"HPAs", len(snapshot.HPAs)},
 		{"PersistentVolumes", len(snapshot.PersistentVolumes)},
 		{"PersistentVolumeClaims", len(snapshot.PersistentVolumeClaims)},
 		{"ResourceQuotas", len(snapshot.ResourceQuotas)},
@@ -2,8 +2,10 @@ package mock
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
+	"AtlHyper/atlhyper_agent_v2/gateway"
 	"AtlHyper/model_v3/cluster"
 	"AtlHyper/model_v3/command"
 )
@@ -12,15 +14,19 @@ import (
 type MasterGateway struct {
 	mu sync.Mutex
 
-	PushSnapshotFn func(ctx context.Context, snapshot *cluster.ClusterSnapshot) error
-	PollCommandsFn func(ctx context.Context, topic string) ([]command.Command, error)
-	ReportResultFn func(ctx context.Context, result *command.Result) error
-	HeartbeatFn    func(ctx context.Context) error
+	PushSnapshotFn   func(ctx context.Context, snapshot *cluster.ClusterSnapshot) error
+	PushDeltaFn      func(ctx context.Context, delta *cluster.SnapshotDelta) error
+	PollCommandsFn   func(ctx context.Context, topic string) ([]command.Command, error)
+	ReportResultFn   func(ctx context.Context, result *command.Result) error
+	HeartbeatFn      func(ctx context.Context) error
+	OpenExecStreamFn func(ctx context.Context, sessionID string) (gateway.ExecStream, error)
 
 	// Tracking fields for assertions
 	PushSnapshotCalls int
+	PushDeltaCalls    int
 	ReportResultCalls int
 	LastSnapshot      *cluster.ClusterSnapshot
+	LastDelta         *cluster.SnapshotDelta
 	LastResult        *command.Result
 }
 
@@ -35,6 +41,17 @@ func (m *MasterGateway) PushSnapshot(ctx context.Context, snapshot *cluster.Clus
 	return nil
 }
 
+func (m *MasterGateway) PushSnapshotDelta(ctx context.Context, delta *cluster.SnapshotDelta) error {
+	m.mu.Lock()
+	m.PushDeltaCalls++
+	m.LastDelta = delta
+	m.mu.Unlock()
+	if m.PushDeltaFn != nil {
+		return m.PushDeltaFn(ctx, delta)
+	}
+	return nil
+}
+
 func (m *MasterGateway) PollCommands(ctx context.Context, topic string) ([]command.Command, error) {
 	if m.PollCommandsFn != nil {
 		return m.PollCommandsFn(ctx, topic)
@@ -59,3 +76,10 @@ func (m *MasterGateway) Heartbeat(ctx context.Context) error {
 	}
 	return nil
 }
+
+func (m *MasterGateway) OpenExecStream(ctx context.Context, sessionID string) (gateway.ExecStream, error) {
+	if m.OpenExecStreamFn != nil {
+		return m.OpenExecStreamFn(ctx, sessionID)
+	}
+	return nil, fmt.Errorf("exec stream not supported")
+}
@@ -290,6 +290,21 @@ func (m *CronJobRepository) Get(ctx context.Context, namespace, name string) (*c
 	return nil, nil
 }
 
+// =============================================================================
+// HPARepository mock
+// =============================================================================
+
+type HPARepository struct {
+	ListFn func(ctx context.Context, namespace string, opts model.ListOptions) ([]cluster.HorizontalPodAutoscaler, error)
+}
+
+func (m *HPARepository) List(ctx context.Context, namespace string, opts model.ListOptions) ([]cluster.HorizontalPodAutoscaler, error) {
+	if m.ListFn != nil {
+		return m.ListFn(ctx, namespace, opts)
+	}
+	return nil, nil
+}
+
 // =============================================================================
 // PersistentVolumeRepository mock
 // =============================================================================
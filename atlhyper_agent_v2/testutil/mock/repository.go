@@ -2,6 +2,8 @@ package mock
 
 import (
 	"context"
+	"io"
+	"strings"
 
 	"AtlHyper/atlhyper_agent_v2/model"
 	"AtlHyper/model_v3/cluster"
@@ -9,9 +11,11 @@ import (
 
 // PodRepository mock
 type PodRepository struct {
-	ListFn    func(ctx context.Context, namespace string, opts model.ListOptions) ([]cluster.Pod, error)
-	GetFn     func(ctx context.Context, namespace, name string) (*cluster.Pod, error)
-	GetLogsFn func(ctx context.Context, namespace, name string, opts model.LogOptions) (string, error)
+	ListFn       func(ctx context.Context, namespace string, opts model.ListOptions) ([]cluster.Pod, error)
+	GetFn        func(ctx context.Context, namespace, name string) (*cluster.Pod, error)
+	GetLogsFn    func(ctx context.Context, namespace, name string, opts model.LogOptions) (string, error)
+	ExecFn       func(ctx context.Context, namespace, name string, opts model.ExecOptions) error
+	StreamLogsFn func(ctx context.Context, namespace, name string, opts model.LogOptions) (io.ReadCloser, error)
 }
 
 func (m *PodRepository) List(ctx context.Context, namespace string, opts model.ListOptions) ([]cluster.Pod, error) {
@@ -32,6 +36,19 @@ func (m *PodRepository) GetLogs(ctx context.Context, namespace, name string, opt
 	}
 	return "", nil
 }
+func (m *PodRepository) StreamLogs(ctx context.Context, namespace, name string, opts model.LogOptions) (io.ReadCloser, error) {
+	if m.StreamLogsFn != nil {
+		return m.StreamLogsFn(ctx, namespace, name, opts)
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (m *PodRepository) Exec(ctx context.Context, namespace, name string, opts model.ExecOptions) error {
+	if m.ExecFn != nil {
+		return m.ExecFn(ctx, namespace, name, opts)
+	}
+	return nil
+}
 
 // GenericRepository mock
 type GenericRepository struct {
@@ -42,9 +59,14 @@ type GenericRepository struct {
 	UpdateDeploymentImageFn func(ctx context.Context, namespace, name, container, image string) error
 	CordonNodeFn            func(ctx context.Context, name string) error
 	UncordonNodeFn          func(ctx context.Context, name string) error
+	ListNodePodsFn          func(ctx context.Context, nodeName string) ([]model.NodePod, error)
+	EvictPodFn              func(ctx context.Context, namespace, name string, opts model.EvictOptions) error
+	PodGoneFn               func(ctx context.Context, namespace, name, uid string) (bool, error)
 	GetConfigMapDataFn      func(ctx context.Context, namespace, name string) (map[string]string, error)
 	GetSecretDataFn         func(ctx context.Context, namespace, name string) (map[string]string, error)
+	GetDeploymentSelectorFn func(ctx context.Context, namespace, name string) (string, error)
 	ExecuteFn               func(ctx context.Context, req *model.DynamicRequest) (*model.DynamicResponse, error)
+	ListAPIResourcesFn      func(ctx context.Context) ([]model.APIResource, error)
 }
 
 func (m *GenericRepository) DeletePod(ctx context.Context, namespace, name string, opts model.DeleteOptions) error {
@@ -96,6 +118,34 @@ func (m *GenericRepository) UncordonNode(ctx context.Context, name string) error
 	return nil
 }
 
+func (m *GenericRepository) GetDeploymentSelector(ctx context.Context, namespace, name string) (string, error) {
+	if m.GetDeploymentSelectorFn != nil {
+		return m.GetDeploymentSelectorFn(ctx, namespace, name)
+	}
+	return "", nil
+}
+
+func (m *GenericRepository) ListNodePods(ctx context.Context, nodeName string) ([]model.NodePod, error) {
+	if m.ListNodePodsFn != nil {
+		return m.ListNodePodsFn(ctx, nodeName)
+	}
+	return nil, nil
+}
+
+func (m *GenericRepository) EvictPod(ctx context.Context, namespace, name string, opts model.EvictOptions) error {
+	if m.EvictPodFn != nil {
+		return m.EvictPodFn(ctx, namespace, name, opts)
+	}
+	return nil
+}
+
+func (m *GenericRepository) PodGone(ctx context.Context, namespace, name, uid string) (bool, error) {
+	if m.PodGoneFn != nil {
+		return m.PodGoneFn(ctx, namespace, name, uid)
+	}
+	return true, nil
+}
+
 func (m *GenericRepository) GetConfigMapData(ctx context.Context, namespace, name string) (map[string]string, error) {
 	if m.GetConfigMapDataFn != nil {
 		return m.GetConfigMapDataFn(ctx, namespace, name)
@@ -116,3 +166,10 @@ func (m *GenericRepository) Execute(ctx context.Context, req *model.DynamicReque
 	}
 	return nil, nil
 }
+
+func (m *GenericRepository) ListAPIResources(ctx context.Context) ([]model.APIResource, error) {
+	if m.ListAPIResourcesFn != nil {
+		return m.ListAPIResourcesFn(ctx)
+	}
+	return nil, nil
+}
@@ -0,0 +1,160 @@
+// atlhyper_master_v2/agentsdk/auth.go
+// Agent 身份认证
+//
+// 每个 AgentSDK 请求都经过 withAuth 包装:
+//   - ClusterID 取自 X-Cluster-ID Header（兼容 cluster_id 查询参数，两者不一致时拒绝）
+//   - Token 取自 Authorization: Bearer <token>，与 cluster_agent_tokens 中的摘要比对
+//   - 轮换后旧 Token 在宽限期内仍有效，便于 Agent 平滑切换
+//   - RequireToken=false 时，未签发 Token 的集群按旧行为放行（迁移期）
+package agentsdk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/common/crypto"
+)
+
+// lastUsedInterval last_used_at 写库的最小间隔（避免每次长轮询都写数据库）
+const lastUsedInterval = time.Minute
+
+type clusterCtxKey struct{}
+
+// authenticator Agent Token 校验器
+type authenticator struct {
+	repo         database.AgentTokenRepository
+	requireToken bool
+
+	mu       sync.Mutex
+	lastUsed map[string]time.Time
+}
+
+func newAuthenticator(repo database.AgentTokenRepository, requireToken bool) *authenticator {
+	return &authenticator{
+		repo:         repo,
+		requireToken: requireToken,
+		lastUsed:     make(map[string]time.Time),
+	}
+}
+
+// verify 校验集群凭证，返回 HTTP 状态码（0 表示通过）和错误信息
+func (a *authenticator) verify(ctx context.Context, clusterID, token string) (int, string) {
+	if a.repo == nil {
+		if a.requireToken {
+			return http.StatusServiceUnavailable, "agent token store unavailable"
+		}
+		return 0, ""
+	}
+
+	if clusterID == "" {
+		if a.requireToken {
+			return http.StatusUnauthorized, "X-Cluster-ID header is required"
+		}
+		return 0, ""
+	}
+
+	rec, err := a.repo.GetByCluster(ctx, clusterID)
+	if err != nil {
+		log.Error("查询 Agent 凭证失败", "cluster", clusterID, "err", err)
+		return http.StatusInternalServerError, "Internal error"
+	}
+	if rec == nil {
+		if a.requireToken {
+			log.Warn("拒绝未签发凭证的集群", "cluster", clusterID)
+			return http.StatusUnauthorized, "no agent token issued for cluster"
+		}
+		return 0, ""
+	}
+
+	if token == "" {
+		log.Warn("Agent 请求缺少 Token", "cluster", clusterID)
+		return http.StatusUnauthorized, "agent token is required"
+	}
+
+	if !tokenMatches(rec, token, time.Now()) {
+		log.Warn("Agent Token 校验失败", "cluster", clusterID)
+		return http.StatusUnauthorized, "invalid agent token"
+	}
+
+	a.touch(clusterID)
+	return 0, ""
+}
+
+// tokenMatches 当前 Token 或宽限期内的旧 Token 均视为有效
+func tokenMatches(rec *database.AgentToken, token string, now time.Time) bool {
+	if crypto.VerifyTokenHash(token, rec.TokenHash) {
+		return true
+	}
+	if rec.PreviousHash != "" && rec.PreviousExpiresAt != nil && now.Before(*rec.PreviousExpiresAt) {
+		return crypto.VerifyTokenHash(token, rec.PreviousHash)
+	}
+	return false
+}
+
+// touch 节流更新 last_used_at
+func (a *authenticator) touch(clusterID string) {
+	now := time.Now()
+	a.mu.Lock()
+	last, ok := a.lastUsed[clusterID]
+	if ok && now.Sub(last) < lastUsedInterval {
+		a.mu.Unlock()
+		return
+	}
+	a.lastUsed[clusterID] = now
+	a.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := a.repo.UpdateLastUsed(ctx, clusterID, now); err != nil {
+			log.Warn("更新 Agent 凭证使用时间失败", "cluster", clusterID, "err", err)
+		}
+	}()
+}
+
+// withAuth 认证中间件
+func (s *Server) withAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		headerID := r.Header.Get("X-Cluster-ID")
+		queryID := r.URL.Query().Get("cluster_id")
+		if headerID != "" && queryID != "" && headerID != queryID {
+			http.Error(w, "cluster_id mismatch", http.StatusBadRequest)
+			return
+		}
+		clusterID := headerID
+		if clusterID == "" {
+			clusterID = queryID
+		}
+
+		if status, msg := s.auth.verify(r.Context(), clusterID, bearerToken(r)); status != 0 {
+			http.Error(w, msg, status)
+			return
+		}
+
+		if clusterID != "" {
+			r = r.WithContext(context.WithValue(r.Context(), clusterCtxKey{}, clusterID))
+		}
+		next(w, r)
+	}
+}
+
+// requestClusterID 获取经过认证中间件解析的 ClusterID
+func requestClusterID(r *http.Request) string {
+	if id, ok := r.Context().Value(clusterCtxKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// bearerToken 从 Authorization Header 提取 Bearer Token
+func bearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	if !strings.HasPrefix(h, "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(h, "Bearer "))
+}
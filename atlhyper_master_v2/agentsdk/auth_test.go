@@ -0,0 +1,168 @@
+package agentsdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/common/crypto"
+)
+
+// fakeTokenRepo 内存版 AgentTokenRepository
+type fakeTokenRepo struct {
+	mu     sync.Mutex
+	tokens map[string]*database.AgentToken
+}
+
+func newFakeTokenRepo() *fakeTokenRepo {
+	return &fakeTokenRepo{tokens: make(map[string]*database.AgentToken)}
+}
+
+func (f *fakeTokenRepo) Upsert(_ context.Context, t *database.AgentToken) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens[t.ClusterID] = t
+	return nil
+}
+
+func (f *fakeTokenRepo) GetByCluster(_ context.Context, clusterID string) (*database.AgentToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tokens[clusterID], nil
+}
+
+func (f *fakeTokenRepo) List(_ context.Context) ([]*database.AgentToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []*database.AgentToken
+	for _, t := range f.tokens {
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func (f *fakeTokenRepo) Delete(_ context.Context, clusterID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.tokens, clusterID)
+	return nil
+}
+
+func (f *fakeTokenRepo) UpdateLastUsed(_ context.Context, _ string, _ time.Time) error {
+	return nil
+}
+
+func newAuthServer(repo database.AgentTokenRepository, requireToken bool) *Server {
+	return &Server{auth: newAuthenticator(repo, requireToken)}
+}
+
+// doAuth 通过 withAuth 发起请求，返回状态码和下游看到的 ClusterID
+func doAuth(s *Server, clusterID, token, query string) (int, string) {
+	var seen string
+	h := s.withAuth(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestClusterID(r)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/agent/commands"+query, nil)
+	if clusterID != "" {
+		req.Header.Set("X-Cluster-ID", clusterID)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	return rec.Code, seen
+}
+
+func TestWithAuth_ValidToken(t *testing.T) {
+	repo := newFakeTokenRepo()
+	repo.Upsert(context.Background(), &database.AgentToken{ClusterID: "c1", TokenHash: crypto.HashToken("ath_good")})
+	s := newAuthServer(repo, true)
+
+	code, seen := doAuth(s, "c1", "ath_good", "")
+	if code != http.StatusOK {
+		t.Fatalf("status: got %d, want 200", code)
+	}
+	if seen != "c1" {
+		t.Errorf("cluster in context: got %q, want %q", seen, "c1")
+	}
+}
+
+func TestWithAuth_InvalidToken(t *testing.T) {
+	repo := newFakeTokenRepo()
+	repo.Upsert(context.Background(), &database.AgentToken{ClusterID: "c1", TokenHash: crypto.HashToken("ath_good")})
+	s := newAuthServer(repo, false)
+
+	if code, _ := doAuth(s, "c1", "ath_bad", ""); code != http.StatusUnauthorized {
+		t.Errorf("invalid token: got %d, want 401", code)
+	}
+	// 已签发 Token 的集群即使在迁移模式下也必须携带 Token
+	if code, _ := doAuth(s, "c1", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("missing token: got %d, want 401", code)
+	}
+}
+
+func TestWithAuth_PreviousTokenGrace(t *testing.T) {
+	repo := newFakeTokenRepo()
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Minute)
+	repo.Upsert(context.Background(), &database.AgentToken{
+		ClusterID:         "c1",
+		TokenHash:         crypto.HashToken("ath_new"),
+		PreviousHash:      crypto.HashToken("ath_old"),
+		PreviousExpiresAt: &future,
+	})
+	repo.Upsert(context.Background(), &database.AgentToken{
+		ClusterID:         "c2",
+		TokenHash:         crypto.HashToken("ath_new"),
+		PreviousHash:      crypto.HashToken("ath_old"),
+		PreviousExpiresAt: &past,
+	})
+	s := newAuthServer(repo, true)
+
+	if code, _ := doAuth(s, "c1", "ath_old", ""); code != http.StatusOK {
+		t.Errorf("previous token within grace: got %d, want 200", code)
+	}
+	if code, _ := doAuth(s, "c2", "ath_old", ""); code != http.StatusUnauthorized {
+		t.Errorf("previous token after grace: got %d, want 401", code)
+	}
+	if code, _ := doAuth(s, "c2", "ath_new", ""); code != http.StatusOK {
+		t.Errorf("current token: got %d, want 200", code)
+	}
+}
+
+func TestWithAuth_LegacyMode(t *testing.T) {
+	s := newAuthServer(newFakeTokenRepo(), false)
+
+	if code, _ := doAuth(s, "legacy", "", ""); code != http.StatusOK {
+		t.Errorf("legacy cluster without token: got %d, want 200", code)
+	}
+}
+
+func TestWithAuth_RequireToken(t *testing.T) {
+	s := newAuthServer(newFakeTokenRepo(), true)
+
+	if code, _ := doAuth(s, "unknown", "ath_any", ""); code != http.StatusUnauthorized {
+		t.Errorf("cluster without issued token: got %d, want 401", code)
+	}
+	if code, _ := doAuth(s, "", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("missing cluster id: got %d, want 401", code)
+	}
+}
+
+func TestWithAuth_ClusterIDMismatch(t *testing.T) {
+	repo := newFakeTokenRepo()
+	repo.Upsert(context.Background(), &database.AgentToken{ClusterID: "c1", TokenHash: crypto.HashToken("ath_good")})
+	s := newAuthServer(repo, true)
+
+	// 用 c1 的 Token 冒充 c2 拉取指令
+	if code, _ := doAuth(s, "c1", "ath_good", "?cluster_id=c2"); code != http.StatusBadRequest {
+		t.Errorf("cluster id mismatch: got %d, want 400", code)
+	}
+}
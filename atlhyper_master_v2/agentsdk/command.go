@@ -17,7 +17,7 @@ func (s *Server) handleCommands(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	clusterID := r.URL.Query().Get("cluster_id")
+	clusterID := requestClusterID(r)
 	if clusterID == "" {
 		http.Error(w, "cluster_id is required", http.StatusBadRequest)
 		return
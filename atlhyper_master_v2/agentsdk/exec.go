@@ -0,0 +1,55 @@
+// atlhyper_master_v2/agentsdk/exec.go
+// Agent 接入交互式 exec 会话
+package agentsdk
+
+import (
+	"errors"
+	"net/http"
+
+	"AtlHyper/atlhyper_master_v2/stream"
+
+	"github.com/gorilla/websocket"
+)
+
+// execUpgrader Agent 侧 WebSocket 升级器（非浏览器客户端，无需校验 Origin）
+var execUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// handleExecStream Agent 回拨接入 exec 会话
+// GET /agent/exec/stream?session=ID (WebSocket)
+// Header: X-Cluster-ID, Authorization
+//
+// 连接保持到会话结束，由 Master 侧的 ExecService 负责转发。
+func (s *Server) handleExecStream(w http.ResponseWriter, r *http.Request) {
+	if s.execHub == nil {
+		http.Error(w, "exec streaming not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	clusterID := requestClusterID(r)
+	sessionID := r.URL.Query().Get("session")
+	if clusterID == "" || sessionID == "" {
+		http.Error(w, "X-Cluster-ID header and session are required", http.StatusBadRequest)
+		return
+	}
+
+	conn, err := execUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Warn("exec 流升级失败", "cluster", clusterID, "err", err)
+		return
+	}
+	agentConn := stream.NewWSConn(conn)
+	defer agentConn.Close()
+
+	if err := s.execHub.Attach(r.Context(), sessionID, clusterID, agentConn); err != nil {
+		if errors.Is(err, stream.ErrClusterMismatch) {
+			log.Warn("拒绝跨集群接入 exec 会话", "cluster", clusterID, "session", sessionID)
+		} else {
+			log.Warn("exec 会话接入失败", "cluster", clusterID, "session", sessionID, "err", err)
+		}
+		return
+	}
+}
@@ -19,8 +19,8 @@ func (s *Server) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 从 Header 获取 ClusterID（与其他 API 保持一致）
-	clusterID := r.Header.Get("X-Cluster-ID")
+	// 从 Header 获取 ClusterID（经认证中间件解析，与其他 API 保持一致）
+	clusterID := requestClusterID(r)
 	if clusterID == "" {
 		http.Error(w, "X-Cluster-ID header is required", http.StatusBadRequest)
 		return
@@ -33,12 +33,28 @@ func (s *Server) handleResult(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 已认证的集群只能上报本集群的指令结果
+	if clusterID := requestClusterID(r); clusterID != "" && !s.commandBelongsTo(req.CommandID, clusterID) {
+		log.Warn("拒绝跨集群上报结果", "cmd", req.CommandID, "cluster", clusterID)
+		http.Error(w, "command does not belong to cluster", http.StatusForbidden)
+		return
+	}
+
+	// 中间进度：只更新指令历史，不结束指令
+	if req.Partial {
+		s.persistProgress(req.CommandID, req.Progress)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(ResultResponse{Status: "ok"})
+		return
+	}
+
 	// 转换为 Model 格式
 	result := &command.Result{
 		CommandID: req.CommandID,
 		Success:   req.Success,
 		Output:    req.Output,
 		Error:     req.Error,
+		Progress:  req.Progress,
 	}
 
 	// 确认指令完成
@@ -57,6 +73,61 @@ func (s *Server) handleResult(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(ResultResponse{Status: "ok"})
 }
 
+// commandBelongsTo 校验指令归属集群
+// 未配置 cmdRepo 时不做限制；查询失败或无指令历史记录时拒绝
+func (s *Server) commandBelongsTo(cmdID, clusterID string) bool {
+	if s.cmdRepo == nil {
+		return true
+	}
+	history, err := s.cmdRepo.GetByCommandID(context.Background(), cmdID)
+	if err != nil {
+		log.Error("查询指令历史失败，拒绝上报", "cmd", cmdID, "cluster", clusterID, "err", err)
+		return false
+	}
+	if history == nil {
+		log.Warn("指令历史不存在，拒绝上报", "cmd", cmdID, "cluster", clusterID)
+		return false
+	}
+	return history.ClusterID == clusterID
+}
+
+// persistProgress 持久化长耗时指令的中间进度
+// 指令保持 running 状态，Result 字段记录最新进度供前端轮询展示
+func (s *Server) persistProgress(cmdID string, progress []command.ProgressEntry) {
+	if s.cmdRepo == nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	history, err := s.cmdRepo.GetByCommandID(ctx, cmdID)
+	if err != nil || history == nil {
+		log.Warn("获取指令历史失败或不存在", "cmd", cmdID, "err", err)
+		return
+	}
+	// 已结束的指令不再接受进度（乱序到达的中间上报）
+	if history.FinishedAt != nil {
+		return
+	}
+
+	now := time.Now()
+	history.Status = command.StatusRunning
+	if history.StartedAt == nil {
+		history.StartedAt = &now
+	}
+
+	resultJSON, _ := json.Marshal(&command.Result{
+		CommandID: cmdID,
+		Partial:   true,
+		Progress:  progress,
+	})
+	history.Result = string(resultJSON)
+
+	if err := s.cmdRepo.Update(ctx, history); err != nil {
+		log.Error("更新指令进度失败", "cmd", cmdID, "err", err)
+	}
+}
+
 // persistResult 持久化指令执行结果
 func (s *Server) persistResult(cmdID string, result *command.Result) {
 	if s.cmdRepo == nil {
@@ -0,0 +1,49 @@
+package agentsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// fakeCmdRepo 内存版 CommandHistoryRepository（仅实现 GetByCommandID）
+type fakeCmdRepo struct {
+	database.CommandHistoryRepository
+	history map[string]*database.CommandHistory
+	err     error
+}
+
+func (f *fakeCmdRepo) GetByCommandID(_ context.Context, cmdID string) (*database.CommandHistory, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.history[cmdID], nil
+}
+
+func TestCommandBelongsTo(t *testing.T) {
+	repo := &fakeCmdRepo{history: map[string]*database.CommandHistory{
+		"cmd-1": {CommandID: "cmd-1", ClusterID: "c1"},
+	}}
+	s := &Server{cmdRepo: repo}
+
+	if !s.commandBelongsTo("cmd-1", "c1") {
+		t.Error("own command rejected")
+	}
+	if s.commandBelongsTo("cmd-1", "c2") {
+		t.Error("command of another cluster accepted")
+	}
+	if s.commandBelongsTo("cmd-unknown", "c1") {
+		t.Error("unknown command accepted")
+	}
+
+	repo.err = errors.New("db down")
+	if s.commandBelongsTo("cmd-1", "c1") {
+		t.Error("command accepted when history lookup fails")
+	}
+
+	if !(&Server{}).commandBelongsTo("cmd-unknown", "c1") {
+		t.Error("without cmdRepo all commands should be accepted")
+	}
+}
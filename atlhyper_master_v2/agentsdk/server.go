@@ -13,6 +13,8 @@ import (
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/mq"
 	"AtlHyper/atlhyper_master_v2/processor"
+	"AtlHyper/atlhyper_master_v2/stream"
+	"AtlHyper/common/crypto"
 	"AtlHyper/common/logger"
 )
 
@@ -25,6 +27,9 @@ type Server struct {
 	bus        mq.Consumer
 	processor  processor.Processor
 	cmdRepo    database.CommandHistoryRepository
+	auth       *authenticator
+	tls        TLSConfig
+	execHub    *stream.Hub
 	httpServer *http.Server
 }
 
@@ -35,6 +40,22 @@ type Config struct {
 	Bus            mq.Consumer
 	Processor      processor.Processor
 	CmdRepo        database.CommandHistoryRepository
+	TokenRepo      database.AgentTokenRepository // Agent 凭证（nil 表示不校验）
+	RequireToken   bool                          // 是否要求所有集群携带 Token
+	TLS            TLSConfig                     // 可选，证书为空时使用 HTTP
+	ExecHub        *stream.Hub                   // 可选，nil 表示不支持交互式 exec
+}
+
+// TLSConfig AgentSDK TLS 配置
+type TLSConfig struct {
+	CertFile     string // 服务端证书
+	KeyFile      string // 服务端私钥
+	ClientCAFile string // 客户端 CA（非空则强制 mTLS）
+}
+
+// enabled 是否启用 TLS
+func (c TLSConfig) enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
 }
 
 // NewServer 创建 Server
@@ -45,6 +66,9 @@ func NewServer(cfg Config) *Server {
 		bus:       cfg.Bus,
 		processor: cfg.Processor,
 		cmdRepo:   cfg.CmdRepo,
+		auth:      newAuthenticator(cfg.TokenRepo, cfg.RequireToken),
+		tls:       cfg.TLS,
+		execHub:   cfg.ExecHub,
 	}
 }
 
@@ -52,11 +76,13 @@ func NewServer(cfg Config) *Server {
 func (s *Server) Start() error {
 	mux := http.NewServeMux()
 
-	// 注册路由
-	mux.HandleFunc("/agent/snapshot", s.handleSnapshot)
-	mux.HandleFunc("/agent/heartbeat", s.handleHeartbeat)
-	mux.HandleFunc("/agent/commands", s.handleCommands)
-	mux.HandleFunc("/agent/result", s.handleResult)
+	// 注册路由（全部经过 Agent 凭证校验）
+	mux.HandleFunc("/agent/snapshot", s.withAuth(s.handleSnapshot))
+	mux.HandleFunc("/agent/snapshot/delta", s.withAuth(s.handleSnapshotDelta))
+	mux.HandleFunc("/agent/heartbeat", s.withAuth(s.handleHeartbeat))
+	mux.HandleFunc("/agent/commands", s.withAuth(s.handleCommands))
+	mux.HandleFunc("/agent/result", s.withAuth(s.handleResult))
+	mux.HandleFunc("/agent/exec/stream", s.withAuth(s.handleExecStream))
 
 	// 健康检查
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -71,10 +97,25 @@ func (s *Server) Start() error {
 		WriteTimeout: s.timeout + 10*time.Second, // 长轮询需要更长的写超时
 	}
 
-	log.Info("启动服务器", "port", s.port)
+	if s.tls.enabled() {
+		tlsCfg, err := crypto.NewServerTLSConfig(s.tls.CertFile, s.tls.KeyFile, s.tls.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to load agentsdk tls config: %w", err)
+		}
+		s.httpServer.TLSConfig = tlsCfg
+	}
+
+	log.Info("启动服务器", "port", s.port, "tls", s.tls.enabled(), "mtls", s.tls.enabled() && s.tls.ClientCAFile != "")
 
 	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if s.tls.enabled() {
+			// 证书已加载到 TLSConfig，此处无需再传文件路径
+			err = s.httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = s.httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Error("服务器错误", "err", err)
 		}
 	}()
@@ -1,10 +1,11 @@
 // atlhyper_master_v2/agentsdk/snapshot.go
 // 处理 Agent 快照上报
-// 直接解析 cluster.ClusterSnapshot 格式
+// 直接解析 cluster.ClusterSnapshot 格式，增量上报解析 cluster.SnapshotDelta
 package agentsdk
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"AtlHyper/common"
@@ -38,14 +39,8 @@ func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 获取 cluster_id（优先使用 Header，其次使用 body 中的值）
-	clusterID := r.Header.Get("X-Cluster-ID")
-	if clusterID == "" {
-		clusterID = snapshot.ClusterID
-	}
-
-	if clusterID == "" {
-		http.Error(w, "cluster_id is required", http.StatusBadRequest)
+	clusterID, ok := s.snapshotClusterID(w, r, snapshot.ClusterID)
+	if !ok {
 		return
 	}
 
@@ -62,3 +57,69 @@ func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 }
+
+// handleSnapshotDelta 处理增量快照上报
+// POST /agent/snapshot/delta
+//
+// 基准版本与 Master 当前快照不一致（Master 重启、上次推送丢失）时返回 409，
+// Agent 收到后改为全量推送。
+func (s *Server) handleSnapshotDelta(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	reader, err := common.MaybeGunzipReaderAuto(r.Body, r.Header.Get("Content-Encoding"))
+	if err != nil {
+		log.Error("解压请求体失败", "err", err)
+		http.Error(w, "Invalid gzip", http.StatusBadRequest)
+		return
+	}
+	defer reader.Close()
+
+	var delta cluster.SnapshotDelta
+	if err := json.NewDecoder(reader).Decode(&delta); err != nil {
+		log.Error("解析增量快照失败", "err", err)
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	clusterID, ok := s.snapshotClusterID(w, r, delta.ClusterID)
+	if !ok {
+		return
+	}
+	delta.ClusterID = clusterID
+
+	if err := s.processor.ProcessSnapshotDelta(clusterID, &delta); err != nil {
+		if errors.Is(err, cluster.ErrDeltaBaseMismatch) {
+			log.Info("增量基准版本不一致，要求全量同步", "cluster", clusterID, "base", delta.BaseVersion)
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		log.Error("处理器错误", "err", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// snapshotClusterID 获取 cluster_id（优先使用已认证的 Header，其次使用 body 中的值）
+// 失败时已写入错误响应
+func (s *Server) snapshotClusterID(w http.ResponseWriter, r *http.Request, bodyID string) (string, bool) {
+	clusterID := requestClusterID(r)
+	if clusterID == "" {
+		if s.auth.requireToken {
+			http.Error(w, "X-Cluster-ID header is required", http.StatusUnauthorized)
+			return "", false
+		}
+		clusterID = bodyID
+	}
+
+	if clusterID == "" {
+		http.Error(w, "cluster_id is required", http.StatusBadRequest)
+		return "", false
+	}
+	return clusterID, true
+}
@@ -34,6 +34,10 @@ type ResultRequest struct {
 	Output     string `json:"output,omitempty"`
 	Error      string `json:"error,omitempty"`
 	ExecTimeMs int64  `json:"execTime"`
+
+	// 长耗时指令的中间进度（Partial=true 时指令仍在执行）
+	Partial  bool                    `json:"partial,omitempty"`
+	Progress []command.ProgressEntry `json:"progress,omitempty"`
 }
 
 // ResultResponse 执行结果响应
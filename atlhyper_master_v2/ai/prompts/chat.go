@@ -29,6 +29,10 @@ const chatRole = `[角色定义]
 - get_logs: Pod 容器日志。需要 namespace + name。多容器时指定 container
 - get_events: K8s 事件。可按 namespace、involved_kind、involved_name 过滤
 - get_configmap: ConfigMap 内容
+- api_resources: 列出集群中安装的 CRD 资源类型（Kind / API 组 / 版本）
+
+自定义资源（Traefik IngressRoute、cert-manager Certificate 等）与内置资源用法相同，kind 填 Kind 名即可。
+不确定集群有哪些 CRD 时先调用 api_resources；同名 Kind 存在于多个 API 组时用 group 指定。
 
 数据量限制：list 最多 200 条，get_logs 最多 200 行。优先用 label_selector 缩小范围。
 
@@ -18,12 +18,20 @@ const toolsJSON = `[
       "properties": {
         "action": {
           "type": "string",
-          "description": "操作类型: get, list, describe, get_logs, get_events, get_configmap",
-          "enum": ["get", "list", "describe", "get_logs", "get_events", "get_configmap"]
+          "description": "操作类型: get, list, describe, get_logs, get_events, get_configmap, api_resources（列出集群中的 CRD 资源类型）",
+          "enum": ["get", "list", "describe", "get_logs", "get_events", "get_configmap", "api_resources"]
         },
         "kind": {
           "type": "string",
-          "description": "Kubernetes 资源类型: Pod, Deployment, Service, Node, HPA, StatefulSet, DaemonSet, Job, CronJob, PVC, PV, Ingress, ConfigMap, NetworkPolicy, ReplicaSet, Endpoints, Namespace, ServiceAccount, Event 等"
+          "description": "Kubernetes 资源类型: Pod, Deployment, Service, Node, HPA, StatefulSet, DaemonSet, Job, CronJob, PVC, PV, Ingress, ConfigMap, NetworkPolicy, ReplicaSet, Endpoints, Namespace, ServiceAccount, Event 等；也支持任意 CRD 的 Kind、复数名或简称（如 IngressRoute, Certificate, ServiceProfile）。api_resources 时不需要填写"
+        },
+        "group": {
+          "type": "string",
+          "description": "API 组（可选）。同名 Kind 存在于多个 API 组时需要指定，如 cert-manager.io；api_resources 时用于按组过滤"
+        },
+        "version": {
+          "type": "string",
+          "description": "API 版本（可选，默认使用集群首选版本）"
         },
         "namespace": {
           "type": "string",
@@ -54,7 +62,7 @@ const toolsJSON = `[
           "description": "关联资源名称（get_events 时过滤用）"
         }
       },
-      "required": ["action", "kind"]
+      "required": ["action"]
     }
   },
   {
@@ -93,7 +93,7 @@ func (e *toolExecutor) Execute(ctx context.Context, clusterID string, tc *llm.To
 	}
 
 	// 4. 创建指令
-	resp, err := e.ops.CreateCommand(req)
+	resp, err := e.ops.CreateCommand(ctx, req)
 	if err != nil {
 		return "", fmt.Errorf("创建指令失败: %w", err)
 	}
@@ -132,10 +132,17 @@ func mapToInternalAction(action string, params map[string]interface{}) (string,
 		return "get_configmap", cmdParams
 
 	default:
-		// get / list / describe / get_events 等统一走 dynamic
+		// get / list / describe / get_events / api_resources 等统一走 dynamic
+		// 非内置 Kind (CRD) 由 Agent 通过 API 发现解析，group/version 可选
 		cmdParams["command"] = action
 		cmdParams["kind"] = getString(params, "kind")
 		// 传递可选过滤参数
+		if v := getString(params, "group"); v != "" {
+			cmdParams["group"] = v
+		}
+		if v := getString(params, "version"); v != "" {
+			cmdParams["version"] = v
+		}
 		if v := getString(params, "label_selector"); v != "" {
 			cmdParams["label_selector"] = v
 		}
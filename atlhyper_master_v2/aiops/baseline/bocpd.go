@@ -0,0 +1,138 @@
+// atlhyper_master_v2/aiops/baseline/bocpd.go
+// 贝叶斯在线变点检测（Adams & MacKay 2007）
+//
+// 维护运行长度（距上次变点的样本数）后验分布，观测模型为 Normal-Gamma 共轭先验
+// （预测分布为 Student-t）。新样本难以由当前水平解释时，后验质量集中到短运行长度，
+// 即判定为变点。先验均值 / 方差取自全局 EMA，新水平从当前基线开始学习。
+package baseline
+
+import (
+	"fmt"
+	"math"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+)
+
+const (
+	bocpdHazard = 1.0 / 250 // 先验变点概率（期望运行长度 250 个样本）
+	bocpdMaxRun = 200       // 运行长度截断
+	bocpdRecent = 5         // 运行长度小于该值视为"刚发生变点"
+)
+
+// bocpdState BOCPD 状态：各运行长度的概率与 Normal-Gamma 后验参数
+type bocpdState struct {
+	Prob  []float64 `json:"p"`
+	Mu    []float64 `json:"mu"`
+	Kappa []float64 `json:"k"`
+	Alpha []float64 `json:"a"`
+	Beta  []float64 `json:"b"`
+}
+
+type bocpdDetector struct{}
+
+func (bocpdDetector) Name() string  { return "bocpd" }
+func (bocpdDetector) NewState() any { return &bocpdState{} }
+
+func (d bocpdDetector) Detect(state *aiops.BaselineState, value float64, now int64, threshold float64) *aiops.AnomalyResult {
+	s := detectorState[bocpdState](state, d)
+	priorMu, priorVar := state.EMA, state.Variance
+	if state.Count == 0 {
+		priorMu = value
+	}
+	changeProb, mapRun := s.update(value, priorMu, priorVar)
+
+	_, result := DetectWithThreshold(state, value, now, threshold)
+	if result == nil {
+		return nil
+	}
+
+	// 变点概率阈值随 σ 阈值升高: 3σ → 0.75，6σ → 0.86
+	required := threshold / (threshold + 1)
+	// 偏离度换算到 σ 刻度（概率达到要求时恰为 threshold）
+	deviation := changeProb / required * threshold
+	result.Deviation = deviation
+	result.Score = sigmoid(deviation, threshold, aiops.SigmoidK)
+	result.IsAnomaly = mapRun < bocpdRecent && changeProb > required
+	result.Detector = d.Name()
+	result.Reason = fmt.Sprintf("change point probability %.2f (run length %d, required %.2f)", changeProb, mapRun, required)
+	return result
+}
+
+// update 吸收新样本，返回短运行长度的后验概率与最可能的运行长度
+func (s *bocpdState) update(x, priorMu, priorVar float64) (changeProb float64, mapRun int) {
+	// 先验: κ0 = 1, α0 = 1, β0 = 方差估计（下限按量级给出，避免方差为 0 时预测分布退化）
+	floor := math.Max(1e-6, math.Pow(0.01*math.Abs(priorMu), 2))
+	beta0 := math.Max(priorVar, floor)
+	if len(s.Prob) == 0 {
+		s.reset(priorMu, beta0)
+	}
+
+	n := len(s.Prob)
+	next := make([]float64, n+1)
+	var total float64
+	for r := 0; r < n; r++ {
+		pred := studentT(x, 2*s.Alpha[r], s.Mu[r], s.Beta[r]*(s.Kappa[r]+1)/(s.Alpha[r]*s.Kappa[r]))
+		joint := s.Prob[r] * pred
+		next[r+1] = joint * (1 - bocpdHazard)
+		next[0] += joint * bocpdHazard
+	}
+	for _, p := range next {
+		total += p
+	}
+	if total <= 0 || math.IsNaN(total) {
+		// 所有运行长度都无法解释该样本（数值下溢），视为确定变点
+		s.reset(priorMu, beta0)
+		return 1, 0
+	}
+
+	// 后验参数: r+1 由 r 吸收 x 得到，r = 0 为先验
+	s.absorb(x)
+	s.Prob = next
+	s.Mu = append([]float64{priorMu}, s.Mu...)
+	s.Kappa = append([]float64{1}, s.Kappa...)
+	s.Alpha = append([]float64{1}, s.Alpha...)
+	s.Beta = append([]float64{beta0}, s.Beta...)
+
+	// 截断最长运行长度并归一化
+	if len(s.Prob) > bocpdMaxRun {
+		total -= s.Prob[bocpdMaxRun]
+		s.Prob, s.Mu, s.Kappa = s.Prob[:bocpdMaxRun], s.Mu[:bocpdMaxRun], s.Kappa[:bocpdMaxRun]
+		s.Alpha, s.Beta = s.Alpha[:bocpdMaxRun], s.Beta[:bocpdMaxRun]
+	}
+	best := 0.0
+	for r := range s.Prob {
+		s.Prob[r] /= total
+		if r < bocpdRecent {
+			changeProb += s.Prob[r]
+		}
+		if s.Prob[r] > best {
+			best, mapRun = s.Prob[r], r
+		}
+	}
+	return changeProb, mapRun
+}
+
+// reset 仅保留运行长度 0（先验）
+func (s *bocpdState) reset(mu, beta float64) {
+	s.Prob = []float64{1}
+	s.Mu, s.Kappa, s.Alpha, s.Beta = []float64{mu}, []float64{1}, []float64{1}, []float64{beta}
+}
+
+// absorb 以样本 x 更新全部运行长度的 Normal-Gamma 后验
+func (s *bocpdState) absorb(x float64) {
+	for r := range s.Mu {
+		k := s.Kappa[r]
+		s.Beta[r] += k * (x - s.Mu[r]) * (x - s.Mu[r]) / (2 * (k + 1))
+		s.Mu[r] = (k*s.Mu[r] + x) / (k + 1)
+		s.Kappa[r] = k + 1
+		s.Alpha[r] += 0.5
+	}
+}
+
+// studentT Student-t 概率密度（自由度 nu，位置 mu，尺度平方 sigma2）
+func studentT(x, nu, mu, sigma2 float64) float64 {
+	z := (x - mu) * (x - mu) / (nu * sigma2)
+	lg1, _ := math.Lgamma((nu + 1) / 2)
+	lg2, _ := math.Lgamma(nu / 2)
+	return math.Exp(lg1 - lg2 - 0.5*math.Log(nu*math.Pi*sigma2) - (nu+1)/2*math.Log1p(z))
+}
@@ -0,0 +1,104 @@
+// atlhyper_master_v2/aiops/baseline/cusum.go
+// 双边 CUSUM 缓慢漂移检测
+//
+// 参考均值 / 方差使用慢速 EMA，且告警期间不更新，
+// 因此持续的缓慢上涨（内存泄漏）会不断累积而不会被基线吸收。
+package baseline
+
+import (
+	"fmt"
+	"math"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+)
+
+const (
+	cusumAlpha = 0.005 // 参考基线 EMA 系数（窗口约 400 个样本）
+	cusumSlack = 0.5   // k：允许的偏移（σ），小于该值的偏移不累积
+	// cusumMargin 决策区间 h = threshold + cusumMargin（默认 5σ）
+	cusumMargin = 2.0
+	// cusumRelearn 连续告警超过该样本数后接受新水平，重置参考基线
+	cusumRelearn = 720
+)
+
+// cusumState CUSUM 检测器状态
+type cusumState struct {
+	Mean     float64 `json:"m"`
+	Variance float64 `json:"v"`
+	Count    int64   `json:"n"`
+	High     float64 `json:"hi"` // S+ 向上累积和（σ）
+	Low      float64 `json:"lo"` // S- 向下累积和（σ）
+	Alarm    int64   `json:"a"`  // 连续告警样本数
+}
+
+type cusumDetector struct{}
+
+func (cusumDetector) Name() string  { return "cusum" }
+func (cusumDetector) NewState() any { return &cusumState{} }
+
+func (d cusumDetector) Detect(state *aiops.BaselineState, value float64, now int64, threshold float64) *aiops.AnomalyResult {
+	s := detectorState[cusumState](state, d)
+	_, result := DetectWithThreshold(state, value, now, threshold)
+
+	// 冷启动期间只学习参考基线
+	if result == nil {
+		s.learn(value)
+		s.High, s.Low, s.Alarm = 0, 0, 0
+		return nil
+	}
+
+	sigma := math.Sqrt(s.Variance)
+	if floor := 1e-3 * math.Abs(s.Mean); sigma < floor {
+		sigma = floor
+	}
+	if sigma > 1e-9 {
+		z := (value - s.Mean) / sigma
+		s.High = math.Max(0, s.High+z-cusumSlack)
+		s.Low = math.Max(0, s.Low-z-cusumSlack)
+	}
+
+	h := threshold + cusumMargin
+	stat := math.Max(s.High, s.Low)
+	alarm := stat > h
+	if alarm {
+		s.Alarm++
+	} else {
+		s.Alarm = 0
+	}
+
+	// 未告警时学习参考基线（慢速 EMA 跟不上持续漂移）；长时间告警后接受新水平
+	switch {
+	case s.Alarm > cusumRelearn:
+		s.Mean, s.High, s.Low, s.Alarm = value, 0, 0, 0
+	case !alarm:
+		s.learn(value)
+	}
+
+	direction := "upward"
+	if s.Low > s.High {
+		direction = "downward"
+	}
+	// 偏离度换算到 σ 倍数刻度，使 sigmoid / 风险评分与其他检测器一致
+	deviation := stat * threshold / h
+	result.Baseline = s.Mean
+	result.Deviation = deviation
+	result.Score = sigmoid(deviation, threshold, aiops.SigmoidK)
+	result.IsAnomaly = alarm
+	result.Detector = d.Name()
+	result.Reason = fmt.Sprintf("CUSUM %s drift %.1fσ from reference %.4g (decision interval %.1fσ)", direction, stat, s.Mean, h)
+	return result
+}
+
+// learn 更新参考均值与方差
+// 样本较少时按累积平均（α = 1/n）快速收敛，之后退化为慢速 EMA
+func (s *cusumState) learn(value float64) {
+	s.Count++
+	if s.Count == 1 {
+		s.Mean, s.Variance = value, 0
+		return
+	}
+	alpha := math.Max(cusumAlpha, 1/float64(s.Count))
+	diff := value - s.Mean
+	s.Mean += alpha * diff
+	s.Variance = (1 - alpha) * (s.Variance + alpha*diff*diff)
+}
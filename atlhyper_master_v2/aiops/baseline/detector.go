@@ -11,6 +11,12 @@ import (
 // Detect 对单个指标执行异常检测
 // 返回更新后的状态和异常结果（冷启动期间 result 为 nil）
 func Detect(state *aiops.BaselineState, value float64, now int64) (*aiops.BaselineState, *aiops.AnomalyResult) {
+	return DetectWithThreshold(state, value, now, aiops.AnomalyThreshold)
+}
+
+// DetectWithThreshold 使用指定阈值（σ 倍数）执行异常检测
+// 误报反馈抬高阈值后，sigmoid 中心同步右移，风险分数随之降低
+func DetectWithThreshold(state *aiops.BaselineState, value float64, now int64, threshold float64) (*aiops.BaselineState, *aiops.AnomalyResult) {
 	state.Count++
 	alpha := aiops.DefaultAlpha
 
@@ -58,7 +64,7 @@ func Detect(state *aiops.BaselineState, value float64, now int64) (*aiops.Baseli
 	}
 
 	// 归一化到 [0, 1]
-	score := sigmoid(deviation, aiops.AnomalyThreshold, aiops.SigmoidK)
+	score := sigmoid(deviation, threshold, aiops.SigmoidK)
 
 	result := &aiops.AnomalyResult{
 		EntityKey:    state.EntityKey,
@@ -67,7 +73,7 @@ func Detect(state *aiops.BaselineState, value float64, now int64) (*aiops.Baseli
 		Baseline:     state.EMA,
 		Deviation:    deviation,
 		Score:        score,
-		IsAnomaly:    deviation > aiops.AnomalyThreshold,
+		IsAnomaly:    deviation > threshold,
 		DetectedAt:   now,
 	}
 
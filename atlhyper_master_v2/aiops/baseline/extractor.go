@@ -117,7 +117,12 @@ func extractServiceMetrics(otel *cluster.OTelSnapshot) []aiops.MetricDataPoint {
 // ExtractDeterministicAnomalies 从快照中提取确定性异常（绕过 EMA 冷启动）
 // 扫描容器状态和关联 Event，对 CrashLoopBackOff/OOMKilled 等确定性异常直接生成 AnomalyResult
 func ExtractDeterministicAnomalies(snap *cluster.ClusterSnapshot) []*aiops.AnomalyResult {
-	now := time.Now().Unix()
+	return ExtractDeterministicAnomaliesAt(snap, time.Now())
+}
+
+// ExtractDeterministicAnomaliesAt 以指定时间为当前时间提取确定性异常（离线回放用）
+func ExtractDeterministicAnomaliesAt(snap *cluster.ClusterSnapshot, at time.Time) []*aiops.AnomalyResult {
+	now := at.Unix()
 	var results []*aiops.AnomalyResult
 
 	// 路径 B1: 容器状态异常
@@ -132,6 +137,9 @@ func ExtractDeterministicAnomalies(snap *cluster.ClusterSnapshot) []*aiops.Anoma
 	// 路径 B4: Node 压力确定性异常（来自 K8s Node Conditions，不依赖 OTel）
 	results = append(results, extractNodePressure(snap, now)...)
 
+	// 路径 B5: HPA 饱和（副本数已达 maxReplicas，扩容余量耗尽）
+	results = append(results, extractHPASaturation(snap, now)...)
+
 	return results
 }
 
@@ -181,7 +189,7 @@ func extractContainerAnomalies(snap *cluster.ClusterSnapshot, now int64) []*aiop
 		var worstReason string
 		var worstScore float64
 		for j := range pod.Containers {
-			reason := classifyContainerAnomaly(&pod.Containers[j])
+			reason := classifyContainerAnomalyAt(&pod.Containers[j], time.Unix(now, 0))
 			if reason == "" {
 				continue
 			}
@@ -202,6 +210,7 @@ func extractContainerAnomalies(snap *cluster.ClusterSnapshot, now int64) []*aiop
 				Score:        worstScore,
 				IsAnomaly:    true,
 				DetectedAt:   now,
+				Reason:       worstReason,
 			})
 		}
 	}
@@ -211,6 +220,11 @@ func extractContainerAnomalies(snap *cluster.ClusterSnapshot, now int64) []*aiop
 // classifyContainerAnomaly 判断容器异常原因
 // 返回空字符串表示无异常
 func classifyContainerAnomaly(c *cluster.PodContainerDetail) string {
+	return classifyContainerAnomalyAt(c, time.Now())
+}
+
+// classifyContainerAnomalyAt 以指定时间判断近期崩溃
+func classifyContainerAnomalyAt(c *cluster.PodContainerDetail, now time.Time) string {
 	// waiting 状态异常（最明确的信号）
 	if c.State == "waiting" {
 		switch c.StateReason {
@@ -229,7 +243,7 @@ func classifyContainerAnomaly(c *cluster.PodContainerDetail) string {
 	// running + 近期崩溃：容器刚重启回来，快照恰好抓到 running 瞬间
 	// 检查 LastTerminationTime 在 10 分钟内，避免对历史重启持续告警
 	if c.State == "running" && c.RestartCount > 0 && c.LastTerminationReason != "" {
-		if isRecentTermination(c.LastTerminationTime) {
+		if isRecentTermination(c.LastTerminationTime, now) {
 			if c.LastTerminationReason == "OOMKilled" {
 				return "OOMKilled"
 			}
@@ -246,7 +260,7 @@ func classifyContainerAnomaly(c *cluster.PodContainerDetail) string {
 }
 
 // isRecentTermination 判断上次终止时间是否在 10 分钟内
-func isRecentTermination(lastTermTime string) bool {
+func isRecentTermination(lastTermTime string, now time.Time) bool {
 	if lastTermTime == "" {
 		return false
 	}
@@ -254,7 +268,7 @@ func isRecentTermination(lastTermTime string) bool {
 	if err != nil {
 		return false
 	}
-	return time.Since(t) < 10*time.Minute
+	return now.Sub(t) < 10*time.Minute
 }
 
 // deterministicScore 异常原因 → 固定分数
@@ -436,6 +450,75 @@ func deploymentImpactScore(ratio float64) float64 {
 	}
 }
 
+// extractHPASaturation HPA 饱和异常
+// 当 HPA 当前副本数已达 maxReplicas 时，为扩缩容目标下的所有 Pod 注入信号，
+// 使饱和服务在延迟恶化之前就体现为风险。
+// HPA 仍想扩容但被上限拦截 (ScalingLimited/TooManyReplicas) 时分数更高。
+func extractHPASaturation(snap *cluster.ClusterSnapshot, now int64) []*aiops.AnomalyResult {
+	// Step 1: 索引饱和的 HPA，key 为 "namespace/Kind/name"
+	saturated := make(map[string]float64)
+	for i := range snap.HPAs {
+		hpa := &snap.HPAs[i]
+		if !hpa.IsAtMaxReplicas() {
+			continue
+		}
+		score := 0.60
+		if hpa.IsScalingLimited() {
+			score = 0.70
+		}
+		saturated[hpa.Namespace+"/"+hpa.ScaleTargetKind+"/"+hpa.ScaleTargetName] = score
+	}
+	if len(saturated) == 0 {
+		return nil
+	}
+
+	// Step 2: ReplicaSet → Deployment 映射
+	rsOwner := make(map[string]string, len(snap.ReplicaSets))
+	for i := range snap.ReplicaSets {
+		rs := &snap.ReplicaSets[i]
+		if rs.OwnerKind == "Deployment" {
+			rsOwner[rs.Namespace+"/"+rs.Name] = rs.OwnerName
+		}
+	}
+
+	// Step 3: Pod → 工作负载 → HPA
+	var results []*aiops.AnomalyResult
+	for i := range snap.Pods {
+		pod := &snap.Pods[i]
+		ns := pod.Summary.Namespace
+
+		var targetKey string
+		switch pod.Summary.OwnerKind {
+		case "ReplicaSet":
+			dep, ok := rsOwner[ns+"/"+pod.Summary.OwnerName]
+			if !ok {
+				continue
+			}
+			targetKey = ns + "/Deployment/" + dep
+		case "StatefulSet":
+			targetKey = ns + "/StatefulSet/" + pod.Summary.OwnerName
+		default:
+			continue
+		}
+
+		score, ok := saturated[targetKey]
+		if !ok {
+			continue
+		}
+		results = append(results, &aiops.AnomalyResult{
+			EntityKey:    aiops.EntityKey(ns, "pod", pod.Summary.Name),
+			MetricName:   "hpa_saturated",
+			CurrentValue: 1,
+			Baseline:     0,
+			Deviation:    score * 10,
+			Score:        score,
+			IsAnomaly:    true,
+			DetectedAt:   now,
+		})
+	}
+	return results
+}
+
 func extractIngressMetrics(otel *cluster.OTelSnapshot) []aiops.MetricDataPoint {
 	var points []aiops.MetricDataPoint
 	for _, ing := range otel.SLOIngress {
@@ -141,11 +141,16 @@ func extractEnhancedNodeMetrics(otel *cluster.OTelSnapshot) []aiops.MetricDataPo
 // ExtractOTelDeterministicAnomalies 从 OTelSnapshot 提取确定性异常
 // 独立于 Basic 层的 ExtractDeterministicAnomalies，由 engine.go 分别调用
 func ExtractOTelDeterministicAnomalies(otel *cluster.OTelSnapshot) []*aiops.AnomalyResult {
+	return ExtractOTelDeterministicAnomaliesAt(otel, time.Now())
+}
+
+// ExtractOTelDeterministicAnomaliesAt 以指定时间为检测时间提取 OTel 确定性异常（离线回放用）
+func ExtractOTelDeterministicAnomaliesAt(otel *cluster.OTelSnapshot, at time.Time) []*aiops.AnomalyResult {
 	if otel == nil {
 		return nil
 	}
 
-	now := time.Now().Unix()
+	now := at.Unix()
 	var results []*aiops.AnomalyResult
 
 	// APM 确定性异常
@@ -873,3 +873,100 @@ func TestExtractIngressMetrics_ErrorRateRange(t *testing.T) {
 	apiMetrics := indexPoints(points, "_cluster/ingress/api@docker")
 	assertMetricApprox(t, apiMetrics, "error_rate", 0.0)
 }
+
+// ==================== Phase 5: HPA 饱和 ====================
+
+// makeHPA 创建 HPA
+func makeHPA(namespace, targetKind, targetName string, current, max int32, limited bool) cluster.HorizontalPodAutoscaler {
+	hpa := cluster.HorizontalPodAutoscaler{
+		CommonMeta:      model_v3.CommonMeta{Name: targetName, Namespace: namespace},
+		ScaleTargetKind: targetKind,
+		ScaleTargetName: targetName,
+		MinReplicas:     1,
+		MaxReplicas:     max,
+		CurrentReplicas: current,
+		DesiredReplicas: current,
+	}
+	if limited {
+		hpa.Conditions = []cluster.WorkloadCondition{
+			{Type: "ScalingLimited", Status: "True", Reason: "TooManyReplicas"},
+		}
+	}
+	return hpa
+}
+
+func TestExtractHPASaturation_DeploymentAtMax(t *testing.T) {
+	snap := &cluster.ClusterSnapshot{
+		HPAs: []cluster.HorizontalPodAutoscaler{
+			makeHPA("default", "Deployment", "web", 3, 3, false),
+		},
+		ReplicaSets: []cluster.ReplicaSet{
+			makeReplicaSet("default", "web-rs-abc", "web"),
+		},
+		Pods: []cluster.Pod{
+			makePodWithOwner("default", "web-1", "Running", 0, "ReplicaSet", "web-rs-abc",
+				makeContainer("app", "running", "", true, 0, ""),
+			),
+			makePodWithOwner("default", "web-2", "Running", 0, "ReplicaSet", "web-rs-abc",
+				makeContainer("app", "running", "", true, 0, ""),
+			),
+			makePodWithOwner("default", "other-1", "Running", 0, "ReplicaSet", "other-rs",
+				makeContainer("app", "running", "", true, 0, ""),
+			),
+		},
+	}
+
+	results := ExtractDeterministicAnomalies(snap)
+	saturated := findAllResults(results, "hpa_saturated")
+	if len(saturated) != 2 {
+		t.Fatalf("饱和 Deployment 的 2 个 Pod 应收到信号, got %d", len(saturated))
+	}
+	for _, r := range saturated {
+		if r.Score != 0.60 {
+			t.Errorf("饱和 score 应为 0.60, got %.2f (entity=%s)", r.Score, r.EntityKey)
+		}
+	}
+	if findResult(results, "default/pod/other-1", "hpa_saturated") != nil {
+		t.Error("无 HPA 的 Pod 不应收到 hpa_saturated 信号")
+	}
+}
+
+func TestExtractHPASaturation_ScalingLimitedStatefulSet(t *testing.T) {
+	snap := &cluster.ClusterSnapshot{
+		HPAs: []cluster.HorizontalPodAutoscaler{
+			makeHPA("default", "StatefulSet", "db", 5, 5, true),
+		},
+		Pods: []cluster.Pod{
+			makePodWithOwner("default", "db-0", "Running", 0, "StatefulSet", "db",
+				makeContainer("db", "running", "", true, 0, ""),
+			),
+		},
+	}
+
+	results := ExtractDeterministicAnomalies(snap)
+	r := findResult(results, "default/pod/db-0", "hpa_saturated")
+	if r == nil {
+		t.Fatal("StatefulSet Pod 应收到 hpa_saturated 信号")
+	}
+	if r.Score != 0.70 {
+		t.Errorf("ScalingLimited score 应为 0.70, got %.2f", r.Score)
+	}
+}
+
+func TestExtractHPASaturation_BelowMax_NoInjection(t *testing.T) {
+	snap := &cluster.ClusterSnapshot{
+		HPAs: []cluster.HorizontalPodAutoscaler{
+			makeHPA("default", "StatefulSet", "db", 2, 5, false),
+		},
+		Pods: []cluster.Pod{
+			makePodWithOwner("default", "db-0", "Running", 0, "StatefulSet", "db",
+				makeContainer("db", "running", "", true, 0, ""),
+			),
+		},
+	}
+
+	results := ExtractDeterministicAnomalies(snap)
+	if len(findAllResults(results, "hpa_saturated")) != 0 {
+		t.Error("未达 maxReplicas 不应注入 hpa_saturated 信号")
+	}
+}
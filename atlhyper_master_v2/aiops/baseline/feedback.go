@@ -0,0 +1,102 @@
+// atlhyper_master_v2/aiops/baseline/feedback.go
+// 误报反馈: 按实体模式 + 指标抬高异常判定阈值
+package baseline
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// wildcardMetric 实体模式下全部指标（误报事件无具体异常指标时使用）
+const wildcardMetric = "*"
+
+// SetFeedbackRepo 设置误报反馈持久化仓库（nil = 仅内存生效）
+func (m *StateManager) SetFeedbackRepo(repo database.AIOpsFeedbackRepository) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.feedbackRepo = repo
+}
+
+// LoadFeedbackFromDB 启动时恢复误报反馈阈值
+func (m *StateManager) LoadFeedbackFromDB(ctx context.Context) error {
+	if m.feedbackRepo == nil {
+		return nil
+	}
+	items, err := m.feedbackRepo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, fb := range items {
+		m.thresholds[fb.EntityPattern+"|"+fb.MetricName] = fb
+	}
+	return nil
+}
+
+// RaiseThreshold 记录一次误报，抬高实体模式下指定指标的阈值
+// metrics 为空时作用于该模式的全部指标；返回更新后的阈值记录
+func (m *StateManager) RaiseThreshold(ctx context.Context, entityKey string, metrics []string) ([]*database.AIOpsThresholdFeedback, error) {
+	if len(metrics) == 0 {
+		metrics = []string{wildcardMetric}
+	}
+	pattern := aiops.EntityPattern(entityKey)
+	now := time.Now().Unix()
+
+	m.mu.Lock()
+	updated := make([]*database.AIOpsThresholdFeedback, 0, len(metrics))
+	for _, metric := range metrics {
+		key := pattern + "|" + metric
+		fb, ok := m.thresholds[key]
+		if !ok {
+			fb = &database.AIOpsThresholdFeedback{
+				EntityPattern: pattern,
+				MetricName:    metric,
+				Threshold:     m.thresholdLocked(entityKey, metric),
+			}
+			m.thresholds[key] = fb
+		}
+		fb.Threshold = math.Min(fb.Threshold+aiops.FeedbackThresholdStep, aiops.FeedbackThresholdMax)
+		fb.FalsePositives++
+		fb.UpdatedAt = now
+		cp := *fb
+		updated = append(updated, &cp)
+	}
+	repo := m.feedbackRepo
+	m.mu.Unlock()
+
+	if repo != nil {
+		for _, fb := range updated {
+			if err := repo.Upsert(ctx, fb); err != nil {
+				return updated, err
+			}
+		}
+	}
+	return updated, nil
+}
+
+// Threshold 返回实体指标当前生效的异常判定阈值
+func (m *StateManager) Threshold(entityKey, metricName string) float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.thresholdLocked(entityKey, metricName)
+}
+
+// thresholdLocked 按 "模式|指标" → "模式|*" → 默认阈值 顺序查找（调用方已持有锁）
+func (m *StateManager) thresholdLocked(entityKey, metricName string) float64 {
+	if len(m.thresholds) == 0 {
+		return aiops.AnomalyThreshold
+	}
+	pattern := aiops.EntityPattern(entityKey)
+	if fb, ok := m.thresholds[pattern+"|"+metricName]; ok {
+		return fb.Threshold
+	}
+	if fb, ok := m.thresholds[pattern+"|"+wildcardMetric]; ok {
+		return fb.Threshold
+	}
+	return aiops.AnomalyThreshold
+}
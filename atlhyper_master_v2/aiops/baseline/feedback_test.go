@@ -0,0 +1,134 @@
+// atlhyper_master_v2/aiops/baseline/feedback_test.go
+package baseline
+
+import (
+	"context"
+	"testing"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// mockFeedbackRepo 模拟误报反馈仓库
+type mockFeedbackRepo struct {
+	rows map[string]*database.AIOpsThresholdFeedback
+}
+
+func (m *mockFeedbackRepo) Upsert(ctx context.Context, fb *database.AIOpsThresholdFeedback) error {
+	cp := *fb
+	m.rows[fb.EntityPattern+"|"+fb.MetricName] = &cp
+	return nil
+}
+
+func (m *mockFeedbackRepo) ListAll(ctx context.Context) ([]*database.AIOpsThresholdFeedback, error) {
+	var out []*database.AIOpsThresholdFeedback
+	for _, fb := range m.rows {
+		cp := *fb
+		out = append(out, &cp)
+	}
+	return out, nil
+}
+
+func TestRaiseThreshold_SharedAcrossReplicas(t *testing.T) {
+	m := NewStateManager(nil)
+	ctx := context.Background()
+
+	updated, err := m.RaiseThreshold(ctx, "default/pod/api-7d4b9c8f6-x2k9z", []string{"cpu_usage"})
+	if err != nil {
+		t.Fatalf("RaiseThreshold: %v", err)
+	}
+	if len(updated) != 1 || updated[0].EntityPattern != "default/pod/api-*" {
+		t.Fatalf("unexpected feedback: %+v", updated)
+	}
+
+	want := aiops.AnomalyThreshold + aiops.FeedbackThresholdStep
+	// 同一 Deployment 的其他副本共享阈值
+	if got := m.Threshold("default/pod/api-7d4b9c8f6-qwzrt", "cpu_usage"); got != want {
+		t.Errorf("sibling replica threshold = %.1f, want %.1f", got, want)
+	}
+	// 其他指标不受影响
+	if got := m.Threshold("default/pod/api-7d4b9c8f6-qwzrt", "memory_usage"); got != aiops.AnomalyThreshold {
+		t.Errorf("other metric threshold = %.1f, want default", got)
+	}
+	// 其他工作负载不受影响
+	if got := m.Threshold("default/pod/web-5c8d7f9b4-x2k9z", "cpu_usage"); got != aiops.AnomalyThreshold {
+		t.Errorf("other workload threshold = %.1f, want default", got)
+	}
+}
+
+func TestRaiseThreshold_WildcardAndCap(t *testing.T) {
+	m := NewStateManager(nil)
+	ctx := context.Background()
+	key := "_cluster/node/worker-1"
+
+	for i := 0; i < 20; i++ {
+		if _, err := m.RaiseThreshold(ctx, key, nil); err != nil {
+			t.Fatalf("RaiseThreshold: %v", err)
+		}
+	}
+	if got := m.Threshold(key, "cpu_usage"); got != aiops.FeedbackThresholdMax {
+		t.Errorf("wildcard threshold = %.1f, want cap %.1f", got, aiops.FeedbackThresholdMax)
+	}
+
+	// 指标级阈值从通配阈值起步继续抬高（已达上限则保持）
+	updated, _ := m.RaiseThreshold(ctx, key, []string{"cpu_usage"})
+	if updated[0].Threshold != aiops.FeedbackThresholdMax || updated[0].FalsePositives != 1 {
+		t.Errorf("metric feedback = %+v", updated[0])
+	}
+}
+
+func TestRaiseThreshold_SuppressesAnomaly(t *testing.T) {
+	m := NewStateManager(nil)
+	key := "default/pod/api-7d4b9c8f6-x2k9z"
+	seed := func() {
+		m.states[key+"|cpu_usage"] = &aiops.BaselineState{
+			EntityKey:  key,
+			MetricName: "cpu_usage",
+			EMA:        50.0,
+			Variance:   4.0, // σ = 2
+			Count:      int64(aiops.ColdStartMinCount),
+		}
+	}
+	point := []aiops.MetricDataPoint{{EntityKey: key, MetricName: "cpu_usage", Value: 58.0}}
+
+	// 偏离约 3.9σ: 默认 3σ 判定为异常
+	seed()
+	results := m.Update(point)
+	if len(results) != 1 || !results[0].IsAnomaly {
+		t.Fatalf("expected anomaly with default threshold, got %+v", results)
+	}
+
+	// 两次误报反馈后阈值 4σ，不再判定为异常
+	m.RaiseThreshold(context.Background(), key, []string{"cpu_usage"})
+	m.RaiseThreshold(context.Background(), key, []string{"cpu_usage"})
+	seed()
+	results = m.Update(point)
+	if len(results) != 1 || results[0].IsAnomaly {
+		t.Fatalf("expected no anomaly after feedback, got %+v", results[0])
+	}
+}
+
+func TestFeedback_PersistAndLoad(t *testing.T) {
+	repo := &mockFeedbackRepo{rows: make(map[string]*database.AIOpsThresholdFeedback)}
+	ctx := context.Background()
+
+	m := NewStateManager(nil)
+	m.SetFeedbackRepo(repo)
+	if _, err := m.RaiseThreshold(ctx, "default/service/api", []string{"error_rate"}); err != nil {
+		t.Fatalf("RaiseThreshold: %v", err)
+	}
+	if fb := repo.rows["default/service/api|error_rate"]; fb == nil || fb.FalsePositives != 1 {
+		t.Fatalf("feedback not persisted: %+v", repo.rows)
+	}
+
+	// 重启后恢复
+	restored := NewStateManager(nil)
+	restored.SetFeedbackRepo(repo)
+	if err := restored.LoadFeedbackFromDB(ctx); err != nil {
+		t.Fatalf("LoadFeedbackFromDB: %v", err)
+	}
+	want := aiops.AnomalyThreshold + aiops.FeedbackThresholdStep
+	if got := restored.Threshold("default/service/api", "error_rate"); got != want {
+		t.Errorf("restored threshold = %.1f, want %.1f", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+// atlhyper_master_v2/aiops/baseline/mad.go
+// 中位数 / MAD 稳健 z 分数检测
+//
+// z = 0.6745 × |x − median| / MAD，窗口内的离群尖峰不会像 EMA 方差那样抬高基线，
+// 适合计数类尖峰指标。
+package baseline
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+)
+
+const (
+	madWindow = 60 // 滑动窗口样本数
+
+	// madZeroDeviation 窗口内数值完全相同（MAD = 0）时，偏离值的固定偏离度
+	madZeroDeviation = 10.0
+)
+
+// madState MAD 检测器状态（最近 madWindow 个样本，环形缓冲）
+type madState struct {
+	Window []float64 `json:"w"`
+	Next   int       `json:"n"`
+}
+
+type madDetector struct{}
+
+func (madDetector) Name() string  { return "mad" }
+func (madDetector) NewState() any { return &madState{} }
+
+func (d madDetector) Detect(state *aiops.BaselineState, value float64, now int64, threshold float64) *aiops.AnomalyResult {
+	s := detectorState[madState](state, d)
+	median, mad := medianMAD(s.Window)
+	full := len(s.Window) >= madWindow/2
+	s.push(value)
+
+	_, result := DetectWithThreshold(state, value, now, threshold)
+	if result == nil || !full {
+		return result
+	}
+
+	var deviation float64
+	switch {
+	case mad > 1e-9:
+		deviation = 0.6745 * math.Abs(value-median) / mad
+	case math.Abs(value-median) > 1e-9:
+		deviation = madZeroDeviation
+	}
+	result.Baseline = median
+	result.Deviation = deviation
+	result.Score = sigmoid(deviation, threshold, aiops.SigmoidK)
+	result.IsAnomaly = deviation > threshold
+	result.Detector = d.Name()
+	result.Reason = fmt.Sprintf("robust z=%.1f from median %.4g (MAD %.4g, threshold %.1f)", deviation, median, mad, threshold)
+	return result
+}
+
+// push 写入样本（窗口满后覆盖最旧样本）
+func (s *madState) push(v float64) {
+	if len(s.Window) < madWindow {
+		s.Window = append(s.Window, v)
+		return
+	}
+	s.Window[s.Next%madWindow] = v
+	s.Next = (s.Next + 1) % madWindow
+}
+
+// medianMAD 计算中位数与绝对中位差
+func medianMAD(values []float64) (median, mad float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	median = medianSorted(sorted)
+	for i, v := range sorted {
+		sorted[i] = math.Abs(v - median)
+	}
+	sort.Float64s(sorted)
+	return median, medianSorted(sorted)
+}
+
+func medianSorted(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
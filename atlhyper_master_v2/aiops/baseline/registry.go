@@ -0,0 +1,143 @@
+// atlhyper_master_v2/aiops/baseline/registry.go
+// 可插拔异常检测器: 接口与注册表
+//
+// 内置检测器:
+//   - ema:   EMA + 3σ（默认，支持季节性基线）
+//   - mad:   中位数 / MAD 稳健 z 分数，适合尖峰型指标（如 log_error_count）
+//   - cusum: 双边 CUSUM，适合缓慢漂移（如 memory_usage 泄漏）
+//   - bocpd: 贝叶斯在线变点检测，适合阶跃式水平变化
+//
+// 所有检测器共用全局 EMA 的冷启动（含零值快速通道），冷启动结束后由各自算法给出判定。
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+)
+
+const (
+	// DefaultDetector 未配置时使用的检测器
+	DefaultDetector = "ema"
+	// DeterministicDetector 确定性异常直注（路径 B，不经过 Detector）
+	DeterministicDetector = "deterministic"
+)
+
+// Detector 异常检测算法
+type Detector interface {
+	// Name 检测器名称（配置与 AnomalyResult.Detector 使用）
+	Name() string
+	// NewState 创建私有状态（须可 JSON 序列化；无状态返回 nil）
+	NewState() any
+	// Detect 更新状态并给出检测结果（冷启动期间返回 nil）
+	// threshold 为 σ 倍数，误报反馈会抬高该值，各算法按自身含义换算
+	Detect(state *aiops.BaselineState, value float64, now int64, threshold float64) *aiops.AnomalyResult
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Detector)
+)
+
+func init() {
+	Register(emaDetector{})
+	Register(madDetector{})
+	Register(cusumDetector{})
+	Register(bocpdDetector{})
+}
+
+// Register 注册检测器（同名覆盖）
+func Register(d Detector) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[d.Name()] = d
+}
+
+// Lookup 按名称查找检测器
+func Lookup(name string) (Detector, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	d, ok := registry[name]
+	return d, ok
+}
+
+// Detectors 已注册的检测器名称（排序）
+func Detectors() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveDetector 查找检测器，未知名称退回默认检测器
+func resolveDetector(name string) Detector {
+	if d, ok := Lookup(name); ok {
+		return d
+	}
+	d, _ := Lookup(DefaultDetector)
+	return d
+}
+
+// detectorState 取出（必要时创建）检测器私有状态
+func detectorState[T any](state *aiops.BaselineState, d Detector) *T {
+	if s, ok := state.DetectorState.(*T); ok {
+		return s
+	}
+	s, _ := d.NewState().(*T)
+	state.DetectorState = s
+	return s
+}
+
+// encodeDetectorState 序列化检测器私有状态（nil 返回空串）
+func encodeDetectorState(s any) string {
+	if s == nil {
+		return ""
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// decodeDetectorState 反序列化检测器私有状态（未知检测器或损坏数据返回 nil，重新学习）
+func decodeDetectorState(name, raw string) any {
+	d, ok := Lookup(name)
+	if !ok || raw == "" {
+		return nil
+	}
+	s := d.NewState()
+	if s == nil || json.Unmarshal([]byte(raw), s) != nil {
+		return nil
+	}
+	return s
+}
+
+// ==================== ema ====================
+
+// emaDetector EMA + 3σ（按 BaselineState.Mode 选择全局或季节性基线）
+type emaDetector struct{}
+
+func (emaDetector) Name() string  { return DefaultDetector }
+func (emaDetector) NewState() any { return nil }
+
+func (emaDetector) Detect(state *aiops.BaselineState, value float64, now int64, threshold float64) *aiops.AnomalyResult {
+	_, result := detect(state, value, now, threshold)
+	if result == nil {
+		return nil
+	}
+	result.Detector = DefaultDetector
+	baseline := "EMA"
+	if state.Mode == aiops.BaselineSeasonal {
+		baseline = "seasonal"
+	}
+	result.Reason = fmt.Sprintf("%.1fσ from %s baseline %.4g (threshold %.1fσ)", result.Deviation, baseline, result.Baseline, threshold)
+	return result
+}
@@ -0,0 +1,163 @@
+// atlhyper_master_v2/aiops/baseline/registry_test.go
+package baseline
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+)
+
+// runDetector 依次喂入样本，返回每个样本的检测结果（冷启动期间为 nil）
+func runDetector(t *testing.T, name string, values []float64) []*aiops.AnomalyResult {
+	t.Helper()
+	d, ok := Lookup(name)
+	if !ok {
+		t.Fatalf("检测器 %s 未注册", name)
+	}
+	state := &aiops.BaselineState{Detector: name}
+	results := make([]*aiops.AnomalyResult, len(values))
+	for i, v := range values {
+		results[i] = d.Detect(state, v, int64(1000+i*10), aiops.AnomalyThreshold)
+	}
+	return results
+}
+
+// noisy 生成均值 mean、均匀噪声幅度 ±amp 的序列
+func noisy(rng *rand.Rand, n int, mean, amp float64) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = mean + (rng.Float64()*2-1)*amp
+	}
+	return values
+}
+
+func countAnomalies(results []*aiops.AnomalyResult, from, to int) int {
+	n := 0
+	for _, r := range results[from:to] {
+		if r != nil && r.IsAnomaly {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRegistry(t *testing.T) {
+	want := []string{"bocpd", "cusum", "ema", "mad"}
+	if got := strings.Join(Detectors(), ","); got != strings.Join(want, ",") {
+		t.Errorf("Detectors() = %s, want %v", got, want)
+	}
+	if d := resolveDetector("unknown"); d.Name() != DefaultDetector {
+		t.Errorf("未知检测器应退回 %s, got %s", DefaultDetector, d.Name())
+	}
+}
+
+func TestMAD_SpikeDoesNotMaskNextSpike(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	values := noisy(rng, 200, 10, 1)
+	values[150] = 60
+	values[160] = 40
+
+	mad := runDetector(t, "mad", values)
+	for _, i := range []int{150, 160} {
+		r := mad[i]
+		if r == nil || !r.IsAnomaly || r.Detector != "mad" || !strings.Contains(r.Reason, "median") {
+			t.Errorf("样本 %d 应被 MAD 判为异常, got %+v", i, r)
+		}
+	}
+	if n := countAnomalies(mad, 100, 150); n != 0 {
+		t.Errorf("尖峰前不应有异常, got %d", n)
+	}
+
+	// EMA 方差被第一个尖峰抬高，第二个较小尖峰被掩盖
+	if ema := runDetector(t, "ema", values); ema[160].IsAnomaly {
+		t.Log("EMA 同样检测到第二个尖峰")
+	}
+}
+
+func TestCUSUM_DetectsSlowDrift(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	values := noisy(rng, 700, 50, 1)
+	// 第 400 个样本起每个样本上涨 0.02（约 0.035σ），模拟内存缓慢泄漏
+	for i := 400; i < len(values); i++ {
+		values[i] += 0.02 * float64(i-400)
+	}
+
+	cusum := runDetector(t, "cusum", values)
+	if n := countAnomalies(cusum, 100, 400); n != 0 {
+		t.Errorf("平稳阶段不应告警, got %d", n)
+	}
+	if n := countAnomalies(cusum, 400, 700); n == 0 {
+		t.Fatal("CUSUM 应检测到缓慢漂移")
+	}
+	last := cusum[len(cusum)-1]
+	if last.Detector != "cusum" || !strings.Contains(last.Reason, "upward") {
+		t.Errorf("unexpected result: %+v", last)
+	}
+
+	if n := countAnomalies(runDetector(t, "ema", values), 400, 700); n != 0 {
+		t.Logf("EMA 在漂移阶段告警 %d 次", n)
+	}
+}
+
+func TestBOCPD_DetectsLevelShift(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	values := append(noisy(rng, 300, 100, 2), noisy(rng, 100, 115, 2)...)
+
+	bocpd := runDetector(t, "bocpd", values)
+	if n := countAnomalies(bocpd, 100, 300); n > 2 {
+		t.Errorf("平稳阶段误报过多: %d", n)
+	}
+	if n := countAnomalies(bocpd, 300, 310); n == 0 {
+		t.Fatal("BOCPD 应在水平跳变后立即检测到变点")
+	}
+	for _, r := range bocpd[300:310] {
+		if r.IsAnomaly && (r.Detector != "bocpd" || !strings.Contains(r.Reason, "change point")) {
+			t.Errorf("unexpected result: %+v", r)
+		}
+	}
+	// 新水平被学习后不再持续告警
+	if n := countAnomalies(bocpd, 350, 400); n > 2 {
+		t.Errorf("新水平稳定后仍告警 %d 次", n)
+	}
+}
+
+func TestStateManager_DetectorStatePersisted(t *testing.T) {
+	repo := &memBaselineRepo{}
+	m := NewStateManager(repo)
+	m.SetDetectors(func(entityKey, metricName string) string {
+		if metricName == "memory_usage" {
+			return "cusum"
+		}
+		return ""
+	})
+	for i := 0; i < 3; i++ {
+		m.Update([]aiops.MetricDataPoint{
+			{EntityKey: "_cluster/node/n1", MetricName: "memory_usage", Value: 40 + float64(i)},
+			{EntityKey: "_cluster/node/n1", MetricName: "cpu_usage", Value: 20},
+		})
+	}
+	if err := m.FlushToDB(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewStateManager(repo)
+	if err := restored.LoadFromDB(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range restored.GetStates("_cluster/node/n1").States {
+		switch s.MetricName {
+		case "memory_usage":
+			cs, ok := s.DetectorState.(*cusumState)
+			if s.Detector != "cusum" || !ok || cs.Count == 0 {
+				t.Errorf("memory_usage 应恢复 CUSUM 状态, got detector=%q state=%+v", s.Detector, s.DetectorState)
+			}
+		case "cpu_usage":
+			if s.Detector != DefaultDetector || s.DetectorState != nil {
+				t.Errorf("cpu_usage 应使用默认检测器, got %q", s.Detector)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// atlhyper_master_v2/aiops/baseline/replay.go
+// 基线回放: 用录制的指标序列对比不同基线模式 / 检测器的误报率
+package baseline
+
+import "AtlHyper/atlhyper_master_v2/aiops"
+
+// ReplaySample 录制的单个指标样本
+type ReplaySample struct {
+	At    int64   `json:"at"` // Unix 秒
+	Value float64 `json:"value"`
+}
+
+// TimeRange 已知故障窗口（闭区间，Unix 秒）
+type TimeRange struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// ReplayStats 单个基线模式的回放统计
+type ReplayStats struct {
+	Mode              aiops.BaselineMode `json:"mode"`
+	Detector          string             `json:"detector"`
+	Evaluated         int                `json:"evaluated"`         // 冷启动后参与检测的样本数
+	Anomalies         int                `json:"anomalies"`         // 判定为异常的样本数
+	FalsePositives    int                `json:"falsePositives"`    // 故障窗口外的异常
+	Detected          int                `json:"detected"`          // 故障窗口内的异常
+	FalsePositiveRate float64            `json:"falsePositiveRate"` // 误报 / 窗口外检测样本
+}
+
+// Replay 以指定基线模式（默认检测器）按时间顺序回放样本
+func Replay(samples []ReplaySample, mode aiops.BaselineMode, incidents []TimeRange) *ReplayStats {
+	return ReplayDetector(samples, DefaultDetector, mode, incidents)
+}
+
+// ReplayDetector 以指定检测器与基线模式按时间顺序回放样本
+func ReplayDetector(samples []ReplaySample, detector string, mode aiops.BaselineMode, incidents []TimeRange) *ReplayStats {
+	d := resolveDetector(detector)
+	stats := &ReplayStats{Mode: mode, Detector: d.Name()}
+	state := &aiops.BaselineState{Mode: mode, Detector: d.Name()}
+	normal := 0
+	for _, s := range samples {
+		result := d.Detect(state, s.Value, s.At, aiops.AnomalyThreshold)
+		if result == nil {
+			continue
+		}
+		stats.Evaluated++
+		inIncident := inRanges(s.At, incidents)
+		if !inIncident {
+			normal++
+		}
+		if !result.IsAnomaly {
+			continue
+		}
+		stats.Anomalies++
+		if inIncident {
+			stats.Detected++
+		} else {
+			stats.FalsePositives++
+		}
+	}
+	if normal > 0 {
+		stats.FalsePositiveRate = float64(stats.FalsePositives) / float64(normal)
+	}
+	return stats
+}
+
+// CompareModes 依次以 EMA 与季节性基线回放同一序列
+func CompareModes(samples []ReplaySample, incidents []TimeRange) []*ReplayStats {
+	return []*ReplayStats{
+		Replay(samples, aiops.BaselineEMA, incidents),
+		Replay(samples, aiops.BaselineSeasonal, incidents),
+	}
+}
+
+// CompareDetectors 以全部已注册检测器（全局 EMA 基线）回放同一序列
+func CompareDetectors(samples []ReplaySample, incidents []TimeRange) []*ReplayStats {
+	names := Detectors()
+	stats := make([]*ReplayStats, 0, len(names))
+	for _, name := range names {
+		stats = append(stats, ReplayDetector(samples, name, aiops.BaselineEMA, incidents))
+	}
+	return stats
+}
+
+func inRanges(at int64, ranges []TimeRange) bool {
+	for _, r := range ranges {
+		if at >= r.From && at <= r.To {
+			return true
+		}
+	}
+	return false
+}
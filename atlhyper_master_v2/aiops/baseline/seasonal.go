@@ -0,0 +1,97 @@
+// atlhyper_master_v2/aiops/baseline/seasonal.go
+// 季节性基线: 按周内小时 / 日内小时分桶的 EMA + 3σ
+//
+// 全局 EMA 仍然更新（冷启动、零值快速通道与桶样本不足时的回退），
+// 桶内样本足够时以该时段的历史均值与方差计算偏离度，
+// 避免每日流量爬坡被判为异常、夜间低谷掩盖服务中断。
+package baseline
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+)
+
+// DetectSeasonal 使用季节性基线执行异常检测
+func DetectSeasonal(state *aiops.BaselineState, value float64, now int64, threshold float64) (*aiops.BaselineState, *aiops.AnomalyResult) {
+	if state.Seasonal == nil {
+		state.Seasonal = &aiops.SeasonalProfile{}
+	}
+	week, day := seasonalBuckets(state.Seasonal, now)
+
+	// 参考桶在更新前选定：周内小时桶优先，样本不足退回日内小时桶
+	ref := week
+	if ref.Count < aiops.SeasonalMinCount {
+		ref = day
+	}
+	warm := ref.Count >= aiops.SeasonalMinCount
+
+	_, result := DetectWithThreshold(state, value, now, threshold)
+	updateBucket(week, value)
+	updateBucket(day, value)
+	if result == nil || !warm {
+		return state, result
+	}
+
+	sigma := math.Sqrt(ref.Variance)
+	var deviation float64
+	if sigma > 1e-9 {
+		deviation = math.Abs(value-ref.Mean) / sigma
+	}
+	result.Baseline = ref.Mean
+	result.Deviation = deviation
+	result.Score = sigmoid(deviation, threshold, aiops.SigmoidK)
+	result.IsAnomaly = deviation > threshold
+	return state, result
+}
+
+// detect 按基线模式分派检测算法
+func detect(state *aiops.BaselineState, value float64, now int64, threshold float64) (*aiops.BaselineState, *aiops.AnomalyResult) {
+	if state.Mode == aiops.BaselineSeasonal {
+		return DetectSeasonal(state, value, now, threshold)
+	}
+	return DetectWithThreshold(state, value, now, threshold)
+}
+
+// seasonalBuckets 返回时间点对应的周内小时桶与日内小时桶（UTC）
+func seasonalBuckets(p *aiops.SeasonalProfile, now int64) (week, day *aiops.SeasonalBucket) {
+	t := time.Unix(now, 0).UTC()
+	return &p.Week[int(t.Weekday())*24+t.Hour()], &p.Day[t.Hour()]
+}
+
+// updateBucket 更新单个桶的 EMA 与方差（首个样本直接作为均值）
+func updateBucket(b *aiops.SeasonalBucket, value float64) {
+	b.Count++
+	if b.Count == 1 {
+		b.Mean, b.Variance = value, 0
+		return
+	}
+	alpha := aiops.DefaultAlpha
+	oldMean := b.Mean
+	b.Mean = alpha*value + (1-alpha)*b.Mean
+	diff := value - oldMean
+	b.Variance = alpha*diff*diff + (1-alpha)*b.Variance
+}
+
+// encodeSeasonal 序列化季节性分桶（nil 返回空串）
+func encodeSeasonal(p *aiops.SeasonalProfile) string {
+	if p == nil {
+		return ""
+	}
+	data, _ := json.Marshal(p)
+	return string(data)
+}
+
+// decodeSeasonal 反序列化季节性分桶（空串或损坏数据返回 nil，重新学习）
+func decodeSeasonal(raw string) *aiops.SeasonalProfile {
+	if raw == "" {
+		return nil
+	}
+	p := &aiops.SeasonalProfile{}
+	if err := json.Unmarshal([]byte(raw), p); err != nil {
+		return nil
+	}
+	return p
+}
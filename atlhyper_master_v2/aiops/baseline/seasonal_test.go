@@ -0,0 +1,126 @@
+// atlhyper_master_v2/aiops/baseline/seasonal_test.go
+package baseline
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// dailyCycle 生成 weeks 周的 5 分钟粒度序列：08:00~20:00 高峰，其余时间低谷
+// outage 窗口内流量跌到 0（服务中断）
+func dailyCycle(weeks int, outage TimeRange) []ReplaySample {
+	rng := rand.New(rand.NewSource(1))
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC).Unix() // 周一
+	var samples []ReplaySample
+	for at := start; at < start+int64(weeks)*7*86400; at += 300 {
+		hour := time.Unix(at, 0).UTC().Hour()
+		value := 20.0
+		if hour >= 8 && hour < 20 {
+			value = 200
+		}
+		value *= 1 + (rng.Float64()-0.5)*0.1
+		if at >= outage.From && at <= outage.To {
+			value = 0
+		}
+		samples = append(samples, ReplaySample{At: at, Value: value})
+	}
+	return samples
+}
+
+func TestCompareModes_SeasonalReducesFalsePositives(t *testing.T) {
+	// 第 3 周周三 10:00~10:30 中断
+	outageStart := time.Date(2026, 3, 18, 10, 0, 0, 0, time.UTC).Unix()
+	outage := TimeRange{From: outageStart, To: outageStart + 1800}
+	stats := CompareModes(dailyCycle(3, outage), []TimeRange{outage})
+
+	ema, seasonal := stats[0], stats[1]
+	t.Logf("ema: %+v", *ema)
+	t.Logf("seasonal: %+v", *seasonal)
+
+	if ema.FalsePositives == 0 {
+		t.Fatal("EMA 基线应将每日爬坡判为异常")
+	}
+	if seasonal.FalsePositives >= ema.FalsePositives/2 {
+		t.Errorf("季节性误报 %d 应明显少于 EMA %d", seasonal.FalsePositives, ema.FalsePositives)
+	}
+	if seasonal.Detected == 0 {
+		t.Error("季节性基线应检测到中断")
+	}
+}
+
+func TestDetectSeasonal_FallsBackToEMAWhenBucketCold(t *testing.T) {
+	state := &aiops.BaselineState{
+		Mode:     aiops.BaselineSeasonal,
+		EMA:      50,
+		Variance: 25,
+		Count:    int64(aiops.ColdStartMinCount),
+	}
+	_, result := DetectSeasonal(state, 80, 1000, aiops.AnomalyThreshold)
+	if result == nil || !result.IsAnomaly {
+		t.Fatalf("桶样本不足时应使用全局 EMA 检测, got %+v", result)
+	}
+	if state.Seasonal == nil || state.Seasonal.Day[0].Count != 1 {
+		t.Error("季节桶应随检测更新")
+	}
+}
+
+func TestStateManager_SeasonalModePersisted(t *testing.T) {
+	repo := &memBaselineRepo{}
+	m := NewStateManager(repo)
+	m.SetBaselineModes(func(entityKey, metricName string) aiops.BaselineMode {
+		if metricName == "request_rate" {
+			return aiops.BaselineSeasonal
+		}
+		return ""
+	})
+	m.Update([]aiops.MetricDataPoint{
+		{EntityKey: "default/service/api", MetricName: "request_rate", Value: 10},
+		{EntityKey: "default/service/api", MetricName: "error_rate", Value: 0.01},
+	})
+	if err := m.FlushToDB(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewStateManager(repo)
+	if err := restored.LoadFromDB(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range restored.GetStates("default/service/api").States {
+		switch s.MetricName {
+		case "request_rate":
+			if s.Mode != aiops.BaselineSeasonal || s.Seasonal == nil {
+				t.Errorf("request_rate 应恢复季节性分桶, got mode=%q", s.Mode)
+			}
+		case "error_rate":
+			if s.Mode != aiops.BaselineEMA || s.Seasonal != nil {
+				t.Errorf("error_rate 应为 EMA, got mode=%q", s.Mode)
+			}
+		}
+	}
+}
+
+type memBaselineRepo struct {
+	states []*database.AIOpsBaselineState
+}
+
+func (r *memBaselineRepo) BatchUpsert(ctx context.Context, states []*database.AIOpsBaselineState) error {
+	r.states = append(r.states, states...)
+	return nil
+}
+
+func (r *memBaselineRepo) ListAll(ctx context.Context) ([]*database.AIOpsBaselineState, error) {
+	return r.states, nil
+}
+
+func (r *memBaselineRepo) ListByEntity(ctx context.Context, entityKey string) ([]*database.AIOpsBaselineState, error) {
+	return nil, nil
+}
+
+func (r *memBaselineRepo) DeleteByEntity(ctx context.Context, entityKey string) error {
+	return nil
+}
@@ -21,17 +21,70 @@ type StateManager struct {
 	// 最新异常结果缓存（供 API 查询）
 	anomalies map[string][]*aiops.AnomalyResult // key = entityKey
 
+	// 误报反馈阈值（key = entityPattern + "|" + metricName）
+	thresholds   map[string]*database.AIOpsThresholdFeedback
+	feedbackRepo database.AIOpsFeedbackRepository
+
 	repo database.AIOpsBaselineRepository
+
+	// 指标基线模式（nil = 全部使用 EMA）
+	modeFn func(entityKey, metricName string) aiops.BaselineMode
+	// 指标检测器（nil = 全部使用 DefaultDetector）
+	detectorFn func(entityKey, metricName string) string
+
+	now func() time.Time
 }
 
 // NewStateManager 创建基线状态管理器
 func NewStateManager(repo database.AIOpsBaselineRepository) *StateManager {
 	return &StateManager{
-		states:    make(map[string]*aiops.BaselineState),
-		dirty:     make(map[string]bool),
-		anomalies: make(map[string][]*aiops.AnomalyResult),
-		repo:      repo,
+		states:     make(map[string]*aiops.BaselineState),
+		dirty:      make(map[string]bool),
+		anomalies:  make(map[string][]*aiops.AnomalyResult),
+		thresholds: make(map[string]*database.AIOpsThresholdFeedback),
+		repo:       repo,
+		now:        time.Now,
+	}
+}
+
+// SetClock 设置时钟（离线回放按快照时间推进）
+func (m *StateManager) SetClock(now func() time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+}
+
+// SetBaselineModes 设置指标基线模式选择函数（通常来自 risk.RiskConfig）
+func (m *StateManager) SetBaselineModes(fn func(entityKey, metricName string) aiops.BaselineMode) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.modeFn = fn
+}
+
+// modeLocked 返回指标的基线模式（调用方持有锁）
+func (m *StateManager) modeLocked(entityKey, metricName string) aiops.BaselineMode {
+	if m.modeFn == nil {
+		return aiops.BaselineEMA
 	}
+	if mode := m.modeFn(entityKey, metricName); mode != "" {
+		return mode
+	}
+	return aiops.BaselineEMA
+}
+
+// SetDetectors 设置指标检测器选择函数（通常来自 risk.RiskConfig）
+func (m *StateManager) SetDetectors(fn func(entityKey, metricName string) string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.detectorFn = fn
+}
+
+// detectorLocked 返回指标使用的检测器（调用方持有锁）
+func (m *StateManager) detectorLocked(entityKey, metricName string) Detector {
+	if m.detectorFn == nil {
+		return resolveDetector(DefaultDetector)
+	}
+	return resolveDetector(m.detectorFn(entityKey, metricName))
 }
 
 // Update 更新指标并检测异常
@@ -39,7 +92,7 @@ func (m *StateManager) Update(points []aiops.MetricDataPoint) []*aiops.AnomalyRe
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	now := time.Now().Unix()
+	now := m.now().Unix()
 	var results []*aiops.AnomalyResult
 
 	for _, p := range points {
@@ -55,8 +108,21 @@ func (m *StateManager) Update(points []aiops.MetricDataPoint) []*aiops.AnomalyRe
 			m.states[cacheKey] = state
 		}
 
+		// 配置切换基线模式时保留全局 EMA，季节桶重新学习
+		if mode := m.modeLocked(p.EntityKey, p.MetricName); state.Mode != mode {
+			state.Mode = mode
+			state.Seasonal = nil
+		}
+
+		// 切换检测器时丢弃旧算法的私有状态
+		detector := m.detectorLocked(p.EntityKey, p.MetricName)
+		if state.Detector != detector.Name() {
+			state.Detector = detector.Name()
+			state.DetectorState = nil
+		}
+
 		// 执行异常检测
-		_, result := Detect(state, p.Value, now)
+		result := detector.Detect(state, p.Value, now, m.thresholdLocked(p.EntityKey, p.MetricName))
 		m.dirty[cacheKey] = true
 
 		if result != nil {
@@ -82,6 +148,11 @@ func (m *StateManager) FlushToDB(ctx context.Context) error {
 				Variance:   state.Variance,
 				Count:      state.Count,
 				UpdatedAt:  state.UpdatedAt,
+				Mode:       string(state.Mode),
+				Seasonal:   encodeSeasonal(state.Seasonal),
+
+				Detector:      state.Detector,
+				DetectorState: encodeDetectorState(state.DetectorState),
 			})
 		}
 	}
@@ -111,6 +182,11 @@ func (m *StateManager) LoadFromDB(ctx context.Context) error {
 			Variance:   s.Variance,
 			Count:      s.Count,
 			UpdatedAt:  s.UpdatedAt,
+			Mode:       aiops.BaselineMode(s.Mode),
+			Seasonal:   decodeSeasonal(s.Seasonal),
+
+			Detector:      s.Detector,
+			DetectorState: decodeDetectorState(s.Detector, s.DetectorState),
 		}
 	}
 	return nil
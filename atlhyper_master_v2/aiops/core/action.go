@@ -0,0 +1,159 @@
+// atlhyper_master_v2/aiops/core/action.go
+// 事件人工处理: 确认、指派、评论、手动解决、误报反馈
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+)
+
+// ApplyIncidentAction 执行人工处理动作，返回最新事件详情
+func (e *engine) ApplyIncidentAction(ctx context.Context, incidentID string, action *aiops.IncidentAction) (*aiops.IncidentDetail, error) {
+	detail := e.incidentStore.GetIncident(ctx, incidentID)
+	if detail == nil {
+		return nil, aiops.ErrIncidentNotFound
+	}
+	inc := &detail.Incident
+	now := time.Now()
+
+	switch action.Type {
+	case aiops.IncidentActionAck:
+		if inc.State == aiops.StateStable {
+			return nil, aiops.ErrIncidentResolved
+		}
+		acked, err := e.incidentStore.Ack(ctx, incidentID, action.Actor, action.Comment, now)
+		if err != nil {
+			return nil, err
+		}
+		if !acked {
+			return nil, aiops.ErrIncidentAcked
+		}
+
+	case aiops.IncidentActionAssign:
+		if err := e.incidentStore.Assign(ctx, incidentID, action.Assignee, action.Actor, action.Comment, now); err != nil {
+			return nil, err
+		}
+
+	case aiops.IncidentActionComment:
+		if err := e.incidentStore.Comment(ctx, incidentID, action.Actor, action.Comment, now); err != nil {
+			return nil, err
+		}
+
+	case aiops.IncidentActionResolve:
+		if inc.State == aiops.StateStable {
+			return nil, aiops.ErrIncidentResolved
+		}
+		msg := fmt.Sprintf("人工解决: %s → stable", inc.State)
+		if action.Comment != "" {
+			msg += ": " + action.Comment
+		}
+		if err := e.closeIncident(ctx, inc, action.Actor, msg, now); err != nil {
+			return nil, err
+		}
+
+	case aiops.IncidentActionFalsePositive:
+		if inc.FalsePositive {
+			return nil, aiops.ErrFalsePositive
+		}
+		if err := e.markFalsePositive(ctx, detail, action, now); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown incident action: %s", action.Type)
+	}
+
+	log.Info("事件人工处理", "id", incidentID, "action", action.Type, "actor", action.Actor)
+	return e.incidentStore.GetIncident(ctx, incidentID), nil
+}
+
+// closeIncident 人工关闭事件: 移出状态机、写库并通知监听器（告警恢复通知）
+func (e *engine) closeIncident(ctx context.Context, inc *aiops.Incident, actor, detail string, now time.Time) error {
+	entityKey := inc.RootCause
+	if e.sm != nil {
+		if entry := e.sm.RemoveByIncident(inc.ID); entry != nil {
+			entityKey = entry.EntityKey
+		}
+	}
+	if err := e.incidentStore.ResolveManually(ctx, inc.ID, entityKey, actor, detail, now); err != nil {
+		return err
+	}
+	e.emitTransition(aiops.TransitionEvent{
+		Kind: aiops.TransitionStable, IncidentID: inc.ID, EntityKey: entityKey,
+		State: aiops.StateStable, At: now,
+	})
+	return nil
+}
+
+// markFalsePositive 标记误报: 抬高相关实体模式 + 指标的检测阈值，活跃事件同时关闭
+func (e *engine) markFalsePositive(ctx context.Context, detail *aiops.IncidentDetail, action *aiops.IncidentAction, now time.Time) error {
+	inc := &detail.Incident
+
+	var adjustments []string
+	for _, entityKey := range incidentEntityKeys(detail) {
+		updated, err := e.stateManager.RaiseThreshold(ctx, entityKey, e.anomalousMetrics(inc.ClusterID, entityKey))
+		if err != nil {
+			log.Warn("持久化误报反馈阈值失败", "entity", entityKey, "err", err)
+		}
+		for _, fb := range updated {
+			adjustments = append(adjustments, fmt.Sprintf("%s/%s → %.1fσ", fb.EntityPattern, fb.MetricName, fb.Threshold))
+		}
+	}
+
+	msg := "标记为误报"
+	if len(adjustments) > 0 {
+		msg += "，阈值调整: " + strings.Join(adjustments, ", ")
+	}
+	if action.Comment != "" {
+		msg += "；" + action.Comment
+	}
+	if err := e.incidentStore.MarkFalsePositive(ctx, inc.ID, action.Actor, msg, now); err != nil {
+		return err
+	}
+
+	if inc.State != aiops.StateStable {
+		return e.closeIncident(ctx, inc, action.Actor, "误报关闭", now)
+	}
+	return nil
+}
+
+// anomalousMetrics 返回实体当前处于异常的指标名（无则返回 nil，作用于全部指标）
+func (e *engine) anomalousMetrics(clusterID, entityKey string) []string {
+	seen := make(map[string]bool)
+	e.anomalyMu.RLock()
+	for _, a := range e.anomalyCache[clusterID] {
+		if a.IsAnomaly && a.EntityKey == entityKey {
+			seen[a.MetricName] = true
+		}
+	}
+	e.anomalyMu.RUnlock()
+
+	metrics := make([]string, 0, len(seen))
+	for m := range seen {
+		metrics = append(metrics, m)
+	}
+	sort.Strings(metrics)
+	return metrics
+}
+
+// incidentEntityKeys 事件根因 + 关联实体（去重）
+func incidentEntityKeys(detail *aiops.IncidentDetail) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	add := func(key string) {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	add(detail.RootCause)
+	for _, ent := range detail.Entities {
+		add(ent.EntityKey)
+	}
+	return keys
+}
@@ -5,6 +5,7 @@ package core
 import (
 	"context"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +24,11 @@ import (
 
 var log = logger.Module("AIOps")
 
+const (
+	recoveryCheckInterval = 10 * time.Minute // Recovery→Stable 检查间隔
+	staleEntryTimeout     = 30 * time.Minute // 状态机条目未评估超过该时长自动关闭
+)
+
 // IncidentNotifyFunc 事件通知回调（供 AI 后台自动分析）
 type IncidentNotifyFunc func(incidentID, severity, trigger string)
 
@@ -41,6 +47,10 @@ type engine struct {
 	// AI 后台分析通知回调（可选）
 	incidentNotify IncidentNotifyFunc
 
+	// 状态机转换监听器（告警通知等，可选）
+	listeners   []aiops.TransitionListener
+	listenersMu sync.RWMutex
+
 	// 异常结果缓存（供风险详情查询）
 	anomalyCache map[string][]*aiops.AnomalyResult // clusterID -> anomalies
 	anomalyMu    sync.RWMutex
@@ -56,6 +66,23 @@ func (e *engine) SetIncidentNotify(fn func(incidentID, severity, trigger string)
 	e.incidentNotify = IncidentNotifyFunc(fn)
 }
 
+// AddTransitionListener 注册状态机转换监听器
+// 监听器在状态机评估路径上同步调用，不应阻塞
+func (e *engine) AddTransitionListener(fn aiops.TransitionListener) {
+	e.listenersMu.Lock()
+	e.listeners = append(e.listeners, fn)
+	e.listenersMu.Unlock()
+}
+
+// emitTransition 通知所有转换监听器
+func (e *engine) emitTransition(ev aiops.TransitionEvent) {
+	e.listenersMu.RLock()
+	defer e.listenersMu.RUnlock()
+	for _, fn := range e.listeners {
+		fn(ev)
+	}
+}
+
 // OnSnapshot 快照更新时触发
 func (e *engine) OnSnapshot(clusterID string) {
 	snap, err := e.store.GetSnapshot(clusterID)
@@ -63,12 +90,7 @@ func (e *engine) OnSnapshot(clusterID string) {
 		return
 	}
 
-	// 获取 OTel 数据（直接从 snap.OTel 读取，非 Ring Buffer）
-	otel := snap.OTel
-
-	// 1. 构建并更新依赖图
-	graph := correlator.BuildFromSnapshot(clusterID, snap, otel)
-	e.corr.Update(clusterID, graph)
+	graph := e.analyze(clusterID, snap, time.Now())
 
 	// 持久化图快照（异步，不阻塞主流程）
 	go func() {
@@ -83,8 +105,17 @@ func (e *engine) OnSnapshot(clusterID string) {
 			log.Error("持久化依赖图失败", "cluster", clusterID, "err", err)
 		}
 	}()
+}
 
-	// otel 已在上方从 snap.OTel 获取（直接读取，非 Ring Buffer）
+// analyze 对单个快照执行图更新 + 基线检测 + 风险评分 + 状态机评估，返回本次构建的依赖图
+// now 为确定性异常的检测时间（在线为当前时间，离线回放为快照时间）
+func (e *engine) analyze(clusterID string, snap *cluster.ClusterSnapshot, now time.Time) *aiops.DependencyGraph {
+	// 获取 OTel 数据（直接从 snap.OTel 读取，非 Ring Buffer）
+	otel := snap.OTel
+
+	// 1. 构建并更新依赖图
+	graph := correlator.BuildFromSnapshot(clusterID, snap, otel)
+	e.corr.Update(clusterID, graph)
 
 	// 2. 清理已不存在于快照中的实体基线状态（防止滚动更新后旧 Pod 状态残留）
 	activeKeys := extractActiveEntityKeys(snap, otel)
@@ -92,20 +123,23 @@ func (e *engine) OnSnapshot(clusterID string) {
 		log.Debug("清理过期基线状态", "cluster", clusterID, "removed", removed)
 	}
 
-	// 3. 提取指标并进行基线检测（路径 A: EMA+3σ）
+	// 3. 提取指标并进行基线检测（路径 A: 按指标配置的检测器，默认 EMA+3σ）
 	points := baseline.ExtractMetrics(clusterID, snap, otel)
 	if len(points) == 0 {
-		return
+		return graph
 	}
 
 	results := e.stateManager.Update(points)
 
 	// 路径 B: 确定性异常直注（绕过冷启动）
-	deterministicResults := baseline.ExtractDeterministicAnomalies(snap)
+	deterministicResults := baseline.ExtractDeterministicAnomaliesAt(snap, now)
 	// Enhanced: OTel 确定性异常（APM 高错误率/高延迟、日志错误尖峰）
 	// otel==nil 时函数内部直接 return nil，不影响 Basic 层
-	otelDeterministic := baseline.ExtractOTelDeterministicAnomalies(otel)
+	otelDeterministic := baseline.ExtractOTelDeterministicAnomaliesAt(otel, now)
 	deterministicResults = append(deterministicResults, otelDeterministic...)
+	for _, r := range deterministicResults {
+		r.Detector = baseline.DeterministicDetector
+	}
 	results = mergeAnomalyResults(results, deterministicResults)
 
 	// 缓存异常结果
@@ -138,6 +172,7 @@ func (e *engine) OnSnapshot(clusterID string) {
 			e.sm.Evaluate(e.bgCtx, clusterID, entityRisks, clusterRisk)
 		}
 	}
+	return graph
 }
 
 // ==================== TransitionCallback 实现 ====================
@@ -149,6 +184,12 @@ func (e *engine) OnWarningCreated(ctx context.Context, clusterID, entityKey stri
 		severity := aiops.SeverityFromRisk(risk.RFinal)
 		e.incidentNotify(id, severity, "incident_created")
 	}
+	if id != "" {
+		e.emitTransition(aiops.TransitionEvent{
+			Kind: aiops.TransitionWarningCreated, IncidentID: id, EntityKey: entityKey,
+			State: aiops.StateWarning, RFinal: risk.RFinal, At: now,
+		})
+	}
 	return id
 }
 
@@ -159,11 +200,19 @@ func (e *engine) OnStateEscalated(ctx context.Context, incidentID string, state
 		severity := aiops.SeverityFromRisk(risk.RFinal)
 		e.incidentNotify(incidentID, severity, "state_escalated")
 	}
+	e.emitTransition(aiops.TransitionEvent{
+		Kind: aiops.TransitionEscalated, IncidentID: incidentID,
+		State: state, RFinal: risk.RFinal, At: now,
+	})
 }
 
 // OnRecoveryStarted 开始恢复
 func (e *engine) OnRecoveryStarted(ctx context.Context, incidentID string, risk *aiops.EntityRisk, now time.Time) {
 	e.incidentStore.UpdateState(ctx, incidentID, aiops.StateRecovery, risk, now)
+	e.emitTransition(aiops.TransitionEvent{
+		Kind: aiops.TransitionRecoveryStarted, IncidentID: incidentID,
+		State: aiops.StateRecovery, RFinal: risk.RFinal, At: now,
+	})
 }
 
 // OnRecurrence 事件复发
@@ -175,6 +224,10 @@ func (e *engine) OnRecurrence(ctx context.Context, incidentID string, risk *aiop
 // OnStable 事件稳定（关闭）
 func (e *engine) OnStable(ctx context.Context, incidentID string, entityKey string, now time.Time) {
 	e.incidentStore.Resolve(ctx, incidentID, entityKey, now)
+	e.emitTransition(aiops.TransitionEvent{
+		Kind: aiops.TransitionStable, IncidentID: incidentID, EntityKey: entityKey,
+		State: aiops.StateStable, At: now,
+	})
 }
 
 // ==================== 查询方法 ====================
@@ -389,6 +442,9 @@ func (e *engine) Start(ctx context.Context) error {
 	if err := e.stateManager.LoadFromDB(ctx); err != nil {
 		log.Warn("加载基线状态失败", "err", err)
 	}
+	if err := e.stateManager.LoadFeedbackFromDB(ctx); err != nil {
+		log.Warn("加载误报反馈阈值失败", "err", err)
+	}
 
 	// 2. 从数据库恢复依赖图
 	clusterIDs, err := e.graphRepo.ListClusterIDs(ctx)
@@ -577,6 +633,7 @@ func (e *engine) reloadActiveIncidents(ctx context.Context) {
 		}
 		entry := &aiops.StateMachineEntry{
 			EntityKey:       inc.RootCause,
+			ClusterID:       inc.ClusterID,
 			CurrentState:    inc.State,
 			IncidentID:      inc.ID,
 			LastEvaluatedAt: now.Unix(),
@@ -587,6 +644,20 @@ func (e *engine) reloadActiveIncidents(ctx context.Context) {
 	log.Info("活跃事件恢复到状态机", "count", count)
 }
 
+// podNode Pod 所在节点名（依赖图 runs_on 边，未知返回空）
+func (e *engine) podNode(clusterID, podKey string) string {
+	graph := e.corr.GetGraph(clusterID)
+	if graph == nil {
+		return ""
+	}
+	for _, to := range graph.Adjacency()[podKey] {
+		if name, ok := strings.CutPrefix(to, "_cluster/node/"); ok {
+			return name
+		}
+	}
+	return ""
+}
+
 // extractActiveEntityKeys 从快照中提取当前所有活跃实体的 entityKey 集合
 func extractActiveEntityKeys(snap *cluster.ClusterSnapshot, otel *cluster.OTelSnapshot) map[string]bool {
 	keys := make(map[string]bool, len(snap.Pods)+len(snap.Nodes)+len(snap.Services))
@@ -629,7 +700,7 @@ func extractActiveEntityKeys(snap *cluster.ClusterSnapshot, otel *cluster.OTelSn
 // recoveryCheckLoop 定期检查 Recovery 状态的实体是否可以转为 Stable
 func (e *engine) recoveryCheckLoop(ctx context.Context) {
 	defer e.wg.Done()
-	ticker := time.NewTicker(10 * time.Minute) // 每 10 分钟检查一次
+	ticker := time.NewTicker(recoveryCheckInterval)
 	defer ticker.Stop()
 
 	for {
@@ -638,7 +709,7 @@ func (e *engine) recoveryCheckLoop(ctx context.Context) {
 			return
 		case <-ticker.C:
 			e.sm.CheckRecoveryToStable(ctx)
-			e.sm.CleanupStaleEntries(ctx, 30*time.Minute)
+			e.sm.CleanupStaleEntries(ctx, staleEntryTimeout)
 		}
 	}
 }
@@ -9,6 +9,8 @@ import (
 	"AtlHyper/atlhyper_master_v2/aiops/baseline"
 	"AtlHyper/atlhyper_master_v2/aiops/correlator"
 	"AtlHyper/atlhyper_master_v2/aiops/incident"
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+	"AtlHyper/atlhyper_master_v2/aiops/policy"
 	"AtlHyper/atlhyper_master_v2/aiops/risk"
 	"AtlHyper/atlhyper_master_v2/aiops/statemachine"
 	"AtlHyper/atlhyper_master_v2/database"
@@ -21,7 +23,10 @@ type EngineConfig struct {
 	GraphRepo     database.AIOpsGraphRepository
 	BaselineRepo  database.AIOpsBaselineRepository
 	IncidentRepo  database.AIOpsIncidentRepository
+	FeedbackRepo  database.AIOpsFeedbackRepository // 可选，nil = 误报反馈仅内存生效
 	SLORepo       database.SLORepository
+	Policies      *policy.Manager      // 可选，nil = 使用编译内置的默认阈值与风险配置
+	Maintenance   *maintenance.Manager // 可选，nil = 不启用维护窗口
 	FlushInterval time.Duration
 }
 
@@ -32,12 +37,13 @@ func NewEngine(cfg EngineConfig) aiops.Engine {
 	}
 
 	incStore := incident.NewStore(cfg.IncidentRepo)
+	riskCfg := risk.DefaultRiskConfig()
 
 	e := &engine{
 		store:         cfg.Store,
 		corr:          correlator.NewCorrelator(),
 		stateManager:  baseline.NewStateManager(cfg.BaselineRepo),
-		scorer:        risk.NewScorer(nil),
+		scorer:        risk.NewScorer(riskCfg),
 		incidentStore: incStore,
 		graphRepo:     cfg.GraphRepo,
 		sloRepo:       cfg.SLORepo,
@@ -45,8 +51,53 @@ func NewEngine(cfg EngineConfig) aiops.Engine {
 		flushInterval: cfg.FlushInterval,
 	}
 
+	configureBaseline(e.stateManager, riskCfg)
+
+	if cfg.FeedbackRepo != nil {
+		e.stateManager.SetFeedbackRepo(cfg.FeedbackRepo)
+	}
+
 	// 创建状态机，engine 本身作为 TransitionCallback
 	e.sm = statemachine.NewStateMachine(e)
 
+	if cfg.Policies != nil {
+		configurePolicies(e, cfg.Policies)
+	}
+	if cfg.Maintenance != nil {
+		configureMaintenance(e, cfg.Maintenance)
+	}
+
 	return e
 }
+
+// configureBaseline 基线模式与检测器按指标取自风险配置
+// （request_rate / apm_rps 默认季节性，log_error_count 用 MAD，memory_usage 用 CUSUM）
+func configureBaseline(m *baseline.StateManager, riskCfg *risk.RiskConfig) {
+	m.SetBaselineModes(func(entityKey, metricName string) aiops.BaselineMode {
+		return riskCfg.GetBaselineMode(aiops.ExtractEntityType(entityKey), metricName)
+	})
+	m.SetDetectors(func(entityKey, metricName string) string {
+		return riskCfg.GetDetector(aiops.ExtractEntityType(entityKey), metricName)
+	})
+}
+
+// configurePolicies 风险配置、状态转换阈值、基线模式与检测器改由 AIOps 策略按集群 / 命名空间 / 实体类型解析
+// 每次评估时解析，策略热加载后下一个快照即生效，内存状态保留
+func configurePolicies(e *engine, policies *policy.Manager) {
+	e.scorer.SetConfigResolver(policies.RiskConfig)
+	e.sm.SetThresholds(policies.Thresholds)
+	e.stateManager.SetBaselineModes(policies.BaselineMode)
+	e.stateManager.SetDetectors(policies.Detector)
+}
+
+// configureMaintenance 维护窗口内状态机继续评分，但不创建 / 升级事件
+// 节点窗口需覆盖其上的 Pod，Pod 所在节点取自依赖图的 runs_on 边
+func configureMaintenance(e *engine, windows *maintenance.Manager) {
+	e.sm.SetMaintenance(func(clusterID, entityKey string, now time.Time) bool {
+		t := maintenance.EntityTarget(clusterID, entityKey)
+		if t.Kind == "pod" {
+			t.Node = e.podNode(clusterID, entityKey)
+		}
+		return windows.Covers(t, now) != nil
+	})
+}
@@ -0,0 +1,82 @@
+// atlhyper_master_v2/aiops/core/pipeline.go
+// 离线分析流水线: 与在线引擎共用 analyze，不持久化、不启动后台任务，时间随快照采集时间推进
+package core
+
+import (
+	"context"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/aiops/baseline"
+	"AtlHyper/atlhyper_master_v2/aiops/correlator"
+	"AtlHyper/atlhyper_master_v2/aiops/policy"
+	"AtlHyper/atlhyper_master_v2/aiops/risk"
+	"AtlHyper/atlhyper_master_v2/aiops/statemachine"
+	"AtlHyper/model_v3/cluster"
+)
+
+// Pipeline 离线分析流水线（回放 / 回测用）
+type Pipeline struct {
+	e         *engine
+	now       time.Time
+	lastCheck time.Time // 上次 Recovery→Stable 检查的回放时间
+}
+
+// NewPipeline 创建离线分析流水线
+// riskCfg 为 nil 时使用默认风险配置；callback 接收状态机转换（代替事件存储）
+func NewPipeline(riskCfg *risk.RiskConfig, callback statemachine.TransitionCallback) *Pipeline {
+	if riskCfg == nil {
+		riskCfg = risk.DefaultRiskConfig()
+	}
+	p := &Pipeline{}
+	clock := func() time.Time { return p.now }
+
+	p.e = &engine{
+		corr:         correlator.NewCorrelator(),
+		stateManager: baseline.NewStateManager(nil),
+		scorer:       risk.NewScorer(riskCfg),
+		sm:           statemachine.NewStateMachine(callback),
+		anomalyCache: make(map[string][]*aiops.AnomalyResult),
+		bgCtx:        context.Background(),
+	}
+	configureBaseline(p.e.stateManager, riskCfg)
+	p.e.stateManager.SetClock(clock)
+	p.e.scorer.SetClock(clock)
+	p.e.sm.SetClock(clock)
+	return p
+}
+
+// SetPolicies 按 AIOps 策略解析阈值与风险配置（离线验证策略变更）
+func (p *Pipeline) SetPolicies(policies *policy.Manager) {
+	configurePolicies(p.e, policies)
+}
+
+// Feed 以快照采集时间为当前时间分析一个快照（快照须按时间升序喂入）
+func (p *Pipeline) Feed(snap *cluster.ClusterSnapshot) {
+	if snap.FetchedAt.Before(p.now) {
+		return
+	}
+	p.now = snap.FetchedAt
+	if p.lastCheck.IsZero() {
+		p.lastCheck = p.now
+	}
+
+	// 按回放时间补齐在线引擎的定期检查
+	for !p.now.Before(p.lastCheck.Add(recoveryCheckInterval)) {
+		p.lastCheck = p.lastCheck.Add(recoveryCheckInterval)
+		p.e.sm.CheckRecoveryToStable(p.e.bgCtx)
+		p.e.sm.CleanupStaleEntries(p.e.bgCtx, staleEntryTimeout)
+	}
+
+	p.e.analyze(snap.ClusterID, snap, p.now)
+}
+
+// Now 当前回放时间
+func (p *Pipeline) Now() time.Time {
+	return p.now
+}
+
+// ClusterRisk 集群当前风险评分
+func (p *Pipeline) ClusterRisk(clusterID string) *aiops.ClusterRisk {
+	return p.e.scorer.GetClusterRisk(clusterID)
+}
@@ -70,6 +70,11 @@ func (m *mockIncidentRepo) TopRootCauses(ctx context.Context, clusterID string,
 func (m *mockIncidentRepo) ListByEntity(ctx context.Context, entityKey string, since time.Time) ([]*database.AIOpsIncident, error) {
 	return m.byEntity, nil
 }
+func (m *mockIncidentRepo) Ack(ctx context.Context, id, by string, at time.Time) (bool, error) {
+	return false, nil
+}
+func (m *mockIncidentRepo) Assign(ctx context.Context, id, assignee string) error  { return nil }
+func (m *mockIncidentRepo) MarkFalsePositive(ctx context.Context, id string) error { return nil }
 
 // mockAIService 模拟 ai.AIService
 type mockAIService struct {
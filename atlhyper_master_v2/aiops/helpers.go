@@ -2,6 +2,8 @@
 // AIOps 工具函数
 package aiops
 
+import "strings"
+
 // EntityKey 生成实体唯一标识
 // 格式: "namespace/type/name"
 // 示例:
@@ -15,3 +17,44 @@ func EntityKey(namespace, entityType, name string) string {
 	}
 	return namespace + "/" + entityType + "/" + name
 }
+
+// EntityPattern 将实体 key 归一化为模式，使同一工作负载的不同副本共享反馈
+// Pod 名称去掉控制器生成的随机后缀，其他实体原样返回
+// 示例:
+//
+//	"default/pod/api-server-7d4b9c8f6-x2k9z" → "default/pod/api-server-*"
+//	"default/pod/redis-0"                    → "default/pod/redis-*"
+//	"default/service/api-server"             → "default/service/api-server"
+func EntityPattern(entityKey string) string {
+	parts := strings.SplitN(entityKey, "/", 3)
+	if len(parts) != 3 || parts[1] != "pod" {
+		return entityKey
+	}
+	segs := strings.Split(parts[2], "-")
+	n := len(segs)
+	for n > 1 && isGeneratedSuffix(segs[n-1]) {
+		n--
+	}
+	if n == len(segs) {
+		return entityKey
+	}
+	return parts[0] + "/pod/" + strings.Join(segs[:n], "-") + "-*"
+}
+
+// isGeneratedSuffix 判断名称片段是否为控制器生成的后缀
+// StatefulSet 序号（纯数字），或 ReplicaSet / Pod 哈希（5~10 位，仅含 K8s 随机字符集）
+func isGeneratedSuffix(seg string) bool {
+	if seg == "" {
+		return false
+	}
+	if strings.Trim(seg, "0123456789") == "" {
+		return true
+	}
+	if len(seg) < 5 || len(seg) > 10 {
+		return false
+	}
+	return strings.Trim(seg, k8sRandAlphabet) == ""
+}
+
+// k8sRandAlphabet K8s 生成名称后缀使用的字符集（去掉元音和易混淆数字）
+const k8sRandAlphabet = "bcdfghjklmnpqrstvwxz2456789"
@@ -0,0 +1,26 @@
+// atlhyper_master_v2/aiops/helpers_test.go
+package aiops
+
+import "testing"
+
+func TestEntityPattern(t *testing.T) {
+	cases := []struct {
+		key  string
+		want string
+	}{
+		{"default/pod/api-server-7d4b9c8f6-x2k9z", "default/pod/api-server-*"},
+		{"default/pod/api-server-7d4b9c8f6-qwzrt", "default/pod/api-server-*"},
+		{"default/pod/redis-0", "default/pod/redis-*"},
+		{"kube-system/pod/kube-proxy-x2k9z", "kube-system/pod/kube-proxy-*"},
+		{"default/pod/api-server", "default/pod/api-server"},
+		{"default/pod/standalone", "default/pod/standalone"},
+		{"default/service/api-server-7d4b9c8f6", "default/service/api-server-7d4b9c8f6"},
+		{"_cluster/node/worker-3", "_cluster/node/worker-3"},
+		{"invalid", "invalid"},
+	}
+	for _, c := range cases {
+		if got := EntityPattern(c.key); got != c.want {
+			t.Errorf("EntityPattern(%q) = %q, want %q", c.key, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// atlhyper_master_v2/aiops/incident/action.go
+// 事件人工处理: 确认、指派、评论、手动解决、误报标记
+// 与引擎自动写入不同，人工操作失败需返回给调用方，时间线记录执行用户
+package incident
+
+import (
+	"context"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// Ack 确认事件，返回是否实际更新（已确认的事件返回 false）
+func (s *Store) Ack(ctx context.Context, incidentID, actor, note string, now time.Time) (bool, error) {
+	acked, err := s.repo.Ack(ctx, incidentID, actor, now)
+	if err != nil || !acked {
+		return false, err
+	}
+	return true, s.addActorTimeline(ctx, incidentID, now, aiops.TimelineAcknowledged, actor, withNote("事件已确认", note))
+}
+
+// Assign 指派事件处理人
+func (s *Store) Assign(ctx context.Context, incidentID, assignee, actor, note string, now time.Time) error {
+	if err := s.repo.Assign(ctx, incidentID, assignee); err != nil {
+		return err
+	}
+	return s.addActorTimeline(ctx, incidentID, now, aiops.TimelineAssigned, actor, withNote("指派给 "+assignee, note))
+}
+
+// Comment 添加评论
+func (s *Store) Comment(ctx context.Context, incidentID, actor, comment string, now time.Time) error {
+	return s.addActorTimeline(ctx, incidentID, now, aiops.TimelineComment, actor, comment)
+}
+
+// MarkFalsePositive 标记为误报
+func (s *Store) MarkFalsePositive(ctx context.Context, incidentID, actor, detail string, now time.Time) error {
+	if err := s.repo.MarkFalsePositive(ctx, incidentID); err != nil {
+		return err
+	}
+	return s.addActorTimeline(ctx, incidentID, now, aiops.TimelineFalsePositive, actor, detail)
+}
+
+// ResolveManually 人工关闭事件
+func (s *Store) ResolveManually(ctx context.Context, incidentID, entityKey, actor, detail string, now time.Time) error {
+	if err := s.repo.UpdateState(ctx, incidentID, string(aiops.StateStable), ""); err != nil {
+		return err
+	}
+	if err := s.repo.Resolve(ctx, incidentID, now); err != nil {
+		return err
+	}
+	entry := &database.AIOpsIncidentTimeline{
+		IncidentID: incidentID,
+		Timestamp:  now,
+		EventType:  aiops.TimelineManualResolved,
+		EntityKey:  entityKey,
+		Detail:     detail,
+		Actor:      actor,
+	}
+	return s.repo.AddTimeline(ctx, entry)
+}
+
+// addActorTimeline 添加带执行用户的时间线条目
+func (s *Store) addActorTimeline(ctx context.Context, incidentID string, timestamp time.Time, eventType, actor, detail string) error {
+	return s.repo.AddTimeline(ctx, &database.AIOpsIncidentTimeline{
+		IncidentID: incidentID,
+		Timestamp:  timestamp,
+		EventType:  eventType,
+		Detail:     detail,
+		Actor:      actor,
+	})
+}
+
+// withNote 在时间线描述后追加备注
+func withNote(detail, note string) string {
+	if note == "" {
+		return detail
+	}
+	return detail + ": " + note
+}
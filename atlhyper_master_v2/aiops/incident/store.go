@@ -5,6 +5,7 @@ package incident
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"sync/atomic"
 	"time"
 
@@ -126,11 +127,32 @@ func (s *Store) GetIncident(ctx context.Context, incidentID string) *aiops.Incid
 	entities, _ := s.repo.GetEntities(ctx, incidentID)
 	timeline, _ := s.repo.GetTimeline(ctx, incidentID)
 
-	return &aiops.IncidentDetail{
+	detail := &aiops.IncidentDetail{
 		Incident: toAIOpsIncident(inc),
 		Entities: toAIOpsEntities(entities),
 		Timeline: toAIOpsTimeline(timeline),
 	}
+	detail.ClusterState = clusterStateLinks(&detail.Incident)
+	return detail
+}
+
+// clusterStateLinks 生成事件开始时集群状态的回溯链接
+func clusterStateLinks(inc *aiops.Incident) *aiops.IncidentClusterState {
+	q := url.Values{}
+	q.Set("cluster_id", inc.ClusterID)
+	q.Set("at", inc.StartedAt.UTC().Format(time.RFC3339))
+	overview := "/api/v2/overview?" + q.Encode()
+
+	q.Del("at")
+	q.Set("from", inc.StartedAt.UTC().Format(time.RFC3339))
+	if inc.ResolvedAt != nil {
+		q.Set("to", inc.ResolvedAt.UTC().Format(time.RFC3339))
+	}
+	return &aiops.IncidentClusterState{
+		At:          inc.StartedAt,
+		OverviewURL: overview,
+		DiffURL:     "/api/v2/snapshots/diff?" + q.Encode(),
+	}
 }
 
 // GetIncidents 查询事件列表
@@ -180,6 +202,11 @@ func toAIOpsIncident(inc *database.AIOpsIncident) aiops.Incident {
 		Recurrence: inc.Recurrence,
 		Summary:    inc.Summary,
 		CreatedAt:  inc.CreatedAt,
+
+		AckedBy:       inc.AckedBy,
+		AckedAt:       inc.AckedAt,
+		Assignee:      inc.Assignee,
+		FalsePositive: inc.FalsePositive,
 	}
 }
 
@@ -208,6 +235,7 @@ func toAIOpsTimeline(timeline []*database.AIOpsIncidentTimeline) []*aiops.Incide
 			EventType:  t.EventType,
 			EntityKey:  t.EntityKey,
 			Detail:     t.Detail,
+			Actor:      t.Actor,
 		}
 	}
 	return result
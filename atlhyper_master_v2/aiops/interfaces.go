@@ -42,9 +42,15 @@ type Engine interface {
 	// GetIncidentPatterns 获取历史事件模式
 	GetIncidentPatterns(ctx context.Context, entityKey string, since time.Time) []*IncidentPattern
 
+	// ApplyIncidentAction 执行人工处理动作（确认/指派/评论/解决/误报），返回最新事件详情
+	ApplyIncidentAction(ctx context.Context, incidentID string, action *IncidentAction) (*IncidentDetail, error)
+
 	// SetIncidentNotify 设置事件通知回调（供 AI 后台自动分析）
 	SetIncidentNotify(fn func(incidentID, severity, trigger string))
 
+	// AddTransitionListener 注册状态机转换监听器（供告警通知）
+	AddTransitionListener(fn TransitionListener)
+
 	// Start 启动引擎（加载 DB 状态 + 定时 flush + Recovery 检查）
 	Start(ctx context.Context) error
 
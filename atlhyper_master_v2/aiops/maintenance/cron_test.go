@@ -0,0 +1,69 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	valid := []string{"* * * * *", "0 2 * * SUN", "*/15 9-17 * * 1-5", "0 0 1,15 * *", "30 3 * JAN-MAR 7", "5/20 * * * *", "@daily"}
+	for _, expr := range valid {
+		if _, err := ParseCron(expr); err != nil {
+			t.Errorf("ParseCron(%q) 应成功: %v", expr, err)
+		}
+	}
+	invalid := []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * FOO *"}
+	for _, expr := range invalid {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) 应失败", expr)
+		}
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	at := func(s string) time.Time {
+		v, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	cases := []struct {
+		expr string
+		at   string
+		want bool
+	}{
+		{"0 2 * * SUN", "2026-03-01 02:00", true}, // 周日
+		{"0 2 * * SUN", "2026-03-02 02:00", false},
+		{"0 2 * * 7", "2026-03-01 02:00", true},
+		{"*/15 9-17 * * 1-5", "2026-03-02 09:45", true},
+		{"*/15 9-17 * * 1-5", "2026-03-02 09:50", false},
+		{"*/15 9-17 * * 1-5", "2026-03-02 18:00", false},
+		// 日与周同时限定: 满足其一
+		{"0 0 13 * FRI", "2026-03-13 00:00", true},
+		{"0 0 13 * FRI", "2026-03-06 00:00", true},
+		{"0 0 13 * FRI", "2026-03-07 00:00", false},
+		{"5/20 * * * *", "2026-03-02 10:45", true},
+	}
+	for _, c := range cases {
+		s, err := ParseCron(c.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.Matches(at(c.at)); got != c.want {
+			t.Errorf("%q at %s = %v, want %v", c.expr, c.at, got, c.want)
+		}
+	}
+}
+
+func TestScheduleLast(t *testing.T) {
+	s, _ := ParseCron("0 2 * * *")
+	now := time.Date(2026, 3, 2, 3, 30, 15, 0, time.UTC)
+	last, ok := s.Last(now, 2*time.Hour)
+	if !ok || !last.Equal(time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Last = %v %v", last, ok)
+	}
+	if _, ok := s.Last(now, time.Hour); ok {
+		t.Error("超出 lookback 不应返回触发时刻")
+	}
+}
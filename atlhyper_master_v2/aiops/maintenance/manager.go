@@ -0,0 +1,242 @@
+// atlhyper_master_v2/aiops/maintenance/manager.go
+// 维护窗口管理器: 持久化 + 内存判定
+//
+// 未结束的窗口常驻内存，写入成功后整体重新加载；状态机与告警触发器在每次判定时调用 Covers。
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/common/logger"
+)
+
+var log = logger.Module("AIOps-Maintenance")
+
+// Manager 维护窗口管理器
+type Manager struct {
+	repo database.AIOpsMaintenanceRepository
+	open atomic.Pointer[[]*Window] // 加载时未结束的窗口
+
+	mu  sync.Mutex // 串行化写入
+	now func() time.Time
+}
+
+// NewManager 创建维护窗口管理器，须调用 Load 加载已保存的窗口
+func NewManager(repo database.AIOpsMaintenanceRepository) *Manager {
+	m := &Manager{repo: repo, now: time.Now}
+	m.open.Store(&[]*Window{})
+	return m
+}
+
+// SetClock 设置时钟（测试用）
+func (m *Manager) SetClock(now func() time.Time) {
+	m.now = now
+}
+
+// Load 从数据库重新加载未结束的窗口
+// 单个窗口无法解析（如时区不可用）时跳过并记录日志
+func (m *Manager) Load(ctx context.Context) error {
+	rows, err := m.repo.ListOpen(ctx, m.now())
+	if err != nil {
+		return fmt.Errorf("load maintenance windows: %w", err)
+	}
+	windows := make([]*Window, 0, len(rows))
+	for _, row := range rows {
+		w := fromRow(row)
+		if err := w.Validate(); err != nil {
+			log.Error("维护窗口无法解析，已跳过", "id", row.ID, "name", row.Name, "err", err)
+			continue
+		}
+		windows = append(windows, w)
+	}
+	m.open.Store(&windows)
+	log.Info("维护窗口已加载", "windows", len(windows))
+	return nil
+}
+
+// List 全部窗口（含已结束，按开始时间倒序），标注当前是否生效
+func (m *Manager) List(ctx context.Context) ([]*Window, error) {
+	rows, err := m.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := m.now()
+	windows := make([]*Window, 0, len(rows))
+	for _, row := range rows {
+		w := fromRow(row)
+		if w.Validate() == nil {
+			w.Active = w.ActiveAt(now)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// Get 查询窗口
+func (m *Manager) Get(ctx context.Context, id int64) (*Window, error) {
+	row, err := m.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, ErrWindowNotFound
+	}
+	w := fromRow(row)
+	if w.Validate() == nil {
+		w.Active = w.ActiveAt(m.now())
+	}
+	return w, nil
+}
+
+// Create 创建窗口（startsAt 为空表示立即开始，source 为空为 manual）
+func (m *Manager) Create(ctx context.Context, w *Window, by string) (*Window, error) {
+	now := m.now()
+	created := *w
+	created.ID = 0
+	if created.StartsAt.IsZero() {
+		created.StartsAt = now
+	}
+	if created.Source == "" {
+		created.Source = SourceManual
+	}
+	if err := created.Validate(); err != nil {
+		return nil, err
+	}
+	created.CreatedBy = by
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	row := toRow(&created)
+	if err := m.repo.Create(ctx, row); err != nil {
+		return nil, fmt.Errorf("create maintenance window: %w", err)
+	}
+	created.ID = row.ID
+	created.Active = created.ActiveAt(now)
+	if err := m.Load(ctx); err != nil {
+		return nil, err
+	}
+	log.Info("维护窗口已创建", "id", created.ID, "name", created.Name, "cluster", created.ClusterID,
+		"source", created.Source, "by", by)
+	return &created, nil
+}
+
+// Update 修改窗口的作用范围与时间（来源与创建信息保持不变）
+func (m *Manager) Update(ctx context.Context, w *Window, by string) (*Window, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	row, err := m.repo.GetByID(ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, ErrWindowNotFound
+	}
+	updated := *w
+	if updated.StartsAt.IsZero() {
+		updated.StartsAt = row.StartsAt
+	}
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+	updated.Source = row.Source
+	updated.CreatedBy = row.CreatedBy
+	updated.CreatedAt = row.CreatedAt
+	updated.UpdatedAt = m.now()
+
+	if err := m.repo.Update(ctx, toRow(&updated)); err != nil {
+		return nil, fmt.Errorf("update maintenance window: %w", err)
+	}
+	updated.Active = updated.ActiveAt(updated.UpdatedAt)
+	if err := m.Load(ctx); err != nil {
+		return nil, err
+	}
+	log.Info("维护窗口已修改", "id", updated.ID, "name", updated.Name, "by", by)
+	return &updated, nil
+}
+
+// Close 在 at 时刻结束窗口（周期窗口不再重复；已在 at 之前结束的窗口不变，保留记录）
+func (m *Manager) Close(ctx context.Context, id int64, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	row, err := m.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if row == nil {
+		return ErrWindowNotFound
+	}
+	if row.EndsAt != nil && !row.EndsAt.After(at) {
+		return nil
+	}
+	row.EndsAt = &at
+	row.UpdatedAt = m.now()
+	if err := m.repo.Update(ctx, row); err != nil {
+		return fmt.Errorf("close maintenance window: %w", err)
+	}
+	log.Info("维护窗口已结束", "id", id, "name", row.Name, "at", at)
+	return m.Load(ctx)
+}
+
+// Covers 返回在 at 时刻覆盖目标的窗口（没有返回 nil）
+func (m *Manager) Covers(t Target, at time.Time) *Window {
+	for _, w := range *m.open.Load() {
+		if w.Covers(t) && w.ActiveAt(at) {
+			return w
+		}
+	}
+	return nil
+}
+
+// ==================== 存储转换 ====================
+
+func fromRow(row *database.AIOpsMaintenanceWindow) *Window {
+	return &Window{
+		ID:              row.ID,
+		Name:            row.Name,
+		ClusterID:       row.ClusterID,
+		Namespace:       row.Namespace,
+		Node:            row.Node,
+		Workload:        row.Workload,
+		StartsAt:        row.StartsAt,
+		EndsAt:          row.EndsAt,
+		Cron:            row.Cron,
+		DurationMinutes: row.DurationMinutes,
+		Timezone:        row.Timezone,
+		Source:          row.Source,
+		Reason:          row.Reason,
+		CreatedBy:       row.CreatedBy,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+}
+
+func toRow(w *Window) *database.AIOpsMaintenanceWindow {
+	return &database.AIOpsMaintenanceWindow{
+		ID:              w.ID,
+		Name:            w.Name,
+		ClusterID:       w.ClusterID,
+		Namespace:       w.Namespace,
+		Node:            w.Node,
+		Workload:        w.Workload,
+		StartsAt:        w.StartsAt,
+		EndsAt:          w.EndsAt,
+		Cron:            w.Cron,
+		DurationMinutes: w.DurationMinutes,
+		Timezone:        w.Timezone,
+		Source:          w.Source,
+		Reason:          w.Reason,
+		CreatedBy:       w.CreatedBy,
+		CreatedAt:       w.CreatedAt,
+		UpdatedAt:       w.UpdatedAt,
+	}
+}
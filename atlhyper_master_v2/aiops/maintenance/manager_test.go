@@ -0,0 +1,271 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// ==================== Mock ====================
+
+type memWindowRepo struct {
+	mu     sync.Mutex
+	nextID int64
+	rows   map[int64]*database.AIOpsMaintenanceWindow
+}
+
+func newMemWindowRepo() *memWindowRepo {
+	return &memWindowRepo{rows: make(map[int64]*database.AIOpsMaintenanceWindow)}
+}
+
+func (r *memWindowRepo) Create(ctx context.Context, w *database.AIOpsMaintenanceWindow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	w.ID = r.nextID
+	cp := *w
+	r.rows[w.ID] = &cp
+	return nil
+}
+
+func (r *memWindowRepo) Update(ctx context.Context, w *database.AIOpsMaintenanceWindow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *w
+	r.rows[w.ID] = &cp
+	return nil
+}
+
+func (r *memWindowRepo) GetByID(ctx context.Context, id int64) (*database.AIOpsMaintenanceWindow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rows[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *row
+	return &cp, nil
+}
+
+func (r *memWindowRepo) List(ctx context.Context) ([]*database.AIOpsMaintenanceWindow, error) {
+	return r.ListOpen(ctx, time.Time{})
+}
+
+func (r *memWindowRepo) ListOpen(ctx context.Context, now time.Time) ([]*database.AIOpsMaintenanceWindow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*database.AIOpsMaintenanceWindow
+	for _, row := range r.rows {
+		if now.IsZero() || row.EndsAt == nil || row.EndsAt.After(now) {
+			cp := *row
+			list = append(list, &cp)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// ==================== Tests ====================
+
+func newTestManager(t *testing.T, now *time.Time) *Manager {
+	t.Helper()
+	m := NewManager(newMemWindowRepo())
+	m.SetClock(func() time.Time { return *now })
+	return m
+}
+
+func TestCoversScope(t *testing.T) {
+	pod := Target{ClusterID: "prod", Namespace: "batch-etl", Kind: "pod", Name: "loader-7d9f8c6b5-x2k4p", Node: "worker-3"}
+	cases := []struct {
+		name string
+		w    Window
+		t    Target
+		want bool
+	}{
+		{"集群", Window{ClusterID: "prod"}, pod, true},
+		{"其他集群", Window{ClusterID: "staging"}, pod, false},
+		{"命名空间通配", Window{ClusterID: "prod", Namespace: "batch-*"}, pod, true},
+		{"命名空间不匹配", Window{ClusterID: "prod", Namespace: "default"}, pod, false},
+		{"命名空间不含集群级实体", Window{ClusterID: "prod", Namespace: "batch-*"}, EntityTarget("prod", "_cluster/node/worker-3"), false},
+		{"节点上的 Pod", Window{ClusterID: "prod", Node: "worker-3"}, pod, true},
+		{"节点本身", Window{ClusterID: "prod", Node: "worker-3"}, EntityTarget("prod", "_cluster/node/worker-3"), true},
+		{"其他节点", Window{ClusterID: "prod", Node: "worker-1"}, pod, false},
+		{"工作负载 Pod", Window{ClusterID: "prod", Namespace: "batch-etl", Workload: "loader"}, pod, true},
+		{"工作负载 ReplicaSet 事件", Window{ClusterID: "prod", Namespace: "batch-etl", Workload: "loader"},
+			Target{ClusterID: "prod", Namespace: "batch-etl", Kind: "ReplicaSet", Name: "loader-7d9f8c6b5"}, true},
+		{"工作负载 Deployment 事件", Window{ClusterID: "prod", Namespace: "batch-etl", Workload: "loader"},
+			Target{ClusterID: "prod", Namespace: "batch-etl", Kind: "Deployment", Name: "loader"}, true},
+		{"同前缀的其他工作负载", Window{ClusterID: "prod", Namespace: "batch-etl", Workload: "loader"},
+			EntityTarget("prod", "batch-etl/pod/loader-api-7d9f8c6b5-x2k4p"), false},
+		{"集群级告警只被整集群窗口覆盖", Window{ClusterID: "prod", Namespace: "batch-*"}, Target{ClusterID: "prod"}, false},
+	}
+	for _, c := range cases {
+		if got := c.w.Covers(c.t); got != c.want {
+			t.Errorf("%s: Covers = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	start := time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	cases := []struct {
+		name string
+		w    Window
+		ok   bool
+	}{
+		{"一次性", Window{Name: "a", ClusterID: "prod", StartsAt: start, EndsAt: &end}, true},
+		{"一次性按时长", Window{Name: "a", ClusterID: "prod", StartsAt: start, DurationMinutes: 30}, true},
+		{"一次性缺结束时间", Window{Name: "a", ClusterID: "prod", StartsAt: start}, false},
+		{"周期", Window{Name: "a", ClusterID: "prod", StartsAt: start, Cron: "0 2 * * SUN", DurationMinutes: 120, Timezone: "Asia/Shanghai"}, true},
+		{"周期缺时长", Window{Name: "a", ClusterID: "prod", StartsAt: start, Cron: "0 2 * * SUN"}, false},
+		{"周期时长过长", Window{Name: "a", ClusterID: "prod", StartsAt: start, Cron: "0 2 * * SUN", DurationMinutes: 2000}, false},
+		{"cron 无效", Window{Name: "a", ClusterID: "prod", StartsAt: start, Cron: "0 2 *", DurationMinutes: 60}, false},
+		{"时区无效", Window{Name: "a", ClusterID: "prod", StartsAt: start, Cron: "0 2 * * *", DurationMinutes: 60, Timezone: "Mars/Base"}, false},
+		{"缺集群", Window{Name: "a", StartsAt: start, EndsAt: &end}, false},
+		{"缺名称", Window{ClusterID: "prod", StartsAt: start, EndsAt: &end}, false},
+		{"节点与命名空间互斥", Window{Name: "a", ClusterID: "prod", Node: "n1", Namespace: "x", StartsAt: start, EndsAt: &end}, false},
+		{"工作负载缺命名空间", Window{Name: "a", ClusterID: "prod", Workload: "api", StartsAt: start, EndsAt: &end}, false},
+	}
+	for _, c := range cases {
+		err := c.w.Validate()
+		if (err == nil) != c.ok {
+			t.Errorf("%s: Validate() = %v, want ok=%v", c.name, err, c.ok)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidWindow) {
+			t.Errorf("%s: 应包装 ErrInvalidWindow", c.name)
+		}
+	}
+}
+
+func TestRecurringWindow(t *testing.T) {
+	// 每周日 02:00（上海时间）开始，持续 2 小时
+	w := Window{Name: "weekly", ClusterID: "prod", StartsAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Cron: "0 2 * * SUN", DurationMinutes: 120, Timezone: "Asia/Shanghai"}
+	if err := w.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	sh, _ := time.LoadLocation("Asia/Shanghai")
+	cases := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2026, 3, 1, 1, 59, 0, 0, sh), false},
+		{time.Date(2026, 3, 1, 2, 0, 0, 0, sh), true},
+		{time.Date(2026, 3, 1, 3, 59, 59, 0, sh), true},
+		{time.Date(2026, 3, 1, 4, 0, 0, 0, sh), false},
+		{time.Date(2026, 3, 2, 2, 30, 0, 0, sh), false},   // 周一
+		{time.Date(2025, 12, 28, 2, 30, 0, 0, sh), false}, // startsAt 之前
+	}
+	for _, c := range cases {
+		if got := w.ActiveAt(c.at); got != c.want {
+			t.Errorf("ActiveAt(%s) = %v, want %v", c.at, got, c.want)
+		}
+	}
+
+	// 截止时间后不再重复
+	until := time.Date(2026, 3, 1, 0, 0, 0, 0, sh)
+	w.EndsAt = &until
+	if w.ActiveAt(time.Date(2026, 3, 1, 2, 30, 0, 0, sh)) {
+		t.Error("截止时间后不应生效")
+	}
+}
+
+func TestManagerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	m := newTestManager(t, &now)
+	if err := m.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	target := EntityTarget("prod", "shop/pod/api-7d9f8c6b5-x2k4p")
+	if m.Covers(target, now) != nil {
+		t.Fatal("无窗口时不应覆盖")
+	}
+
+	// 立即开始，持续 30 分钟
+	w, err := m.Create(ctx, &Window{Name: "upgrade api", ClusterID: "prod", Namespace: "shop", DurationMinutes: 30}, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.ID == 0 || w.Source != SourceManual || w.CreatedBy != "alice" || !w.Active {
+		t.Fatalf("创建结果不正确: %+v", w)
+	}
+	if got := m.Covers(target, now); got == nil || got.ID != w.ID {
+		t.Fatal("窗口内应覆盖命名空间下的实体")
+	}
+	if m.Covers(EntityTarget("prod", "other/pod/x"), now) != nil {
+		t.Error("不应覆盖其他命名空间")
+	}
+	if m.Covers(target, now.Add(31*time.Minute)) != nil {
+		t.Error("窗口结束后不应覆盖")
+	}
+
+	// 修改为节点范围（来源与创建人保持不变）
+	w.Namespace = ""
+	w.Node = "worker-3"
+	updated, err := m.Update(ctx, w, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Source != SourceManual || updated.CreatedBy != "alice" {
+		t.Errorf("修改不应改变来源与创建人: %+v", updated)
+	}
+	if m.Covers(target, now) != nil {
+		t.Error("修改后不应再覆盖命名空间")
+	}
+	target.Node = "worker-3"
+	if m.Covers(target, now) == nil {
+		t.Error("修改后应覆盖节点上的 Pod")
+	}
+
+	// 提前结束，保留记录
+	now = now.Add(5 * time.Minute)
+	if err := m.Close(ctx, w.ID, now); err != nil {
+		t.Fatal(err)
+	}
+	if m.Covers(target, now) != nil {
+		t.Error("结束后不应覆盖")
+	}
+	list, err := m.List(ctx)
+	if err != nil || len(list) != 1 || list[0].Active || list[0].EndsAt == nil || !list[0].EndsAt.Equal(now) {
+		t.Fatalf("结束后记录应保留且不生效: %+v %v", list, err)
+	}
+
+	if _, err := m.Update(ctx, &Window{ID: 99, Name: "x", ClusterID: "prod", DurationMinutes: 5}, "bob"); !errors.Is(err, ErrWindowNotFound) {
+		t.Errorf("修改不存在的窗口应返回 ErrWindowNotFound, got %v", err)
+	}
+	if err := m.Close(ctx, 99, now); !errors.Is(err, ErrWindowNotFound) {
+		t.Errorf("结束不存在的窗口应返回 ErrWindowNotFound, got %v", err)
+	}
+}
+
+func TestManagerReload(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	repo := newMemWindowRepo()
+	m := NewManager(repo)
+	m.SetClock(func() time.Time { return now })
+	if _, err := m.Create(ctx, &Window{Name: "nightly", ClusterID: "prod", Cron: "0 * * * *", DurationMinutes: 15}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 重启后重新加载
+	m2 := NewManager(repo)
+	m2.SetClock(func() time.Time { return now })
+	if err := m2.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if m2.Covers(Target{ClusterID: "prod"}, now.Add(10*time.Minute)) == nil {
+		t.Error("重启后周期窗口应生效")
+	}
+	if m2.Covers(Target{ClusterID: "prod"}, now.Add(20*time.Minute)) != nil {
+		t.Error("周期窗口单次结束后不应生效")
+	}
+}
@@ -0,0 +1,221 @@
+// atlhyper_master_v2/aiops/maintenance/types.go
+// 维护窗口定义、校验与作用范围匹配
+//
+// 窗口为一次性（startsAt ~ endsAt）或周期性（cron 指定每次开始时间 + durationMinutes），
+// 作用范围为集群，可进一步限定命名空间、节点或工作负载:
+//
+//	{"name": "node reboot", "clusterId": "prod", "node": "worker-3",
+//	 "startsAt": "2026-03-02T02:00:00Z", "endsAt": "2026-03-02T03:00:00Z"}
+//
+//	{"name": "weekly batch upgrade", "clusterId": "prod", "namespace": "batch-*",
+//	 "cron": "0 2 * * SUN", "durationMinutes": 120, "timezone": "Asia/Shanghai"}
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+)
+
+// 维护窗口错误
+var (
+	ErrWindowNotFound = errors.New("maintenance window not found")
+	ErrInvalidWindow  = errors.New("invalid maintenance window")
+)
+
+// 窗口来源
+const (
+	SourceManual   = "manual"   // API 创建
+	SourceDeployer = "deployer" // Deployer 部署期间自动创建
+)
+
+// maxRecurringMinutes 周期窗口单次最长持续时长
+const maxRecurringMinutes = 24 * 60
+
+// Window 维护窗口
+type Window struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+
+	// 作用范围: 集群必填；节点与命名空间 / 工作负载互斥
+	ClusterID string `json:"clusterId"`
+	Namespace string `json:"namespace,omitempty"` // 支持通配（如 "batch-*"）
+	Node      string `json:"node,omitempty"`      // 节点本身及其上运行的 Pod
+	Workload  string `json:"workload,omitempty"`  // 工作负载名称（Deployment / StatefulSet 等，须指定命名空间）
+
+	StartsAt        time.Time  `json:"startsAt"`
+	EndsAt          *time.Time `json:"endsAt,omitempty"`          // 一次性窗口必填；周期窗口为重复截止时间（空 = 不截止）
+	Cron            string     `json:"cron,omitempty"`            // 周期窗口每次开始时间，空 = 一次性窗口
+	DurationMinutes int        `json:"durationMinutes,omitempty"` // 周期窗口每次持续时长；一次性窗口未给 endsAt 时据此计算
+	Timezone        string     `json:"timezone,omitempty"`        // cron 时区（空 = UTC）
+
+	Source    string    `json:"source"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	Active bool `json:"active"` // 查询时刻是否生效（仅 API 返回）
+
+	rt *runtime // 校验后生成
+}
+
+// runtime 周期窗口的解析结果与最近一次触发时刻缓存
+type runtime struct {
+	sched *Schedule
+	loc   *time.Location
+
+	mu     sync.Mutex
+	minute time.Time // 缓存对应的分钟
+	start  time.Time // 该分钟所在的窗口开始时刻（零值 = 不在窗口内）
+}
+
+// Target 待判定的对象（AIOps 实体或告警对象）
+type Target struct {
+	ClusterID string
+	Namespace string // 集群级资源为空
+	Kind      string // pod / service / node / Deployment ...（不区分大小写）
+	Name      string
+	Node      string // Pod 所在节点（未知为空）
+}
+
+// EntityTarget 由 AIOps entityKey（"namespace/type/name"）构造判定对象
+func EntityTarget(clusterID, entityKey string) Target {
+	t := Target{ClusterID: clusterID}
+	parts := strings.SplitN(entityKey, "/", 3)
+	if len(parts) != 3 {
+		return t
+	}
+	if parts[0] != "_cluster" {
+		t.Namespace = parts[0]
+	}
+	t.Kind, t.Name = parts[1], parts[2]
+	return t
+}
+
+// Validate 校验窗口并补全派生字段（一次性窗口按 durationMinutes 计算 endsAt）
+func (w *Window) Validate() error {
+	if err := w.validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWindow, err)
+	}
+	return nil
+}
+
+func (w *Window) validate() error {
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Name == "" || len(w.Name) > 128 {
+		return errors.New("name is required (at most 128 characters)")
+	}
+	if w.ClusterID == "" {
+		return errors.New("clusterId is required")
+	}
+	if w.Node != "" && (w.Namespace != "" || w.Workload != "") {
+		return errors.New("node cannot be combined with namespace or workload")
+	}
+	if w.Workload != "" && w.Namespace == "" {
+		return errors.New("workload requires namespace")
+	}
+	if w.Namespace != "" {
+		if _, err := path.Match(w.Namespace, ""); err != nil {
+			return fmt.Errorf("namespace pattern %q: %v", w.Namespace, err)
+		}
+	}
+	if w.StartsAt.IsZero() {
+		return errors.New("startsAt is required")
+	}
+
+	if w.Cron == "" {
+		if w.EndsAt == nil && w.DurationMinutes > 0 {
+			end := w.StartsAt.Add(time.Duration(w.DurationMinutes) * time.Minute)
+			w.EndsAt = &end
+		}
+		if w.EndsAt == nil || !w.EndsAt.After(w.StartsAt) {
+			return errors.New("one-off window requires endsAt (or durationMinutes) after startsAt")
+		}
+		w.rt = nil
+		return nil
+	}
+
+	sched, err := ParseCron(w.Cron)
+	if err != nil {
+		return err
+	}
+	if w.DurationMinutes <= 0 || w.DurationMinutes > maxRecurringMinutes {
+		return fmt.Errorf("recurring window requires durationMinutes in [1, %d]", maxRecurringMinutes)
+	}
+	loc := time.UTC
+	if w.Timezone != "" {
+		if loc, err = time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("timezone %q: %v", w.Timezone, err)
+		}
+	}
+	if w.EndsAt != nil && !w.EndsAt.After(w.StartsAt) {
+		return errors.New("endsAt must be after startsAt")
+	}
+	w.rt = &runtime{sched: sched, loc: loc}
+	return nil
+}
+
+// ActiveAt 判断窗口在 at 时刻是否生效
+func (w *Window) ActiveAt(at time.Time) bool {
+	if at.Before(w.StartsAt) || (w.EndsAt != nil && !at.Before(*w.EndsAt)) {
+		return false
+	}
+	if w.Cron == "" {
+		return true
+	}
+	if w.rt == nil {
+		return false
+	}
+	start := w.rt.lastStart(at, time.Duration(w.DurationMinutes)*time.Minute)
+	return !start.IsZero() && at.Before(start.Add(time.Duration(w.DurationMinutes)*time.Minute))
+}
+
+// lastStart 在 at 之前 duration 内最近一次开始时刻（按分钟缓存，同一分钟内大量实体判定只计算一次）
+func (r *runtime) lastStart(at time.Time, duration time.Duration) time.Time {
+	minute := at.Truncate(time.Minute)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !minute.Equal(r.minute) {
+		r.minute = minute
+		r.start, _ = r.sched.Last(at.In(r.loc), duration)
+	}
+	return r.start
+}
+
+// Covers 判断窗口作用范围是否包含目标（不判断时间）
+func (w *Window) Covers(t Target) bool {
+	if w.ClusterID != t.ClusterID {
+		return false
+	}
+	if w.Node != "" {
+		return (strings.EqualFold(t.Kind, "node") && t.Name == w.Node) || t.Node == w.Node
+	}
+	if w.Namespace != "" {
+		if t.Namespace == "" {
+			return false
+		}
+		if ok, _ := path.Match(w.Namespace, t.Namespace); !ok {
+			return false
+		}
+	}
+	if w.Workload != "" {
+		return t.Name != "" && workloadOf(t.Kind, t.Name) == w.Workload
+	}
+	return true
+}
+
+// workloadOf 对象所属工作负载名称（Pod / ReplicaSet 去掉控制器生成的后缀）
+func workloadOf(kind, name string) string {
+	switch strings.ToLower(kind) {
+	case "pod", "replicaset":
+		pattern := aiops.EntityPattern("_/pod/" + name)
+		return strings.TrimSuffix(strings.TrimPrefix(pattern, "_/pod/"), "-*")
+	}
+	return name
+}
@@ -0,0 +1,332 @@
+// atlhyper_master_v2/aiops/policy/manager.go
+// 策略管理器: 版本化存储 + 热加载
+//
+// 每次保存 / 删除 / 回滚插入新版本（历史版本不修改），写入成功后重新加载并整体替换生效策略。
+// 引擎通过 RiskConfig / Thresholds / BaselineMode / Detector 在每次评估时解析，
+// 因此策略变更在下一个快照即生效，基线、风险与状态机的内存状态均保留。
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/aiops/risk"
+	"AtlHyper/atlhyper_master_v2/aiops/statemachine"
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/common/logger"
+)
+
+var log = logger.Module("AIOps-Policy")
+
+// Manager 策略管理器
+type Manager struct {
+	repo database.AIOpsPolicyRepository // nil = 静态策略（离线回放）
+	base *risk.RiskConfig
+	set  atomic.Pointer[compiled]
+
+	mu  sync.Mutex // 串行化写入（版本号分配）
+	now func() time.Time
+}
+
+// NewManager 创建策略管理器（base 为 nil 时使用 risk.DefaultRiskConfig），须调用 Load 加载已保存的策略
+func NewManager(repo database.AIOpsPolicyRepository, base *risk.RiskConfig) *Manager {
+	if base == nil {
+		base = risk.DefaultRiskConfig()
+	}
+	m := &Manager{repo: repo, base: base, now: time.Now}
+	m.set.Store(compile(nil, base))
+	return m
+}
+
+// NewStatic 由给定策略创建只读管理器（离线回放 -policies 使用）
+func NewStatic(policies []*Policy, base *risk.RiskConfig) (*Manager, error) {
+	seen := make(map[string]bool, len(policies))
+	for _, p := range policies {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("policy %q: %w", p.Name, err)
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("%w: duplicate policy name %q", ErrInvalidPolicy, p.Name)
+		}
+		seen[p.Name] = true
+	}
+	m := NewManager(nil, base)
+	c := compile(policies, m.base)
+	if err := c.checkLayered(); err != nil {
+		return nil, err
+	}
+	m.set.Store(c)
+	return m, nil
+}
+
+// LoadFile 读取策略文件（JSON 数组，格式同 API）
+func LoadFile(path string) ([]*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var policies []*Policy
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, fmt.Errorf("parse policies %s: %w", path, err)
+	}
+	return policies, nil
+}
+
+// Load 从数据库重新加载当前版本并替换生效策略
+// 单个策略内容损坏时跳过并记录日志，不影响其他策略
+func (m *Manager) Load(ctx context.Context) error {
+	if m.repo == nil {
+		return nil
+	}
+	rows, err := m.repo.ListCurrent(ctx)
+	if err != nil {
+		return fmt.Errorf("load aiops policies: %w", err)
+	}
+	policies := make([]*Policy, 0, len(rows))
+	for _, row := range rows {
+		p, err := fromRow(row)
+		if err != nil {
+			log.Error("策略内容无法解析，已跳过", "policy", row.Name, "version", row.Version, "err", err)
+			continue
+		}
+		policies = append(policies, p)
+	}
+	m.set.Store(compile(policies, m.base))
+	log.Info("AIOps 策略已加载", "policies", len(policies))
+	return nil
+}
+
+// Policies 当前版本的全部策略（含已停用，按名称排序）
+func (m *Manager) Policies() []*Policy {
+	return m.set.Load().policies
+}
+
+// History 策略全部版本（版本号降序，含删除标记版本）
+func (m *Manager) History(ctx context.Context, name string) ([]*Policy, error) {
+	if m.repo == nil {
+		return nil, ErrPolicyNotFound
+	}
+	rows, err := m.repo.ListVersions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrPolicyNotFound
+	}
+	versions := make([]*Policy, 0, len(rows))
+	for _, row := range rows {
+		p, err := fromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, p)
+	}
+	return versions, nil
+}
+
+// Save 保存策略为新版本并热加载
+// p.Version 为基于的版本（乐观锁）: 0 = 不检查，否则须等于当前最新版本，不一致返回 ErrVersionConflict
+func (m *Manager) Save(ctx context.Context, p *Policy, by string) (*Policy, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	latest, err := m.latest(ctx, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	if p.Version > 0 && (latest == nil || latest.Version != p.Version) {
+		return nil, ErrVersionConflict
+	}
+	next := *p
+	next.Deleted = false
+	if err := m.checkReplace(p.Name, &next); err != nil {
+		return nil, err
+	}
+	return m.insert(ctx, &next, latest, by)
+}
+
+// Delete 删除策略（插入删除标记版本，可回滚恢复）
+func (m *Manager) Delete(ctx context.Context, name, by string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	latest, err := m.latest(ctx, name)
+	if err != nil {
+		return err
+	}
+	if latest == nil || latest.Deleted {
+		return ErrPolicyNotFound
+	}
+	if err := m.checkReplace(name, nil); err != nil {
+		return err
+	}
+	tombstone := *latest
+	tombstone.Deleted = true
+	tombstone.Comment = "deleted"
+	_, err = m.insert(ctx, &tombstone, latest, by)
+	return err
+}
+
+// Rollback 以指定历史版本的内容创建新版本
+func (m *Manager) Rollback(ctx context.Context, name string, version int, by string) (*Policy, error) {
+	if m.repo == nil {
+		return nil, ErrPolicyNotFound
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	row, err := m.repo.GetVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil || row.Deleted {
+		return nil, ErrPolicyNotFound
+	}
+	target, err := fromRow(row)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := m.latest(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkReplace(name, target); err != nil {
+		return nil, err
+	}
+	target.Comment = fmt.Sprintf("rollback to v%d", version)
+	return m.insert(ctx, target, latest, by)
+}
+
+// checkReplace 校验以 next 替换（nil = 删除）指定策略后，各范围叠加的阈值仍然一致
+func (m *Manager) checkReplace(name string, next *Policy) error {
+	current := m.Policies()
+	policies := make([]*Policy, 0, len(current)+1)
+	for _, p := range current {
+		if p.Name != name {
+			policies = append(policies, p)
+		}
+	}
+	if next != nil {
+		policies = append(policies, next)
+	}
+	return compile(policies, m.base).checkLayered()
+}
+
+// latest 查询最新版本（含删除标记，不存在返回 nil）
+func (m *Manager) latest(ctx context.Context, name string) (*Policy, error) {
+	if m.repo == nil {
+		return nil, ErrPolicyNotFound
+	}
+	row, err := m.repo.GetVersion(ctx, name, 0)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	return fromRow(row)
+}
+
+// insert 写入新版本并重新加载（调用方持有 m.mu）
+func (m *Manager) insert(ctx context.Context, p *Policy, latest *Policy, by string) (*Policy, error) {
+	p.Version = 1
+	if latest != nil {
+		p.Version = latest.Version + 1
+	}
+	p.Author = by
+	p.CreatedAt = m.now()
+
+	row, err := toRow(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.repo.Create(ctx, row); err != nil {
+		return nil, fmt.Errorf("save aiops policy: %w", err)
+	}
+	if err := m.Load(ctx); err != nil {
+		return nil, err
+	}
+	log.Info("AIOps 策略已更新", "policy", p.Name, "version", p.Version, "deleted", p.Deleted, "by", by)
+	return p, nil
+}
+
+// ==================== 引擎解析接口 ====================
+
+// RiskConfig 解析风险配置（实现 risk.ConfigResolver，entityKey 为空为集群级）
+func (m *Manager) RiskConfig(clusterID, entityKey string) *risk.RiskConfig {
+	return m.set.Load().resolve(entityScope(clusterID, entityKey)).risk
+}
+
+// Thresholds 解析状态转换阈值（实现 statemachine.ThresholdResolver）
+func (m *Manager) Thresholds(clusterID, entityKey string) statemachine.Thresholds {
+	return m.set.Load().resolve(entityScope(clusterID, entityKey)).thresholds
+}
+
+// BaselineMode 解析指标基线模式（基线状态不区分集群，仅应用未限定集群的策略）
+func (m *Manager) BaselineMode(entityKey, metricName string) aiops.BaselineMode {
+	return m.RiskConfig("", entityKey).GetBaselineMode(aiops.ExtractEntityType(entityKey), metricName)
+}
+
+// Detector 解析指标检测器（同 BaselineMode，仅应用未限定集群的策略）
+func (m *Manager) Detector(entityKey, metricName string) string {
+	return m.RiskConfig("", entityKey).GetDetector(aiops.ExtractEntityType(entityKey), metricName)
+}
+
+// Effective 生效配置预览（entityKey 为空为集群级）
+func (m *Manager) Effective(clusterID, entityKey string) *Effective {
+	s := entityScope(clusterID, entityKey)
+	r := m.set.Load().resolve(s)
+	return &Effective{
+		ClusterID: clusterID,
+		EntityKey: entityKey,
+		Policies:  r.policies,
+		Spec:      r.effective(s),
+	}
+}
+
+// ==================== 存储转换 ====================
+
+func fromRow(row *database.AIOpsPolicy) (*Policy, error) {
+	p := &Policy{
+		Name:       row.Name,
+		Version:    row.Version,
+		ClusterID:  row.ClusterID,
+		Namespace:  row.Namespace,
+		EntityType: row.EntityType,
+		Enabled:    row.Enabled,
+		Deleted:    row.Deleted,
+		Author:     row.Author,
+		Comment:    row.Comment,
+		CreatedAt:  row.CreatedAt,
+	}
+	if err := json.Unmarshal([]byte(row.Spec), &p.Spec); err != nil {
+		return nil, fmt.Errorf("decode policy %q v%d: %w", row.Name, row.Version, err)
+	}
+	return p, nil
+}
+
+func toRow(p *Policy) (*database.AIOpsPolicy, error) {
+	spec, err := json.Marshal(p.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &database.AIOpsPolicy{
+		Name:       p.Name,
+		Version:    p.Version,
+		ClusterID:  p.ClusterID,
+		Namespace:  p.Namespace,
+		EntityType: p.EntityType,
+		Spec:       string(spec),
+		Enabled:    p.Enabled,
+		Deleted:    p.Deleted,
+		Author:     p.Author,
+		Comment:    p.Comment,
+		CreatedAt:  p.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,344 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/aiops/risk"
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// ==================== Mock ====================
+
+type memPolicyRepo struct {
+	mu     sync.Mutex
+	nextID int64
+	rows   []*database.AIOpsPolicy
+}
+
+func (r *memPolicyRepo) Create(ctx context.Context, p *database.AIOpsPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, row := range r.rows {
+		if row.Name == p.Name && row.Version == p.Version {
+			return errors.New("UNIQUE constraint failed")
+		}
+	}
+	r.nextID++
+	p.ID = r.nextID
+	cp := *p
+	r.rows = append(r.rows, &cp)
+	return nil
+}
+
+func (r *memPolicyRepo) ListCurrent(ctx context.Context) ([]*database.AIOpsPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	latest := map[string]*database.AIOpsPolicy{}
+	for _, row := range r.rows {
+		if cur := latest[row.Name]; cur == nil || row.Version > cur.Version {
+			latest[row.Name] = row
+		}
+	}
+	var list []*database.AIOpsPolicy
+	for _, row := range latest {
+		if !row.Deleted {
+			cp := *row
+			list = append(list, &cp)
+		}
+	}
+	return list, nil
+}
+
+func (r *memPolicyRepo) ListVersions(ctx context.Context, name string) ([]*database.AIOpsPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*database.AIOpsPolicy
+	for _, row := range r.rows {
+		if row.Name == name {
+			cp := *row
+			list = append(list, &cp)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version > list[j].Version })
+	return list, nil
+}
+
+func (r *memPolicyRepo) GetVersion(ctx context.Context, name string, version int) (*database.AIOpsPolicy, error) {
+	list, _ := r.ListVersions(ctx, name)
+	for _, row := range list {
+		if version <= 0 || row.Version == version {
+			return row, nil
+		}
+	}
+	return nil, nil
+}
+
+// ==================== Helpers ====================
+
+func float(v float64) *float64 { return &v }
+
+func duration(d time.Duration) *Duration {
+	v := Duration(d)
+	return &v
+}
+
+func boolPtr(v bool) *bool { return &v }
+
+// ==================== Tests ====================
+
+// TestResolvePrecedence 作用范围越具体优先级越高: 全局 < 集群 < 实体类型 < 命名空间
+func TestResolvePrecedence(t *testing.T) {
+	m, err := NewStatic([]*Policy{
+		{Name: "global", Enabled: true, Spec: Spec{StateMachine: &StateMachineSpec{
+			WarningAfter: duration(3 * time.Minute), IncidentRisk: float(0.6),
+		}}},
+		{Name: "prod-cluster", ClusterID: "prod", Enabled: true, Spec: Spec{StateMachine: &StateMachineSpec{
+			WarningAfter: duration(90 * time.Second),
+		}}},
+		{Name: "prod-ns", Namespace: "prod-*", Enabled: true, Spec: Spec{StateMachine: &StateMachineSpec{
+			WarningAfter: duration(time.Minute), IncidentAfter: duration(2 * time.Minute),
+		}}},
+		{Name: "pods", EntityType: "pod", Enabled: true, Spec: Spec{StateMachine: &StateMachineSpec{
+			WarningAfter: duration(4 * time.Minute), IncidentAfter: duration(4 * time.Minute),
+		}}},
+		{Name: "disabled", Enabled: false, Spec: Spec{StateMachine: &StateMachineSpec{WarningRisk: float(0.3)}}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	th := m.Thresholds("prod", "prod-api/pod/api-7c9fd8b6f5-x2kqz")
+	if th.WarningAfter != time.Minute || th.IncidentAfter != 2*time.Minute {
+		t.Errorf("命名空间策略应覆盖实体类型策略: %+v", th)
+	}
+	if th.IncidentRisk != 0.6 || th.WarningRisk != 0.2 || th.RecoveryAfter != 10*time.Minute {
+		t.Errorf("未覆盖的字段应沿用全局策略 / 默认值: %+v", th)
+	}
+
+	th = m.Thresholds("prod", "default/pod/web-5d8c7b9f4d-q7r2m")
+	if th.WarningAfter != 4*time.Minute {
+		t.Errorf("实体类型策略应覆盖集群策略: %+v", th)
+	}
+	th = m.Thresholds("prod", "_cluster/node/worker-1")
+	if th.WarningAfter != 90*time.Second {
+		t.Errorf("集群策略应覆盖全局策略: %+v", th)
+	}
+	th = m.Thresholds("staging", "_cluster/node/worker-1")
+	if th.WarningAfter != 3*time.Minute {
+		t.Errorf("其他集群只应用全局策略: %+v", th)
+	}
+
+	eff := m.Effective("prod", "prod-api/pod/api-7c9fd8b6f5-x2kqz")
+	want := []string{"global", "prod-cluster", "pods", "prod-ns"}
+	if len(eff.Policies) != len(want) {
+		t.Fatalf("effective policies = %v, want %v", eff.Policies, want)
+	}
+	for i := range want {
+		if eff.Policies[i] != want[i] {
+			t.Fatalf("effective policies = %v, want %v", eff.Policies, want)
+		}
+	}
+	if _, ok := eff.Spec.Metrics["pod"]; !ok || len(eff.Spec.Metrics) != 1 {
+		t.Errorf("实体级预览应只包含该实体类型的指标: %v", eff.Spec.Metrics)
+	}
+}
+
+// TestResolveMetrics 批处理命名空间关闭重启指标，其他命名空间不受影响
+func TestResolveMetrics(t *testing.T) {
+	m, err := NewStatic([]*Policy{
+		{Name: "batch", Namespace: "batch-*", EntityType: "pod", Enabled: true, Spec: Spec{
+			Metrics: map[string]map[string]MetricSpec{"pod": {
+				"restart_count":   {Disabled: boolPtr(true)},
+				"container_crash": {Weight: float(0.3), Channel: "deterministic"},
+			}},
+		}},
+		{Name: "global-detector", Enabled: true, Spec: Spec{
+			Metrics:    map[string]map[string]MetricSpec{"node": {"cpu_usage": {Detector: "mad"}}},
+			SelfWeight: float(0.8),
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch := m.RiskConfig("prod", "batch-etl/pod/job-x2kqz").GetMetricConfigs("pod")
+	if !batch["restart_count"].Disabled || batch["restart_count"].Weight != 0.20 {
+		t.Errorf("restart_count 应仅被关闭: %+v", batch["restart_count"])
+	}
+	if mc := batch["container_crash"]; mc.Weight != 0.3 || mc.Channel != risk.ChannelDeterministic {
+		t.Errorf("未内置的指标应按覆盖创建: %+v", mc)
+	}
+	if m.RiskConfig("prod", "default/pod/api-x2kqz").GetMetricConfigs("pod")["restart_count"].Disabled {
+		t.Error("非批处理命名空间不应关闭 restart_count")
+	}
+	if m.RiskConfig("prod", "").SelfWeight != 0.8 {
+		t.Error("集群级参数应取自未限定范围的策略")
+	}
+	if m.Detector("_cluster/node/worker-1", "cpu_usage") != "mad" || m.Detector("_cluster/node/worker-1", "memory_usage") != "cusum" {
+		t.Error("检测器应叠加在默认配置之上")
+	}
+	if m.BaselineMode("default/service/api", "request_rate") != aiops.BaselineSeasonal {
+		t.Error("未覆盖的基线模式应沿用默认配置")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]*Policy{
+		"bad name":            {Name: "Prod_Policy"},
+		"risk out of range":   {Name: "a", Spec: Spec{StateMachine: &StateMachineSpec{IncidentRisk: float(1.5)}}},
+		"inconsistent":        {Name: "a", Spec: Spec{StateMachine: &StateMachineSpec{WarningRisk: float(0.6)}}},
+		"negative duration":   {Name: "a", Spec: Spec{StateMachine: &StateMachineSpec{WarningAfter: duration(-time.Minute)}}},
+		"unknown entity":      {Name: "a", EntityType: "deployment"},
+		"entity mismatch":     {Name: "a", EntityType: "pod", Spec: Spec{Metrics: map[string]map[string]MetricSpec{"node": {"cpu_usage": {}}}}},
+		"unknown detector":    {Name: "a", Spec: Spec{Metrics: map[string]map[string]MetricSpec{"node": {"cpu_usage": {Detector: "lstm"}}}}},
+		"cluster detector":    {Name: "a", ClusterID: "prod", Spec: Spec{Metrics: map[string]map[string]MetricSpec{"node": {"cpu_usage": {Detector: "mad"}}}}},
+		"scoped cluster knob": {Name: "a", Namespace: "prod", Spec: Spec{SelfWeight: float(0.5)}},
+	}
+	for name, p := range cases {
+		if err := p.Validate(); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("%s: expected ErrInvalidPolicy, got %v", name, err)
+		}
+	}
+	ok := &Policy{Name: "prod-fast", Namespace: "prod-*", Spec: Spec{StateMachine: &StateMachineSpec{
+		WarningRisk: float(0.15), IncidentRisk: float(0.4), WarningAfter: duration(time.Minute),
+	}}}
+	if err := ok.Validate(); err != nil {
+		t.Errorf("valid policy rejected: %v", err)
+	}
+}
+
+// TestLayeredThresholds 保存 / 删除前校验各范围逐层叠加后的阈值顺序
+func TestLayeredThresholds(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(&memPolicyRepo{}, nil)
+	if err := m.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	global := &Policy{Name: "global", Enabled: true, Spec: Spec{StateMachine: &StateMachineSpec{
+		WarningRisk: float(0.4), IncidentRisk: float(0.7),
+	}}}
+	sensitive := &Policy{Name: "c1-sensitive", ClusterID: "c1", Enabled: true, Spec: Spec{StateMachine: &StateMachineSpec{
+		WarningRisk: float(0.1), RecoveryRisk: float(0.05),
+	}}}
+	prod := &Policy{Name: "c1-prod", ClusterID: "c1", Namespace: "prod", Enabled: true, Spec: Spec{StateMachine: &StateMachineSpec{
+		IncidentRisk: float(0.3),
+	}}}
+	for _, p := range []*Policy{global, sensitive, prod} {
+		if _, err := m.Save(ctx, p, "alice"); err != nil {
+			t.Fatalf("save %s: %v", p.Name, err)
+		}
+	}
+	if th := m.Thresholds("c1", "prod/pod/api-x2kqz"); th.WarningRisk != 0.1 || th.IncidentRisk != 0.3 {
+		t.Fatalf("c1/prod thresholds = %+v", th)
+	}
+
+	// 单独合法，但叠加到全局策略后 warningRisk (0.4) >= incidentRisk (0.3)
+	if _, err := m.Save(ctx, &Policy{Name: "prod", Namespace: "prod", Enabled: true, Spec: Spec{
+		StateMachine: &StateMachineSpec{IncidentRisk: float(0.3)},
+	}}, "bob"); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("layered save err = %v, want ErrInvalidPolicy", err)
+	}
+	// 删除中间层后 c1/prod 同样不一致
+	if err := m.Delete(ctx, "c1-sensitive", "bob"); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("delete err = %v, want ErrInvalidPolicy", err)
+	}
+	if len(m.Policies()) != 3 {
+		t.Errorf("policies = %d, want 3", len(m.Policies()))
+	}
+
+	// 历史数据中的不一致叠加: 忽略该策略的风险阈值，其余字段照常生效
+	bad := &Policy{Name: "c1-prod", ClusterID: "c1", Namespace: "prod", Enabled: true, Spec: Spec{StateMachine: &StateMachineSpec{
+		IncidentRisk: float(0.3), WarningAfter: duration(time.Minute),
+	}}}
+	if _, err := NewStatic([]*Policy{global, bad}, nil); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("NewStatic err = %v, want ErrInvalidPolicy", err)
+	}
+	th := compile([]*Policy{global, bad}, risk.DefaultRiskConfig()).resolve(entityScope("c1", "prod/pod/api-x2kqz")).thresholds
+	if th.WarningRisk != 0.4 || th.IncidentRisk != 0.7 || th.WarningAfter != time.Minute {
+		t.Errorf("guarded thresholds = %+v", th)
+	}
+}
+
+// TestVersioning 保存 / 乐观锁 / 删除 / 回滚均插入新版本并热加载
+func TestVersioning(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(&memPolicyRepo{}, nil)
+	if err := m.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	key := "prod/pod/api-x2kqz"
+
+	v1, err := m.Save(ctx, &Policy{Name: "prod", Namespace: "prod", Enabled: true, Spec: Spec{
+		StateMachine: &StateMachineSpec{WarningAfter: duration(time.Minute)},
+	}}, "alice")
+	if err != nil || v1.Version != 1 || v1.Author != "alice" {
+		t.Fatalf("save v1: %+v %v", v1, err)
+	}
+	if m.Thresholds("c1", key).WarningAfter != time.Minute {
+		t.Fatal("保存后应立即生效")
+	}
+
+	if _, err := m.Save(ctx, &Policy{Name: "prod", Version: 7, Namespace: "prod", Enabled: true}, "bob"); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("过期版本应冲突, got %v", err)
+	}
+	v2, err := m.Save(ctx, &Policy{Name: "prod", Version: 1, Namespace: "prod", Enabled: true, Spec: Spec{
+		StateMachine: &StateMachineSpec{WarningAfter: duration(30 * time.Second)},
+	}}, "bob")
+	if err != nil || v2.Version != 2 || m.Thresholds("c1", key).WarningAfter != 30*time.Second {
+		t.Fatalf("save v2: %+v %v", v2, err)
+	}
+
+	if err := m.Delete(ctx, "prod", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Policies()) != 0 || m.Thresholds("c1", key).WarningAfter != 2*time.Minute {
+		t.Fatal("删除后应恢复默认阈值")
+	}
+	if err := m.Delete(ctx, "prod", "bob"); !errors.Is(err, ErrPolicyNotFound) {
+		t.Fatalf("重复删除应返回不存在, got %v", err)
+	}
+
+	v4, err := m.Rollback(ctx, "prod", 1, "carol")
+	if err != nil || v4.Version != 4 || v4.Comment != "rollback to v1" {
+		t.Fatalf("rollback: %+v %v", v4, err)
+	}
+	if m.Thresholds("c1", key).WarningAfter != time.Minute {
+		t.Fatal("回滚后应使用 v1 内容")
+	}
+
+	history, err := m.History(ctx, "prod")
+	if err != nil || len(history) != 4 || !history[1].Deleted || history[0].Author != "carol" {
+		t.Fatalf("unexpected history: %v", err)
+	}
+
+	// 重启后从数据库恢复
+	restarted := NewManager(m.repo, nil)
+	if err := restarted.Load(ctx); err != nil || len(restarted.Policies()) != 1 || restarted.Policies()[0].Version != 4 {
+		t.Fatalf("reload: %v %+v", err, restarted.Policies())
+	}
+}
+
+func TestSpecJSON(t *testing.T) {
+	var p Policy
+	raw := `{"name":"prod","namespace":"prod-*","enabled":true,"spec":{"stateMachine":{"incidentAfter":"2m","incidentRisk":0.4},"temporalHalfLife":"10m"}}`
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(*p.Spec.StateMachine.IncidentAfter) != 2*time.Minute || time.Duration(*p.Spec.TemporalHalfLife) != 10*time.Minute {
+		t.Fatalf("unexpected spec: %+v", p.Spec)
+	}
+	out, _ := json.Marshal(p.Spec.StateMachine)
+	if string(out) != `{"incidentRisk":0.4,"incidentAfter":"2m0s"}` {
+		t.Errorf("unexpected encoding: %s", out)
+	}
+	if err := json.Unmarshal([]byte(`{"stateMachine":{"warningAfter":120}}`), &Spec{}); err == nil {
+		t.Error("数字时长应报错")
+	}
+}
@@ -0,0 +1,311 @@
+// atlhyper_master_v2/aiops/policy/resolve.go
+// 策略解析: 按作用范围从低到高优先级叠加，结果按 (集群, 命名空间, 实体类型) 缓存
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/aiops/risk"
+	"AtlHyper/atlhyper_master_v2/aiops/statemachine"
+)
+
+// scope 解析范围
+type scope struct {
+	clusterID  string
+	namespace  string
+	entityType string
+}
+
+// entityScope 由 entityKey（"namespace/type/name"）得到解析范围；entityKey 为空为集群级
+func entityScope(clusterID, entityKey string) scope {
+	if entityKey == "" {
+		return scope{clusterID: clusterID}
+	}
+	parts := strings.SplitN(entityKey, "/", 3)
+	return scope{clusterID: clusterID, namespace: parts[0], entityType: aiops.ExtractEntityType(entityKey)}
+}
+
+// resolved 某一范围叠加后的结果
+type resolved struct {
+	policies   []string
+	risk       *risk.RiskConfig
+	thresholds statemachine.Thresholds
+}
+
+// compiled 一组生效策略（不可变，策略变更时整体替换）
+type compiled struct {
+	policies []*Policy // 当前版本（含已停用），按名称排序
+	ordered  []*Policy // 已启用，按优先级升序（具体程度，其次名称）
+	base     *risk.RiskConfig
+	cache    sync.Map // scope -> *resolved
+}
+
+// compile 编译策略集合
+func compile(policies []*Policy, base *risk.RiskConfig) *compiled {
+	c := &compiled{policies: policies, base: base}
+	sort.Slice(c.policies, func(i, j int) bool { return c.policies[i].Name < c.policies[j].Name })
+	for _, p := range c.policies {
+		if p.Enabled {
+			c.ordered = append(c.ordered, p)
+		}
+	}
+	sort.SliceStable(c.ordered, func(i, j int) bool {
+		return c.ordered[i].specificity() < c.ordered[j].specificity()
+	})
+	return c
+}
+
+// resolve 解析范围内的风险配置与阈值
+// 叠加后风险阈值顺序不一致的策略（保存时已拒绝，仅可能来自历史数据）忽略其风险阈值
+func (c *compiled) resolve(s scope) *resolved {
+	if r, ok := c.cache.Load(s); ok {
+		return r.(*resolved)
+	}
+	r := &resolved{
+		policies:   []string{},
+		risk:       cloneRiskConfig(c.base),
+		thresholds: statemachine.DefaultThresholds(),
+	}
+	for _, p := range c.ordered {
+		if !p.matches(s.clusterID, s.namespace, s.entityType) {
+			continue
+		}
+		r.policies = append(r.policies, p.Name)
+		applyRisk(r.risk, &p.Spec)
+		if p.Spec.StateMachine != nil {
+			if err := layerThresholds(&r.thresholds, p.Spec.StateMachine); err != nil {
+				log.Warn("策略叠加后阈值不一致，已忽略其风险阈值", "policy", p.Name,
+					"cluster", s.clusterID, "namespace", s.namespace, "entityType", s.entityType, "err", err)
+			}
+		}
+	}
+	actual, _ := c.cache.LoadOrStore(s, r)
+	return actual.(*resolved)
+}
+
+// checkLayered 校验每个可区分范围逐层叠加后的阈值顺序（保存 / 删除 / 回滚前对结果策略集调用）
+// 可区分范围: 各策略出现过的集群、命名空间（通配模式按字面值参与匹配）与全部实体类型的组合，
+// 空值代表未被任何策略点名的集群 / 集群级查询
+func (c *compiled) checkLayered() error {
+	clusters := map[string]bool{"": true}
+	namespaces := map[string]bool{"": true}
+	for _, p := range c.ordered {
+		clusters[p.ClusterID] = true
+		namespaces[p.Namespace] = true
+	}
+	types := map[string]bool{"": true}
+	for t := range entityTypes {
+		types[t] = true
+	}
+
+	for _, clusterID := range sortedKeys(clusters) {
+		for _, namespace := range sortedKeys(namespaces) {
+			for _, entityType := range sortedKeys(types) {
+				th := statemachine.DefaultThresholds()
+				var names []string
+				for _, p := range c.ordered {
+					if !p.matches(clusterID, namespace, entityType) {
+						continue
+					}
+					names = append(names, p.Name)
+					if p.Spec.StateMachine == nil {
+						continue
+					}
+					if err := layerThresholds(&th, p.Spec.StateMachine); err != nil {
+						return fmt.Errorf("%w: policy %q layered over %v (cluster %q, namespace %q, entityType %q): %v",
+							ErrInvalidPolicy, p.Name, names[:len(names)-1], clusterID, namespace, entityType, err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// layerThresholds 叠加一层阈值覆盖；风险阈值叠加后顺序不一致时保留原风险阈值并返回错误（时长等其他字段照常叠加）
+func layerThresholds(th *statemachine.Thresholds, sm *StateMachineSpec) error {
+	next := *th
+	applyThresholds(&next, sm)
+	err := checkThresholds(next)
+	if err != nil {
+		next.WarningRisk, next.IncidentRisk, next.RecoveryRisk = th.WarningRisk, th.IncidentRisk, th.RecoveryRisk
+	}
+	*th = next
+	return err
+}
+
+// sortedKeys 集合的有序键（保证校验错误信息稳定）
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cloneRiskConfig 深拷贝风险配置
+func cloneRiskConfig(cfg *risk.RiskConfig) *risk.RiskConfig {
+	out := *cfg
+	out.MetricConfigs = make(map[string]map[string]risk.MetricConfig, len(cfg.MetricConfigs))
+	for entityType, metrics := range cfg.MetricConfigs {
+		m := make(map[string]risk.MetricConfig, len(metrics))
+		for name, mc := range metrics {
+			m[name] = mc
+		}
+		out.MetricConfigs[entityType] = m
+	}
+	return &out
+}
+
+// applyRisk 叠加风险配置覆盖
+func applyRisk(cfg *risk.RiskConfig, spec *Spec) {
+	for entityType, metrics := range spec.Metrics {
+		target := cfg.MetricConfigs[entityType]
+		if target == nil {
+			target = make(map[string]risk.MetricConfig)
+			cfg.MetricConfigs[entityType] = target
+		}
+		for name, ms := range metrics {
+			mc, ok := target[name]
+			if !ok {
+				// 与局部风险对未配置指标的默认处理一致
+				mc = risk.MetricConfig{Weight: 0.1, Channel: risk.ChannelStatistical}
+			}
+			if ms.Weight != nil {
+				mc.Weight = *ms.Weight
+			}
+			switch ms.Channel {
+			case "statistical":
+				mc.Channel = risk.ChannelStatistical
+			case "deterministic":
+				mc.Channel = risk.ChannelDeterministic
+			case "both":
+				mc.Channel = risk.ChannelBoth
+			}
+			if ms.Baseline != "" {
+				mc.Baseline = aiops.BaselineMode(ms.Baseline)
+			}
+			if ms.Detector != "" {
+				mc.Detector = ms.Detector
+			}
+			if ms.Disabled != nil {
+				mc.Disabled = *ms.Disabled
+			}
+			target[name] = mc
+		}
+	}
+	if spec.TemporalHalfLife != nil {
+		cfg.TemporalHalfLife = time.Duration(*spec.TemporalHalfLife).Seconds()
+	}
+	if spec.SelfWeight != nil {
+		cfg.SelfWeight = *spec.SelfWeight
+	}
+	if cw := spec.ClusterWeights; cw != nil {
+		if cw.Max != nil {
+			cfg.ClusterWeightMax = *cw.Max
+		}
+		if cw.SLO != nil {
+			cfg.ClusterWeightSLO = *cw.SLO
+		}
+		if cw.Growth != nil {
+			cfg.ClusterWeightGrowth = *cw.Growth
+		}
+	}
+}
+
+// applyThresholds 叠加状态转换阈值覆盖
+func applyThresholds(th *statemachine.Thresholds, sm *StateMachineSpec) {
+	setFloat(&th.WarningRisk, sm.WarningRisk)
+	setFloat(&th.IncidentRisk, sm.IncidentRisk)
+	setFloat(&th.RecoveryRisk, sm.RecoveryRisk)
+	setFloat(&th.ClusterRisk, sm.ClusterRisk)
+	setDuration(&th.WarningAfter, sm.WarningAfter)
+	setDuration(&th.HealthyAfter, sm.HealthyAfter)
+	setDuration(&th.IncidentAfter, sm.IncidentAfter)
+	setDuration(&th.RecoveryAfter, sm.RecoveryAfter)
+	setDuration(&th.StableAfter, sm.StableAfter)
+}
+
+func setFloat(dst *float64, v *float64) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+func setDuration(dst *time.Duration, v *Duration) {
+	if v != nil {
+		*dst = time.Duration(*v)
+	}
+}
+
+// ==================== 生效配置预览 ====================
+
+// Effective 某一集群 / 实体叠加后的生效配置
+type Effective struct {
+	ClusterID string   `json:"clusterId"`
+	EntityKey string   `json:"entityKey,omitempty"`
+	Policies  []string `json:"policies"` // 命中的策略（优先级升序，后者覆盖前者）
+	Spec      Spec     `json:"spec"`     // 完整取值（含默认值）
+}
+
+// effective 生成预览（实体级只展示该实体类型的指标）
+func (r *resolved) effective(s scope) Spec {
+	th, cfg := r.thresholds, *r.risk // 拷贝，避免调用方修改缓存
+	spec := Spec{
+		StateMachine: &StateMachineSpec{
+			WarningRisk:   &th.WarningRisk,
+			IncidentRisk:  &th.IncidentRisk,
+			RecoveryRisk:  &th.RecoveryRisk,
+			ClusterRisk:   &th.ClusterRisk,
+			WarningAfter:  durationPtr(th.WarningAfter),
+			HealthyAfter:  durationPtr(th.HealthyAfter),
+			IncidentAfter: durationPtr(th.IncidentAfter),
+			RecoveryAfter: durationPtr(th.RecoveryAfter),
+			StableAfter:   durationPtr(th.StableAfter),
+		},
+		Metrics:          make(map[string]map[string]MetricSpec),
+		TemporalHalfLife: durationPtr(time.Duration(cfg.TemporalHalfLife * float64(time.Second))),
+		SelfWeight:       &cfg.SelfWeight,
+		ClusterWeights:   &ClusterWeightSpec{Max: &cfg.ClusterWeightMax, SLO: &cfg.ClusterWeightSLO, Growth: &cfg.ClusterWeightGrowth},
+	}
+	for entityType, metrics := range cfg.MetricConfigs {
+		if s.entityType != "" && entityType != s.entityType {
+			continue
+		}
+		out := make(map[string]MetricSpec, len(metrics))
+		for name, mc := range metrics {
+			weight, disabled := mc.Weight, mc.Disabled
+			out[name] = MetricSpec{
+				Weight:   &weight,
+				Channel:  channelName(mc.Channel),
+				Baseline: string(mc.Baseline),
+				Detector: mc.Detector,
+				Disabled: &disabled,
+			}
+		}
+		spec.Metrics[entityType] = out
+	}
+	return spec
+}
+
+func durationPtr(d time.Duration) *Duration {
+	v := Duration(d)
+	return &v
+}
+
+func channelName(ch risk.MetricChannel) string {
+	switch ch {
+	case risk.ChannelDeterministic:
+		return "deterministic"
+	case risk.ChannelBoth:
+		return "both"
+	default:
+		return "statistical"
+	}
+}
@@ -0,0 +1,265 @@
+// atlhyper_master_v2/aiops/policy/types.go
+// AIOps 策略定义与校验
+//
+// 策略按作用范围覆盖编译内置的默认值（risk.DefaultRiskConfig + statemachine.DefaultThresholds），
+// 所有字段可选，未设置的字段沿用更低优先级的策略或默认值。
+//
+// 示例（批处理命名空间容忍重启、生产命名空间更快升级）:
+//
+//	{"name": "batch-tolerate-restarts", "namespace": "batch-*", "entityType": "pod",
+//	 "spec": {"metrics": {"pod": {"restart_count": {"disabled": true}, "max_container_restarts": {"disabled": true}}}}}
+//
+//	{"name": "prod-fast-escalation", "namespace": "prod-*",
+//	 "spec": {"stateMachine": {"warningAfter": "1m", "incidentAfter": "2m", "incidentRisk": 0.4}}}
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/aiops/baseline"
+	"AtlHyper/atlhyper_master_v2/aiops/statemachine"
+)
+
+// 策略错误
+var (
+	ErrPolicyNotFound  = errors.New("aiops policy not found")
+	ErrInvalidPolicy   = errors.New("invalid aiops policy")
+	ErrVersionConflict = errors.New("aiops policy version conflict")
+)
+
+// namePattern 策略名称（DNS label 风格）
+var namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,62}[a-z0-9])?$`)
+
+// entityTypes 可作为作用范围的实体类型
+var entityTypes = map[string]bool{"service": true, "pod": true, "node": true, "ingress": true, "logs": true}
+
+// 指标通道名称
+var channels = map[string]bool{"statistical": true, "deterministic": true, "both": true}
+
+// Policy AIOps 策略（某一版本）
+type Policy struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+
+	// 作用范围（空 = 全部）；命名空间支持通配（如 "batch-*"），集群级实体（节点等）的命名空间为 "_cluster"
+	ClusterID  string `json:"clusterId,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	EntityType string `json:"entityType,omitempty"`
+
+	Enabled bool `json:"enabled"`
+	Spec    Spec `json:"spec"`
+
+	Deleted   bool      `json:"deleted,omitempty"` // 删除标记版本（仅出现在历史中）
+	Author    string    `json:"author,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Spec 策略内容
+type Spec struct {
+	StateMachine *StateMachineSpec `json:"stateMachine,omitempty"`
+	// Metrics 实体类型 → 指标名 → 覆盖
+	Metrics map[string]map[string]MetricSpec `json:"metrics,omitempty"`
+
+	// 集群级参数（仅可出现在未限定命名空间 / 实体类型的策略中）
+	TemporalHalfLife *Duration          `json:"temporalHalfLife,omitempty"` // 时序衰减半衰期
+	SelfWeight       *float64           `json:"selfWeight,omitempty"`       // 图传播自身权重 α
+	ClusterWeights   *ClusterWeightSpec `json:"clusterWeights,omitempty"`   // ClusterRisk 聚合权重
+}
+
+// StateMachineSpec 状态转换阈值覆盖
+type StateMachineSpec struct {
+	WarningRisk  *float64 `json:"warningRisk,omitempty"`
+	IncidentRisk *float64 `json:"incidentRisk,omitempty"`
+	RecoveryRisk *float64 `json:"recoveryRisk,omitempty"`
+	ClusterRisk  *float64 `json:"clusterRisk,omitempty"` // 0 = 不按 ClusterRisk 升级
+
+	WarningAfter  *Duration `json:"warningAfter,omitempty"`
+	HealthyAfter  *Duration `json:"healthyAfter,omitempty"`
+	IncidentAfter *Duration `json:"incidentAfter,omitempty"`
+	RecoveryAfter *Duration `json:"recoveryAfter,omitempty"`
+	StableAfter   *Duration `json:"stableAfter,omitempty"`
+}
+
+// MetricSpec 指标配置覆盖
+type MetricSpec struct {
+	Weight   *float64 `json:"weight,omitempty"`
+	Channel  string   `json:"channel,omitempty"`  // statistical / deterministic / both
+	Baseline string   `json:"baseline,omitempty"` // ema / seasonal（不可限定集群）
+	Detector string   `json:"detector,omitempty"` // ema / mad / cusum / bocpd（不可限定集群）
+	Disabled *bool    `json:"disabled,omitempty"` // 关闭该指标
+}
+
+// ClusterWeightSpec ClusterRisk 聚合权重覆盖
+type ClusterWeightSpec struct {
+	Max    *float64 `json:"max,omitempty"`
+	SLO    *float64 `json:"slo,omitempty"`
+	Growth *float64 `json:"growth,omitempty"`
+}
+
+// Duration JSON 中以字符串表示的时长（如 "5m"）
+type Duration time.Duration
+
+// MarshalJSON 序列化为时长字符串
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON 解析时长字符串
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5m\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Validate 校验策略（名称、作用范围、取值范围，以及叠加到默认阈值后的一致性）
+func (p *Policy) Validate() error {
+	if err := p.validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+	return nil
+}
+
+func (p *Policy) validate() error {
+	if !namePattern.MatchString(p.Name) {
+		return fmt.Errorf("name %q must be lowercase letters, digits and '-'", p.Name)
+	}
+	if p.Namespace != "" {
+		if _, err := path.Match(p.Namespace, ""); err != nil {
+			return fmt.Errorf("namespace pattern %q: %v", p.Namespace, err)
+		}
+	}
+	if p.EntityType != "" && !entityTypes[p.EntityType] {
+		return fmt.Errorf("unknown entityType %q", p.EntityType)
+	}
+
+	spec := &p.Spec
+	if sm := spec.StateMachine; sm != nil {
+		for name, v := range map[string]*float64{"warningRisk": sm.WarningRisk, "incidentRisk": sm.IncidentRisk, "recoveryRisk": sm.RecoveryRisk} {
+			if v != nil && (*v <= 0 || *v > 1) {
+				return fmt.Errorf("stateMachine.%s must be in (0, 1]", name)
+			}
+		}
+		if sm.ClusterRisk != nil && (*sm.ClusterRisk < 0 || *sm.ClusterRisk > 100) {
+			return errors.New("stateMachine.clusterRisk must be in [0, 100]")
+		}
+		for name, v := range map[string]*Duration{"warningAfter": sm.WarningAfter, "healthyAfter": sm.HealthyAfter,
+			"incidentAfter": sm.IncidentAfter, "recoveryAfter": sm.RecoveryAfter, "stableAfter": sm.StableAfter} {
+			if v != nil && *v < 0 {
+				return fmt.Errorf("stateMachine.%s must not be negative", name)
+			}
+		}
+		th := statemachine.DefaultThresholds()
+		applyThresholds(&th, sm)
+		if err := checkThresholds(th); err != nil {
+			return err
+		}
+	}
+
+	for entityType, metrics := range spec.Metrics {
+		if !entityTypes[entityType] {
+			return fmt.Errorf("metrics: unknown entityType %q", entityType)
+		}
+		if p.EntityType != "" && entityType != p.EntityType {
+			return fmt.Errorf("metrics: policy scoped to entityType %q cannot override %q metrics", p.EntityType, entityType)
+		}
+		for metric, m := range metrics {
+			where := entityType + "." + metric
+			if m.Weight != nil && (*m.Weight < 0 || *m.Weight > 1) {
+				return fmt.Errorf("metrics.%s.weight must be in [0, 1]", where)
+			}
+			if m.Channel != "" && !channels[m.Channel] {
+				return fmt.Errorf("metrics.%s.channel must be statistical, deterministic or both", where)
+			}
+			if m.Baseline != "" && m.Baseline != string(aiops.BaselineEMA) && m.Baseline != string(aiops.BaselineSeasonal) {
+				return fmt.Errorf("metrics.%s.baseline must be ema or seasonal", where)
+			}
+			if m.Detector != "" {
+				if _, ok := baseline.Lookup(m.Detector); !ok {
+					return fmt.Errorf("metrics.%s.detector %q unknown (available: %v)", where, m.Detector, baseline.Detectors())
+				}
+			}
+			// 基线状态按实体 + 指标维护，不区分集群
+			if (m.Baseline != "" || m.Detector != "") && p.ClusterID != "" {
+				return fmt.Errorf("metrics.%s: baseline / detector cannot be scoped to a cluster", where)
+			}
+		}
+	}
+
+	if spec.TemporalHalfLife != nil || spec.SelfWeight != nil || spec.ClusterWeights != nil {
+		if p.Namespace != "" || p.EntityType != "" {
+			return errors.New("temporalHalfLife / selfWeight / clusterWeights apply per cluster and cannot be scoped to a namespace or entityType")
+		}
+		if spec.TemporalHalfLife != nil && *spec.TemporalHalfLife <= 0 {
+			return errors.New("temporalHalfLife must be positive")
+		}
+		if spec.SelfWeight != nil && (*spec.SelfWeight < 0 || *spec.SelfWeight > 1) {
+			return errors.New("selfWeight must be in [0, 1]")
+		}
+		if cw := spec.ClusterWeights; cw != nil {
+			for name, v := range map[string]*float64{"max": cw.Max, "slo": cw.SLO, "growth": cw.Growth} {
+				if v != nil && (*v < 0 || *v > 1) {
+					return fmt.Errorf("clusterWeights.%s must be in [0, 1]", name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkThresholds 校验风险阈值顺序（单个策略叠加到默认值，以及多个策略逐层叠加后均须满足）
+func checkThresholds(th statemachine.Thresholds) error {
+	if !(th.RecoveryRisk <= th.WarningRisk && th.WarningRisk < th.IncidentRisk) {
+		return fmt.Errorf("stateMachine thresholds must satisfy recoveryRisk (%.2f) <= warningRisk (%.2f) < incidentRisk (%.2f)",
+			th.RecoveryRisk, th.WarningRisk, th.IncidentRisk)
+	}
+	return nil
+}
+
+// specificity 作用范围具体程度（命名空间 > 实体类型 > 集群），越具体优先级越高
+func (p *Policy) specificity() int {
+	n := 0
+	if p.ClusterID != "" {
+		n += 1
+	}
+	if p.EntityType != "" {
+		n += 2
+	}
+	if p.Namespace != "" {
+		n += 4
+	}
+	return n
+}
+
+// matches 判断策略是否作用于指定范围
+// namespace / entityType 为空表示集群级查询，仅匹配未限定命名空间 / 实体类型的策略
+func (p *Policy) matches(clusterID, namespace, entityType string) bool {
+	if p.ClusterID != "" && p.ClusterID != clusterID {
+		return false
+	}
+	if p.EntityType != "" && p.EntityType != entityType {
+		return false
+	}
+	if p.Namespace != "" {
+		if namespace == "" {
+			return false
+		}
+		if ok, _ := path.Match(p.Namespace, namespace); !ok {
+			return false
+		}
+	}
+	return true
+}
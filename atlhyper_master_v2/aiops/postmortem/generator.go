@@ -0,0 +1,228 @@
+// atlhyper_master_v2/aiops/postmortem/generator.go
+// 事件复盘文档生成器
+//
+// 汇总事件详情、AI 报告、窗口内部署与运维操作、SLO 错误预算消耗，生成 Markdown 复盘。
+// 配置了 analysis 角色 Provider 时由 LLM 撰写叙述部分，否则（或调用失败时）使用纯模板。
+package postmortem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"AtlHyper/atlhyper_master_v2/ai"
+	"AtlHyper/atlhyper_master_v2/ai/prompts"
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/datahub"
+	"AtlHyper/atlhyper_master_v2/slo"
+	"AtlHyper/common/logger"
+)
+
+var log = logger.Module("AIOps-Postmortem")
+
+// 文档来源
+const (
+	SourceAI       = "ai"
+	SourceTemplate = "template"
+)
+
+// 数据采集参数
+const (
+	deployLookback  = 2 * time.Hour // 事件开始前的部署也可能是诱因
+	maxDeploys      = 20
+	maxCommands     = 50
+	maxPromptChars  = 16000           // 事件资料截断上限（~4000 tokens）
+	generateTimeout = 2 * time.Minute // 需低于 Gateway WriteTimeout（180s）
+)
+
+// Config 生成器依赖
+type Config struct {
+	Engine      aiops.Engine                       // 必需
+	Repo        database.AIOpsPostmortemRepository // 必需
+	ReportRepo  database.AIReportRepository        // 可选
+	DeployRepo  database.DeployHistoryRepository   // 可选
+	CommandRepo database.CommandHistoryRepository  // 可选
+	SLORepo     database.SLORepository             // 可选
+	Store       datahub.Store                      // 可选（读取 OTelSnapshot 计算 SLO 影响）
+	AIService   ai.AIService                       // 可选，nil = 纯模板
+}
+
+// Generator 事件复盘文档生成器
+type Generator struct {
+	cfg Config
+	now func() time.Time
+}
+
+// NewGenerator 创建复盘文档生成器
+func NewGenerator(cfg Config) *Generator {
+	return &Generator{cfg: cfg, now: time.Now}
+}
+
+// Generate 生成并保存事件复盘文档（覆盖已有文档）
+// 事件不存在返回 aiops.ErrIncidentNotFound
+func (g *Generator) Generate(ctx context.Context, incidentID, by string) (*database.AIOpsPostmortem, error) {
+	ctx, cancel := context.WithTimeout(ctx, generateTimeout)
+	defer cancel()
+
+	facts, err := g.collect(ctx, incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := g.now()
+	fallback := templateNarrative(facts)
+	narrative, source := fallback, SourceTemplate
+	var providerName, model string
+
+	if g.cfg.AIService != nil {
+		if aiNarrative, result, err := g.writeNarrative(ctx, facts, fallback, now); err != nil {
+			log.Warn("AI 撰写复盘失败，使用模板", "incident", incidentID, "err", err)
+		} else {
+			narrative, source = mergeNarrative(aiNarrative, fallback), SourceAI
+			providerName, model = result.ProviderName, result.Model
+		}
+	}
+
+	pm := &database.AIOpsPostmortem{
+		IncidentID:   incidentID,
+		ClusterID:    facts.Incident.ClusterID,
+		Content:      Render(facts, narrative, source, now),
+		Source:       source,
+		ProviderName: providerName,
+		Model:        model,
+		GeneratedBy:  by,
+		UpdatedBy:    by,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := g.cfg.Repo.Save(ctx, pm); err != nil {
+		return nil, fmt.Errorf("保存复盘文档失败: %w", err)
+	}
+
+	log.Info("复盘文档已生成", "incident", incidentID, "source", source, "by", by)
+	return g.cfg.Repo.Get(ctx, incidentID)
+}
+
+// collect 采集复盘所需的事实数据（可选数据源失败时记录日志并跳过）
+func (g *Generator) collect(ctx context.Context, incidentID string) (*Facts, error) {
+	detail := g.cfg.Engine.GetIncidentDetail(ctx, incidentID)
+	if detail == nil {
+		return nil, aiops.ErrIncidentNotFound
+	}
+
+	f := &Facts{Incident: detail, WindowStart: detail.StartedAt, WindowEnd: g.now()}
+	if detail.ResolvedAt != nil {
+		f.WindowEnd = *detail.ResolvedAt
+	}
+
+	if g.cfg.ReportRepo != nil {
+		reports, err := g.cfg.ReportRepo.ListByIncident(ctx, incidentID)
+		if err != nil {
+			log.Warn("查询 AI 报告失败", "incident", incidentID, "err", err)
+		}
+		f.Reports = reports
+	}
+
+	if g.cfg.DeployRepo != nil {
+		deploys, err := g.cfg.DeployRepo.List(ctx, database.DeployHistoryQueryOpts{
+			ClusterID: detail.ClusterID,
+			Since:     f.WindowStart.Add(-deployLookback),
+			Until:     f.WindowEnd,
+			Limit:     maxDeploys,
+		})
+		if err != nil {
+			log.Warn("查询部署历史失败", "incident", incidentID, "err", err)
+		}
+		f.Deploys = deploys
+	}
+
+	if g.cfg.CommandRepo != nil {
+		commands, err := g.cfg.CommandRepo.List(ctx, database.CommandQueryOpts{
+			ClusterID: detail.ClusterID,
+			Since:     f.WindowStart,
+			Until:     f.WindowEnd,
+			Limit:     maxCommands,
+		})
+		if err != nil {
+			log.Warn("查询命令历史失败", "incident", incidentID, "err", err)
+		}
+		f.Commands = commands
+	}
+
+	f.SLOImpacts = g.sloImpacts(ctx, detail.ClusterID, f.WindowStart, f.WindowEnd)
+	return f, nil
+}
+
+// sloImpacts 计算事件窗口内的错误预算消耗（OTelSnapshot 仅保留最近 1 天，更早的事件无数据）
+func (g *Generator) sloImpacts(ctx context.Context, clusterID string, start, end time.Time) []slo.BudgetImpact {
+	if g.cfg.SLORepo == nil || g.cfg.Store == nil {
+		return nil
+	}
+	targets, err := g.cfg.SLORepo.GetTargets(ctx, clusterID)
+	if err != nil || len(targets) == 0 {
+		return nil
+	}
+	snapshot, err := g.cfg.Store.GetSnapshot(clusterID)
+	if err != nil || snapshot == nil {
+		return nil
+	}
+	return slo.WindowBudgetImpact(snapshot.OTel, targets, start, end)
+}
+
+// writeNarrative 通过 analysis 角色撰写叙述部分
+// 事件资料即模板渲染的文档，LLM 在其基础上改写叙述
+func (g *Generator) writeNarrative(ctx context.Context, f *Facts, fallback *Narrative, now time.Time) (*Narrative, *ai.CompleteResult, error) {
+	facts := Render(f, fallback, SourceTemplate, now)
+	if len(facts) > maxPromptChars {
+		facts = truncateUTF8(facts, maxPromptChars) + "\n\n（资料过长，已截断）"
+	}
+
+	prompt := prompts.BuildPostmortemPrompt(facts)
+	result, err := g.cfg.AIService.Complete(ctx, &ai.CompleteRequest{
+		Role:         ai.RoleAnalysis,
+		SystemPrompt: prompt.System,
+		UserPrompt:   prompt.User,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	n, err := parseNarrative(result.Response)
+	if err != nil {
+		return nil, nil, err
+	}
+	return n, result, nil
+}
+
+// parseNarrative 解析 LLM 输出的叙述 JSON
+func parseNarrative(raw string) (*Narrative, error) {
+	jsonStr := raw
+	if start := strings.Index(raw, "{"); start != -1 {
+		if end := strings.LastIndex(raw, "}"); end > start {
+			jsonStr = raw[start : end+1]
+		}
+	}
+	var n Narrative
+	if err := json.Unmarshal([]byte(jsonStr), &n); err != nil {
+		return nil, fmt.Errorf("解析 LLM 输出失败: %w", err)
+	}
+	if strings.TrimSpace(n.Summary) == "" && strings.TrimSpace(n.RootCause) == "" {
+		return nil, fmt.Errorf("LLM 输出缺少叙述内容")
+	}
+	return &n, nil
+}
+
+// truncateUTF8 按字节截断且不切断多字节字符
+func truncateUTF8(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
@@ -0,0 +1,184 @@
+package postmortem
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/ai"
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// ==================== Mock ====================
+
+type stubEngine struct {
+	aiops.Engine
+	detail *aiops.IncidentDetail
+}
+
+func (e *stubEngine) GetIncidentDetail(ctx context.Context, id string) *aiops.IncidentDetail {
+	if e.detail == nil || e.detail.ID != id {
+		return nil
+	}
+	return e.detail
+}
+
+type memRepo struct {
+	docs map[string]*database.AIOpsPostmortem
+}
+
+func (r *memRepo) Save(ctx context.Context, pm *database.AIOpsPostmortem) error {
+	cp := *pm
+	r.docs[pm.IncidentID] = &cp
+	return nil
+}
+
+func (r *memRepo) UpdateContent(ctx context.Context, incidentID, content, by string, at time.Time) (bool, error) {
+	pm, ok := r.docs[incidentID]
+	if !ok {
+		return false, nil
+	}
+	pm.Content, pm.UpdatedBy, pm.UpdatedAt = content, by, at
+	return true, nil
+}
+
+func (r *memRepo) Get(ctx context.Context, incidentID string) (*database.AIOpsPostmortem, error) {
+	return r.docs[incidentID], nil
+}
+
+type stubReports struct {
+	database.AIReportRepository
+	reports []*database.AIReport
+}
+
+func (r *stubReports) ListByIncident(ctx context.Context, incidentID string) ([]*database.AIReport, error) {
+	return r.reports, nil
+}
+
+type stubAI struct {
+	ai.AIService
+	response string
+	err      error
+	req      *ai.CompleteRequest
+}
+
+func (s *stubAI) Complete(ctx context.Context, req *ai.CompleteRequest) (*ai.CompleteResult, error) {
+	s.req = req
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &ai.CompleteResult{Response: s.response, ProviderName: "test", Model: "m1"}, nil
+}
+
+// ==================== 测试用例 ====================
+
+func newTestGenerator(aiSvc ai.AIService) (*Generator, *memRepo) {
+	started := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	resolved := started.Add(45 * time.Minute)
+	detail := &aiops.IncidentDetail{
+		Incident: aiops.Incident{
+			ID: "inc-1", ClusterID: "c1", State: aiops.StateStable, Severity: "critical",
+			RootCause: "default/pod/api-7d9f8-x2k4p", PeakRisk: 0.92,
+			StartedAt: started, ResolvedAt: &resolved, AckedBy: "alice",
+		},
+		Entities: []*aiops.IncidentEntity{
+			{EntityKey: "default/pod/api-7d9f8-x2k4p", Role: "root_cause", RFinal: 0.92},
+		},
+		Timeline: []*aiops.IncidentTimeline{
+			{Timestamp: started, EventType: aiops.TimelineAnomalyDetected, EntityKey: "default/pod/api-7d9f8-x2k4p", Detail: "cpu spike"},
+			{Timestamp: started.Add(5 * time.Minute), EventType: aiops.TimelineAcknowledged, Actor: "alice"},
+		},
+	}
+	repo := &memRepo{docs: make(map[string]*database.AIOpsPostmortem)}
+	g := NewGenerator(Config{
+		Engine: &stubEngine{detail: detail},
+		Repo:   repo,
+		ReportRepo: &stubReports{reports: []*database.AIReport{{
+			Role: "analysis", RootCauseAnalysis: "内存泄漏导致 GC 抖动",
+			Recommendations: `[{"action":"为 api 设置内存上限"}]`, CreatedAt: started.Add(10 * time.Minute),
+		}}},
+		AIService: aiSvc,
+	})
+	g.now = func() time.Time { return started.Add(2 * time.Hour) }
+	return g, repo
+}
+
+func TestGenerate_TemplateWithoutProvider(t *testing.T) {
+	g, repo := newTestGenerator(nil)
+
+	pm, err := g.Generate(context.Background(), "inc-1", "bob")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if pm.Source != SourceTemplate || pm.GeneratedBy != "bob" || repo.docs["inc-1"] == nil {
+		t.Fatalf("unexpected postmortem: %+v", pm)
+	}
+	for _, want := range []string{
+		"# 事件复盘：inc-1",
+		"| 持续时间 | 45 分钟 |",
+		"| 确认人 | alice |",
+		"内存泄漏导致 GC 抖动",
+		"- [ ] 为 api 设置内存上限",
+		"（alice）",
+		"## 经验教训\n\n- _待补充_",
+	} {
+		if !strings.Contains(pm.Content, want) {
+			t.Errorf("content missing %q\n%s", want, pm.Content)
+		}
+	}
+}
+
+func TestGenerate_AINarrative(t *testing.T) {
+	aiSvc := &stubAI{response: "```json\n" +
+		`{"summary":"API 因内存泄漏不可用","impact":"","rootCause":"连接池未释放","resolution":"回滚","lessonsLearned":["补充内存告警"],"actionItems":[]}` +
+		"\n```"}
+	g, _ := newTestGenerator(aiSvc)
+
+	pm, err := g.Generate(context.Background(), "inc-1", "bob")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if aiSvc.req == nil || aiSvc.req.Role != ai.RoleAnalysis {
+		t.Fatalf("expected analysis role request, got %+v", aiSvc.req)
+	}
+	if pm.Source != SourceAI || pm.ProviderName != "test" || pm.Model != "m1" {
+		t.Errorf("unexpected metadata: %+v", pm)
+	}
+	for _, want := range []string{
+		"API 因内存泄漏不可用",
+		"连接池未释放",
+		"- 补充内存告警",
+		"共 1 个实体受影响", // 空 impact 由模板补齐
+		"- [ ] 为 api 设置内存上限",
+	} {
+		if !strings.Contains(pm.Content, want) {
+			t.Errorf("content missing %q", want)
+		}
+	}
+}
+
+func TestGenerate_AIFailureFallsBack(t *testing.T) {
+	for name, aiSvc := range map[string]*stubAI{
+		"error":   {err: errors.New("角色 analysis 未分配 Provider")},
+		"garbage": {response: "not json"},
+	} {
+		g, _ := newTestGenerator(aiSvc)
+		pm, err := g.Generate(context.Background(), "inc-1", "bob")
+		if err != nil {
+			t.Fatalf("%s: Generate: %v", name, err)
+		}
+		if pm.Source != SourceTemplate {
+			t.Errorf("%s: source = %s, want template", name, pm.Source)
+		}
+	}
+}
+
+func TestGenerate_NotFound(t *testing.T) {
+	g, _ := newTestGenerator(nil)
+	if _, err := g.Generate(context.Background(), "missing", "bob"); !errors.Is(err, aiops.ErrIncidentNotFound) {
+		t.Errorf("err = %v, want ErrIncidentNotFound", err)
+	}
+}
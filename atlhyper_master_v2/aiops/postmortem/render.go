@@ -0,0 +1,407 @@
+// atlhyper_master_v2/aiops/postmortem/render.go
+// 复盘文档渲染 — 事实部分固定由模板生成，叙述部分来自 LLM 或模板兜底
+package postmortem
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/slo"
+)
+
+// 条目上限常量 — 防止文档过长
+const (
+	maxTimelineEntries = 50
+	maxEntityEntries   = 20
+	maxReportEntries   = 3
+	maxSLOEntries      = 5
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// 待补充占位（模板兜底时的经验教训 / 改进项）
+const placeholder = "_待补充_"
+
+// Facts 复盘所需的事实数据
+type Facts struct {
+	Incident    *aiops.IncidentDetail
+	WindowStart time.Time // 事件开始
+	WindowEnd   time.Time // 事件恢复（进行中则为生成时间）
+	Reports     []*database.AIReport
+	Deploys     []*database.DeployHistory
+	Commands    []*database.CommandHistory
+	SLOImpacts  []slo.BudgetImpact
+}
+
+// Narrative 复盘叙述部分
+type Narrative struct {
+	Summary        string   `json:"summary"`
+	Impact         string   `json:"impact"`
+	RootCause      string   `json:"rootCause"`
+	Resolution     string   `json:"resolution"`
+	LessonsLearned []string `json:"lessonsLearned"`
+	ActionItems    []string `json:"actionItems"`
+}
+
+// Render 渲染复盘文档（Markdown）
+func Render(f *Facts, n *Narrative, source string, generatedAt time.Time) string {
+	inc := f.Incident
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# 事件复盘：%s\n\n", inc.ID)
+	sourceLabel := "模板生成"
+	if source == SourceAI {
+		sourceLabel = "AI 辅助撰写"
+	}
+	fmt.Fprintf(&b, "> 生成于 %s · %s · 本文档可编辑\n\n", generatedAt.Format(timeLayout), sourceLabel)
+
+	b.WriteString("## 概要\n\n")
+	b.WriteString(section(n.Summary))
+	b.WriteString(renderOverview(f))
+
+	b.WriteString("## 影响\n\n")
+	b.WriteString(section(n.Impact))
+	b.WriteString("### SLO 错误预算消耗\n\n")
+	b.WriteString(renderSLO(f.SLOImpacts))
+	b.WriteString("### 受影响实体\n\n")
+	b.WriteString(renderEntities(inc.Entities))
+
+	b.WriteString("## 时间线\n\n")
+	b.WriteString(renderTimeline(inc.Timeline))
+
+	b.WriteString("## 根因分析\n\n")
+	b.WriteString(section(n.RootCause))
+
+	b.WriteString("## 处置过程\n\n")
+	b.WriteString(section(n.Resolution))
+	b.WriteString("### 窗口内部署\n\n")
+	b.WriteString(renderDeploys(f.Deploys))
+	b.WriteString("### 窗口内运维操作\n\n")
+	b.WriteString(renderCommands(f.Commands))
+
+	if len(f.Reports) > 0 {
+		b.WriteString("## AI 分析报告\n\n")
+		b.WriteString(renderReports(f.Reports))
+	}
+
+	b.WriteString("## 经验教训\n\n")
+	b.WriteString(renderList(n.LessonsLearned, "- "))
+	b.WriteString("## 改进项\n\n")
+	b.WriteString(renderList(n.ActionItems, "- [ ] "))
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// templateNarrative 模板兜底叙述（无 AI Provider 或调用失败时使用）
+func templateNarrative(f *Facts) *Narrative {
+	inc := f.Incident
+	n := &Narrative{}
+
+	n.Summary = fmt.Sprintf("%s，集群 %s 的 %s 出现异常，严重级别 %s，峰值风险 %.0f，持续 %s。",
+		inc.StartedAt.Format(timeLayout), inc.ClusterID, orDash(inc.RootCause), inc.Severity,
+		scaleRisk(inc.PeakRisk), formatDuration(f.WindowEnd.Sub(f.WindowStart)))
+	if inc.Summary != "" {
+		n.Summary += inc.Summary
+	}
+
+	n.Impact = fmt.Sprintf("共 %d 个实体受影响。", len(inc.Entities))
+	if len(f.SLOImpacts) > 0 {
+		top := f.SLOImpacts[0]
+		n.Impact += fmt.Sprintf("SLO 影响最大的服务为 %s，事件期间消耗 %s 周期错误预算的 %.2f%%。",
+			top.ServiceKey, top.TimeRange, top.ConsumedPct)
+	}
+
+	n.RootCause = fmt.Sprintf("引擎判定的根因实体为 %s。", orDash(inc.RootCause))
+	if r := latestReport(f.Reports); r != nil && r.RootCauseAnalysis != "" {
+		n.RootCause += "\n\n" + r.RootCauseAnalysis
+	}
+
+	var steps []string
+	if inc.AckedBy != "" && inc.AckedAt != nil {
+		steps = append(steps, fmt.Sprintf("%s 于 %s 确认事件", inc.AckedBy, inc.AckedAt.Format(timeLayout)))
+	}
+	if len(f.Deploys) > 0 {
+		steps = append(steps, fmt.Sprintf("窗口内共 %d 次部署", len(f.Deploys)))
+	}
+	if len(f.Commands) > 0 {
+		steps = append(steps, fmt.Sprintf("执行 %d 次运维操作", len(f.Commands)))
+	}
+	switch {
+	case inc.FalsePositive:
+		steps = append(steps, "事件被标记为误报")
+	case inc.ResolvedAt != nil:
+		steps = append(steps, fmt.Sprintf("事件于 %s 恢复", inc.ResolvedAt.Format(timeLayout)))
+	default:
+		steps = append(steps, "事件尚未恢复")
+	}
+	n.Resolution = strings.Join(steps, "；") + "。"
+
+	n.ActionItems = reportActions(latestReport(f.Reports))
+	return n
+}
+
+// mergeNarrative 以模板叙述补齐 LLM 未给出的部分
+func mergeNarrative(ai, fallback *Narrative) *Narrative {
+	merged := *ai
+	if strings.TrimSpace(merged.Summary) == "" {
+		merged.Summary = fallback.Summary
+	}
+	if strings.TrimSpace(merged.Impact) == "" {
+		merged.Impact = fallback.Impact
+	}
+	if strings.TrimSpace(merged.RootCause) == "" {
+		merged.RootCause = fallback.RootCause
+	}
+	if strings.TrimSpace(merged.Resolution) == "" {
+		merged.Resolution = fallback.Resolution
+	}
+	if len(merged.ActionItems) == 0 {
+		merged.ActionItems = fallback.ActionItems
+	}
+	return &merged
+}
+
+// ==================== 事实部分渲染 ====================
+
+func renderOverview(f *Facts) string {
+	inc := f.Incident
+	var b strings.Builder
+	b.WriteString("| 项目 | 值 |\n|---|---|\n")
+	row := func(k, v string) { fmt.Fprintf(&b, "| %s | %s |\n", k, cell(v)) }
+	row("集群", inc.ClusterID)
+	row("严重级别", inc.Severity)
+	row("状态", string(inc.State))
+	row("根因实体", orDash(inc.RootCause))
+	row("开始时间", inc.StartedAt.Format(timeLayout))
+	if inc.ResolvedAt != nil {
+		row("恢复时间", inc.ResolvedAt.Format(timeLayout))
+	} else {
+		row("恢复时间", "进行中")
+	}
+	row("持续时间", formatDuration(f.WindowEnd.Sub(f.WindowStart)))
+	row("峰值风险", fmt.Sprintf("%.0f", scaleRisk(inc.PeakRisk)))
+	row("复发次数", fmt.Sprintf("%d", inc.Recurrence))
+	if inc.AckedBy != "" {
+		row("确认人", inc.AckedBy)
+	}
+	if inc.Assignee != "" {
+		row("处理人", inc.Assignee)
+	}
+	if inc.FalsePositive {
+		row("误报", "是")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func renderSLO(impacts []slo.BudgetImpact) string {
+	if len(impacts) == 0 {
+		return "事件窗口内无 SLO 数据或未消耗错误预算。\n\n"
+	}
+	var b strings.Builder
+	b.WriteString("| 服务 | 可用性目标 | 预算周期 | 错误率 | 燃烧率 | 消耗预算 |\n|---|---|---|---|---|---|\n")
+	for i, s := range impacts {
+		if i >= maxSLOEntries {
+			break
+		}
+		fmt.Fprintf(&b, "| %s | %.2f%% | %s | %.2f%% | %.1fx | %.2f%% |\n",
+			cell(s.ServiceKey), s.Target, s.TimeRange, s.ErrorRatio*100, s.BurnRate, s.ConsumedPct)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func renderEntities(entities []*aiops.IncidentEntity) string {
+	if len(entities) == 0 {
+		return "无。\n\n"
+	}
+	var b strings.Builder
+	b.WriteString("| 实体 | 角色 | 风险 |\n|---|---|---|\n")
+	for i, e := range entities {
+		if i >= maxEntityEntries {
+			fmt.Fprintf(&b, "\n另有 %d 个实体未列出。\n", len(entities)-maxEntityEntries)
+			break
+		}
+		fmt.Fprintf(&b, "| %s | %s | %.0f |\n", cell(e.EntityKey), e.Role, scaleRisk(e.RFinal))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func renderTimeline(timeline []*aiops.IncidentTimeline) string {
+	if len(timeline) == 0 {
+		return "无。\n\n"
+	}
+	var b strings.Builder
+	for i, t := range timeline {
+		if i >= maxTimelineEntries {
+			fmt.Fprintf(&b, "- …另有 %d 条记录未列出\n", len(timeline)-maxTimelineEntries)
+			break
+		}
+		fmt.Fprintf(&b, "- `%s` **%s**", t.Timestamp.Format(timeLayout), t.EventType)
+		if t.EntityKey != "" {
+			fmt.Fprintf(&b, " %s", t.EntityKey)
+		}
+		if t.Detail != "" {
+			fmt.Fprintf(&b, " — %s", oneLine(t.Detail))
+		}
+		if t.Actor != "" {
+			fmt.Fprintf(&b, "（%s）", t.Actor)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func renderDeploys(deploys []*database.DeployHistory) string {
+	if len(deploys) == 0 {
+		return "无。\n\n"
+	}
+	var b strings.Builder
+	for _, d := range deploys {
+		fmt.Fprintf(&b, "- `%s` %s `%s` %s（%s，%s）",
+			d.DeployedAt.Format(timeLayout), d.Path, shortSHA(d.CommitSHA), oneLine(d.CommitMessage), d.Trigger, d.Status)
+		if d.PRURL != "" {
+			fmt.Fprintf(&b, " [PR #%d](%s)", d.PRNumber, d.PRURL)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func renderCommands(commands []*database.CommandHistory) string {
+	if len(commands) == 0 {
+		return "无。\n\n"
+	}
+	var b strings.Builder
+	for _, c := range commands {
+		target := c.TargetName
+		if c.TargetNamespace != "" {
+			target = c.TargetNamespace + "/" + c.TargetName
+		}
+		fmt.Fprintf(&b, "- `%s` %s %s %s（%s，%s）\n",
+			c.CreatedAt.Format(timeLayout), c.Action, c.TargetKind, target, c.Source, c.Status)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func renderReports(reports []*database.AIReport) string {
+	var b strings.Builder
+	for i, r := range reports {
+		if i >= maxReportEntries {
+			break
+		}
+		fmt.Fprintf(&b, "### %s · %s\n\n", r.Role, r.CreatedAt.Format(timeLayout))
+		if r.Summary != "" {
+			b.WriteString(r.Summary + "\n\n")
+		}
+		if r.RootCauseAnalysis != "" {
+			b.WriteString("**根因分析**：" + r.RootCauseAnalysis + "\n\n")
+		}
+	}
+	return b.String()
+}
+
+func renderList(items []string, prefix string) string {
+	var b strings.Builder
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			b.WriteString(prefix + oneLine(item) + "\n")
+		}
+	}
+	if b.Len() == 0 {
+		b.WriteString(prefix + placeholder + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// ==================== 工具函数 ====================
+
+// latestReport 最新的 AI 报告（优先 analysis 角色）
+func latestReport(reports []*database.AIReport) *database.AIReport {
+	var latest *database.AIReport
+	for _, r := range reports {
+		if latest == nil ||
+			(r.Role == "analysis" && latest.Role != "analysis") ||
+			(r.Role == latest.Role && r.CreatedAt.After(latest.CreatedAt)) {
+			latest = r
+		}
+	}
+	return latest
+}
+
+// reportActions 从报告的处置建议提取改进项
+func reportActions(r *database.AIReport) []string {
+	if r == nil || r.Recommendations == "" {
+		return nil
+	}
+	var recs []struct {
+		Action string `json:"action"`
+	}
+	if err := json.Unmarshal([]byte(r.Recommendations), &recs); err != nil {
+		return nil
+	}
+	var actions []string
+	for _, rec := range recs {
+		if rec.Action != "" {
+			actions = append(actions, rec.Action)
+		}
+	}
+	return actions
+}
+
+func section(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		text = placeholder
+	}
+	return text + "\n\n"
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%d 秒", int(d.Seconds()))
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%d 分钟", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%d 小时 %d 分钟", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// scaleRisk 风险分数 [0,1] → 百分制
+func scaleRisk(v float64) float64 {
+	return math.Round(v * 100)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// oneLine 折叠换行，避免破坏列表结构
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// cell 表格单元格转义
+func cell(s string) string {
+	return strings.ReplaceAll(oneLine(s), "|", "\\|")
+}
@@ -0,0 +1,117 @@
+// atlhyper_master_v2/aiops/replay/cli.go
+// Master 二进制的 replay 子命令:
+//
+//	atlhyper_master_v2 replay [-cluster ID] [-labels labels.json] [-policies policies.json] [-tolerance 5m] [-json] recording.jsonl[.gz] ...
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops/policy"
+)
+
+// RunCLI 执行 replay 子命令（args 不含子命令名）
+func RunCLI(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	clusterID := fs.String("cluster", "", "replay only this cluster (required when the recording has several)")
+	labelsPath := fs.String("labels", "", "labelled incident file (JSON array) for precision / recall")
+	policiesPath := fs.String("policies", "", "AIOps policy file (JSON array, same format as the policy API) to replay with")
+	tolerance := fs.Duration("tolerance", DefaultTolerance, "time tolerance when matching incidents to labels")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: atlhyper_master_v2 replay [flags] recording.jsonl[.gz] ...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no recording file given")
+	}
+
+	snapshots, err := ReadFiles(fs.Args()...)
+	if err != nil {
+		return err
+	}
+	opts := Options{ClusterID: *clusterID, Tolerance: *tolerance}
+	if *labelsPath != "" {
+		if opts.Labels, err = LoadLabels(*labelsPath); err != nil {
+			return err
+		}
+	}
+	if *policiesPath != "" {
+		if opts.Policies, err = policy.LoadFile(*policiesPath); err != nil {
+			return err
+		}
+	}
+
+	report, err := Run(snapshots, opts)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printReport(stdout, report)
+	return nil
+}
+
+// printReport 文本格式输出
+func printReport(w io.Writer, report *Report) {
+	fmt.Fprintf(w, "Cluster %s: %d snapshots, %s → %s (%s)\n\n", report.ClusterID, report.Snapshots,
+		formatTime(report.From), formatTime(report.To), report.To.Sub(report.From).Round(time.Second))
+
+	fmt.Fprintf(w, "Incidents: %d\n", len(report.Incidents))
+	if len(report.Incidents) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tENTITY\tMAX STATE\tSEVERITY\tPEAK RISK\tSTARTED\tESCALATED\tRESOLVED")
+		for _, inc := range report.Incidents {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.2f\t%s\t%s\t%s\n", inc.ID, inc.EntityKey, inc.MaxState, inc.Severity,
+				inc.PeakRisk, formatTime(inc.StartedAt), formatTimePtr(inc.EscalatedAt), formatTimePtr(inc.ResolvedAt))
+		}
+		tw.Flush()
+	}
+
+	ev := report.Evaluation
+	if ev == nil {
+		return
+	}
+	fmt.Fprintf(w, "\nLabels: %d, detected %d\n", ev.Labels, ev.Detected)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LABEL\tENTITY\tFROM\tINCIDENTS\tDELAY")
+	for _, m := range ev.Matches {
+		delay := "missed"
+		if m.Delay != nil {
+			delay = (time.Duration(*m.Delay) * time.Second).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", m.Label.Name, m.Label.Entity, formatTime(m.Label.From), len(m.Incidents), delay)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nPrecision: %.3f (%d/%d)  Recall: %.3f (%d/%d)  Mean delay: %s\n",
+		ev.Precision, ev.TruePositives, ev.Incidents, ev.Recall, ev.Detected, ev.Labels,
+		(time.Duration(ev.MeanDelay) * time.Second).String())
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return formatTime(*t)
+}
@@ -0,0 +1,76 @@
+// atlhyper_master_v2/aiops/replay/evaluate.go
+// 回放结果评估: 事件与标注按实体 + 时间区间匹配
+package replay
+
+import "time"
+
+// Evaluation 精确率 / 召回率
+type Evaluation struct {
+	Labels         int     `json:"labels"`
+	Incidents      int     `json:"incidents"`
+	TruePositives  int     `json:"truePositives"`  // 命中任一标注的事件数
+	FalsePositives int     `json:"falsePositives"` // 未命中任何标注的事件数
+	Detected       int     `json:"detected"`       // 被至少一个事件命中的标注数
+	Precision      float64 `json:"precision"`      // TruePositives / Incidents
+	Recall         float64 `json:"recall"`         // Detected / Labels
+	// MeanDelay 命中标注的首个事件相对故障开始的平均延迟（秒，可为负: 提前发现）
+	MeanDelay float64       `json:"meanDelaySeconds"`
+	Matches   []*LabelMatch `json:"matches"`
+}
+
+// LabelMatch 单个标注的匹配结果
+type LabelMatch struct {
+	Label     Label    `json:"label"`
+	Incidents []string `json:"incidents"`              // 命中的事件 ID
+	Delay     *float64 `json:"delaySeconds,omitempty"` // 首个事件相对故障开始的延迟（秒）
+}
+
+// Evaluate 以标注评估回放事件
+// 事件区间 [StartedAt, ResolvedAt 或 replayEnd] 与标注区间（放宽 tolerance）重叠且实体匹配即为命中
+func Evaluate(incidents []*Incident, labels []Label, replayEnd time.Time, tolerance time.Duration) *Evaluation {
+	ev := &Evaluation{
+		Labels:    len(labels),
+		Incidents: len(incidents),
+		Matches:   make([]*LabelMatch, len(labels)),
+	}
+	hit := make([]bool, len(incidents))
+	var delaySum float64
+
+	for i := range labels {
+		label := &labels[i]
+		match := &LabelMatch{Label: *label, Incidents: []string{}}
+		for j, inc := range incidents {
+			if !label.matchesEntity(inc.EntityKey) || !label.overlaps(inc.StartedAt, inc.end(replayEnd), tolerance) {
+				continue
+			}
+			hit[j] = true
+			match.Incidents = append(match.Incidents, inc.ID)
+			delay := inc.StartedAt.Sub(label.From).Seconds()
+			if match.Delay == nil || delay < *match.Delay {
+				match.Delay = &delay
+			}
+		}
+		if match.Delay != nil {
+			ev.Detected++
+			delaySum += *match.Delay
+		}
+		ev.Matches[i] = match
+	}
+
+	for _, h := range hit {
+		if h {
+			ev.TruePositives++
+		}
+	}
+	ev.FalsePositives = len(incidents) - ev.TruePositives
+	if len(incidents) > 0 {
+		ev.Precision = float64(ev.TruePositives) / float64(len(incidents))
+	}
+	if len(labels) > 0 {
+		ev.Recall = float64(ev.Detected) / float64(len(labels))
+	}
+	if ev.Detected > 0 {
+		ev.MeanDelay = delaySum / float64(ev.Detected)
+	}
+	return ev
+}
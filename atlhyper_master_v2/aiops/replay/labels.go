@@ -0,0 +1,62 @@
+// atlhyper_master_v2/aiops/replay/labels.go
+// 标注文件: 已知故障列表（JSON 数组），用于计算回放结果的精确率 / 召回率
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+)
+
+// Label 一次已知故障
+type Label struct {
+	Name string `json:"name,omitempty"`
+	// Entity 故障实体: 完整 entityKey、同工作负载的 Pod（按 EntityPattern 匹配）或 glob（如 "default/pod/api-*"）
+	Entity string    `json:"entity"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+// LoadLabels 读取标注文件
+func LoadLabels(file string) ([]Label, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var labels []Label
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return nil, fmt.Errorf("parse labels %s: %w", file, err)
+	}
+	for i, l := range labels {
+		if l.Entity == "" || l.From.IsZero() {
+			return nil, fmt.Errorf("label %d: entity and from are required", i+1)
+		}
+		if l.To.IsZero() {
+			labels[i].To = l.From
+		}
+		if labels[i].To.Before(l.From) {
+			return nil, fmt.Errorf("label %d: to is before from", i+1)
+		}
+	}
+	return labels, nil
+}
+
+// matchesEntity 事件实体是否命中标注实体
+func (l *Label) matchesEntity(entityKey string) bool {
+	if l.Entity == entityKey {
+		return true
+	}
+	if ok, _ := path.Match(l.Entity, entityKey); ok {
+		return true
+	}
+	return aiops.EntityPattern(l.Entity) == aiops.EntityPattern(entityKey)
+}
+
+// overlaps 事件区间与标注区间（两侧各放宽 tolerance）是否重叠
+func (l *Label) overlaps(from, to time.Time, tolerance time.Duration) bool {
+	return !from.After(l.To.Add(tolerance)) && !to.Before(l.From.Add(-tolerance))
+}
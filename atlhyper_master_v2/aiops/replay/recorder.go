@@ -0,0 +1,71 @@
+// atlhyper_master_v2/aiops/replay/recorder.go
+// 在线快照录制: 按集群、按天追加写入 <dir>/<clusterID>-<YYYYMMDD>.jsonl
+package replay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"AtlHyper/common/logger"
+	"AtlHyper/model_v3/cluster"
+)
+
+var log = logger.Module("AIOps.Replay")
+
+// Recorder 在线快照录制器
+type Recorder struct {
+	dir      string
+	interval time.Duration
+
+	mu   sync.Mutex
+	last map[string]time.Time // clusterID -> 上次录制的采集时间
+}
+
+// NewRecorder 创建录制器（目录不存在时自动创建）
+// interval 为同一集群两次录制的最小间隔
+func NewRecorder(dir string, interval time.Duration) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create record dir: %w", err)
+	}
+	return &Recorder{
+		dir:      dir,
+		interval: interval,
+		last:     make(map[string]time.Time),
+	}, nil
+}
+
+// Record 按间隔追加一个快照（错误仅记录日志，不影响主流程）
+func (r *Recorder) Record(snap *cluster.ClusterSnapshot) {
+	if snap == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if last, ok := r.last[snap.ClusterID]; ok && snap.FetchedAt.Sub(last) < r.interval {
+		return
+	}
+	if err := r.append(snap); err != nil {
+		log.Warn("快照录制失败", "cluster", snap.ClusterID, "err", err)
+		return
+	}
+	r.last[snap.ClusterID] = snap.FetchedAt
+}
+
+// append 追加写入当天的录制文件（调用方持有锁）
+func (r *Recorder) append(snap *cluster.ClusterSnapshot) error {
+	name := fmt.Sprintf("%s-%s.jsonl", snap.ClusterID, snap.FetchedAt.UTC().Format("20060102"))
+	f, err := os.OpenFile(filepath.Join(r.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := WriteSnapshots(f, snap); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,74 @@
+// atlhyper_master_v2/aiops/replay/recording.go
+// 快照录制文件: 每行一个 ClusterSnapshot（JSON Lines），可选 Gzip 压缩
+//
+// 录制来源:
+//   - 在线录制: 配置 MASTER_AIOPS_RECORD_DIR 后 Master 按间隔追加写入（含 OTel）
+//   - 快照历史: GET /api/v2/snapshots/export 导出 DataHub 中的历史快照（不含 OTel）
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"AtlHyper/common"
+	"AtlHyper/model_v3/cluster"
+)
+
+// WriteSnapshots 以 JSON Lines 格式写出快照
+func WriteSnapshots(w io.Writer, snapshots ...*cluster.ClusterSnapshot) error {
+	enc := json.NewEncoder(w)
+	for _, snap := range snapshots {
+		if err := enc.Encode(snap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSnapshots 读取 JSON Lines 快照流（自动识别 Gzip）
+func ReadSnapshots(r io.Reader) ([]*cluster.ClusterSnapshot, error) {
+	reader, err := common.MaybeGunzipReaderAuto(io.NopCloser(r), "")
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var snapshots []*cluster.ClusterSnapshot
+	dec := json.NewDecoder(reader)
+	for {
+		var snap cluster.ClusterSnapshot
+		err := dec.Decode(&snap)
+		if errors.Is(err, io.EOF) {
+			return snapshots, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode snapshot %d: %w", len(snapshots)+1, err)
+		}
+		snapshots = append(snapshots, &snap)
+	}
+}
+
+// ReadFiles 读取多个录制文件，按采集时间升序合并
+func ReadFiles(paths ...string) ([]*cluster.ClusterSnapshot, error) {
+	var all []*cluster.ClusterSnapshot
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		snapshots, err := ReadSnapshots(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		all = append(all, snapshots...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].FetchedAt.Before(all[j].FetchedAt)
+	})
+	return all, nil
+}
@@ -0,0 +1,46 @@
+// atlhyper_master_v2/aiops/replay/replaytest/replaytest.go
+// Go 测试辅助: 在单元测试中回放录制文件并断言检测质量
+//
+//	report := replaytest.Run(t, replay.Options{}, "testdata/labels.json", "testdata/oom.jsonl.gz")
+//	replaytest.RequireQuality(t, report, 0.8, 1.0)
+package replaytest
+
+import (
+	"testing"
+
+	"AtlHyper/atlhyper_master_v2/aiops/replay"
+)
+
+// Run 读取录制文件（与可选的标注文件）执行回放，任何错误都会使测试失败
+func Run(tb testing.TB, opts replay.Options, labelsPath string, recordings ...string) *replay.Report {
+	tb.Helper()
+	snapshots, err := replay.ReadFiles(recordings...)
+	if err != nil {
+		tb.Fatalf("read recordings: %v", err)
+	}
+	if labelsPath != "" {
+		if opts.Labels, err = replay.LoadLabels(labelsPath); err != nil {
+			tb.Fatalf("load labels: %v", err)
+		}
+	}
+	report, err := replay.Run(snapshots, opts)
+	if err != nil {
+		tb.Fatalf("replay: %v", err)
+	}
+	return report
+}
+
+// RequireQuality 断言精确率 / 召回率不低于给定值
+func RequireQuality(tb testing.TB, report *replay.Report, minPrecision, minRecall float64) {
+	tb.Helper()
+	ev := report.Evaluation
+	if ev == nil {
+		tb.Fatal("report has no evaluation (no labels given)")
+	}
+	if ev.Precision < minPrecision {
+		tb.Errorf("precision %.3f < %.3f (%d of %d incidents matched a label)", ev.Precision, minPrecision, ev.TruePositives, ev.Incidents)
+	}
+	if ev.Recall < minRecall {
+		tb.Errorf("recall %.3f < %.3f (%d of %d labels detected)", ev.Recall, minRecall, ev.Detected, ev.Labels)
+	}
+}
@@ -0,0 +1,194 @@
+// atlhyper_master_v2/aiops/replay/runner.go
+// 离线回放: 录制快照按采集时间依次经 baseline → risk.Scorer → statemachine，
+// 收集本应创建的事件，并与标注文件对比计算精确率 / 召回率
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/aiops/core"
+	"AtlHyper/atlhyper_master_v2/aiops/policy"
+	"AtlHyper/atlhyper_master_v2/aiops/risk"
+	"AtlHyper/model_v3/cluster"
+)
+
+// DefaultTolerance 事件与标注匹配时两侧放宽的时间
+const DefaultTolerance = 5 * time.Minute
+
+// Options 回放选项
+type Options struct {
+	ClusterID  string           // 仅回放指定集群（录制中只有一个集群时可为空）
+	RiskConfig *risk.RiskConfig // nil = 默认风险配置
+	Policies   []*policy.Policy // AIOps 策略（叠加在 RiskConfig 与默认阈值之上）
+	Labels     []Label          // 为空则不计算精确率 / 召回率
+	Tolerance  time.Duration    // 0 = DefaultTolerance
+}
+
+// Incident 回放中创建的事件
+type Incident struct {
+	ID          string            `json:"id"`
+	EntityKey   string            `json:"entityKey"`
+	State       aiops.EntityState `json:"state"`    // 回放结束时的状态
+	MaxState    aiops.EntityState `json:"maxState"` // 到达过的最高状态（warning / incident）
+	Severity    string            `json:"severity"` // 按峰值风险
+	PeakRisk    float64           `json:"peakRisk"`
+	Recurrences int               `json:"recurrences"`
+	StartedAt   time.Time         `json:"startedAt"`
+	EscalatedAt *time.Time        `json:"escalatedAt,omitempty"`
+	RecoveredAt *time.Time        `json:"recoveredAt,omitempty"`
+	ResolvedAt  *time.Time        `json:"resolvedAt,omitempty"`
+}
+
+// end 事件结束时间（未关闭则为回放结束时间）
+func (inc *Incident) end(replayEnd time.Time) time.Time {
+	if inc.ResolvedAt != nil {
+		return *inc.ResolvedAt
+	}
+	return replayEnd
+}
+
+// Report 回放结果
+type Report struct {
+	ClusterID  string      `json:"clusterId"`
+	From       time.Time   `json:"from"`
+	To         time.Time   `json:"to"`
+	Snapshots  int         `json:"snapshots"`
+	Incidents  []*Incident `json:"incidents"`
+	Evaluation *Evaluation `json:"evaluation,omitempty"`
+}
+
+// Run 回放快照序列（须为同一集群，或通过 Options.ClusterID 过滤）
+func Run(snapshots []*cluster.ClusterSnapshot, opts Options) (*Report, error) {
+	clusterID, err := resolveCluster(snapshots, opts.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &collector{byID: make(map[string]*Incident)}
+	pipeline := core.NewPipeline(opts.RiskConfig, rec)
+	if len(opts.Policies) > 0 {
+		policies, err := policy.NewStatic(opts.Policies, opts.RiskConfig)
+		if err != nil {
+			return nil, err
+		}
+		pipeline.SetPolicies(policies)
+	}
+	report := &Report{ClusterID: clusterID}
+	for _, snap := range snapshots {
+		if snap.ClusterID != clusterID {
+			continue
+		}
+		pipeline.Feed(snap)
+		if report.Snapshots == 0 {
+			report.From = snap.FetchedAt
+		}
+		report.To = pipeline.Now()
+		report.Snapshots++
+	}
+
+	report.Incidents = rec.incidents
+	if report.Incidents == nil {
+		report.Incidents = []*Incident{}
+	}
+	if len(opts.Labels) > 0 {
+		tolerance := opts.Tolerance
+		if tolerance <= 0 {
+			tolerance = DefaultTolerance
+		}
+		report.Evaluation = Evaluate(report.Incidents, opts.Labels, report.To, tolerance)
+	}
+	return report, nil
+}
+
+// resolveCluster 确定回放的集群
+func resolveCluster(snapshots []*cluster.ClusterSnapshot, want string) (string, error) {
+	seen := make(map[string]bool)
+	for _, snap := range snapshots {
+		seen[snap.ClusterID] = true
+	}
+	if want != "" {
+		if !seen[want] {
+			return "", fmt.Errorf("cluster %q not found in recording", want)
+		}
+		return want, nil
+	}
+	if len(seen) != 1 {
+		ids := make([]string, 0, len(seen))
+		for id := range seen {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		return "", fmt.Errorf("recording contains %d clusters %v, select one", len(ids), ids)
+	}
+	for id := range seen {
+		return id, nil
+	}
+	return "", nil
+}
+
+// ==================== 状态机回调 ====================
+
+// collector 收集状态机转换（实现 statemachine.TransitionCallback，代替事件存储）
+type collector struct {
+	incidents []*Incident
+	byID      map[string]*Incident
+}
+
+func (c *collector) OnWarningCreated(ctx context.Context, clusterID, entityKey string, risk *aiops.EntityRisk, now time.Time) string {
+	inc := &Incident{
+		ID:        fmt.Sprintf("replay-%d", len(c.incidents)+1),
+		EntityKey: entityKey,
+		State:     aiops.StateWarning,
+		MaxState:  aiops.StateWarning,
+		StartedAt: now,
+	}
+	c.observe(inc, risk)
+	c.incidents = append(c.incidents, inc)
+	c.byID[inc.ID] = inc
+	return inc.ID
+}
+
+func (c *collector) OnStateEscalated(ctx context.Context, incidentID string, state aiops.EntityState, risk *aiops.EntityRisk, now time.Time) {
+	if inc := c.byID[incidentID]; inc != nil {
+		inc.State, inc.MaxState = state, state
+		if inc.EscalatedAt == nil {
+			inc.EscalatedAt = &now
+		}
+		c.observe(inc, risk)
+	}
+}
+
+func (c *collector) OnRecoveryStarted(ctx context.Context, incidentID string, risk *aiops.EntityRisk, now time.Time) {
+	if inc := c.byID[incidentID]; inc != nil {
+		inc.State = aiops.StateRecovery
+		inc.RecoveredAt = &now
+	}
+}
+
+func (c *collector) OnRecurrence(ctx context.Context, incidentID string, risk *aiops.EntityRisk, now time.Time) {
+	if inc := c.byID[incidentID]; inc != nil {
+		inc.State = aiops.StateWarning
+		inc.Recurrences++
+		inc.RecoveredAt = nil
+		c.observe(inc, risk)
+	}
+}
+
+func (c *collector) OnStable(ctx context.Context, incidentID string, entityKey string, now time.Time) {
+	if inc := c.byID[incidentID]; inc != nil {
+		inc.State = aiops.StateStable
+		inc.ResolvedAt = &now
+	}
+}
+
+// observe 更新峰值风险与严重度
+func (c *collector) observe(inc *Incident, risk *aiops.EntityRisk) {
+	if risk != nil && risk.RFinal > inc.PeakRisk {
+		inc.PeakRisk = risk.RFinal
+		inc.Severity = aiops.SeverityFromRisk(risk.RFinal)
+	}
+}
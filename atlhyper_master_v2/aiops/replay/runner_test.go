@@ -0,0 +1,222 @@
+// atlhyper_master_v2/aiops/replay/runner_test.go
+package replay_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/aiops/replay"
+	"AtlHyper/atlhyper_master_v2/aiops/replay/replaytest"
+	"AtlHyper/model_v3/cluster"
+)
+
+var (
+	start      = time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	faultFrom  = start.Add(60 * time.Minute)
+	faultTo    = start.Add(90 * time.Minute)
+	crashPod   = "api-7c9fd8b6f5-x2kqz"
+	crashKey   = "default/pod/" + crashPod
+	healthyPod = "web-5d8c7b9f4d-q7r2m"
+)
+
+// recording 生成 3 小时、每 30 秒一份的快照: api Pod 在 [60min, 90min) 处于 CrashLoopBackOff
+func recording() []*cluster.ClusterSnapshot {
+	var snaps []*cluster.ClusterSnapshot
+	for at := start; at.Before(start.Add(3 * time.Hour)); at = at.Add(30 * time.Second) {
+		api := container("running", "", true, 0, "", "")
+		switch {
+		case !at.Before(faultFrom) && at.Before(faultTo):
+			api = container("waiting", "CrashLoopBackOff", false, 5, "Error", "")
+		case !at.Before(faultTo):
+			api = container("running", "", true, 5, "Error", faultTo.Format(time.RFC3339))
+		}
+		snaps = append(snaps, &cluster.ClusterSnapshot{
+			ClusterID: "prod",
+			FetchedAt: at,
+			Pods: []cluster.Pod{
+				pod(crashPod, api),
+				pod(healthyPod, container("running", "", true, 0, "", "")),
+			},
+		})
+	}
+	return snaps
+}
+
+func pod(name string, c cluster.PodContainerDetail) cluster.Pod {
+	return cluster.Pod{
+		Summary:    cluster.PodSummary{Name: name, Namespace: "default"},
+		Status:     cluster.PodStatus{Phase: "Running", Restarts: c.RestartCount},
+		Containers: []cluster.PodContainerDetail{c},
+	}
+}
+
+func container(state, reason string, ready bool, restarts int32, lastReason, lastTime string) cluster.PodContainerDetail {
+	return cluster.PodContainerDetail{
+		Name: "app", State: state, StateReason: reason, Ready: ready, RestartCount: restarts,
+		LastTerminationReason: lastReason, LastTerminationTime: lastTime,
+	}
+}
+
+func TestRun_CrashLoopIncident(t *testing.T) {
+	report, err := replay.Run(recording(), replay.Options{
+		Labels: []replay.Label{{Name: "api crashloop", Entity: "default/pod/api-*", From: faultFrom, To: faultTo}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ClusterID != "prod" || report.Snapshots != 360 {
+		t.Fatalf("unexpected report header: %+v", report)
+	}
+	if len(report.Incidents) != 1 {
+		t.Fatalf("应只创建 1 个事件, got %d: %+v", len(report.Incidents), report.Incidents)
+	}
+
+	inc := report.Incidents[0]
+	if inc.EntityKey != crashKey || inc.MaxState != aiops.StateIncident {
+		t.Errorf("unexpected incident: %+v", inc)
+	}
+	// Healthy→Warning 需风险持续 2 分钟，时间按快照推进而非墙钟
+	if delay := inc.StartedAt.Sub(faultFrom); delay < 2*time.Minute || delay > 3*time.Minute {
+		t.Errorf("事件应在故障开始后约 2 分钟创建, got %s", delay)
+	}
+	if inc.EscalatedAt == nil || inc.EscalatedAt.Sub(inc.StartedAt) < 5*time.Minute {
+		t.Errorf("Warning→Incident 需持续 5 分钟, got %v", inc.EscalatedAt)
+	}
+	if inc.RecoveredAt == nil || inc.RecoveredAt.Before(faultTo) {
+		t.Errorf("故障结束后应进入 Recovery, got %v", inc.RecoveredAt)
+	}
+
+	ev := report.Evaluation
+	if ev == nil || ev.Precision != 1 || ev.Recall != 1 || ev.Detected != 1 {
+		t.Fatalf("unexpected evaluation: %+v", ev)
+	}
+	if d := ev.Matches[0].Delay; d == nil || *d != inc.StartedAt.Sub(faultFrom).Seconds() {
+		t.Errorf("unexpected delay: %v", d)
+	}
+}
+
+func TestRun_RequiresClusterSelection(t *testing.T) {
+	snaps := recording()
+	other := *snaps[0]
+	other.ClusterID = "staging"
+	snaps = append(snaps, &other)
+
+	if _, err := replay.Run(snaps, replay.Options{}); err == nil || !strings.Contains(err.Error(), "2 clusters") {
+		t.Fatalf("多集群录制应要求指定集群, got %v", err)
+	}
+	report, err := replay.Run(snaps, replay.Options{ClusterID: "prod"})
+	if err != nil || report.Snapshots != 360 {
+		t.Fatalf("指定集群后应只回放该集群: %v %+v", err, report)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	end := start.Add(3 * time.Hour)
+	resolved := start.Add(40 * time.Minute)
+	incidents := []*replay.Incident{
+		{ID: "replay-1", EntityKey: "default/pod/api-7c9fd8b6f5-m4n8z", StartedAt: start.Add(33 * time.Minute), ResolvedAt: &resolved},
+		{ID: "replay-2", EntityKey: "default/pod/web-5d8c7b9f4d-q7r2m", StartedAt: start.Add(2 * time.Hour)},
+	}
+	labels := []replay.Label{
+		{Name: "api", Entity: crashKey, From: start.Add(30 * time.Minute), To: start.Add(35 * time.Minute)},
+		{Name: "db", Entity: "_cluster/node/db-1", From: start.Add(time.Hour), To: start.Add(time.Hour)},
+	}
+
+	ev := replay.Evaluate(incidents, labels, end, replay.DefaultTolerance)
+	if ev.TruePositives != 1 || ev.FalsePositives != 1 || ev.Detected != 1 {
+		t.Fatalf("unexpected evaluation: %+v", ev)
+	}
+	if ev.Precision != 0.5 || ev.Recall != 0.5 || ev.MeanDelay != 180 {
+		t.Errorf("precision/recall/delay = %.2f/%.2f/%.0f", ev.Precision, ev.Recall, ev.MeanDelay)
+	}
+	if len(ev.Matches[1].Incidents) != 0 || ev.Matches[1].Delay != nil {
+		t.Errorf("db 标注应未命中: %+v", ev.Matches[1])
+	}
+}
+
+func TestRecordingRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	snaps := recording()
+
+	// 在线录制: 间隔 5 分钟 → 3 小时 36 份
+	rec, err := replay.NewRecorder(dir, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range snaps {
+		rec.Record(s)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "prod-*.jsonl"))
+	if len(files) != 1 {
+		t.Fatalf("应按集群和日期生成 1 个录制文件, got %v", files)
+	}
+	recorded, err := replay.ReadFiles(files...)
+	if err != nil || len(recorded) != 36 {
+		t.Fatalf("recorded %d snapshots, err=%v", len(recorded), err)
+	}
+
+	// Gzip 录制（快照历史导出格式）
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := replay.WriteSnapshots(zw, snaps...); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	decoded, err := replay.ReadSnapshots(&buf)
+	if err != nil || len(decoded) != len(snaps) || !decoded[10].FetchedAt.Equal(snaps[10].FetchedAt) {
+		t.Fatalf("gzip round trip failed: %d snapshots, err=%v", len(decoded), err)
+	}
+}
+
+func TestCLIAndTestHelper(t *testing.T) {
+	dir := t.TempDir()
+	recPath := filepath.Join(dir, "prod.jsonl")
+	labelsPath := filepath.Join(dir, "labels.json")
+
+	f, err := os.Create(recPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := replay.WriteSnapshots(f, recording()...); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	labels, _ := json.Marshal([]replay.Label{{Name: "api crashloop", Entity: crashKey, From: faultFrom, To: faultTo}})
+	if err := os.WriteFile(labelsPath, labels, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report := replaytest.Run(t, replay.Options{}, labelsPath, recPath)
+	replaytest.RequireQuality(t, report, 1, 1)
+
+	var stdout, stderr bytes.Buffer
+	if err := replay.RunCLI([]string{"-labels", labelsPath, recPath}, &stdout, &stderr); err != nil {
+		t.Fatalf("RunCLI: %v (%s)", err, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"Incidents: 1", crashKey, "Precision: 1.000 (1/1)", "Recall: 1.000 (1/1)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("CLI output missing %q:\n%s", want, out)
+		}
+	}
+
+	stdout.Reset()
+	if err := replay.RunCLI([]string{"-json", recPath}, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	var decoded replay.Report
+	if err := json.Unmarshal(stdout.Bytes(), &decoded); err != nil || len(decoded.Incidents) != 1 || decoded.Evaluation != nil {
+		t.Fatalf("unexpected JSON report: %v %s", err, stdout.String())
+	}
+
+	if err := replay.RunCLI(nil, &stdout, &stderr); err == nil {
+		t.Error("缺少录制文件应报错")
+	}
+}
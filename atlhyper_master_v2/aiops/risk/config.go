@@ -2,6 +2,8 @@
 // 风险评分权重配置
 package risk
 
+import "AtlHyper/atlhyper_master_v2/aiops"
+
 // MetricChannel 指标通道类型
 type MetricChannel int
 
@@ -11,10 +13,13 @@ const (
 	ChannelBoth                               // 双通道 (同时参与统计和确定性)
 )
 
-// MetricConfig 指标配置 (权重 + 通道)
+// MetricConfig 指标配置 (权重 + 通道 + 基线模式)
 type MetricConfig struct {
-	Weight  float64
-	Channel MetricChannel
+	Weight   float64
+	Channel  MetricChannel
+	Baseline aiops.BaselineMode // 统计通道基线模式，空 = EMA
+	Detector string             // 统计通道检测器（ema / mad / cusum / bocpd），空 = ema
+	Disabled bool               // 不参与局部风险（策略关闭指标，如批处理命名空间容忍重启）
 }
 
 // RiskConfig 风险评分配置
@@ -42,13 +47,13 @@ func DefaultRiskConfig() *RiskConfig {
 				// Basic: SLO 指标
 				"error_rate":   {Weight: 0.10, Channel: ChannelStatistical},
 				"avg_latency":  {Weight: 0.05, Channel: ChannelStatistical},
-				"request_rate": {Weight: 0.05, Channel: ChannelStatistical},
+				"request_rate": {Weight: 0.05, Channel: ChannelStatistical, Baseline: aiops.BaselineSeasonal},
 				// Enhanced: APM 统计指标
 				"apm_error_rate":   {Weight: 0.15, Channel: ChannelStatistical},
 				"apm_p99_latency":  {Weight: 0.10, Channel: ChannelStatistical},
-				"apm_rps":          {Weight: 0.05, Channel: ChannelStatistical},
+				"apm_rps":          {Weight: 0.05, Channel: ChannelStatistical, Baseline: aiops.BaselineSeasonal},
 				// Enhanced: Log 统计指标
-				"log_error_count": {Weight: 0.05, Channel: ChannelStatistical},
+				"log_error_count": {Weight: 0.05, Channel: ChannelStatistical, Detector: "mad"},
 				"log_warn_count":  {Weight: 0.05, Channel: ChannelStatistical},
 				// Enhanced: 确定性异常（阈值直注，绕过冷启动）
 				"apm_high_error_rate":   {Weight: 0.20, Channel: ChannelDeterministic},
@@ -63,11 +68,12 @@ func DefaultRiskConfig() *RiskConfig {
 				"container_anomaly":      {Weight: 0.25, Channel: ChannelDeterministic},
 				"critical_event":         {Weight: 0.15, Channel: ChannelDeterministic},
 				"deployment_impact":      {Weight: 0.25, Channel: ChannelDeterministic},
+				"hpa_saturated":          {Weight: 0.15, Channel: ChannelDeterministic},
 			},
 			"node": {
 				// Basic: K8s Metrics Server
 				"cpu_usage":       {Weight: 0.20, Channel: ChannelStatistical},
-				"memory_usage":    {Weight: 0.20, Channel: ChannelStatistical},
+				"memory_usage":    {Weight: 0.20, Channel: ChannelStatistical, Detector: "cusum"},
 				"memory_pressure": {Weight: 0.10, Channel: ChannelDeterministic},
 				"disk_pressure":   {Weight: 0.05, Channel: ChannelDeterministic},
 				"pid_pressure":    {Weight: 0.05, Channel: ChannelDeterministic},
@@ -82,7 +88,7 @@ func DefaultRiskConfig() *RiskConfig {
 				"avg_latency": {Weight: 0.50, Channel: ChannelStatistical},
 			},
 			"logs": {
-				"log_error_count":  {Weight: 0.40, Channel: ChannelStatistical},
+				"log_error_count":  {Weight: 0.40, Channel: ChannelStatistical, Detector: "mad"},
 				"log_warn_count":   {Weight: 0.20, Channel: ChannelStatistical},
 				"log_error_spike":  {Weight: 0.40, Channel: ChannelDeterministic},
 			},
@@ -105,6 +111,19 @@ func (c *RiskConfig) GetWeights(entityType string) map[string]float64 {
 	return weights
 }
 
+// GetBaselineMode 获取指标的基线模式（未配置为 EMA）
+func (c *RiskConfig) GetBaselineMode(entityType, metricName string) aiops.BaselineMode {
+	if cfg, ok := c.GetMetricConfigs(entityType)[metricName]; ok && cfg.Baseline != "" {
+		return cfg.Baseline
+	}
+	return aiops.BaselineEMA
+}
+
+// GetDetector 获取指标的检测器名称（未配置为空，由 baseline 使用默认检测器）
+func (c *RiskConfig) GetDetector(entityType, metricName string) string {
+	return c.GetMetricConfigs(entityType)[metricName].Detector
+}
+
 // GetMetricConfigs 获取指定实体类型的指标配置
 func (c *RiskConfig) GetMetricConfigs(entityType string) map[string]MetricConfig {
 	if m, ok := c.MetricConfigs[entityType]; ok {
@@ -26,6 +26,11 @@ func breadthBoost(n int) float64 {
 
 // ComputeLocalRisks 计算每个实体的局部风险分数 (双通道)
 func ComputeLocalRisks(anomalies []*aiops.AnomalyResult, config *RiskConfig) map[string]float64 {
+	return ComputeLocalRisksWith(anomalies, func(string) *RiskConfig { return config })
+}
+
+// ComputeLocalRisksWith 同 ComputeLocalRisks，指标配置按实体解析（命名空间 / 实体类型策略覆盖）
+func ComputeLocalRisksWith(anomalies []*aiops.AnomalyResult, configFor func(entityKey string) *RiskConfig) map[string]float64 {
 	// 按 entityKey 分组
 	byEntity := map[string][]*aiops.AnomalyResult{}
 	for _, a := range anomalies {
@@ -35,7 +40,7 @@ func ComputeLocalRisks(anomalies []*aiops.AnomalyResult, config *RiskConfig) map
 	localRisks := make(map[string]float64, len(byEntity))
 	for entityKey, results := range byEntity {
 		entityType := aiops.ExtractEntityType(entityKey)
-		metricConfigs := config.GetMetricConfigs(entityType)
+		metricConfigs := configFor(entityKey).GetMetricConfigs(entityType)
 
 		var channel1 float64 // 统计通道: Σ(w_i × score_i)
 		var maxScore float64 // 确定性通道: max(score_i)
@@ -51,6 +56,9 @@ func ComputeLocalRisks(anomalies []*aiops.AnomalyResult, config *RiskConfig) map
 				// 未配置的指标: 默认 statistical, weight=0.1
 				mc = MetricConfig{Weight: 0.1, Channel: ChannelStatistical}
 			}
+			if mc.Disabled {
+				continue
+			}
 
 			// 通道 1: statistical + both 参与
 			if mc.Channel == ChannelStatistical || mc.Channel == ChannelBoth {
@@ -13,11 +13,13 @@ import (
 // Scorer 风险评分引擎
 type Scorer struct {
 	config       *RiskConfig
+	resolve      ConfigResolver // 可选，nil = 所有实体使用 config
 	mu           sync.RWMutex
 	results      map[string]*aiops.ClusterRisk                // clusterID -> ClusterRisk
 	entityMap    map[string]map[string]*aiops.EntityRisk      // clusterID -> entityKey -> EntityRisk
 	propagations map[string][]*aiops.PropagationPath          // clusterID -> propagation paths
 	firstAnomaly map[string]int64                             // entityKey -> 首次异常时间
+	now          func() time.Time
 }
 
 // NewScorer 创建风险评分引擎
@@ -31,9 +33,38 @@ func NewScorer(config *RiskConfig) *Scorer {
 		entityMap:    make(map[string]map[string]*aiops.EntityRisk),
 		propagations: make(map[string][]*aiops.PropagationPath),
 		firstAnomaly: make(map[string]int64),
+		now:          time.Now,
 	}
 }
 
+// SetClock 设置时钟（离线回放按快照时间推进）
+func (s *Scorer) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// ConfigResolver 按集群 + 实体解析风险配置
+// entityKey 为空时返回集群级配置（时序 / 传播 / 聚合参数）
+type ConfigResolver func(clusterID, entityKey string) *RiskConfig
+
+// SetConfigResolver 设置风险配置解析器（每次评分调用，策略变更即时生效，首次异常时间等状态保留）
+func (s *Scorer) SetConfigResolver(resolve ConfigResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resolve = resolve
+}
+
+// configFor 解析风险配置（解析器未设置或返回 nil 时使用默认配置）
+func (s *Scorer) configFor(clusterID, entityKey string) *RiskConfig {
+	if s.resolve != nil {
+		if cfg := s.resolve(clusterID, entityKey); cfg != nil {
+			return cfg
+		}
+	}
+	return s.config
+}
+
 // Calculate 执行三阶段风险评分
 func (s *Scorer) Calculate(
 	clusterID string,
@@ -44,19 +75,23 @@ func (s *Scorer) Calculate(
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	now := time.Now().Unix()
+	now := s.now().Unix()
 
 	// 更新首次异常时间记录
 	s.updateFirstAnomalyTimes(anomalies, now)
 
-	// Stage 1: 局部风险
-	localRisks := ComputeLocalRisks(anomalies, s.config)
+	config := s.configFor(clusterID, "")
+
+	// Stage 1: 局部风险（指标权重可按命名空间 / 实体类型覆盖）
+	localRisks := ComputeLocalRisksWith(anomalies, func(entityKey string) *RiskConfig {
+		return s.configFor(clusterID, entityKey)
+	})
 
 	// Stage 2: 时序权重
-	weightedRisks := ApplyTemporalWeights(localRisks, s.firstAnomaly, now, s.config.TemporalHalfLife)
+	weightedRisks := ApplyTemporalWeights(localRisks, s.firstAnomaly, now, config.TemporalHalfLife)
 
 	// Stage 3: 图传播
-	finalRisks, paths := Propagate(graph, weightedRisks, s.config.SelfWeight)
+	finalRisks, paths := Propagate(graph, weightedRisks, config.SelfWeight)
 	s.propagations[clusterID] = paths
 
 	// 构建 EntityRisk 列表
@@ -64,7 +99,7 @@ func (s *Scorer) Calculate(
 	s.entityMap[clusterID] = entityRisks
 
 	// 聚合 ClusterRisk
-	clusterRisk := Aggregate(clusterID, entityRisks, finalRisks, sloCtx, s.config, now)
+	clusterRisk := Aggregate(clusterID, entityRisks, finalRisks, sloCtx, config, now)
 	s.results[clusterID] = clusterRisk
 
 	return clusterRisk
@@ -0,0 +1,238 @@
+// atlhyper_master_v2/aiops/runbook/executor.go
+// 步骤执行: 指令经 CommandExecutor 下发（写入 MQ + 指令历史），等待 / 验证在 Master 内完成
+package runbook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/model_v3/cluster"
+)
+
+// 步骤状态
+const (
+	StepPending   = "pending"
+	StepRunning   = "running"
+	StepSucceeded = "succeeded"
+	StepFailed    = "failed"
+	StepSkipped   = "skipped" // 演练 / 前序步骤失败
+)
+
+// commandSource 指令来源（记录在指令历史中）
+const commandSource = "runbook"
+
+// StepResult 单个步骤的执行结果（序列化到 AIOpsRunbookRun.Steps）
+type StepResult struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"` // command / wait / verify
+	Status string `json:"status"`
+
+	// command 步骤解析后的目标
+	Command         string         `json:"command,omitempty"`
+	TargetKind      string         `json:"targetKind,omitempty"`
+	TargetNamespace string         `json:"targetNamespace,omitempty"`
+	TargetName      string         `json:"targetName,omitempty"`
+	Params          map[string]any `json:"params,omitempty"`
+	CommandID       string         `json:"commandId,omitempty"`
+
+	Message    string     `json:"message,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// buildPlan 解析全部步骤目标（任一目标无法解析则整个 Runbook 不执行）
+func buildPlan(rb *Runbook, snap *cluster.ClusterSnapshot, entityKey string) ([]*StepResult, error) {
+	plan := make([]*StepResult, 0, len(rb.Steps))
+	for i := range rb.Steps {
+		step := &rb.Steps[i]
+		res := &StepResult{}
+		if step.Kind() == "command" {
+			resolved, err := resolveCommand(step, snap, entityKey)
+			if err != nil {
+				return nil, fmt.Errorf("step %d (%s): %w", i+1, step.Label(), err)
+			}
+			res = resolved
+		}
+		res.Name = step.Label()
+		res.Kind = step.Kind()
+		res.Status = StepPending
+		plan = append(plan, res)
+	}
+	return plan, nil
+}
+
+// encodePlan 序列化步骤结果
+func encodePlan(plan []*StepResult) string {
+	data, _ := json.Marshal(plan)
+	return string(data)
+}
+
+// DecodeSteps 反序列化执行记录中的步骤结果
+func DecodeSteps(raw string) []*StepResult {
+	var plan []*StepResult
+	if raw != "" {
+		_ = json.Unmarshal([]byte(raw), &plan)
+	}
+	return plan
+}
+
+// execute 按顺序执行步骤，首个失败步骤后其余步骤标记为 skipped
+func (m *Manager) execute(ctx context.Context, run *database.AIOpsRunbookRun, rb *Runbook) {
+	plan := DecodeSteps(run.Steps)
+	started := m.now()
+	run.Status = database.RunbookRunRunning
+	run.StartedAt = &started
+	m.saveRun(run, plan)
+
+	var failure error
+	for i, res := range plan {
+		if failure != nil {
+			res.Status = StepSkipped
+			continue
+		}
+		if i >= len(rb.Steps) {
+			failure = fmt.Errorf("runbook %s changed since the run was planned", rb.Name)
+			res.Status = StepFailed
+			res.Message = failure.Error()
+			continue
+		}
+
+		at := m.now()
+		res.Status, res.StartedAt = StepRunning, &at
+		m.saveRun(run, plan)
+
+		if run.DryRun {
+			res.Status = StepSkipped
+			res.Message = "dry run: " + describe(res, &rb.Steps[i])
+		} else if err := m.runStep(ctx, run, &rb.Steps[i], res); err != nil {
+			res.Status = StepFailed
+			res.Message = err.Error()
+			failure = fmt.Errorf("step %d (%s): %w", i+1, res.Name, err)
+		} else {
+			res.Status = StepSucceeded
+		}
+		done := m.now()
+		res.FinishedAt = &done
+	}
+
+	finished := m.now()
+	run.FinishedAt = &finished
+	run.Status = database.RunbookRunSucceeded
+	if failure != nil {
+		run.Status = database.RunbookRunFailed
+		run.Error = failure.Error()
+	}
+	m.saveRun(run, plan)
+
+	log.Info("Runbook 执行结束", "run", run.ID, "runbook", run.Runbook, "incident", run.IncidentID,
+		"status", run.Status, "dryRun", run.DryRun)
+	m.comment(run.IncidentID, commandSource, finishComment(run))
+}
+
+// runStep 执行单个步骤
+func (m *Manager) runStep(ctx context.Context, run *database.AIOpsRunbookRun, step *Step, res *StepResult) error {
+	switch step.Kind() {
+	case "wait":
+		return m.sleep(ctx, step.Wait)
+	case "verify":
+		return m.verify(ctx, run, step.Verify)
+	}
+
+	result, err := m.cfg.Commands.ExecuteCommandSync(ctx, &model.CreateCommandRequest{
+		ClusterID:       run.ClusterID,
+		Action:          res.Command,
+		TargetKind:      res.TargetKind,
+		TargetNamespace: res.TargetNamespace,
+		TargetName:      res.TargetName,
+		Params:          res.Params,
+		Source:          commandSource,
+	}, step.Timeout)
+	if err != nil {
+		return err
+	}
+	res.CommandID = result.CommandID
+	if !result.Success {
+		return fmt.Errorf("agent: %s", result.Error)
+	}
+	res.Message = "ok"
+	return nil
+}
+
+// verify 轮询同一工作负载的全部副本，直到风险回落且指标不再异常
+func (m *Manager) verify(ctx context.Context, run *database.AIOpsRunbookRun, v *Verify) error {
+	pattern := aiops.EntityPattern(run.EntityKey)
+	deadline := m.now().Add(v.Timeout)
+	for {
+		blocking := m.unhealthyReplica(run.ClusterID, pattern, v)
+		if blocking == "" {
+			return nil
+		}
+		if !m.now().Before(deadline) {
+			return fmt.Errorf("verify timed out after %s: %s still unhealthy", v.Timeout, blocking)
+		}
+		if err := m.sleep(ctx, v.Interval); err != nil {
+			return err
+		}
+	}
+}
+
+// unhealthyReplica 返回第一个仍不满足验证条件的实体（全部满足返回空）
+func (m *Manager) unhealthyReplica(clusterID, pattern string, v *Verify) string {
+	for _, r := range m.cfg.Source.GetEntityRisks(clusterID, "r_final", 0) {
+		if aiops.EntityPattern(r.EntityKey) != pattern {
+			continue
+		}
+		if r.RFinal >= v.MaxRisk {
+			return r.EntityKey
+		}
+		if v.Metric != "" && hasAnomaly(m.cfg.Source.GetEntityRisk(clusterID, r.EntityKey), v.Metric) {
+			return r.EntityKey
+		}
+	}
+	return ""
+}
+
+// sleepContext 可取消的等待
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// describe 演练时的步骤描述
+func describe(res *StepResult, step *Step) string {
+	switch res.Kind {
+	case "wait":
+		return "wait " + step.Wait.String()
+	case "verify":
+		return fmt.Sprintf("verify (metric=%q, maxRisk=%.2f, timeout=%s)", step.Verify.Metric, step.Verify.MaxRisk, step.Verify.Timeout)
+	}
+	target := res.TargetName
+	if res.TargetNamespace != "" {
+		target = res.TargetNamespace + "/" + target
+	}
+	if len(res.Params) > 0 {
+		params, _ := json.Marshal(res.Params)
+		return fmt.Sprintf("%s %s %s %s", res.Command, res.TargetKind, target, params)
+	}
+	return fmt.Sprintf("%s %s %s", res.Command, res.TargetKind, target)
+}
+
+// finishComment 执行结束的时间线评论
+func finishComment(run *database.AIOpsRunbookRun) string {
+	prefix := "Runbook " + run.Runbook + dryRunSuffix(run.DryRun)
+	if run.Status == database.RunbookRunSucceeded {
+		return prefix + " 执行成功"
+	}
+	return prefix + " 执行失败: " + run.Error
+}
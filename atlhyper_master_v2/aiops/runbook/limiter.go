@@ -0,0 +1,73 @@
+// atlhyper_master_v2/aiops/runbook/limiter.go
+// 执行限流: 单集群每小时次数上限 + 同一工作负载冷却时间
+package runbook
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// limiter 执行限流器（演练不占用额度）
+type limiter struct {
+	mu         sync.Mutex
+	maxPerHour int                    // 0 = 不限制
+	runs       map[string][]time.Time // clusterID → 最近一小时的执行时间
+	last       map[string]time.Time   // runbook|cluster|workload → 最近一次执行时间
+}
+
+func newLimiter(maxPerHour int) *limiter {
+	return &limiter{
+		maxPerHour: maxPerHour,
+		runs:       make(map[string][]time.Time),
+		last:       make(map[string]time.Time),
+	}
+}
+
+// allow 检查并占用额度，超限返回 ErrRateLimited
+func (l *limiter) allow(clusterID, cooldownKey string, cooldown time.Duration, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, ok := l.last[cooldownKey]; ok && now.Sub(last) < cooldown {
+		return fmt.Errorf("%w: cooldown until %s", ErrRateLimited, last.Add(cooldown).Format(time.RFC3339))
+	}
+
+	recent := l.prune(clusterID, now)
+	if l.maxPerHour > 0 && len(recent) >= l.maxPerHour {
+		return fmt.Errorf("%w: %d runs in the last hour for cluster %s", ErrRateLimited, len(recent), clusterID)
+	}
+
+	l.runs[clusterID] = append(recent, now)
+	l.last[cooldownKey] = now
+	return nil
+}
+
+// record 记录已有执行（启动时从数据库恢复，不做检查）
+func (l *limiter) record(clusterID, cooldownKey string, at time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.runs[clusterID] = append(l.runs[clusterID], at)
+	if at.After(l.last[cooldownKey]) {
+		l.last[cooldownKey] = at
+	}
+}
+
+// prune 丢弃一小时前的记录
+func (l *limiter) prune(clusterID string, now time.Time) []time.Time {
+	cutoff := now.Add(-time.Hour)
+	runs := l.runs[clusterID]
+	kept := runs[:0]
+	for _, t := range runs {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// cooldownKey 冷却维度: 同一 Runbook 作用于同一集群的同一工作负载
+func cooldownKey(runbook, clusterID, workload string) string {
+	return runbook + "|" + clusterID + "|" + workload
+}
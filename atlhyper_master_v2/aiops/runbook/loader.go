@@ -0,0 +1,81 @@
+// atlhyper_master_v2/aiops/runbook/loader.go
+// Runbook YAML 加载
+package runbook
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse 解析 YAML 内容（支持 --- 分隔的多个 Runbook）并逐个校验
+func Parse(data []byte) ([]*Runbook, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var runbooks []*Runbook
+	for {
+		rb := &Runbook{}
+		err := dec.Decode(rb)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse yaml: %w", err)
+		}
+		if err := rb.Validate(); err != nil {
+			return nil, fmt.Errorf("runbook %q: %w", rb.Name, err)
+		}
+		runbooks = append(runbooks, rb)
+	}
+	return runbooks, nil
+}
+
+// LoadDir 加载目录下所有 .yaml / .yml 文件
+// 单个文件解析失败不影响其他文件，错误合并返回；名称重复时保留先加载的
+func LoadDir(dir string) ([]*Runbook, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var (
+		runbooks []*Runbook
+		errs     []error
+		seen     = make(map[string]string)
+	)
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Name(), err))
+			continue
+		}
+		parsed, err := Parse(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Name(), err))
+			continue
+		}
+		for _, rb := range parsed {
+			if prev, ok := seen[rb.Name]; ok {
+				errs = append(errs, fmt.Errorf("%s: duplicate runbook %q (already defined in %s)", e.Name(), rb.Name, prev))
+				continue
+			}
+			seen[rb.Name] = e.Name()
+			rb.File = e.Name()
+			runbooks = append(runbooks, rb)
+		}
+	}
+	return runbooks, errors.Join(errs...)
+}
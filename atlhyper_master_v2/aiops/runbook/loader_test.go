@@ -0,0 +1,106 @@
+package runbook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const oomRunbook = `
+name: oom-restart
+match:
+  entityType: pod
+  metric: container_anomaly
+  reason: OOMKilled
+  ownerKind: Deployment
+  namespaces: ["prod-*"]
+mode: auto
+cooldown: 10m
+steps:
+  - action: restart
+  - wait: 1m
+  - verify:
+      metric: container_anomaly
+      timeout: 2m
+`
+
+func TestParse_Defaults(t *testing.T) {
+	runbooks, err := Parse([]byte(oomRunbook))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(runbooks) != 1 {
+		t.Fatalf("runbooks = %d, want 1", len(runbooks))
+	}
+	rb := runbooks[0]
+	if rb.Mode != ModeAuto || rb.Cooldown != 10*time.Minute || rb.Match.State != StateIncident {
+		t.Errorf("unexpected runbook: mode=%s cooldown=%s state=%s", rb.Mode, rb.Cooldown, rb.Match.State)
+	}
+	if got := rb.Steps[0]; got.Target != TargetOwner || got.Timeout != defaultStepTimeout {
+		t.Errorf("restart defaults: target=%s timeout=%s", got.Target, got.Timeout)
+	}
+	if got := rb.Steps[1]; got.Kind() != "wait" || got.Wait != time.Minute {
+		t.Errorf("wait step: kind=%s wait=%s", got.Kind(), got.Wait)
+	}
+	v := rb.Steps[2].Verify
+	if v == nil || v.Timeout != 2*time.Minute || v.Interval != defaultVerifyInterval || v.MaxRisk != defaultVerifyMaxRisk {
+		t.Errorf("verify defaults: %+v", v)
+	}
+}
+
+func TestParse_MultiDocument(t *testing.T) {
+	data := oomRunbook + "---\nname: cordon-node\nmatch:\n  entityType: node\nsteps:\n  - action: cordon\n"
+	runbooks, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(runbooks) != 2 || runbooks[1].Name != "cordon-node" || runbooks[1].Mode != ModeApproval {
+		t.Fatalf("unexpected runbooks: %+v", runbooks)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"bad name":         "name: Bad_Name\nsteps:\n  - wait: 1s\n",
+		"no steps":         "name: empty\n",
+		"unknown field":    "name: x\nretries: 3\nsteps:\n  - wait: 1s\n",
+		"unknown action":   "name: x\nmatch:\n  entityType: pod\nsteps:\n  - action: reboot\n",
+		"two kinds":        "name: x\nmatch:\n  entityType: pod\nsteps:\n  - action: restart\n    wait: 1s\n",
+		"scale no replica": "name: x\nmatch:\n  entityType: pod\nsteps:\n  - action: scale\n",
+		"restart on node":  "name: x\nmatch:\n  entityType: node\nsteps:\n  - action: restart\n",
+		"reason on node":   "name: x\nmatch:\n  entityType: node\n  reason: OOMKilled\nsteps:\n  - action: cordon\n",
+		"bad duration":     "name: x\nsteps:\n  - wait: soon\n",
+		"wait too long":    "name: x\nsteps:\n  - wait: 2h\n",
+		"bad mode":         "name: x\nmode: yolo\nsteps:\n  - wait: 1s\n",
+	}
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(data)); err == nil {
+				t.Errorf("expected error for %q", data)
+			}
+		})
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.yaml", oomRunbook)
+	write("b.yml", "name: oom-restart\nsteps:\n  - wait: 1s\n")
+	write("c.yaml", "name: broken\n")
+	write("notes.txt", "ignored")
+
+	runbooks, err := LoadDir(dir)
+	if len(runbooks) != 1 || runbooks[0].File != "a.yaml" {
+		t.Fatalf("runbooks = %+v", runbooks)
+	}
+	if err == nil || !strings.Contains(err.Error(), "duplicate runbook") || !strings.Contains(err.Error(), "c.yaml") {
+		t.Errorf("err = %v, want duplicate and c.yaml errors", err)
+	}
+}
@@ -0,0 +1,435 @@
+// atlhyper_master_v2/aiops/runbook/manager.go
+// Runbook 管理器
+//
+// 订阅状态机转换，事件命中 Runbook 时创建执行记录:
+//   - mode=auto: 立即执行
+//   - mode=approval: 等待一键审批（Approve）后执行
+//
+// 每次执行经限流检查（单集群每小时上限 + 工作负载冷却），演练模式只解析步骤不下发指令。
+package runbook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/common/logger"
+	"AtlHyper/model_v3/cluster"
+	"AtlHyper/model_v3/command"
+)
+
+var log = logger.Module("AIOps-Runbook")
+
+// 触发方式
+const (
+	TriggerAuto   = "auto"
+	TriggerManual = "manual"
+)
+
+// Runbook 错误
+var (
+	ErrRunbookNotFound = errors.New("runbook not found")
+	ErrRunNotFound     = errors.New("runbook run not found")
+	ErrRunNotPending   = errors.New("runbook run is not pending approval")
+	ErrNoMatch         = errors.New("incident does not match runbook")
+	ErrRateLimited     = errors.New("runbook rate limited")
+)
+
+// IncidentSource 事件与风险数据来源（由 aiops.Engine 实现）
+type IncidentSource interface {
+	GetIncidentDetail(ctx context.Context, incidentID string) *aiops.IncidentDetail
+	GetEntityRisk(clusterID, entityKey string) *aiops.EntityRiskDetail
+	GetEntityRisks(clusterID, sortBy string, limit int) []*aiops.EntityRisk
+	ApplyIncidentAction(ctx context.Context, incidentID string, action *aiops.IncidentAction) (*aiops.IncidentDetail, error)
+}
+
+// CommandExecutor 指令下发（由 operations.CommandService 实现: 写入 mq.Producer + 记录指令历史）
+type CommandExecutor interface {
+	ExecuteCommandSync(ctx context.Context, req *model.CreateCommandRequest, timeout time.Duration) (*command.Result, error)
+}
+
+// SnapshotSource 集群快照来源（由 datahub.Store 实现）
+type SnapshotSource interface {
+	GetSnapshot(clusterID string) (*cluster.ClusterSnapshot, error)
+}
+
+// Config 管理器依赖与配置
+type Config struct {
+	Runbooks       []*Runbook
+	Source         IncidentSource
+	Commands       CommandExecutor
+	Snapshots      SnapshotSource
+	Repo           database.AIOpsRunbookRunRepository
+	DryRun         bool // 全局演练模式
+	MaxRunsPerHour int  // 单集群每小时执行上限（0 = 不限制）
+}
+
+// Manager Runbook 管理器
+type Manager struct {
+	cfg     Config
+	byName  map[string]*Runbook
+	limiter *limiter
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+
+	matchMu sync.Mutex // 串行化匹配，避免同一事件的多次转换重复创建执行
+
+	ctx     context.Context // 执行上下文，Stop 时取消
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	stopped bool
+}
+
+// NewManager 创建 Runbook 管理器（Runbook 须已通过 Validate）
+func NewManager(cfg Config) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		cfg:     cfg,
+		byName:  make(map[string]*Runbook, len(cfg.Runbooks)),
+		limiter: newLimiter(cfg.MaxRunsPerHour),
+		now:     time.Now,
+		sleep:   sleepContext,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	for _, rb := range cfg.Runbooks {
+		m.byName[rb.Name] = rb
+	}
+	return m
+}
+
+// Start 恢复状态: 中断的执行标记为失败，最近一小时的执行计入限流（待审批的执行不占用额度）
+func (m *Manager) Start(ctx context.Context) error {
+	runs, err := m.cfg.Repo.List(ctx, database.RunbookRunQueryOpts{Limit: 500})
+	if err != nil {
+		return fmt.Errorf("load runbook runs: %w", err)
+	}
+	now := m.now()
+	for _, run := range runs {
+		if run.Status == database.RunbookRunRunning {
+			run.Status = database.RunbookRunFailed
+			run.Error = "interrupted by master restart"
+			run.FinishedAt = &now
+			if err := m.cfg.Repo.Update(ctx, run); err != nil {
+				log.Warn("标记中断的 Runbook 执行失败", "run", run.ID, "err", err)
+			}
+		}
+		if run.DryRun || run.Status == database.RunbookRunRejected || run.Status == database.RunbookRunPending {
+			continue
+		}
+		at := run.CreatedAt
+		if run.StartedAt != nil {
+			at = *run.StartedAt
+		}
+		if now.Sub(at) < time.Hour {
+			m.limiter.record(run.ClusterID, cooldownKey(run.Runbook, run.ClusterID, aiops.EntityPattern(run.EntityKey)), at)
+		}
+	}
+	log.Info("Runbook 管理器已启动", "runbooks", len(m.byName), "dryRun", m.cfg.DryRun)
+	return nil
+}
+
+// Stop 停止管理器，取消进行中的执行并等待退出
+func (m *Manager) Stop() error {
+	m.mu.Lock()
+	m.stopped = true
+	m.mu.Unlock()
+
+	m.cancel()
+	m.wg.Wait()
+	log.Info("Runbook 管理器已停止")
+	return nil
+}
+
+// Runbooks 已加载的 Runbook（按名称排序）
+func (m *Manager) Runbooks() []*Runbook {
+	list := make([]*Runbook, 0, len(m.byName))
+	for _, rb := range m.byName {
+		list = append(list, rb)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// ListRuns 查询执行记录
+func (m *Manager) ListRuns(ctx context.Context, opts database.RunbookRunQueryOpts) ([]*database.AIOpsRunbookRun, int64, error) {
+	runs, err := m.cfg.Repo.List(ctx, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := m.cfg.Repo.Count(ctx, opts)
+	return runs, total, err
+}
+
+// GetRun 查询单条执行记录
+func (m *Manager) GetRun(ctx context.Context, id int64) (*database.AIOpsRunbookRun, error) {
+	run, err := m.cfg.Repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, ErrRunNotFound
+	}
+	return run, nil
+}
+
+// OnTransition 状态机转换回调（aiops.TransitionListener），匹配在后台进行
+func (m *Manager) OnTransition(ev aiops.TransitionEvent) {
+	var state string
+	switch {
+	case ev.Kind == aiops.TransitionWarningCreated:
+		state = StateWarning
+	case ev.Kind == aiops.TransitionEscalated && ev.State == aiops.StateIncident:
+		state = StateIncident
+	default:
+		return
+	}
+	m.goRun(func() { m.handleTransition(ev, state) })
+}
+
+// handleTransition 匹配 Runbook 并创建执行
+func (m *Manager) handleTransition(ev aiops.TransitionEvent, state string) {
+	m.matchMu.Lock()
+	defer m.matchMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(m.ctx, 30*time.Second)
+	defer cancel()
+
+	detail := m.cfg.Source.GetIncidentDetail(ctx, ev.IncidentID)
+	if detail == nil || detail.ResolvedAt != nil || detail.FalsePositive {
+		return
+	}
+	entityKey := detail.RootCause
+	if entityKey == "" {
+		entityKey = ev.EntityKey
+	}
+
+	existing, err := m.cfg.Repo.List(ctx, database.RunbookRunQueryOpts{IncidentID: detail.ID})
+	if err != nil {
+		log.Warn("查询 Runbook 执行记录失败", "incident", detail.ID, "err", err)
+		return
+	}
+	done := make(map[string]bool, len(existing))
+	for _, run := range existing {
+		done[run.Runbook] = true
+	}
+
+	snap, _ := m.cfg.Snapshots.GetSnapshot(detail.ClusterID)
+	risk := m.cfg.Source.GetEntityRisk(detail.ClusterID, entityKey)
+	for _, rb := range m.Runbooks() {
+		// warning 阶段的 Runbook 在升级时仍可匹配（转换可能直接跳到 Incident）
+		if done[rb.Name] || (rb.Match.State == StateIncident && state != StateIncident) {
+			continue
+		}
+		if !rb.Match.matchesEntity(entityKey) || !rb.Match.matchesSignal(risk, snap, entityKey) {
+			continue
+		}
+		if _, err := m.start(ctx, rb, detail, entityKey, snap, TriggerAuto, commandSource, false); err != nil {
+			log.Warn("Runbook 未执行", "runbook", rb.Name, "incident", detail.ID, "err", err)
+		}
+	}
+}
+
+// Trigger 手动对事件执行 Runbook（视为已审批，仅检查实体类型与命名空间）
+func (m *Manager) Trigger(ctx context.Context, name, incidentID, by string, dryRun bool) (*database.AIOpsRunbookRun, error) {
+	rb := m.byName[name]
+	if rb == nil {
+		return nil, ErrRunbookNotFound
+	}
+	detail := m.cfg.Source.GetIncidentDetail(ctx, incidentID)
+	if detail == nil {
+		return nil, aiops.ErrIncidentNotFound
+	}
+	if detail.ResolvedAt != nil && !dryRun {
+		return nil, aiops.ErrIncidentResolved
+	}
+	if !rb.Match.matchesEntity(detail.RootCause) {
+		return nil, fmt.Errorf("%w: root cause %s", ErrNoMatch, detail.RootCause)
+	}
+	snap, _ := m.cfg.Snapshots.GetSnapshot(detail.ClusterID)
+	return m.start(ctx, rb, detail, detail.RootCause, snap, TriggerManual, by, dryRun)
+}
+
+// Approve 审批通过并开始执行
+// 匹配到审批之间集群可能已变化：事件已恢复 / 误报时拒绝审批，
+// 步骤目标按当前快照重新解析，限流额度在审批时占用
+func (m *Manager) Approve(ctx context.Context, id int64, by string) (*database.AIOpsRunbookRun, error) {
+	run, err := m.GetRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != database.RunbookRunPending {
+		return nil, ErrRunNotPending
+	}
+	rb := m.byName[run.Runbook]
+	if rb == nil {
+		return nil, ErrRunbookNotFound
+	}
+
+	detail := m.cfg.Source.GetIncidentDetail(ctx, run.IncidentID)
+	switch {
+	case detail == nil:
+		return nil, aiops.ErrIncidentNotFound
+	case detail.ResolvedAt != nil:
+		return nil, aiops.ErrIncidentResolved
+	case detail.FalsePositive:
+		return nil, aiops.ErrFalsePositive
+	}
+	snap, _ := m.cfg.Snapshots.GetSnapshot(run.ClusterID)
+	plan, err := buildPlan(rb, snap, run.EntityKey)
+	if err != nil {
+		return nil, err
+	}
+	if !run.DryRun {
+		key := cooldownKey(rb.Name, run.ClusterID, aiops.EntityPattern(run.EntityKey))
+		if err := m.limiter.allow(run.ClusterID, key, rb.Cooldown, m.now()); err != nil {
+			return nil, err
+		}
+	}
+
+	ok, err := m.cfg.Repo.TransitionStatus(ctx, id, database.RunbookRunPending, database.RunbookRunRunning, by)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrRunNotPending
+	}
+	run.Status, run.ApprovedBy, run.Steps = database.RunbookRunRunning, by, encodePlan(plan)
+
+	log.Info("Runbook 已审批", "run", id, "runbook", run.Runbook, "by", by)
+	m.comment(run.IncidentID, by, "审批通过 Runbook "+run.Runbook)
+	m.goRun(func() { m.execute(m.ctx, run, rb) })
+	return run, nil
+}
+
+// Reject 拒绝待审批的执行
+func (m *Manager) Reject(ctx context.Context, id int64, by, reason string) (*database.AIOpsRunbookRun, error) {
+	run, err := m.GetRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := m.cfg.Repo.TransitionStatus(ctx, id, database.RunbookRunPending, database.RunbookRunRejected, by)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrRunNotPending
+	}
+	now := m.now()
+	run.Status, run.ApprovedBy, run.Error, run.FinishedAt = database.RunbookRunRejected, by, reason, &now
+	if err := m.cfg.Repo.Update(ctx, run); err != nil {
+		return nil, err
+	}
+
+	comment := "拒绝执行 Runbook " + run.Runbook
+	if reason != "" {
+		comment += ": " + reason
+	}
+	m.comment(run.IncidentID, by, comment)
+	return run, nil
+}
+
+// start 解析步骤、限流并创建执行记录；auto 模式、手动触发与演练立即执行
+// 待审批的执行在 Approve 时才占用限流额度
+func (m *Manager) start(ctx context.Context, rb *Runbook, detail *aiops.IncidentDetail, entityKey string,
+	snap *cluster.ClusterSnapshot, trigger, by string, dryRun bool) (*database.AIOpsRunbookRun, error) {
+	dryRun = dryRun || rb.DryRun || m.cfg.DryRun
+	needsApproval := trigger == TriggerAuto && rb.Mode == ModeApproval && !dryRun
+
+	plan, err := buildPlan(rb, snap, entityKey)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun && !needsApproval {
+		key := cooldownKey(rb.Name, detail.ClusterID, aiops.EntityPattern(entityKey))
+		if err := m.limiter.allow(detail.ClusterID, key, rb.Cooldown, m.now()); err != nil {
+			return nil, err
+		}
+	}
+
+	run := &database.AIOpsRunbookRun{
+		Runbook:     rb.Name,
+		IncidentID:  detail.ID,
+		ClusterID:   detail.ClusterID,
+		EntityKey:   entityKey,
+		Status:      database.RunbookRunRunning,
+		DryRun:      dryRun,
+		Trigger:     trigger,
+		Steps:       encodePlan(plan),
+		RequestedBy: by,
+		CreatedAt:   m.now(),
+	}
+	if needsApproval {
+		run.Status = database.RunbookRunPending
+	} else if trigger == TriggerManual {
+		run.ApprovedBy = by
+	}
+	if err := m.cfg.Repo.Create(ctx, run); err != nil {
+		return nil, fmt.Errorf("save runbook run: %w", err)
+	}
+
+	log.Info("Runbook 已匹配", "run", run.ID, "runbook", rb.Name, "incident", detail.ID,
+		"entity", entityKey, "status", run.Status, "dryRun", dryRun)
+	if needsApproval {
+		m.comment(detail.ID, commandSource, "Runbook "+rb.Name+" 已匹配，等待审批")
+		return run, nil
+	}
+
+	m.comment(detail.ID, by, "开始执行 Runbook "+rb.Name+dryRunSuffix(dryRun))
+	m.goRun(func() { m.execute(m.ctx, run, rb) })
+	return run, nil
+}
+
+// goRun 在后台执行任务（Stop 后不再接收）
+func (m *Manager) goRun(fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopped {
+		return
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		fn()
+	}()
+}
+
+// saveRun 持久化执行进度（使用独立 context，取消后仍能写入最终状态）
+func (m *Manager) saveRun(run *database.AIOpsRunbookRun, plan []*StepResult) {
+	run.Steps = encodePlan(plan)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := m.cfg.Repo.Update(ctx, run); err != nil {
+		log.Warn("保存 Runbook 执行进度失败", "run", run.ID, "err", err)
+	}
+}
+
+// comment 在事件时间线记录 Runbook 动态（失败仅记录日志）
+func (m *Manager) comment(incidentID, actor, text string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := m.cfg.Source.ApplyIncidentAction(ctx, incidentID, &aiops.IncidentAction{
+		Type:    aiops.IncidentActionComment,
+		Actor:   actor,
+		Comment: text,
+	})
+	if err != nil {
+		log.Debug("写入 Runbook 时间线失败", "incident", incidentID, "err", err)
+	}
+}
+
+// dryRunSuffix 演练标记
+func dryRunSuffix(dryRun bool) string {
+	if dryRun {
+		return "（演练）"
+	}
+	return ""
+}
@@ -0,0 +1,487 @@
+package runbook
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/model_v3"
+	"AtlHyper/model_v3/cluster"
+	"AtlHyper/model_v3/command"
+)
+
+// ==================== Mock ====================
+
+type memRunRepo struct {
+	mu     sync.Mutex
+	nextID int64
+	runs   map[int64]*database.AIOpsRunbookRun
+}
+
+func newMemRunRepo() *memRunRepo {
+	return &memRunRepo{runs: make(map[int64]*database.AIOpsRunbookRun)}
+}
+
+func (r *memRunRepo) Create(ctx context.Context, run *database.AIOpsRunbookRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	run.ID = r.nextID
+	cp := *run
+	r.runs[run.ID] = &cp
+	return nil
+}
+
+func (r *memRunRepo) Update(ctx context.Context, run *database.AIOpsRunbookRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *run
+	r.runs[run.ID] = &cp
+	return nil
+}
+
+func (r *memRunRepo) TransitionStatus(ctx context.Context, id int64, from, to, by string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run, ok := r.runs[id]
+	if !ok || run.Status != from {
+		return false, nil
+	}
+	run.Status, run.ApprovedBy = to, by
+	return true, nil
+}
+
+func (r *memRunRepo) GetByID(ctx context.Context, id int64) (*database.AIOpsRunbookRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run, ok := r.runs[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *run
+	return &cp, nil
+}
+
+func (r *memRunRepo) List(ctx context.Context, opts database.RunbookRunQueryOpts) ([]*database.AIOpsRunbookRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*database.AIOpsRunbookRun
+	for id := int64(1); id <= r.nextID; id++ {
+		run := r.runs[id]
+		if opts.IncidentID != "" && run.IncidentID != opts.IncidentID {
+			continue
+		}
+		cp := *run
+		list = append(list, &cp)
+	}
+	return list, nil
+}
+
+func (r *memRunRepo) Count(ctx context.Context, opts database.RunbookRunQueryOpts) (int64, error) {
+	list, err := r.List(ctx, opts)
+	return int64(len(list)), err
+}
+
+type stubSource struct {
+	mu       sync.Mutex
+	detail   *aiops.IncidentDetail
+	risk     *aiops.EntityRiskDetail
+	risks    []*aiops.EntityRisk
+	comments []string
+}
+
+func (s *stubSource) GetIncidentDetail(ctx context.Context, id string) *aiops.IncidentDetail {
+	if s.detail == nil || s.detail.ID != id {
+		return nil
+	}
+	return s.detail
+}
+
+// GetEntityRisk 仅根因 Pod 携带异常指标（重建后的副本视为已恢复）
+func (s *stubSource) GetEntityRisk(clusterID, entityKey string) *aiops.EntityRiskDetail {
+	if entityKey != podKey {
+		return nil
+	}
+	return s.risk
+}
+
+func (s *stubSource) GetEntityRisks(clusterID, sortBy string, limit int) []*aiops.EntityRisk {
+	return s.risks
+}
+
+func (s *stubSource) ApplyIncidentAction(ctx context.Context, id string, action *aiops.IncidentAction) (*aiops.IncidentDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.comments = append(s.comments, action.Comment)
+	return s.detail, nil
+}
+
+type stubCommands struct {
+	mu   sync.Mutex
+	reqs []*model.CreateCommandRequest
+	fail bool
+}
+
+func (c *stubCommands) ExecuteCommandSync(ctx context.Context, req *model.CreateCommandRequest, timeout time.Duration) (*command.Result, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reqs = append(c.reqs, req)
+	if c.fail {
+		return &command.Result{CommandID: "cmd-x", Success: false, Error: "deployment not found"}, nil
+	}
+	return &command.Result{CommandID: "cmd-1", Success: true}, nil
+}
+
+func (c *stubCommands) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.reqs)
+}
+
+type stubSnapshots struct {
+	snap *cluster.ClusterSnapshot
+}
+
+func (s *stubSnapshots) GetSnapshot(clusterID string) (*cluster.ClusterSnapshot, error) {
+	return s.snap, nil
+}
+
+// ==================== 测试辅助 ====================
+
+const podKey = "prod-web/pod/api-7c9fd8b6f5-x2kqz"
+
+type fixture struct {
+	m      *Manager
+	repo   *memRunRepo
+	source *stubSource
+	cmds   *stubCommands
+}
+
+func newFixture(t *testing.T, mode string, cfg Config) *fixture {
+	t.Helper()
+	runbooks, err := Parse([]byte(oomRunbook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runbooks[0].Mode = mode
+
+	snap := &cluster.ClusterSnapshot{
+		Pods: []cluster.Pod{{
+			Summary: cluster.PodSummary{
+				Name: "api-7c9fd8b6f5-x2kqz", Namespace: "prod-web", NodeName: "node-1",
+				OwnerKind: "ReplicaSet", OwnerName: "api-7c9fd8b6f5",
+			},
+			Containers: []cluster.PodContainerDetail{{Name: "api", LastTerminationReason: "OOMKilled"}},
+		}},
+		ReplicaSets: []cluster.ReplicaSet{{
+			CommonMeta: model_v3.CommonMeta{Name: "api-7c9fd8b6f5", Namespace: "prod-web", OwnerKind: "Deployment", OwnerName: "api"},
+		}},
+	}
+	source := &stubSource{
+		detail: &aiops.IncidentDetail{Incident: aiops.Incident{
+			ID: "inc-1", ClusterID: "c1", State: aiops.StateIncident, RootCause: podKey,
+		}},
+		risk: &aiops.EntityRiskDetail{Metrics: []*aiops.AnomalyResult{
+			{MetricName: "container_anomaly", IsAnomaly: true},
+		}},
+		risks: []*aiops.EntityRisk{{EntityKey: "prod-web/pod/api-7c9fd8b6f5-m4pwt", RFinal: 0.1}},
+	}
+
+	f := &fixture{repo: newMemRunRepo(), source: source, cmds: &stubCommands{}}
+	cfg.Runbooks = runbooks
+	cfg.Source = source
+	cfg.Commands = f.cmds
+	cfg.Snapshots = &stubSnapshots{snap: snap}
+	cfg.Repo = f.repo
+	f.m = NewManager(cfg)
+	f.m.sleep = func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	return f
+}
+
+func (f *fixture) escalate() {
+	f.m.OnTransition(aiops.TransitionEvent{
+		Kind: aiops.TransitionEscalated, IncidentID: "inc-1", State: aiops.StateIncident,
+	})
+	f.m.wg.Wait()
+}
+
+func (f *fixture) onlyRun(t *testing.T) *database.AIOpsRunbookRun {
+	t.Helper()
+	runs, _ := f.repo.List(context.Background(), database.RunbookRunQueryOpts{})
+	if len(runs) != 1 {
+		t.Fatalf("runs = %d, want 1", len(runs))
+	}
+	return runs[0]
+}
+
+// ==================== 测试用例 ====================
+
+func TestAutoRun_RestartsOwnerDeployment(t *testing.T) {
+	f := newFixture(t, ModeAuto, Config{})
+	f.escalate()
+
+	run := f.onlyRun(t)
+	if run.Status != database.RunbookRunSucceeded {
+		t.Fatalf("status = %s (err=%s), want succeeded", run.Status, run.Error)
+	}
+	if len(f.cmds.reqs) != 1 {
+		t.Fatalf("commands = %d, want 1", len(f.cmds.reqs))
+	}
+	req := f.cmds.reqs[0]
+	if req.Action != command.ActionRestart || req.TargetKind != "Deployment" ||
+		req.TargetNamespace != "prod-web" || req.TargetName != "api" || req.Source != commandSource {
+		t.Errorf("unexpected command: %+v", req)
+	}
+
+	steps := DecodeSteps(run.Steps)
+	if len(steps) != 3 || steps[0].CommandID != "cmd-1" {
+		t.Fatalf("steps = %+v", steps)
+	}
+	for _, s := range steps {
+		if s.Status != StepSucceeded {
+			t.Errorf("step %s status = %s", s.Name, s.Status)
+		}
+	}
+}
+
+func TestAutoRun_NoMatch(t *testing.T) {
+	f := newFixture(t, ModeAuto, Config{})
+	f.source.risk.Metrics[0].IsAnomaly = false
+	f.escalate()
+
+	if runs, _ := f.repo.List(context.Background(), database.RunbookRunQueryOpts{}); len(runs) != 0 {
+		t.Fatalf("runs = %d, want 0", len(runs))
+	}
+}
+
+func TestAutoRun_WarningTransitionIgnoredForIncidentState(t *testing.T) {
+	f := newFixture(t, ModeAuto, Config{})
+	f.m.OnTransition(aiops.TransitionEvent{
+		Kind: aiops.TransitionWarningCreated, IncidentID: "inc-1", EntityKey: podKey, State: aiops.StateWarning,
+	})
+	f.m.wg.Wait()
+
+	if runs, _ := f.repo.List(context.Background(), database.RunbookRunQueryOpts{}); len(runs) != 0 {
+		t.Fatalf("runs = %d, want 0", len(runs))
+	}
+}
+
+func TestDryRun_IssuesNoCommands(t *testing.T) {
+	f := newFixture(t, ModeApproval, Config{DryRun: true})
+	f.escalate()
+
+	run := f.onlyRun(t)
+	if !run.DryRun || run.Status != database.RunbookRunSucceeded {
+		t.Fatalf("dryRun=%v status=%s, want dry run succeeded", run.DryRun, run.Status)
+	}
+	if f.cmds.count() != 0 {
+		t.Errorf("commands = %d, want 0", f.cmds.count())
+	}
+	for _, s := range DecodeSteps(run.Steps) {
+		if s.Status != StepSkipped || s.Message == "" {
+			t.Errorf("step %s: status=%s message=%q", s.Name, s.Status, s.Message)
+		}
+	}
+}
+
+func TestApprovalFlow(t *testing.T) {
+	f := newFixture(t, ModeApproval, Config{})
+	f.escalate()
+
+	run := f.onlyRun(t)
+	if run.Status != database.RunbookRunPending || f.cmds.count() != 0 {
+		t.Fatalf("status = %s commands = %d, want pending without commands", run.Status, f.cmds.count())
+	}
+
+	ctx := context.Background()
+	if _, err := f.m.Approve(ctx, run.ID, "alice"); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	f.m.wg.Wait()
+	if _, err := f.m.Approve(ctx, run.ID, "bob"); !errors.Is(err, ErrRunNotPending) {
+		t.Errorf("second Approve err = %v, want ErrRunNotPending", err)
+	}
+
+	run = f.onlyRun(t)
+	if run.Status != database.RunbookRunSucceeded || run.ApprovedBy != "alice" || f.cmds.count() != 1 {
+		t.Errorf("status=%s approvedBy=%s commands=%d", run.Status, run.ApprovedBy, f.cmds.count())
+	}
+}
+
+func TestApprove_RechecksIncidentAndLimits(t *testing.T) {
+	f := newFixture(t, ModeApproval, Config{MaxRunsPerHour: 1})
+	f.escalate()
+	run := f.onlyRun(t)
+	ctx := context.Background()
+
+	// 事件已恢复 / 误报时拒绝审批，记录保持待审批
+	resolved := time.Now()
+	f.source.detail.ResolvedAt = &resolved
+	if _, err := f.m.Approve(ctx, run.ID, "alice"); !errors.Is(err, aiops.ErrIncidentResolved) {
+		t.Errorf("resolved Approve err = %v, want ErrIncidentResolved", err)
+	}
+	f.source.detail.ResolvedAt = nil
+	f.source.detail.FalsePositive = true
+	if _, err := f.m.Approve(ctx, run.ID, "alice"); !errors.Is(err, aiops.ErrFalsePositive) {
+		t.Errorf("false positive Approve err = %v, want ErrFalsePositive", err)
+	}
+	f.source.detail.FalsePositive = false
+	if got := f.onlyRun(t); got.Status != database.RunbookRunPending || f.cmds.count() != 0 {
+		t.Fatalf("status=%s commands=%d, want pending without commands", got.Status, f.cmds.count())
+	}
+
+	// 待审批不占用额度；手动执行用尽额度后审批被限流
+	if _, err := f.m.Trigger(ctx, "oom-restart", "inc-1", "bob", false); err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	f.m.wg.Wait()
+	if _, err := f.m.Approve(ctx, run.ID, "alice"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Approve err = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestApprove_ReplansAgainstCurrentSnapshot(t *testing.T) {
+	f := newFixture(t, ModeApproval, Config{})
+	f.escalate()
+	run := f.onlyRun(t)
+
+	// 审批前 ReplicaSet 已归属新的 Deployment
+	snaps := f.m.cfg.Snapshots.(*stubSnapshots)
+	snaps.snap.ReplicaSets[0].OwnerName = "api-v2"
+	if _, err := f.m.Approve(context.Background(), run.ID, "alice"); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	f.m.wg.Wait()
+
+	if f.cmds.count() != 1 || f.cmds.reqs[0].TargetName != "api-v2" {
+		t.Fatalf("commands = %+v, want restart of api-v2", f.cmds.reqs)
+	}
+
+	// 目标已无法解析时拒绝审批
+	f2 := newFixture(t, ModeApproval, Config{})
+	f2.escalate()
+	run = f2.onlyRun(t)
+	f2.m.cfg.Snapshots.(*stubSnapshots).snap.Pods = nil
+	if _, err := f2.m.Approve(context.Background(), run.ID, "alice"); err == nil {
+		t.Error("Approve with unresolvable target should fail")
+	}
+	if got := f2.onlyRun(t); got.Status != database.RunbookRunPending {
+		t.Errorf("status = %s, want pending", got.Status)
+	}
+}
+
+func TestReject(t *testing.T) {
+	f := newFixture(t, ModeApproval, Config{})
+	f.escalate()
+	run := f.onlyRun(t)
+
+	if _, err := f.m.Reject(context.Background(), run.ID, "alice", "not now"); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	run = f.onlyRun(t)
+	if run.Status != database.RunbookRunRejected || run.Error != "not now" || f.cmds.count() != 0 {
+		t.Errorf("status=%s error=%q commands=%d", run.Status, run.Error, f.cmds.count())
+	}
+}
+
+func TestFailedStep_SkipsRemaining(t *testing.T) {
+	f := newFixture(t, ModeAuto, Config{})
+	f.cmds.fail = true
+	f.escalate()
+
+	run := f.onlyRun(t)
+	if run.Status != database.RunbookRunFailed || run.Error == "" {
+		t.Fatalf("status = %s error = %q, want failed", run.Status, run.Error)
+	}
+	steps := DecodeSteps(run.Steps)
+	if steps[0].Status != StepFailed || steps[1].Status != StepSkipped || steps[2].Status != StepSkipped {
+		t.Errorf("steps = %s/%s/%s", steps[0].Status, steps[1].Status, steps[2].Status)
+	}
+}
+
+func TestVerify_TimesOutWhileReplicaUnhealthy(t *testing.T) {
+	f := newFixture(t, ModeAuto, Config{})
+	f.source.risks[0].RFinal = 0.9
+	clock := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	f.m.now = func() time.Time { return clock }
+	f.m.sleep = func(ctx context.Context, d time.Duration) error { clock = clock.Add(d); return nil }
+	f.escalate()
+
+	run := f.onlyRun(t)
+	steps := DecodeSteps(run.Steps)
+	if run.Status != database.RunbookRunFailed || steps[2].Status != StepFailed {
+		t.Fatalf("status = %s verify = %s, want verify failure", run.Status, steps[2].Status)
+	}
+}
+
+func TestManualTrigger_RateLimited(t *testing.T) {
+	f := newFixture(t, ModeApproval, Config{MaxRunsPerHour: 1})
+	ctx := context.Background()
+
+	run, err := f.m.Trigger(ctx, "oom-restart", "inc-1", "alice", false)
+	if err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	f.m.wg.Wait()
+	if run.Trigger != TriggerManual || run.ApprovedBy != "alice" || f.cmds.count() != 1 {
+		t.Fatalf("trigger=%s approvedBy=%s commands=%d", run.Trigger, run.ApprovedBy, f.cmds.count())
+	}
+
+	if _, err := f.m.Trigger(ctx, "oom-restart", "inc-1", "alice", false); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("second Trigger err = %v, want ErrRateLimited", err)
+	}
+	// 演练不受限流
+	if _, err := f.m.Trigger(ctx, "oom-restart", "inc-1", "alice", true); err != nil {
+		t.Errorf("dry run Trigger err = %v", err)
+	}
+	f.m.wg.Wait()
+	if _, err := f.m.Trigger(ctx, "missing", "inc-1", "alice", false); !errors.Is(err, ErrRunbookNotFound) {
+		t.Errorf("missing runbook err = %v", err)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(2)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	if err := l.allow("c1", "a", time.Minute, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.allow("c1", "a", time.Minute, now.Add(30*time.Second)); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("cooldown err = %v, want ErrRateLimited", err)
+	}
+	if err := l.allow("c1", "b", time.Minute, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.allow("c1", "c", time.Minute, now.Add(2*time.Minute)); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("hourly err = %v, want ErrRateLimited", err)
+	}
+	if err := l.allow("c2", "c", time.Minute, now.Add(2*time.Minute)); err != nil {
+		t.Errorf("other cluster err = %v", err)
+	}
+	if err := l.allow("c1", "c", time.Minute, now.Add(61*time.Minute)); err != nil {
+		t.Errorf("after an hour err = %v", err)
+	}
+}
+
+func TestResolveCommand(t *testing.T) {
+	f := newFixture(t, ModeAuto, Config{})
+	snap, _ := f.m.cfg.Snapshots.GetSnapshot("c1")
+
+	res, err := resolveCommand(&Step{Action: ActionDeletePod}, snap, podKey)
+	if err != nil || res.Command != command.ActionDelete || res.TargetKind != "Pod" || res.TargetName != "api-7c9fd8b6f5-x2kqz" {
+		t.Errorf("delete_pod: %+v, %v", res, err)
+	}
+	res, err = resolveCommand(&Step{Action: ActionCordon}, snap, podKey)
+	if err != nil || res.Command != command.ActionCordon || res.TargetName != "node-1" || res.TargetNamespace != "" {
+		t.Errorf("cordon: %+v, %v", res, err)
+	}
+	if _, err := resolveCommand(&Step{Action: ActionRestart}, snap, "prod-web/pod/other"); err == nil {
+		t.Error("restart of unknown pod should fail")
+	}
+}
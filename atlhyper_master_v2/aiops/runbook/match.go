@@ -0,0 +1,153 @@
+// atlhyper_master_v2/aiops/runbook/match.go
+// 事件匹配与步骤目标解析（基于集群快照）
+package runbook
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/model_v3/cluster"
+	"AtlHyper/model_v3/command"
+)
+
+// matchesEntity 实体类型与命名空间匹配（手动触发也需满足，否则无法解析目标）
+func (m *Match) matchesEntity(entityKey string) bool {
+	namespace, entityType, _ := splitEntityKey(entityKey)
+	if entityType == "" {
+		return false
+	}
+	if m.EntityType != "" && m.EntityType != entityType {
+		return false
+	}
+	if len(m.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range m.Namespaces {
+		if ok, _ := path.Match(pattern, namespace); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSignal 异常指标、容器原因、控制器类型匹配（仅自动触发检查）
+func (m *Match) matchesSignal(risk *aiops.EntityRiskDetail, snap *cluster.ClusterSnapshot, entityKey string) bool {
+	if m.Metric != "" && !hasAnomaly(risk, m.Metric) {
+		return false
+	}
+	if m.Reason == "" && m.OwnerKind == "" {
+		return true
+	}
+
+	pod := findPod(snap, entityKey)
+	if pod == nil {
+		return false
+	}
+	if m.Reason != "" && !hasContainerReason(pod, m.Reason) {
+		return false
+	}
+	if m.OwnerKind != "" {
+		kind, _ := podOwner(snap, pod)
+		if !strings.EqualFold(kind, m.OwnerKind) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAnomaly 实体当前是否存在指定异常指标
+func hasAnomaly(risk *aiops.EntityRiskDetail, metric string) bool {
+	if risk == nil {
+		return false
+	}
+	for _, a := range risk.Metrics {
+		if a.IsAnomaly && a.MetricName == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// hasContainerReason 任一容器当前或最近一次终止原因匹配
+func hasContainerReason(pod *cluster.Pod, reason string) bool {
+	for i := range pod.Containers {
+		c := &pod.Containers[i]
+		if c.StateReason == reason || c.LastTerminationReason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// findPod 在快照中查找 Pod 实体
+func findPod(snap *cluster.ClusterSnapshot, entityKey string) *cluster.Pod {
+	namespace, entityType, name := splitEntityKey(entityKey)
+	if snap == nil || entityType != "pod" {
+		return nil
+	}
+	for i := range snap.Pods {
+		p := &snap.Pods[i]
+		if p.Summary.Namespace == namespace && p.Summary.Name == name {
+			return &snap.Pods[i]
+		}
+	}
+	return nil
+}
+
+// podOwner 解析 Pod 的顶层控制器（ReplicaSet 上溯到 Deployment）
+func podOwner(snap *cluster.ClusterSnapshot, pod *cluster.Pod) (kind, name string) {
+	kind, name = pod.Summary.OwnerKind, pod.Summary.OwnerName
+	if kind != "ReplicaSet" {
+		return kind, name
+	}
+	for i := range snap.ReplicaSets {
+		rs := &snap.ReplicaSets[i]
+		if rs.Namespace == pod.Summary.Namespace && rs.Name == name {
+			if rs.OwnerKind != "" {
+				return rs.OwnerKind, rs.OwnerName
+			}
+			break
+		}
+	}
+	return kind, name
+}
+
+// resolveCommand 将指令步骤解析为具体的 command 目标
+func resolveCommand(step *Step, snap *cluster.ClusterSnapshot, entityKey string) (*StepResult, error) {
+	namespace, entityType, name := splitEntityKey(entityKey)
+	res := &StepResult{Params: step.Params}
+
+	switch step.Action {
+	case ActionRestart, ActionScale:
+		pod := findPod(snap, entityKey)
+		if pod == nil {
+			return nil, fmt.Errorf("pod %s not found in cluster snapshot", entityKey)
+		}
+		kind, owner := podOwner(snap, pod)
+		if kind != "Deployment" {
+			return nil, fmt.Errorf("pod %s is not owned by a Deployment (owner: %s %s)", entityKey, kind, owner)
+		}
+		res.Command = step.Action
+		res.TargetKind, res.TargetNamespace, res.TargetName = "Deployment", namespace, owner
+
+	case ActionDeletePod:
+		// Agent 通过 delete + Kind=Pod 删除 Pod（与 Web 端 Pod 重启一致）
+		res.Command = command.ActionDelete
+		res.TargetKind, res.TargetNamespace, res.TargetName = "Pod", namespace, name
+
+	case ActionCordon:
+		node := name
+		if entityType == "pod" {
+			pod := findPod(snap, entityKey)
+			if pod == nil || pod.Summary.NodeName == "" {
+				return nil, fmt.Errorf("node of pod %s not found in cluster snapshot", entityKey)
+			}
+			node = pod.Summary.NodeName
+		}
+		res.Command = command.ActionCordon
+		res.TargetKind, res.TargetName = "Node", node
+	}
+	return res, nil
+}
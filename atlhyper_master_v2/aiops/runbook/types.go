@@ -0,0 +1,318 @@
+// atlhyper_master_v2/aiops/runbook/types.go
+// Runbook 定义（YAML）与校验
+//
+// 示例:
+//
+//	name: oom-restart
+//	description: Deployment Pod OOMKilled 时滚动重启并确认恢复
+//	match:
+//	  entityType: pod
+//	  metric: container_anomaly
+//	  reason: OOMKilled
+//	  ownerKind: Deployment
+//	  namespaces: ["prod-*"]
+//	mode: approval
+//	cooldown: 30m
+//	steps:
+//	  - action: restart
+//	    target: owner
+//	  - wait: 1m
+//	  - verify:
+//	      metric: container_anomaly
+//	      timeout: 5m
+package runbook
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 执行模式
+const (
+	ModeAuto     = "auto"     // 匹配后自动执行
+	ModeApproval = "approval" // 匹配后等待一键审批（默认）
+)
+
+// 触发阶段（match.state）
+const (
+	StateWarning  = "warning"  // 事件创建即匹配
+	StateIncident = "incident" // 升级为 Incident 后匹配（默认）
+)
+
+// 步骤目标（step.target）
+const (
+	TargetEntity = "entity" // 匹配到的实体本身
+	TargetOwner  = "owner"  // Pod 所属 Deployment
+	TargetNode   = "node"   // Pod 所在节点（节点实体即自身）
+)
+
+// 步骤动作（step.action），均映射为已有的 command 指令
+const (
+	ActionRestart   = "restart"    // 滚动重启 Deployment
+	ActionScale     = "scale"      // Deployment 扩缩容（params.replicas）
+	ActionDeletePod = "delete_pod" // 删除 Pod 触发重建
+	ActionCordon    = "cordon"     // 封锁节点
+)
+
+// 默认值与上限
+const (
+	defaultCooldown       = 30 * time.Minute
+	defaultStepTimeout    = 60 * time.Second
+	defaultVerifyTimeout  = 5 * time.Minute
+	defaultVerifyInterval = 15 * time.Second
+	defaultVerifyMaxRisk  = 0.5
+	maxSteps              = 20
+	maxWait               = 30 * time.Minute
+)
+
+var namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,62}[a-z0-9])?$`)
+
+// Runbook 运行手册：匹配事件后按顺序执行的步骤
+type Runbook struct {
+	Name        string        `yaml:"name" json:"name"`
+	Description string        `yaml:"description" json:"description,omitempty"`
+	Match       Match         `yaml:"match" json:"match"`
+	Mode        string        `yaml:"mode" json:"mode"`
+	DryRun      bool          `yaml:"dryRun" json:"dryRun"`
+	Cooldown    time.Duration `yaml:"cooldown" json:"cooldown"` // 同一工作负载两次执行的最小间隔
+	Steps       []Step        `yaml:"steps" json:"steps"`
+
+	File string `yaml:"-" json:"file"` // 来源文件
+}
+
+// Match 事件匹配条件（未填写的条件不参与匹配）
+type Match struct {
+	EntityType string   `yaml:"entityType" json:"entityType,omitempty"` // pod / node / service
+	Metric     string   `yaml:"metric" json:"metric,omitempty"`         // 实体当前异常指标，如 container_anomaly
+	Reason     string   `yaml:"reason" json:"reason,omitempty"`         // 容器异常原因（仅 Pod），如 OOMKilled
+	OwnerKind  string   `yaml:"ownerKind" json:"ownerKind,omitempty"`   // Pod 所属控制器类型，如 Deployment
+	Namespaces []string `yaml:"namespaces" json:"namespaces,omitempty"` // 命名空间（支持 * 通配）
+	State      string   `yaml:"state" json:"state"`                     // warning / incident
+}
+
+// Step 单个步骤：指令 / 等待 / 验证三选一
+type Step struct {
+	Name    string         `yaml:"name" json:"name,omitempty"`
+	Action  string         `yaml:"action" json:"action,omitempty"`
+	Target  string         `yaml:"target" json:"target,omitempty"`
+	Params  map[string]any `yaml:"params" json:"params,omitempty"`
+	Timeout time.Duration  `yaml:"timeout" json:"timeout,omitempty"` // 等待 Agent 执行结果的超时
+	Wait    time.Duration  `yaml:"wait" json:"wait,omitempty"`
+	Verify  *Verify        `yaml:"verify" json:"verify,omitempty"`
+}
+
+// Verify 验证步骤：轮询工作负载风险直到恢复或超时
+// 同一工作负载的所有副本（按 EntityPattern 归并）均满足条件才算通过
+type Verify struct {
+	Metric   string        `yaml:"metric" json:"metric,omitempty"` // 指标不再异常
+	MaxRisk  float64       `yaml:"maxRisk" json:"maxRisk"`         // 风险分低于该值（0~1）
+	Timeout  time.Duration `yaml:"timeout" json:"timeout"`
+	Interval time.Duration `yaml:"interval" json:"interval"`
+}
+
+// Kind 步骤类型
+func (s *Step) Kind() string {
+	switch {
+	case s.Action != "":
+		return "command"
+	case s.Verify != nil:
+		return "verify"
+	default:
+		return "wait"
+	}
+}
+
+// Label 步骤展示名
+func (s *Step) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	switch s.Kind() {
+	case "command":
+		return s.Action + " " + s.Target
+	case "verify":
+		return "verify"
+	default:
+		return "wait " + s.Wait.String()
+	}
+}
+
+// Validate 校验并填充默认值
+func (rb *Runbook) Validate() error {
+	if !namePattern.MatchString(rb.Name) {
+		return fmt.Errorf("invalid name %q: lowercase letters, digits and '-' only", rb.Name)
+	}
+
+	switch rb.Mode {
+	case "":
+		rb.Mode = ModeApproval
+	case ModeAuto, ModeApproval:
+	default:
+		return fmt.Errorf("invalid mode %q", rb.Mode)
+	}
+	if rb.Cooldown == 0 {
+		rb.Cooldown = defaultCooldown
+	}
+	if rb.Cooldown < 0 {
+		return fmt.Errorf("cooldown must not be negative")
+	}
+
+	if err := rb.Match.validate(); err != nil {
+		return fmt.Errorf("match: %w", err)
+	}
+
+	if len(rb.Steps) == 0 {
+		return fmt.Errorf("at least one step is required")
+	}
+	if len(rb.Steps) > maxSteps {
+		return fmt.Errorf("too many steps (%d > %d)", len(rb.Steps), maxSteps)
+	}
+	for i := range rb.Steps {
+		if err := rb.Steps[i].validate(rb.Match.EntityType); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (m *Match) validate() error {
+	switch m.State {
+	case "":
+		m.State = StateIncident
+	case StateWarning, StateIncident:
+	default:
+		return fmt.Errorf("invalid state %q", m.State)
+	}
+	switch m.EntityType {
+	case "", "pod", "node", "service":
+	default:
+		return fmt.Errorf("invalid entityType %q", m.EntityType)
+	}
+	if (m.Reason != "" || m.OwnerKind != "") && m.EntityType != "pod" {
+		return fmt.Errorf("reason / ownerKind require entityType pod")
+	}
+	for _, ns := range m.Namespaces {
+		if _, err := path.Match(ns, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q", ns)
+		}
+	}
+	return nil
+}
+
+func (s *Step) validate(entityType string) error {
+	kinds := 0
+	if s.Action != "" {
+		kinds++
+	}
+	if s.Wait != 0 {
+		kinds++
+	}
+	if s.Verify != nil {
+		kinds++
+	}
+	if kinds != 1 {
+		return fmt.Errorf("exactly one of action / wait / verify is required")
+	}
+
+	switch s.Kind() {
+	case "wait":
+		if s.Wait < 0 || s.Wait > maxWait {
+			return fmt.Errorf("wait must be between 0 and %s", maxWait)
+		}
+	case "verify":
+		v := s.Verify
+		if v.Timeout == 0 {
+			v.Timeout = defaultVerifyTimeout
+		}
+		if v.Interval == 0 {
+			v.Interval = defaultVerifyInterval
+		}
+		if v.MaxRisk == 0 {
+			v.MaxRisk = defaultVerifyMaxRisk
+		}
+		if v.Timeout < 0 || v.Timeout > maxWait || v.Interval < 0 || v.MaxRisk < 0 || v.MaxRisk > 1 {
+			return fmt.Errorf("invalid verify settings")
+		}
+	case "command":
+		return s.validateCommand(entityType)
+	}
+	return nil
+}
+
+func (s *Step) validateCommand(entityType string) error {
+	if s.Timeout == 0 {
+		s.Timeout = defaultStepTimeout
+	}
+	if s.Timeout < 0 || s.Timeout > maxWait {
+		return fmt.Errorf("timeout must be between 0 and %s", maxWait)
+	}
+
+	switch s.Action {
+	case ActionRestart, ActionScale:
+		if s.Target == "" {
+			s.Target = TargetOwner
+		}
+		if s.Target != TargetOwner {
+			return fmt.Errorf("%s only supports target owner", s.Action)
+		}
+		if s.Action == ActionScale {
+			n, ok := intParam(s.Params, "replicas")
+			if !ok || n < 0 {
+				return fmt.Errorf("scale requires params.replicas >= 0")
+			}
+		}
+	case ActionDeletePod:
+		if s.Target == "" {
+			s.Target = TargetEntity
+		}
+		if s.Target != TargetEntity {
+			return fmt.Errorf("delete_pod only supports target entity")
+		}
+	case ActionCordon:
+		if s.Target == "" {
+			s.Target = TargetNode
+		}
+		if s.Target != TargetNode {
+			return fmt.Errorf("cordon only supports target node")
+		}
+	default:
+		return fmt.Errorf("unsupported action %q", s.Action)
+	}
+
+	// owner / delete_pod 需要 Pod 实体才能解析目标
+	if s.Target != TargetNode && entityType != "pod" {
+		return fmt.Errorf("%s requires match.entityType pod", s.Action)
+	}
+	if s.Target == TargetNode && entityType != "pod" && entityType != "node" {
+		return fmt.Errorf("cordon requires match.entityType pod or node")
+	}
+	return nil
+}
+
+// intParam 读取整数参数（YAML 解析为 int，JSON 为 float64）
+func intParam(params map[string]any, key string) (int, bool) {
+	switch v := params[key].(type) {
+	case int:
+		return v, true
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), true
+		}
+	}
+	return 0, false
+}
+
+// splitEntityKey 拆分实体 key（"namespace/type/name"，集群级实体命名空间返回空）
+func splitEntityKey(entityKey string) (namespace, entityType, name string) {
+	parts := strings.SplitN(entityKey, "/", 3)
+	if len(parts) != 3 {
+		return "", "", ""
+	}
+	if parts[0] == "_cluster" {
+		parts[0] = ""
+	}
+	return parts[0], parts[1], parts[2]
+}
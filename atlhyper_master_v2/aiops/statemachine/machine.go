@@ -33,53 +33,44 @@ type transitionCondition struct {
 
 // StateMachine 状态机管理器
 type StateMachine struct {
-	mu         sync.RWMutex
-	entries    map[string]*aiops.StateMachineEntry // entityKey -> entry
-	callback   TransitionCallback
-	conditions []transitionCondition
+	mu          sync.RWMutex
+	entries     map[string]*aiops.StateMachineEntry // entityKey -> entry
+	callback    TransitionCallback
+	thresholds  ThresholdResolver
+	maintenance MaintenanceChecker
+	now         func() time.Time
 }
 
-// NewStateMachine 创建状态机
+// NewStateMachine 创建状态机（使用默认阈值）
 func NewStateMachine(callback TransitionCallback) *StateMachine {
-	sm := &StateMachine{
+	return &StateMachine{
 		entries:  make(map[string]*aiops.StateMachineEntry),
 		callback: callback,
+		now:      time.Now,
 	}
-	sm.conditions = []transitionCondition{
-		{
-			FromState:   aiops.StateHealthy,
-			ToState:     aiops.StateWarning,
-			RiskCheck:   func(r float64) bool { return r > 0.2 },
-			MinDuration: 2 * time.Minute,
-		},
-		// Warning → Healthy（风险持续低于阈值 5 分钟 → 自动恢复）
-		// 顺序重要：放在 Warning→Incident 之前，低风险时先匹配 →Healthy
-		{
-			FromState:   aiops.StateWarning,
-			ToState:     aiops.StateHealthy,
-			RiskCheck:   func(r float64) bool { return r < 0.15 },
-			MinDuration: 5 * time.Minute,
-		},
-		{
-			FromState:   aiops.StateWarning,
-			ToState:     aiops.StateIncident,
-			RiskCheck:   func(r float64) bool { return r > 0.5 },
-			MinDuration: 5 * time.Minute,
-		},
-		{
-			FromState:   aiops.StateIncident,
-			ToState:     aiops.StateRecovery,
-			RiskCheck:   func(r float64) bool { return r < 0.15 },
-			MinDuration: 10 * time.Minute,
-		},
-		{
-			FromState:   aiops.StateRecovery,
-			ToState:     aiops.StateWarning,
-			RiskCheck:   func(r float64) bool { return r > 0.2 },
-			MinDuration: 0, // 复发立即触发
-		},
+}
+
+// SetThresholds 设置阈值解析器（nil = 默认阈值）
+// 已有条目的状态和计时保留，下一次评估起按新阈值判断
+func (sm *StateMachine) SetThresholds(resolve ThresholdResolver) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.thresholds = resolve
+}
+
+// thresholdsFor 解析实体的阈值
+func (sm *StateMachine) thresholdsFor(clusterID, entityKey string) Thresholds {
+	if sm.thresholds == nil {
+		return DefaultThresholds()
 	}
-	return sm
+	return sm.thresholds(clusterID, entityKey)
+}
+
+// SetClock 设置时钟（离线回放按快照时间推进）
+func (sm *StateMachine) SetClock(now func() time.Time) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.now = now
 }
 
 // GetEntry 获取指定实体的状态机条目
@@ -96,13 +87,27 @@ func (sm *StateMachine) RestoreEntry(entry *aiops.StateMachineEntry) {
 	sm.entries[entry.EntityKey] = entry
 }
 
+// RemoveByIncident 移除关联指定事件的条目（人工关闭事件用），返回被移除的条目
+// 不触发回调，由调用方负责关闭事件
+func (sm *StateMachine) RemoveByIncident(incidentID string) *aiops.StateMachineEntry {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	for entityKey, entry := range sm.entries {
+		if entry.IncidentID == incidentID {
+			delete(sm.entries, entityKey)
+			return entry
+		}
+	}
+	return nil
+}
+
 // CleanupStaleEntries 清理长时间未评估的过期条目
 // 实体从集群消失（Pod 被删除）后 SM 条目永远不会被 Evaluate 触达，需要超时自动关闭
 func (sm *StateMachine) CleanupStaleEntries(ctx context.Context, staleThreshold time.Duration) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	now := time.Now()
+	now := sm.now()
 	for entityKey, entry := range sm.entries {
 		if entry.LastEvaluatedAt == 0 {
 			continue
@@ -118,7 +123,7 @@ func (sm *StateMachine) CleanupStaleEntries(ctx context.Context, staleThreshold
 }
 
 // getOrCreate 获取或创建状态机条目
-func (sm *StateMachine) getOrCreate(entityKey string) *aiops.StateMachineEntry {
+func (sm *StateMachine) getOrCreate(clusterID, entityKey string) *aiops.StateMachineEntry {
 	entry, ok := sm.entries[entityKey]
 	if !ok {
 		entry = &aiops.StateMachineEntry{
@@ -127,5 +132,6 @@ func (sm *StateMachine) getOrCreate(entityKey string) *aiops.StateMachineEntry {
 		}
 		sm.entries[entityKey] = entry
 	}
+	entry.ClusterID = clusterID
 	return entry
 }
@@ -627,3 +627,147 @@ func TestWarningToHealthy_FullCycle(t *testing.T) {
 		t.Fatalf("expected 2 warningCreated, got %d", cb.warningCreated)
 	}
 }
+
+// TestRemoveByIncident 测试人工关闭事件时移除状态机条目（不触发回调）
+func TestRemoveByIncident(t *testing.T) {
+	cb := &mockCallback{}
+	sm := NewStateMachine(cb)
+
+	sm.RestoreEntry(&aiops.StateMachineEntry{
+		EntityKey:    "ns/pod/pod-a",
+		CurrentState: aiops.StateIncident,
+		IncidentID:   "inc-manual-1",
+	})
+
+	if got := sm.RemoveByIncident("inc-missing"); got != nil {
+		t.Fatalf("expected nil for unknown incident, got %+v", got)
+	}
+
+	got := sm.RemoveByIncident("inc-manual-1")
+	if got == nil || got.EntityKey != "ns/pod/pod-a" {
+		t.Fatalf("expected removed entry for ns/pod/pod-a, got %+v", got)
+	}
+	if sm.GetEntry("ns/pod/pod-a") != nil {
+		t.Fatal("entry should be removed")
+	}
+	if cb.stable != 0 {
+		t.Fatalf("RemoveByIncident should not trigger OnStable, got %d", cb.stable)
+	}
+}
+
+// TestSetThresholds 按实体解析阈值，切换阈值时保留已有状态与计时
+func TestSetThresholds(t *testing.T) {
+	cb := &mockCallback{}
+	sm := NewStateMachine(cb)
+	ctx := context.Background()
+	now := time.Now()
+	sm.SetClock(func() time.Time { return now })
+
+	fast := DefaultThresholds()
+	fast.WarningAfter = 30 * time.Second
+	fast.IncidentRisk = 0.4
+	sm.SetThresholds(func(clusterID, entityKey string) Thresholds {
+		if entityKey == "prod/service/api" {
+			return fast
+		}
+		return DefaultThresholds()
+	})
+
+	risks := map[string]*aiops.EntityRisk{
+		"prod/service/api":  {EntityKey: "prod/service/api", RFinal: 0.45},
+		"batch/service/etl": {EntityKey: "batch/service/etl", RFinal: 0.45},
+	}
+	sm.Evaluate(ctx, "cluster-1", risks, nil)
+	now = now.Add(time.Minute)
+	sm.Evaluate(ctx, "cluster-1", risks, nil)
+
+	if sm.GetEntry("prod/service/api").CurrentState != aiops.StateWarning {
+		t.Fatal("prod 实体应按 30s 阈值进入 Warning")
+	}
+	if sm.GetEntry("batch/service/etl").CurrentState != aiops.StateHealthy {
+		t.Fatal("其他实体仍需持续 2 分钟")
+	}
+	if sm.GetEntry("prod/service/api").ClusterID != "cluster-1" {
+		t.Error("条目应记录所属集群")
+	}
+
+	// 热切换回默认阈值: 状态保留，0.45 不再满足 Warning→Incident (> 0.5)
+	sm.SetThresholds(nil)
+	now = now.Add(10 * time.Minute)
+	sm.Evaluate(ctx, "cluster-1", risks, nil)
+	now = now.Add(10 * time.Minute)
+	sm.Evaluate(ctx, "cluster-1", risks, nil)
+	entry := sm.GetEntry("prod/service/api")
+	if entry.CurrentState != aiops.StateWarning || cb.stateEscalated != 0 {
+		t.Fatalf("切换阈值后应保留 Warning 且不升级, got %s escalated=%d", entry.CurrentState, cb.stateEscalated)
+	}
+}
+
+// TestThresholds_ClusterRiskDisabled ClusterRisk 阈值为 0 时不按集群风险升级
+func TestThresholds_ClusterRiskDisabled(t *testing.T) {
+	cb := &mockCallback{}
+	sm := NewStateMachine(cb)
+	ctx := context.Background()
+	th := DefaultThresholds()
+	th.ClusterRisk = 0
+	sm.SetThresholds(func(string, string) Thresholds { return th })
+
+	entity := "ns/service/svc-a"
+	sm.RestoreEntry(&aiops.StateMachineEntry{EntityKey: entity, CurrentState: aiops.StateWarning, IncidentID: "inc-test"})
+	risks := makeEntityRisks(entity, 0.3)
+	sm.Evaluate(ctx, "cluster-1", risks, &aiops.ClusterRisk{Risk: 95})
+	if sm.GetEntry(entity).ConditionMetSince != 0 {
+		t.Fatal("ClusterRisk 升级已关闭，不应开始计时")
+	}
+}
+
+// TestMaintenance 维护窗口内继续评分但不创建 / 升级事件，窗口结束后重新计时
+func TestMaintenance(t *testing.T) {
+	cb := &mockCallback{}
+	sm := NewStateMachine(cb)
+	ctx := context.Background()
+	now := time.Now()
+	sm.SetClock(func() time.Time { return now })
+
+	windowEnd := now.Add(11 * time.Minute)
+	sm.SetMaintenance(func(clusterID, entityKey string, at time.Time) bool {
+		return clusterID == "cluster-1" && at.Before(windowEnd)
+	})
+
+	entity := "ns/service/svc-a"
+	warning := "ns/service/svc-b"
+	sm.RestoreEntry(&aiops.StateMachineEntry{EntityKey: warning, ClusterID: "cluster-1", CurrentState: aiops.StateWarning, IncidentID: "inc-b"})
+	risks := map[string]*aiops.EntityRisk{
+		entity:  {EntityKey: entity, RFinal: 0.6},
+		warning: {EntityKey: warning, RFinal: 0.6},
+	}
+
+	for i := 0; i < 5; i++ {
+		sm.Evaluate(ctx, "cluster-1", risks, nil)
+		now = now.Add(2 * time.Minute)
+	}
+	if cb.warningCreated != 0 || cb.stateEscalated != 0 {
+		t.Fatalf("维护窗口内不应创建或升级事件, warning=%d escalated=%d", cb.warningCreated, cb.stateEscalated)
+	}
+	if e := sm.GetEntry(entity); e.CurrentState != aiops.StateHealthy || e.LastRFinal != 0.6 {
+		t.Fatalf("维护窗口内应继续评分且保持 Healthy, got %+v", e)
+	}
+	if !sm.ShouldSuppress(entity) {
+		t.Error("维护窗口内应抑制告警")
+	}
+
+	// 窗口结束: 重新计时，持续 2 分钟后才创建 Warning
+	now = windowEnd
+	sm.Evaluate(ctx, "cluster-1", risks, nil)
+	if cb.warningCreated != 0 {
+		t.Fatal("窗口结束后须重新满足持续时间")
+	}
+	if sm.ShouldSuppress(entity) {
+		t.Error("窗口结束后不应抑制")
+	}
+	now = now.Add(2 * time.Minute)
+	sm.Evaluate(ctx, "cluster-1", risks, nil)
+	if cb.warningCreated != 1 {
+		t.Fatalf("窗口结束后应创建 Warning, got %d", cb.warningCreated)
+	}
+}
@@ -1,11 +1,31 @@
 // atlhyper_master_v2/aiops/statemachine/suppressor.go
-// 告警抑制逻辑
+// 告警抑制逻辑: 重复事件抑制 + 维护窗口
 package statemachine
 
-import "AtlHyper/atlhyper_master_v2/aiops"
+import (
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+)
+
+// MaintenanceChecker 判断实体在 now 时刻是否处于维护窗口
+type MaintenanceChecker func(clusterID, entityKey string, now time.Time) bool
+
+// SetMaintenance 设置维护窗口判定（nil = 不启用）
+// 维护窗口内继续评分与计时回落，但不创建、升级或复发事件
+func (sm *StateMachine) SetMaintenance(check MaintenanceChecker) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.maintenance = check
+}
+
+// inMaintenance 实体是否处于维护窗口（调用方持有锁）
+func (sm *StateMachine) inMaintenance(clusterID, entityKey string, now time.Time) bool {
+	return sm.maintenance != nil && sm.maintenance(clusterID, entityKey, now)
+}
 
 // ShouldSuppress 判断是否应该抑制告警
-// 同一实体在 Incident/Recovery 状态期间不创建新 Incident
+// 同一实体在 Incident/Recovery 状态期间不创建新 Incident；处于维护窗口的实体同样抑制
 func (sm *StateMachine) ShouldSuppress(entityKey string) bool {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
@@ -15,7 +35,10 @@ func (sm *StateMachine) ShouldSuppress(entityKey string) bool {
 		return false
 	}
 
-	return entry.CurrentState == aiops.StateIncident || entry.CurrentState == aiops.StateRecovery
+	if entry.CurrentState == aiops.StateIncident || entry.CurrentState == aiops.StateRecovery {
+		return true
+	}
+	return sm.inMaintenance(entry.ClusterID, entityKey, sm.now())
 }
 
 // GetActiveIncidentID 获取实体当前关联的 Incident ID
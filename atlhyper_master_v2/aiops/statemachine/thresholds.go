@@ -0,0 +1,81 @@
+// atlhyper_master_v2/aiops/statemachine/thresholds.go
+// 状态转换阈值: 默认值 + 按集群 / 实体解析（由 AIOps 策略覆盖）
+package statemachine
+
+import (
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+)
+
+// Thresholds 状态转换阈值
+// 风险阈值比较 R_final，持续时间为条件连续满足的时长
+type Thresholds struct {
+	WarningRisk  float64 // Healthy→Warning、Recovery→Warning: R_final > WarningRisk
+	IncidentRisk float64 // Warning→Incident: R_final > IncidentRisk
+	RecoveryRisk float64 // Warning→Healthy、Incident→Recovery: R_final < RecoveryRisk
+	ClusterRisk  float64 // Warning→Incident 也可由 ClusterRisk > 该值触发（0 = 不启用）
+
+	WarningAfter  time.Duration // Healthy→Warning 持续时间
+	HealthyAfter  time.Duration // Warning→Healthy 持续时间
+	IncidentAfter time.Duration // Warning→Incident 持续时间
+	RecoveryAfter time.Duration // Incident→Recovery 持续时间
+	StableAfter   time.Duration // Recovery→Stable: 该时长内未复发即关闭事件
+}
+
+// DefaultThresholds 返回默认阈值
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		WarningRisk:   0.2,
+		IncidentRisk:  0.5,
+		RecoveryRisk:  0.15,
+		ClusterRisk:   80,
+		WarningAfter:  2 * time.Minute,
+		HealthyAfter:  5 * time.Minute,
+		IncidentAfter: 5 * time.Minute,
+		RecoveryAfter: 10 * time.Minute,
+		StableAfter:   48 * time.Hour,
+	}
+}
+
+// ThresholdResolver 按集群 + 实体解析阈值（每次评估调用，策略变更即时生效）
+type ThresholdResolver func(clusterID, entityKey string) Thresholds
+
+// conditions 由阈值构建转换条件
+// 对同一状态的多个出口条件按顺序匹配第一个满足的条件
+func (th Thresholds) conditions() []transitionCondition {
+	return []transitionCondition{
+		{
+			FromState:   aiops.StateHealthy,
+			ToState:     aiops.StateWarning,
+			RiskCheck:   func(r float64) bool { return r > th.WarningRisk },
+			MinDuration: th.WarningAfter,
+		},
+		// Warning → Healthy（风险持续低于恢复阈值 → 自动恢复）
+		// 顺序重要：放在 Warning→Incident 之前，低风险时先匹配 →Healthy
+		{
+			FromState:   aiops.StateWarning,
+			ToState:     aiops.StateHealthy,
+			RiskCheck:   func(r float64) bool { return r < th.RecoveryRisk },
+			MinDuration: th.HealthyAfter,
+		},
+		{
+			FromState:   aiops.StateWarning,
+			ToState:     aiops.StateIncident,
+			RiskCheck:   func(r float64) bool { return r > th.IncidentRisk },
+			MinDuration: th.IncidentAfter,
+		},
+		{
+			FromState:   aiops.StateIncident,
+			ToState:     aiops.StateRecovery,
+			RiskCheck:   func(r float64) bool { return r < th.RecoveryRisk },
+			MinDuration: th.RecoveryAfter,
+		},
+		{
+			FromState:   aiops.StateRecovery,
+			ToState:     aiops.StateWarning,
+			RiskCheck:   func(r float64) bool { return r > th.WarningRisk },
+			MinDuration: 0, // 复发立即触发
+		},
+	}
+}
@@ -19,10 +19,10 @@ func (sm *StateMachine) Evaluate(
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	now := time.Now()
+	now := sm.now()
 
 	for entityKey, risk := range entityRisks {
-		entry := sm.getOrCreate(entityKey)
+		entry := sm.getOrCreate(clusterID, entityKey)
 		entry.LastRFinal = risk.RFinal
 		entry.LastEvaluatedAt = now.Unix()
 
@@ -40,19 +40,22 @@ func (sm *StateMachine) evaluateEntity(
 	clusterRisk *aiops.ClusterRisk,
 	now time.Time,
 ) {
+	th := sm.thresholdsFor(clusterID, entry.EntityKey)
+	conditions := th.conditions()
+
 	// 查找当前状态下第一个满足的转换条件
 	var matched *transitionCondition
-	for i := range sm.conditions {
-		cond := &sm.conditions[i]
+	for i := range conditions {
+		cond := &conditions[i]
 		if entry.CurrentState != cond.FromState {
 			continue
 		}
 
 		conditionMet := cond.RiskCheck(risk.RFinal)
 
-		// 特殊处理: Warning → Incident 也可由 ClusterRisk > 80 触发
+		// 特殊处理: Warning → Incident 也可由 ClusterRisk 超过阈值（默认 80）触发
 		if cond.FromState == aiops.StateWarning && cond.ToState == aiops.StateIncident {
-			if clusterRisk != nil && clusterRisk.Risk > 80 {
+			if clusterRisk != nil && th.ClusterRisk > 0 && clusterRisk.Risk > th.ClusterRisk {
 				conditionMet = true
 			}
 		}
@@ -68,6 +71,12 @@ func (sm *StateMachine) evaluateEntity(
 		return
 	}
 
+	// 维护窗口内不创建 / 升级 / 复发事件，计时清零，窗口结束后重新计时
+	if worsens(*matched) && sm.inMaintenance(clusterID, entry.EntityKey, now) {
+		entry.ConditionMetSince = 0
+		return
+	}
+
 	if entry.ConditionMetSince == 0 {
 		entry.ConditionMetSince = now.Unix()
 	}
@@ -118,14 +127,24 @@ func (sm *StateMachine) transition(
 	)
 }
 
+// worsens 转换是否使事件产生或加重（Healthy→Warning、Warning→Incident、Recovery→Warning）
+func worsens(cond transitionCondition) bool {
+	switch {
+	case cond.FromState == aiops.StateHealthy && cond.ToState == aiops.StateWarning,
+		cond.FromState == aiops.StateWarning && cond.ToState == aiops.StateIncident,
+		cond.FromState == aiops.StateRecovery && cond.ToState == aiops.StateWarning:
+		return true
+	}
+	return false
+}
+
 // CheckRecoveryToStable 检查 Recovery 状态的实体是否可以转为 Stable
-// 条件: 48h 内 R_final 未再 > 0.5
+// 条件: StableAfter（默认 48h）内未复发
 func (sm *StateMachine) CheckRecoveryToStable(ctx context.Context) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	now := time.Now()
-	stableThreshold := 48 * time.Hour
+	now := sm.now()
 
 	for entityKey, entry := range sm.entries {
 		if entry.CurrentState != aiops.StateRecovery {
@@ -138,7 +157,7 @@ func (sm *StateMachine) CheckRecoveryToStable(ctx context.Context) {
 		}
 
 		duration := time.Duration(now.Unix()-entry.ConditionMetSince) * time.Second
-		if duration < stableThreshold {
+		if duration < sm.thresholdsFor(entry.ClusterID, entityKey).StableAfter {
 			continue
 		}
 
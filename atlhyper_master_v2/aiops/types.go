@@ -2,7 +2,10 @@
 // AIOps 引擎共用类型定义
 package aiops
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 // ==================== 依赖图类型 ====================
 
@@ -117,6 +120,36 @@ type BaselineState struct {
 	Count           int64   `json:"count"`
 	ConsecutiveZero int64   `json:"consecutiveZero"` // 连续零值计数（快速冷启动用）
 	UpdatedAt       int64   `json:"updatedAt"`
+
+	// 季节性基线（仅 seasonal 模式）
+	Mode     BaselineMode     `json:"mode,omitempty"` // 空 = ema
+	Seasonal *SeasonalProfile `json:"-"`
+
+	// 检测算法（空 = ema）及其私有状态（由 baseline.Detector 维护并序列化）
+	Detector      string `json:"detector,omitempty"`
+	DetectorState any    `json:"-"`
+}
+
+// BaselineMode 基线模式（按指标在 risk.RiskConfig 中选择）
+type BaselineMode string
+
+const (
+	BaselineEMA      BaselineMode = "ema"      // 单一 EMA + 方差（默认）
+	BaselineSeasonal BaselineMode = "seasonal" // 按周内小时 / 日内小时分桶
+)
+
+// SeasonalProfile 季节性基线分桶（UTC 时间）
+// 优先使用周内小时桶（区分工作日 / 周末），样本不足时退回日内小时桶，再退回全局 EMA
+type SeasonalProfile struct {
+	Week [168]SeasonalBucket `json:"week"` // weekday*24 + hour
+	Day  [24]SeasonalBucket  `json:"day"`  // hour
+}
+
+// SeasonalBucket 单个时段的 EMA + 方差
+type SeasonalBucket struct {
+	Mean     float64 `json:"m"`
+	Variance float64 `json:"v"`
+	Count    int64   `json:"n"`
 }
 
 // AnomalyResult 异常检测结果
@@ -129,6 +162,8 @@ type AnomalyResult struct {
 	Score        float64 `json:"score"`
 	IsAnomaly    bool    `json:"isAnomaly"`
 	DetectedAt   int64   `json:"detectedAt"`
+	Detector     string  `json:"detector,omitempty"` // 产出结果的检测器（ema / mad / cusum / bocpd / deterministic）
+	Reason       string  `json:"reason,omitempty"`   // 判定依据
 }
 
 // EntityBaseline 实体基线汇总（API 响应）
@@ -270,6 +305,9 @@ const (
 	DefaultAlpha           = 0.033 // α = 2/(60+1), 窗口 60 个采样点
 	AnomalyThreshold       = 3.0   // 3σ 规则
 	SigmoidK               = 2.0   // sigmoid 斜率
+	FeedbackThresholdStep  = 0.5   // 每次误报反馈抬高的阈值（σ）
+	FeedbackThresholdMax   = 6.0   // 误报反馈阈值上限（σ）
+	SeasonalMinCount       = 30    // 季节桶至少 30 个样本才参与检测
 )
 
 // ==================== 状态机类型 ====================
@@ -288,6 +326,7 @@ const (
 // StateMachineEntry 状态机条目（每个实体一个）
 type StateMachineEntry struct {
 	EntityKey         string      `json:"entityKey"`
+	ClusterID         string      `json:"clusterId"`
 	CurrentState      EntityState `json:"currentState"`
 	IncidentID        string      `json:"incidentId"`
 	ConditionMetSince int64       `json:"conditionMetSince"`
@@ -311,8 +350,37 @@ type Incident struct {
 	Recurrence int         `json:"recurrence"`
 	Summary    string      `json:"summary"`
 	CreatedAt  time.Time   `json:"createdAt"`
+
+	// 人工处理状态
+	AckedBy       string     `json:"ackedBy,omitempty"`
+	AckedAt       *time.Time `json:"ackedAt,omitempty"`
+	Assignee      string     `json:"assignee,omitempty"`
+	FalsePositive bool       `json:"falsePositive"`
 }
 
+// TransitionKind 状态机转换类型（供告警通知订阅）
+type TransitionKind string
+
+const (
+	TransitionWarningCreated  TransitionKind = "warning_created"  // Healthy → Warning，新建事件
+	TransitionEscalated       TransitionKind = "escalated"        // Warning → Incident
+	TransitionRecoveryStarted TransitionKind = "recovery_started" // Incident → Recovery
+	TransitionStable          TransitionKind = "stable"           // Recovery/Warning → Stable，事件关闭
+)
+
+// TransitionEvent 状态机转换事件
+type TransitionEvent struct {
+	Kind       TransitionKind
+	IncidentID string
+	EntityKey  string      // 仅 WarningCreated / Stable 携带
+	State      EntityState // 转换后的状态
+	RFinal     float64     // 转换时的实体风险（Stable 为 0）
+	At         time.Time
+}
+
+// TransitionListener 状态机转换监听器
+type TransitionListener func(ev TransitionEvent)
+
 // IncidentEntity 受影响实体
 type IncidentEntity struct {
 	IncidentID string  `json:"incidentId"`
@@ -331,6 +399,7 @@ type IncidentTimeline struct {
 	EventType  string    `json:"eventType"`
 	EntityKey  string    `json:"entityKey"`
 	Detail     string    `json:"detail"`
+	Actor      string    `json:"actor,omitempty"` // 人工操作的执行用户（引擎写入为空）
 }
 
 // 时间线事件类型常量
@@ -341,13 +410,58 @@ const (
 	TimelineRootCauseIdentified = "root_cause_identified"
 	TimelineRecoveryStarted    = "recovery_started"
 	TimelineRecurrence         = "recurrence"
+	TimelineAcknowledged       = "acknowledged"
+	TimelineAssigned           = "assigned"
+	TimelineComment            = "comment"
+	TimelineManualResolved     = "manual_resolved"
+	TimelineFalsePositive      = "false_positive"
+)
+
+// IncidentActionType 人工处理动作类型
+type IncidentActionType string
+
+const (
+	IncidentActionAck           IncidentActionType = "ack"
+	IncidentActionAssign        IncidentActionType = "assign"
+	IncidentActionComment       IncidentActionType = "comment"
+	IncidentActionResolve       IncidentActionType = "resolve"
+	IncidentActionFalsePositive IncidentActionType = "false_positive"
 )
 
+// IncidentAction 人工处理动作
+type IncidentAction struct {
+	Type     IncidentActionType
+	Actor    string // 执行用户
+	Assignee string // assign 动作的被指派人
+	Comment  string // 评论内容，其他动作为可选备注
+}
+
+// 人工处理动作错误
+var (
+	ErrIncidentNotFound = errors.New("incident not found")
+	ErrIncidentResolved = errors.New("incident already resolved")
+	ErrIncidentAcked    = errors.New("incident already acknowledged")
+	ErrFalsePositive    = errors.New("incident already marked as false positive")
+)
+
+// ErrPostmortemNotFound 事件尚未生成复盘文档
+var ErrPostmortemNotFound = errors.New("postmortem not found")
+
 // IncidentDetail 事件详情（API 响应）
 type IncidentDetail struct {
 	Incident
 	Entities []*IncidentEntity  `json:"entities"`
 	Timeline []*IncidentTimeline `json:"timeline"`
+
+	// ClusterState 事件开始时的集群状态（快照历史回溯链接）
+	ClusterState *IncidentClusterState `json:"clusterState,omitempty"`
+}
+
+// IncidentClusterState 事件开始时的集群状态链接
+type IncidentClusterState struct {
+	At          time.Time `json:"at"`          // 事件开始时间（?at= 参数）
+	OverviewURL string    `json:"overviewUrl"` // 事件开始时的集群概览
+	DiffURL     string    `json:"diffUrl"`     // 事件开始到恢复（或当前）之间的资源变更
 }
 
 // IncidentStats 事件统计
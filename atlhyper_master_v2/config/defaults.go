@@ -11,6 +11,15 @@ var defaultDurations = map[string]string{
 	"MASTER_DATAHUB_EVENT_RETENTION":  "30m", // Event 保留时间
 	"MASTER_DATAHUB_HEARTBEAT_EXPIRE":    "45s",  // 心跳过期时间
 	"MASTER_DATAHUB_SNAPSHOT_RETENTION": "15m",  // OTel 快照时间线保留时间
+	"MASTER_DATAHUB_HISTORY_RETENTION":  "6h",   // 快照历史保留时间（0 = 不保存）
+	"MASTER_DATAHUB_HISTORY_INTERVAL":   "1m",   // 快照历史保存间隔
+
+	// -------------------- AgentSDK 安全配置 --------------------
+	"MASTER_AGENTSDK_TOKEN_GRACE": "1h", // Token 轮换后旧 Token 宽限期
+
+	// -------------------- Exec 会话配置 --------------------
+	"MASTER_EXEC_MAX_DURATION":   "30m", // 单个交互式会话最长时长
+	"MASTER_EXEC_ATTACH_TIMEOUT": "30s", // 等待 Agent 接入会话的超时
 
 	// -------------------- 超时配置 --------------------
 	"MASTER_TIMEOUT_COMMAND_POLL": "60s", // 长轮询超时
@@ -25,6 +34,9 @@ var defaultDurations = map[string]string{
 	// -------------------- JWT 配置 --------------------
 	"MASTER_JWT_TOKEN_EXPIRY": "24h", // Token 有效期
 
+	// -------------------- OIDC 配置 --------------------
+	"MASTER_OIDC_STATE_TTL": "10m", // 登录请求有效期
+
 	// -------------------- AI 配置 --------------------
 	"MASTER_AI_TOOL_TIMEOUT": "30s", // Tool 执行超时
 
@@ -34,10 +46,14 @@ var defaultDurations = map[string]string{
 	"MASTER_SLO_RAW_RETENTION":      "48h",    // raw 数据保留时间
 	"MASTER_SLO_HOURLY_RETENTION":   "2160h",  // hourly 数据保留时间 (90 天)
 	"MASTER_SLO_STATUS_RETENTION":   "4320h",  // 状态历史保留时间 (180 天)
+	"MASTER_SLO_BURN_RATE_INTERVAL": "1m",     // 燃烧率告警评估间隔
 
 	// -------------------- 节点指标持久化配置 --------------------
 	"MASTER_METRICS_SAMPLE_INTERVAL":  "30s", // 历史数据采样间隔
 	"MASTER_METRICS_CLEANUP_INTERVAL": "1h",  // 清理检查间隔
+
+	// -------------------- AIOps 快照录制 --------------------
+	"MASTER_AIOPS_RECORD_INTERVAL": "1m", // 同一集群两次录制的最小间隔
 }
 
 // ============================================================
@@ -65,6 +81,12 @@ var defaultInts = map[string]int{
 	// -------------------- 节点指标持久化 --------------------
 	"MASTER_METRICS_RETENTION_DAYS": 30, // 历史数据保留天数
 
+	// -------------------- OIDC 配置 --------------------
+	"MASTER_OIDC_DEFAULT_ROLE": 1, // 未匹配任何组时的角色（0 = 拒绝登录）
+
+	// -------------------- Runbook 自动化 --------------------
+	"MASTER_RUNBOOK_MAX_RUNS_PER_HOUR": 10, // 单集群每小时最多执行次数
+
 	// -------------------- GitHub 配置 --------------------
 	"GITHUB_APP_ID": 0, // GitHub App ID
 }
@@ -80,6 +102,11 @@ var defaultStrings = map[string]string{
 	// -------------------- DataHub 配置 --------------------
 	"MASTER_DATAHUB_TYPE": "memory", // DataHub 类型
 
+	// -------------------- AgentSDK 安全配置 --------------------
+	"MASTER_AGENTSDK_TLS_CERT":      "", // 服务端证书（为空则使用 HTTP）
+	"MASTER_AGENTSDK_TLS_KEY":       "", // 服务端私钥
+	"MASTER_AGENTSDK_TLS_CLIENT_CA": "", // 客户端 CA（非空则强制 mTLS）
+
 	// -------------------- Redis 配置 --------------------
 	"MASTER_REDIS_ADDR":     "localhost:6379", // Redis 地址
 	"MASTER_REDIS_PASSWORD": "",               // Redis 密码
@@ -92,6 +119,17 @@ var defaultStrings = map[string]string{
 	// -------------------- JWT 配置 --------------------
 	"MASTER_JWT_SECRET": "", // JWT 密钥（必须通过环境变量配置）
 
+	// -------------------- OIDC 配置 --------------------
+	"MASTER_OIDC_ISSUER":          "",                                        // IdP Issuer 地址
+	"MASTER_OIDC_CLIENT_ID":       "",                                        // 客户端 ID
+	"MASTER_OIDC_CLIENT_SECRET":   "",                                        // 客户端密钥
+	"MASTER_OIDC_REDIRECT_URL":    "http://localhost:3000/auth/oidc/callback", // Web 回调页
+	"MASTER_OIDC_SCOPES":          "openid,profile,email,groups",             // 请求的 scope（逗号分隔）
+	"MASTER_OIDC_GROUPS_CLAIM":    "groups",                                  // 组声明（如 realm_access.roles）
+	"MASTER_OIDC_ADMIN_GROUPS":    "",                                        // Admin 组（逗号分隔）
+	"MASTER_OIDC_OPERATOR_GROUPS": "",                                        // Operator 组（逗号分隔）
+	"MASTER_OIDC_VIEWER_GROUPS":   "",                                        // Viewer 组（逗号分隔）
+
 	// -------------------- 默认管理员配置 --------------------
 	"MASTER_ADMIN_USERNAME":     "", // 管理员用户名（必须通过环境变量配置）
 	"MASTER_ADMIN_PASSWORD":     "", // 管理员密码（必须通过环境变量配置）
@@ -108,15 +146,44 @@ var defaultStrings = map[string]string{
 	"GITHUB_APP_SLUG":         "",                                          // GitHub App URL slug
 	"GITHUB_PRIVATE_KEY_PATH": "",                                          // GitHub Private Key PEM 文件路径
 	"GITHUB_CALLBACK_URL":     "http://localhost:3000/auth/github/callback", // GitHub App 安装回调 URL
+
+	// -------------------- AIOps 事件告警 --------------------
+	"MASTER_INCIDENT_ALERT_WEB_URL": "http://localhost:3000", // 告警中事件详情链接的 Web 地址
+
+	// -------------------- 值班升级 --------------------
+	"MASTER_ONCALL_ACK_URL": "http://localhost:8080", // 告警确认链接的 Master 对外地址
+
+	// -------------------- Runbook 自动化 --------------------
+	"MASTER_RUNBOOK_DIR": "", // Runbook YAML 目录（为空则不启用）
+
+	// -------------------- AIOps 快照录制 --------------------
+	"MASTER_AIOPS_RECORD_DIR": "", // 快照录制目录（为空则不录制）
 }
 
 // ============================================================
 // 布尔类型默认值
 // ============================================================
 var defaultBools = map[string]bool{
+	// -------------------- AgentSDK 安全配置 --------------------
+	"MASTER_AGENTSDK_REQUIRE_TOKEN": false, // 是否要求所有集群携带 Agent Token
+
+	// -------------------- JWT 配置 --------------------
+	"MASTER_JWT_REQUIRE_READ_AUTH": false, // 只读查询是否要求登录（多租户授权时应开启）
+
+	// -------------------- OIDC 配置 --------------------
+	"MASTER_OIDC_ENABLED":          false, // 是否启用 SSO 登录
+	"MASTER_OIDC_LOCAL_ADMIN_ONLY": true,  // 启用 SSO 后本地密码登录仅限 Admin
+
 	// -------------------- AI 配置 --------------------
 	"MASTER_AI_ENABLED": false, // 是否启用 AI 功能（Web UI 配置）
 
 	// -------------------- Event 告警 --------------------
 	"MASTER_EVENT_ALERT_ENABLED": true, // 是否启用事件告警
+
+	// -------------------- AIOps 事件告警 --------------------
+	"MASTER_INCIDENT_ALERT_ENABLED": true,  // 是否启用 AIOps 事件告警
+	"MASTER_INCIDENT_ALERT_WARNING": false, // Warning 阶段也通知
+
+	// -------------------- Runbook 自动化 --------------------
+	"MASTER_RUNBOOK_DRY_RUN": false, // 全局演练模式（只解析步骤，不下发指令）
 }
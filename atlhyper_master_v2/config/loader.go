@@ -29,12 +29,27 @@ func LoadConfig() {
 		TesterPort:   getInt("MASTER_TESTER_PORT"),
 	}
 
+	GlobalConfig.AgentSDK = AgentSDKConfig{
+		RequireToken:    getBool("MASTER_AGENTSDK_REQUIRE_TOKEN"),
+		TokenGrace:      getDuration("MASTER_AGENTSDK_TOKEN_GRACE"),
+		TLSCertFile:     getString("MASTER_AGENTSDK_TLS_CERT"),
+		TLSKeyFile:      getString("MASTER_AGENTSDK_TLS_KEY"),
+		TLSClientCAFile: getString("MASTER_AGENTSDK_TLS_CLIENT_CA"),
+	}
+
+	GlobalConfig.Exec = ExecConfig{
+		MaxDuration:   getDuration("MASTER_EXEC_MAX_DURATION"),
+		AttachTimeout: getDuration("MASTER_EXEC_ATTACH_TIMEOUT"),
+	}
+
 	GlobalConfig.DataHub = DataHubConfig{
 		Type:              getString("MASTER_DATAHUB_TYPE"),
 		EventRetention:    getDuration("MASTER_DATAHUB_EVENT_RETENTION"),
 		SnapshotRetain:    getInt("MASTER_DATAHUB_SNAPSHOT_RETAIN"),
 		HeartbeatExpire:   getDuration("MASTER_DATAHUB_HEARTBEAT_EXPIRE"),
 		SnapshotRetention: getDuration("MASTER_DATAHUB_SNAPSHOT_RETENTION"),
+		HistoryRetention:  getDuration("MASTER_DATAHUB_HISTORY_RETENTION"),
+		HistoryInterval:   getDuration("MASTER_DATAHUB_HISTORY_INTERVAL"),
 	}
 
 	GlobalConfig.Redis = RedisConfig{
@@ -61,14 +76,50 @@ func LoadConfig() {
 		CheckInterval: getDuration("MASTER_EVENT_ALERT_INTERVAL"),
 	}
 
+	GlobalConfig.IncidentAlert = IncidentAlertConfig{
+		Enabled:       getBool("MASTER_INCIDENT_ALERT_ENABLED"),
+		NotifyWarning: getBool("MASTER_INCIDENT_ALERT_WARNING"),
+		WebURL:        getString("MASTER_INCIDENT_ALERT_WEB_URL"),
+	}
+
+	GlobalConfig.Oncall = OncallConfig{
+		AckURL: getString("MASTER_ONCALL_ACK_URL"),
+	}
+
+	GlobalConfig.Runbook = RunbookConfig{
+		Dir:            getString("MASTER_RUNBOOK_DIR"),
+		DryRun:         getBool("MASTER_RUNBOOK_DRY_RUN"),
+		MaxRunsPerHour: getInt("MASTER_RUNBOOK_MAX_RUNS_PER_HOUR"),
+	}
+
+	GlobalConfig.AIOps.RecordDir = getString("MASTER_AIOPS_RECORD_DIR")
+	GlobalConfig.AIOps.RecordInterval = getDuration("MASTER_AIOPS_RECORD_INTERVAL")
+
 	GlobalConfig.Timeout = TimeoutConfig{
 		CommandPoll: getDuration("MASTER_TIMEOUT_COMMAND_POLL"),
 		Heartbeat:   getDuration("MASTER_TIMEOUT_HEARTBEAT"),
 	}
 
 	GlobalConfig.JWT = JWTConfig{
-		SecretKey:   getString("MASTER_JWT_SECRET"),
-		TokenExpiry: getDuration("MASTER_JWT_TOKEN_EXPIRY"),
+		SecretKey:       getString("MASTER_JWT_SECRET"),
+		TokenExpiry:     getDuration("MASTER_JWT_TOKEN_EXPIRY"),
+		RequireReadAuth: getBool("MASTER_JWT_REQUIRE_READ_AUTH"),
+	}
+
+	GlobalConfig.OIDC = OIDCConfig{
+		Enabled:        getBool("MASTER_OIDC_ENABLED"),
+		IssuerURL:      getString("MASTER_OIDC_ISSUER"),
+		ClientID:       getString("MASTER_OIDC_CLIENT_ID"),
+		ClientSecret:   getString("MASTER_OIDC_CLIENT_SECRET"),
+		RedirectURL:    getString("MASTER_OIDC_REDIRECT_URL"),
+		Scopes:         getStringSlice("MASTER_OIDC_SCOPES"),
+		GroupsClaim:    getString("MASTER_OIDC_GROUPS_CLAIM"),
+		AdminGroups:    getStringSlice("MASTER_OIDC_ADMIN_GROUPS"),
+		OperatorGroups: getStringSlice("MASTER_OIDC_OPERATOR_GROUPS"),
+		ViewerGroups:   getStringSlice("MASTER_OIDC_VIEWER_GROUPS"),
+		DefaultRole:    getInt("MASTER_OIDC_DEFAULT_ROLE"),
+		StateTTL:       getDuration("MASTER_OIDC_STATE_TTL"),
+		LocalAdminOnly: getBool("MASTER_OIDC_LOCAL_ADMIN_ONLY"),
 	}
 
 	GlobalConfig.Admin = AdminConfig{
@@ -95,6 +146,7 @@ func LoadConfig() {
 		RawRetention:      getDuration("MASTER_SLO_RAW_RETENTION"),
 		HourlyRetention:   getDuration("MASTER_SLO_HOURLY_RETENTION"),
 		StatusRetention:   getDuration("MASTER_SLO_STATUS_RETENTION"),
+		BurnRateInterval:  getDuration("MASTER_SLO_BURN_RATE_INTERVAL"),
 	}
 
 	GlobalConfig.MetricsPersist = MetricsPersistConfig{
@@ -114,6 +166,10 @@ func LoadConfig() {
 	validateRequired("MASTER_JWT_SECRET", GlobalConfig.JWT.SecretKey)
 	validateRequired("MASTER_ADMIN_USERNAME", GlobalConfig.Admin.Username)
 	validateRequired("MASTER_ADMIN_PASSWORD", GlobalConfig.Admin.Password)
+	if GlobalConfig.OIDC.Enabled {
+		validateRequired("MASTER_OIDC_ISSUER", GlobalConfig.OIDC.IssuerURL)
+		validateRequired("MASTER_OIDC_CLIENT_ID", GlobalConfig.OIDC.ClientID)
+	}
 
 	log.Info("Master 配置加载完成",
 		"gatewayPort", GlobalConfig.Server.GatewayPort,
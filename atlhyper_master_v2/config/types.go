@@ -11,6 +11,23 @@ type ServerConfig struct {
 	TesterPort   int // Tester 端口（测试服务）
 }
 
+// AgentSDKConfig AgentSDK 通道安全配置
+// Token: 每集群独立凭证，由 Master 签发/轮换（见 cluster_agent_tokens 表）
+// TLS: 配置证书后 AgentSDK 以 HTTPS 提供服务；配置 ClientCA 后强制 mTLS
+type AgentSDKConfig struct {
+	RequireToken    bool          // 是否要求所有集群携带 Token（false 时仅校验已签发 Token 的集群）
+	TokenGrace      time.Duration // Token 轮换后旧 Token 的宽限期
+	TLSCertFile     string        // 服务端证书路径（为空则使用 HTTP）
+	TLSKeyFile      string        // 服务端私钥路径
+	TLSClientCAFile string        // 客户端 CA 路径（非空则要求 Agent 提供客户端证书）
+}
+
+// ExecConfig 交互式 exec 会话配置
+type ExecConfig struct {
+	MaxDuration   time.Duration // 单个会话最长时长，到期后由 Master 强制关闭
+	AttachTimeout time.Duration // 下发指令后等待 Agent 接入会话的超时
+}
+
 // DataHubConfig DataHub 配置
 type DataHubConfig struct {
 	Type              string        // 类型: memory / redis
@@ -18,6 +35,8 @@ type DataHubConfig struct {
 	SnapshotRetain    int           // 快照保留数量
 	HeartbeatExpire   time.Duration // 心跳过期时间
 	SnapshotRetention time.Duration // OTel 快照时间线保留时间（默认 15min）
+	HistoryRetention  time.Duration // 快照历史保留时间（默认 6h，用于时间回溯）
+	HistoryInterval   time.Duration // 快照历史保存间隔（默认 1min）
 }
 
 // DatabaseConfig 数据库配置
@@ -43,8 +62,26 @@ type TimeoutConfig struct {
 
 // JWTConfig JWT 配置
 type JWTConfig struct {
-	SecretKey   string        // JWT 密钥
-	TokenExpiry time.Duration // Token 有效期
+	SecretKey       string        // JWT 密钥
+	TokenExpiry     time.Duration // Token 有效期
+	RequireReadAuth bool          // 只读查询是否要求登录（false 时匿名可读，登录用户按授权范围过滤）
+}
+
+// OIDCConfig OIDC 单点登录配置
+type OIDCConfig struct {
+	Enabled        bool          // 是否启用 SSO 登录
+	IssuerURL      string        // IdP Issuer 地址（Keycloak / Dex 等）
+	ClientID       string        // 客户端 ID
+	ClientSecret   string        // 客户端密钥（公共客户端可为空）
+	RedirectURL    string        // 回调地址（Web 回调页）
+	Scopes         []string      // 请求的 scope
+	GroupsClaim    string        // 组声明名称（支持点分路径）
+	AdminGroups    []string      // 映射为 Admin 的组
+	OperatorGroups []string      // 映射为 Operator 的组
+	ViewerGroups   []string      // 映射为 Viewer 的组
+	DefaultRole    int           // 未匹配任何组时的角色（0 = 拒绝登录）
+	StateTTL       time.Duration // 登录请求有效期
+	LocalAdminOnly bool          // 启用 SSO 后本地密码登录仅限 Admin（应急入口）
 }
 
 // AdminConfig 默认管理员配置
@@ -87,6 +124,25 @@ type EventAlertConfig struct {
 	CheckInterval time.Duration // 检测间隔
 }
 
+// IncidentAlertConfig AIOps 事件告警配置
+type IncidentAlertConfig struct {
+	Enabled       bool   // 是否启用 AIOps 事件告警
+	NotifyWarning bool   // Warning 阶段也通知（默认仅升级为 Incident 后通知）
+	WebURL        string // Web 地址，用于告警中的事件详情链接
+}
+
+// OncallConfig 值班升级配置
+type OncallConfig struct {
+	AckURL string // Master 对外地址，用于邮件 / Slack 中的确认链接（为空则不附链接）
+}
+
+// RunbookConfig 事件 Runbook 自动化配置
+type RunbookConfig struct {
+	Dir            string // Runbook YAML 目录（为空则不启用）
+	DryRun         bool   // 全局演练模式：所有执行只解析步骤，不下发指令
+	MaxRunsPerHour int    // 单集群每小时最多执行次数（含待审批）
+}
+
 // LogConfig 日志配置
 type LogConfig struct {
 	Level  string // 日志级别: debug / info / warn / error (默认 info)
@@ -102,6 +158,7 @@ type SLOConfig struct {
 	RawRetention      time.Duration // raw 数据保留时间（默认 48h）
 	HourlyRetention   time.Duration // hourly 数据保留时间（默认 90d）
 	StatusRetention   time.Duration // 状态历史保留时间（默认 180d）
+	BurnRateInterval  time.Duration // 燃烧率告警评估间隔（默认 1m）
 }
 
 // MetricsPersistConfig 节点指标持久化配置
@@ -115,6 +172,9 @@ type MetricsPersistConfig struct {
 type AIOpsConfig struct {
 	Enable        bool          // 是否启用 AIOps 引擎（默认 true）
 	FlushInterval time.Duration // 基线状态 flush 间隔（默认 5min）
+
+	RecordDir      string        // 快照录制目录（供离线回放，为空则不录制）
+	RecordInterval time.Duration // 同一集群两次录制的最小间隔（默认 1min）
 }
 
 // GitHubConfig GitHub App 配置
@@ -129,13 +189,19 @@ type GitHubConfig struct {
 type AppConfig struct {
 	Log            LogConfig
 	Server         ServerConfig
+	AgentSDK       AgentSDKConfig
+	Exec           ExecConfig
 	DataHub        DataHubConfig
 	Database       DatabaseConfig
 	Redis          RedisConfig
 	Event          EventConfig
 	EventAlert     EventAlertConfig
+	IncidentAlert  IncidentAlertConfig
+	Oncall         OncallConfig
+	Runbook        RunbookConfig
 	Timeout        TimeoutConfig
 	JWT            JWTConfig
+	OIDC           OIDCConfig
 	Admin          AdminConfig
 	AI             AIConfig
 	SLO            SLOConfig
@@ -18,8 +18,21 @@ type DB struct {
 	User           UserRepository
 	Event          ClusterEventRepository
 	Notify         NotifyChannelRepository
+	NotifyRoute    NotifyRouteRepository
+	NotifySilence  NotifySilenceRepository
+	NotifyInhibit  NotifyInhibitRuleRepository
+	NotifyDelivery NotifyDeliveryRepository
+	NotifyTemplate NotifyTemplateRepository
+	Oncall         OncallScheduleRepository
+	OncallOverride OncallOverrideRepository
+	Escalation     EscalationPolicyRepository
+	EscalationLog  EscalationRepository
 	Cluster        ClusterRepository
+	AgentToken     AgentTokenRepository
 	Command        CommandHistoryRepository
+	ExecSession    ExecSessionRepository
+	RoleBinding    RoleBindingRepository
+	UserIdentity   UserIdentityRepository
 	Settings       SettingsRepository
 	AIConversation AIConversationRepository
 	AIMessage      AIMessageRepository
@@ -28,9 +41,14 @@ type DB struct {
 	AIModel        AIProviderModelRepository
 	SLO SLORepository
 
-	AIOpsBaseline AIOpsBaselineRepository
-	AIOpsGraph     AIOpsGraphRepository
-	AIOpsIncident  AIOpsIncidentRepository
+	AIOpsBaseline    AIOpsBaselineRepository
+	AIOpsGraph       AIOpsGraphRepository
+	AIOpsIncident    AIOpsIncidentRepository
+	AIOpsFeedback    AIOpsFeedbackRepository
+	AIOpsPostmortem  AIOpsPostmortemRepository
+	AIOpsRunbookRun  AIOpsRunbookRunRepository
+	AIOpsPolicy      AIOpsPolicyRepository
+	AIOpsMaintenance AIOpsMaintenanceRepository
 
 	AIRoleBudget AIRoleBudgetRepository
 	AIReport     AIReportRepository
@@ -68,6 +86,25 @@ type UserRepository interface {
 	UpdateLastLogin(ctx context.Context, id int64, ip string) error
 }
 
+// UserIdentityRepository 外部身份接口（OIDC 登录关联本地用户）
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *UserIdentity) error
+	// Touch 更新最近登录时间与邮箱
+	Touch(ctx context.Context, id int64, email string) error
+	DeleteByUser(ctx context.Context, userID int64) error
+	GetBySubject(ctx context.Context, issuer, subject string) (*UserIdentity, error)
+}
+
+// RoleBindingRepository 角色绑定接口（按集群/命名空间授权）
+type RoleBindingRepository interface {
+	Create(ctx context.Context, binding *RoleBinding) error
+	Delete(ctx context.Context, id int64) error
+	DeleteByUser(ctx context.Context, userID int64) error
+	GetByID(ctx context.Context, id int64) (*RoleBinding, error)
+	ListByUser(ctx context.Context, userID int64) ([]*RoleBinding, error)
+	List(ctx context.Context) ([]*RoleBinding, error)
+}
+
 // ClusterEventRepository Event 持久化接口
 type ClusterEventRepository interface {
 	// 写入
@@ -104,6 +141,111 @@ type NotifyChannelRepository interface {
 	ListEnabled(ctx context.Context) ([]*NotifyChannel, error)
 }
 
+// NotifyRouteRepository 告警路由接口
+type NotifyRouteRepository interface {
+	Create(ctx context.Context, route *NotifyRoute) error
+	Update(ctx context.Context, route *NotifyRoute) error
+	Delete(ctx context.Context, id int64) error
+	GetByID(ctx context.Context, id int64) (*NotifyRoute, error)
+	List(ctx context.Context) ([]*NotifyRoute, error)
+}
+
+// NotifySilenceRepository 告警静默接口
+type NotifySilenceRepository interface {
+	Create(ctx context.Context, silence *NotifySilence) error
+	Expire(ctx context.Context, id int64, at time.Time) error
+	GetByID(ctx context.Context, id int64) (*NotifySilence, error)
+	List(ctx context.Context) ([]*NotifySilence, error)
+	ListActive(ctx context.Context, now time.Time) ([]*NotifySilence, error)
+	DeleteExpiredBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// NotifyInhibitRuleRepository 告警抑制规则接口
+type NotifyInhibitRuleRepository interface {
+	Create(ctx context.Context, rule *NotifyInhibitRule) error
+	Update(ctx context.Context, rule *NotifyInhibitRule) error
+	Delete(ctx context.Context, id int64) error
+	GetByID(ctx context.Context, id int64) (*NotifyInhibitRule, error)
+	List(ctx context.Context) ([]*NotifyInhibitRule, error)
+}
+
+// NotifyDeliveryRepository 通知投递记录接口
+type NotifyDeliveryRepository interface {
+	Create(ctx context.Context, d *NotifyDelivery) error
+	// UpdateAttempt 更新发送结果（status / attempts / last_error / next_attempt_at / sent_at）
+	UpdateAttempt(ctx context.Context, d *NotifyDelivery) error
+	GetByID(ctx context.Context, id int64) (*NotifyDelivery, error)
+	List(ctx context.Context, opts NotifyDeliveryQueryOpts) ([]*NotifyDelivery, error)
+	Count(ctx context.Context, opts NotifyDeliveryQueryOpts) (int64, error)
+	// ListDue 到期待重试的记录（pending / failed 且 next_attempt_at <= now）
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*NotifyDelivery, error)
+	// Requeue 重置为 pending 并立即重试（手动重发）
+	Requeue(ctx context.Context, id int64, now time.Time) error
+	// DeleteFinishedBefore 清理早于 before 的 sent / dead 记录
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// NotifyTemplateRepository 自定义通知模板接口
+type NotifyTemplateRepository interface {
+	// Upsert 按 (name, variant) 创建或更新
+	Upsert(ctx context.Context, t *NotifyTemplate) error
+	// Delete 删除自定义模板（恢复内置模板）
+	Delete(ctx context.Context, name, variant string) error
+	Get(ctx context.Context, name, variant string) (*NotifyTemplate, error)
+	List(ctx context.Context) ([]*NotifyTemplate, error)
+}
+
+// OncallScheduleRepository 值班表接口
+type OncallScheduleRepository interface {
+	Create(ctx context.Context, s *OncallSchedule) error
+	Update(ctx context.Context, s *OncallSchedule) error
+	Delete(ctx context.Context, id int64) error
+	GetByID(ctx context.Context, id int64) (*OncallSchedule, error)
+	List(ctx context.Context) ([]*OncallSchedule, error)
+}
+
+// OncallOverrideRepository 值班替班接口
+type OncallOverrideRepository interface {
+	Create(ctx context.Context, o *OncallOverride) error
+	Delete(ctx context.Context, id int64) error
+	GetByID(ctx context.Context, id int64) (*OncallOverride, error)
+	// ListBySchedule 值班表的替班（按开始时间排序，含已结束）
+	ListBySchedule(ctx context.Context, scheduleID int64) ([]*OncallOverride, error)
+	// ListActive 时间点 at 生效的替班
+	ListActive(ctx context.Context, scheduleID int64, at time.Time) ([]*OncallOverride, error)
+	DeleteEndedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EscalationPolicyRepository 升级策略接口
+type EscalationPolicyRepository interface {
+	Create(ctx context.Context, p *EscalationPolicy) error
+	Update(ctx context.Context, p *EscalationPolicy) error
+	Delete(ctx context.Context, id int64) error
+	GetByID(ctx context.Context, id int64) (*EscalationPolicy, error)
+	List(ctx context.Context) ([]*EscalationPolicy, error)
+}
+
+// EscalationRepository 告警升级记录接口
+type EscalationRepository interface {
+	Create(ctx context.Context, e *Escalation) error
+	// UpdateProgress 更新升级进度（level / next_at）
+	UpdateProgress(ctx context.Context, e *Escalation) error
+	GetByID(ctx context.Context, id int64) (*Escalation, error)
+	GetByToken(ctx context.Context, token string) (*Escalation, error)
+	// GetOpen 策略下该告警未结束的升级
+	GetOpen(ctx context.Context, policyID int64, alertKey string) (*Escalation, error)
+	List(ctx context.Context, opts EscalationQueryOpts) ([]*Escalation, error)
+	Count(ctx context.Context, opts EscalationQueryOpts) (int64, error)
+	// ListDue 到期待执行下一步骤的升级（open 且 next_at <= now）
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*Escalation, error)
+	// Ack 确认（仅 open 状态生效，返回是否更新）
+	Ack(ctx context.Context, id int64, by string, at time.Time) (bool, error)
+	// ResolveAlert 告警恢复，结束该告警所有未结束的升级
+	ResolveAlert(ctx context.Context, alertKey string, at time.Time) (int64, error)
+	// DeleteFinishedBefore 清理早于 before 的已确认 / 已恢复记录
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
 // ClusterRepository 集群接口
 type ClusterRepository interface {
 	Create(ctx context.Context, cluster *Cluster) error
@@ -114,6 +256,22 @@ type ClusterRepository interface {
 	List(ctx context.Context) ([]*Cluster, error)
 }
 
+// AgentTokenRepository 集群 Agent 凭证接口
+type AgentTokenRepository interface {
+	Upsert(ctx context.Context, token *AgentToken) error
+	GetByCluster(ctx context.Context, clusterID string) (*AgentToken, error)
+	List(ctx context.Context) ([]*AgentToken, error)
+	Delete(ctx context.Context, clusterID string) error
+	UpdateLastUsed(ctx context.Context, clusterID string, at time.Time) error
+}
+
+// ExecSessionRepository 交互式 exec 会话记录接口
+type ExecSessionRepository interface {
+	Create(ctx context.Context, session *ExecSession) error
+	GetBySessionID(ctx context.Context, sessionID string) (*ExecSession, error)
+	List(ctx context.Context, opts ExecSessionQueryOpts) ([]*ExecSession, error)
+}
+
 // CommandHistoryRepository 指令历史接口
 type CommandHistoryRepository interface {
 	Create(ctx context.Context, cmd *CommandHistory) error
@@ -267,6 +425,60 @@ type AIOpsIncidentRepository interface {
 	GetIncidentStats(ctx context.Context, clusterID string, since time.Time) (*AIOpsIncidentStatsRaw, error)
 	TopRootCauses(ctx context.Context, clusterID string, since time.Time, limit int) ([]AIOpsRootCauseCount, error)
 	ListByEntity(ctx context.Context, entityKey string, since time.Time) ([]*AIOpsIncident, error)
+	// Ack 确认事件（已确认时不重复更新）
+	Ack(ctx context.Context, id, by string, at time.Time) (bool, error)
+	Assign(ctx context.Context, id, assignee string) error
+	MarkFalsePositive(ctx context.Context, id string) error
+}
+
+// AIOpsFeedbackRepository 误报反馈阈值数据访问接口
+type AIOpsFeedbackRepository interface {
+	Upsert(ctx context.Context, fb *AIOpsThresholdFeedback) error
+	ListAll(ctx context.Context) ([]*AIOpsThresholdFeedback, error)
+}
+
+// AIOpsPostmortemRepository 事件复盘文档数据访问接口
+type AIOpsPostmortemRepository interface {
+	// Save 保存生成结果（覆盖同一事件已有文档）
+	Save(ctx context.Context, pm *AIOpsPostmortem) error
+	// UpdateContent 保存人工编辑（文档不存在返回 false）
+	UpdateContent(ctx context.Context, incidentID, content, by string, at time.Time) (bool, error)
+	// Get 查询文档（不存在返回 nil）
+	Get(ctx context.Context, incidentID string) (*AIOpsPostmortem, error)
+}
+
+// AIOpsRunbookRunRepository Runbook 执行记录数据访问接口
+type AIOpsRunbookRunRepository interface {
+	Create(ctx context.Context, run *AIOpsRunbookRun) error
+	Update(ctx context.Context, run *AIOpsRunbookRun) error
+	// TransitionStatus 条件更新状态（仅当前状态为 from 时生效），用于审批防并发
+	TransitionStatus(ctx context.Context, id int64, from, to, by string) (bool, error)
+	GetByID(ctx context.Context, id int64) (*AIOpsRunbookRun, error)
+	List(ctx context.Context, opts RunbookRunQueryOpts) ([]*AIOpsRunbookRun, error)
+	Count(ctx context.Context, opts RunbookRunQueryOpts) (int64, error)
+}
+
+// AIOpsPolicyRepository AIOps 策略版本数据访问接口（只追加，不修改已有版本）
+type AIOpsPolicyRepository interface {
+	// Create 插入新版本（name + version 唯一，冲突返回错误）
+	Create(ctx context.Context, p *AIOpsPolicy) error
+	// ListCurrent 每个策略的当前版本（不含已删除）
+	ListCurrent(ctx context.Context) ([]*AIOpsPolicy, error)
+	// ListVersions 策略全部版本（版本号降序）
+	ListVersions(ctx context.Context, name string) ([]*AIOpsPolicy, error)
+	// GetVersion 查询指定版本（version <= 0 为最新版本，不存在返回 nil）
+	GetVersion(ctx context.Context, name string, version int) (*AIOpsPolicy, error)
+}
+
+// AIOpsMaintenanceRepository 维护窗口数据访问接口
+type AIOpsMaintenanceRepository interface {
+	Create(ctx context.Context, w *AIOpsMaintenanceWindow) error
+	Update(ctx context.Context, w *AIOpsMaintenanceWindow) error
+	GetByID(ctx context.Context, id int64) (*AIOpsMaintenanceWindow, error)
+	// List 全部窗口（按开始时间倒序）
+	List(ctx context.Context) ([]*AIOpsMaintenanceWindow, error)
+	// ListOpen 未结束的窗口（ends_at 为空或晚于 now）
+	ListOpen(ctx context.Context, now time.Time) ([]*AIOpsMaintenanceWindow, error)
 }
 
 // ==================== GitHub Integration Repository 接口 ====================
@@ -313,8 +525,21 @@ type Dialect interface {
 	User() UserDialect
 	Event() EventDialect
 	Notify() NotifyDialect
+	NotifyRoute() NotifyRouteDialect
+	NotifySilence() NotifySilenceDialect
+	NotifyInhibit() NotifyInhibitRuleDialect
+	NotifyDelivery() NotifyDeliveryDialect
+	NotifyTemplate() NotifyTemplateDialect
+	Oncall() OncallScheduleDialect
+	OncallOverride() OncallOverrideDialect
+	Escalation() EscalationPolicyDialect
+	EscalationLog() EscalationDialect
 	Cluster() ClusterDialect
+	AgentToken() AgentTokenDialect
 	Command() CommandDialect
+	ExecSession() ExecSessionDialect
+	RoleBinding() RoleBindingDialect
+	UserIdentity() UserIdentityDialect
 	Settings() SettingsDialect
 	AIConversation() AIConversationDialect
 	AIMessage() AIMessageDialect
@@ -328,6 +553,11 @@ type Dialect interface {
 	AIOpsBaseline() AIOpsBaselineDialect
 	AIOpsGraph() AIOpsGraphDialect
 	AIOpsIncident() AIOpsIncidentDialect
+	AIOpsFeedback() AIOpsFeedbackDialect
+	AIOpsPostmortem() AIOpsPostmortemDialect
+	AIOpsRunbookRun() AIOpsRunbookRunDialect
+	AIOpsPolicy() AIOpsPolicyDialect
+	AIOpsMaintenance() AIOpsMaintenanceDialect
 	GitHubInstall() GitHubInstallDialect
 	RepoConfig() RepoConfigDialect
 	DeployConfig() DeployConfigDialect
@@ -382,6 +612,106 @@ type NotifyDialect interface {
 	ScanRow(rows *sql.Rows) (*NotifyChannel, error)
 }
 
+// NotifyRouteDialect 告警路由 SQL 方言
+type NotifyRouteDialect interface {
+	Insert(route *NotifyRoute) (query string, args []any)
+	Update(route *NotifyRoute) (query string, args []any)
+	Delete(id int64) (query string, args []any)
+	SelectByID(id int64) (query string, args []any)
+	SelectAll() (query string, args []any)
+	ScanRow(rows *sql.Rows) (*NotifyRoute, error)
+}
+
+// NotifySilenceDialect 告警静默 SQL 方言
+type NotifySilenceDialect interface {
+	Insert(silence *NotifySilence) (query string, args []any)
+	Expire(id int64, at time.Time) (query string, args []any)
+	SelectByID(id int64) (query string, args []any)
+	SelectAll() (query string, args []any)
+	SelectActive(now time.Time) (query string, args []any)
+	DeleteExpiredBefore(before time.Time) (query string, args []any)
+	ScanRow(rows *sql.Rows) (*NotifySilence, error)
+}
+
+// NotifyInhibitRuleDialect 告警抑制规则 SQL 方言
+type NotifyInhibitRuleDialect interface {
+	Insert(rule *NotifyInhibitRule) (query string, args []any)
+	Update(rule *NotifyInhibitRule) (query string, args []any)
+	Delete(id int64) (query string, args []any)
+	SelectByID(id int64) (query string, args []any)
+	SelectAll() (query string, args []any)
+	ScanRow(rows *sql.Rows) (*NotifyInhibitRule, error)
+}
+
+// NotifyDeliveryDialect 通知投递记录 SQL 方言
+type NotifyDeliveryDialect interface {
+	Insert(d *NotifyDelivery) (query string, args []any)
+	UpdateAttempt(d *NotifyDelivery) (query string, args []any)
+	SelectByID(id int64) (query string, args []any)
+	List(opts NotifyDeliveryQueryOpts) (query string, args []any)
+	Count(opts NotifyDeliveryQueryOpts) (query string, args []any)
+	SelectDue(now time.Time, limit int) (query string, args []any)
+	Requeue(id int64, now time.Time) (query string, args []any)
+	DeleteFinishedBefore(before time.Time) (query string, args []any)
+	ScanRow(rows *sql.Rows) (*NotifyDelivery, error)
+}
+
+// NotifyTemplateDialect 自定义通知模板 SQL 方言
+type NotifyTemplateDialect interface {
+	Upsert(t *NotifyTemplate) (query string, args []any)
+	Delete(name, variant string) (query string, args []any)
+	SelectOne(name, variant string) (query string, args []any)
+	SelectAll() (query string, args []any)
+	ScanRow(rows *sql.Rows) (*NotifyTemplate, error)
+}
+
+// OncallScheduleDialect 值班表 SQL 方言
+type OncallScheduleDialect interface {
+	Insert(s *OncallSchedule) (query string, args []any)
+	Update(s *OncallSchedule) (query string, args []any)
+	Delete(id int64) (query string, args []any)
+	SelectByID(id int64) (query string, args []any)
+	SelectAll() (query string, args []any)
+	ScanRow(rows *sql.Rows) (*OncallSchedule, error)
+}
+
+// OncallOverrideDialect 值班替班 SQL 方言
+type OncallOverrideDialect interface {
+	Insert(o *OncallOverride) (query string, args []any)
+	Delete(id int64) (query string, args []any)
+	SelectByID(id int64) (query string, args []any)
+	SelectBySchedule(scheduleID int64) (query string, args []any)
+	SelectActive(scheduleID int64, at time.Time) (query string, args []any)
+	DeleteEndedBefore(before time.Time) (query string, args []any)
+	ScanRow(rows *sql.Rows) (*OncallOverride, error)
+}
+
+// EscalationPolicyDialect 升级策略 SQL 方言
+type EscalationPolicyDialect interface {
+	Insert(p *EscalationPolicy) (query string, args []any)
+	Update(p *EscalationPolicy) (query string, args []any)
+	Delete(id int64) (query string, args []any)
+	SelectByID(id int64) (query string, args []any)
+	SelectAll() (query string, args []any)
+	ScanRow(rows *sql.Rows) (*EscalationPolicy, error)
+}
+
+// EscalationDialect 告警升级记录 SQL 方言
+type EscalationDialect interface {
+	Insert(e *Escalation) (query string, args []any)
+	UpdateProgress(e *Escalation) (query string, args []any)
+	SelectByID(id int64) (query string, args []any)
+	SelectByToken(token string) (query string, args []any)
+	SelectOpen(policyID int64, alertKey string) (query string, args []any)
+	List(opts EscalationQueryOpts) (query string, args []any)
+	Count(opts EscalationQueryOpts) (query string, args []any)
+	SelectDue(now time.Time, limit int) (query string, args []any)
+	Ack(id int64, by string, at time.Time) (query string, args []any)
+	ResolveAlert(alertKey string, at time.Time) (query string, args []any)
+	DeleteFinishedBefore(before time.Time) (query string, args []any)
+	ScanRow(rows *sql.Rows) (*Escalation, error)
+}
+
 // ClusterDialect 集群 SQL 方言
 type ClusterDialect interface {
 	Insert(cluster *Cluster) (query string, args []any)
@@ -393,6 +723,44 @@ type ClusterDialect interface {
 	ScanRow(rows *sql.Rows) (*Cluster, error)
 }
 
+// AgentTokenDialect 集群 Agent 凭证 SQL 方言
+type AgentTokenDialect interface {
+	Upsert(token *AgentToken) (query string, args []any)
+	SelectByCluster(clusterID string) (query string, args []any)
+	SelectAll() (query string, args []any)
+	Delete(clusterID string) (query string, args []any)
+	UpdateLastUsed(clusterID string, at time.Time) (query string, args []any)
+	ScanRow(rows *sql.Rows) (*AgentToken, error)
+}
+
+// ExecSessionDialect exec 会话记录 SQL 方言
+type ExecSessionDialect interface {
+	Insert(session *ExecSession) (query string, args []any)
+	SelectBySessionID(sessionID string) (query string, args []any)
+	List(opts ExecSessionQueryOpts) (query string, args []any)
+	ScanRow(rows *sql.Rows) (*ExecSession, error)
+}
+
+// UserIdentityDialect 外部身份 SQL 方言
+type UserIdentityDialect interface {
+	Insert(identity *UserIdentity) (query string, args []any)
+	Touch(id int64, email string) (query string, args []any)
+	DeleteByUser(userID int64) (query string, args []any)
+	SelectBySubject(issuer, subject string) (query string, args []any)
+	ScanRow(rows *sql.Rows) (*UserIdentity, error)
+}
+
+// RoleBindingDialect 角色绑定 SQL 方言
+type RoleBindingDialect interface {
+	Insert(binding *RoleBinding) (query string, args []any)
+	Delete(id int64) (query string, args []any)
+	DeleteByUser(userID int64) (query string, args []any)
+	SelectByID(id int64) (query string, args []any)
+	SelectByUser(userID int64) (query string, args []any)
+	SelectAll() (query string, args []any)
+	ScanRow(rows *sql.Rows) (*RoleBinding, error)
+}
+
 // CommandDialect 指令历史 SQL 方言
 type CommandDialect interface {
 	Insert(cmd *CommandHistory) (query string, args []any)
@@ -549,6 +917,54 @@ type AIOpsIncidentDialect interface {
 	ScanIncident(rows *sql.Rows) (*AIOpsIncident, error)
 	ScanEntity(rows *sql.Rows) (*AIOpsIncidentEntity, error)
 	ScanTimeline(rows *sql.Rows) (*AIOpsIncidentTimeline, error)
+	Ack(id, by string, at time.Time) (string, []any)
+	Assign(id, assignee string) (string, []any)
+	MarkFalsePositive(id string) (string, []any)
+}
+
+// AIOpsFeedbackDialect 误报反馈阈值 SQL 方言
+type AIOpsFeedbackDialect interface {
+	Upsert(fb *AIOpsThresholdFeedback) (query string, args []any)
+	SelectAll() (query string, args []any)
+	ScanRow(rows *sql.Rows) (*AIOpsThresholdFeedback, error)
+}
+
+// AIOpsPostmortemDialect 事件复盘文档 SQL 方言
+type AIOpsPostmortemDialect interface {
+	Upsert(pm *AIOpsPostmortem) (query string, args []any)
+	UpdateContent(incidentID, content, by string, at time.Time) (query string, args []any)
+	SelectByIncident(incidentID string) (query string, args []any)
+	ScanRow(rows *sql.Rows) (*AIOpsPostmortem, error)
+}
+
+// AIOpsRunbookRunDialect Runbook 执行记录 SQL 方言
+type AIOpsRunbookRunDialect interface {
+	Insert(run *AIOpsRunbookRun) (query string, args []any)
+	Update(run *AIOpsRunbookRun) (query string, args []any)
+	TransitionStatus(id int64, from, to, by string) (query string, args []any)
+	SelectByID(id int64) (query string, args []any)
+	List(opts RunbookRunQueryOpts) (query string, args []any)
+	Count(opts RunbookRunQueryOpts) (query string, args []any)
+	ScanRow(rows *sql.Rows) (*AIOpsRunbookRun, error)
+}
+
+// AIOpsPolicyDialect AIOps 策略版本 SQL 方言
+type AIOpsPolicyDialect interface {
+	Insert(p *AIOpsPolicy) (query string, args []any)
+	SelectCurrent() (query string, args []any)
+	SelectVersions(name string) (query string, args []any)
+	SelectVersion(name string, version int) (query string, args []any)
+	ScanRow(rows *sql.Rows) (*AIOpsPolicy, error)
+}
+
+// AIOpsMaintenanceDialect 维护窗口 SQL 方言
+type AIOpsMaintenanceDialect interface {
+	Insert(w *AIOpsMaintenanceWindow) (query string, args []any)
+	Update(w *AIOpsMaintenanceWindow) (query string, args []any)
+	SelectByID(id int64) (query string, args []any)
+	SelectAll() (query string, args []any)
+	SelectOpen(now time.Time) (query string, args []any)
+	ScanRow(rows *sql.Rows) (*AIOpsMaintenanceWindow, error)
 }
 
 // ==================== GitHub Integration Dialect 接口 ====================
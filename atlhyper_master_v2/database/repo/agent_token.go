@@ -0,0 +1,69 @@
+// atlhyper_master_v2/database/repo/agent_token.go
+// AgentTokenRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type agentTokenRepo struct {
+	db      *sql.DB
+	dialect database.AgentTokenDialect
+}
+
+func newAgentTokenRepo(db *sql.DB, dialect database.AgentTokenDialect) *agentTokenRepo {
+	return &agentTokenRepo{db: db, dialect: dialect}
+}
+
+func (r *agentTokenRepo) Upsert(ctx context.Context, token *database.AgentToken) error {
+	query, args := r.dialect.Upsert(token)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *agentTokenRepo) GetByCluster(ctx context.Context, clusterID string) (*database.AgentToken, error) {
+	query, args := r.dialect.SelectByCluster(clusterID)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *agentTokenRepo) List(ctx context.Context) ([]*database.AgentToken, error) {
+	query, args := r.dialect.SelectAll()
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.AgentToken
+	for rows.Next() {
+		t, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, rows.Err()
+}
+
+func (r *agentTokenRepo) Delete(ctx context.Context, clusterID string) error {
+	query, args := r.dialect.Delete(clusterID)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *agentTokenRepo) UpdateLastUsed(ctx context.Context, clusterID string, at time.Time) error {
+	query, args := r.dialect.UpdateLastUsed(clusterID, at)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
@@ -0,0 +1,51 @@
+// atlhyper_master_v2/database/repo/aiops_feedback.go
+// AIOps 误报反馈阈值 Repository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// aiopsFeedbackRepo AIOps 误报反馈阈值 Repository 实现
+type aiopsFeedbackRepo struct {
+	db      *sql.DB
+	dialect database.AIOpsFeedbackDialect
+}
+
+// newAIOpsFeedbackRepo 创建 AIOps 误报反馈阈值 Repository
+func newAIOpsFeedbackRepo(db *sql.DB, dialect database.AIOpsFeedbackDialect) *aiopsFeedbackRepo {
+	return &aiopsFeedbackRepo{db: db, dialect: dialect}
+}
+
+// Upsert 插入或更新反馈阈值
+func (r *aiopsFeedbackRepo) Upsert(ctx context.Context, fb *database.AIOpsThresholdFeedback) error {
+	query, args := r.dialect.Upsert(fb)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// ListAll 查询全部反馈阈值
+func (r *aiopsFeedbackRepo) ListAll(ctx context.Context) ([]*database.AIOpsThresholdFeedback, error) {
+	query, args := r.dialect.SelectAll()
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*database.AIOpsThresholdFeedback
+	for rows.Next() {
+		fb, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, fb)
+	}
+	return result, rows.Err()
+}
+
+// 确保实现了接口
+var _ database.AIOpsFeedbackRepository = (*aiopsFeedbackRepo)(nil)
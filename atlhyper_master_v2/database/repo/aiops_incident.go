@@ -290,7 +290,8 @@ func (r *aiopsIncidentRepo) TopRootCauses(ctx context.Context, clusterID string,
 func (r *aiopsIncidentRepo) ListByEntity(ctx context.Context, entityKey string, since time.Time) ([]*database.AIOpsIncident, error) {
 	sinceStr := since.Format(time.RFC3339)
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT i.id, i.cluster_id, i.state, i.severity, i.root_cause, i.peak_risk, i.started_at, i.resolved_at, i.duration_s, i.recurrence, i.summary, i.created_at
+		`SELECT i.id, i.cluster_id, i.state, i.severity, i.root_cause, i.peak_risk, i.started_at, i.resolved_at, i.duration_s, i.recurrence, i.summary, i.created_at,
+		i.acked_by, i.acked_at, i.assignee, i.false_positive
 		FROM aiops_incidents i
 		INNER JOIN aiops_incident_entities e ON i.id = e.incident_id
 		WHERE e.entity_key = ? AND i.started_at >= ?
@@ -312,6 +313,31 @@ func (r *aiopsIncidentRepo) ListByEntity(ctx context.Context, entityKey string,
 	return result, rows.Err()
 }
 
+// Ack 确认事件，返回是否实际更新（已确认的事件不覆盖原确认人）
+func (r *aiopsIncidentRepo) Ack(ctx context.Context, id, by string, at time.Time) (bool, error) {
+	query, args := r.dialect.Ack(id, by, at)
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Assign 指派事件处理人
+func (r *aiopsIncidentRepo) Assign(ctx context.Context, id, assignee string) error {
+	query, args := r.dialect.Assign(id, assignee)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// MarkFalsePositive 标记事件为误报
+func (r *aiopsIncidentRepo) MarkFalsePositive(ctx context.Context, id string) error {
+	query, args := r.dialect.MarkFalsePositive(id)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
 // buildIncidentListQuery 构建事件列表查询（动态 WHERE 条件）
 func buildIncidentListQuery(opts database.AIOpsIncidentQueryOpts, countOnly bool) (string, []any) {
 	var conditions []string
@@ -347,7 +373,8 @@ func buildIncidentListQuery(opts database.AIOpsIncidentQueryOpts, countOnly bool
 		return "SELECT COUNT(*) FROM aiops_incidents" + where, args
 	}
 
-	query := `SELECT id, cluster_id, state, severity, root_cause, peak_risk, started_at, resolved_at, duration_s, recurrence, summary, created_at
+	query := `SELECT id, cluster_id, state, severity, root_cause, peak_risk, started_at, resolved_at, duration_s, recurrence, summary, created_at,
+		acked_by, acked_at, assignee, false_positive
 		FROM aiops_incidents` + where + ` ORDER BY started_at DESC`
 
 	if opts.Limit > 0 {
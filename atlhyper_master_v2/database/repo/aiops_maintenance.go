@@ -0,0 +1,79 @@
+// atlhyper_master_v2/database/repo/aiops_maintenance.go
+// AIOps 维护窗口 Repository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// aiopsMaintenanceRepo AIOps 维护窗口 Repository 实现
+type aiopsMaintenanceRepo struct {
+	db      *sql.DB
+	dialect database.AIOpsMaintenanceDialect
+}
+
+// newAIOpsMaintenanceRepo 创建 AIOps 维护窗口 Repository
+func newAIOpsMaintenanceRepo(db *sql.DB, dialect database.AIOpsMaintenanceDialect) *aiopsMaintenanceRepo {
+	return &aiopsMaintenanceRepo{db: db, dialect: dialect}
+}
+
+func (r *aiopsMaintenanceRepo) Create(ctx context.Context, w *database.AIOpsMaintenanceWindow) error {
+	query, args := r.dialect.Insert(w)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	w.ID = id
+	return nil
+}
+
+func (r *aiopsMaintenanceRepo) Update(ctx context.Context, w *database.AIOpsMaintenanceWindow) error {
+	query, args := r.dialect.Update(w)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *aiopsMaintenanceRepo) GetByID(ctx context.Context, id int64) (*database.AIOpsMaintenanceWindow, error) {
+	query, args := r.dialect.SelectByID(id)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *aiopsMaintenanceRepo) List(ctx context.Context) ([]*database.AIOpsMaintenanceWindow, error) {
+	query, args := r.dialect.SelectAll()
+	return r.query(ctx, query, args)
+}
+
+func (r *aiopsMaintenanceRepo) ListOpen(ctx context.Context, now time.Time) ([]*database.AIOpsMaintenanceWindow, error) {
+	query, args := r.dialect.SelectOpen(now)
+	return r.query(ctx, query, args)
+}
+
+func (r *aiopsMaintenanceRepo) query(ctx context.Context, query string, args []any) ([]*database.AIOpsMaintenanceWindow, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.AIOpsMaintenanceWindow
+	for rows.Next() {
+		item, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,72 @@
+// atlhyper_master_v2/database/repo/aiops_policy.go
+// AIOps 策略版本 Repository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// aiopsPolicyRepo AIOps 策略版本 Repository 实现
+type aiopsPolicyRepo struct {
+	db      *sql.DB
+	dialect database.AIOpsPolicyDialect
+}
+
+// newAIOpsPolicyRepo 创建 AIOps 策略版本 Repository
+func newAIOpsPolicyRepo(db *sql.DB, dialect database.AIOpsPolicyDialect) *aiopsPolicyRepo {
+	return &aiopsPolicyRepo{db: db, dialect: dialect}
+}
+
+func (r *aiopsPolicyRepo) Create(ctx context.Context, p *database.AIOpsPolicy) error {
+	query, args := r.dialect.Insert(p)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	p.ID = id
+	return nil
+}
+
+func (r *aiopsPolicyRepo) ListCurrent(ctx context.Context) ([]*database.AIOpsPolicy, error) {
+	query, args := r.dialect.SelectCurrent()
+	return r.query(ctx, query, args)
+}
+
+func (r *aiopsPolicyRepo) ListVersions(ctx context.Context, name string) ([]*database.AIOpsPolicy, error) {
+	query, args := r.dialect.SelectVersions(name)
+	return r.query(ctx, query, args)
+}
+
+func (r *aiopsPolicyRepo) GetVersion(ctx context.Context, name string, version int) (*database.AIOpsPolicy, error) {
+	query, args := r.dialect.SelectVersion(name, version)
+	policies, err := r.query(ctx, query, args)
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+	return policies[0], nil
+}
+
+func (r *aiopsPolicyRepo) query(ctx context.Context, query string, args []any) ([]*database.AIOpsPolicy, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*database.AIOpsPolicy
+	for rows.Next() {
+		p, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+// 确保实现了接口
+var _ database.AIOpsPolicyRepository = (*aiopsPolicyRepo)(nil)
@@ -0,0 +1,58 @@
+// atlhyper_master_v2/database/repo/aiops_postmortem.go
+// AIOps 事件复盘文档 Repository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// aiopsPostmortemRepo AIOps 事件复盘文档 Repository 实现
+type aiopsPostmortemRepo struct {
+	db      *sql.DB
+	dialect database.AIOpsPostmortemDialect
+}
+
+// newAIOpsPostmortemRepo 创建 AIOps 事件复盘文档 Repository
+func newAIOpsPostmortemRepo(db *sql.DB, dialect database.AIOpsPostmortemDialect) *aiopsPostmortemRepo {
+	return &aiopsPostmortemRepo{db: db, dialect: dialect}
+}
+
+// Save 保存生成结果
+func (r *aiopsPostmortemRepo) Save(ctx context.Context, pm *database.AIOpsPostmortem) error {
+	query, args := r.dialect.Upsert(pm)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// UpdateContent 保存人工编辑
+func (r *aiopsPostmortemRepo) UpdateContent(ctx context.Context, incidentID, content, by string, at time.Time) (bool, error) {
+	query, args := r.dialect.UpdateContent(incidentID, content, by, at)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// Get 查询文档
+func (r *aiopsPostmortemRepo) Get(ctx context.Context, incidentID string) (*database.AIOpsPostmortem, error) {
+	query, args := r.dialect.SelectByIncident(incidentID)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+// 确保实现了接口
+var _ database.AIOpsPostmortemRepository = (*aiopsPostmortemRepo)(nil)
@@ -0,0 +1,90 @@
+// atlhyper_master_v2/database/repo/aiops_runbook_run.go
+// AIOps Runbook 执行记录 Repository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// aiopsRunbookRunRepo Runbook 执行记录 Repository 实现
+type aiopsRunbookRunRepo struct {
+	db      *sql.DB
+	dialect database.AIOpsRunbookRunDialect
+}
+
+// newAIOpsRunbookRunRepo 创建 Runbook 执行记录 Repository
+func newAIOpsRunbookRunRepo(db *sql.DB, dialect database.AIOpsRunbookRunDialect) *aiopsRunbookRunRepo {
+	return &aiopsRunbookRunRepo{db: db, dialect: dialect}
+}
+
+func (r *aiopsRunbookRunRepo) Create(ctx context.Context, run *database.AIOpsRunbookRun) error {
+	query, args := r.dialect.Insert(run)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	run.ID = id
+	return nil
+}
+
+func (r *aiopsRunbookRunRepo) Update(ctx context.Context, run *database.AIOpsRunbookRun) error {
+	query, args := r.dialect.Update(run)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *aiopsRunbookRunRepo) TransitionStatus(ctx context.Context, id int64, from, to, by string) (bool, error) {
+	query, args := r.dialect.TransitionStatus(id, from, to, by)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *aiopsRunbookRunRepo) GetByID(ctx context.Context, id int64) (*database.AIOpsRunbookRun, error) {
+	query, args := r.dialect.SelectByID(id)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *aiopsRunbookRunRepo) List(ctx context.Context, opts database.RunbookRunQueryOpts) ([]*database.AIOpsRunbookRun, error) {
+	query, args := r.dialect.List(opts)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*database.AIOpsRunbookRun
+	for rows.Next() {
+		run, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *aiopsRunbookRunRepo) Count(ctx context.Context, opts database.RunbookRunQueryOpts) (int64, error) {
+	query, args := r.dialect.Count(opts)
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+// 确保实现了接口
+var _ database.AIOpsRunbookRunRepository = (*aiopsRunbookRunRepo)(nil)
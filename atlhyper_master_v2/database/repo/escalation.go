@@ -0,0 +1,138 @@
+// atlhyper_master_v2/database/repo/escalation.go
+// EscalationRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type escalationRepo struct {
+	db      *sql.DB
+	dialect database.EscalationDialect
+}
+
+func newEscalationRepo(db *sql.DB, dialect database.EscalationDialect) *escalationRepo {
+	return &escalationRepo{db: db, dialect: dialect}
+}
+
+func (r *escalationRepo) Create(ctx context.Context, e *database.Escalation) error {
+	query, args := r.dialect.Insert(e)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	e.ID = id
+	return nil
+}
+
+func (r *escalationRepo) UpdateProgress(ctx context.Context, e *database.Escalation) error {
+	query, args := r.dialect.UpdateProgress(e)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *escalationRepo) GetByID(ctx context.Context, id int64) (*database.Escalation, error) {
+	query, args := r.dialect.SelectByID(id)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *escalationRepo) GetByToken(ctx context.Context, token string) (*database.Escalation, error) {
+	query, args := r.dialect.SelectByToken(token)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *escalationRepo) GetOpen(ctx context.Context, policyID int64, alertKey string) (*database.Escalation, error) {
+	query, args := r.dialect.SelectOpen(policyID, alertKey)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *escalationRepo) List(ctx context.Context, opts database.EscalationQueryOpts) ([]*database.Escalation, error) {
+	query, args := r.dialect.List(opts)
+	return r.query(ctx, query, args)
+}
+
+func (r *escalationRepo) Count(ctx context.Context, opts database.EscalationQueryOpts) (int64, error) {
+	query, args := r.dialect.Count(opts)
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+func (r *escalationRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]*database.Escalation, error) {
+	query, args := r.dialect.SelectDue(now, limit)
+	return r.query(ctx, query, args)
+}
+
+func (r *escalationRepo) Ack(ctx context.Context, id int64, by string, at time.Time) (bool, error) {
+	query, args := r.dialect.Ack(id, by, at)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *escalationRepo) ResolveAlert(ctx context.Context, alertKey string, at time.Time) (int64, error) {
+	query, args := r.dialect.ResolveAlert(alertKey, at)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *escalationRepo) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query, args := r.dialect.DeleteFinishedBefore(before)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *escalationRepo) query(ctx context.Context, query string, args []any) ([]*database.Escalation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.Escalation
+	for rows.Next() {
+		item, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
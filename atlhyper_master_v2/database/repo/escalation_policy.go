@@ -0,0 +1,77 @@
+// atlhyper_master_v2/database/repo/escalation_policy.go
+// EscalationPolicyRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type escalationPolicyRepo struct {
+	db      *sql.DB
+	dialect database.EscalationPolicyDialect
+}
+
+func newEscalationPolicyRepo(db *sql.DB, dialect database.EscalationPolicyDialect) *escalationPolicyRepo {
+	return &escalationPolicyRepo{db: db, dialect: dialect}
+}
+
+func (r *escalationPolicyRepo) Create(ctx context.Context, p *database.EscalationPolicy) error {
+	query, args := r.dialect.Insert(p)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	p.ID = id
+	return nil
+}
+
+func (r *escalationPolicyRepo) Update(ctx context.Context, p *database.EscalationPolicy) error {
+	query, args := r.dialect.Update(p)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *escalationPolicyRepo) Delete(ctx context.Context, id int64) error {
+	query, args := r.dialect.Delete(id)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *escalationPolicyRepo) GetByID(ctx context.Context, id int64) (*database.EscalationPolicy, error) {
+	query, args := r.dialect.SelectByID(id)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *escalationPolicyRepo) List(ctx context.Context) ([]*database.EscalationPolicy, error) {
+	query, args := r.dialect.SelectAll()
+	return r.query(ctx, query, args)
+}
+
+func (r *escalationPolicyRepo) query(ctx context.Context, query string, args []any) ([]*database.EscalationPolicy, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.EscalationPolicy
+	for rows.Next() {
+		item, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,61 @@
+// atlhyper_master_v2/database/repo/exec_session.go
+// ExecSessionRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type execSessionRepo struct {
+	db      *sql.DB
+	dialect database.ExecSessionDialect
+}
+
+func newExecSessionRepo(db *sql.DB, dialect database.ExecSessionDialect) *execSessionRepo {
+	return &execSessionRepo{db: db, dialect: dialect}
+}
+
+func (r *execSessionRepo) Create(ctx context.Context, session *database.ExecSession) error {
+	query, args := r.dialect.Insert(session)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	session.ID = id
+	return nil
+}
+
+func (r *execSessionRepo) GetBySessionID(ctx context.Context, sessionID string) (*database.ExecSession, error) {
+	query, args := r.dialect.SelectBySessionID(sessionID)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *execSessionRepo) List(ctx context.Context, opts database.ExecSessionQueryOpts) ([]*database.ExecSession, error) {
+	query, args := r.dialect.List(opts)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.ExecSession
+	for rows.Next() {
+		s, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
@@ -28,8 +28,21 @@ func Init(db *database.DB, dialect database.Dialect) {
 	db.User = newUserRepo(db.Conn, dialect.User())
 	db.Event = newEventRepo(db.Conn, dialect.Event())
 	db.Notify = newNotifyRepo(db.Conn, dialect.Notify())
+	db.NotifyRoute = newNotifyRouteRepo(db.Conn, dialect.NotifyRoute())
+	db.NotifySilence = newNotifySilenceRepo(db.Conn, dialect.NotifySilence())
+	db.NotifyInhibit = newNotifyInhibitRepo(db.Conn, dialect.NotifyInhibit())
+	db.NotifyDelivery = newNotifyDeliveryRepo(db.Conn, dialect.NotifyDelivery())
+	db.NotifyTemplate = newNotifyTemplateRepo(db.Conn, dialect.NotifyTemplate())
+	db.Oncall = newOncallScheduleRepo(db.Conn, dialect.Oncall())
+	db.OncallOverride = newOncallOverrideRepo(db.Conn, dialect.OncallOverride())
+	db.Escalation = newEscalationPolicyRepo(db.Conn, dialect.Escalation())
+	db.EscalationLog = newEscalationRepo(db.Conn, dialect.EscalationLog())
 	db.Cluster = newClusterRepo(db.Conn, dialect.Cluster())
+	db.AgentToken = newAgentTokenRepo(db.Conn, dialect.AgentToken())
 	db.Command = newCommandRepo(db.Conn, dialect.Command())
+	db.ExecSession = newExecSessionRepo(db.Conn, dialect.ExecSession())
+	db.RoleBinding = newRoleBindingRepo(db.Conn, dialect.RoleBinding())
+	db.UserIdentity = newUserIdentityRepo(db.Conn, dialect.UserIdentity())
 	db.Settings = newSettingsRepo(db.Conn, dialect.Settings())
 	db.AIConversation = newAIConversationRepo(db.Conn, dialect.AIConversation())
 	db.AIMessage = newAIMessageRepo(db.Conn, dialect.AIMessage())
@@ -44,6 +57,11 @@ func Init(db *database.DB, dialect database.Dialect) {
 	db.AIOpsBaseline = newAIOpsBaselineRepo(db.Conn, dialect.AIOpsBaseline())
 	db.AIOpsGraph = newAIOpsGraphRepo(db.Conn, dialect.AIOpsGraph())
 	db.AIOpsIncident = newAIOpsIncidentRepo(db.Conn, dialect.AIOpsIncident())
+	db.AIOpsFeedback = newAIOpsFeedbackRepo(db.Conn, dialect.AIOpsFeedback())
+	db.AIOpsPostmortem = newAIOpsPostmortemRepo(db.Conn, dialect.AIOpsPostmortem())
+	db.AIOpsRunbookRun = newAIOpsRunbookRunRepo(db.Conn, dialect.AIOpsRunbookRun())
+	db.AIOpsPolicy = newAIOpsPolicyRepo(db.Conn, dialect.AIOpsPolicy())
+	db.AIOpsMaintenance = newAIOpsMaintenanceRepo(db.Conn, dialect.AIOpsMaintenance())
 
 	db.GitHubInstall = newGitHubInstallRepo(db.Conn, dialect.GitHubInstall())
 	db.RepoConfig = newRepoConfigRepo(db.Conn, dialect.RepoConfig())
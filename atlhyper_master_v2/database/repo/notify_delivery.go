@@ -0,0 +1,99 @@
+// atlhyper_master_v2/database/repo/notify_delivery.go
+// NotifyDeliveryRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type notifyDeliveryRepo struct {
+	db      *sql.DB
+	dialect database.NotifyDeliveryDialect
+}
+
+func newNotifyDeliveryRepo(db *sql.DB, dialect database.NotifyDeliveryDialect) *notifyDeliveryRepo {
+	return &notifyDeliveryRepo{db: db, dialect: dialect}
+}
+
+func (r *notifyDeliveryRepo) Create(ctx context.Context, d *database.NotifyDelivery) error {
+	query, args := r.dialect.Insert(d)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	d.ID = id
+	return nil
+}
+
+func (r *notifyDeliveryRepo) UpdateAttempt(ctx context.Context, d *database.NotifyDelivery) error {
+	query, args := r.dialect.UpdateAttempt(d)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *notifyDeliveryRepo) GetByID(ctx context.Context, id int64) (*database.NotifyDelivery, error) {
+	query, args := r.dialect.SelectByID(id)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *notifyDeliveryRepo) List(ctx context.Context, opts database.NotifyDeliveryQueryOpts) ([]*database.NotifyDelivery, error) {
+	query, args := r.dialect.List(opts)
+	return r.query(ctx, query, args)
+}
+
+func (r *notifyDeliveryRepo) Count(ctx context.Context, opts database.NotifyDeliveryQueryOpts) (int64, error) {
+	query, args := r.dialect.Count(opts)
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+func (r *notifyDeliveryRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]*database.NotifyDelivery, error) {
+	query, args := r.dialect.SelectDue(now, limit)
+	return r.query(ctx, query, args)
+}
+
+func (r *notifyDeliveryRepo) Requeue(ctx context.Context, id int64, now time.Time) error {
+	query, args := r.dialect.Requeue(id, now)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *notifyDeliveryRepo) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query, args := r.dialect.DeleteFinishedBefore(before)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *notifyDeliveryRepo) query(ctx context.Context, query string, args []any) ([]*database.NotifyDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.NotifyDelivery
+	for rows.Next() {
+		item, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,77 @@
+// atlhyper_master_v2/database/repo/notify_inhibit.go
+// NotifyInhibitRuleRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type notifyInhibitRepo struct {
+	db      *sql.DB
+	dialect database.NotifyInhibitRuleDialect
+}
+
+func newNotifyInhibitRepo(db *sql.DB, dialect database.NotifyInhibitRuleDialect) *notifyInhibitRepo {
+	return &notifyInhibitRepo{db: db, dialect: dialect}
+}
+
+func (r *notifyInhibitRepo) Create(ctx context.Context, rule *database.NotifyInhibitRule) error {
+	query, args := r.dialect.Insert(rule)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	rule.ID = id
+	return nil
+}
+
+func (r *notifyInhibitRepo) Update(ctx context.Context, rule *database.NotifyInhibitRule) error {
+	query, args := r.dialect.Update(rule)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *notifyInhibitRepo) Delete(ctx context.Context, id int64) error {
+	query, args := r.dialect.Delete(id)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *notifyInhibitRepo) GetByID(ctx context.Context, id int64) (*database.NotifyInhibitRule, error) {
+	query, args := r.dialect.SelectByID(id)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *notifyInhibitRepo) List(ctx context.Context) ([]*database.NotifyInhibitRule, error) {
+	query, args := r.dialect.SelectAll()
+	return r.query(ctx, query, args)
+}
+
+func (r *notifyInhibitRepo) query(ctx context.Context, query string, args []any) ([]*database.NotifyInhibitRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.NotifyInhibitRule
+	for rows.Next() {
+		item, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,77 @@
+// atlhyper_master_v2/database/repo/notify_route.go
+// NotifyRouteRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type notifyRouteRepo struct {
+	db      *sql.DB
+	dialect database.NotifyRouteDialect
+}
+
+func newNotifyRouteRepo(db *sql.DB, dialect database.NotifyRouteDialect) *notifyRouteRepo {
+	return &notifyRouteRepo{db: db, dialect: dialect}
+}
+
+func (r *notifyRouteRepo) Create(ctx context.Context, route *database.NotifyRoute) error {
+	query, args := r.dialect.Insert(route)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	route.ID = id
+	return nil
+}
+
+func (r *notifyRouteRepo) Update(ctx context.Context, route *database.NotifyRoute) error {
+	query, args := r.dialect.Update(route)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *notifyRouteRepo) Delete(ctx context.Context, id int64) error {
+	query, args := r.dialect.Delete(id)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *notifyRouteRepo) GetByID(ctx context.Context, id int64) (*database.NotifyRoute, error) {
+	query, args := r.dialect.SelectByID(id)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *notifyRouteRepo) List(ctx context.Context) ([]*database.NotifyRoute, error) {
+	query, args := r.dialect.SelectAll()
+	return r.query(ctx, query, args)
+}
+
+func (r *notifyRouteRepo) query(ctx context.Context, query string, args []any) ([]*database.NotifyRoute, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.NotifyRoute
+	for rows.Next() {
+		item, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,86 @@
+// atlhyper_master_v2/database/repo/notify_silence.go
+// NotifySilenceRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type notifySilenceRepo struct {
+	db      *sql.DB
+	dialect database.NotifySilenceDialect
+}
+
+func newNotifySilenceRepo(db *sql.DB, dialect database.NotifySilenceDialect) *notifySilenceRepo {
+	return &notifySilenceRepo{db: db, dialect: dialect}
+}
+
+func (r *notifySilenceRepo) Create(ctx context.Context, silence *database.NotifySilence) error {
+	query, args := r.dialect.Insert(silence)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	silence.ID = id
+	return nil
+}
+
+func (r *notifySilenceRepo) Expire(ctx context.Context, id int64, at time.Time) error {
+	query, args := r.dialect.Expire(id, at)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *notifySilenceRepo) GetByID(ctx context.Context, id int64) (*database.NotifySilence, error) {
+	query, args := r.dialect.SelectByID(id)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *notifySilenceRepo) List(ctx context.Context) ([]*database.NotifySilence, error) {
+	query, args := r.dialect.SelectAll()
+	return r.query(ctx, query, args)
+}
+
+func (r *notifySilenceRepo) ListActive(ctx context.Context, now time.Time) ([]*database.NotifySilence, error) {
+	query, args := r.dialect.SelectActive(now)
+	return r.query(ctx, query, args)
+}
+
+func (r *notifySilenceRepo) DeleteExpiredBefore(ctx context.Context, before time.Time) (int64, error) {
+	query, args := r.dialect.DeleteExpiredBefore(before)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *notifySilenceRepo) query(ctx context.Context, query string, args []any) ([]*database.NotifySilence, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.NotifySilence
+	for rows.Next() {
+		item, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,62 @@
+// atlhyper_master_v2/database/repo/notify_template.go
+// NotifyTemplateRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type notifyTemplateRepo struct {
+	db      *sql.DB
+	dialect database.NotifyTemplateDialect
+}
+
+func newNotifyTemplateRepo(db *sql.DB, dialect database.NotifyTemplateDialect) *notifyTemplateRepo {
+	return &notifyTemplateRepo{db: db, dialect: dialect}
+}
+
+func (r *notifyTemplateRepo) Upsert(ctx context.Context, t *database.NotifyTemplate) error {
+	query, args := r.dialect.Upsert(t)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *notifyTemplateRepo) Delete(ctx context.Context, name, variant string) error {
+	query, args := r.dialect.Delete(name, variant)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *notifyTemplateRepo) Get(ctx context.Context, name, variant string) (*database.NotifyTemplate, error) {
+	query, args := r.dialect.SelectOne(name, variant)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *notifyTemplateRepo) List(ctx context.Context) ([]*database.NotifyTemplate, error) {
+	query, args := r.dialect.SelectAll()
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.NotifyTemplate
+	for rows.Next() {
+		item, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,86 @@
+// atlhyper_master_v2/database/repo/oncall_override.go
+// OncallOverrideRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type oncallOverrideRepo struct {
+	db      *sql.DB
+	dialect database.OncallOverrideDialect
+}
+
+func newOncallOverrideRepo(db *sql.DB, dialect database.OncallOverrideDialect) *oncallOverrideRepo {
+	return &oncallOverrideRepo{db: db, dialect: dialect}
+}
+
+func (r *oncallOverrideRepo) Create(ctx context.Context, o *database.OncallOverride) error {
+	query, args := r.dialect.Insert(o)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	o.ID = id
+	return nil
+}
+
+func (r *oncallOverrideRepo) Delete(ctx context.Context, id int64) error {
+	query, args := r.dialect.Delete(id)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *oncallOverrideRepo) GetByID(ctx context.Context, id int64) (*database.OncallOverride, error) {
+	query, args := r.dialect.SelectByID(id)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *oncallOverrideRepo) ListBySchedule(ctx context.Context, scheduleID int64) ([]*database.OncallOverride, error) {
+	query, args := r.dialect.SelectBySchedule(scheduleID)
+	return r.query(ctx, query, args)
+}
+
+func (r *oncallOverrideRepo) ListActive(ctx context.Context, scheduleID int64, at time.Time) ([]*database.OncallOverride, error) {
+	query, args := r.dialect.SelectActive(scheduleID, at)
+	return r.query(ctx, query, args)
+}
+
+func (r *oncallOverrideRepo) DeleteEndedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query, args := r.dialect.DeleteEndedBefore(before)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *oncallOverrideRepo) query(ctx context.Context, query string, args []any) ([]*database.OncallOverride, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.OncallOverride
+	for rows.Next() {
+		item, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,77 @@
+// atlhyper_master_v2/database/repo/oncall_schedule.go
+// OncallScheduleRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type oncallScheduleRepo struct {
+	db      *sql.DB
+	dialect database.OncallScheduleDialect
+}
+
+func newOncallScheduleRepo(db *sql.DB, dialect database.OncallScheduleDialect) *oncallScheduleRepo {
+	return &oncallScheduleRepo{db: db, dialect: dialect}
+}
+
+func (r *oncallScheduleRepo) Create(ctx context.Context, s *database.OncallSchedule) error {
+	query, args := r.dialect.Insert(s)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	s.ID = id
+	return nil
+}
+
+func (r *oncallScheduleRepo) Update(ctx context.Context, s *database.OncallSchedule) error {
+	query, args := r.dialect.Update(s)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *oncallScheduleRepo) Delete(ctx context.Context, id int64) error {
+	query, args := r.dialect.Delete(id)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *oncallScheduleRepo) GetByID(ctx context.Context, id int64) (*database.OncallSchedule, error) {
+	query, args := r.dialect.SelectByID(id)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *oncallScheduleRepo) List(ctx context.Context) ([]*database.OncallSchedule, error) {
+	query, args := r.dialect.SelectAll()
+	return r.query(ctx, query, args)
+}
+
+func (r *oncallScheduleRepo) query(ctx context.Context, query string, args []any) ([]*database.OncallSchedule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.OncallSchedule
+	for rows.Next() {
+		item, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
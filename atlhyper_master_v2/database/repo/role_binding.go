@@ -0,0 +1,82 @@
+// atlhyper_master_v2/database/repo/role_binding.go
+// RoleBindingRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type roleBindingRepo struct {
+	db      *sql.DB
+	dialect database.RoleBindingDialect
+}
+
+func newRoleBindingRepo(db *sql.DB, dialect database.RoleBindingDialect) *roleBindingRepo {
+	return &roleBindingRepo{db: db, dialect: dialect}
+}
+
+func (r *roleBindingRepo) Create(ctx context.Context, binding *database.RoleBinding) error {
+	query, args := r.dialect.Insert(binding)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	binding.ID = id
+	return nil
+}
+
+func (r *roleBindingRepo) Delete(ctx context.Context, id int64) error {
+	query, args := r.dialect.Delete(id)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *roleBindingRepo) DeleteByUser(ctx context.Context, userID int64) error {
+	query, args := r.dialect.DeleteByUser(userID)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *roleBindingRepo) GetByID(ctx context.Context, id int64) (*database.RoleBinding, error) {
+	query, args := r.dialect.SelectByID(id)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
+
+func (r *roleBindingRepo) ListByUser(ctx context.Context, userID int64) ([]*database.RoleBinding, error) {
+	query, args := r.dialect.SelectByUser(userID)
+	return r.query(ctx, query, args)
+}
+
+func (r *roleBindingRepo) List(ctx context.Context) ([]*database.RoleBinding, error) {
+	query, args := r.dialect.SelectAll()
+	return r.query(ctx, query, args)
+}
+
+func (r *roleBindingRepo) query(ctx context.Context, query string, args []any) ([]*database.RoleBinding, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*database.RoleBinding
+	for rows.Next() {
+		b, err := r.dialect.ScanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, b)
+	}
+	return result, rows.Err()
+}
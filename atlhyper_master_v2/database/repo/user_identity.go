@@ -0,0 +1,55 @@
+// atlhyper_master_v2/database/repo/user_identity.go
+// UserIdentityRepository 实现
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type userIdentityRepo struct {
+	db      *sql.DB
+	dialect database.UserIdentityDialect
+}
+
+func newUserIdentityRepo(db *sql.DB, dialect database.UserIdentityDialect) *userIdentityRepo {
+	return &userIdentityRepo{db: db, dialect: dialect}
+}
+
+func (r *userIdentityRepo) Create(ctx context.Context, identity *database.UserIdentity) error {
+	query, args := r.dialect.Insert(identity)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	identity.ID = id
+	return nil
+}
+
+func (r *userIdentityRepo) Touch(ctx context.Context, id int64, email string) error {
+	query, args := r.dialect.Touch(id, email)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *userIdentityRepo) DeleteByUser(ctx context.Context, userID int64) error {
+	query, args := r.dialect.DeleteByUser(userID)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *userIdentityRepo) GetBySubject(ctx context.Context, issuer, subject string) (*database.UserIdentity, error) {
+	query, args := r.dialect.SelectBySubject(issuer, subject)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.dialect.ScanRow(rows)
+}
@@ -0,0 +1,80 @@
+// atlhyper_master_v2/database/sqlite/agent_token.go
+// SQLite AgentTokenDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type agentTokenDialect struct{}
+
+const agentTokenColumns = `cluster_id, token_hash, token_prefix, previous_hash, previous_expires_at, created_by, created_at, rotated_at, last_used_at`
+
+func (d *agentTokenDialect) Upsert(t *database.AgentToken) (string, []any) {
+	var prevExpires any
+	if t.PreviousExpiresAt != nil {
+		prevExpires = t.PreviousExpiresAt.Format(time.RFC3339)
+	}
+	createdAt := t.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	rotatedAt := t.RotatedAt
+	if rotatedAt.IsZero() {
+		rotatedAt = createdAt
+	}
+	return `INSERT INTO cluster_agent_tokens (cluster_id, token_hash, token_prefix, previous_hash, previous_expires_at, created_by, created_at, rotated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(cluster_id) DO UPDATE SET token_hash = excluded.token_hash, token_prefix = excluded.token_prefix,
+		previous_hash = excluded.previous_hash, previous_expires_at = excluded.previous_expires_at,
+		created_by = excluded.created_by, rotated_at = excluded.rotated_at`,
+		[]any{t.ClusterID, t.TokenHash, t.TokenPrefix, t.PreviousHash, prevExpires, t.CreatedBy,
+			createdAt.Format(time.RFC3339), rotatedAt.Format(time.RFC3339)}
+}
+
+func (d *agentTokenDialect) SelectByCluster(clusterID string) (string, []any) {
+	return "SELECT " + agentTokenColumns + " FROM cluster_agent_tokens WHERE cluster_id = ?", []any{clusterID}
+}
+
+func (d *agentTokenDialect) SelectAll() (string, []any) {
+	return "SELECT " + agentTokenColumns + " FROM cluster_agent_tokens ORDER BY cluster_id", nil
+}
+
+func (d *agentTokenDialect) Delete(clusterID string) (string, []any) {
+	return "DELETE FROM cluster_agent_tokens WHERE cluster_id = ?", []any{clusterID}
+}
+
+func (d *agentTokenDialect) UpdateLastUsed(clusterID string, at time.Time) (string, []any) {
+	return "UPDATE cluster_agent_tokens SET last_used_at = ? WHERE cluster_id = ?",
+		[]any{at.Format(time.RFC3339), clusterID}
+}
+
+func (d *agentTokenDialect) ScanRow(rows *sql.Rows) (*database.AgentToken, error) {
+	t := &database.AgentToken{}
+	var prevHash, prevExpires, lastUsed sql.NullString
+	var createdBy sql.NullInt64
+	var createdAt, rotatedAt string
+	err := rows.Scan(&t.ClusterID, &t.TokenHash, &t.TokenPrefix, &prevHash, &prevExpires,
+		&createdBy, &createdAt, &rotatedAt, &lastUsed)
+	if err != nil {
+		return nil, err
+	}
+	t.PreviousHash = prevHash.String
+	t.CreatedBy = createdBy.Int64
+	t.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	t.RotatedAt, _ = time.Parse(time.RFC3339, rotatedAt)
+	if prevExpires.Valid && prevExpires.String != "" {
+		ts, _ := time.Parse(time.RFC3339, prevExpires.String)
+		t.PreviousExpiresAt = &ts
+	}
+	if lastUsed.Valid && lastUsed.String != "" {
+		ts, _ := time.Parse(time.RFC3339, lastUsed.String)
+		t.LastUsedAt = &ts
+	}
+	return t, nil
+}
+
+var _ database.AgentTokenDialect = (*agentTokenDialect)(nil)
@@ -13,24 +13,28 @@ type aIOpsBaselineDialect struct{}
 
 // Upsert 插入或更新基线状态
 func (d *aIOpsBaselineDialect) Upsert(state *database.AIOpsBaselineState) (string, []any) {
-	return `INSERT INTO aiops_baseline_states (entity_key, metric_name, ema, variance, count, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+	return `INSERT INTO aiops_baseline_states (entity_key, metric_name, ema, variance, count, updated_at, mode, seasonal, detector, detector_state)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(entity_key, metric_name) DO UPDATE SET
 			ema = excluded.ema,
 			variance = excluded.variance,
 			count = excluded.count,
-			updated_at = excluded.updated_at`,
-		[]any{state.EntityKey, state.MetricName, state.EMA, state.Variance, state.Count, state.UpdatedAt}
+			updated_at = excluded.updated_at,
+			mode = excluded.mode,
+			seasonal = excluded.seasonal,
+			detector = excluded.detector,
+			detector_state = excluded.detector_state`,
+		[]any{state.EntityKey, state.MetricName, state.EMA, state.Variance, state.Count, state.UpdatedAt, state.Mode, state.Seasonal, state.Detector, state.DetectorState}
 }
 
 // SelectAll 查询所有基线状态
 func (d *aIOpsBaselineDialect) SelectAll() (string, []any) {
-	return `SELECT entity_key, metric_name, ema, variance, count, updated_at FROM aiops_baseline_states`, nil
+	return `SELECT entity_key, metric_name, ema, variance, count, updated_at, mode, seasonal, detector, detector_state FROM aiops_baseline_states`, nil
 }
 
 // SelectByEntity 按实体查询基线状态
 func (d *aIOpsBaselineDialect) SelectByEntity(entityKey string) (string, []any) {
-	return `SELECT entity_key, metric_name, ema, variance, count, updated_at
+	return `SELECT entity_key, metric_name, ema, variance, count, updated_at, mode, seasonal, detector, detector_state
 		FROM aiops_baseline_states WHERE entity_key = ?`, []any{entityKey}
 }
 
@@ -42,7 +46,7 @@ func (d *aIOpsBaselineDialect) DeleteByEntity(entityKey string) (string, []any)
 // ScanRow 扫描基线状态行
 func (d *aIOpsBaselineDialect) ScanRow(rows *sql.Rows) (*database.AIOpsBaselineState, error) {
 	s := &database.AIOpsBaselineState{}
-	err := rows.Scan(&s.EntityKey, &s.MetricName, &s.EMA, &s.Variance, &s.Count, &s.UpdatedAt)
+	err := rows.Scan(&s.EntityKey, &s.MetricName, &s.EMA, &s.Variance, &s.Count, &s.UpdatedAt, &s.Mode, &s.Seasonal, &s.Detector, &s.DetectorState)
 	if err != nil {
 		return nil, err
 	}
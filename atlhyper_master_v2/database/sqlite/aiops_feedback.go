@@ -0,0 +1,38 @@
+// atlhyper_master_v2/database/sqlite/aiops_feedback.go
+// AIOps 误报反馈阈值 SQLite 方言实现
+package sqlite
+
+import (
+	"database/sql"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// aIOpsFeedbackDialect AIOps 误报反馈阈值 SQLite 方言
+type aIOpsFeedbackDialect struct{}
+
+// Upsert 插入或更新反馈阈值
+func (d *aIOpsFeedbackDialect) Upsert(fb *database.AIOpsThresholdFeedback) (string, []any) {
+	return `INSERT INTO aiops_threshold_feedback (entity_pattern, metric_name, threshold, false_positives, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(entity_pattern, metric_name) DO UPDATE SET
+			threshold = excluded.threshold,
+			false_positives = excluded.false_positives,
+			updated_at = excluded.updated_at`,
+		[]any{fb.EntityPattern, fb.MetricName, fb.Threshold, fb.FalsePositives, fb.UpdatedAt}
+}
+
+// SelectAll 查询全部反馈阈值
+func (d *aIOpsFeedbackDialect) SelectAll() (string, []any) {
+	return `SELECT entity_pattern, metric_name, threshold, false_positives, updated_at FROM aiops_threshold_feedback`, nil
+}
+
+// ScanRow 扫描反馈阈值行
+func (d *aIOpsFeedbackDialect) ScanRow(rows *sql.Rows) (*database.AIOpsThresholdFeedback, error) {
+	fb := &database.AIOpsThresholdFeedback{}
+	err := rows.Scan(&fb.EntityPattern, &fb.MetricName, &fb.Threshold, &fb.FalsePositives, &fb.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return fb, nil
+}
@@ -20,7 +20,8 @@ func (d *aIOpsIncidentDialect) InsertIncident(inc *database.AIOpsIncident) (stri
 }
 
 func (d *aIOpsIncidentDialect) SelectByID(id string) (string, []any) {
-	return `SELECT id, cluster_id, state, severity, root_cause, peak_risk, started_at, resolved_at, duration_s, recurrence, summary, created_at
+	return `SELECT id, cluster_id, state, severity, root_cause, peak_risk, started_at, resolved_at, duration_s, recurrence, summary, created_at,
+		acked_by, acked_at, assignee, false_positive
 		FROM aiops_incidents WHERE id = ?`, []any{id}
 }
 
@@ -45,6 +46,19 @@ func (d *aIOpsIncidentDialect) IncrementRecurrence(id string) (string, []any) {
 	return `UPDATE aiops_incidents SET recurrence = recurrence + 1 WHERE id = ?`, []any{id}
 }
 
+func (d *aIOpsIncidentDialect) Ack(id, by string, at time.Time) (string, []any) {
+	return `UPDATE aiops_incidents SET acked_by = ?, acked_at = ? WHERE id = ? AND acked_at IS NULL`,
+		[]any{by, at.Format(time.RFC3339), id}
+}
+
+func (d *aIOpsIncidentDialect) Assign(id, assignee string) (string, []any) {
+	return `UPDATE aiops_incidents SET assignee = ? WHERE id = ?`, []any{assignee, id}
+}
+
+func (d *aIOpsIncidentDialect) MarkFalsePositive(id string) (string, []any) {
+	return `UPDATE aiops_incidents SET false_positive = 1 WHERE id = ?`, []any{id}
+}
+
 func (d *aIOpsIncidentDialect) InsertEntity(entity *database.AIOpsIncidentEntity) (string, []any) {
 	return `INSERT OR REPLACE INTO aiops_incident_entities (incident_id, entity_key, entity_type, r_local, r_final, role)
 		VALUES (?, ?, ?, ?, ?, ?)`,
@@ -57,22 +71,24 @@ func (d *aIOpsIncidentDialect) SelectEntities(incidentID string) (string, []any)
 }
 
 func (d *aIOpsIncidentDialect) InsertTimeline(entry *database.AIOpsIncidentTimeline) (string, []any) {
-	return `INSERT INTO aiops_incident_timeline (incident_id, timestamp, event_type, entity_key, detail)
-		VALUES (?, ?, ?, ?, ?)`,
-		[]any{entry.IncidentID, entry.Timestamp.Format(time.RFC3339), entry.EventType, entry.EntityKey, entry.Detail}
+	return `INSERT INTO aiops_incident_timeline (incident_id, timestamp, event_type, entity_key, detail, actor)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		[]any{entry.IncidentID, entry.Timestamp.Format(time.RFC3339), entry.EventType, entry.EntityKey, entry.Detail, entry.Actor}
 }
 
 func (d *aIOpsIncidentDialect) SelectTimeline(incidentID string) (string, []any) {
-	return `SELECT id, incident_id, timestamp, event_type, entity_key, detail
+	return `SELECT id, incident_id, timestamp, event_type, entity_key, detail, actor
 		FROM aiops_incident_timeline WHERE incident_id = ? ORDER BY timestamp ASC`, []any{incidentID}
 }
 
 func (d *aIOpsIncidentDialect) ScanIncident(rows *sql.Rows) (*database.AIOpsIncident, error) {
 	inc := &database.AIOpsIncident{}
 	var startedAt, createdAt string
-	var resolvedAt sql.NullString
+	var resolvedAt, ackedBy, ackedAt, assignee sql.NullString
+	var falsePositive int
 	err := rows.Scan(&inc.ID, &inc.ClusterID, &inc.State, &inc.Severity, &inc.RootCause, &inc.PeakRisk,
-		&startedAt, &resolvedAt, &inc.DurationS, &inc.Recurrence, &inc.Summary, &createdAt)
+		&startedAt, &resolvedAt, &inc.DurationS, &inc.Recurrence, &inc.Summary, &createdAt,
+		&ackedBy, &ackedAt, &assignee, &falsePositive)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +98,13 @@ func (d *aIOpsIncidentDialect) ScanIncident(rows *sql.Rows) (*database.AIOpsInci
 		t, _ := time.Parse(time.RFC3339, resolvedAt.String)
 		inc.ResolvedAt = &t
 	}
+	inc.AckedBy = ackedBy.String
+	if ackedAt.Valid {
+		t, _ := time.Parse(time.RFC3339, ackedAt.String)
+		inc.AckedAt = &t
+	}
+	inc.Assignee = assignee.String
+	inc.FalsePositive = falsePositive == 1
 	// 活跃事件：动态计算持续时间（DB 中只有 Resolve 时才写入 duration_s）
 	if inc.ResolvedAt == nil {
 		inc.DurationS = int64(time.Since(inc.StartedAt).Seconds())
@@ -101,10 +124,12 @@ func (d *aIOpsIncidentDialect) ScanEntity(rows *sql.Rows) (*database.AIOpsIncide
 func (d *aIOpsIncidentDialect) ScanTimeline(rows *sql.Rows) (*database.AIOpsIncidentTimeline, error) {
 	t := &database.AIOpsIncidentTimeline{}
 	var timestamp string
-	err := rows.Scan(&t.ID, &t.IncidentID, &timestamp, &t.EventType, &t.EntityKey, &t.Detail)
+	var actor sql.NullString
+	err := rows.Scan(&t.ID, &t.IncidentID, &timestamp, &t.EventType, &t.EntityKey, &t.Detail, &actor)
 	if err != nil {
 		return nil, err
 	}
+	t.Actor = actor.String
 	t.Timestamp, _ = time.Parse(time.RFC3339, timestamp)
 	return t, nil
 }
@@ -0,0 +1,77 @@
+// atlhyper_master_v2/database/sqlite/aiops_maintenance.go
+// AIOps 维护窗口 SQLite 方言实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// aIOpsMaintenanceDialect AIOps 维护窗口 SQLite 方言
+type aIOpsMaintenanceDialect struct{}
+
+const maintenanceColumns = `id, name, cluster_id, namespace, node, workload, starts_at, ends_at, cron, duration_minutes,
+	timezone, source, reason, created_by, created_at, updated_at`
+
+func (d *aIOpsMaintenanceDialect) Insert(w *database.AIOpsMaintenanceWindow) (string, []any) {
+	query := `INSERT INTO aiops_maintenance_windows (name, cluster_id, namespace, node, workload, starts_at, ends_at, cron,
+	duration_minutes, timezone, source, reason, created_by, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	return query, []any{w.Name, w.ClusterID, w.Namespace, w.Node, w.Workload, w.StartsAt.UTC().Format(time.RFC3339),
+		formatEndsAt(w.EndsAt), w.Cron, w.DurationMinutes, w.Timezone, w.Source, w.Reason, w.CreatedBy,
+		w.CreatedAt.UTC().Format(time.RFC3339), w.UpdatedAt.UTC().Format(time.RFC3339)}
+}
+
+func (d *aIOpsMaintenanceDialect) Update(w *database.AIOpsMaintenanceWindow) (string, []any) {
+	query := `UPDATE aiops_maintenance_windows SET name = ?, cluster_id = ?, namespace = ?, node = ?, workload = ?,
+	starts_at = ?, ends_at = ?, cron = ?, duration_minutes = ?, timezone = ?, reason = ?, updated_at = ?
+	WHERE id = ?`
+	return query, []any{w.Name, w.ClusterID, w.Namespace, w.Node, w.Workload, w.StartsAt.UTC().Format(time.RFC3339),
+		formatEndsAt(w.EndsAt), w.Cron, w.DurationMinutes, w.Timezone, w.Reason, w.UpdatedAt.UTC().Format(time.RFC3339), w.ID}
+}
+
+func (d *aIOpsMaintenanceDialect) SelectByID(id int64) (string, []any) {
+	return "SELECT " + maintenanceColumns + " FROM aiops_maintenance_windows WHERE id = ?", []any{id}
+}
+
+func (d *aIOpsMaintenanceDialect) SelectAll() (string, []any) {
+	return "SELECT " + maintenanceColumns + " FROM aiops_maintenance_windows ORDER BY starts_at DESC, id DESC", nil
+}
+
+func (d *aIOpsMaintenanceDialect) SelectOpen(now time.Time) (string, []any) {
+	return "SELECT " + maintenanceColumns + ` FROM aiops_maintenance_windows
+	WHERE ends_at IS NULL OR ends_at > ? ORDER BY starts_at, id`, []any{now.UTC().Format(time.RFC3339)}
+}
+
+func (d *aIOpsMaintenanceDialect) ScanRow(rows *sql.Rows) (*database.AIOpsMaintenanceWindow, error) {
+	w := &database.AIOpsMaintenanceWindow{}
+	var endsAt, reason, createdBy sql.NullString
+	var startsAt, createdAt, updatedAt string
+	err := rows.Scan(&w.ID, &w.Name, &w.ClusterID, &w.Namespace, &w.Node, &w.Workload, &startsAt, &endsAt, &w.Cron,
+		&w.DurationMinutes, &w.Timezone, &w.Source, &reason, &createdBy, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	w.StartsAt, _ = time.Parse(time.RFC3339, startsAt)
+	if endsAt.Valid {
+		t, _ := time.Parse(time.RFC3339, endsAt.String)
+		w.EndsAt = &t
+	}
+	w.Reason = reason.String
+	w.CreatedBy = createdBy.String
+	w.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	w.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return w, nil
+}
+
+// formatEndsAt 结束时间（nil 写入 NULL）
+func formatEndsAt(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+var _ database.AIOpsMaintenanceDialect = (*aIOpsMaintenanceDialect)(nil)
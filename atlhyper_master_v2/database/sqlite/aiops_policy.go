@@ -0,0 +1,60 @@
+// atlhyper_master_v2/database/sqlite/aiops_policy.go
+// AIOps 策略版本 SQLite 方言实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// aIOpsPolicyDialect AIOps 策略版本 SQLite 方言
+type aIOpsPolicyDialect struct{}
+
+const policyColumns = `id, name, version, cluster_id, namespace, entity_type, spec, enabled, deleted, author, comment, created_at`
+
+func (d *aIOpsPolicyDialect) Insert(p *database.AIOpsPolicy) (string, []any) {
+	query := `INSERT INTO aiops_policies (name, version, cluster_id, namespace, entity_type, spec, enabled, deleted, author, comment, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	return query, []any{p.Name, p.Version, p.ClusterID, p.Namespace, p.EntityType, p.Spec, boolToInt(p.Enabled),
+		boolToInt(p.Deleted), p.Author, p.Comment, p.CreatedAt.UTC().Format(time.RFC3339)}
+}
+
+func (d *aIOpsPolicyDialect) SelectCurrent() (string, []any) {
+	query := "SELECT " + policyColumns + ` FROM aiops_policies p
+	WHERE version = (SELECT MAX(version) FROM aiops_policies WHERE name = p.name) AND deleted = 0
+	ORDER BY name`
+	return query, nil
+}
+
+func (d *aIOpsPolicyDialect) SelectVersions(name string) (string, []any) {
+	return "SELECT " + policyColumns + " FROM aiops_policies WHERE name = ? ORDER BY version DESC", []any{name}
+}
+
+func (d *aIOpsPolicyDialect) SelectVersion(name string, version int) (string, []any) {
+	if version <= 0 {
+		return "SELECT " + policyColumns + " FROM aiops_policies WHERE name = ? ORDER BY version DESC LIMIT 1", []any{name}
+	}
+	return "SELECT " + policyColumns + " FROM aiops_policies WHERE name = ? AND version = ?", []any{name, version}
+}
+
+func (d *aIOpsPolicyDialect) ScanRow(rows *sql.Rows) (*database.AIOpsPolicy, error) {
+	p := &database.AIOpsPolicy{}
+	var author, comment sql.NullString
+	var enabled, deleted int
+	var createdAt string
+	err := rows.Scan(&p.ID, &p.Name, &p.Version, &p.ClusterID, &p.Namespace, &p.EntityType, &p.Spec,
+		&enabled, &deleted, &author, &comment, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	p.Enabled = enabled != 0
+	p.Deleted = deleted != 0
+	p.Author = author.String
+	p.Comment = comment.String
+	p.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return p, nil
+}
+
+var _ database.AIOpsPolicyDialect = (*aIOpsPolicyDialect)(nil)
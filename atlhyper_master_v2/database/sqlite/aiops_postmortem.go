@@ -0,0 +1,60 @@
+// atlhyper_master_v2/database/sqlite/aiops_postmortem.go
+// AIOps 事件复盘文档 SQLite 方言实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// aIOpsPostmortemDialect AIOps 事件复盘文档 SQLite 方言
+type aIOpsPostmortemDialect struct{}
+
+// Upsert 保存生成结果（重新生成时覆盖正文与生成元数据，保留首次创建时间）
+func (d *aIOpsPostmortemDialect) Upsert(pm *database.AIOpsPostmortem) (string, []any) {
+	return `INSERT INTO aiops_postmortems (incident_id, cluster_id, content, source, provider_name, model, generated_by, updated_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(incident_id) DO UPDATE SET
+			content = excluded.content,
+			source = excluded.source,
+			provider_name = excluded.provider_name,
+			model = excluded.model,
+			generated_by = excluded.generated_by,
+			updated_by = excluded.updated_by,
+			updated_at = excluded.updated_at`,
+		[]any{pm.IncidentID, pm.ClusterID, pm.Content, pm.Source, pm.ProviderName, pm.Model,
+			pm.GeneratedBy, pm.UpdatedBy, pm.CreatedAt.Format(time.RFC3339), pm.UpdatedAt.Format(time.RFC3339)}
+}
+
+// UpdateContent 保存人工编辑后的正文
+func (d *aIOpsPostmortemDialect) UpdateContent(incidentID, content, by string, at time.Time) (string, []any) {
+	return `UPDATE aiops_postmortems SET content = ?, updated_by = ?, updated_at = ? WHERE incident_id = ?`,
+		[]any{content, by, at.Format(time.RFC3339), incidentID}
+}
+
+// SelectByIncident 按事件 ID 查询
+func (d *aIOpsPostmortemDialect) SelectByIncident(incidentID string) (string, []any) {
+	return `SELECT incident_id, cluster_id, content, source, provider_name, model, generated_by, updated_by, created_at, updated_at
+		FROM aiops_postmortems WHERE incident_id = ?`, []any{incidentID}
+}
+
+// ScanRow 扫描复盘文档行
+func (d *aIOpsPostmortemDialect) ScanRow(rows *sql.Rows) (*database.AIOpsPostmortem, error) {
+	pm := &database.AIOpsPostmortem{}
+	var providerName, model, generatedBy, updatedBy sql.NullString
+	var createdAt, updatedAt string
+	err := rows.Scan(&pm.IncidentID, &pm.ClusterID, &pm.Content, &pm.Source, &providerName, &model,
+		&generatedBy, &updatedBy, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	pm.ProviderName = providerName.String
+	pm.Model = model.String
+	pm.GeneratedBy = generatedBy.String
+	pm.UpdatedBy = updatedBy.String
+	pm.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	pm.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return pm, nil
+}
@@ -0,0 +1,103 @@
+// atlhyper_master_v2/database/sqlite/aiops_runbook_run.go
+// AIOps Runbook 执行记录 SQLite 方言实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// aIOpsRunbookRunDialect Runbook 执行记录 SQLite 方言
+type aIOpsRunbookRunDialect struct{}
+
+const runbookRunColumns = `id, runbook, incident_id, cluster_id, entity_key, status, dry_run, trigger_type, steps, error,
+	requested_by, approved_by, created_at, started_at, finished_at`
+
+func (d *aIOpsRunbookRunDialect) Insert(run *database.AIOpsRunbookRun) (string, []any) {
+	query := `INSERT INTO aiops_runbook_runs (runbook, incident_id, cluster_id, entity_key, status, dry_run, trigger_type, steps, error,
+	requested_by, approved_by, created_at, started_at, finished_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	return query, []any{run.Runbook, run.IncidentID, run.ClusterID, run.EntityKey, run.Status, boolToInt(run.DryRun),
+		run.Trigger, run.Steps, run.Error, run.RequestedBy, run.ApprovedBy, run.CreatedAt.UTC().Format(time.RFC3339),
+		formatOptionalTime(run.StartedAt), formatOptionalTime(run.FinishedAt)}
+}
+
+func (d *aIOpsRunbookRunDialect) Update(run *database.AIOpsRunbookRun) (string, []any) {
+	query := `UPDATE aiops_runbook_runs SET status = ?, steps = ?, error = ?, approved_by = ?, started_at = ?, finished_at = ?
+	WHERE id = ?`
+	return query, []any{run.Status, run.Steps, run.Error, run.ApprovedBy,
+		formatOptionalTime(run.StartedAt), formatOptionalTime(run.FinishedAt), run.ID}
+}
+
+func (d *aIOpsRunbookRunDialect) TransitionStatus(id int64, from, to, by string) (string, []any) {
+	return "UPDATE aiops_runbook_runs SET status = ?, approved_by = ? WHERE id = ? AND status = ?",
+		[]any{to, by, id, from}
+}
+
+func (d *aIOpsRunbookRunDialect) SelectByID(id int64) (string, []any) {
+	return "SELECT " + runbookRunColumns + " FROM aiops_runbook_runs WHERE id = ?", []any{id}
+}
+
+func (d *aIOpsRunbookRunDialect) List(opts database.RunbookRunQueryOpts) (string, []any) {
+	where, args := runbookRunWhere(opts)
+	query := "SELECT " + runbookRunColumns + " FROM aiops_runbook_runs" + where + " ORDER BY id DESC"
+	if opts.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, opts.Limit)
+	}
+	if opts.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, opts.Offset)
+	}
+	return query, args
+}
+
+func (d *aIOpsRunbookRunDialect) Count(opts database.RunbookRunQueryOpts) (string, []any) {
+	where, args := runbookRunWhere(opts)
+	return "SELECT COUNT(*) FROM aiops_runbook_runs" + where, args
+}
+
+func (d *aIOpsRunbookRunDialect) ScanRow(rows *sql.Rows) (*database.AIOpsRunbookRun, error) {
+	run := &database.AIOpsRunbookRun{}
+	var entityKey, steps, errMsg, requestedBy, approvedBy, startedAt, finishedAt sql.NullString
+	var dryRun int
+	var createdAt string
+	err := rows.Scan(&run.ID, &run.Runbook, &run.IncidentID, &run.ClusterID, &entityKey, &run.Status, &dryRun, &run.Trigger,
+		&steps, &errMsg, &requestedBy, &approvedBy, &createdAt, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	run.EntityKey = entityKey.String
+	run.DryRun = dryRun != 0
+	run.Steps = steps.String
+	run.Error = errMsg.String
+	run.RequestedBy = requestedBy.String
+	run.ApprovedBy = approvedBy.String
+	run.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	run.StartedAt = parseOptionalTime(startedAt)
+	run.FinishedAt = parseOptionalTime(finishedAt)
+	return run, nil
+}
+
+// runbookRunWhere 构建查询条件
+func runbookRunWhere(opts database.RunbookRunQueryOpts) (string, []any) {
+	where := " WHERE 1=1"
+	var args []any
+	if opts.IncidentID != "" {
+		where += " AND incident_id = ?"
+		args = append(args, opts.IncidentID)
+	}
+	if opts.ClusterID != "" {
+		where += " AND cluster_id = ?"
+		args = append(args, opts.ClusterID)
+	}
+	if opts.Status != "" {
+		where += " AND status = ?"
+		args = append(args, opts.Status)
+	}
+	return where, args
+}
+
+var _ database.AIOpsRunbookRunDialect = (*aIOpsRunbookRunDialect)(nil)
@@ -119,6 +119,14 @@ func (d *commandDialect) buildWhereClause(opts database.CommandQueryOpts) (strin
 		conditions = append(conditions, "target_name LIKE ?")
 		args = append(args, "%"+opts.Search+"%")
 	}
+	if !opts.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, opts.Since.Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, opts.Until.Format(time.RFC3339))
+	}
 
 	if len(conditions) == 0 {
 		return "", args
@@ -37,6 +37,14 @@ func (d *deployHistoryDialect) SelectWithOpts(opts database.DeployHistoryQueryOp
 		q += " AND path = ?"
 		args = append(args, opts.Path)
 	}
+	if !opts.Since.IsZero() {
+		q += " AND deployed_at >= ?"
+		args = append(args, opts.Since.Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		q += " AND deployed_at <= ?"
+		args = append(args, opts.Until.Format(time.RFC3339))
+	}
 	q += " ORDER BY deployed_at DESC"
 	if opts.Limit > 0 {
 		q += fmt.Sprintf(" LIMIT %d", opts.Limit)
@@ -58,6 +66,14 @@ func (d *deployHistoryDialect) CountWithOpts(opts database.DeployHistoryQueryOpt
 		q += " AND path = ?"
 		args = append(args, opts.Path)
 	}
+	if !opts.Since.IsZero() {
+		q += " AND deployed_at >= ?"
+		args = append(args, opts.Since.Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		q += " AND deployed_at <= ?"
+		args = append(args, opts.Until.Format(time.RFC3339))
+	}
 	return q, args
 }
 
@@ -14,8 +14,21 @@ type Dialect struct {
 	user            *userDialect
 	event           *eventDialect
 	notify          *notifyDialect
+	notifyRoute     *notifyRouteDialect
+	notifySilence   *notifySilenceDialect
+	notifyInhibit   *notifyInhibitDialect
+	notifyDelivery  *notifyDeliveryDialect
+	notifyTemplate  *notifyTemplateDialect
+	oncall          *oncallScheduleDialect
+	oncallOverride  *oncallOverrideDialect
+	escalation      *escalationPolicyDialect
+	escalationLog   *escalationDialect
 	cluster         *clusterDialect
+	agentToken      *agentTokenDialect
 	command         *commandDialect
+	execSession     *execSessionDialect
+	roleBinding     *roleBindingDialect
+	userIdentity    *userIdentityDialect
 	settings        *settingsDialect
 	aiConversation  *aiConversationDialect
 	aiMessage       *aiMessageDialect
@@ -26,9 +39,14 @@ type Dialect struct {
 	aiRoleBudget  *aiRoleBudgetDialect
 	aiReport      *aiReportDialect
 
-	aiopsBaseline *aIOpsBaselineDialect
-	aiopsGraph      *aIOpsGraphDialect
-	aiopsIncident   *aIOpsIncidentDialect
+	aiopsBaseline    *aIOpsBaselineDialect
+	aiopsGraph       *aIOpsGraphDialect
+	aiopsIncident    *aIOpsIncidentDialect
+	aiopsFeedback    *aIOpsFeedbackDialect
+	aiopsPostmortem  *aIOpsPostmortemDialect
+	aiopsRunbookRun  *aIOpsRunbookRunDialect
+	aiopsPolicy      *aIOpsPolicyDialect
+	aiopsMaintenance *aIOpsMaintenanceDialect
 
 	gitHubInstall  *gitHubInstallDialect
 	repoConfig     *repoConfigDialect
@@ -43,8 +61,21 @@ func NewDialect() *Dialect {
 		user:            &userDialect{},
 		event:           &eventDialect{},
 		notify:          &notifyDialect{},
+		notifyRoute:     &notifyRouteDialect{},
+		notifySilence:   &notifySilenceDialect{},
+		notifyInhibit:   &notifyInhibitDialect{},
+		notifyDelivery:  &notifyDeliveryDialect{},
+		notifyTemplate:  &notifyTemplateDialect{},
+		oncall:          &oncallScheduleDialect{},
+		oncallOverride:  &oncallOverrideDialect{},
+		escalation:      &escalationPolicyDialect{},
+		escalationLog:   &escalationDialect{},
 		cluster:         &clusterDialect{},
+		agentToken:      &agentTokenDialect{},
 		command:         &commandDialect{},
+		execSession:     &execSessionDialect{},
+		roleBinding:     &roleBindingDialect{},
+		userIdentity:    &userIdentityDialect{},
 		settings:        &settingsDialect{},
 		aiConversation:  &aiConversationDialect{},
 		aiMessage:       &aiMessageDialect{},
@@ -55,9 +86,14 @@ func NewDialect() *Dialect {
 		aiRoleBudget: &aiRoleBudgetDialect{},
 		aiReport:     &aiReportDialect{},
 
-		aiopsBaseline: &aIOpsBaselineDialect{},
-		aiopsGraph:      &aIOpsGraphDialect{},
-		aiopsIncident:   &aIOpsIncidentDialect{},
+		aiopsBaseline:    &aIOpsBaselineDialect{},
+		aiopsGraph:       &aIOpsGraphDialect{},
+		aiopsIncident:    &aIOpsIncidentDialect{},
+		aiopsFeedback:    &aIOpsFeedbackDialect{},
+		aiopsPostmortem:  &aIOpsPostmortemDialect{},
+		aiopsRunbookRun:  &aIOpsRunbookRunDialect{},
+		aiopsPolicy:      &aIOpsPolicyDialect{},
+		aiopsMaintenance: &aIOpsMaintenanceDialect{},
 
 		gitHubInstall: &gitHubInstallDialect{},
 		repoConfig:    &repoConfigDialect{},
@@ -70,8 +106,21 @@ func (d *Dialect) Audit() database.AuditDialect                   { return d.aud
 func (d *Dialect) User() database.UserDialect                     { return d.user }
 func (d *Dialect) Event() database.EventDialect                   { return d.event }
 func (d *Dialect) Notify() database.NotifyDialect                 { return d.notify }
+func (d *Dialect) NotifyRoute() database.NotifyRouteDialect       { return d.notifyRoute }
+func (d *Dialect) NotifySilence() database.NotifySilenceDialect   { return d.notifySilence }
+func (d *Dialect) NotifyInhibit() database.NotifyInhibitRuleDialect { return d.notifyInhibit }
+func (d *Dialect) NotifyDelivery() database.NotifyDeliveryDialect   { return d.notifyDelivery }
+func (d *Dialect) NotifyTemplate() database.NotifyTemplateDialect   { return d.notifyTemplate }
+func (d *Dialect) Oncall() database.OncallScheduleDialect           { return d.oncall }
+func (d *Dialect) OncallOverride() database.OncallOverrideDialect   { return d.oncallOverride }
+func (d *Dialect) Escalation() database.EscalationPolicyDialect     { return d.escalation }
+func (d *Dialect) EscalationLog() database.EscalationDialect        { return d.escalationLog }
 func (d *Dialect) Cluster() database.ClusterDialect               { return d.cluster }
+func (d *Dialect) AgentToken() database.AgentTokenDialect         { return d.agentToken }
 func (d *Dialect) Command() database.CommandDialect               { return d.command }
+func (d *Dialect) ExecSession() database.ExecSessionDialect       { return d.execSession }
+func (d *Dialect) RoleBinding() database.RoleBindingDialect       { return d.roleBinding }
+func (d *Dialect) UserIdentity() database.UserIdentityDialect     { return d.userIdentity }
 func (d *Dialect) Settings() database.SettingsDialect             { return d.settings }
 func (d *Dialect) AIConversation() database.AIConversationDialect { return d.aiConversation }
 func (d *Dialect) AIMessage() database.AIMessageDialect           { return d.aiMessage }
@@ -82,9 +131,14 @@ func (d *Dialect) SLO() database.SLODialect                         { return d.s
 func (d *Dialect) AIRoleBudget() database.AIRoleBudgetDialect       { return d.aiRoleBudget }
 func (d *Dialect) AIReport() database.AIReportDialect               { return d.aiReport }
 
-func (d *Dialect) AIOpsBaseline() database.AIOpsBaselineDialect     { return d.aiopsBaseline }
-func (d *Dialect) AIOpsGraph() database.AIOpsGraphDialect           { return d.aiopsGraph }
-func (d *Dialect) AIOpsIncident() database.AIOpsIncidentDialect     { return d.aiopsIncident }
+func (d *Dialect) AIOpsBaseline() database.AIOpsBaselineDialect       { return d.aiopsBaseline }
+func (d *Dialect) AIOpsGraph() database.AIOpsGraphDialect             { return d.aiopsGraph }
+func (d *Dialect) AIOpsIncident() database.AIOpsIncidentDialect       { return d.aiopsIncident }
+func (d *Dialect) AIOpsFeedback() database.AIOpsFeedbackDialect       { return d.aiopsFeedback }
+func (d *Dialect) AIOpsPostmortem() database.AIOpsPostmortemDialect   { return d.aiopsPostmortem }
+func (d *Dialect) AIOpsRunbookRun() database.AIOpsRunbookRunDialect   { return d.aiopsRunbookRun }
+func (d *Dialect) AIOpsPolicy() database.AIOpsPolicyDialect           { return d.aiopsPolicy }
+func (d *Dialect) AIOpsMaintenance() database.AIOpsMaintenanceDialect { return d.aiopsMaintenance }
 
 func (d *Dialect) GitHubInstall() database.GitHubInstallDialect   { return d.gitHubInstall }
 func (d *Dialect) RepoConfig() database.RepoConfigDialect         { return d.repoConfig }
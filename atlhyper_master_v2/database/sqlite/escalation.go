@@ -0,0 +1,128 @@
+// atlhyper_master_v2/database/sqlite/escalation.go
+// SQLite EscalationDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type escalationDialect struct{}
+
+const escalationColumns = `id, policy_id, alert_key, template_name, title, severity, data, level, status,
+	ack_token, acked_by, acked_at, next_at, created_at, updated_at`
+
+func (d *escalationDialect) Insert(e *database.Escalation) (string, []any) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	query := `INSERT INTO escalations (policy_id, alert_key, template_name, title, severity, data, level, status,
+	ack_token, acked_by, acked_at, next_at, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	return query, []any{e.PolicyID, e.AlertKey, e.TemplateName, e.Title, e.Severity, e.Data, e.Level, e.Status,
+		e.AckToken, e.AckedBy, formatOptionalTime(e.AckedAt), formatOptionalTime(e.NextAt), now, now}
+}
+
+func (d *escalationDialect) UpdateProgress(e *database.Escalation) (string, []any) {
+	return "UPDATE escalations SET level = ?, next_at = ?, updated_at = ? WHERE id = ? AND status = ?",
+		[]any{e.Level, formatOptionalTime(e.NextAt), time.Now().UTC().Format(time.RFC3339), e.ID, database.EscalationOpen}
+}
+
+func (d *escalationDialect) SelectByID(id int64) (string, []any) {
+	return "SELECT " + escalationColumns + " FROM escalations WHERE id = ?", []any{id}
+}
+
+func (d *escalationDialect) SelectByToken(token string) (string, []any) {
+	return "SELECT " + escalationColumns + " FROM escalations WHERE ack_token = ?", []any{token}
+}
+
+func (d *escalationDialect) SelectOpen(policyID int64, alertKey string) (string, []any) {
+	return "SELECT " + escalationColumns + " FROM escalations WHERE policy_id = ? AND alert_key = ? AND status = ? ORDER BY id DESC LIMIT 1",
+		[]any{policyID, alertKey, database.EscalationOpen}
+}
+
+func (d *escalationDialect) List(opts database.EscalationQueryOpts) (string, []any) {
+	where, args := escalationWhere(opts)
+	query := "SELECT " + escalationColumns + " FROM escalations" + where + " ORDER BY id DESC"
+	if opts.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, opts.Limit)
+	}
+	if opts.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, opts.Offset)
+	}
+	return query, args
+}
+
+func (d *escalationDialect) Count(opts database.EscalationQueryOpts) (string, []any) {
+	where, args := escalationWhere(opts)
+	return "SELECT COUNT(*) FROM escalations" + where, args
+}
+
+func (d *escalationDialect) SelectDue(now time.Time, limit int) (string, []any) {
+	query := "SELECT " + escalationColumns + ` FROM escalations
+	WHERE status = ? AND next_at IS NOT NULL AND next_at <= ? ORDER BY next_at LIMIT ?`
+	return query, []any{database.EscalationOpen, now.UTC().Format(time.RFC3339), limit}
+}
+
+func (d *escalationDialect) Ack(id int64, by string, at time.Time) (string, []any) {
+	ts := at.UTC().Format(time.RFC3339)
+	return "UPDATE escalations SET status = ?, acked_by = ?, acked_at = ?, next_at = NULL, updated_at = ? WHERE id = ? AND status = ?",
+		[]any{database.EscalationAcked, by, ts, ts, id, database.EscalationOpen}
+}
+
+func (d *escalationDialect) ResolveAlert(alertKey string, at time.Time) (string, []any) {
+	return "UPDATE escalations SET status = ?, next_at = NULL, updated_at = ? WHERE alert_key = ? AND status = ?",
+		[]any{database.EscalationResolved, at.UTC().Format(time.RFC3339), alertKey, database.EscalationOpen}
+}
+
+func (d *escalationDialect) DeleteFinishedBefore(before time.Time) (string, []any) {
+	return "DELETE FROM escalations WHERE status IN (?, ?) AND updated_at < ?",
+		[]any{database.EscalationAcked, database.EscalationResolved, before.UTC().Format(time.RFC3339)}
+}
+
+func (d *escalationDialect) ScanRow(rows *sql.Rows) (*database.Escalation, error) {
+	e := &database.Escalation{}
+	var templateName, title, severity, ackedBy, ackedAt, nextAt sql.NullString
+	var createdAt, updatedAt string
+	err := rows.Scan(&e.ID, &e.PolicyID, &e.AlertKey, &templateName, &title, &severity, &e.Data, &e.Level, &e.Status,
+		&e.AckToken, &ackedBy, &ackedAt, &nextAt, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	e.TemplateName = templateName.String
+	e.Title = title.String
+	e.Severity = severity.String
+	e.AckedBy = ackedBy.String
+	e.AckedAt = parseOptionalTime(ackedAt)
+	e.NextAt = parseOptionalTime(nextAt)
+	e.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	e.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return e, nil
+}
+
+// escalationWhere 构建查询条件
+func escalationWhere(opts database.EscalationQueryOpts) (string, []any) {
+	where := " WHERE 1=1"
+	var args []any
+	if opts.Status != "" {
+		where += " AND status = ?"
+		args = append(args, opts.Status)
+	}
+	return where, args
+}
+
+// parseOptionalTime 可选时间（NULL / 空串返回 nil）
+func parseOptionalTime(s sql.NullString) *time.Time {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+var _ database.EscalationDialect = (*escalationDialect)(nil)
@@ -0,0 +1,54 @@
+// atlhyper_master_v2/database/sqlite/escalation_policy.go
+// SQLite EscalationPolicyDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type escalationPolicyDialect struct{}
+
+const escalationPolicyColumns = "id, name, enabled, matchers, schedule_id, steps, created_at, updated_at"
+
+func (d *escalationPolicyDialect) Insert(p *database.EscalationPolicy) (string, []any) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	query := `INSERT INTO escalation_policies (name, enabled, matchers, schedule_id, steps, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+	return query, []any{p.Name, p.Enabled, p.Matchers, p.ScheduleID, p.Steps, now, now}
+}
+
+func (d *escalationPolicyDialect) Update(p *database.EscalationPolicy) (string, []any) {
+	query := `UPDATE escalation_policies SET name = ?, enabled = ?, matchers = ?, schedule_id = ?, steps = ?, updated_at = ?
+	WHERE id = ?`
+	return query, []any{p.Name, p.Enabled, p.Matchers, p.ScheduleID, p.Steps, time.Now().UTC().Format(time.RFC3339), p.ID}
+}
+
+func (d *escalationPolicyDialect) Delete(id int64) (string, []any) {
+	return "DELETE FROM escalation_policies WHERE id = ?", []any{id}
+}
+
+func (d *escalationPolicyDialect) SelectByID(id int64) (string, []any) {
+	return "SELECT " + escalationPolicyColumns + " FROM escalation_policies WHERE id = ?", []any{id}
+}
+
+func (d *escalationPolicyDialect) SelectAll() (string, []any) {
+	return "SELECT " + escalationPolicyColumns + " FROM escalation_policies ORDER BY id", nil
+}
+
+func (d *escalationPolicyDialect) ScanRow(rows *sql.Rows) (*database.EscalationPolicy, error) {
+	p := &database.EscalationPolicy{}
+	var enabled int
+	var createdAt, updatedAt string
+	if err := rows.Scan(&p.ID, &p.Name, &enabled, &p.Matchers, &p.ScheduleID, &p.Steps, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	p.Enabled = enabled == 1
+	p.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	p.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return p, nil
+}
+
+var _ database.EscalationPolicyDialect = (*escalationPolicyDialect)(nil)
@@ -0,0 +1,87 @@
+// atlhyper_master_v2/database/sqlite/exec_session.go
+// SQLite ExecSessionDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type execSessionDialect struct{}
+
+const execSessionColumns = `id, session_id, cluster_id, namespace, pod, container, command, user_id, username,
+	started_at, ended_at, exit_code, end_reason, transcript, truncated`
+
+// execSessionListColumns 列表查询不返回 transcript（体积较大，详情接口单独获取）
+const execSessionListColumns = `id, session_id, cluster_id, namespace, pod, container, command, user_id, username,
+	started_at, ended_at, exit_code, end_reason, '' AS transcript, truncated`
+
+func (d *execSessionDialect) Insert(s *database.ExecSession) (string, []any) {
+	query := `INSERT INTO exec_sessions (session_id, cluster_id, namespace, pod, container, command, user_id, username,
+		started_at, ended_at, exit_code, end_reason, transcript, truncated)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	var exitCode any
+	if s.ExitCode != nil {
+		exitCode = *s.ExitCode
+	}
+	args := []any{
+		s.SessionID, s.ClusterID, s.Namespace, s.Pod, s.Container, s.Command, s.UserID, s.Username,
+		s.StartedAt.Format(time.RFC3339), s.EndedAt.Format(time.RFC3339), exitCode, s.EndReason,
+		s.Transcript, boolToInt(s.Truncated),
+	}
+	return query, args
+}
+
+func (d *execSessionDialect) SelectBySessionID(sessionID string) (string, []any) {
+	return "SELECT " + execSessionColumns + " FROM exec_sessions WHERE session_id = ?", []any{sessionID}
+}
+
+func (d *execSessionDialect) List(opts database.ExecSessionQueryOpts) (string, []any) {
+	query := "SELECT " + execSessionListColumns + " FROM exec_sessions WHERE 1=1"
+	args := []any{}
+
+	if opts.ClusterID != "" {
+		query += " AND cluster_id = ?"
+		args = append(args, opts.ClusterID)
+	}
+	if opts.UserID > 0 {
+		query += " AND user_id = ?"
+		args = append(args, opts.UserID)
+	}
+
+	query += " ORDER BY started_at DESC"
+
+	if opts.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, opts.Limit)
+	}
+	if opts.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, opts.Offset)
+	}
+	return query, args
+}
+
+func (d *execSessionDialect) ScanRow(rows *sql.Rows) (*database.ExecSession, error) {
+	s := &database.ExecSession{}
+	var exitCode sql.NullInt64
+	var startedAt, endedAt string
+	var truncated int
+	err := rows.Scan(&s.ID, &s.SessionID, &s.ClusterID, &s.Namespace, &s.Pod, &s.Container, &s.Command,
+		&s.UserID, &s.Username, &startedAt, &endedAt, &exitCode, &s.EndReason, &s.Transcript, &truncated)
+	if err != nil {
+		return nil, err
+	}
+	s.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
+	s.EndedAt, _ = time.Parse(time.RFC3339, endedAt)
+	if exitCode.Valid {
+		code := int(exitCode.Int64)
+		s.ExitCode = &code
+	}
+	s.Truncated = truncated == 1
+	return s, nil
+}
+
+var _ database.ExecSessionDialect = (*execSessionDialect)(nil)
@@ -4,6 +4,7 @@ package sqlite
 
 import (
 	"database/sql"
+	"strings"
 	"time"
 
 	"AtlHyper/atlhyper_master_v2/config"
@@ -47,7 +48,7 @@ func migrate(db *sql.DB) error {
 		// ==================== 通知渠道表 ====================
 		`CREATE TABLE IF NOT EXISTS notify_channels (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			type TEXT UNIQUE NOT NULL,
+			type TEXT NOT NULL,
 			name TEXT NOT NULL,
 			enabled INTEGER DEFAULT 0,
 			config TEXT,
@@ -55,6 +56,133 @@ func migrate(db *sql.DB) error {
 			updated_at TEXT NOT NULL
 		)`,
 
+		// ==================== 告警路由 ====================
+		`CREATE TABLE IF NOT EXISTS notify_routes (
+			id               INTEGER PRIMARY KEY AUTOINCREMENT,
+			name             TEXT NOT NULL,
+			priority         INTEGER DEFAULT 0,
+			enabled          INTEGER DEFAULT 1,
+			matchers         TEXT NOT NULL DEFAULT '{}',
+			channel_ids      TEXT NOT NULL DEFAULT '[]',
+			group_by         TEXT NOT NULL DEFAULT '[]',
+			group_wait_s     INTEGER DEFAULT 30,
+			group_interval_s INTEGER DEFAULT 300,
+			continue_match   INTEGER DEFAULT 0,
+			created_at       TEXT NOT NULL,
+			updated_at       TEXT NOT NULL
+		)`,
+
+		// ==================== 告警静默 ====================
+		`CREATE TABLE IF NOT EXISTS notify_silences (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			matchers   TEXT NOT NULL DEFAULT '{}',
+			starts_at  TEXT NOT NULL,
+			ends_at    TEXT NOT NULL,
+			comment    TEXT DEFAULT '',
+			created_by TEXT DEFAULT '',
+			created_at TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_notify_silences_ends ON notify_silences(ends_at)`,
+
+		// ==================== 告警抑制规则 ====================
+		`CREATE TABLE IF NOT EXISTS notify_inhibit_rules (
+			id              INTEGER PRIMARY KEY AUTOINCREMENT,
+			name            TEXT NOT NULL UNIQUE,
+			enabled         INTEGER DEFAULT 1,
+			source_matchers TEXT NOT NULL DEFAULT '{}',
+			target_matchers TEXT NOT NULL DEFAULT '{}',
+			equal_labels    TEXT NOT NULL DEFAULT '[]',
+			created_at      TEXT NOT NULL,
+			updated_at      TEXT NOT NULL
+		)`,
+
+		// ==================== 通知投递记录 ====================
+		`CREATE TABLE IF NOT EXISTS notify_deliveries (
+			id              INTEGER PRIMARY KEY AUTOINCREMENT,
+			channel_id      INTEGER NOT NULL,
+			channel_type    TEXT NOT NULL,
+			channel_name    TEXT DEFAULT '',
+			template_name   TEXT DEFAULT '',
+			subject         TEXT DEFAULT '',
+			payload         TEXT NOT NULL,
+			payload_hash    TEXT NOT NULL,
+			status          TEXT NOT NULL,
+			attempts        INTEGER DEFAULT 0,
+			last_error      TEXT DEFAULT '',
+			next_attempt_at TEXT NOT NULL,
+			sent_at         TEXT,
+			created_at      TEXT NOT NULL,
+			updated_at      TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_notify_deliveries_due ON notify_deliveries(status, next_attempt_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_notify_deliveries_created ON notify_deliveries(created_at)`,
+
+		// ==================== 自定义通知模板 ====================
+		// 每个 (name, variant) 一条，覆盖嵌入的内置模板
+		`CREATE TABLE IF NOT EXISTS notify_templates (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			name       TEXT NOT NULL,
+			variant    TEXT NOT NULL,
+			body       TEXT NOT NULL,
+			updated_by TEXT DEFAULT '',
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			UNIQUE(name, variant)
+		)`,
+
+		// ==================== 值班与升级 ====================
+		`CREATE TABLE IF NOT EXISTS oncall_schedules (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			name        TEXT NOT NULL UNIQUE,
+			description TEXT DEFAULT '',
+			rotation    TEXT NOT NULL,
+			start_at    TEXT NOT NULL,
+			members     TEXT NOT NULL DEFAULT '[]',
+			created_at  TEXT NOT NULL,
+			updated_at  TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS oncall_overrides (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			schedule_id INTEGER NOT NULL,
+			user_id     INTEGER NOT NULL,
+			starts_at   TEXT NOT NULL,
+			ends_at     TEXT NOT NULL,
+			comment     TEXT DEFAULT '',
+			created_by  TEXT DEFAULT '',
+			created_at  TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_oncall_overrides_schedule ON oncall_overrides(schedule_id, ends_at)`,
+		`CREATE TABLE IF NOT EXISTS escalation_policies (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			name        TEXT NOT NULL UNIQUE,
+			enabled     INTEGER DEFAULT 1,
+			matchers    TEXT NOT NULL DEFAULT '{}',
+			schedule_id INTEGER NOT NULL,
+			steps       TEXT NOT NULL DEFAULT '[]',
+			created_at  TEXT NOT NULL,
+			updated_at  TEXT NOT NULL
+		)`,
+		// 每个策略 × 告警一条；ack_token 用于邮件 / Slack 中的确认链接
+		`CREATE TABLE IF NOT EXISTS escalations (
+			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			policy_id     INTEGER NOT NULL,
+			alert_key     TEXT NOT NULL,
+			template_name TEXT DEFAULT '',
+			title         TEXT DEFAULT '',
+			severity      TEXT DEFAULT '',
+			data          TEXT NOT NULL DEFAULT '{}',
+			level         INTEGER DEFAULT 0,
+			status        TEXT NOT NULL,
+			ack_token     TEXT NOT NULL UNIQUE,
+			acked_by      TEXT DEFAULT '',
+			acked_at      TEXT,
+			next_at       TEXT,
+			created_at    TEXT NOT NULL,
+			updated_at    TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_escalations_due ON escalations(status, next_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_escalations_alert ON escalations(alert_key, status)`,
+
 		// ==================== 用户表 ====================
 		`CREATE TABLE IF NOT EXISTS users (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -81,6 +209,20 @@ func migrate(db *sql.DB) error {
 			updated_at TEXT NOT NULL
 		)`,
 
+		// ==================== 集群 Agent 凭证表 ====================
+		// 每集群一条，只保存 Token 摘要；previous_hash 用于轮换宽限期
+		`CREATE TABLE IF NOT EXISTS cluster_agent_tokens (
+			cluster_id TEXT PRIMARY KEY,
+			token_hash TEXT NOT NULL,
+			token_prefix TEXT NOT NULL,
+			previous_hash TEXT,
+			previous_expires_at TEXT,
+			created_by INTEGER,
+			created_at TEXT NOT NULL,
+			rotated_at TEXT NOT NULL,
+			last_used_at TEXT
+		)`,
+
 		// ==================== 审计日志表 ====================
 		`CREATE TABLE IF NOT EXISTS audit_logs (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -125,6 +267,55 @@ func migrate(db *sql.DB) error {
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_cmd_cluster ON command_history(cluster_id, created_at DESC)`,
 
+		// ==================== Exec 会话表 ====================
+		// 交互式 exec 会话的完整记录（审计日志只保存摘要）
+		`CREATE TABLE IF NOT EXISTS exec_sessions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id TEXT UNIQUE NOT NULL,
+			cluster_id TEXT NOT NULL,
+			namespace TEXT NOT NULL,
+			pod TEXT NOT NULL,
+			container TEXT,
+			command TEXT,
+			user_id INTEGER,
+			username TEXT,
+			started_at TEXT NOT NULL,
+			ended_at TEXT NOT NULL,
+			exit_code INTEGER,
+			end_reason TEXT,
+			transcript TEXT,
+			truncated INTEGER DEFAULT 0
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_exec_cluster ON exec_sessions(cluster_id, started_at DESC)`,
+
+		// ==================== 角色绑定表 ====================
+		// 按集群/命名空间授权（无绑定的用户沿用 users.role 全局角色）
+		`CREATE TABLE IF NOT EXISTS role_bindings (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			cluster_id TEXT NOT NULL DEFAULT '',
+			namespace TEXT NOT NULL DEFAULT '',
+			role INTEGER NOT NULL,
+			created_by INTEGER,
+			created_at TEXT NOT NULL,
+			UNIQUE(user_id, cluster_id, namespace)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_role_bindings_user ON role_bindings(user_id)`,
+
+		// ==================== 外部身份表 ====================
+		// OIDC 登录的 issuer + sub 关联到本地用户（首次登录时自动创建用户）
+		`CREATE TABLE IF NOT EXISTS user_identities (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			issuer TEXT NOT NULL,
+			subject TEXT NOT NULL,
+			email TEXT,
+			created_at TEXT NOT NULL,
+			last_login TEXT NOT NULL,
+			UNIQUE(issuer, subject)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_user_identities_user ON user_identities(user_id)`,
+
 		// ==================== 系统设置表 ====================
 		`CREATE TABLE IF NOT EXISTS settings (
 			key TEXT PRIMARY KEY,
@@ -302,7 +493,7 @@ func migrate(db *sql.DB) error {
 		`CREATE INDEX IF NOT EXISTS idx_route_mapping_service ON slo_route_mapping(cluster_id, service_key)`,
 
 		// ==================== AIOps: 基线状态表 ====================
-		// 持久化 EMA 状态，用于重启恢复
+		// 持久化 EMA 状态（含季节性分桶），用于重启恢复
 		`CREATE TABLE IF NOT EXISTS aiops_baseline_states (
 			entity_key  TEXT NOT NULL,
 			metric_name TEXT NOT NULL,
@@ -310,6 +501,10 @@ func migrate(db *sql.DB) error {
 			variance    REAL NOT NULL,
 			count       INTEGER NOT NULL,
 			updated_at  INTEGER NOT NULL,
+			mode        TEXT NOT NULL DEFAULT 'ema',
+			seasonal    TEXT NOT NULL DEFAULT '',
+			detector    TEXT NOT NULL DEFAULT 'ema',
+			detector_state TEXT NOT NULL DEFAULT '',
 			PRIMARY KEY (entity_key, metric_name)
 		)`,
 
@@ -335,7 +530,11 @@ func migrate(db *sql.DB) error {
 			duration_s INTEGER NOT NULL DEFAULT 0,
 			recurrence INTEGER NOT NULL DEFAULT 0,
 			summary TEXT,
-			created_at TEXT NOT NULL
+			created_at TEXT NOT NULL,
+			acked_by TEXT,
+			acked_at TEXT,
+			assignee TEXT,
+			false_positive INTEGER NOT NULL DEFAULT 0
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_aiops_incidents_cluster ON aiops_incidents(cluster_id, started_at DESC)`,
 		`CREATE INDEX IF NOT EXISTS idx_aiops_incidents_state ON aiops_incidents(state)`,
@@ -363,10 +562,97 @@ func migrate(db *sql.DB) error {
 			event_type TEXT NOT NULL,
 			entity_key TEXT,
 			detail TEXT,
+			actor TEXT,
 			FOREIGN KEY (incident_id) REFERENCES aiops_incidents(id) ON DELETE CASCADE
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_aiops_incident_timeline_inc ON aiops_incident_timeline(incident_id, timestamp ASC)`,
 
+		// ==================== AIOps: 误报反馈阈值表 ====================
+		// 人工标记误报后，按实体模式 + 指标抬高异常判定阈值
+		`CREATE TABLE IF NOT EXISTS aiops_threshold_feedback (
+			entity_pattern TEXT NOT NULL,
+			metric_name TEXT NOT NULL,
+			threshold REAL NOT NULL,
+			false_positives INTEGER NOT NULL DEFAULT 0,
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY (entity_pattern, metric_name)
+		)`,
+
+		// ==================== AIOps: 事件复盘文档表 ====================
+		// 每个事件一份 Markdown 复盘，重新生成时覆盖，人工编辑只更新正文
+		`CREATE TABLE IF NOT EXISTS aiops_postmortems (
+			incident_id TEXT PRIMARY KEY,
+			cluster_id TEXT NOT NULL,
+			content TEXT NOT NULL,
+			source TEXT NOT NULL DEFAULT 'template',
+			provider_name TEXT,
+			model TEXT,
+			generated_by TEXT,
+			updated_by TEXT,
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL
+		)`,
+
+		// ==================== AIOps: Runbook 执行记录表 ====================
+		// steps 为各步骤执行结果 JSON；同一事件同一 Runbook 只执行一次
+		`CREATE TABLE IF NOT EXISTS aiops_runbook_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			runbook TEXT NOT NULL,
+			incident_id TEXT NOT NULL,
+			cluster_id TEXT NOT NULL,
+			entity_key TEXT,
+			status TEXT NOT NULL,
+			dry_run INTEGER NOT NULL DEFAULT 0,
+			trigger_type TEXT NOT NULL DEFAULT 'auto',
+			steps TEXT,
+			error TEXT,
+			requested_by TEXT,
+			approved_by TEXT,
+			created_at TEXT NOT NULL,
+			started_at TEXT,
+			finished_at TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_aiops_runbook_runs_incident ON aiops_runbook_runs(incident_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_aiops_runbook_runs_status ON aiops_runbook_runs(status)`,
+
+		// ==================== AIOps 策略（版本化）====================
+		`CREATE TABLE IF NOT EXISTS aiops_policies (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			cluster_id TEXT NOT NULL DEFAULT '',
+			namespace TEXT NOT NULL DEFAULT '',
+			entity_type TEXT NOT NULL DEFAULT '',
+			spec TEXT NOT NULL,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			deleted INTEGER NOT NULL DEFAULT 0,
+			author TEXT,
+			comment TEXT,
+			created_at TEXT NOT NULL,
+			UNIQUE(name, version)
+		)`,
+
+		// ==================== AIOps 维护窗口 ====================
+		`CREATE TABLE IF NOT EXISTS aiops_maintenance_windows (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			cluster_id TEXT NOT NULL,
+			namespace TEXT NOT NULL DEFAULT '',
+			node TEXT NOT NULL DEFAULT '',
+			workload TEXT NOT NULL DEFAULT '',
+			starts_at TEXT NOT NULL,
+			ends_at TEXT,
+			cron TEXT NOT NULL DEFAULT '',
+			duration_minutes INTEGER NOT NULL DEFAULT 0,
+			timezone TEXT NOT NULL DEFAULT '',
+			source TEXT NOT NULL DEFAULT 'manual',
+			reason TEXT,
+			created_by TEXT,
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_aiops_maintenance_ends ON aiops_maintenance_windows(ends_at)`,
+
 		// ==================== GitHub App 安装记录（单行）====================
 		`CREATE TABLE IF NOT EXISTS github_installations (
 			id              INTEGER PRIMARY KEY,
@@ -432,6 +718,34 @@ func migrate(db *sql.DB) error {
 		}
 	}
 
+	// notify_channels 旧表 type 唯一约束移除（同类型允许多个渠道）
+	if err := migrateNotifyChannelsMultiType(db); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_notify_channels_type ON notify_channels(type)`); err != nil {
+		return err
+	}
+
+	// AIOps 事件旧表补充人工处理字段
+	if err := migrateAIOpsIncidentActions(db); err != nil {
+		return err
+	}
+
+	// AIOps 基线旧表补充季节性与检测器字段
+	if err := addMissingColumns(db, "aiops_baseline_states", []columnDef{
+		{"mode", "mode TEXT NOT NULL DEFAULT 'ema'"},
+		{"seasonal", "seasonal TEXT NOT NULL DEFAULT ''"},
+		{"detector", "detector TEXT NOT NULL DEFAULT 'ema'"},
+		{"detector_state", "detector_state TEXT NOT NULL DEFAULT ''"},
+	}); err != nil {
+		return err
+	}
+
+	// 初始化默认抑制规则
+	if err := initDefaultInhibitRules(db); err != nil {
+		return err
+	}
+
 	// 初始化默认管理员（从配置读取）
 	if err := initDefaultAdmin(db); err != nil {
 		return err
@@ -611,6 +925,132 @@ func initDefaultRoleBudgets(db *sql.DB) error {
 	return nil
 }
 
+// migrateNotifyChannelsMultiType 重建 notify_channels 表以去掉 type 唯一约束
+// SQLite 不支持 DROP CONSTRAINT，只能建新表、复制数据、改名
+func migrateNotifyChannelsMultiType(db *sql.DB) error {
+	var ddl string
+	err := db.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'notify_channels'`).Scan(&ddl)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(ddl, "type TEXT UNIQUE") {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmts := []string{
+		`CREATE TABLE notify_channels_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			type TEXT NOT NULL,
+			name TEXT NOT NULL,
+			enabled INTEGER DEFAULT 0,
+			config TEXT,
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL
+		)`,
+		`INSERT INTO notify_channels_new (id, type, name, enabled, config, created_at, updated_at)
+			SELECT id, type, name, enabled, config, created_at, updated_at FROM notify_channels`,
+		`DROP TABLE notify_channels`,
+		`ALTER TABLE notify_channels_new RENAME TO notify_channels`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	log.Info("已移除 notify_channels.type 唯一约束")
+	return nil
+}
+
+// migrateAIOpsIncidentActions 为旧版 AIOps 事件表补充人工处理字段
+// （事件确认/指派/误报、时间线操作人）
+func migrateAIOpsIncidentActions(db *sql.DB) error {
+	if err := addMissingColumns(db, "aiops_incidents", []columnDef{
+		{"acked_by", "acked_by TEXT"},
+		{"acked_at", "acked_at TEXT"},
+		{"assignee", "assignee TEXT"},
+		{"false_positive", "false_positive INTEGER NOT NULL DEFAULT 0"},
+	}); err != nil {
+		return err
+	}
+	return addMissingColumns(db, "aiops_incident_timeline", []columnDef{
+		{"actor", "actor TEXT"},
+	})
+}
+
+// columnDef 待补充的列（列名 + ADD COLUMN 定义）
+type columnDef struct {
+	name string
+	ddl  string
+}
+
+// addMissingColumns 为已存在的表补充缺失列
+// SQLite 的 ADD COLUMN 不支持 IF NOT EXISTS，先查 table_info 再逐列补齐
+func addMissingColumns(db *sql.DB, table string, columns []columnDef) error {
+	rows, err := db.Query(`PRAGMA table_info(` + table + `)`)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid       int
+			name, typ string
+			notNull   int
+			dflt      sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range columns {
+		if existing[c.name] {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + c.ddl); err != nil {
+			return err
+		}
+		log.Info("已补充数据库字段", "table", table, "column", c.name)
+	}
+	return nil
+}
+
+// initDefaultInhibitRules 初始化默认抑制规则（种子数据）
+// 集群心跳离线时抑制该集群的 K8s 事件告警
+func initDefaultInhibitRules(db *sql.DB) error {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM notify_inhibit_rules").Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	_, err := db.Exec(`INSERT INTO notify_inhibit_rules
+		(name, enabled, source_matchers, target_matchers, equal_labels, created_at, updated_at)
+		VALUES (?, 1, ?, ?, ?, ?, ?)`,
+		"heartbeat-offline-inhibits-k8s-event",
+		`{"alertname":"heartbeat_offline"}`, `{"source":"k8s_event"}`, `["cluster"]`, now, now)
+	return err
+}
+
 // cleanupEntrypointData 清理无效的 entrypoint 级别 SLO 数据
 func cleanupEntrypointData(db *sql.DB) error {
 	result, err := db.Exec(`DELETE FROM slo_targets WHERE host LIKE ?`, "%@entrypoint%")
@@ -31,11 +31,11 @@ func (d *notifyDialect) SelectByID(id int64) (string, []any) {
 }
 
 func (d *notifyDialect) SelectByType(channelType string) (string, []any) {
-	return "SELECT id, type, name, enabled, config, created_at, updated_at FROM notify_channels WHERE type = ?", []any{channelType}
+	return "SELECT id, type, name, enabled, config, created_at, updated_at FROM notify_channels WHERE type = ? ORDER BY id LIMIT 1", []any{channelType}
 }
 
 func (d *notifyDialect) SelectAll() (string, []any) {
-	return "SELECT id, type, name, enabled, config, created_at, updated_at FROM notify_channels", nil
+	return "SELECT id, type, name, enabled, config, created_at, updated_at FROM notify_channels ORDER BY id", nil
 }
 
 func (d *notifyDialect) SelectEnabled() (string, []any) {
@@ -0,0 +1,119 @@
+// atlhyper_master_v2/database/sqlite/notify_delivery.go
+// SQLite NotifyDeliveryDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type notifyDeliveryDialect struct{}
+
+const notifyDeliveryColumns = `id, channel_id, channel_type, channel_name, template_name, subject, payload, payload_hash,
+	status, attempts, last_error, next_attempt_at, sent_at, created_at, updated_at`
+
+func (d *notifyDeliveryDialect) Insert(n *database.NotifyDelivery) (string, []any) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	query := `INSERT INTO notify_deliveries (channel_id, channel_type, channel_name, template_name, subject, payload, payload_hash,
+	status, attempts, last_error, next_attempt_at, sent_at, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	return query, []any{n.ChannelID, n.ChannelType, n.ChannelName, n.TemplateName, n.Subject, n.Payload, n.PayloadHash,
+		n.Status, n.Attempts, n.LastError, n.NextAttemptAt.UTC().Format(time.RFC3339), formatOptionalTime(n.SentAt), now, now}
+}
+
+func (d *notifyDeliveryDialect) UpdateAttempt(n *database.NotifyDelivery) (string, []any) {
+	query := `UPDATE notify_deliveries SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, sent_at = ?, updated_at = ?
+	WHERE id = ?`
+	return query, []any{n.Status, n.Attempts, n.LastError, n.NextAttemptAt.UTC().Format(time.RFC3339),
+		formatOptionalTime(n.SentAt), time.Now().UTC().Format(time.RFC3339), n.ID}
+}
+
+func (d *notifyDeliveryDialect) SelectByID(id int64) (string, []any) {
+	return "SELECT " + notifyDeliveryColumns + " FROM notify_deliveries WHERE id = ?", []any{id}
+}
+
+func (d *notifyDeliveryDialect) List(opts database.NotifyDeliveryQueryOpts) (string, []any) {
+	where, args := notifyDeliveryWhere(opts)
+	query := "SELECT " + notifyDeliveryColumns + " FROM notify_deliveries" + where + " ORDER BY id DESC"
+	if opts.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, opts.Limit)
+	}
+	if opts.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, opts.Offset)
+	}
+	return query, args
+}
+
+func (d *notifyDeliveryDialect) Count(opts database.NotifyDeliveryQueryOpts) (string, []any) {
+	where, args := notifyDeliveryWhere(opts)
+	return "SELECT COUNT(*) FROM notify_deliveries" + where, args
+}
+
+func (d *notifyDeliveryDialect) SelectDue(now time.Time, limit int) (string, []any) {
+	query := "SELECT " + notifyDeliveryColumns + ` FROM notify_deliveries
+	WHERE status IN (?, ?) AND next_attempt_at <= ? ORDER BY next_attempt_at LIMIT ?`
+	return query, []any{database.DeliveryPending, database.DeliveryFailed, now.UTC().Format(time.RFC3339), limit}
+}
+
+func (d *notifyDeliveryDialect) Requeue(id int64, now time.Time) (string, []any) {
+	ts := now.UTC().Format(time.RFC3339)
+	return "UPDATE notify_deliveries SET status = ?, attempts = 0, next_attempt_at = ?, updated_at = ? WHERE id = ?",
+		[]any{database.DeliveryPending, ts, ts, id}
+}
+
+func (d *notifyDeliveryDialect) DeleteFinishedBefore(before time.Time) (string, []any) {
+	return "DELETE FROM notify_deliveries WHERE status IN (?, ?) AND created_at < ?",
+		[]any{database.DeliverySent, database.DeliveryDead, before.UTC().Format(time.RFC3339)}
+}
+
+func (d *notifyDeliveryDialect) ScanRow(rows *sql.Rows) (*database.NotifyDelivery, error) {
+	n := &database.NotifyDelivery{}
+	var channelName, templateName, subject, lastError, sentAt sql.NullString
+	var nextAttemptAt, createdAt, updatedAt string
+	err := rows.Scan(&n.ID, &n.ChannelID, &n.ChannelType, &channelName, &templateName, &subject, &n.Payload, &n.PayloadHash,
+		&n.Status, &n.Attempts, &lastError, &nextAttemptAt, &sentAt, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	n.ChannelName = channelName.String
+	n.TemplateName = templateName.String
+	n.Subject = subject.String
+	n.LastError = lastError.String
+	n.NextAttemptAt, _ = time.Parse(time.RFC3339, nextAttemptAt)
+	n.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	n.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	if sentAt.Valid && sentAt.String != "" {
+		ts, _ := time.Parse(time.RFC3339, sentAt.String)
+		n.SentAt = &ts
+	}
+	return n, nil
+}
+
+// notifyDeliveryWhere 构建查询条件
+func notifyDeliveryWhere(opts database.NotifyDeliveryQueryOpts) (string, []any) {
+	where := " WHERE 1=1"
+	var args []any
+	if opts.Status != "" {
+		where += " AND status = ?"
+		args = append(args, opts.Status)
+	}
+	if opts.ChannelID > 0 {
+		where += " AND channel_id = ?"
+		args = append(args, opts.ChannelID)
+	}
+	return where, args
+}
+
+// formatOptionalTime 可选时间（nil 存 NULL）
+func formatOptionalTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+var _ database.NotifyDeliveryDialect = (*notifyDeliveryDialect)(nil)
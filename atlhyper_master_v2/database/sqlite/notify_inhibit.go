@@ -0,0 +1,54 @@
+// atlhyper_master_v2/database/sqlite/notify_inhibit.go
+// SQLite NotifyInhibitRuleDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type notifyInhibitDialect struct{}
+
+const notifyInhibitColumns = "id, name, enabled, source_matchers, target_matchers, equal_labels, created_at, updated_at"
+
+func (d *notifyInhibitDialect) Insert(r *database.NotifyInhibitRule) (string, []any) {
+	now := time.Now().Format(time.RFC3339)
+	query := `INSERT INTO notify_inhibit_rules (name, enabled, source_matchers, target_matchers, equal_labels, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+	return query, []any{r.Name, r.Enabled, r.SourceMatchers, r.TargetMatchers, r.Equal, now, now}
+}
+
+func (d *notifyInhibitDialect) Update(r *database.NotifyInhibitRule) (string, []any) {
+	query := `UPDATE notify_inhibit_rules SET name = ?, enabled = ?, source_matchers = ?, target_matchers = ?, equal_labels = ?, updated_at = ?
+	WHERE id = ?`
+	return query, []any{r.Name, r.Enabled, r.SourceMatchers, r.TargetMatchers, r.Equal, time.Now().Format(time.RFC3339), r.ID}
+}
+
+func (d *notifyInhibitDialect) Delete(id int64) (string, []any) {
+	return "DELETE FROM notify_inhibit_rules WHERE id = ?", []any{id}
+}
+
+func (d *notifyInhibitDialect) SelectByID(id int64) (string, []any) {
+	return "SELECT " + notifyInhibitColumns + " FROM notify_inhibit_rules WHERE id = ?", []any{id}
+}
+
+func (d *notifyInhibitDialect) SelectAll() (string, []any) {
+	return "SELECT " + notifyInhibitColumns + " FROM notify_inhibit_rules ORDER BY id", nil
+}
+
+func (d *notifyInhibitDialect) ScanRow(rows *sql.Rows) (*database.NotifyInhibitRule, error) {
+	r := &database.NotifyInhibitRule{}
+	var enabled int
+	var createdAt, updatedAt string
+	if err := rows.Scan(&r.ID, &r.Name, &enabled, &r.SourceMatchers, &r.TargetMatchers, &r.Equal, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	r.Enabled = enabled == 1
+	r.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	r.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return r, nil
+}
+
+var _ database.NotifyInhibitRuleDialect = (*notifyInhibitDialect)(nil)
@@ -0,0 +1,57 @@
+// atlhyper_master_v2/database/sqlite/notify_route.go
+// SQLite NotifyRouteDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type notifyRouteDialect struct{}
+
+const notifyRouteColumns = "id, name, priority, enabled, matchers, channel_ids, group_by, group_wait_s, group_interval_s, continue_match, created_at, updated_at"
+
+func (d *notifyRouteDialect) Insert(r *database.NotifyRoute) (string, []any) {
+	now := time.Now().Format(time.RFC3339)
+	query := `INSERT INTO notify_routes (name, priority, enabled, matchers, channel_ids, group_by, group_wait_s, group_interval_s, continue_match, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	return query, []any{r.Name, r.Priority, r.Enabled, r.Matchers, r.ChannelIDs, r.GroupBy, r.GroupWaitS, r.GroupIntervalS, r.Continue, now, now}
+}
+
+func (d *notifyRouteDialect) Update(r *database.NotifyRoute) (string, []any) {
+	query := `UPDATE notify_routes SET name = ?, priority = ?, enabled = ?, matchers = ?, channel_ids = ?, group_by = ?,
+	group_wait_s = ?, group_interval_s = ?, continue_match = ?, updated_at = ? WHERE id = ?`
+	return query, []any{r.Name, r.Priority, r.Enabled, r.Matchers, r.ChannelIDs, r.GroupBy, r.GroupWaitS, r.GroupIntervalS, r.Continue,
+		time.Now().Format(time.RFC3339), r.ID}
+}
+
+func (d *notifyRouteDialect) Delete(id int64) (string, []any) {
+	return "DELETE FROM notify_routes WHERE id = ?", []any{id}
+}
+
+func (d *notifyRouteDialect) SelectByID(id int64) (string, []any) {
+	return "SELECT " + notifyRouteColumns + " FROM notify_routes WHERE id = ?", []any{id}
+}
+
+func (d *notifyRouteDialect) SelectAll() (string, []any) {
+	return "SELECT " + notifyRouteColumns + " FROM notify_routes ORDER BY priority, id", nil
+}
+
+func (d *notifyRouteDialect) ScanRow(rows *sql.Rows) (*database.NotifyRoute, error) {
+	r := &database.NotifyRoute{}
+	var enabled, cont int
+	var createdAt, updatedAt string
+	if err := rows.Scan(&r.ID, &r.Name, &r.Priority, &enabled, &r.Matchers, &r.ChannelIDs, &r.GroupBy,
+		&r.GroupWaitS, &r.GroupIntervalS, &cont, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	r.Enabled = enabled == 1
+	r.Continue = cont == 1
+	r.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	r.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return r, nil
+}
+
+var _ database.NotifyRouteDialect = (*notifyRouteDialect)(nil)
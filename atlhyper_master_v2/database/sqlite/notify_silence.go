@@ -0,0 +1,57 @@
+// atlhyper_master_v2/database/sqlite/notify_silence.go
+// SQLite NotifySilenceDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type notifySilenceDialect struct{}
+
+const notifySilenceColumns = "id, matchers, starts_at, ends_at, comment, created_by, created_at"
+
+func (d *notifySilenceDialect) Insert(s *database.NotifySilence) (string, []any) {
+	query := `INSERT INTO notify_silences (matchers, starts_at, ends_at, comment, created_by, created_at)
+	VALUES (?, ?, ?, ?, ?, ?)`
+	return query, []any{s.Matchers, s.StartsAt.UTC().Format(time.RFC3339), s.EndsAt.UTC().Format(time.RFC3339),
+		s.Comment, s.CreatedBy, time.Now().UTC().Format(time.RFC3339)}
+}
+
+func (d *notifySilenceDialect) Expire(id int64, at time.Time) (string, []any) {
+	return "UPDATE notify_silences SET ends_at = ? WHERE id = ? AND ends_at > ?",
+		[]any{at.UTC().Format(time.RFC3339), id, at.UTC().Format(time.RFC3339)}
+}
+
+func (d *notifySilenceDialect) SelectByID(id int64) (string, []any) {
+	return "SELECT " + notifySilenceColumns + " FROM notify_silences WHERE id = ?", []any{id}
+}
+
+func (d *notifySilenceDialect) SelectAll() (string, []any) {
+	return "SELECT " + notifySilenceColumns + " FROM notify_silences ORDER BY ends_at DESC", nil
+}
+
+func (d *notifySilenceDialect) SelectActive(now time.Time) (string, []any) {
+	ts := now.UTC().Format(time.RFC3339)
+	return "SELECT " + notifySilenceColumns + " FROM notify_silences WHERE starts_at <= ? AND ends_at > ? ORDER BY ends_at", []any{ts, ts}
+}
+
+func (d *notifySilenceDialect) DeleteExpiredBefore(before time.Time) (string, []any) {
+	return "DELETE FROM notify_silences WHERE ends_at < ?", []any{before.UTC().Format(time.RFC3339)}
+}
+
+func (d *notifySilenceDialect) ScanRow(rows *sql.Rows) (*database.NotifySilence, error) {
+	s := &database.NotifySilence{}
+	var startsAt, endsAt, createdAt string
+	if err := rows.Scan(&s.ID, &s.Matchers, &startsAt, &endsAt, &s.Comment, &s.CreatedBy, &createdAt); err != nil {
+		return nil, err
+	}
+	s.StartsAt, _ = time.Parse(time.RFC3339, startsAt)
+	s.EndsAt, _ = time.Parse(time.RFC3339, endsAt)
+	s.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return s, nil
+}
+
+var _ database.NotifySilenceDialect = (*notifySilenceDialect)(nil)
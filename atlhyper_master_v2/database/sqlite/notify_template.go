@@ -0,0 +1,48 @@
+// atlhyper_master_v2/database/sqlite/notify_template.go
+// SQLite NotifyTemplateDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type notifyTemplateDialect struct{}
+
+const notifyTemplateColumns = "id, name, variant, body, updated_by, created_at, updated_at"
+
+func (d *notifyTemplateDialect) Upsert(t *database.NotifyTemplate) (string, []any) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	query := `INSERT INTO notify_templates (name, variant, body, updated_by, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?)
+	ON CONFLICT(name, variant) DO UPDATE SET body = excluded.body, updated_by = excluded.updated_by,
+	updated_at = excluded.updated_at`
+	return query, []any{t.Name, t.Variant, t.Body, t.UpdatedBy, now, now}
+}
+
+func (d *notifyTemplateDialect) Delete(name, variant string) (string, []any) {
+	return "DELETE FROM notify_templates WHERE name = ? AND variant = ?", []any{name, variant}
+}
+
+func (d *notifyTemplateDialect) SelectOne(name, variant string) (string, []any) {
+	return "SELECT " + notifyTemplateColumns + " FROM notify_templates WHERE name = ? AND variant = ?", []any{name, variant}
+}
+
+func (d *notifyTemplateDialect) SelectAll() (string, []any) {
+	return "SELECT " + notifyTemplateColumns + " FROM notify_templates ORDER BY name, variant", nil
+}
+
+func (d *notifyTemplateDialect) ScanRow(rows *sql.Rows) (*database.NotifyTemplate, error) {
+	t := &database.NotifyTemplate{}
+	var createdAt, updatedAt string
+	if err := rows.Scan(&t.ID, &t.Name, &t.Variant, &t.Body, &t.UpdatedBy, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	t.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	t.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return t, nil
+}
+
+var _ database.NotifyTemplateDialect = (*notifyTemplateDialect)(nil)
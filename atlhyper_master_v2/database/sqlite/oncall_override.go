@@ -0,0 +1,60 @@
+// atlhyper_master_v2/database/sqlite/oncall_override.go
+// SQLite OncallOverrideDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type oncallOverrideDialect struct{}
+
+const oncallOverrideColumns = "id, schedule_id, user_id, starts_at, ends_at, comment, created_by, created_at"
+
+func (d *oncallOverrideDialect) Insert(o *database.OncallOverride) (string, []any) {
+	query := `INSERT INTO oncall_overrides (schedule_id, user_id, starts_at, ends_at, comment, created_by, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+	return query, []any{o.ScheduleID, o.UserID, o.StartsAt.UTC().Format(time.RFC3339), o.EndsAt.UTC().Format(time.RFC3339),
+		o.Comment, o.CreatedBy, time.Now().UTC().Format(time.RFC3339)}
+}
+
+func (d *oncallOverrideDialect) Delete(id int64) (string, []any) {
+	return "DELETE FROM oncall_overrides WHERE id = ?", []any{id}
+}
+
+func (d *oncallOverrideDialect) SelectByID(id int64) (string, []any) {
+	return "SELECT " + oncallOverrideColumns + " FROM oncall_overrides WHERE id = ?", []any{id}
+}
+
+func (d *oncallOverrideDialect) SelectBySchedule(scheduleID int64) (string, []any) {
+	return "SELECT " + oncallOverrideColumns + " FROM oncall_overrides WHERE schedule_id = ? ORDER BY starts_at", []any{scheduleID}
+}
+
+func (d *oncallOverrideDialect) SelectActive(scheduleID int64, at time.Time) (string, []any) {
+	ts := at.UTC().Format(time.RFC3339)
+	return "SELECT " + oncallOverrideColumns + ` FROM oncall_overrides
+	WHERE schedule_id = ? AND starts_at <= ? AND ends_at > ? ORDER BY starts_at DESC`, []any{scheduleID, ts, ts}
+}
+
+func (d *oncallOverrideDialect) DeleteEndedBefore(before time.Time) (string, []any) {
+	return "DELETE FROM oncall_overrides WHERE ends_at < ?", []any{before.UTC().Format(time.RFC3339)}
+}
+
+func (d *oncallOverrideDialect) ScanRow(rows *sql.Rows) (*database.OncallOverride, error) {
+	o := &database.OncallOverride{}
+	var comment, createdBy sql.NullString
+	var startsAt, endsAt, createdAt string
+	if err := rows.Scan(&o.ID, &o.ScheduleID, &o.UserID, &startsAt, &endsAt, &comment, &createdBy, &createdAt); err != nil {
+		return nil, err
+	}
+	o.Comment = comment.String
+	o.CreatedBy = createdBy.String
+	o.StartsAt, _ = time.Parse(time.RFC3339, startsAt)
+	o.EndsAt, _ = time.Parse(time.RFC3339, endsAt)
+	o.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return o, nil
+}
+
+var _ database.OncallOverrideDialect = (*oncallOverrideDialect)(nil)
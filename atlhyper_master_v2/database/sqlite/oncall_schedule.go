@@ -0,0 +1,56 @@
+// atlhyper_master_v2/database/sqlite/oncall_schedule.go
+// SQLite OncallScheduleDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type oncallScheduleDialect struct{}
+
+const oncallScheduleColumns = "id, name, description, rotation, start_at, members, created_at, updated_at"
+
+func (d *oncallScheduleDialect) Insert(s *database.OncallSchedule) (string, []any) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	query := `INSERT INTO oncall_schedules (name, description, rotation, start_at, members, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+	return query, []any{s.Name, s.Description, s.Rotation, s.StartAt.UTC().Format(time.RFC3339), s.Members, now, now}
+}
+
+func (d *oncallScheduleDialect) Update(s *database.OncallSchedule) (string, []any) {
+	query := `UPDATE oncall_schedules SET name = ?, description = ?, rotation = ?, start_at = ?, members = ?, updated_at = ?
+	WHERE id = ?`
+	return query, []any{s.Name, s.Description, s.Rotation, s.StartAt.UTC().Format(time.RFC3339), s.Members,
+		time.Now().UTC().Format(time.RFC3339), s.ID}
+}
+
+func (d *oncallScheduleDialect) Delete(id int64) (string, []any) {
+	return "DELETE FROM oncall_schedules WHERE id = ?", []any{id}
+}
+
+func (d *oncallScheduleDialect) SelectByID(id int64) (string, []any) {
+	return "SELECT " + oncallScheduleColumns + " FROM oncall_schedules WHERE id = ?", []any{id}
+}
+
+func (d *oncallScheduleDialect) SelectAll() (string, []any) {
+	return "SELECT " + oncallScheduleColumns + " FROM oncall_schedules ORDER BY id", nil
+}
+
+func (d *oncallScheduleDialect) ScanRow(rows *sql.Rows) (*database.OncallSchedule, error) {
+	s := &database.OncallSchedule{}
+	var description sql.NullString
+	var startAt, createdAt, updatedAt string
+	if err := rows.Scan(&s.ID, &s.Name, &description, &s.Rotation, &startAt, &s.Members, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	s.Description = description.String
+	s.StartAt, _ = time.Parse(time.RFC3339, startAt)
+	s.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	s.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return s, nil
+}
+
+var _ database.OncallScheduleDialect = (*oncallScheduleDialect)(nil)
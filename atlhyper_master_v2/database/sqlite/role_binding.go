@@ -0,0 +1,54 @@
+// atlhyper_master_v2/database/sqlite/role_binding.go
+// SQLite RoleBindingDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type roleBindingDialect struct{}
+
+const roleBindingColumns = "id, user_id, cluster_id, namespace, role, created_by, created_at"
+
+func (d *roleBindingDialect) Insert(b *database.RoleBinding) (string, []any) {
+	query := `INSERT INTO role_bindings (user_id, cluster_id, namespace, role, created_by, created_at)
+	VALUES (?, ?, ?, ?, ?, ?)`
+	return query, []any{b.UserID, b.ClusterID, b.Namespace, b.Role, b.CreatedBy, b.CreatedAt.Format(time.RFC3339)}
+}
+
+func (d *roleBindingDialect) Delete(id int64) (string, []any) {
+	return "DELETE FROM role_bindings WHERE id = ?", []any{id}
+}
+
+func (d *roleBindingDialect) DeleteByUser(userID int64) (string, []any) {
+	return "DELETE FROM role_bindings WHERE user_id = ?", []any{userID}
+}
+
+func (d *roleBindingDialect) SelectByID(id int64) (string, []any) {
+	return "SELECT " + roleBindingColumns + " FROM role_bindings WHERE id = ?", []any{id}
+}
+
+func (d *roleBindingDialect) SelectByUser(userID int64) (string, []any) {
+	return "SELECT " + roleBindingColumns + " FROM role_bindings WHERE user_id = ? ORDER BY cluster_id, namespace", []any{userID}
+}
+
+func (d *roleBindingDialect) SelectAll() (string, []any) {
+	return "SELECT " + roleBindingColumns + " FROM role_bindings ORDER BY user_id, cluster_id, namespace", nil
+}
+
+func (d *roleBindingDialect) ScanRow(rows *sql.Rows) (*database.RoleBinding, error) {
+	b := &database.RoleBinding{}
+	var createdBy sql.NullInt64
+	var createdAt string
+	if err := rows.Scan(&b.ID, &b.UserID, &b.ClusterID, &b.Namespace, &b.Role, &createdBy, &createdAt); err != nil {
+		return nil, err
+	}
+	b.CreatedBy = createdBy.Int64
+	b.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return b, nil
+}
+
+var _ database.RoleBindingDialect = (*roleBindingDialect)(nil)
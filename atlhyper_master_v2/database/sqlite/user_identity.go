@@ -0,0 +1,48 @@
+// atlhyper_master_v2/database/sqlite/user_identity.go
+// SQLite UserIdentityDialect 实现
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+type userIdentityDialect struct{}
+
+const userIdentityColumns = "id, user_id, issuer, subject, email, created_at, last_login"
+
+func (d *userIdentityDialect) Insert(i *database.UserIdentity) (string, []any) {
+	query := `INSERT INTO user_identities (user_id, issuer, subject, email, created_at, last_login)
+	VALUES (?, ?, ?, ?, ?, ?)`
+	return query, []any{i.UserID, i.Issuer, i.Subject, i.Email, i.CreatedAt.Format(time.RFC3339), i.LastLogin.Format(time.RFC3339)}
+}
+
+func (d *userIdentityDialect) Touch(id int64, email string) (string, []any) {
+	return "UPDATE user_identities SET email = ?, last_login = ? WHERE id = ?",
+		[]any{email, time.Now().Format(time.RFC3339), id}
+}
+
+func (d *userIdentityDialect) DeleteByUser(userID int64) (string, []any) {
+	return "DELETE FROM user_identities WHERE user_id = ?", []any{userID}
+}
+
+func (d *userIdentityDialect) SelectBySubject(issuer, subject string) (string, []any) {
+	return "SELECT " + userIdentityColumns + " FROM user_identities WHERE issuer = ? AND subject = ?", []any{issuer, subject}
+}
+
+func (d *userIdentityDialect) ScanRow(rows *sql.Rows) (*database.UserIdentity, error) {
+	i := &database.UserIdentity{}
+	var email sql.NullString
+	var createdAt, lastLogin string
+	if err := rows.Scan(&i.ID, &i.UserID, &i.Issuer, &i.Subject, &email, &createdAt, &lastLogin); err != nil {
+		return nil, err
+	}
+	i.Email = email.String
+	i.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	i.LastLogin, _ = time.Parse(time.RFC3339, lastLogin)
+	return i, nil
+}
+
+var _ database.UserIdentityDialect = (*userIdentityDialect)(nil)
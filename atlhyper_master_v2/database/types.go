@@ -105,7 +105,7 @@ type HourlyKindCount struct {
 // NotifyChannel 通知渠道
 type NotifyChannel struct {
 	ID        int64
-	Type      string // slack / email（UNIQUE，一个类型一条记录）
+	Type      string // slack / email（同一类型可配置多个渠道）
 	Name      string // 显示名称
 	Enabled   bool   // 是否启用（默认 false）
 	Config    string // JSON 配置
@@ -113,6 +113,184 @@ type NotifyChannel struct {
 	UpdatedAt time.Time
 }
 
+// NotifyRoute 告警路由规则
+// 按 Priority 升序匹配；命中后 Continue=false 则停止匹配后续路由
+// Matchers / ChannelIDs / GroupBy 均为 JSON 存储
+type NotifyRoute struct {
+	ID             int64
+	Name           string
+	Priority       int
+	Enabled        bool
+	Matchers       string // JSON: map[label]value，value 逗号分隔表示多选，空 map 匹配全部
+	ChannelIDs     string // JSON: []int64
+	GroupBy        string // JSON: []string，空 = 不分组立即发送
+	GroupWaitS     int    // 新分组首次发送前等待（秒）
+	GroupIntervalS int    // 分组后续批次最小间隔（秒）
+	Continue       bool   // 命中后是否继续匹配后续路由
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NotifySilence 告警静默（时间范围内匹配的告警不发送）
+type NotifySilence struct {
+	ID        int64
+	Matchers  string // JSON: map[label]value
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Comment   string
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// 通知投递状态
+const (
+	DeliveryPending = "pending" // 已创建，发送中（进程中断后由重试 Worker 接管）
+	DeliverySent    = "sent"    // 发送成功
+	DeliveryFailed  = "failed"  // 发送失败，等待重试
+	DeliveryDead    = "dead"    // 重试耗尽（死信），需手动重发
+)
+
+// NotifyDelivery 通知投递记录（每个渠道每次发送一条）
+type NotifyDelivery struct {
+	ID            int64
+	ChannelID     int64
+	ChannelType   string
+	ChannelName   string
+	TemplateName  string // 合并发送时为首条告警的模板
+	Subject       string
+	Payload       string // JSON: 渲染后的消息（重试时直接发送，不再渲染）
+	PayloadHash   string // sha256(Payload)
+	Status        string // pending / sent / failed / dead
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	SentAt        *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NotifyDeliveryQueryOpts 投递记录查询选项
+type NotifyDeliveryQueryOpts struct {
+	Status    string
+	ChannelID int64
+	Limit     int
+	Offset    int
+}
+
+// NotifyTemplate 自定义通知模板（覆盖同名同变体的内置模板）
+type NotifyTemplate struct {
+	ID        int64
+	Name      string // 模板名称：heartbeat_offline, k8s_event, ...
+	Variant   string // 模板变体：slack, email, markdown, html
+	Body      string // Go template 源码
+	UpdatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// 值班轮换周期
+const (
+	RotationDaily  = "daily"
+	RotationWeekly = "weekly"
+)
+
+// OncallSchedule 值班表
+// 从 StartAt 起每个周期（daily / weekly）按 Members 顺序交接一次，交接时刻与 StartAt 相同
+type OncallSchedule struct {
+	ID          int64
+	Name        string
+	Description string
+	Rotation    string // daily / weekly
+	StartAt     time.Time
+	Members     string // JSON: []int64 用户 ID（轮换顺序）
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// OncallOverride 值班替班（时间范围内由 UserID 代替当前主值班）
+type OncallOverride struct {
+	ID         int64
+	ScheduleID int64
+	UserID     int64
+	StartsAt   time.Time
+	EndsAt     time.Time
+	Comment    string
+	CreatedBy  string
+	CreatedAt  time.Time
+}
+
+// EscalationPolicy 升级策略
+// 匹配 Matchers 的告警按 Steps 依次通知值班人员，直到被确认或恢复
+type EscalationPolicy struct {
+	ID         int64
+	Name       string
+	Enabled    bool
+	Matchers   string // JSON: map[label]value
+	ScheduleID int64
+	Steps      string // JSON: []EscalationStep
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// 升级目标
+const (
+	EscalatePrimary   = "primary"   // 当前主值班
+	EscalateSecondary = "secondary" // 轮换中的下一位（副值班）
+	EscalateAll       = "all"       // 值班表全部成员
+)
+
+// EscalationStep 升级步骤（DelayMinutes 为告警触发后的分钟数）
+type EscalationStep struct {
+	DelayMinutes int    `json:"delayMinutes"`
+	Target       string `json:"target"` // primary / secondary / all
+}
+
+// 升级状态
+const (
+	EscalationOpen     = "open"     // 未确认，按步骤升级中
+	EscalationAcked    = "acked"    // 已确认，停止升级
+	EscalationResolved = "resolved" // 告警已恢复
+)
+
+// Escalation 告警升级记录（每个策略 × 告警一条）
+type Escalation struct {
+	ID           int64
+	PolicyID     int64
+	AlertKey     string
+	TemplateName string
+	Title        string
+	Severity     string
+	Data         string // JSON: 告警模板数据（升级通知时重新渲染）
+	Level        int    // 已执行的步骤数
+	Status       string // open / acked / resolved
+	AckToken     string // 确认链接令牌
+	AckedBy      string
+	AckedAt      *time.Time
+	NextAt       *time.Time // 下一步骤时间（为空表示无后续步骤）
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// EscalationQueryOpts 升级记录查询选项
+type EscalationQueryOpts struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+// NotifyInhibitRule 告警抑制规则
+// 存在匹配 SourceMatchers 的活跃告警，且 Equal 中的标签值相同时，抑制匹配 TargetMatchers 的告警
+type NotifyInhibitRule struct {
+	ID             int64
+	Name           string
+	Enabled        bool
+	SourceMatchers string // JSON: map[label]value
+	TargetMatchers string // JSON: map[label]value
+	Equal          string // JSON: []string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
 // SlackConfig Slack 配置
 type SlackConfig struct {
 	WebhookURL string `json:"webhookUrl"`
@@ -129,6 +307,37 @@ type EmailConfig struct {
 	ToAddresses  []string `json:"toAddresses"`
 }
 
+// WebhookConfig 通用 Webhook 配置
+type WebhookConfig struct {
+	URL        string            `json:"url"`
+	Secret     string            `json:"secret"`     // HMAC-SHA256 签名密钥（空 = 不签名）
+	Headers    map[string]string `json:"headers"`    // 附加请求头
+	MaxRetries *int              `json:"maxRetries"` // 失败重试次数（默认 3）
+}
+
+// TeamsConfig Microsoft Teams 配置
+type TeamsConfig struct {
+	WebhookURL string `json:"webhookUrl"`
+}
+
+// DingTalkConfig 钉钉机器人配置
+type DingTalkConfig struct {
+	WebhookURL string `json:"webhookUrl"`
+	Secret     string `json:"secret"` // 加签密钥（可选）
+}
+
+// FeishuConfig 飞书 / Lark 机器人配置
+type FeishuConfig struct {
+	WebhookURL string `json:"webhookUrl"`
+	Secret     string `json:"secret"` // 签名校验密钥（可选）
+}
+
+// PagerDutyConfig PagerDuty Events API v2 配置
+type PagerDutyConfig struct {
+	RoutingKey string `json:"routingKey"`
+	EventsURL  string `json:"eventsUrl"` // 默认 https://events.pagerduty.com/v2/enqueue
+}
+
 // Cluster 集群信息
 type Cluster struct {
 	ID          int64
@@ -140,6 +349,20 @@ type Cluster struct {
 	UpdatedAt   time.Time
 }
 
+// AgentToken 集群 Agent 凭证（与 Cluster 一一对应，按 ClusterUID 关联）
+// 只保存 Token 的 SHA-256 摘要；轮换后旧 Token 在宽限期内仍可使用
+type AgentToken struct {
+	ClusterID         string
+	TokenHash         string
+	TokenPrefix       string // 明文前若干位，仅用于界面识别
+	PreviousHash      string // 轮换前的 Token 摘要
+	PreviousExpiresAt *time.Time
+	CreatedBy         int64
+	CreatedAt         time.Time
+	RotatedAt         time.Time
+	LastUsedAt        *time.Time
+}
+
 // CommandHistory 指令历史
 type CommandHistory struct {
 	ID              int64
@@ -161,13 +384,67 @@ type CommandHistory struct {
 	DurationMs      int64
 }
 
+// ExecSession 交互式 exec 会话记录
+// Transcript 为 JSON 数组（按时间顺序的 stdin/stdout/stderr 片段），超过上限时截断
+type ExecSession struct {
+	ID         int64
+	SessionID  string
+	ClusterID  string
+	Namespace  string
+	Pod        string
+	Container  string
+	Command    string // JSON 数组
+	UserID     int64
+	Username   string
+	StartedAt  time.Time
+	EndedAt    time.Time
+	ExitCode   *int
+	EndReason  string // exit / client_closed / agent_disconnected / timeout / error
+	Transcript string
+	Truncated  bool
+}
+
+// RoleBinding 角色绑定：授予用户在指定集群/命名空间内的角色
+// ClusterID / Namespace 为空表示全部集群 / 全部命名空间（含集群级资源）
+type RoleBinding struct {
+	ID        int64
+	UserID    int64
+	ClusterID string
+	Namespace string
+	Role      int // 1=Viewer 2=Operator（Admin 为全局角色，不可按范围授予）
+	CreatedBy int64
+	CreatedAt time.Time
+}
+
+// UserIdentity 外部身份（OIDC）与本地用户的关联
+// Issuer + Subject 唯一标识一个 IdP 账号
+type UserIdentity struct {
+	ID        int64
+	UserID    int64
+	Issuer    string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+	LastLogin time.Time
+}
+
+// ExecSessionQueryOpts exec 会话查询选项
+type ExecSessionQueryOpts struct {
+	ClusterID string
+	UserID    int64
+	Limit     int
+	Offset    int
+}
+
 // CommandQueryOpts 命令查询选项
 type CommandQueryOpts struct {
-	ClusterID string // 集群 ID
-	Source    string // web / ai
-	Status    string // pending / running / success / failed / timeout
-	Action    string // restart / scale / delete_pod / cordon / uncordon
-	Search    string // 模糊搜索目标名称
+	ClusterID string    // 集群 ID
+	Source    string    // web / ai
+	Status    string    // pending / running / success / failed / timeout
+	Action    string    // restart / scale / delete_pod / cordon / uncordon
+	Search    string    // 模糊搜索目标名称
+	Since     time.Time // 创建时间下限（零值不限）
+	Until     time.Time // 创建时间上限（零值不限）
 	Limit     int
 	Offset    int
 }
@@ -371,24 +648,129 @@ type AIOpsBaselineState struct {
 	Variance   float64
 	Count      int64
 	UpdatedAt  int64
+	Mode       string // ema / seasonal
+	Seasonal   string // 季节性分桶 JSON（仅 seasonal 模式）
+
+	Detector      string // 检测算法
+	DetectorState string // 检测器私有状态 JSON
+}
+
+// AIOpsThresholdFeedback 误报反馈后的检测阈值（按实体模式 + 指标）
+type AIOpsThresholdFeedback struct {
+	EntityPattern  string  // 实体模式，如 "default/pod/api-*"
+	MetricName     string  // 指标名，"*" 表示该模式下全部指标
+	Threshold      float64 // 异常判定阈值（σ 倍数）
+	FalsePositives int     // 累计误报次数
+	UpdatedAt      int64
+}
+
+// AIOpsPostmortem 事件复盘文档（Markdown，生成后可人工编辑）
+type AIOpsPostmortem struct {
+	IncidentID   string
+	ClusterID    string
+	Content      string // Markdown 正文
+	Source       string // "ai"（叙述部分由 LLM 撰写）/ "template"（纯模板）
+	ProviderName string
+	Model        string
+	GeneratedBy  string // 生成人
+	UpdatedBy    string // 最后编辑人
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Runbook 执行状态
+const (
+	RunbookRunPending   = "pending_approval" // 等待人工审批
+	RunbookRunRunning   = "running"          // 执行中
+	RunbookRunSucceeded = "succeeded"        // 全部步骤成功
+	RunbookRunFailed    = "failed"           // 某一步骤失败（后续步骤不再执行）
+	RunbookRunRejected  = "rejected"         // 审批被拒绝
+)
+
+// AIOpsRunbookRun Runbook 执行记录（每次匹配事件或手动触发一条）
+type AIOpsRunbookRun struct {
+	ID          int64
+	Runbook     string // Runbook 名称
+	IncidentID  string
+	ClusterID   string
+	EntityKey   string // 匹配到的实体
+	Status      string // pending_approval / running / succeeded / failed / rejected
+	DryRun      bool   // 仅解析步骤，不下发指令
+	Trigger     string // auto / manual
+	Steps       string // JSON: 各步骤执行结果
+	Error       string
+	RequestedBy string // 手动触发人（自动触发为 "runbook"）
+	ApprovedBy  string // 审批人 / 拒绝人
+	CreatedAt   time.Time
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+}
+
+// RunbookRunQueryOpts Runbook 执行记录查询选项
+type RunbookRunQueryOpts struct {
+	IncidentID string
+	ClusterID  string
+	Status     string
+	Limit      int
+	Offset     int
+}
+
+// AIOpsPolicy AIOps 策略版本（每次修改插入新版本，同名最大版本为当前生效版本）
+type AIOpsPolicy struct {
+	ID         int64
+	Name       string
+	Version    int
+	ClusterID  string // 作用范围: 集群（空 = 全部）
+	Namespace  string // 作用范围: 命名空间，支持通配（空 = 全部）
+	EntityType string // 作用范围: 实体类型 service / pod / node / ingress / logs（空 = 全部）
+	Spec       string // JSON: 阈值与指标配置覆盖
+	Enabled    bool
+	Deleted    bool // 删除标记版本（保留历史，可回滚）
+	Author     string
+	Comment    string
+	CreatedAt  time.Time
+}
+
+// AIOpsMaintenanceWindow 维护窗口（窗口内不创建 AIOps 事件，匹配实体的告警不发送）
+type AIOpsMaintenanceWindow struct {
+	ID              int64
+	Name            string
+	ClusterID       string
+	Namespace       string // 作用范围: 命名空间，支持通配（空 = 整个集群）
+	Node            string // 作用范围: 节点（节点本身及其上的 Pod）
+	Workload        string // 作用范围: 工作负载名称（须指定命名空间）
+	StartsAt        time.Time
+	EndsAt          *time.Time // 一次性窗口的结束时间；周期窗口为重复截止时间（nil = 不截止）
+	Cron            string     // 周期窗口开始时间（5 字段 cron），空 = 一次性窗口
+	DurationMinutes int        // 周期窗口每次持续时长
+	Timezone        string     // cron 时区（空 = UTC）
+	Source          string     // manual / deployer
+	Reason          string
+	CreatedBy       string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 // ==================== AIOps Incident 模型定义 ====================
 
 // AIOpsIncident 事件数据库模型
 type AIOpsIncident struct {
-	ID         string
-	ClusterID  string
-	State      string
-	Severity   string
-	RootCause  string
-	PeakRisk   float64
-	StartedAt  time.Time
-	ResolvedAt *time.Time
-	DurationS  int64
-	Recurrence int
-	Summary    string
-	CreatedAt  time.Time
+	ID            string
+	ClusterID     string
+	State         string
+	Severity      string
+	RootCause     string
+	PeakRisk      float64
+	StartedAt     time.Time
+	ResolvedAt    *time.Time
+	DurationS     int64
+	Recurrence    int
+	Summary       string
+	CreatedAt     time.Time
+	AckedBy       string     // 确认人（空 = 未确认）
+	AckedAt       *time.Time // 确认时间
+	Assignee      string     // 指派处理人
+	FalsePositive bool       // 是否被标记为误报
 }
 
 // AIOpsIncidentEntity 受影响实体数据库模型
@@ -409,6 +791,7 @@ type AIOpsIncidentTimeline struct {
 	EventType  string
 	EntityKey  string
 	Detail     string
+	Actor      string // 人工操作的执行用户（引擎写入为空）
 }
 
 // AIOpsIncidentQueryOpts 事件查询选项
@@ -499,6 +882,8 @@ type DeployHistory struct {
 type DeployHistoryQueryOpts struct {
 	ClusterID string
 	Path      string
+	Since     time.Time // 部署时间下限（零值不限）
+	Until     time.Time // 部署时间上限（零值不限）
 	Limit     int
 	Offset    int
 }
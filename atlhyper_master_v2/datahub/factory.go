@@ -12,13 +12,17 @@ func NewStore(cfg Config) Store {
 	switch cfg.Type {
 	case "redis":
 		return redisStore.NewRedisStore(redisStore.Config{
-			Addr:            cfg.RedisAddr,
-			Password:        cfg.RedisPassword,
-			DB:              cfg.RedisDB,
-			EventRetention:  cfg.EventRetention,
-			HeartbeatExpire: cfg.HeartbeatExpire,
+			Addr:             cfg.RedisAddr,
+			Password:         cfg.RedisPassword,
+			DB:               cfg.RedisDB,
+			EventRetention:   cfg.EventRetention,
+			HeartbeatExpire:  cfg.HeartbeatExpire,
+			HistoryRetention: cfg.HistoryRetention,
+			HistoryInterval:  cfg.HistoryInterval,
 		})
 	default:
-		return memory.NewMemoryStore(cfg.EventRetention, cfg.HeartbeatExpire, cfg.SnapshotRetention)
+		store := memory.NewMemoryStore(cfg.EventRetention, cfg.HeartbeatExpire, cfg.SnapshotRetention)
+		store.SetHistory(cfg.HistoryRetention, cfg.HistoryInterval)
+		return store
 	}
 }
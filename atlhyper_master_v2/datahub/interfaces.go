@@ -21,6 +21,19 @@ type Store interface {
 	// GetSnapshot 获取集群快照
 	GetSnapshot(clusterID string) (*cluster.ClusterSnapshot, error)
 
+	// ApplySnapshotDelta 将 Agent 上报的增量应用到当前快照，返回应用后的快照
+	// 当前无快照或版本不连续时返回 cluster.ErrDeltaBaseMismatch（Agent 需改为全量推送）
+	ApplySnapshotDelta(clusterID string, delta *cluster.SnapshotDelta) (*cluster.ClusterSnapshot, error)
+
+	// ==================== 快照历史 ====================
+
+	// GetSnapshotAt 获取指定时间点的集群快照（该时间点之前最近的一份）
+	// 时间点不早于当前快照时返回当前快照；超出历史保留范围时返回 nil
+	GetSnapshotAt(clusterID string, at time.Time) (*cluster.ClusterSnapshot, error)
+
+	// ListSnapshotHistory 列出已保存的历史快照时间点（升序）
+	ListSnapshotHistory(clusterID string) ([]time.Time, error)
+
 	// ==================== Agent 状态 ====================
 
 	// UpdateHeartbeat 更新 Agent 心跳
@@ -57,6 +70,8 @@ type Config struct {
 	EventRetention    time.Duration // Event 保留时间
 	HeartbeatExpire   time.Duration // 心跳过期时间
 	SnapshotRetention time.Duration // OTel 快照时间线保留时间（默认 15min）
+	HistoryRetention  time.Duration // 快照历史保留时间（0 = 不保存历史）
+	HistoryInterval   time.Duration // 快照历史保存间隔
 
 	// Redis 配置（Type=redis 时使用）
 	RedisAddr     string
@@ -0,0 +1,186 @@
+// atlhyper_master_v2/datahub/memory/history.go
+// 快照历史（时间回溯）
+// 每个集群按固定间隔保存一份 Gzip 压缩的快照，保留可配置时长，用于按时间点查询集群状态
+package memory
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"AtlHyper/common"
+	"AtlHyper/model_v3/cluster"
+)
+
+// historyEntry 一份压缩快照
+type historyEntry struct {
+	at   time.Time
+	data []byte
+}
+
+// snapshotHistory 单集群的快照历史（按时间升序）
+type snapshotHistory struct {
+	mu      sync.Mutex
+	entries []historyEntry
+
+	// 最近一次解压结果（前端同一时间点的多个查询只解压一次）
+	cachedAt time.Time
+	cached   *cluster.ClusterSnapshot
+}
+
+// SetHistory 配置快照历史
+// retention 为 0 时不保存历史；interval 为两次保存的最小间隔
+func (s *MemoryStore) SetHistory(retention, interval time.Duration) {
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+	s.historyRetention = retention
+	s.historyInterval = interval
+}
+
+// recordHistory 按间隔保存压缩快照，并清理超出保留时长的历史
+func (s *MemoryStore) recordHistory(clusterID string, snapshot *cluster.ClusterSnapshot) {
+	s.historyMu.Lock()
+	retention, interval := s.historyRetention, s.historyInterval
+	if retention <= 0 {
+		s.historyMu.Unlock()
+		return
+	}
+	h, ok := s.history[clusterID]
+	if !ok {
+		h = &snapshotHistory{}
+		s.history[clusterID] = h
+	}
+	s.historyMu.Unlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if n := len(h.entries); n > 0 && snapshot.FetchedAt.Sub(h.entries[n-1].at) < interval {
+		return
+	}
+
+	data, err := encodeHistory(snapshot)
+	if err != nil {
+		log.Warn("快照历史压缩失败", "cluster", clusterID, "err", err)
+		return
+	}
+	h.entries = append(h.entries, historyEntry{at: snapshot.FetchedAt, data: data})
+	h.pruneBefore(snapshot.FetchedAt.Add(-retention))
+}
+
+// pruneHistory 清理所有集群超出保留时长的历史（定期调用）
+func (s *MemoryStore) pruneHistory() {
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	if s.historyRetention <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-s.historyRetention)
+	for clusterID, h := range s.history {
+		h.mu.Lock()
+		h.pruneBefore(cutoff)
+		empty := len(h.entries) == 0
+		h.mu.Unlock()
+		if empty {
+			delete(s.history, clusterID)
+		}
+	}
+}
+
+// pruneBefore 删除早于 cutoff 的历史（调用方持有 h.mu）
+func (h *snapshotHistory) pruneBefore(cutoff time.Time) {
+	i := sort.Search(len(h.entries), func(i int) bool {
+		return !h.entries[i].at.Before(cutoff)
+	})
+	if i > 0 {
+		h.entries = append([]historyEntry(nil), h.entries[i:]...)
+	}
+}
+
+// GetSnapshotAt 获取指定时间点的集群快照
+//
+// 返回该时间点之前最近的一份历史快照；时间点不早于当前快照时返回当前快照。
+// 早于最早历史时返回 nil。
+func (s *MemoryStore) GetSnapshotAt(clusterID string, at time.Time) (*cluster.ClusterSnapshot, error) {
+	current, _ := s.GetSnapshot(clusterID)
+	if current != nil && !at.Before(current.FetchedAt) {
+		return current, nil
+	}
+
+	s.historyMu.Lock()
+	h, ok := s.history[clusterID]
+	s.historyMu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	i := sort.Search(len(h.entries), func(i int) bool {
+		return h.entries[i].at.After(at)
+	}) - 1
+	if i < 0 {
+		return nil, nil
+	}
+
+	entry := h.entries[i]
+	if h.cached != nil && h.cachedAt.Equal(entry.at) {
+		return h.cached, nil
+	}
+	snapshot, err := decodeHistory(entry.data)
+	if err != nil {
+		return nil, err
+	}
+	h.cached, h.cachedAt = snapshot, entry.at
+	return snapshot, nil
+}
+
+// ListSnapshotHistory 列出集群已保存的历史快照时间点（升序）
+func (s *MemoryStore) ListSnapshotHistory(clusterID string) ([]time.Time, error) {
+	s.historyMu.Lock()
+	h, ok := s.history[clusterID]
+	s.historyMu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	times := make([]time.Time, len(h.entries))
+	for i, e := range h.entries {
+		times[i] = e.at
+	}
+	return times, nil
+}
+
+// encodeHistory 压缩快照（不含 OTel，OTel 有独立时间线）
+func encodeHistory(snapshot *cluster.ClusterSnapshot) ([]byte, error) {
+	copied := *snapshot
+	copied.OTel = nil
+	data, err := json.Marshal(&copied)
+	if err != nil {
+		return nil, err
+	}
+	return common.GzipBytes(data)
+}
+
+// decodeHistory 解压快照
+func decodeHistory(data []byte) (*cluster.ClusterSnapshot, error) {
+	reader, err := common.MaybeGunzipReaderAuto(io.NopCloser(bytes.NewReader(data)), "gzip")
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var snapshot cluster.ClusterSnapshot
+	if err := json.NewDecoder(reader).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
@@ -30,6 +30,12 @@ type MemoryStore struct {
 	agents   map[string]*agentmodel.AgentInfo
 	agentsMu sync.RWMutex
 
+	// 快照历史（时间回溯，见 history.go）
+	history          map[string]*snapshotHistory
+	historyMu        sync.Mutex
+	historyRetention time.Duration
+	historyInterval  time.Duration
+
 	// 配置
 	eventRetention    time.Duration
 	heartbeatExpire   time.Duration
@@ -50,6 +56,7 @@ func NewMemoryStore(eventRetention, heartbeatExpire, snapshotRetention time.Dura
 		snapshots:         make(map[string]*cluster.ClusterSnapshot),
 		otelTimeline:      make(map[string]*OTelRing),
 		agents:            make(map[string]*agentmodel.AgentInfo),
+		history:           make(map[string]*snapshotHistory),
 		eventRetention:    eventRetention,
 		heartbeatExpire:   heartbeatExpire,
 		snapshotRetention: snapshotRetention,
@@ -90,6 +97,7 @@ func (s *MemoryStore) cleanupLoop() {
 		case <-ticker.C:
 			s.updateAgentStatus()
 			s.cleanupOfflineClusterData()
+			s.pruneHistory()
 		}
 	}
 }
@@ -162,20 +170,57 @@ func (s *MemoryStore) SetSnapshot(clusterID string, snapshot *cluster.ClusterSna
 	}
 
 	// 同时更新 Agent 状态
+	s.touchAgentSnapshot(clusterID, snapshot.FetchedAt)
+
+	s.recordHistory(clusterID, snapshot)
+
+	return nil
+}
+
+// ApplySnapshotDelta 应用增量快照
+// 读取-应用-写回在同一把锁内完成；ApplyDelta 生成新快照，持有旧快照的读者不受影响
+func (s *MemoryStore) ApplySnapshotDelta(clusterID string, delta *cluster.SnapshotDelta) (*cluster.ClusterSnapshot, error) {
+	s.snapshotsMu.Lock()
+	current, ok := s.snapshots[clusterID]
+	if !ok {
+		s.snapshotsMu.Unlock()
+		return nil, cluster.ErrDeltaBaseMismatch
+	}
+	next, err := current.ApplyDelta(delta)
+	if err != nil {
+		s.snapshotsMu.Unlock()
+		return nil, err
+	}
+	s.snapshots[clusterID] = next
+	s.snapshotsMu.Unlock()
+
+	// 增量只在携带新 OTel 时追加时间线，避免重复记录旧数据
+	if delta.OTel != nil {
+		s.appendOTel(clusterID, delta.OTel, next.FetchedAt)
+	}
+
+	s.touchAgentSnapshot(clusterID, next.FetchedAt)
+
+	s.recordHistory(clusterID, next)
+
+	return next, nil
+}
+
+// touchAgentSnapshot 更新 Agent 最近快照时间（首次上报时注册 Agent）
+func (s *MemoryStore) touchAgentSnapshot(clusterID string, fetchedAt time.Time) {
 	s.agentsMu.Lock()
+	defer s.agentsMu.Unlock()
+
 	if agent, ok := s.agents[clusterID]; ok {
-		agent.LastSnapshot = snapshot.FetchedAt
+		agent.LastSnapshot = fetchedAt
 	} else {
 		s.agents[clusterID] = &agentmodel.AgentInfo{
 			ClusterID:     clusterID,
 			Status:        agentmodel.StatusOnline,
 			LastHeartbeat: time.Now(),
-			LastSnapshot:  snapshot.FetchedAt,
+			LastSnapshot:  fetchedAt,
 		}
 	}
-	s.agentsMu.Unlock()
-
-	return nil
 }
 
 // appendOTel 追加 OTel 快照到时间线
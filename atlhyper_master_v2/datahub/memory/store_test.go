@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -171,6 +172,259 @@ func TestSetSnapshot_UpdatesAgentInfo(t *testing.T) {
 	}
 }
 
+// ==================== 增量快照 ====================
+
+// helper: 创建指定 Pod 的快照
+func makeSnapshotWithPods(version uint64, pods ...cluster.Pod) *cluster.ClusterSnapshot {
+	snap := &cluster.ClusterSnapshot{
+		ClusterID: "cluster-a",
+		FetchedAt: time.Now(),
+		Version:   version,
+		Pods:      pods,
+	}
+	snap.Summary = snap.GenerateSummary()
+	return snap
+}
+
+func makePod(name, phase string) cluster.Pod {
+	return cluster.Pod{
+		Summary: cluster.PodSummary{Name: name, Namespace: "default"},
+		Status:  cluster.PodStatus{Phase: phase},
+	}
+}
+
+// helper: 按 Agent 的方式计算 prev → cur 的增量
+func makeDelta(t *testing.T, prev, cur *cluster.ClusterSnapshot) *cluster.SnapshotDelta {
+	t.Helper()
+	idx, err := cluster.IndexSnapshot(prev)
+	if err != nil {
+		t.Fatalf("IndexSnapshot error: %v", err)
+	}
+	changes, _, err := cluster.DiffSnapshot(idx, cur)
+	if err != nil {
+		t.Fatalf("DiffSnapshot error: %v", err)
+	}
+	return &cluster.SnapshotDelta{
+		ClusterID:   cur.ClusterID,
+		BaseVersion: prev.Version,
+		Version:     cur.Version,
+		FetchedAt:   cur.FetchedAt,
+		Changes:     changes,
+		OTel:        cur.OTel,
+	}
+}
+
+func TestApplySnapshotDelta_AddUpdateDelete(t *testing.T) {
+	store := newTestStore()
+	v1 := makeSnapshotWithPods(1, makePod("a", "Running"), makePod("b", "Pending"))
+	store.SetSnapshot("cluster-a", v1)
+
+	v2 := makeSnapshotWithPods(2, makePod("b", "Running"), makePod("c", "Pending"))
+	delta := makeDelta(t, v1, v2)
+	if len(delta.Changes) != 3 {
+		t.Fatalf("changes = %d, want 3 (update b, add c, delete a): %+v", len(delta.Changes), delta.Changes)
+	}
+
+	got, err := store.ApplySnapshotDelta("cluster-a", delta)
+	if err != nil {
+		t.Fatalf("ApplySnapshotDelta error: %v", err)
+	}
+	if got.Version != 2 {
+		t.Errorf("Version = %d, want 2", got.Version)
+	}
+	phases := map[string]string{}
+	for _, p := range got.Pods {
+		phases[p.Summary.Name] = p.Status.Phase
+	}
+	if len(phases) != 2 || phases["b"] != "Running" || phases["c"] != "Pending" {
+		t.Errorf("unexpected pods after delta: %v", phases)
+	}
+	if got.Summary.TotalPods != 2 || got.Summary.RunningPods != 1 {
+		t.Errorf("summary not regenerated: %+v", got.Summary)
+	}
+
+	// 旧快照不被修改（读者可能仍持有）
+	if len(v1.Pods) != 2 || v1.Pods[1].Status.Phase != "Pending" {
+		t.Errorf("base snapshot mutated: %+v", v1.Pods)
+	}
+
+	stored, _ := store.GetSnapshot("cluster-a")
+	if stored != got {
+		t.Error("GetSnapshot should return the applied snapshot")
+	}
+}
+
+func TestApplySnapshotDelta_BaseMismatch(t *testing.T) {
+	store := newTestStore()
+
+	// 无当前快照（Master 重启）
+	delta := &cluster.SnapshotDelta{BaseVersion: 1, Version: 2}
+	if _, err := store.ApplySnapshotDelta("cluster-a", delta); !errors.Is(err, cluster.ErrDeltaBaseMismatch) {
+		t.Fatalf("err = %v, want ErrDeltaBaseMismatch", err)
+	}
+
+	// 版本不连续（上次推送丢失）
+	store.SetSnapshot("cluster-a", makeSnapshotWithPods(3, makePod("a", "Running")))
+	if _, err := store.ApplySnapshotDelta("cluster-a", delta); !errors.Is(err, cluster.ErrDeltaBaseMismatch) {
+		t.Fatalf("err = %v, want ErrDeltaBaseMismatch", err)
+	}
+	stored, _ := store.GetSnapshot("cluster-a")
+	if stored.Version != 3 {
+		t.Errorf("snapshot should be unchanged, version = %d", stored.Version)
+	}
+}
+
+func TestApplySnapshotDelta_OTelTimeline(t *testing.T) {
+	store := newTestStore()
+	base := time.Now().Add(-time.Minute)
+	v1 := makeSnapshotWithOTel("cluster-a", base, 3)
+	v1.Version = 1
+	store.SetSnapshot("cluster-a", v1)
+
+	// 不携带 OTel 的增量保留原 OTel，且不追加时间线
+	got, err := store.ApplySnapshotDelta("cluster-a", &cluster.SnapshotDelta{BaseVersion: 1, Version: 2, FetchedAt: base.Add(10 * time.Second)})
+	if err != nil {
+		t.Fatalf("ApplySnapshotDelta error: %v", err)
+	}
+	if got.OTel == nil || got.OTel.TotalServices != 3 {
+		t.Errorf("OTel should be carried over, got %+v", got.OTel)
+	}
+
+	// 携带 OTel 的增量替换并追加时间线
+	_, err = store.ApplySnapshotDelta("cluster-a", &cluster.SnapshotDelta{
+		BaseVersion: 2, Version: 3, FetchedAt: base.Add(20 * time.Second),
+		OTel: &cluster.OTelSnapshot{TotalServices: 5},
+	})
+	if err != nil {
+		t.Fatalf("ApplySnapshotDelta error: %v", err)
+	}
+	timeline, _ := store.GetOTelTimeline("cluster-a", base.Add(-time.Second))
+	if len(timeline) != 2 {
+		t.Fatalf("timeline entries = %d, want 2", len(timeline))
+	}
+	if timeline[1].Snapshot.TotalServices != 5 {
+		t.Errorf("latest timeline entry = %+v", timeline[1].Snapshot)
+	}
+}
+
+// ==================== 快照历史 ====================
+
+// helper: 在 base 之后 offset 采集的快照
+func makeHistorySnapshot(base time.Time, offset time.Duration, pods ...cluster.Pod) *cluster.ClusterSnapshot {
+	snap := makeSnapshotWithPods(0, pods...)
+	snap.FetchedAt = base.Add(offset)
+	snap.OTel = &cluster.OTelSnapshot{TotalServices: 1}
+	return snap
+}
+
+func TestSnapshotHistory_GetSnapshotAt(t *testing.T) {
+	s := newTestStore()
+	s.SetHistory(time.Hour, time.Minute)
+	base := time.Now().Add(-10 * time.Minute)
+
+	s.SetSnapshot("cluster-a", makeHistorySnapshot(base, 0, makePod("a", "Running")))
+	s.SetSnapshot("cluster-a", makeHistorySnapshot(base, 30*time.Second, makePod("a", "Failed"))) // 间隔不足，不记录
+	s.SetSnapshot("cluster-a", makeHistorySnapshot(base, 2*time.Minute, makePod("b", "Running")))
+	s.SetSnapshot("cluster-a", makeHistorySnapshot(base, 3*time.Minute, makePod("c", "Running")))
+
+	times, _ := s.ListSnapshotHistory("cluster-a")
+	if len(times) != 3 {
+		t.Fatalf("expected 3 history entries, got %d", len(times))
+	}
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		wantPod string
+	}{
+		{"exact first entry", base, "a"},
+		{"between entries uses previous", base.Add(90 * time.Second), "a"},
+		{"second entry", base.Add(2*time.Minute + time.Second), "b"},
+		{"after current returns current", time.Now(), "c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.GetSnapshotAt("cluster-a", tt.at)
+			if err != nil || got == nil {
+				t.Fatalf("GetSnapshotAt: snapshot=%v err=%v", got, err)
+			}
+			if len(got.Pods) != 1 || got.Pods[0].Summary.Name != tt.wantPod {
+				t.Errorf("expected pod %q, got %+v", tt.wantPod, got.Pods)
+			}
+		})
+	}
+
+	// 历史快照不保存 OTel
+	got, _ := s.GetSnapshotAt("cluster-a", base)
+	if got.OTel != nil {
+		t.Error("history snapshot should not carry OTel")
+	}
+
+	// 早于最早历史
+	got, err := s.GetSnapshotAt("cluster-a", base.Add(-time.Minute))
+	if err != nil || got != nil {
+		t.Errorf("expected nil before earliest history, got %v (err=%v)", got, err)
+	}
+}
+
+func TestSnapshotHistory_Retention(t *testing.T) {
+	s := newTestStore()
+	s.SetHistory(5*time.Minute, time.Minute)
+	base := time.Now().Add(-20 * time.Minute)
+
+	s.SetSnapshot("cluster-a", makeHistorySnapshot(base, 0, makePod("old", "Running")))
+	s.SetSnapshot("cluster-a", makeHistorySnapshot(base, 10*time.Minute, makePod("mid", "Running")))
+
+	// 写入时按最新快照时间裁剪
+	times, _ := s.ListSnapshotHistory("cluster-a")
+	if len(times) != 1 || !times[0].Equal(base.Add(10*time.Minute)) {
+		t.Fatalf("expected only the recent entry, got %v", times)
+	}
+
+	// 定期清理按当前时间裁剪
+	s.pruneHistory()
+	if times, _ := s.ListSnapshotHistory("cluster-a"); len(times) != 0 {
+		t.Errorf("expected history pruned, got %v", times)
+	}
+}
+
+func TestSnapshotHistory_Disabled(t *testing.T) {
+	s := newTestStore()
+	base := time.Now().Add(-10 * time.Minute)
+
+	s.SetSnapshot("cluster-a", makeHistorySnapshot(base, 0, makePod("a", "Running")))
+	s.SetSnapshot("cluster-a", makeHistorySnapshot(base, 5*time.Minute, makePod("b", "Running")))
+
+	if times, _ := s.ListSnapshotHistory("cluster-a"); len(times) != 0 {
+		t.Errorf("history should be disabled by default, got %v", times)
+	}
+	if got, _ := s.GetSnapshotAt("cluster-a", base); got != nil {
+		t.Errorf("expected nil without history, got %v", got)
+	}
+}
+
+func TestSnapshotHistory_RecordedFromDelta(t *testing.T) {
+	s := newTestStore()
+	s.SetHistory(time.Hour, time.Minute)
+
+	prev := makeSnapshotWithPods(1, makePod("a", "Running"))
+	prev.FetchedAt = time.Now().Add(-5 * time.Minute)
+	s.SetSnapshot("cluster-a", prev)
+
+	cur := makeSnapshotWithPods(2, makePod("a", "Running"), makePod("b", "Running"))
+	if _, err := s.ApplySnapshotDelta("cluster-a", makeDelta(t, prev, cur)); err != nil {
+		t.Fatalf("ApplySnapshotDelta: %v", err)
+	}
+
+	if times, _ := s.ListSnapshotHistory("cluster-a"); len(times) != 2 {
+		t.Errorf("expected 2 history entries, got %d", len(times))
+	}
+	got, _ := s.GetSnapshotAt("cluster-a", prev.FetchedAt)
+	if got == nil || len(got.Pods) != 1 {
+		t.Errorf("expected snapshot before delta, got %+v", got)
+	}
+}
+
 // ==================== Agent 状态 ====================
 
 func TestUpdateHeartbeat_NewAgent(t *testing.T) {
@@ -0,0 +1,152 @@
+// atlhyper_master_v2/datahub/redis/history.go
+// 快照历史（时间回溯）
+// ZSET 记录时间点索引，每份快照单独一个 Gzip 压缩 Key 并设置 TTL
+package redis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"AtlHyper/common"
+	"AtlHyper/model_v3/cluster"
+)
+
+// historyIndexKey 集群历史索引 Key
+func historyIndexKey(clusterID string) string {
+	return keyHistory + clusterID
+}
+
+// historyDataKey 单份历史快照 Key
+func historyDataKey(clusterID string, ms int64) string {
+	return keyHistory + clusterID + ":" + strconv.FormatInt(ms, 10)
+}
+
+// recordHistory 按间隔保存压缩快照，并清理超出保留时长的索引
+func (s *RedisStore) recordHistory(ctx context.Context, clusterID string, snapshot *cluster.ClusterSnapshot) {
+	if s.historyRetention <= 0 {
+		return
+	}
+
+	s.lastHistoryMu.Lock()
+	last, ok := s.lastHistory[clusterID]
+	if ok && snapshot.FetchedAt.Sub(last) < s.historyInterval {
+		s.lastHistoryMu.Unlock()
+		return
+	}
+	s.lastHistory[clusterID] = snapshot.FetchedAt
+	s.lastHistoryMu.Unlock()
+
+	data, err := encodeHistory(snapshot)
+	if err != nil {
+		log.Warn("快照历史压缩失败", "clusterID", clusterID, "err", err)
+		return
+	}
+
+	ms := snapshot.FetchedAt.UnixMilli()
+	cutoff := snapshot.FetchedAt.Add(-s.historyRetention).UnixMilli()
+	pipe := s.client.Pipeline()
+	pipe.Set(ctx, historyDataKey(clusterID, ms), data, s.historyRetention)
+	pipe.ZAdd(ctx, historyIndexKey(clusterID), redis.Z{Score: float64(ms), Member: ms})
+	pipe.ZRemRangeByScore(ctx, historyIndexKey(clusterID), "-inf", "("+strconv.FormatInt(cutoff, 10))
+	pipe.Expire(ctx, historyIndexKey(clusterID), s.historyRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Warn("快照历史保存失败", "clusterID", clusterID, "err", err)
+	}
+}
+
+// GetSnapshotAt 获取指定时间点的集群快照
+func (s *RedisStore) GetSnapshotAt(clusterID string, at time.Time) (*cluster.ClusterSnapshot, error) {
+	current, err := s.GetSnapshot(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && !at.Before(current.FetchedAt) {
+		return current, nil
+	}
+
+	ctx := context.Background()
+	members, err := s.client.ZRevRangeByScore(ctx, historyIndexKey(clusterID), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(at.UnixMilli(), 10),
+		Count: 1,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get snapshot history: %w", err)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	ms, err := strconv.ParseInt(members[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid history member %q: %w", members[0], err)
+	}
+	data, err := s.client.Get(ctx, historyDataKey(clusterID, ms)).Bytes()
+	if err == redis.Nil {
+		// 数据 Key 已过期，索引尚未清理
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get snapshot history: %w", err)
+	}
+	return decodeHistory(data)
+}
+
+// ListSnapshotHistory 列出已保存的历史快照时间点（升序）
+func (s *RedisStore) ListSnapshotHistory(clusterID string) ([]time.Time, error) {
+	ctx := context.Background()
+	min := "-inf"
+	if s.historyRetention > 0 {
+		min = strconv.FormatInt(time.Now().Add(-s.historyRetention).UnixMilli(), 10)
+	}
+	members, err := s.client.ZRangeByScore(ctx, historyIndexKey(clusterID), &redis.ZRangeBy{
+		Min: min,
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("list snapshot history: %w", err)
+	}
+
+	times := make([]time.Time, 0, len(members))
+	for _, m := range members {
+		ms, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			continue
+		}
+		times = append(times, time.UnixMilli(ms))
+	}
+	return times, nil
+}
+
+// encodeHistory 压缩快照（不含 OTel，OTel 有独立时间线）
+func encodeHistory(snapshot *cluster.ClusterSnapshot) ([]byte, error) {
+	copied := *snapshot
+	copied.OTel = nil
+	data, err := json.Marshal(&copied)
+	if err != nil {
+		return nil, err
+	}
+	return common.GzipBytes(data)
+}
+
+// decodeHistory 解压快照
+func decodeHistory(data []byte) (*cluster.ClusterSnapshot, error) {
+	reader, err := common.MaybeGunzipReaderAuto(io.NopCloser(bytes.NewReader(data)), "gzip")
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var snapshot cluster.ClusterSnapshot
+	if err := json.NewDecoder(reader).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("unmarshal snapshot history: %w", err)
+	}
+	return &snapshot, nil
+}
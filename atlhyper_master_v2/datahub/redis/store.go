@@ -23,6 +23,7 @@ const (
 	keySnapshot = "datahub:snapshot:" // + clusterID -> JSON
 	keyAgents   = "datahub:agents"    // SET of clusterIDs
 	keyAgent    = "datahub:agent:"    // + clusterID -> JSON (AgentInfo)
+	keyHistory  = "datahub:history:"  // + clusterID -> ZSET(score=unix ms)；+ clusterID:ms -> Gzip JSON
 )
 
 // Config RedisStore 配置
@@ -32,6 +33,9 @@ type Config struct {
 	DB              int
 	EventRetention  time.Duration
 	HeartbeatExpire time.Duration
+
+	HistoryRetention time.Duration // 快照历史保留时间（0 = 不保存）
+	HistoryInterval  time.Duration // 快照历史保存间隔
 }
 
 // RedisStore Redis 数据存储
@@ -40,6 +44,12 @@ type RedisStore struct {
 	eventRetention  time.Duration
 	heartbeatExpire time.Duration
 
+	// 快照历史（见 history.go）
+	historyRetention time.Duration
+	historyInterval  time.Duration
+	lastHistory      map[string]time.Time // clusterID -> 最近一次保存时间
+	lastHistoryMu    sync.Mutex
+
 	stopCh chan struct{}
 	wg     sync.WaitGroup
 }
@@ -52,10 +62,13 @@ func NewRedisStore(cfg Config) *RedisStore {
 		DB:       cfg.DB,
 	})
 	return &RedisStore{
-		client:          client,
-		eventRetention:  cfg.EventRetention,
-		heartbeatExpire: cfg.HeartbeatExpire,
-		stopCh:          make(chan struct{}),
+		client:           client,
+		eventRetention:   cfg.EventRetention,
+		heartbeatExpire:  cfg.HeartbeatExpire,
+		historyRetention: cfg.HistoryRetention,
+		historyInterval:  cfg.HistoryInterval,
+		lastHistory:      make(map[string]time.Time),
+		stopCh:           make(chan struct{}),
 	}
 }
 
@@ -211,9 +224,31 @@ func (s *RedisStore) SetSnapshot(clusterID string, snapshot *cluster.ClusterSnap
 		}
 	}
 
+	s.recordHistory(ctx, clusterID, snapshot)
+
 	return nil
 }
 
+// ApplySnapshotDelta 应用增量快照
+// 读取-应用-写回非原子操作；同一集群的快照由单个 Agent 串行推送，不存在并发写
+func (s *RedisStore) ApplySnapshotDelta(clusterID string, delta *cluster.SnapshotDelta) (*cluster.ClusterSnapshot, error) {
+	current, err := s.GetSnapshot(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, cluster.ErrDeltaBaseMismatch
+	}
+	next, err := current.ApplyDelta(delta)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SetSnapshot(clusterID, next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
 // GetSnapshot 获取集群快照
 func (s *RedisStore) GetSnapshot(clusterID string) (*cluster.ClusterSnapshot, error) {
 	ctx := context.Background()
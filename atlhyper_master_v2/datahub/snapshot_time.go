@@ -0,0 +1,22 @@
+// atlhyper_master_v2/datahub/snapshot_time.go
+// 查询时间点上下文
+// Gateway 解析 ?at= 后写入 context，Query 层据此读取历史快照（时间回溯）
+package datahub
+
+import (
+	"context"
+	"time"
+)
+
+type snapshotTimeKey struct{}
+
+// WithSnapshotTime 在 context 中设置查询时间点
+func WithSnapshotTime(ctx context.Context, at time.Time) context.Context {
+	return context.WithValue(ctx, snapshotTimeKey{}, at)
+}
+
+// SnapshotTimeFrom 读取 context 中的查询时间点，未设置时返回 false
+func SnapshotTimeFrom(ctx context.Context) (time.Time, bool) {
+	at, ok := ctx.Value(snapshotTimeKey{}).(time.Time)
+	return at, ok
+}
@@ -2,7 +2,12 @@
 // Deployer 模块接口定义
 package deployer
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+)
 
 // Deployer manages the CD lifecycle: polling, rendering, deploying
 type Deployer interface {
@@ -16,3 +21,9 @@ type Deployer interface {
 	// GetPathStatus returns sync status for all configured paths
 	GetPathStatus(ctx context.Context) ([]PathStatus, error)
 }
+
+// MaintenanceWindows 部署期间的维护窗口（由 aiops/maintenance.Manager 实现）
+type MaintenanceWindows interface {
+	Create(ctx context.Context, w *maintenance.Window, by string) (*maintenance.Window, error)
+	Close(ctx context.Context, id int64, at time.Time) error
+}
@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/github"
 	"AtlHyper/atlhyper_master_v2/mq"
@@ -24,6 +25,7 @@ type service struct {
 	ghClient github.Client
 	db       *database.DB
 	bus      mq.Producer
+	windows  MaintenanceWindows // 可选，nil = 部署期间不创建维护窗口
 
 	mu            sync.Mutex
 	cancel        context.CancelFunc
@@ -31,12 +33,21 @@ type service struct {
 	deploying     map[string]bool // 正在部署的路径，防重复
 }
 
+// 部署维护窗口: 窗口随部署开始，部署结束后再保留 deploySettle 覆盖滚动更新；
+// deployWindowMax 为窗口上限，防止 Master 异常退出时窗口无法关闭
+const (
+	deploySettle    = 10 * time.Minute
+	deployWindowMax = 30 * time.Minute
+)
+
 // NewService 创建 Deployer 服务
-func NewService(ghClient github.Client, db *database.DB, bus mq.Producer) Deployer {
+// windows 可为 nil（不为部署创建维护窗口）
+func NewService(ghClient github.Client, db *database.DB, bus mq.Producer, windows MaintenanceWindows) Deployer {
 	return &service{
 		ghClient:  ghClient,
 		db:        db,
 		bus:       bus,
+		windows:   windows,
 		deploying: make(map[string]bool),
 	}
 }
@@ -233,6 +244,11 @@ func (s *service) deployPath(ctx context.Context, config *database.DeployConfig,
 	// 从 manifests 解析源码仓库信息（image tag → source SHA → GitHub API）
 	s.enrichSourceInfo(ctx, record, manifests, config.RepoURL, compareResult)
 
+	// 部署期间开启维护窗口，抑制滚动更新产生的事故与告警
+	if closeWindow := s.openWindow(ctx, record); closeWindow != nil {
+		defer closeWindow()
+	}
+
 	// 通过 MQ 发送 apply_manifests 指令
 	cmd := &command.Command{
 		ID:        fmt.Sprintf("deploy-%s-%d", path, time.Now().UnixMilli()),
@@ -246,6 +262,21 @@ func (s *service) deployPath(ctx context.Context, config *database.DeployConfig,
 		CreatedAt: time.Now(),
 	}
 
+	// 记录指令历史（Agent 上报结果时据此校验指令归属集群）
+	if err := s.db.Command.Create(ctx, &database.CommandHistory{
+		CommandID:  cmd.ID,
+		ClusterID:  clusterID,
+		Source:     cmd.Source,
+		Action:     cmd.Action,
+		TargetKind: "Kustomization",
+		TargetName: path,
+		Params:     "{}",
+		Status:     command.StatusPending,
+		CreatedAt:  cmd.CreatedAt,
+	}); err != nil {
+		logger.Warn("[Deployer] persist command history failed", "path", path, "error", err)
+	}
+
 	if err := s.bus.EnqueueCommand(clusterID, mq.TopicOps, cmd); err != nil {
 		record.Status = "failed"
 		record.ErrorMessage = fmt.Sprintf("enqueue command failed: %v", err)
@@ -285,6 +316,34 @@ func (s *service) deployPath(ctx context.Context, config *database.DeployConfig,
 	logger.Info("[Deployer] deployed", "path", path, "status", record.Status, "durationMs", record.DurationMs)
 }
 
+// openWindow 为部署创建维护窗口（命名空间未知时作用于整个集群），返回关闭函数；
+// 未启用或创建失败返回 nil（不影响部署）
+func (s *service) openWindow(ctx context.Context, record *database.DeployHistory) func() {
+	if s.windows == nil {
+		return nil
+	}
+	now := time.Now()
+	endsAt := now.Add(deployWindowMax)
+	w, err := s.windows.Create(ctx, &maintenance.Window{
+		Name:      "deploy " + record.Path,
+		ClusterID: record.ClusterID,
+		Namespace: record.Namespace,
+		StartsAt:  now,
+		EndsAt:    &endsAt,
+		Source:    maintenance.SourceDeployer,
+		Reason:    fmt.Sprintf("deploy %s@%s (%s)", record.Path, record.CommitSHA[:min(8, len(record.CommitSHA))], record.Trigger),
+	}, "deployer")
+	if err != nil {
+		logger.Warn("[Deployer] create maintenance window failed", "path", record.Path, "error", err)
+		return nil
+	}
+	return func() {
+		if err := s.windows.Close(context.Background(), w.ID, time.Now().Add(deploySettle)); err != nil {
+			logger.Warn("[Deployer] close maintenance window failed", "path", record.Path, "id", w.ID, "error", err)
+		}
+	}
+}
+
 func (s *service) SyncNow(ctx context.Context, path string) error {
 	// 防重复：检查该路径是否正在部署
 	s.mu.Lock()
@@ -0,0 +1,133 @@
+// atlhyper_master_v2/gateway/handler/admin/agent_token.go
+// Agent 凭证管理 API Handler
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/service"
+)
+
+// AgentTokenHandler Agent Token Handler
+type AgentTokenHandler struct {
+	svc service.Service
+}
+
+// NewAgentTokenHandler 创建 AgentTokenHandler
+func NewAgentTokenHandler(svc service.Service) *AgentTokenHandler {
+	return &AgentTokenHandler{svc: svc}
+}
+
+// AgentTokenResponse Token 摘要响应（不含哈希）
+type AgentTokenResponse struct {
+	ClusterID         string     `json:"clusterId"`
+	TokenPrefix       string     `json:"tokenPrefix"`
+	CreatedBy         int64      `json:"createdBy"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	RotatedAt         time.Time  `json:"rotatedAt"`
+	LastUsedAt        *time.Time `json:"lastUsedAt,omitempty"`
+	PreviousExpiresAt *time.Time `json:"previousExpiresAt,omitempty"`
+}
+
+// IssueAgentTokenResponse 签发响应（明文 Token 仅返回一次）
+type IssueAgentTokenResponse struct {
+	AgentTokenResponse
+	Token string `json:"token"`
+}
+
+// List 列出所有集群的 Token 摘要
+// GET /api/v2/agent-tokens
+func (h *AgentTokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	tokens, err := h.svc.ListAgentTokens(ctx)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to list agent tokens")
+		return
+	}
+
+	responses := make([]AgentTokenResponse, 0, len(tokens))
+	for _, t := range tokens {
+		responses = append(responses, toAgentTokenResponse(t))
+	}
+
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"tokens": responses,
+		"total":  len(responses),
+	})
+}
+
+// TokenHandler 单集群 Token 操作
+// GET    /api/v2/agent-tokens/{clusterID}  -> 摘要
+// POST   /api/v2/agent-tokens/{clusterID}  -> 签发/轮换
+// DELETE /api/v2/agent-tokens/{clusterID}  -> 吊销
+func (h *AgentTokenHandler) TokenHandler(w http.ResponseWriter, r *http.Request) {
+	clusterID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v2/agent-tokens/"), "/")
+	if clusterID == "" || strings.Contains(clusterID, "/") {
+		handler.WriteError(w, http.StatusBadRequest, "cluster id required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		token, err := h.svc.GetAgentToken(ctx, clusterID)
+		if err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to get agent token")
+			return
+		}
+		if token == nil {
+			handler.WriteError(w, http.StatusNotFound, "agent token not found")
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, toAgentTokenResponse(token))
+
+	case http.MethodPost:
+		userID, _ := middleware.GetUserID(r.Context())
+		plain, token, err := h.svc.IssueAgentToken(ctx, clusterID, userID)
+		if err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to issue agent token: "+err.Error())
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, IssueAgentTokenResponse{
+			AgentTokenResponse: toAgentTokenResponse(token),
+			Token:              plain,
+		})
+
+	case http.MethodDelete:
+		if err := h.svc.RevokeAgentToken(ctx, clusterID); err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to revoke agent token")
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]string{"message": "revoked"})
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func toAgentTokenResponse(t *database.AgentToken) AgentTokenResponse {
+	return AgentTokenResponse{
+		ClusterID:         t.ClusterID,
+		TokenPrefix:       t.TokenPrefix,
+		CreatedBy:         t.CreatedBy,
+		CreatedAt:         t.CreatedAt,
+		RotatedAt:         t.RotatedAt,
+		LastUsedAt:        t.LastUsedAt,
+		PreviousExpiresAt: t.PreviousExpiresAt,
+	}
+}
@@ -54,7 +54,7 @@ func (h *CommandHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 创建指令
-	resp, err := h.svc.CreateCommand(&model.CreateCommandRequest{
+	resp, err := h.svc.CreateCommand(r.Context(), &model.CreateCommandRequest{
 		ClusterID:       req.ClusterID,
 		Action:          req.Action,
 		TargetKind:      req.TargetKind,
@@ -64,7 +64,7 @@ func (h *CommandHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Source:          "web",
 	})
 	if err != nil {
-		handler.WriteError(w, http.StatusBadRequest, err.Error())
+		handler.WriteError(w, handler.CommandErrorStatus(err, http.StatusBadRequest), err.Error())
 		return
 	}
 
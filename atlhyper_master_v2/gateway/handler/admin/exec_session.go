@@ -0,0 +1,146 @@
+// atlhyper_master_v2/gateway/handler/admin/exec_session.go
+// Exec 会话记录查询 API Handler
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/service"
+)
+
+// ExecSessionHandler Exec 会话记录 Handler
+type ExecSessionHandler struct {
+	svc service.Query
+}
+
+// NewExecSessionHandler 创建 ExecSessionHandler
+func NewExecSessionHandler(svc service.Query) *ExecSessionHandler {
+	return &ExecSessionHandler{svc: svc}
+}
+
+// ExecSessionResponse 会话记录响应
+type ExecSessionResponse struct {
+	SessionID  string          `json:"sessionId"`
+	ClusterID  string          `json:"clusterId"`
+	Namespace  string          `json:"namespace"`
+	Pod        string          `json:"pod"`
+	Container  string          `json:"container,omitempty"`
+	Command    json.RawMessage `json:"command,omitempty"`
+	UserID     int64           `json:"userId"`
+	Username   string          `json:"username"`
+	StartedAt  time.Time       `json:"startedAt"`
+	EndedAt    time.Time       `json:"endedAt"`
+	ExitCode   *int            `json:"exitCode,omitempty"`
+	EndReason  string          `json:"endReason"`
+	Transcript json.RawMessage `json:"transcript,omitempty"`
+	Truncated  bool            `json:"truncated"`
+}
+
+// List 列出会话记录（不含 transcript）
+// GET /api/v2/exec/sessions?cluster_id=&user_id=&limit=&offset=
+func (h *ExecSessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	opts := database.ExecSessionQueryOpts{
+		ClusterID: query.Get("cluster_id"),
+		Limit:     50,
+	}
+	if userID, err := strconv.ParseInt(query.Get("user_id"), 10, 64); err == nil {
+		opts.UserID = userID
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			if limit > 200 {
+				limit = 200 // 最大限制
+			}
+			opts.Limit = limit
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			opts.Offset = offset
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	sessions, err := h.svc.ListExecSessions(ctx, opts)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to list exec sessions")
+		return
+	}
+
+	responses := make([]ExecSessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		responses = append(responses, toExecSessionResponse(s))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"sessions": responses,
+		"total":    len(responses),
+	})
+}
+
+// Get 获取单个会话记录（含 transcript）
+// GET /api/v2/exec/sessions/{sessionID}
+func (h *ExecSessionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	sessionID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v2/exec/sessions/"), "/")
+	if sessionID == "" || strings.Contains(sessionID, "/") {
+		handler.WriteError(w, http.StatusBadRequest, "session id required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	session, err := h.svc.GetExecSession(ctx, sessionID)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to get exec session")
+		return
+	}
+	if session == nil {
+		handler.WriteError(w, http.StatusNotFound, "exec session not found")
+		return
+	}
+	handler.WriteJSON(w, http.StatusOK, toExecSessionResponse(session))
+}
+
+func toExecSessionResponse(s *database.ExecSession) ExecSessionResponse {
+	resp := ExecSessionResponse{
+		SessionID: s.SessionID,
+		ClusterID: s.ClusterID,
+		Namespace: s.Namespace,
+		Pod:       s.Pod,
+		Container: s.Container,
+		UserID:    s.UserID,
+		Username:  s.Username,
+		StartedAt: s.StartedAt,
+		EndedAt:   s.EndedAt,
+		ExitCode:  s.ExitCode,
+		EndReason: s.EndReason,
+		Truncated: s.Truncated,
+	}
+	if s.Command != "" {
+		resp.Command = json.RawMessage(s.Command)
+	}
+	if s.Transcript != "" {
+		resp.Transcript = json.RawMessage(s.Transcript)
+	}
+	return resp
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -76,9 +77,13 @@ type UpdateChannelRequest struct {
 
 // ChannelHandler 通知渠道综合处理（Admin 权限）
 // 根据 Method 和 Path 分发到对应的处理函数
-// GET  /api/v2/notify/channels/{type}       -> 获取详情
-// PUT  /api/v2/notify/channels/{type}       -> 更新配置
-// POST /api/v2/notify/channels/{type}/test  -> 测试发送
+// POST   /api/v2/notify/channels/            -> 创建渠道（同类型可创建多个）
+// GET    /api/v2/notify/channels/{id}        -> 获取详情
+// PUT    /api/v2/notify/channels/{id}        -> 更新配置
+// DELETE /api/v2/notify/channels/{id}        -> 删除渠道
+// GET    /api/v2/notify/channels/{type}      -> 获取该类型第一个渠道（兼容）
+// PUT    /api/v2/notify/channels/{type}      -> 更新该类型第一个渠道，不存在则创建（兼容）
+// POST   /api/v2/notify/channels/{id}/test   -> 测试发送
 func (h *NotifyHandler) ChannelHandler(w http.ResponseWriter, r *http.Request) {
 	// 解析路径
 	path := strings.TrimPrefix(r.URL.Path, "/api/v2/notify/channels/")
@@ -89,17 +94,146 @@ func (h *NotifyHandler) ChannelHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	path = strings.TrimSuffix(path, "/")
+	if path == "" {
+		if r.Method != http.MethodPost {
+			handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		h.createChannel(w, r)
+		return
+	}
+
+	// 数字路径按 ID 操作
+	if id, err := strconv.ParseInt(path, 10, 64); err == nil {
+		switch r.Method {
+		case http.MethodGet:
+			h.getChannelByID(w, r, id)
+		case http.MethodPut, http.MethodPatch:
+			h.updateChannelByID(w, r, id)
+		case http.MethodDelete:
+			h.deleteChannel(w, r, id)
+		default:
+			handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
 	// 根据 Method 分发
 	switch r.Method {
 	case http.MethodGet:
-		h.getChannel(w, r, strings.TrimSuffix(path, "/"))
+		h.getChannel(w, r, path)
 	case http.MethodPut, http.MethodPatch:
-		h.updateChannel(w, r, strings.TrimSuffix(path, "/"))
+		h.updateChannel(w, r, path)
 	default:
 		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
 	}
 }
 
+// CreateChannelRequest 创建通知渠道请求
+type CreateChannelRequest struct {
+	Type    string          `json:"type"`
+	Name    string          `json:"name"`
+	Enabled bool            `json:"enabled"`
+	Config  json.RawMessage `json:"config,omitempty"`
+}
+
+// createChannel 创建通知渠道
+func (h *NotifyHandler) createChannel(w http.ResponseWriter, r *http.Request) {
+	var req CreateChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if !isValidChannelType(req.Type) {
+		handler.WriteError(w, http.StatusBadRequest, "invalid channel type")
+		return
+	}
+
+	ch := &database.NotifyChannel{
+		Type:    req.Type,
+		Name:    req.Name,
+		Enabled: req.Enabled,
+		Config:  "{}",
+	}
+	if ch.Name == "" {
+		ch.Name = req.Type
+	}
+	if req.Config != nil {
+		ch.Config = string(req.Config)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := h.svc.CreateNotifyChannel(ctx, ch); err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to create channel")
+		return
+	}
+
+	handler.WriteJSON(w, http.StatusCreated, toChannelResponse(ch))
+}
+
+// getChannelByID 按 ID 获取通知渠道详情
+func (h *NotifyHandler) getChannelByID(w http.ResponseWriter, r *http.Request, id int64) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	channel, err := h.svc.GetNotifyChannel(ctx, id)
+	if err != nil || channel == nil {
+		handler.WriteError(w, http.StatusNotFound, "channel not found")
+		return
+	}
+
+	handler.WriteJSON(w, http.StatusOK, toChannelResponse(channel))
+}
+
+// updateChannelByID 按 ID 更新通知渠道
+func (h *NotifyHandler) updateChannelByID(w http.ResponseWriter, r *http.Request, id int64) {
+	var req UpdateChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	existing, err := h.svc.GetNotifyChannel(ctx, id)
+	if err != nil || existing == nil {
+		handler.WriteError(w, http.StatusNotFound, "channel not found")
+		return
+	}
+
+	applyChannelUpdate(existing, &req)
+	if err := h.svc.UpdateNotifyChannel(ctx, existing); err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to update channel")
+		return
+	}
+
+	handler.WriteJSON(w, http.StatusOK, toChannelResponse(existing))
+}
+
+// deleteChannel 删除通知渠道
+func (h *NotifyHandler) deleteChannel(w http.ResponseWriter, r *http.Request, id int64) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	existing, err := h.svc.GetNotifyChannel(ctx, id)
+	if err != nil || existing == nil {
+		handler.WriteError(w, http.StatusNotFound, "channel not found")
+		return
+	}
+	if err := h.svc.DeleteNotifyChannel(ctx, id); err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to delete channel")
+		return
+	}
+
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "channel deleted",
+	})
+}
+
 // getChannel 获取通知渠道详情
 func (h *NotifyHandler) getChannel(w http.ResponseWriter, r *http.Request, channelType string) {
 	if channelType == "" {
@@ -165,15 +299,7 @@ func (h *NotifyHandler) updateChannel(w http.ResponseWriter, r *http.Request, ch
 	}
 
 	// 更新字段
-	if req.Enabled != nil {
-		existing.Enabled = *req.Enabled
-	}
-	if req.Name != "" {
-		existing.Name = req.Name
-	}
-	if req.Config != nil {
-		existing.Config = string(req.Config)
-	}
+	applyChannelUpdate(existing, &req)
 
 	// 保存
 	if existing.ID == 0 {
@@ -192,7 +318,7 @@ func (h *NotifyHandler) updateChannel(w http.ResponseWriter, r *http.Request, ch
 // testChannel 测试通知渠道（内部方法）
 // 已迁移到独立的 tester 模块（端口 9080）
 // 此处保留空实现，返回提示信息
-func (h *NotifyHandler) testChannel(w http.ResponseWriter, r *http.Request, channelType string) {
+func (h *NotifyHandler) testChannel(w http.ResponseWriter, r *http.Request, channelID string) {
 	if r.Method != http.MethodPost {
 		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
@@ -200,21 +326,37 @@ func (h *NotifyHandler) testChannel(w http.ResponseWriter, r *http.Request, chan
 
 	// 测试功能已迁移到独立端口
 	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
-		"message": "测试功能已迁移到 :9080/test/notifier/" + channelType,
-		"channel": channelType,
+		"message": "测试功能已迁移到 :9080/test/notifier/" + channelID,
+		"channel": channelID,
 		"success": false,
 	})
 }
 
 // ==================== 辅助函数 ====================
 
+// applyChannelUpdate 应用更新请求中的非空字段
+func applyChannelUpdate(ch *database.NotifyChannel, req *UpdateChannelRequest) {
+	if req.Enabled != nil {
+		ch.Enabled = *req.Enabled
+	}
+	if req.Name != "" {
+		ch.Name = req.Name
+	}
+	if req.Config != nil {
+		ch.Config = string(req.Config)
+	}
+}
+
 // validChannelTypes 有效的渠道类型
 var validChannelTypes = map[string]bool{
-	"email":    true,
-	"mail":     true, // alias
-	"slack":    true,
-	"webhook":  true,
-	"dingtalk": true,
+	"email":     true,
+	"mail":      true, // alias
+	"slack":     true,
+	"webhook":   true,
+	"teams":     true,
+	"dingtalk":  true,
+	"feishu":    true,
+	"pagerduty": true,
 }
 
 // isValidChannelType 校验渠道类型
@@ -262,10 +404,7 @@ func validateChannelConfig(channelType, config string) []string {
 		}
 
 	case "webhook":
-		// webhook 类型需要 url
-		var cfg struct {
-			URL string `json:"url"`
-		}
+		var cfg database.WebhookConfig
 		if err := json.Unmarshal([]byte(config), &cfg); err != nil {
 			errors = append(errors, "配置格式错误")
 			return errors
@@ -273,12 +412,32 @@ func validateChannelConfig(channelType, config string) []string {
 		if cfg.URL == "" {
 			errors = append(errors, "url 未配置")
 		}
+		if cfg.MaxRetries != nil && *cfg.MaxRetries < 0 {
+			errors = append(errors, "maxRetries 不能为负数")
+		}
+
+	case "teams":
+		var cfg database.TeamsConfig
+		if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+			errors = append(errors, "配置格式错误")
+			return errors
+		}
+		if cfg.WebhookURL == "" {
+			errors = append(errors, "webhook_url 未配置")
+		}
 
 	case "dingtalk":
-		// 钉钉需要 webhook
-		var cfg struct {
-			WebhookURL string `json:"webhook_url"`
+		var cfg database.DingTalkConfig
+		if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+			errors = append(errors, "配置格式错误")
+			return errors
+		}
+		if cfg.WebhookURL == "" {
+			errors = append(errors, "webhook_url 未配置")
 		}
+
+	case "feishu":
+		var cfg database.FeishuConfig
 		if err := json.Unmarshal([]byte(config), &cfg); err != nil {
 			errors = append(errors, "配置格式错误")
 			return errors
@@ -286,6 +445,16 @@ func validateChannelConfig(channelType, config string) []string {
 		if cfg.WebhookURL == "" {
 			errors = append(errors, "webhook_url 未配置")
 		}
+
+	case "pagerduty":
+		var cfg database.PagerDutyConfig
+		if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+			errors = append(errors, "配置格式错误")
+			return errors
+		}
+		if cfg.RoutingKey == "" {
+			errors = append(errors, "routing_key 未配置")
+		}
 	}
 
 	return errors
@@ -0,0 +1,173 @@
+// atlhyper_master_v2/gateway/handler/admin/notify_delivery.go
+// 通知投递记录 API Handler（查询投递历史、手动重发死信）
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+)
+
+// DeliveryDTO 通知投递记录
+type DeliveryDTO struct {
+	ID            int64           `json:"id"`
+	ChannelID     int64           `json:"channelId"`
+	ChannelType   string          `json:"channelType"`
+	ChannelName   string          `json:"channelName"`
+	TemplateName  string          `json:"templateName"`
+	Subject       string          `json:"subject"`
+	PayloadHash   string          `json:"payloadHash"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"lastError,omitempty"`
+	NextAttemptAt string          `json:"nextAttemptAt,omitempty"`
+	SentAt        string          `json:"sentAt,omitempty"`
+	CreatedAt     string          `json:"createdAt"`
+	UpdatedAt     string          `json:"updatedAt"`
+	Payload       json.RawMessage `json:"payload,omitempty"` // 仅详情返回
+}
+
+// validDeliveryStatus 可过滤的投递状态
+var validDeliveryStatus = map[string]bool{
+	database.DeliveryPending: true,
+	database.DeliverySent:    true,
+	database.DeliveryFailed:  true,
+	database.DeliveryDead:    true,
+}
+
+// ListDeliveries 投递记录列表
+// GET /api/v2/notify/deliveries?status=dead&channelId=1&limit=50&offset=0
+func (h *NotifyHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	opts := database.NotifyDeliveryQueryOpts{Limit: 50}
+	if status := query.Get("status"); status != "" {
+		if !validDeliveryStatus[status] {
+			handler.WriteError(w, http.StatusBadRequest, "invalid status")
+			return
+		}
+		opts.Status = status
+	}
+	if v := query.Get("channelId"); v != "" {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil && id > 0 {
+			opts.ChannelID = id
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if limit, err := strconv.Atoi(v); err == nil && limit > 0 {
+			if limit > 200 {
+				limit = 200 // 最大限制
+			}
+			opts.Limit = limit
+		}
+	}
+	if v := query.Get("offset"); v != "" {
+		if offset, err := strconv.Atoi(v); err == nil && offset >= 0 {
+			opts.Offset = offset
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	deliveries, err := h.svc.ListNotifyDeliveries(ctx, opts)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to list deliveries")
+		return
+	}
+	total, err := h.svc.CountNotifyDeliveries(ctx, opts)
+	if err != nil {
+		total = int64(len(deliveries))
+	}
+
+	dtos := make([]DeliveryDTO, 0, len(deliveries))
+	for _, d := range deliveries {
+		dtos = append(dtos, toDeliveryDTO(d, false))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"deliveries": dtos,
+		"total":      total,
+	})
+}
+
+// DeliveryHandler 投递记录详情与手动重发
+// GET  /api/v2/notify/deliveries/{id}         详情（含渲染后的消息）
+// POST /api/v2/notify/deliveries/{id}/resend  重发（仅 failed / dead）
+func (h *NotifyHandler) DeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/notify/deliveries/"), "/")
+	idStr, action, _ := strings.Cut(rest, "/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		handler.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	existing, err := h.svc.GetNotifyDelivery(ctx, id)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to get delivery")
+		return
+	}
+	if existing == nil {
+		handler.WriteError(w, http.StatusNotFound, "delivery not found")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && action == "":
+		handler.WriteJSON(w, http.StatusOK, toDeliveryDTO(existing, true))
+
+	case r.Method == http.MethodPost && action == "resend":
+		if existing.Status != database.DeliveryDead && existing.Status != database.DeliveryFailed {
+			handler.WriteError(w, http.StatusConflict, "only failed or dead deliveries can be resent")
+			return
+		}
+		if err := h.svc.ResendNotifyDelivery(ctx, id); err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to resend delivery")
+			return
+		}
+		handler.WriteJSON(w, http.StatusAccepted, map[string]interface{}{"message": "delivery queued for resend"})
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func toDeliveryDTO(d *database.NotifyDelivery, withPayload bool) DeliveryDTO {
+	dto := DeliveryDTO{
+		ID:           d.ID,
+		ChannelID:    d.ChannelID,
+		ChannelType:  d.ChannelType,
+		ChannelName:  d.ChannelName,
+		TemplateName: d.TemplateName,
+		Subject:      d.Subject,
+		PayloadHash:  d.PayloadHash,
+		Status:       d.Status,
+		Attempts:     d.Attempts,
+		LastError:    d.LastError,
+		CreatedAt:    formatTime(d.CreatedAt),
+		UpdatedAt:    formatTime(d.UpdatedAt),
+	}
+	if d.Status == database.DeliveryPending || d.Status == database.DeliveryFailed {
+		dto.NextAttemptAt = formatTime(d.NextAttemptAt)
+	}
+	if d.SentAt != nil {
+		dto.SentAt = formatTime(*d.SentAt)
+	}
+	if withPayload && json.Valid([]byte(d.Payload)) {
+		dto.Payload = json.RawMessage(d.Payload)
+	}
+	return dto
+}
@@ -0,0 +1,535 @@
+// atlhyper_master_v2/gateway/handler/admin/notify_routing.go
+// 告警路由、静默、抑制规则 API Handler
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+)
+
+// 路由分组默认值（与 notify_routes 表默认值一致）
+const (
+	defaultGroupWaitS     = 30
+	defaultGroupIntervalS = 300
+)
+
+// RouteDTO 告警路由
+type RouteDTO struct {
+	ID             int64             `json:"id"`
+	Name           string            `json:"name"`
+	Priority       int               `json:"priority"`
+	Enabled        bool              `json:"enabled"`
+	Matchers       map[string]string `json:"matchers"`
+	ChannelIDs     []int64           `json:"channelIds"`
+	GroupBy        []string          `json:"groupBy"`
+	GroupWaitS     *int              `json:"groupWaitSeconds,omitempty"`
+	GroupIntervalS *int              `json:"groupIntervalSeconds,omitempty"`
+	Continue       bool              `json:"continue"`
+	CreatedAt      string            `json:"createdAt,omitempty"`
+	UpdatedAt      string            `json:"updatedAt,omitempty"`
+}
+
+// SilenceDTO 告警静默
+type SilenceDTO struct {
+	ID        int64             `json:"id"`
+	Matchers  map[string]string `json:"matchers"`
+	StartsAt  string            `json:"startsAt"`
+	EndsAt    string            `json:"endsAt"`
+	Comment   string            `json:"comment"`
+	CreatedBy string            `json:"createdBy"`
+	CreatedAt string            `json:"createdAt,omitempty"`
+	Active    bool              `json:"active"`
+}
+
+// CreateSilenceRequest 创建静默请求（endsAt 与 durationMinutes 二选一）
+type CreateSilenceRequest struct {
+	Matchers        map[string]string `json:"matchers"`
+	StartsAt        string            `json:"startsAt,omitempty"`
+	EndsAt          string            `json:"endsAt,omitempty"`
+	DurationMinutes int               `json:"durationMinutes,omitempty"`
+	Comment         string            `json:"comment"`
+}
+
+// InhibitRuleDTO 告警抑制规则
+type InhibitRuleDTO struct {
+	ID             int64             `json:"id"`
+	Name           string            `json:"name"`
+	Enabled        bool              `json:"enabled"`
+	SourceMatchers map[string]string `json:"sourceMatchers"`
+	TargetMatchers map[string]string `json:"targetMatchers"`
+	Equal          []string          `json:"equal"`
+	CreatedAt      string            `json:"createdAt,omitempty"`
+	UpdatedAt      string            `json:"updatedAt,omitempty"`
+}
+
+// ==================== 路由 ====================
+
+// ListRoutes 列出告警路由
+// GET /api/v2/notify/routes
+func (h *NotifyHandler) ListRoutes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	routes, err := h.svc.ListNotifyRoutes(ctx)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to list routes")
+		return
+	}
+
+	dtos := make([]RouteDTO, 0, len(routes))
+	for _, rt := range routes {
+		dtos = append(dtos, toRouteDTO(rt))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"routes": dtos,
+		"total":  len(dtos),
+	})
+}
+
+// RouteHandler 单条路由操作
+// POST   /api/v2/notify/routes/      -> 创建
+// PUT    /api/v2/notify/routes/{id}  -> 更新
+// DELETE /api/v2/notify/routes/{id}  -> 删除
+func (h *NotifyHandler) RouteHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "/api/v2/notify/routes/")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch {
+	case r.Method == http.MethodPost && id == 0:
+		var req RouteDTO
+		if !decodeRoute(w, r, &req) {
+			return
+		}
+		route := fromRouteDTO(&req)
+		if err := h.svc.CreateNotifyRoute(ctx, route); err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to create route")
+			return
+		}
+		handler.WriteJSON(w, http.StatusCreated, toRouteDTO(route))
+
+	case (r.Method == http.MethodPut || r.Method == http.MethodPatch) && id != 0:
+		existing, err := h.svc.GetNotifyRoute(ctx, id)
+		if err != nil || existing == nil {
+			handler.WriteError(w, http.StatusNotFound, "route not found")
+			return
+		}
+		var req RouteDTO
+		if !decodeRoute(w, r, &req) {
+			return
+		}
+		route := fromRouteDTO(&req)
+		route.ID = id
+		if err := h.svc.UpdateNotifyRoute(ctx, route); err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to update route")
+			return
+		}
+		route.CreatedAt = existing.CreatedAt
+		route.UpdatedAt = time.Now()
+		handler.WriteJSON(w, http.StatusOK, toRouteDTO(route))
+
+	case r.Method == http.MethodDelete && id != 0:
+		if err := h.svc.DeleteNotifyRoute(ctx, id); err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to delete route")
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]interface{}{"message": "route deleted"})
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// decodeRoute 解析并校验路由请求
+func decodeRoute(w http.ResponseWriter, r *http.Request, req *RouteDTO) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		handler.WriteError(w, http.StatusBadRequest, "name required")
+		return false
+	}
+	if len(req.ChannelIDs) == 0 {
+		handler.WriteError(w, http.StatusBadRequest, "channelIds required")
+		return false
+	}
+	if (req.GroupWaitS != nil && *req.GroupWaitS < 0) || (req.GroupIntervalS != nil && *req.GroupIntervalS < 0) {
+		handler.WriteError(w, http.StatusBadRequest, "group wait/interval must be >= 0")
+		return false
+	}
+	return true
+}
+
+// ==================== 静默 ====================
+
+// ListSilences 列出告警静默（含已过期，按结束时间倒序）
+// GET /api/v2/notify/silences
+func (h *NotifyHandler) ListSilences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	silences, err := h.svc.ListNotifySilences(ctx)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to list silences")
+		return
+	}
+
+	now := time.Now()
+	dtos := make([]SilenceDTO, 0, len(silences))
+	for _, s := range silences {
+		dtos = append(dtos, toSilenceDTO(s, now))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"silences": dtos,
+		"total":    len(dtos),
+	})
+}
+
+// SilenceHandler 单条静默操作
+// POST   /api/v2/notify/silences/      -> 创建
+// DELETE /api/v2/notify/silences/{id}  -> 立即结束（保留记录）
+func (h *NotifyHandler) SilenceHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "/api/v2/notify/silences/")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch {
+	case r.Method == http.MethodPost && id == 0:
+		h.createSilence(ctx, w, r)
+
+	case r.Method == http.MethodDelete && id != 0:
+		existing, err := h.svc.GetNotifySilence(ctx, id)
+		if err != nil || existing == nil {
+			handler.WriteError(w, http.StatusNotFound, "silence not found")
+			return
+		}
+		if err := h.svc.ExpireNotifySilence(ctx, id); err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to expire silence")
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]interface{}{"message": "silence expired"})
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// createSilence 创建静默
+func (h *NotifyHandler) createSilence(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req CreateSilenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Matchers) == 0 {
+		handler.WriteError(w, http.StatusBadRequest, "matchers required")
+		return
+	}
+
+	now := time.Now()
+	startsAt := now
+	if req.StartsAt != "" {
+		t, err := time.Parse(time.RFC3339, req.StartsAt)
+		if err != nil {
+			handler.WriteError(w, http.StatusBadRequest, "invalid startsAt")
+			return
+		}
+		startsAt = t
+	}
+
+	var endsAt time.Time
+	switch {
+	case req.EndsAt != "":
+		t, err := time.Parse(time.RFC3339, req.EndsAt)
+		if err != nil {
+			handler.WriteError(w, http.StatusBadRequest, "invalid endsAt")
+			return
+		}
+		endsAt = t
+	case req.DurationMinutes > 0:
+		endsAt = startsAt.Add(time.Duration(req.DurationMinutes) * time.Minute)
+	default:
+		handler.WriteError(w, http.StatusBadRequest, "endsAt or durationMinutes required")
+		return
+	}
+	if !endsAt.After(startsAt) || !endsAt.After(now) {
+		handler.WriteError(w, http.StatusBadRequest, "endsAt must be after startsAt and now")
+		return
+	}
+
+	createdBy, _ := middleware.GetUsername(r.Context())
+	silence := &database.NotifySilence{
+		Matchers:  marshalJSON(req.Matchers, "{}"),
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		Comment:   req.Comment,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}
+	if err := h.svc.CreateNotifySilence(ctx, silence); err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to create silence")
+		return
+	}
+	handler.WriteJSON(w, http.StatusCreated, toSilenceDTO(silence, now))
+}
+
+// ==================== 抑制规则 ====================
+
+// ListInhibitRules 列出告警抑制规则
+// GET /api/v2/notify/inhibit-rules
+func (h *NotifyHandler) ListInhibitRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	rules, err := h.svc.ListNotifyInhibitRules(ctx)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to list inhibit rules")
+		return
+	}
+
+	dtos := make([]InhibitRuleDTO, 0, len(rules))
+	for _, rule := range rules {
+		dtos = append(dtos, toInhibitRuleDTO(rule))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"rules": dtos,
+		"total": len(dtos),
+	})
+}
+
+// InhibitRuleHandler 单条抑制规则操作
+// POST   /api/v2/notify/inhibit-rules/      -> 创建
+// PUT    /api/v2/notify/inhibit-rules/{id}  -> 更新
+// DELETE /api/v2/notify/inhibit-rules/{id}  -> 删除
+func (h *NotifyHandler) InhibitRuleHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "/api/v2/notify/inhibit-rules/")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch {
+	case r.Method == http.MethodPost && id == 0:
+		var req InhibitRuleDTO
+		if !decodeInhibitRule(w, r, &req) {
+			return
+		}
+		rule := fromInhibitRuleDTO(&req)
+		if err := h.svc.CreateNotifyInhibitRule(ctx, rule); err != nil {
+			if strings.Contains(err.Error(), "UNIQUE") {
+				handler.WriteError(w, http.StatusConflict, "inhibit rule name already exists")
+				return
+			}
+			handler.WriteError(w, http.StatusInternalServerError, "failed to create inhibit rule")
+			return
+		}
+		handler.WriteJSON(w, http.StatusCreated, toInhibitRuleDTO(rule))
+
+	case (r.Method == http.MethodPut || r.Method == http.MethodPatch) && id != 0:
+		existing, err := h.svc.GetNotifyInhibitRule(ctx, id)
+		if err != nil || existing == nil {
+			handler.WriteError(w, http.StatusNotFound, "inhibit rule not found")
+			return
+		}
+		var req InhibitRuleDTO
+		if !decodeInhibitRule(w, r, &req) {
+			return
+		}
+		rule := fromInhibitRuleDTO(&req)
+		rule.ID = id
+		if err := h.svc.UpdateNotifyInhibitRule(ctx, rule); err != nil {
+			if strings.Contains(err.Error(), "UNIQUE") {
+				handler.WriteError(w, http.StatusConflict, "inhibit rule name already exists")
+				return
+			}
+			handler.WriteError(w, http.StatusInternalServerError, "failed to update inhibit rule")
+			return
+		}
+		rule.CreatedAt = existing.CreatedAt
+		rule.UpdatedAt = time.Now()
+		handler.WriteJSON(w, http.StatusOK, toInhibitRuleDTO(rule))
+
+	case r.Method == http.MethodDelete && id != 0:
+		if err := h.svc.DeleteNotifyInhibitRule(ctx, id); err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to delete inhibit rule")
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]interface{}{"message": "inhibit rule deleted"})
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// decodeInhibitRule 解析并校验抑制规则请求
+func decodeInhibitRule(w http.ResponseWriter, r *http.Request, req *InhibitRuleDTO) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		handler.WriteError(w, http.StatusBadRequest, "name required")
+		return false
+	}
+	// 空匹配器会抑制所有告警，不允许
+	if len(req.SourceMatchers) == 0 || len(req.TargetMatchers) == 0 {
+		handler.WriteError(w, http.StatusBadRequest, "sourceMatchers and targetMatchers required")
+		return false
+	}
+	return true
+}
+
+// ==================== 辅助函数 ====================
+
+// parsePathID 解析路径中的 ID（路径为空返回 0）
+func parsePathID(w http.ResponseWriter, r *http.Request, prefix string) (int64, bool) {
+	idStr := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if idStr == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		handler.WriteError(w, http.StatusBadRequest, "invalid id")
+		return 0, false
+	}
+	return id, true
+}
+
+// marshalJSON 序列化为 JSON 字符串（nil 使用默认值）
+func marshalJSON(v interface{}, fallback string) string {
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return fallback
+	}
+	return string(data)
+}
+
+// formatTime 格式化时间（零值返回空）
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func toRouteDTO(rt *database.NotifyRoute) RouteDTO {
+	dto := RouteDTO{
+		ID:             rt.ID,
+		Name:           rt.Name,
+		Priority:       rt.Priority,
+		Enabled:        rt.Enabled,
+		Matchers:       map[string]string{},
+		ChannelIDs:     []int64{},
+		GroupBy:        []string{},
+		GroupWaitS:     &rt.GroupWaitS,
+		GroupIntervalS: &rt.GroupIntervalS,
+		Continue:       rt.Continue,
+		CreatedAt:      formatTime(rt.CreatedAt),
+		UpdatedAt:      formatTime(rt.UpdatedAt),
+	}
+	_ = json.Unmarshal([]byte(rt.Matchers), &dto.Matchers)
+	_ = json.Unmarshal([]byte(rt.ChannelIDs), &dto.ChannelIDs)
+	_ = json.Unmarshal([]byte(rt.GroupBy), &dto.GroupBy)
+	return dto
+}
+
+func fromRouteDTO(dto *RouteDTO) *database.NotifyRoute {
+	rt := &database.NotifyRoute{
+		Name:           strings.TrimSpace(dto.Name),
+		Priority:       dto.Priority,
+		Enabled:        dto.Enabled,
+		Matchers:       marshalJSON(dto.Matchers, "{}"),
+		ChannelIDs:     marshalJSON(dto.ChannelIDs, "[]"),
+		GroupBy:        marshalJSON(dto.GroupBy, "[]"),
+		GroupWaitS:     defaultGroupWaitS,
+		GroupIntervalS: defaultGroupIntervalS,
+		Continue:       dto.Continue,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if dto.GroupWaitS != nil {
+		rt.GroupWaitS = *dto.GroupWaitS
+	}
+	if dto.GroupIntervalS != nil {
+		rt.GroupIntervalS = *dto.GroupIntervalS
+	}
+	return rt
+}
+
+func toSilenceDTO(s *database.NotifySilence, now time.Time) SilenceDTO {
+	dto := SilenceDTO{
+		ID:        s.ID,
+		Matchers:  map[string]string{},
+		StartsAt:  formatTime(s.StartsAt),
+		EndsAt:    formatTime(s.EndsAt),
+		Comment:   s.Comment,
+		CreatedBy: s.CreatedBy,
+		CreatedAt: formatTime(s.CreatedAt),
+		Active:    !now.Before(s.StartsAt) && now.Before(s.EndsAt),
+	}
+	_ = json.Unmarshal([]byte(s.Matchers), &dto.Matchers)
+	return dto
+}
+
+func toInhibitRuleDTO(rule *database.NotifyInhibitRule) InhibitRuleDTO {
+	dto := InhibitRuleDTO{
+		ID:             rule.ID,
+		Name:           rule.Name,
+		Enabled:        rule.Enabled,
+		SourceMatchers: map[string]string{},
+		TargetMatchers: map[string]string{},
+		Equal:          []string{},
+		CreatedAt:      formatTime(rule.CreatedAt),
+		UpdatedAt:      formatTime(rule.UpdatedAt),
+	}
+	_ = json.Unmarshal([]byte(rule.SourceMatchers), &dto.SourceMatchers)
+	_ = json.Unmarshal([]byte(rule.TargetMatchers), &dto.TargetMatchers)
+	_ = json.Unmarshal([]byte(rule.Equal), &dto.Equal)
+	return dto
+}
+
+func fromInhibitRuleDTO(dto *InhibitRuleDTO) *database.NotifyInhibitRule {
+	return &database.NotifyInhibitRule{
+		Name:           strings.TrimSpace(dto.Name),
+		Enabled:        dto.Enabled,
+		SourceMatchers: marshalJSON(dto.SourceMatchers, "{}"),
+		TargetMatchers: marshalJSON(dto.TargetMatchers, "{}"),
+		Equal:          marshalJSON(dto.Equal, "[]"),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+}
@@ -0,0 +1,212 @@
+// atlhyper_master_v2/gateway/handler/admin/notify_template.go
+// 通知模板 API Handler（自定义模板覆盖内置模板、保存时校验、预览）
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/notifier/template"
+)
+
+// TemplateDTO 通知模板（未自定义时 Body 为内置模板）
+type TemplateDTO struct {
+	Name        string `json:"name"`
+	Variant     string `json:"variant"`
+	Body        string `json:"body"`
+	DefaultBody string `json:"defaultBody"`
+	Customized  bool   `json:"customized"`
+	UpdatedBy   string `json:"updatedBy,omitempty"`
+	UpdatedAt   string `json:"updatedAt,omitempty"`
+}
+
+// SaveTemplateRequest 保存自定义模板请求
+type SaveTemplateRequest struct {
+	Body string `json:"body"`
+}
+
+// PreviewTemplateRequest 模板预览请求
+// Body 为空时预览当前生效的模板；Data 覆盖示例数据中的对应字段（字段名同 AlertData，如 Title、Fields、Enriched）
+type PreviewTemplateRequest struct {
+	Name    string          `json:"name"`
+	Variant string          `json:"variant"`
+	Body    string          `json:"body"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// maxTemplateSize 单个模板源码上限
+const maxTemplateSize = 64 << 10
+
+// ListTemplates 所有模板（内置 + 自定义覆盖）
+// GET /api/v2/notify/templates
+func (h *NotifyHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	custom, err := h.svc.ListNotifyTemplates(ctx)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to list templates")
+		return
+	}
+	byKey := make(map[string]*database.NotifyTemplate, len(custom))
+	for _, t := range custom {
+		byKey[t.Name+"_"+t.Variant] = t
+	}
+
+	dtos := make([]TemplateDTO, 0, len(template.TemplateNames)*len(template.TemplateVariants))
+	for _, name := range template.TemplateNames {
+		for _, variant := range template.TemplateVariants {
+			dtos = append(dtos, toTemplateDTO(name, variant, byKey[name+"_"+variant]))
+		}
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"templates": dtos,
+		"total":     len(dtos),
+	})
+}
+
+// TemplateHandler 单个模板查询、保存与恢复默认
+// GET    /api/v2/notify/templates/{name}/{variant}  当前生效的模板
+// PUT    /api/v2/notify/templates/{name}/{variant}  保存自定义模板（解析 + 示例数据试渲染，失败返回 400）
+// DELETE /api/v2/notify/templates/{name}/{variant}  删除自定义模板，恢复内置模板
+func (h *NotifyHandler) TemplateHandler(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/notify/templates/"), "/")
+	name, variant, _ := strings.Cut(rest, "/")
+	if !template.IsKnown(name, variant) {
+		handler.WriteError(w, http.StatusNotFound, "template not found")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		custom, err := h.svc.GetNotifyTemplate(ctx, name, variant)
+		if err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to get template")
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, toTemplateDTO(name, variant, custom))
+
+	case http.MethodPut:
+		var req SaveTemplateRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTemplateSize+1024)).Decode(&req); err != nil {
+			handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if strings.TrimSpace(req.Body) == "" {
+			handler.WriteError(w, http.StatusBadRequest, "body is required")
+			return
+		}
+		if len(req.Body) > maxTemplateSize {
+			handler.WriteError(w, http.StatusBadRequest, "template too large")
+			return
+		}
+
+		updatedBy, _ := middleware.GetUsername(r.Context())
+		t := &database.NotifyTemplate{Name: name, Variant: variant, Body: req.Body, UpdatedBy: updatedBy}
+		if err := h.svc.SaveNotifyTemplate(ctx, t); err != nil {
+			if errors.Is(err, template.ErrInvalidTemplate) {
+				handler.WriteError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			handler.WriteError(w, http.StatusInternalServerError, "failed to save template")
+			return
+		}
+		saved, err := h.svc.GetNotifyTemplate(ctx, name, variant)
+		if err != nil || saved == nil {
+			saved = t
+		}
+		handler.WriteJSON(w, http.StatusOK, toTemplateDTO(name, variant, saved))
+
+	case http.MethodDelete:
+		if err := h.svc.DeleteNotifyTemplate(ctx, name, variant); err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to reset template")
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, toTemplateDTO(name, variant, nil))
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// PreviewTemplate 使用示例数据（可部分覆盖）渲染模板，不保存
+// POST /api/v2/notify/templates/preview
+func (h *NotifyHandler) PreviewTemplate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req PreviewTemplateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2*maxTemplateSize)).Decode(&req); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if !template.IsKnown(req.Name, req.Variant) {
+		handler.WriteError(w, http.StatusBadRequest, "unknown template")
+		return
+	}
+
+	body := req.Body
+	if body == "" {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+		custom, err := h.svc.GetNotifyTemplate(ctx, req.Name, req.Variant)
+		if err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to get template")
+			return
+		}
+		body = toTemplateDTO(req.Name, req.Variant, custom).Body
+	}
+
+	data := template.SampleData(req.Name)
+	if len(req.Data) > 0 && string(req.Data) != "null" {
+		if err := json.Unmarshal(req.Data, data); err != nil {
+			handler.WriteError(w, http.StatusBadRequest, "invalid data")
+			return
+		}
+	}
+
+	output, err := template.Preview(req.Name, req.Variant, body, data)
+	if err != nil {
+		handler.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"subject": data.Title,
+		"body":    output,
+		"html":    req.Variant == "html",
+	})
+}
+
+func toTemplateDTO(name, variant string, custom *database.NotifyTemplate) TemplateDTO {
+	defaultBody, _ := template.DefaultSource(name, variant)
+	dto := TemplateDTO{
+		Name:        name,
+		Variant:     variant,
+		Body:        defaultBody,
+		DefaultBody: defaultBody,
+	}
+	if custom != nil {
+		dto.Body = custom.Body
+		dto.Customized = true
+		dto.UpdatedBy = custom.UpdatedBy
+		dto.UpdatedAt = formatTime(custom.UpdatedAt)
+	}
+	return dto
+}
@@ -0,0 +1,143 @@
+// atlhyper_master_v2/gateway/handler/admin/oidc.go
+// OIDC 单点登录 Handler
+//
+// 登录流程：Web 调用 Login 获取授权地址并跳转 IdP，
+// IdP 回调 Web 回调页后，由回调页将 code/state 提交 Callback 换取 AtlHyper Token。
+// Login 同时把 state 写入 HttpOnly Cookie，Callback 要求 Cookie 与 state 一致，
+// 防止他人把自己的 code/state 交给受害者浏览器完成登录（登录 CSRF）。
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"AtlHyper/atlhyper_master_v2/config"
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/oidc"
+)
+
+// OIDCHandler SSO 登录 Handler
+type OIDCHandler struct {
+	provider *oidc.Provider // nil 表示未启用
+	userRepo database.UserRepository
+}
+
+// NewOIDCHandler 创建 OIDCHandler
+func NewOIDCHandler(provider *oidc.Provider, userRepo database.UserRepository) *OIDCHandler {
+	return &OIDCHandler{provider: provider, userRepo: userRepo}
+}
+
+// oidcStateCookie 发起登录的浏览器持有的 state
+const (
+	oidcStateCookie     = "atlhyper_oidc_state"
+	oidcStateCookiePath = "/api/v2/auth/oidc"
+)
+
+// OIDCCallbackRequest 回调请求
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+// Config 登录方式配置（登录页据此显示 SSO 按钮）
+// GET /api/v2/auth/oidc/config
+func (h *OIDCHandler) Config(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{
+			"enabled":        h.provider != nil,
+			"localAdminOnly": h.provider != nil && config.GlobalConfig.OIDC.LocalAdminOnly,
+		},
+	})
+}
+
+// Login 发起 SSO 登录，返回 IdP 授权地址
+// GET /api/v2/auth/oidc/login
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.provider == nil {
+		handler.WriteError(w, http.StatusNotFound, "SSO 登录未启用")
+		return
+	}
+
+	authURL, state, err := h.provider.AuthURL(r.Context())
+	if err != nil {
+		log.Error("发起 SSO 登录失败", "err", err)
+		handler.WriteError(w, http.StatusBadGateway, "身份提供方不可用")
+		return
+	}
+	setStateCookie(w, r, state, int(h.provider.StateTTL().Seconds()))
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]string{"authUrl": authURL},
+	})
+}
+
+// Callback 完成 SSO 登录，返回与密码登录相同的 Token 响应
+// POST /api/v2/auth/oidc/callback
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.provider == nil {
+		handler.WriteError(w, http.StatusNotFound, "SSO 登录未启用")
+		return
+	}
+
+	var req OIDCCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" || req.State == "" {
+		handler.WriteError(w, http.StatusBadRequest, "code 和 state 不能为空")
+		return
+	}
+
+	// state 须与发起登录的浏览器 Cookie 一致（Cookie 仅使用一次）
+	cookie, err := r.Cookie(oidcStateCookie)
+	setStateCookie(w, r, "", -1)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(req.State)) != 1 {
+		log.Warn("SSO 回调 state 与浏览器 Cookie 不一致", "ip", r.RemoteAddr)
+		handler.WriteError(w, http.StatusBadRequest, "登录请求已过期，请重新登录")
+		return
+	}
+
+	user, err := h.provider.Authenticate(r.Context(), req.Code, req.State)
+	switch {
+	case errors.Is(err, oidc.ErrInvalidState):
+		handler.WriteError(w, http.StatusBadRequest, "登录请求已过期，请重新登录")
+		return
+	case errors.Is(err, oidc.ErrNoRole):
+		handler.WriteError(w, http.StatusForbidden, "账号未被授权访问 AtlHyper")
+		return
+	case errors.Is(err, oidc.ErrUserDisabled):
+		handler.WriteError(w, http.StatusForbidden, "账号已被禁用")
+		return
+	case err != nil:
+		log.Warn("SSO 登录失败", "err", err)
+		handler.WriteError(w, http.StatusUnauthorized, "SSO 登录失败")
+		return
+	}
+
+	writeLoginResponse(w, r, h.userRepo, user)
+}
+
+// setStateCookie 写入（maxAge > 0）或清除（maxAge < 0）state Cookie
+func setStateCookie(w http.ResponseWriter, r *http.Request, state string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     oidcStateCookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https"),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
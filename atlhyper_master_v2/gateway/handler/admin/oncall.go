@@ -0,0 +1,832 @@
+// atlhyper_master_v2/gateway/handler/admin/oncall.go
+// 值班表、替班、升级策略与告警确认 API Handler
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/notifier"
+	"AtlHyper/atlhyper_master_v2/service"
+)
+
+// OncallHandler 值班 Handler
+type OncallHandler struct {
+	svc      service.Service
+	userRepo database.UserRepository
+}
+
+// NewOncallHandler 创建 OncallHandler
+func NewOncallHandler(svc service.Service, userRepo database.UserRepository) *OncallHandler {
+	return &OncallHandler{svc: svc, userRepo: userRepo}
+}
+
+// OncallUserDTO 值班人员
+type OncallUserDTO struct {
+	ID          int64  `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// OncallShiftDTO 当前值班
+type OncallShiftDTO struct {
+	Primary       *OncallUserDTO `json:"primary,omitempty"`
+	Secondary     *OncallUserDTO `json:"secondary,omitempty"`
+	Scheduled     *OncallUserDTO `json:"scheduled,omitempty"`
+	OverrideID    int64          `json:"overrideId,omitempty"`
+	ShiftStartsAt string         `json:"shiftStartsAt"`
+	ShiftEndsAt   string         `json:"shiftEndsAt"`
+}
+
+// ScheduleDTO 值班表
+type ScheduleDTO struct {
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Rotation    string          `json:"rotation"`
+	StartAt     string          `json:"startAt"`
+	Members     []int64         `json:"members"`
+	Current     *OncallShiftDTO `json:"current,omitempty"`
+	CreatedAt   string          `json:"createdAt,omitempty"`
+	UpdatedAt   string          `json:"updatedAt,omitempty"`
+}
+
+// OverrideDTO 替班
+type OverrideDTO struct {
+	ID         int64  `json:"id"`
+	ScheduleID int64  `json:"scheduleId"`
+	UserID     int64  `json:"userId"`
+	StartsAt   string `json:"startsAt"`
+	EndsAt     string `json:"endsAt"`
+	Comment    string `json:"comment"`
+	CreatedBy  string `json:"createdBy"`
+	CreatedAt  string `json:"createdAt,omitempty"`
+	Active     bool   `json:"active"`
+}
+
+// PolicyDTO 升级策略
+type PolicyDTO struct {
+	ID         int64                     `json:"id"`
+	Name       string                    `json:"name"`
+	Enabled    bool                      `json:"enabled"`
+	Matchers   map[string]string         `json:"matchers"`
+	ScheduleID int64                     `json:"scheduleId"`
+	Steps      []database.EscalationStep `json:"steps"`
+	CreatedAt  string                    `json:"createdAt,omitempty"`
+	UpdatedAt  string                    `json:"updatedAt,omitempty"`
+}
+
+// EscalationDTO 告警升级记录
+type EscalationDTO struct {
+	ID           int64  `json:"id"`
+	PolicyID     int64  `json:"policyId"`
+	AlertKey     string `json:"alertKey"`
+	TemplateName string `json:"templateName"`
+	Title        string `json:"title"`
+	Severity     string `json:"severity"`
+	Level        int    `json:"level"`
+	Status       string `json:"status"`
+	AckedBy      string `json:"ackedBy,omitempty"`
+	AckedAt      string `json:"ackedAt,omitempty"`
+	NextAt       string `json:"nextAt,omitempty"`
+	CreatedAt    string `json:"createdAt"`
+	UpdatedAt    string `json:"updatedAt"`
+}
+
+// validEscalationStatus 可过滤的升级状态
+var validEscalationStatus = map[string]bool{
+	database.EscalationOpen:     true,
+	database.EscalationAcked:    true,
+	database.EscalationResolved: true,
+}
+
+// ==================== 值班表 ====================
+
+// ListSchedules 列出值班表（含当前值班）
+// GET /api/v2/oncall/schedules
+func (h *OncallHandler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	schedules, err := h.svc.ListOncallSchedules(ctx)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to list schedules")
+		return
+	}
+
+	now := time.Now()
+	dtos := make([]ScheduleDTO, 0, len(schedules))
+	for _, s := range schedules {
+		dtos = append(dtos, h.toScheduleDTO(ctx, s, now))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"schedules": dtos,
+		"total":     len(dtos),
+	})
+}
+
+// ScheduleHandler 单个值班表与替班操作
+// POST   /api/v2/oncall/schedules/                        -> 创建
+// GET    /api/v2/oncall/schedules/{id}?at=RFC3339         -> 详情（at 时刻的值班，默认当前）
+// PUT    /api/v2/oncall/schedules/{id}                    -> 更新
+// DELETE /api/v2/oncall/schedules/{id}                    -> 删除（被升级策略引用时拒绝）
+// GET    /api/v2/oncall/schedules/{id}/overrides          -> 替班列表
+// POST   /api/v2/oncall/schedules/{id}/overrides          -> 创建替班
+// DELETE /api/v2/oncall/schedules/{id}/overrides/{oid}    -> 删除替班
+func (h *OncallHandler) ScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/oncall/schedules/"), "/")
+	parts := strings.Split(rest, "/")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if rest == "" {
+		if r.Method != http.MethodPost {
+			handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		h.createSchedule(ctx, w, r)
+		return
+	}
+
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || id <= 0 || len(parts) > 3 || (len(parts) > 1 && parts[1] != "overrides") {
+		handler.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+	existing, err := h.svc.GetOncallSchedule(ctx, id)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to get schedule")
+		return
+	}
+	if existing == nil {
+		handler.WriteError(w, http.StatusNotFound, "schedule not found")
+		return
+	}
+
+	if len(parts) > 1 {
+		h.overrideHandler(ctx, w, r, existing, parts[2:])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		at := time.Now()
+		if v := r.URL.Query().Get("at"); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				handler.WriteError(w, http.StatusBadRequest, "invalid at")
+				return
+			}
+			at = t
+		}
+		handler.WriteJSON(w, http.StatusOK, h.toScheduleDTO(ctx, existing, at))
+
+	case http.MethodPut, http.MethodPatch:
+		var req ScheduleDTO
+		if !h.decodeSchedule(ctx, w, r, &req) {
+			return
+		}
+		s := fromScheduleDTO(&req)
+		s.ID = id
+		if err := h.svc.UpdateOncallSchedule(ctx, s); err != nil {
+			if strings.Contains(err.Error(), "UNIQUE") {
+				handler.WriteError(w, http.StatusConflict, "schedule name already exists")
+				return
+			}
+			handler.WriteError(w, http.StatusInternalServerError, "failed to update schedule")
+			return
+		}
+		s.CreatedAt = existing.CreatedAt
+		s.UpdatedAt = time.Now()
+		handler.WriteJSON(w, http.StatusOK, h.toScheduleDTO(ctx, s, time.Now()))
+
+	case http.MethodDelete:
+		policies, err := h.svc.ListEscalationPolicies(ctx)
+		if err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to list policies")
+			return
+		}
+		for _, p := range policies {
+			if p.ScheduleID == id {
+				handler.WriteError(w, http.StatusConflict, "schedule is used by escalation policy "+p.Name)
+				return
+			}
+		}
+		if err := h.svc.DeleteOncallSchedule(ctx, id); err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to delete schedule")
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]interface{}{"message": "schedule deleted"})
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// createSchedule 创建值班表
+func (h *OncallHandler) createSchedule(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req ScheduleDTO
+	if !h.decodeSchedule(ctx, w, r, &req) {
+		return
+	}
+	s := fromScheduleDTO(&req)
+	if err := h.svc.CreateOncallSchedule(ctx, s); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			handler.WriteError(w, http.StatusConflict, "schedule name already exists")
+			return
+		}
+		handler.WriteError(w, http.StatusInternalServerError, "failed to create schedule")
+		return
+	}
+	handler.WriteJSON(w, http.StatusCreated, h.toScheduleDTO(ctx, s, time.Now()))
+}
+
+// decodeSchedule 解析并校验值班表请求
+func (h *OncallHandler) decodeSchedule(ctx context.Context, w http.ResponseWriter, r *http.Request, req *ScheduleDTO) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		handler.WriteError(w, http.StatusBadRequest, "name required")
+		return false
+	}
+	if _, err := notifier.RotationPeriod(req.Rotation); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "rotation must be daily or weekly")
+		return false
+	}
+	if _, err := time.Parse(time.RFC3339, req.StartAt); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid startAt")
+		return false
+	}
+	if len(req.Members) == 0 {
+		handler.WriteError(w, http.StatusBadRequest, "members required")
+		return false
+	}
+	seen := make(map[int64]bool, len(req.Members))
+	for _, id := range req.Members {
+		if seen[id] {
+			handler.WriteError(w, http.StatusBadRequest, "duplicate member")
+			return false
+		}
+		seen[id] = true
+		if !h.userExists(ctx, w, id) {
+			return false
+		}
+	}
+	return true
+}
+
+// ==================== 替班 ====================
+
+// overrideHandler 值班表的替班操作（rest 为 overrides 之后的路径段）
+func (h *OncallHandler) overrideHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, s *database.OncallSchedule, rest []string) {
+	switch {
+	case r.Method == http.MethodGet && len(rest) == 0:
+		overrides, err := h.svc.ListOncallOverrides(ctx, s.ID)
+		if err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to list overrides")
+			return
+		}
+		now := time.Now()
+		dtos := make([]OverrideDTO, 0, len(overrides))
+		for _, o := range overrides {
+			dtos = append(dtos, toOverrideDTO(o, now))
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+			"overrides": dtos,
+			"total":     len(dtos),
+		})
+
+	case r.Method == http.MethodPost && len(rest) == 0:
+		h.createOverride(ctx, w, r, s)
+
+	case r.Method == http.MethodDelete && len(rest) == 1:
+		oid, err := strconv.ParseInt(rest[0], 10, 64)
+		if err != nil || oid <= 0 {
+			handler.WriteError(w, http.StatusBadRequest, "invalid override id")
+			return
+		}
+		existing, err := h.svc.GetOncallOverride(ctx, oid)
+		if err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to get override")
+			return
+		}
+		if existing == nil || existing.ScheduleID != s.ID {
+			handler.WriteError(w, http.StatusNotFound, "override not found")
+			return
+		}
+		if err := h.svc.DeleteOncallOverride(ctx, oid); err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to delete override")
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]interface{}{"message": "override deleted"})
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// createOverride 创建替班
+func (h *OncallHandler) createOverride(ctx context.Context, w http.ResponseWriter, r *http.Request, s *database.OncallSchedule) {
+	var req OverrideDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	startsAt, err1 := time.Parse(time.RFC3339, req.StartsAt)
+	endsAt, err2 := time.Parse(time.RFC3339, req.EndsAt)
+	if err1 != nil || err2 != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid startsAt or endsAt")
+		return
+	}
+	now := time.Now()
+	if !endsAt.After(startsAt) || !endsAt.After(now) {
+		handler.WriteError(w, http.StatusBadRequest, "endsAt must be after startsAt and now")
+		return
+	}
+	if !h.userExists(ctx, w, req.UserID) {
+		return
+	}
+
+	createdBy, _ := middleware.GetUsername(r.Context())
+	o := &database.OncallOverride{
+		ScheduleID: s.ID,
+		UserID:     req.UserID,
+		StartsAt:   startsAt,
+		EndsAt:     endsAt,
+		Comment:    req.Comment,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+	}
+	if err := h.svc.CreateOncallOverride(ctx, o); err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to create override")
+		return
+	}
+	handler.WriteJSON(w, http.StatusCreated, toOverrideDTO(o, now))
+}
+
+// ==================== 升级策略 ====================
+
+// ListPolicies 列出升级策略
+// GET /api/v2/oncall/policies
+func (h *OncallHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	policies, err := h.svc.ListEscalationPolicies(ctx)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to list policies")
+		return
+	}
+
+	dtos := make([]PolicyDTO, 0, len(policies))
+	for _, p := range policies {
+		dtos = append(dtos, toPolicyDTO(p))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"policies": dtos,
+		"total":    len(dtos),
+	})
+}
+
+// PolicyHandler 单条升级策略操作
+// POST   /api/v2/oncall/policies/      -> 创建
+// PUT    /api/v2/oncall/policies/{id}  -> 更新
+// DELETE /api/v2/oncall/policies/{id}  -> 删除
+func (h *OncallHandler) PolicyHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "/api/v2/oncall/policies/")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch {
+	case r.Method == http.MethodPost && id == 0:
+		var req PolicyDTO
+		if !h.decodePolicy(ctx, w, r, &req) {
+			return
+		}
+		p := fromPolicyDTO(&req)
+		if err := h.svc.CreateEscalationPolicy(ctx, p); err != nil {
+			if strings.Contains(err.Error(), "UNIQUE") {
+				handler.WriteError(w, http.StatusConflict, "policy name already exists")
+				return
+			}
+			handler.WriteError(w, http.StatusInternalServerError, "failed to create policy")
+			return
+		}
+		handler.WriteJSON(w, http.StatusCreated, toPolicyDTO(p))
+
+	case (r.Method == http.MethodPut || r.Method == http.MethodPatch) && id != 0:
+		existing, err := h.svc.GetEscalationPolicy(ctx, id)
+		if err != nil || existing == nil {
+			handler.WriteError(w, http.StatusNotFound, "policy not found")
+			return
+		}
+		var req PolicyDTO
+		if !h.decodePolicy(ctx, w, r, &req) {
+			return
+		}
+		p := fromPolicyDTO(&req)
+		p.ID = id
+		if err := h.svc.UpdateEscalationPolicy(ctx, p); err != nil {
+			if strings.Contains(err.Error(), "UNIQUE") {
+				handler.WriteError(w, http.StatusConflict, "policy name already exists")
+				return
+			}
+			handler.WriteError(w, http.StatusInternalServerError, "failed to update policy")
+			return
+		}
+		p.CreatedAt = existing.CreatedAt
+		p.UpdatedAt = time.Now()
+		handler.WriteJSON(w, http.StatusOK, toPolicyDTO(p))
+
+	case r.Method == http.MethodDelete && id != 0:
+		if err := h.svc.DeleteEscalationPolicy(ctx, id); err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to delete policy")
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]interface{}{"message": "policy deleted"})
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// decodePolicy 解析并校验升级策略请求
+func (h *OncallHandler) decodePolicy(ctx context.Context, w http.ResponseWriter, r *http.Request, req *PolicyDTO) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		handler.WriteError(w, http.StatusBadRequest, "name required")
+		return false
+	}
+	if _, err := notifier.ParseEscalationSteps(marshalJSON(req.Steps, "[]")); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	s, err := h.svc.GetOncallSchedule(ctx, req.ScheduleID)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to get schedule")
+		return false
+	}
+	if s == nil {
+		handler.WriteError(w, http.StatusBadRequest, "schedule not found")
+		return false
+	}
+	return true
+}
+
+// ==================== 升级记录与确认 ====================
+
+// ListEscalations 告警升级记录列表
+// GET /api/v2/oncall/escalations?status=open&limit=50&offset=0
+func (h *OncallHandler) ListEscalations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	opts := database.EscalationQueryOpts{Limit: 50}
+	if status := query.Get("status"); status != "" {
+		if !validEscalationStatus[status] {
+			handler.WriteError(w, http.StatusBadRequest, "invalid status")
+			return
+		}
+		opts.Status = status
+	}
+	if v := query.Get("limit"); v != "" {
+		if limit, err := strconv.Atoi(v); err == nil && limit > 0 {
+			if limit > 200 {
+				limit = 200 // 最大限制
+			}
+			opts.Limit = limit
+		}
+	}
+	if v := query.Get("offset"); v != "" {
+		if offset, err := strconv.Atoi(v); err == nil && offset >= 0 {
+			opts.Offset = offset
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	escalations, err := h.svc.ListEscalations(ctx, opts)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to list escalations")
+		return
+	}
+	total, err := h.svc.CountEscalations(ctx, opts)
+	if err != nil {
+		total = int64(len(escalations))
+	}
+
+	dtos := make([]EscalationDTO, 0, len(escalations))
+	for _, e := range escalations {
+		dtos = append(dtos, toEscalationDTO(e))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"escalations": dtos,
+		"total":       total,
+	})
+}
+
+// EscalationHandler 升级记录详情与确认
+// GET  /api/v2/oncall/escalations/{id}      详情
+// POST /api/v2/oncall/escalations/{id}/ack  确认（停止后续升级步骤）
+func (h *OncallHandler) EscalationHandler(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/oncall/escalations/"), "/")
+	idStr, action, _ := strings.Cut(rest, "/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		handler.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	existing, err := h.svc.GetEscalation(ctx, id)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to get escalation")
+		return
+	}
+	if existing == nil {
+		handler.WriteError(w, http.StatusNotFound, "escalation not found")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && action == "":
+		handler.WriteJSON(w, http.StatusOK, toEscalationDTO(existing))
+
+	case r.Method == http.MethodPost && action == "ack":
+		by, _ := middleware.GetUsername(r.Context())
+		acked, err := h.svc.AckEscalation(ctx, id, by)
+		if err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to ack escalation")
+			return
+		}
+		if !acked {
+			handler.WriteError(w, http.StatusConflict, "escalation is already "+existing.Status)
+			return
+		}
+		updated, err := h.svc.GetEscalation(ctx, id)
+		if err != nil || updated == nil {
+			updated = existing
+		}
+		handler.WriteJSON(w, http.StatusOK, toEscalationDTO(updated))
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// ackPage 确认链接页面
+var ackPage = template.Must(template.New("ack").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>AtlHyper</title></head>
+<body style="font-family: sans-serif; max-width: 560px; margin: 48px auto; padding: 0 16px; color: #111827;">
+{{- if .Escalation}}
+<h2 style="margin-bottom: 4px;">{{.Escalation.Title}}</h2>
+<p style="color: #6b7280; margin-top: 0;">{{.Escalation.Severity}} · L{{.Escalation.Level}}</p>
+{{- end}}
+<p>{{.Message}}</p>
+{{- if .Form}}
+<form method="POST" action="?token={{.Token}}">
+<button type="submit" style="background: #2563eb; color: #fff; border: 0; border-radius: 6px; padding: 10px 20px; font-size: 15px; cursor: pointer;">确认告警</button>
+</form>
+{{- end}}
+</body></html>`))
+
+// ackPageData 确认链接页面数据
+type ackPageData struct {
+	Escalation *database.Escalation
+	Message    string
+	Token      string
+	Form       bool
+}
+
+// AckLink 通过邮件 / Slack 消息中的确认链接确认告警（令牌即凭证，无需登录）
+// GET  /api/v2/oncall/ack?token=xxx  确认页面（避免邮件安全扫描等预取请求误确认）
+// POST /api/v2/oncall/ack?token=xxx  确认（令牌放在查询参数中，不写入审计请求体）
+func (h *OncallHandler) AckLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	token := r.URL.Query().Get("token")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if token == "" {
+		renderAckPage(w, http.StatusBadRequest, ackPageData{Message: "确认链接无效"})
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		e, err := h.svc.GetEscalationByToken(ctx, token)
+		switch {
+		case err != nil:
+			renderAckPage(w, http.StatusInternalServerError, ackPageData{Message: "查询告警失败，请稍后重试"})
+		case e == nil:
+			renderAckPage(w, http.StatusNotFound, ackPageData{Message: "确认链接无效或已过期"})
+		case e.Status != database.EscalationOpen:
+			renderAckPage(w, http.StatusOK, ackPageData{Escalation: e, Message: ackStatusMessage(e)})
+		default:
+			renderAckPage(w, http.StatusOK, ackPageData{Escalation: e, Message: "确认后将停止后续升级通知。", Token: token, Form: true})
+		}
+		return
+	}
+
+	e, acked, err := h.svc.AckEscalationByToken(ctx, token)
+	if e != nil {
+		middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"escalationId":%d,"acked":%t}`, e.ID, acked))
+	}
+	switch {
+	case err != nil:
+		renderAckPage(w, http.StatusInternalServerError, ackPageData{Message: "确认失败，请稍后重试"})
+	case e == nil:
+		renderAckPage(w, http.StatusNotFound, ackPageData{Message: "确认链接无效或已过期"})
+	case acked:
+		renderAckPage(w, http.StatusOK, ackPageData{Escalation: e, Message: "已确认，后续升级通知已停止。"})
+	default:
+		renderAckPage(w, http.StatusOK, ackPageData{Escalation: e, Message: ackStatusMessage(e)})
+	}
+}
+
+// ackStatusMessage 已结束升级的提示
+func ackStatusMessage(e *database.Escalation) string {
+	if e.Status == database.EscalationResolved {
+		return "告警已恢复，无需确认。"
+	}
+	if e.AckedBy != "" {
+		return "告警已由 " + e.AckedBy + " 确认。"
+	}
+	return "告警已确认。"
+}
+
+func renderAckPage(w http.ResponseWriter, status int, data ackPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = ackPage.Execute(w, data)
+}
+
+// ==================== 辅助函数 ====================
+
+// userExists 校验用户存在（不存在时写入 400）
+func (h *OncallHandler) userExists(ctx context.Context, w http.ResponseWriter, id int64) bool {
+	u, err := h.userRepo.GetByID(ctx, id)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "failed to get user")
+		return false
+	}
+	if u == nil {
+		handler.WriteError(w, http.StatusBadRequest, "user not found: "+strconv.FormatInt(id, 10))
+		return false
+	}
+	return true
+}
+
+// oncallUser 查询值班人员（0 或不存在返回 nil）
+func (h *OncallHandler) oncallUser(ctx context.Context, id int64) *OncallUserDTO {
+	if id == 0 {
+		return nil
+	}
+	dto := &OncallUserDTO{ID: id}
+	if u, err := h.userRepo.GetByID(ctx, id); err == nil && u != nil {
+		dto.Username = u.Username
+		dto.DisplayName = u.DisplayName
+	}
+	return dto
+}
+
+func (h *OncallHandler) toScheduleDTO(ctx context.Context, s *database.OncallSchedule, at time.Time) ScheduleDTO {
+	dto := ScheduleDTO{
+		ID:          s.ID,
+		Name:        s.Name,
+		Description: s.Description,
+		Rotation:    s.Rotation,
+		StartAt:     formatTime(s.StartAt),
+		Members:     []int64{},
+		CreatedAt:   formatTime(s.CreatedAt),
+		UpdatedAt:   formatTime(s.UpdatedAt),
+	}
+	_ = json.Unmarshal([]byte(s.Members), &dto.Members)
+
+	shift, err := h.svc.CurrentOncall(ctx, s.ID, at)
+	if err != nil || shift == nil {
+		return dto
+	}
+	dto.Current = &OncallShiftDTO{
+		Primary:       h.oncallUser(ctx, shift.Primary),
+		Secondary:     h.oncallUser(ctx, shift.Secondary),
+		Scheduled:     h.oncallUser(ctx, shift.Scheduled),
+		OverrideID:    shift.OverrideID,
+		ShiftStartsAt: formatTime(shift.StartsAt),
+		ShiftEndsAt:   formatTime(shift.EndsAt),
+	}
+	return dto
+}
+
+func fromScheduleDTO(dto *ScheduleDTO) *database.OncallSchedule {
+	startAt, _ := time.Parse(time.RFC3339, dto.StartAt)
+	return &database.OncallSchedule{
+		Name:        strings.TrimSpace(dto.Name),
+		Description: dto.Description,
+		Rotation:    dto.Rotation,
+		StartAt:     startAt,
+		Members:     marshalJSON(dto.Members, "[]"),
+	}
+}
+
+func toOverrideDTO(o *database.OncallOverride, now time.Time) OverrideDTO {
+	return OverrideDTO{
+		ID:         o.ID,
+		ScheduleID: o.ScheduleID,
+		UserID:     o.UserID,
+		StartsAt:   formatTime(o.StartsAt),
+		EndsAt:     formatTime(o.EndsAt),
+		Comment:    o.Comment,
+		CreatedBy:  o.CreatedBy,
+		CreatedAt:  formatTime(o.CreatedAt),
+		Active:     !now.Before(o.StartsAt) && now.Before(o.EndsAt),
+	}
+}
+
+func toPolicyDTO(p *database.EscalationPolicy) PolicyDTO {
+	dto := PolicyDTO{
+		ID:         p.ID,
+		Name:       p.Name,
+		Enabled:    p.Enabled,
+		Matchers:   map[string]string{},
+		ScheduleID: p.ScheduleID,
+		Steps:      []database.EscalationStep{},
+		CreatedAt:  formatTime(p.CreatedAt),
+		UpdatedAt:  formatTime(p.UpdatedAt),
+	}
+	_ = json.Unmarshal([]byte(p.Matchers), &dto.Matchers)
+	_ = json.Unmarshal([]byte(p.Steps), &dto.Steps)
+	return dto
+}
+
+func fromPolicyDTO(dto *PolicyDTO) *database.EscalationPolicy {
+	return &database.EscalationPolicy{
+		Name:       strings.TrimSpace(dto.Name),
+		Enabled:    dto.Enabled,
+		Matchers:   marshalJSON(dto.Matchers, "{}"),
+		ScheduleID: dto.ScheduleID,
+		Steps:      marshalJSON(dto.Steps, "[]"),
+	}
+}
+
+func toEscalationDTO(e *database.Escalation) EscalationDTO {
+	dto := EscalationDTO{
+		ID:           e.ID,
+		PolicyID:     e.PolicyID,
+		AlertKey:     e.AlertKey,
+		TemplateName: e.TemplateName,
+		Title:        e.Title,
+		Severity:     e.Severity,
+		Level:        e.Level,
+		Status:       e.Status,
+		AckedBy:      e.AckedBy,
+		CreatedAt:    formatTime(e.CreatedAt),
+		UpdatedAt:    formatTime(e.UpdatedAt),
+	}
+	if e.AckedAt != nil {
+		dto.AckedAt = formatTime(*e.AckedAt)
+	}
+	if e.NextAt != nil && e.Status == database.EscalationOpen {
+		dto.NextAt = formatTime(*e.NextAt)
+	}
+	return dto
+}
@@ -0,0 +1,195 @@
+// atlhyper_master_v2/gateway/handler/admin/role_binding.go
+// 角色绑定管理 Handler（按集群/命名空间授权）
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/rbac"
+)
+
+// RoleBindingHandler 角色绑定 Handler
+type RoleBindingHandler struct {
+	bindingRepo database.RoleBindingRepository
+	userRepo    database.UserRepository
+	resolver    *rbac.Resolver
+}
+
+// NewRoleBindingHandler 创建 RoleBindingHandler
+func NewRoleBindingHandler(bindingRepo database.RoleBindingRepository, userRepo database.UserRepository, resolver *rbac.Resolver) *RoleBindingHandler {
+	return &RoleBindingHandler{bindingRepo: bindingRepo, userRepo: userRepo, resolver: resolver}
+}
+
+// RoleBindingDTO 角色绑定
+type RoleBindingDTO struct {
+	ID        int64  `json:"id"`
+	UserID    int64  `json:"userId"`
+	ClusterID string `json:"clusterId"` // 空 = 全部集群
+	Namespace string `json:"namespace"` // 空 = 全部命名空间（含集群级资源）
+	Role      int    `json:"role"`
+	CreatedBy int64  `json:"createdBy"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// CreateRoleBindingRequest 创建角色绑定请求
+type CreateRoleBindingRequest struct {
+	UserID    int64  `json:"userId"`
+	ClusterID string `json:"clusterId"`
+	Namespace string `json:"namespace"`
+	Role      int    `json:"role"`
+}
+
+// List 列出角色绑定
+// GET /api/v2/role-bindings?user_id=xxx
+func (h *RoleBindingHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var (
+		bindings []*database.RoleBinding
+		err      error
+	)
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		userID, perr := strconv.ParseInt(v, 10, 64)
+		if perr != nil {
+			handler.WriteError(w, http.StatusBadRequest, "user_id 无效")
+			return
+		}
+		bindings, err = h.bindingRepo.ListByUser(r.Context(), userID)
+	} else {
+		bindings, err = h.bindingRepo.List(r.Context())
+	}
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "查询角色绑定失败")
+		return
+	}
+
+	dtos := make([]RoleBindingDTO, 0, len(bindings))
+	for _, b := range bindings {
+		dtos = append(dtos, toRoleBindingDTO(b))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "获取成功",
+		"data":    dtos,
+		"total":   len(dtos),
+	})
+}
+
+// BindingHandler 单条角色绑定操作
+// POST   /api/v2/role-bindings/      -> 创建
+// DELETE /api/v2/role-bindings/{id}  -> 删除
+func (h *RoleBindingHandler) BindingHandler(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/role-bindings/"), "/")
+
+	switch {
+	case r.Method == http.MethodPost && idStr == "":
+		h.create(w, r)
+	case r.Method == http.MethodDelete && idStr != "":
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			handler.WriteError(w, http.StatusBadRequest, "绑定 ID 无效")
+			return
+		}
+		h.delete(w, r, id)
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *RoleBindingHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req CreateRoleBindingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "请求参数无效")
+		return
+	}
+	if req.UserID == 0 {
+		handler.WriteError(w, http.StatusBadRequest, "用户 ID 不能为空")
+		return
+	}
+	// Admin 是全局角色，不能按范围授予
+	if req.Role != rbac.RoleViewer && req.Role != rbac.RoleOperator {
+		handler.WriteError(w, http.StatusBadRequest, "role 只能为 1（Viewer）或 2（Operator）")
+		return
+	}
+	if req.Namespace != "" && req.ClusterID == "" {
+		handler.WriteError(w, http.StatusBadRequest, "指定 namespace 时必须指定 clusterId")
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), req.UserID)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "查询用户失败")
+		return
+	}
+	if user == nil {
+		handler.WriteError(w, http.StatusNotFound, "用户不存在")
+		return
+	}
+
+	operatorID, _ := middleware.GetUserID(r.Context())
+	binding := &database.RoleBinding{
+		UserID:    req.UserID,
+		ClusterID: req.ClusterID,
+		Namespace: req.Namespace,
+		Role:      req.Role,
+		CreatedBy: operatorID,
+		CreatedAt: time.Now(),
+	}
+	if err := h.bindingRepo.Create(r.Context(), binding); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			handler.WriteError(w, http.StatusConflict, "该用户在此范围已有绑定")
+			return
+		}
+		handler.WriteError(w, http.StatusInternalServerError, "创建角色绑定失败")
+		return
+	}
+	h.resolver.Invalidate(req.UserID)
+
+	handler.WriteJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "角色绑定创建成功",
+		"data":    toRoleBindingDTO(binding),
+	})
+}
+
+func (h *RoleBindingHandler) delete(w http.ResponseWriter, r *http.Request, id int64) {
+	binding, err := h.bindingRepo.GetByID(r.Context(), id)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "查询角色绑定失败")
+		return
+	}
+	if binding == nil {
+		handler.WriteError(w, http.StatusNotFound, "角色绑定不存在")
+		return
+	}
+
+	if err := h.bindingRepo.Delete(r.Context(), id); err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "删除角色绑定失败")
+		return
+	}
+	h.resolver.Invalidate(binding.UserID)
+
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "角色绑定删除成功",
+	})
+}
+
+func toRoleBindingDTO(b *database.RoleBinding) RoleBindingDTO {
+	return RoleBindingDTO{
+		ID:        b.ID,
+		UserID:    b.UserID,
+		ClusterID: b.ClusterID,
+		Namespace: b.Namespace,
+		Role:      b.Role,
+		CreatedBy: b.CreatedBy,
+		CreatedAt: b.CreatedAt.Format(time.RFC3339),
+	}
+}
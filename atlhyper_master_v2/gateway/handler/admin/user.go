@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"AtlHyper/atlhyper_master_v2/config"
 	"AtlHyper/atlhyper_master_v2/gateway/handler"
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/gateway/middleware"
@@ -18,12 +19,14 @@ var log = logger.Module("UserHandler")
 
 // UserHandler 用户管理 Handler
 type UserHandler struct {
-	userRepo database.UserRepository
+	userRepo     database.UserRepository
+	bindingRepo  database.RoleBindingRepository
+	identityRepo database.UserIdentityRepository
 }
 
 // NewUserHandler 创建 UserHandler
-func NewUserHandler(userRepo database.UserRepository) *UserHandler {
-	return &UserHandler{userRepo: userRepo}
+func NewUserHandler(userRepo database.UserRepository, bindingRepo database.RoleBindingRepository, identityRepo database.UserIdentityRepository) *UserHandler {
+	return &UserHandler{userRepo: userRepo, bindingRepo: bindingRepo, identityRepo: identityRepo}
 }
 
 // ==================== 请求/响应结构 ====================
@@ -131,6 +134,18 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 启用 SSO 后本地密码登录仅保留给 Admin（IdP 不可用时的应急入口）
+	oidcCfg := config.GlobalConfig.OIDC
+	if oidcCfg.Enabled && oidcCfg.LocalAdminOnly && user.Role < middleware.RoleAdmin {
+		handler.WriteError(w, http.StatusForbidden, "请使用 SSO 登录")
+		return
+	}
+
+	writeLoginResponse(w, r, h.userRepo, user)
+}
+
+// writeLoginResponse 签发 Token、记录登录时间并写入登录响应（密码登录与 SSO 登录共用）
+func writeLoginResponse(w http.ResponseWriter, r *http.Request, userRepo database.UserRepository, user *database.User) {
 	// 生成 Token
 	token, err := middleware.GenerateToken(user.ID, user.Username, user.Role)
 	if err != nil {
@@ -143,7 +158,7 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	if xForwardedFor := r.Header.Get("X-Forwarded-For"); xForwardedFor != "" {
 		clientIP = xForwardedFor
 	}
-	_ = h.userRepo.UpdateLastLogin(r.Context(), user.ID, clientIP)
+	_ = userRepo.UpdateLastLogin(r.Context(), user.ID, clientIP)
 
 	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
 		"message": "登录成功",
@@ -366,6 +381,12 @@ func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		handler.WriteError(w, http.StatusInternalServerError, "删除用户失败")
 		return
 	}
+	if err := h.bindingRepo.DeleteByUser(r.Context(), req.UserID); err != nil {
+		log.Warn("清理用户角色绑定失败", "userID", req.UserID, "err", err)
+	}
+	if err := h.identityRepo.DeleteByUser(r.Context(), req.UserID); err != nil {
+		log.Warn("清理用户外部身份失败", "userID", req.UserID, "err", err)
+	}
 
 	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
 		"message": "用户删除成功",
@@ -3,9 +3,11 @@
 package aiops
 
 import (
+	"errors"
 	"net/http"
 
 	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/rbac"
 	"AtlHyper/atlhyper_master_v2/service"
 )
 
@@ -40,6 +42,10 @@ func (h *AIOpsBaselineHandler) Baseline(w http.ResponseWriter, r *http.Request)
 	}
 
 	baseline, err := h.svc.GetAIOpsBaseline(r.Context(), clusterID, entityKey)
+	if errors.Is(err, rbac.ErrForbidden) {
+		handler.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
 	if err != nil {
 		handler.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
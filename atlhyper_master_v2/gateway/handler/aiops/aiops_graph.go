@@ -3,10 +3,12 @@
 package aiops
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/rbac"
 	"AtlHyper/atlhyper_master_v2/service"
 )
 
@@ -35,6 +37,10 @@ func (h *AIOpsGraphHandler) Graph(w http.ResponseWriter, r *http.Request) {
 	}
 
 	graph, err := h.svc.GetAIOpsGraph(r.Context(), clusterID)
+	if errors.Is(err, rbac.ErrForbidden) {
+		handler.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
 	if err != nil {
 		handler.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -76,6 +82,10 @@ func (h *AIOpsGraphHandler) Trace(w http.ResponseWriter, r *http.Request) {
 	}
 
 	result, err := h.svc.GetAIOpsGraphTrace(r.Context(), clusterID, fromKey, direction, maxDepth)
+	if errors.Is(err, rbac.ErrForbidden) {
+		handler.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
 	if err != nil {
 		handler.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
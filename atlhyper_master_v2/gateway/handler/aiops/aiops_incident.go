@@ -9,18 +9,29 @@ import (
 	"time"
 
 	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/gateway/handler"
 	"AtlHyper/atlhyper_master_v2/service"
 )
 
 // AIOpsIncidentHandler AIOps 事件 Handler
 type AIOpsIncidentHandler struct {
-	svc service.Query
+	svc      service.Service
+	userRepo database.UserRepository
+
+	// action 人工处理入口（已包装权限与审计），详情路径上的 POST 转发至此
+	action http.HandlerFunc
 }
 
 // NewAIOpsIncidentHandler 创建 Handler
-func NewAIOpsIncidentHandler(svc service.Query) *AIOpsIncidentHandler {
-	return &AIOpsIncidentHandler{svc: svc}
+func NewAIOpsIncidentHandler(svc service.Service, userRepo database.UserRepository) *AIOpsIncidentHandler {
+	return &AIOpsIncidentHandler{svc: svc, userRepo: userRepo}
+}
+
+// SetActionHandler 设置人工处理入口
+// 详情路由注册在公开路由上，写操作需由调用方包装 Operator 权限与审计
+func (h *AIOpsIncidentHandler) SetActionHandler(fn http.HandlerFunc) {
+	h.action = fn
 }
 
 // List 事件列表
@@ -78,7 +89,12 @@ func (h *AIOpsIncidentHandler) List(w http.ResponseWriter, r *http.Request) {
 
 // Detail 事件详情
 // GET /api/v2/aiops/incidents/{id}
+// POST /api/v2/aiops/incidents/{id}/{action} 转发至人工处理入口
 func (h *AIOpsIncidentHandler) Detail(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && h.action != nil {
+		h.action(w, r)
+		return
+	}
 	if r.Method != http.MethodGet {
 		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
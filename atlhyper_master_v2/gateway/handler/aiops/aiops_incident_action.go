@@ -0,0 +1,131 @@
+// atlhyper_master_v2/gateway/handler/aiops_incident_action.go
+// AIOps 事件人工处理 API Handler（确认 / 指派 / 评论 / 解决 / 误报）
+package aiops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/rbac"
+)
+
+// maxIncidentCommentLen 评论 / 备注最大长度（字符）
+const maxIncidentCommentLen = 2000
+
+// incidentActions URL 动作名 → 处理动作类型
+var incidentActions = map[string]aiops.IncidentActionType{
+	"ack":            aiops.IncidentActionAck,
+	"assign":         aiops.IncidentActionAssign,
+	"comment":        aiops.IncidentActionComment,
+	"resolve":        aiops.IncidentActionResolve,
+	"false-positive": aiops.IncidentActionFalsePositive,
+}
+
+// IncidentActionRequest 人工处理请求体
+type IncidentActionRequest struct {
+	Assignee string `json:"assignee"` // assign 必填：被指派用户名
+	Comment  string `json:"comment"`  // comment 必填，其他动作为可选备注
+}
+
+// Action 事件人工处理
+// POST /api/v2/aiops/incidents/{id}/ack
+// POST /api/v2/aiops/incidents/{id}/assign          {"assignee": "alice", "comment": "..."}
+// POST /api/v2/aiops/incidents/{id}/comment         {"comment": "..."}
+// POST /api/v2/aiops/incidents/{id}/resolve         {"comment": "..."}
+// POST /api/v2/aiops/incidents/{id}/false-positive  {"comment": "..."}
+func (h *AIOpsIncidentHandler) Action(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	id, name, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v2/aiops/incidents/"), "/")
+	if !ok || id == "" || name == "" {
+		handler.WriteError(w, http.StatusNotFound, "unknown incident action")
+		return
+	}
+	actionType, ok := incidentActions[name]
+	if !ok {
+		handler.WriteError(w, http.StatusNotFound, "unknown incident action: "+name)
+		return
+	}
+
+	var req IncidentActionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	req.Assignee = strings.TrimSpace(req.Assignee)
+	req.Comment = strings.TrimSpace(req.Comment)
+	if utf8.RuneCountInString(req.Comment) > maxIncidentCommentLen {
+		handler.WriteError(w, http.StatusBadRequest, fmt.Sprintf("comment exceeds %d characters", maxIncidentCommentLen))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch actionType {
+	case aiops.IncidentActionComment:
+		if req.Comment == "" {
+			handler.WriteError(w, http.StatusBadRequest, "comment is required")
+			return
+		}
+	case aiops.IncidentActionAssign:
+		if req.Assignee == "" {
+			handler.WriteError(w, http.StatusBadRequest, "assignee is required")
+			return
+		}
+		u, err := h.userRepo.GetByUsername(ctx, req.Assignee)
+		if err != nil {
+			handler.WriteError(w, http.StatusInternalServerError, "failed to get user")
+			return
+		}
+		if u == nil {
+			handler.WriteError(w, http.StatusBadRequest, "user not found: "+req.Assignee)
+			return
+		}
+	}
+
+	actor, _ := middleware.GetUsername(r.Context())
+	middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"incidentId":%q,"action":%q}`, id, name))
+
+	detail, err := h.svc.ApplyAIOpsIncidentAction(ctx, id, &aiops.IncidentAction{
+		Type:     actionType,
+		Actor:    actor,
+		Assignee: req.Assignee,
+		Comment:  req.Comment,
+	})
+	switch {
+	case errors.Is(err, rbac.ErrForbidden):
+		handler.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, aiops.ErrIncidentNotFound):
+		handler.WriteError(w, http.StatusNotFound, "incident not found")
+		return
+	case errors.Is(err, aiops.ErrIncidentResolved),
+		errors.Is(err, aiops.ErrIncidentAcked),
+		errors.Is(err, aiops.ErrFalsePositive):
+		handler.WriteError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		handler.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	handler.WriteJSON(w, http.StatusOK, map[string]any{
+		"message": "操作成功",
+		"data":    ScaleIncidentDetail(detail),
+	})
+}
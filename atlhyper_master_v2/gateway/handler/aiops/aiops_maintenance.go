@@ -0,0 +1,146 @@
+// atlhyper_master_v2/gateway/handler/aiops_maintenance.go
+// AIOps 维护窗口 API Handler（列表 / 创建 / 修改 / 立即结束）
+package aiops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/rbac"
+	"AtlHyper/atlhyper_master_v2/service"
+)
+
+// maxMaintenanceSize 维护窗口请求体上限
+const maxMaintenanceSize = 16 * 1024
+
+// AIOpsMaintenanceHandler AIOps 维护窗口 Handler
+type AIOpsMaintenanceHandler struct {
+	svc service.Service
+}
+
+// NewAIOpsMaintenanceHandler 创建 Handler
+func NewAIOpsMaintenanceHandler(svc service.Service) *AIOpsMaintenanceHandler {
+	return &AIOpsMaintenanceHandler{svc: svc}
+}
+
+// List 维护窗口列表（含已结束，active 标注当前是否生效）
+// GET /api/v2/aiops/maintenance-windows
+func (h *AIOpsMaintenanceHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	windows, err := h.svc.ListAIOpsMaintenanceWindows(r.Context())
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if windows == nil {
+		windows = []*maintenance.Window{}
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]any{
+		"message": "获取成功",
+		"data":    windows,
+		"total":   len(windows),
+	})
+}
+
+// Handler 单个维护窗口操作
+// POST   /api/v2/aiops/maintenance-windows/      创建
+// PUT    /api/v2/aiops/maintenance-windows/{id}  修改
+// DELETE /api/v2/aiops/maintenance-windows/{id}  立即结束（保留记录）
+func (h *AIOpsMaintenanceHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	var id int64
+	if idStr := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/aiops/maintenance-windows/"), "/"); idStr != "" {
+		n, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil || n <= 0 {
+			handler.WriteError(w, http.StatusBadRequest, "invalid window id")
+			return
+		}
+		id = n
+	}
+	by, _ := middleware.GetUsername(r.Context())
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch {
+	case r.Method == http.MethodPost && id == 0:
+		win, ok := decodeWindow(w, r)
+		if !ok {
+			return
+		}
+		middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"name":%q,"cluster":%q}`, win.Name, win.ClusterID))
+		created, err := h.svc.CreateAIOpsMaintenanceWindow(ctx, win, by)
+		if err != nil {
+			writeMaintenanceError(w, err)
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]any{
+			"message": "创建成功",
+			"data":    created,
+		})
+
+	case r.Method == http.MethodPut && id != 0:
+		win, ok := decodeWindow(w, r)
+		if !ok {
+			return
+		}
+		win.ID = id
+		middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"id":%d,"name":%q}`, id, win.Name))
+		updated, err := h.svc.UpdateAIOpsMaintenanceWindow(ctx, win, by)
+		if err != nil {
+			writeMaintenanceError(w, err)
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]any{
+			"message": "保存成功",
+			"data":    updated,
+		})
+
+	case r.Method == http.MethodDelete && id != 0:
+		middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"id":%d}`, id))
+		if err := h.svc.CloseAIOpsMaintenanceWindow(ctx, id); err != nil {
+			writeMaintenanceError(w, err)
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]any{"message": "维护窗口已结束"})
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// decodeWindow 解析请求体（失败时已写入 400）
+func decodeWindow(w http.ResponseWriter, r *http.Request) (*maintenance.Window, bool) {
+	var win maintenance.Window
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMaintenanceSize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&win); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return nil, false
+	}
+	return &win, true
+}
+
+// writeMaintenanceError 映射维护窗口错误码
+func writeMaintenanceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, maintenance.ErrInvalidWindow):
+		handler.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, maintenance.ErrWindowNotFound):
+		handler.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, rbac.ErrForbidden):
+		handler.WriteError(w, http.StatusForbidden, err.Error())
+	default:
+		handler.WriteError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,177 @@
+// atlhyper_master_v2/gateway/handler/aiops_policy.go
+// AIOps 策略 API Handler（列表 / 生效配置预览 / 版本历史 / 保存 / 删除 / 回滚）
+package aiops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops/policy"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/service"
+)
+
+// maxPolicySize 策略请求体上限
+const maxPolicySize = 64 * 1024
+
+// AIOpsPolicyHandler AIOps 策略 Handler
+type AIOpsPolicyHandler struct {
+	svc service.Service
+}
+
+// NewAIOpsPolicyHandler 创建 Handler
+func NewAIOpsPolicyHandler(svc service.Service) *AIOpsPolicyHandler {
+	return &AIOpsPolicyHandler{svc: svc}
+}
+
+// RollbackPolicyRequest 回滚请求体
+type RollbackPolicyRequest struct {
+	Version int `json:"version"`
+}
+
+// List 各策略当前版本
+// GET /api/v2/aiops/policies
+func (h *AIOpsPolicyHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	policies, err := h.svc.ListAIOpsPolicies(r.Context())
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if policies == nil {
+		policies = []*policy.Policy{}
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]any{
+		"message": "获取成功",
+		"data":    policies,
+		"total":   len(policies),
+	})
+}
+
+// Effective 叠加后的生效配置（entity_key 为空返回集群级配置）
+// GET /api/v2/aiops/effective-policy?cluster_id=xxx&entity_key=prod/pod/api-xxx
+func (h *AIOpsPolicyHandler) Effective(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	clusterID := r.URL.Query().Get("cluster_id")
+	if clusterID == "" {
+		handler.WriteError(w, http.StatusBadRequest, "cluster_id is required")
+		return
+	}
+	eff, err := h.svc.GetAIOpsEffectivePolicy(r.Context(), clusterID, r.URL.Query().Get("entity_key"))
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]any{
+		"message": "获取成功",
+		"data":    eff,
+	})
+}
+
+// Handler 单个策略的版本历史与修改
+// GET    /api/v2/aiops/policies/{name}/versions
+// PUT    /api/v2/aiops/policies/{name}            {"version": 当前版本（可选，乐观锁）, "namespace": "...", "spec": {...}}
+// DELETE /api/v2/aiops/policies/{name}
+// POST   /api/v2/aiops/policies/{name}/rollback   {"version": 3}
+func (h *AIOpsPolicyHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	name, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/aiops/policies/"), "/"), "/")
+	if name == "" {
+		handler.WriteError(w, http.StatusNotFound, "policy name is required")
+		return
+	}
+	by, _ := middleware.GetUsername(r.Context())
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch {
+	case r.Method == http.MethodGet && action == "versions":
+		versions, err := h.svc.GetAIOpsPolicyHistory(ctx, name)
+		if err != nil {
+			writePolicyError(w, err)
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]any{
+			"message": "获取成功",
+			"data":    versions,
+			"total":   len(versions),
+		})
+
+	case r.Method == http.MethodPut && action == "":
+		var p policy.Policy
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPolicySize))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&p); err != nil {
+			handler.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if p.Name != "" && p.Name != name {
+			handler.WriteError(w, http.StatusBadRequest, "policy name in body does not match path")
+			return
+		}
+		p.Name = name
+		middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"policy":%q,"baseVersion":%d}`, name, p.Version))
+		saved, err := h.svc.SaveAIOpsPolicy(ctx, &p, by)
+		if err != nil {
+			writePolicyError(w, err)
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]any{
+			"message": "保存成功",
+			"data":    saved,
+		})
+
+	case r.Method == http.MethodDelete && action == "":
+		middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"policy":%q}`, name))
+		if err := h.svc.DeleteAIOpsPolicy(ctx, name, by); err != nil {
+			writePolicyError(w, err)
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]any{"message": "删除成功"})
+
+	case r.Method == http.MethodPost && action == "rollback":
+		var req RollbackPolicyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Version <= 0 {
+			handler.WriteError(w, http.StatusBadRequest, "version is required")
+			return
+		}
+		middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"policy":%q,"rollbackTo":%d}`, name, req.Version))
+		saved, err := h.svc.RollbackAIOpsPolicy(ctx, name, req.Version, by)
+		if err != nil {
+			writePolicyError(w, err)
+			return
+		}
+		handler.WriteJSON(w, http.StatusOK, map[string]any{
+			"message": "回滚成功",
+			"data":    saved,
+		})
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// writePolicyError 映射策略错误码
+func writePolicyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, policy.ErrInvalidPolicy):
+		handler.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, policy.ErrPolicyNotFound):
+		handler.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, policy.ErrVersionConflict):
+		handler.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		handler.WriteError(w, http.StatusInternalServerError, err.Error())
+	}
+}
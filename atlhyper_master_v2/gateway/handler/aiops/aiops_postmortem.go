@@ -0,0 +1,151 @@
+// atlhyper_master_v2/gateway/handler/aiops_postmortem.go
+// AIOps 事件复盘文档 API Handler（查看 / 生成 / 编辑）
+package aiops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/rbac"
+	"AtlHyper/atlhyper_master_v2/service"
+)
+
+// maxPostmortemLen 复盘文档最大长度（字符）
+const maxPostmortemLen = 100000
+
+// 生成涉及 LLM 调用，超时需覆盖 AI 撰写且低于 Gateway WriteTimeout（180s）
+const postmortemGenerateTimeout = 2 * time.Minute
+
+// AIOpsPostmortemHandler 事件复盘文档 Handler
+type AIOpsPostmortemHandler struct {
+	svc service.Service
+}
+
+// NewAIOpsPostmortemHandler 创建 Handler
+func NewAIOpsPostmortemHandler(svc service.Service) *AIOpsPostmortemHandler {
+	return &AIOpsPostmortemHandler{svc: svc}
+}
+
+// PostmortemResponse 复盘文档响应
+type PostmortemResponse struct {
+	IncidentID   string `json:"incidentId"`
+	ClusterID    string `json:"clusterId"`
+	Content      string `json:"content"`
+	Source       string `json:"source"`
+	ProviderName string `json:"providerName,omitempty"`
+	Model        string `json:"model,omitempty"`
+	GeneratedBy  string `json:"generatedBy"`
+	UpdatedBy    string `json:"updatedBy"`
+	CreatedAt    string `json:"createdAt"`
+	UpdatedAt    string `json:"updatedAt"`
+}
+
+// UpdatePostmortemRequest 编辑复盘文档请求体
+type UpdatePostmortemRequest struct {
+	Content string `json:"content"`
+}
+
+// Handler 事件复盘文档
+// GET  /api/v2/aiops/postmortems/{incidentId}  查看
+// POST /api/v2/aiops/postmortems/{incidentId}  生成（覆盖已有文档）
+// PUT  /api/v2/aiops/postmortems/{incidentId}  保存编辑 {"content": "..."}
+func (h *AIOpsPostmortemHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/aiops/postmortems/"), "/")
+	if id == "" || strings.Contains(id, "/") {
+		handler.WriteError(w, http.StatusBadRequest, "invalid incident id")
+		return
+	}
+	by, _ := middleware.GetUsername(r.Context())
+
+	var (
+		pm  *database.AIOpsPostmortem
+		err error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+		pm, err = h.svc.GetAIOpsPostmortem(ctx, id)
+		if err == nil && pm == nil {
+			err = aiops.ErrPostmortemNotFound
+		}
+
+	case http.MethodPost:
+		middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"incidentId":%q,"action":"generate"}`, id))
+		ctx, cancel := context.WithTimeout(r.Context(), postmortemGenerateTimeout)
+		defer cancel()
+		pm, err = h.svc.GenerateAIOpsPostmortem(ctx, id, by)
+
+	case http.MethodPut:
+		var req UpdatePostmortemRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if strings.TrimSpace(req.Content) == "" {
+			handler.WriteError(w, http.StatusBadRequest, "content is required")
+			return
+		}
+		if utf8.RuneCountInString(req.Content) > maxPostmortemLen {
+			handler.WriteError(w, http.StatusBadRequest, fmt.Sprintf("content exceeds %d characters", maxPostmortemLen))
+			return
+		}
+		middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"incidentId":%q,"action":"edit"}`, id))
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+		pm, err = h.svc.UpdateAIOpsPostmortem(ctx, id, req.Content, by)
+
+	default:
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	switch {
+	case errors.Is(err, rbac.ErrForbidden):
+		handler.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, aiops.ErrIncidentNotFound):
+		handler.WriteError(w, http.StatusNotFound, "incident not found")
+		return
+	case errors.Is(err, aiops.ErrPostmortemNotFound):
+		handler.WriteError(w, http.StatusNotFound, "postmortem not found")
+		return
+	case err != nil:
+		handler.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	handler.WriteJSON(w, http.StatusOK, map[string]any{
+		"message": "操作成功",
+		"data":    toPostmortemResponse(pm),
+	})
+}
+
+// toPostmortemResponse 转换为 API 响应
+func toPostmortemResponse(pm *database.AIOpsPostmortem) *PostmortemResponse {
+	if pm == nil {
+		return nil
+	}
+	return &PostmortemResponse{
+		IncidentID:   pm.IncidentID,
+		ClusterID:    pm.ClusterID,
+		Content:      pm.Content,
+		Source:       pm.Source,
+		ProviderName: pm.ProviderName,
+		Model:        pm.Model,
+		GeneratedBy:  pm.GeneratedBy,
+		UpdatedBy:    pm.UpdatedBy,
+		CreatedAt:    pm.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    pm.UpdatedAt.Format(time.RFC3339),
+	}
+}
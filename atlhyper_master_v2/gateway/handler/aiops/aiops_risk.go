@@ -3,10 +3,12 @@
 package aiops
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/rbac"
 	"AtlHyper/atlhyper_master_v2/service"
 )
 
@@ -35,6 +37,10 @@ func (h *AIOpsRiskHandler) ClusterRisk(w http.ResponseWriter, r *http.Request) {
 	}
 
 	risk, err := h.svc.GetAIOpsClusterRisk(r.Context(), clusterID)
+	if errors.Is(err, rbac.ErrForbidden) {
+		handler.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
 	if err != nil {
 		handler.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -70,6 +76,10 @@ func (h *AIOpsRiskHandler) EntityRisks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	risks, err := h.svc.GetAIOpsEntityRisks(r.Context(), clusterID, sortBy, limit)
+	if errors.Is(err, rbac.ErrForbidden) {
+		handler.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
 	if err != nil {
 		handler.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -99,6 +109,10 @@ func (h *AIOpsRiskHandler) EntityRisk(w http.ResponseWriter, r *http.Request) {
 	}
 
 	detail, err := h.svc.GetAIOpsEntityRisk(r.Context(), clusterID, entityKey)
+	if errors.Is(err, rbac.ErrForbidden) {
+		handler.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
 	if err != nil {
 		handler.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -0,0 +1,319 @@
+// atlhyper_master_v2/gateway/handler/aiops_runbook.go
+// AIOps Runbook API Handler（定义列表 / 执行记录 / 手动触发 / 审批）
+package aiops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/aiops/runbook"
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/rbac"
+	"AtlHyper/atlhyper_master_v2/service"
+)
+
+// maxRejectReasonLen 拒绝原因最大长度（字符）
+const maxRejectReasonLen = 500
+
+// AIOpsRunbookHandler Runbook Handler
+type AIOpsRunbookHandler struct {
+	svc service.Service
+}
+
+// NewAIOpsRunbookHandler 创建 Handler
+func NewAIOpsRunbookHandler(svc service.Service) *AIOpsRunbookHandler {
+	return &AIOpsRunbookHandler{svc: svc}
+}
+
+// RunbookResponse Runbook 定义响应（时长转为字符串）
+type RunbookResponse struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	File        string        `json:"file"`
+	Mode        string        `json:"mode"`
+	DryRun      bool          `json:"dryRun"`
+	Cooldown    string        `json:"cooldown"`
+	Match       runbook.Match `json:"match"`
+	Steps       []RunbookStep `json:"steps"`
+}
+
+// RunbookStep Runbook 步骤响应
+type RunbookStep struct {
+	Name    string         `json:"name"`
+	Kind    string         `json:"kind"`
+	Action  string         `json:"action,omitempty"`
+	Target  string         `json:"target,omitempty"`
+	Params  map[string]any `json:"params,omitempty"`
+	Timeout string         `json:"timeout,omitempty"`
+	Wait    string         `json:"wait,omitempty"`
+	Verify  *RunbookVerify `json:"verify,omitempty"`
+}
+
+// RunbookVerify 验证步骤响应
+type RunbookVerify struct {
+	Metric  string  `json:"metric,omitempty"`
+	MaxRisk float64 `json:"maxRisk"`
+	Timeout string  `json:"timeout"`
+}
+
+// RunbookRunResponse Runbook 执行记录响应
+type RunbookRunResponse struct {
+	ID          int64                 `json:"id"`
+	Runbook     string                `json:"runbook"`
+	IncidentID  string                `json:"incidentId"`
+	ClusterID   string                `json:"clusterId"`
+	EntityKey   string                `json:"entityKey"`
+	Status      string                `json:"status"`
+	DryRun      bool                  `json:"dryRun"`
+	Trigger     string                `json:"trigger"`
+	Steps       []*runbook.StepResult `json:"steps"`
+	Error       string                `json:"error,omitempty"`
+	RequestedBy string                `json:"requestedBy"`
+	ApprovedBy  string                `json:"approvedBy,omitempty"`
+	CreatedAt   string                `json:"createdAt"`
+	StartedAt   string                `json:"startedAt,omitempty"`
+	FinishedAt  string                `json:"finishedAt,omitempty"`
+}
+
+// TriggerRunbookRequest 手动触发请求体
+type TriggerRunbookRequest struct {
+	IncidentID string `json:"incidentId"`
+	DryRun     bool   `json:"dryRun"`
+}
+
+// RejectRunbookRequest 拒绝请求体
+type RejectRunbookRequest struct {
+	Reason string `json:"reason"`
+}
+
+// List 已加载的 Runbook
+// GET /api/v2/aiops/runbooks
+func (h *AIOpsRunbookHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	runbooks, err := h.svc.ListAIOpsRunbooks(r.Context())
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	data := make([]RunbookResponse, 0, len(runbooks))
+	for _, rb := range runbooks {
+		data = append(data, toRunbookResponse(rb))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]any{
+		"message": "获取成功",
+		"data":    data,
+		"total":   len(data),
+	})
+}
+
+// Runs 执行记录列表
+// GET /api/v2/aiops/runbook-runs?incidentId=xxx&cluster=xxx&status=pending_approval&limit=50&offset=0
+func (h *AIOpsRunbookHandler) Runs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	opts := database.RunbookRunQueryOpts{
+		IncidentID: q.Get("incidentId"),
+		ClusterID:  q.Get("cluster"),
+		Status:     q.Get("status"),
+		Limit:      50,
+	}
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil && v > 0 && v <= 200 {
+		opts.Limit = v
+	}
+	if v, err := strconv.Atoi(q.Get("offset")); err == nil && v > 0 {
+		opts.Offset = v
+	}
+
+	runs, total, err := h.svc.ListAIOpsRunbookRuns(r.Context(), opts)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	data := make([]*RunbookRunResponse, 0, len(runs))
+	for _, run := range runs {
+		data = append(data, toRunbookRunResponse(run))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]any{
+		"message": "获取成功",
+		"data":    data,
+		"total":   total,
+	})
+}
+
+// Trigger 手动对事件执行 Runbook
+// POST /api/v2/aiops/runbooks/{name}/run  {"incidentId": "...", "dryRun": true}
+func (h *AIOpsRunbookHandler) Trigger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	name, action, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v2/aiops/runbooks/"), "/")
+	if !ok || name == "" || action != "run" {
+		handler.WriteError(w, http.StatusNotFound, "unknown runbook action")
+		return
+	}
+
+	var req TriggerRunbookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.IncidentID == "" {
+		handler.WriteError(w, http.StatusBadRequest, "incidentId is required")
+		return
+	}
+	middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"runbook":%q,"incidentId":%q,"dryRun":%t}`, name, req.IncidentID, req.DryRun))
+
+	by, _ := middleware.GetUsername(r.Context())
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	run, err := h.svc.TriggerAIOpsRunbook(ctx, name, req.IncidentID, by, req.DryRun)
+	h.writeRun(w, run, err)
+}
+
+// RunAction 审批 / 拒绝待执行的 Runbook
+// POST /api/v2/aiops/runbook-runs/{id}/approve
+// POST /api/v2/aiops/runbook-runs/{id}/reject  {"reason": "..."}
+func (h *AIOpsRunbookHandler) RunAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	rawID, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v2/aiops/runbook-runs/"), "/")
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || id <= 0 {
+		handler.WriteError(w, http.StatusBadRequest, "invalid run id")
+		return
+	}
+
+	by, _ := middleware.GetUsername(r.Context())
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	var run *database.AIOpsRunbookRun
+	switch action {
+	case "approve":
+		middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"runId":%d,"action":"approve"}`, id))
+		run, err = h.svc.ApproveAIOpsRunbookRun(ctx, id, by)
+	case "reject":
+		var req RejectRunbookRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				handler.WriteError(w, http.StatusBadRequest, "invalid request body")
+				return
+			}
+		}
+		req.Reason = strings.TrimSpace(req.Reason)
+		if utf8.RuneCountInString(req.Reason) > maxRejectReasonLen {
+			handler.WriteError(w, http.StatusBadRequest, fmt.Sprintf("reason exceeds %d characters", maxRejectReasonLen))
+			return
+		}
+		middleware.SetAuditDetail(r.Context(), fmt.Sprintf(`{"runId":%d,"action":"reject"}`, id))
+		run, err = h.svc.RejectAIOpsRunbookRun(ctx, id, by, req.Reason)
+	default:
+		handler.WriteError(w, http.StatusNotFound, "unknown runbook run action: "+action)
+		return
+	}
+	h.writeRun(w, run, err)
+}
+
+// writeRun 写出执行记录或映射错误码
+func (h *AIOpsRunbookHandler) writeRun(w http.ResponseWriter, run *database.AIOpsRunbookRun, err error) {
+	switch {
+	case errors.Is(err, rbac.ErrForbidden):
+		handler.WriteError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, runbook.ErrRunbookNotFound), errors.Is(err, runbook.ErrRunNotFound),
+		errors.Is(err, aiops.ErrIncidentNotFound):
+		handler.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, runbook.ErrRunNotPending), errors.Is(err, aiops.ErrIncidentResolved),
+		errors.Is(err, aiops.ErrFalsePositive):
+		handler.WriteError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, runbook.ErrRateLimited):
+		handler.WriteError(w, http.StatusTooManyRequests, err.Error())
+	case errors.Is(err, runbook.ErrNoMatch):
+		handler.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+	case err != nil:
+		// 目标解析失败（如 Pod 不属于 Deployment）
+		handler.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		handler.WriteJSON(w, http.StatusOK, map[string]any{
+			"message": "操作成功",
+			"data":    toRunbookRunResponse(run),
+		})
+	}
+}
+
+// toRunbookResponse 转换 Runbook 定义
+func toRunbookResponse(rb *runbook.Runbook) RunbookResponse {
+	resp := RunbookResponse{
+		Name:        rb.Name,
+		Description: rb.Description,
+		File:        rb.File,
+		Mode:        rb.Mode,
+		DryRun:      rb.DryRun,
+		Cooldown:    rb.Cooldown.String(),
+		Match:       rb.Match,
+		Steps:       make([]RunbookStep, 0, len(rb.Steps)),
+	}
+	for i := range rb.Steps {
+		s := &rb.Steps[i]
+		step := RunbookStep{Name: s.Label(), Kind: s.Kind(), Action: s.Action, Target: s.Target, Params: s.Params}
+		switch step.Kind {
+		case "command":
+			step.Timeout = s.Timeout.String()
+		case "wait":
+			step.Wait = s.Wait.String()
+		case "verify":
+			step.Verify = &RunbookVerify{Metric: s.Verify.Metric, MaxRisk: s.Verify.MaxRisk, Timeout: s.Verify.Timeout.String()}
+		}
+		resp.Steps = append(resp.Steps, step)
+	}
+	return resp
+}
+
+// toRunbookRunResponse 转换执行记录
+func toRunbookRunResponse(run *database.AIOpsRunbookRun) *RunbookRunResponse {
+	if run == nil {
+		return nil
+	}
+	resp := &RunbookRunResponse{
+		ID:          run.ID,
+		Runbook:     run.Runbook,
+		IncidentID:  run.IncidentID,
+		ClusterID:   run.ClusterID,
+		EntityKey:   run.EntityKey,
+		Status:      run.Status,
+		DryRun:      run.DryRun,
+		Trigger:     run.Trigger,
+		Steps:       runbook.DecodeSteps(run.Steps),
+		Error:       run.Error,
+		RequestedBy: run.RequestedBy,
+		ApprovedBy:  run.ApprovedBy,
+		CreatedAt:   run.CreatedAt.Format(time.RFC3339),
+	}
+	if run.StartedAt != nil {
+		resp.StartedAt = run.StartedAt.Format(time.RFC3339)
+	}
+	if run.FinishedAt != nil {
+		resp.FinishedAt = run.FinishedAt.Format(time.RFC3339)
+	}
+	return resp
+}
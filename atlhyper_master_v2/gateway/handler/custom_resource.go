@@ -0,0 +1,146 @@
+// atlhyper_master_v2/gateway/handler/custom_resource.go
+// 自定义资源 (CRD) 查询 Handler
+// 快照只覆盖固定的内置资源，CRD 实例通过 Agent 的 API 发现 + dynamic 指令实时查询
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/service"
+	"AtlHyper/model_v3/command"
+)
+
+// customResourceTimeout 自定义资源查询超时
+const customResourceTimeout = 30 * time.Second
+
+// CustomResourceHandler 自定义资源 Handler
+type CustomResourceHandler struct {
+	svc service.Ops
+}
+
+// NewCustomResourceHandler 创建 CustomResourceHandler
+func NewCustomResourceHandler(svc service.Ops) *CustomResourceHandler {
+	return &CustomResourceHandler{svc: svc}
+}
+
+// Kinds 列出集群中可查询的资源类型（默认仅 CRD）
+// GET /api/v2/custom-resources/kinds?cluster_id=xxx&group=xxx&all=true
+func (h *CustomResourceHandler) Kinds(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	clusterID := q.Get("cluster_id")
+	if clusterID == "" {
+		writeError(w, http.StatusBadRequest, "cluster_id is required")
+		return
+	}
+
+	params := map[string]interface{}{"command": "api_resources"}
+	if group := q.Get("group"); group != "" {
+		params["group"] = group
+	}
+	if q.Get("all") == "true" {
+		params["all"] = true
+	}
+
+	h.query(w, r, &model.CreateCommandRequest{
+		ClusterID: clusterID,
+		Action:    command.ActionDynamic,
+		Params:    params,
+		Source:    "web",
+	})
+}
+
+// Resources 按 group/version/kind 列出或获取自定义资源
+// GET /api/v2/custom-resources?cluster_id=xxx&group=xxx&version=xxx&kind=xxx&namespace=xxx&name=xxx&label_selector=xxx
+//
+// name 为空时返回 List，否则返回单个资源。group/version 可省略，由 Agent 通过 API 发现解析。
+// 仅允许自定义资源: 解析为内置资源（含 Secret）时 Agent 拒绝执行，内置资源须走带权限 / 审计的专用接口。
+func (h *CustomResourceHandler) Resources(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	clusterID := q.Get("cluster_id")
+	kind := q.Get("kind")
+	if clusterID == "" || kind == "" {
+		writeError(w, http.StatusBadRequest, "cluster_id 和 kind 不能为空")
+		return
+	}
+
+	name := q.Get("name")
+	params := map[string]interface{}{
+		"command":     "list",
+		"kind":        kind,
+		"custom_only": true,
+	}
+	if name != "" {
+		params["command"] = "get"
+	}
+	if group := q.Get("group"); group != "" {
+		params["group"] = group
+	}
+	if version := q.Get("version"); version != "" {
+		params["version"] = version
+	}
+	if selector := q.Get("label_selector"); selector != "" {
+		params["label_selector"] = selector
+	}
+
+	h.query(w, r, &model.CreateCommandRequest{
+		ClusterID:       clusterID,
+		Action:          command.ActionDynamic,
+		TargetKind:      kind,
+		TargetNamespace: q.Get("namespace"),
+		TargetName:      name,
+		Params:          params,
+		Source:          "web",
+	})
+}
+
+// query 同步执行 dynamic 指令并原样返回 Agent 的 JSON 结果
+func (h *CustomResourceHandler) query(w http.ResponseWriter, r *http.Request, req *model.CreateCommandRequest) {
+	result, err := h.svc.ExecuteCommandSync(r.Context(), req, customResourceTimeout)
+	if err != nil {
+		if strings.Contains(err.Error(), "create command:") {
+			writeError(w, CommandErrorStatus(err, http.StatusInternalServerError), "创建指令失败: "+err.Error())
+		} else {
+			writeError(w, http.StatusGatewayTimeout, "查询超时")
+		}
+		return
+	}
+	if result == nil {
+		writeError(w, http.StatusGatewayTimeout, "查询超时，Agent 可能未响应")
+		return
+	}
+	if !result.Success {
+		status := http.StatusBadGateway
+		if strings.Contains(result.Error, "unsupported kind") || strings.Contains(result.Error, "ambiguous") {
+			status = http.StatusBadRequest
+		} else if strings.Contains(result.Error, "built-in resource") || strings.Contains(result.Error, "not allowed") {
+			status = http.StatusForbidden
+		} else if strings.Contains(result.Error, "status 404") {
+			status = http.StatusNotFound
+		}
+		writeError(w, status, result.Error)
+		return
+	}
+
+	var data interface{} = result.Output
+	if json.Valid([]byte(result.Output)) {
+		data = json.RawMessage(result.Output)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "获取成功",
+		"data":    data,
+	})
+}
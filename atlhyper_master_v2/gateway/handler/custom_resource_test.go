@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/rbac"
+	"AtlHyper/atlhyper_master_v2/service"
+	"AtlHyper/atlhyper_master_v2/service/operations"
+	"AtlHyper/model_v3/command"
+)
+
+// fakeBus 记录入队指令，等待结果时直接返回成功
+type fakeBus struct {
+	enqueued []*command.Command
+}
+
+func (f *fakeBus) EnqueueCommand(clusterID, topic string, cmd *command.Command) error {
+	f.enqueued = append(f.enqueued, cmd)
+	return nil
+}
+
+func (f *fakeBus) GetCommandStatus(cmdID string) (*command.Status, error) { return nil, nil }
+
+func (f *fakeBus) WaitCommandResult(ctx context.Context, cmdID string, timeout time.Duration) (*command.Result, error) {
+	return &command.Result{CommandID: cmdID, Success: true, Output: `{"items":[]}`}, nil
+}
+
+// fakeCommandRepo 仅实现指令历史写入
+type fakeCommandRepo struct {
+	database.CommandHistoryRepository
+}
+
+func (f *fakeCommandRepo) Create(ctx context.Context, cmd *database.CommandHistory) error { return nil }
+func (f *fakeCommandRepo) Update(ctx context.Context, cmd *database.CommandHistory) error { return nil }
+
+// commandOps 将指令执行委托给真实的 CommandService
+type commandOps struct {
+	service.Ops
+	cmd *operations.CommandService
+}
+
+func (o *commandOps) ExecuteCommandSync(ctx context.Context, req *model.CreateCommandRequest, timeout time.Duration) (*command.Result, error) {
+	return o.cmd.ExecuteCommandSync(ctx, req, timeout)
+}
+
+func TestCustomResources_NamespaceBoundWithoutNamespace(t *testing.T) {
+	bus := &fakeBus{}
+	h := NewCustomResourceHandler(&commandOps{cmd: operations.NewCommandService(bus, &fakeCommandRepo{})})
+	scope := &rbac.Scope{
+		UserID:   7,
+		Bindings: []rbac.Binding{{ClusterID: "c1", Namespace: "team-a", Role: rbac.RoleOperator}},
+	}
+
+	// 命名空间级绑定不指定 namespace 列出 CRD（跨命名空间 / 集群级）被拒绝，且不下发指令
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/custom-resources?cluster_id=c1&kind=IngressRoute", nil)
+	req = req.WithContext(rbac.WithScope(req.Context(), scope))
+	w := httptest.NewRecorder()
+	h.Resources(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403 (body: %s)", w.Code, w.Body.String())
+	}
+	if len(bus.enqueued) != 0 {
+		t.Fatalf("refused query must not be enqueued, got %d commands", len(bus.enqueued))
+	}
+
+	// 授权命名空间内的查询正常下发，并由 Agent 限定为命名空间级资源
+	req = httptest.NewRequest(http.MethodGet, "/api/v2/custom-resources?cluster_id=c1&kind=IngressRoute&namespace=team-a", nil)
+	req = req.WithContext(rbac.WithScope(req.Context(), scope))
+	w = httptest.NewRecorder()
+	h.Resources(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body: %s)", w.Code, w.Body.String())
+	}
+	if len(bus.enqueued) != 1 || bus.enqueued[0].Params["namespaced_only"] != true {
+		t.Errorf("enqueued = %+v, want one namespaced_only query", bus.enqueued)
+	}
+}
@@ -0,0 +1,113 @@
+// atlhyper_master_v2/gateway/handler/exec.go
+// 交互式 exec 会话 Handler（WebSocket）
+//
+// 浏览器无法为 WebSocket 设置请求头，认证 Token 通过 ?token= 查询参数传递
+// （见 middleware.AuthRequired）。帧格式见 model_v3/command.ExecFrame。
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/rbac"
+	"AtlHyper/atlhyper_master_v2/service"
+	"AtlHyper/atlhyper_master_v2/stream"
+
+	"github.com/gorilla/websocket"
+)
+
+// execUpgrader WebSocket 升级器
+// 会话必须携带有效 JWT，跨站页面无法取得 Token，因此不额外校验 Origin（与 CORS "*" 策略一致）
+var execUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// ExecHandler exec 会话 Handler
+type ExecHandler struct {
+	svc service.Ops
+}
+
+// NewExecHandler 创建 ExecHandler
+func NewExecHandler(svc service.Ops) *ExecHandler {
+	return &ExecHandler{svc: svc}
+}
+
+// execAuditDetail 审计日志中的会话摘要
+type execAuditDetail struct {
+	SessionID string   `json:"sessionId,omitempty"`
+	ClusterID string   `json:"clusterId"`
+	Namespace string   `json:"namespace"`
+	Pod       string   `json:"pod"`
+	Container string   `json:"container,omitempty"`
+	Command   []string `json:"command,omitempty"`
+	EndReason string   `json:"endReason,omitempty"`
+	ExitCode  *int     `json:"exitCode,omitempty"`
+	Duration  string   `json:"duration,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// PodExec 打开交互式 exec 会话
+// GET /api/v2/ops/pods/exec?cluster_id=&namespace=&pod=&container=&command=sh&tty=true (WebSocket)
+//
+// command 可重复传递组成参数列表，省略时使用 /bin/sh。
+func (h *ExecHandler) PodExec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	req := &model.ExecSessionRequest{
+		ClusterID: q.Get("cluster_id"),
+		Namespace: q.Get("namespace"),
+		Pod:       q.Get("pod"),
+		Container: q.Get("container"),
+		Command:   q["command"],
+		TTY:       q.Get("tty") != "false",
+	}
+	if req.ClusterID == "" || req.Namespace == "" || req.Pod == "" {
+		writeError(w, http.StatusBadRequest, "cluster_id, namespace and pod are required")
+		return
+	}
+	if !rbac.ScopeFrom(r.Context()).Allows(req.ClusterID, req.Namespace, rbac.RoleOperator) {
+		writeError(w, http.StatusForbidden, "目标超出授权范围")
+		return
+	}
+	req.UserID, _ = middleware.GetUserID(r.Context())
+	req.Username, _ = middleware.GetUsername(r.Context())
+
+	conn, err := execUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade 已写入错误响应
+		return
+	}
+	client := stream.NewWSConn(conn)
+	defer client.Close()
+
+	start := time.Now()
+	detail := execAuditDetail{
+		ClusterID: req.ClusterID,
+		Namespace: req.Namespace,
+		Pod:       req.Pod,
+		Container: req.Container,
+		Command:   req.Command,
+	}
+
+	session, err := h.svc.RunExecSession(r.Context(), req, client)
+	if err != nil {
+		detail.Error = err.Error()
+	} else {
+		detail.SessionID = session.SessionID
+		detail.EndReason = session.EndReason
+		detail.ExitCode = session.ExitCode
+	}
+	detail.Duration = time.Since(start).Round(time.Second).String()
+
+	data, _ := json.Marshal(detail)
+	middleware.SetAuditDetail(r.Context(), string(data))
+}
@@ -4,7 +4,10 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"AtlHyper/atlhyper_master_v2/rbac"
 )
 
 // WriteJSON 写入 JSON 响应（导出供子包使用）
@@ -28,3 +31,11 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 func writeError(w http.ResponseWriter, status int, message string) {
 	WriteError(w, status, message)
 }
+
+// CommandErrorStatus 指令创建失败时的 HTTP 状态码：超出授权范围返回 403，其余返回 fallback
+func CommandErrorStatus(err error, fallback int) int {
+	if errors.Is(err, rbac.ErrForbidden) {
+		return http.StatusForbidden
+	}
+	return fallback
+}
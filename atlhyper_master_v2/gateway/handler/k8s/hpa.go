@@ -0,0 +1,95 @@
+// atlhyper_master_v2/gateway/handler/k8s/hpa.go
+// HorizontalPodAutoscaler 查询 Handler
+package k8s
+
+import (
+	"net/http"
+	"strings"
+
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+	"AtlHyper/atlhyper_master_v2/model/convert"
+	"AtlHyper/atlhyper_master_v2/service"
+)
+
+// HPAHandler HPA Handler
+type HPAHandler struct {
+	svc service.Query
+}
+
+// NewHPAHandler 创建 HPAHandler
+func NewHPAHandler(svc service.Query) *HPAHandler {
+	return &HPAHandler{svc: svc}
+}
+
+// List 获取 HPA 列表
+// GET /api/v2/hpas?cluster_id=xxx&namespace=xxx
+func (h *HPAHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	clusterID := r.URL.Query().Get("cluster_id")
+	if clusterID == "" {
+		handler.WriteError(w, http.StatusBadRequest, "cluster_id is required")
+		return
+	}
+
+	namespace := r.URL.Query().Get("namespace")
+
+	hpas, err := h.svc.GetHPAs(r.Context(), clusterID, namespace)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "查询 HPA 失败")
+		return
+	}
+
+	items := convert.HPAItems(hpas)
+	handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "获取成功",
+		"data":    items,
+		"total":   len(items),
+	})
+}
+
+// Get 获取单个 HPA 详情
+// GET /api/v2/hpas/{name}?cluster_id=xxx&namespace=xxx
+func (h *HPAHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v2/hpas/")
+	name := strings.TrimSuffix(path, "/")
+	if name == "" {
+		handler.WriteError(w, http.StatusBadRequest, "hpa name is required")
+		return
+	}
+
+	clusterID := r.URL.Query().Get("cluster_id")
+	if clusterID == "" {
+		handler.WriteError(w, http.StatusBadRequest, "cluster_id is required")
+		return
+	}
+
+	namespace := r.URL.Query().Get("namespace")
+
+	hpas, err := h.svc.GetHPAs(r.Context(), clusterID, namespace)
+	if err != nil {
+		handler.WriteError(w, http.StatusInternalServerError, "查询 HPA 失败")
+		return
+	}
+
+	for i := range hpas {
+		if hpas[i].Name == name {
+			detail := convert.HPADetail(&hpas[i])
+			handler.WriteJSON(w, http.StatusOK, map[string]interface{}{
+				"message": "获取成功",
+				"data":    detail,
+			})
+			return
+		}
+	}
+
+	handler.WriteError(w, http.StatusNotFound, "HPA not found")
+}
@@ -0,0 +1,100 @@
+// atlhyper_master_v2/gateway/handler/logstream.go
+// 日志跟踪 Handler（Server-Sent Events）
+//
+// 浏览器 EventSource 无法设置请求头，认证 Token 通过 ?token= 查询参数传递
+// （见 middleware.AuthRequired）。事件格式见 stream.SSEEvent。
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/rbac"
+	"AtlHyper/atlhyper_master_v2/stream"
+)
+
+// logStreamAuditDetail 审计日志中的日志跟踪摘要
+type logStreamAuditDetail struct {
+	ClusterID string `json:"clusterId"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Container string `json:"container,omitempty"`
+	EndReason string `json:"endReason,omitempty"`
+	Duration  string `json:"duration,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// LogStream 跟踪 Pod / Deployment 日志
+// GET /api/v2/ops/logs/stream?cluster_id=&namespace=&kind=Pod&name=&container=&tail_lines=&since_seconds= (SSE)
+//
+// kind 可选 Pod（默认）或 Deployment；Deployment 会合并其全部 Pod 的日志。
+// 事件类型: log（一行日志，含 source/time/color）、exit（全部来源结束）、error（会话异常结束）。
+func (h *ExecHandler) LogStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	req := &model.LogStreamRequest{
+		ClusterID: q.Get("cluster_id"),
+		Kind:      q.Get("kind"),
+		Namespace: q.Get("namespace"),
+		Name:      q.Get("name"),
+		Container: q.Get("container"),
+	}
+	if req.Name == "" {
+		req.Name = q.Get("pod")
+	}
+	if req.Kind == "" {
+		req.Kind = "Pod"
+	}
+	if req.ClusterID == "" || req.Namespace == "" || req.Name == "" {
+		writeError(w, http.StatusBadRequest, "cluster_id, namespace and name are required")
+		return
+	}
+	if req.Kind != "Pod" && req.Kind != "Deployment" {
+		writeError(w, http.StatusBadRequest, "kind must be Pod or Deployment")
+		return
+	}
+	if v := q.Get("tail_lines"); v != "" {
+		req.TailLines, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if v := q.Get("since_seconds"); v != "" {
+		req.SinceSeconds, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if !rbac.ScopeFrom(r.Context()).Allows(req.ClusterID, req.Namespace, rbac.RoleOperator) {
+		writeError(w, http.StatusForbidden, "目标超出授权范围")
+		return
+	}
+	req.UserID, _ = middleware.GetUserID(r.Context())
+	req.Username, _ = middleware.GetUsername(r.Context())
+
+	client := stream.NewSSEConn(w, r)
+	defer client.Close()
+
+	start := time.Now()
+	detail := logStreamAuditDetail{
+		ClusterID: req.ClusterID,
+		Kind:      req.Kind,
+		Namespace: req.Namespace,
+		Name:      req.Name,
+		Container: req.Container,
+	}
+
+	result, err := h.svc.StreamLogs(r.Context(), req, client)
+	if err != nil {
+		detail.Error = err.Error()
+	} else {
+		detail.EndReason = result.EndReason
+	}
+	detail.Duration = time.Since(start).Round(time.Second).String()
+
+	data, _ := json.Marshal(detail)
+	middleware.SetAuditDetail(r.Context(), string(data))
+}
@@ -235,7 +235,7 @@ func (h *NodeMetricsHandler) getHistoryFromCH(w http.ResponseWriter, r *http.Req
 	}, 30*time.Second)
 	if err != nil {
 		if strings.Contains(err.Error(), "create command:") {
-			handler.WriteError(w, http.StatusInternalServerError, "创建查询指令失败: "+err.Error())
+			handler.WriteError(w, handler.CommandErrorStatus(err, http.StatusInternalServerError), "创建查询指令失败: "+err.Error())
 		} else {
 			handler.WriteError(w, http.StatusGatewayTimeout, "查询超时，请稍后重试")
 		}
@@ -74,6 +74,16 @@ type NodeCordonRequest struct {
 	Name      string `json:"name"`
 }
 
+// NodeDrainRequest Node 排空请求
+type NodeDrainRequest struct {
+	ClusterID          string `json:"clusterId"`
+	Name               string `json:"name"`
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"` // 覆盖 Pod 优雅终止时间
+	TimeoutSeconds     int    `json:"timeoutSeconds,omitempty"`     // 整体超时（默认 300）
+	DeleteEmptyDirData bool   `json:"deleteEmptyDirData,omitempty"` // 允许驱逐使用 emptyDir 的 Pod
+	Force              bool   `json:"force,omitempty"`              // 允许驱逐无控制器的 Pod
+}
+
 // ConfigMapDataRequest 获取 ConfigMap 数据请求
 type ConfigMapDataRequest struct {
 	ClusterID string `json:"clusterId"`
@@ -133,7 +143,7 @@ func (h *OpsHandler) PodLogs(w http.ResponseWriter, r *http.Request) {
 	}, 30*time.Second)
 	if err != nil {
 		if strings.Contains(err.Error(), "create command:") {
-			writeError(w, http.StatusInternalServerError, "创建指令失败: "+err.Error())
+			writeError(w, CommandErrorStatus(err, http.StatusInternalServerError), "创建指令失败: "+err.Error())
 		} else {
 			writeError(w, http.StatusGatewayTimeout, "获取日志超时")
 		}
@@ -178,7 +188,7 @@ func (h *OpsHandler) PodRestart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.svc.CreateCommand(&model.CreateCommandRequest{
+	resp, err := h.svc.CreateCommand(r.Context(), &model.CreateCommandRequest{
 		ClusterID:       req.ClusterID,
 		Action:          command.ActionDelete,
 		TargetKind:      "Pod",
@@ -187,7 +197,7 @@ func (h *OpsHandler) PodRestart(w http.ResponseWriter, r *http.Request) {
 		Source:          "web",
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "创建指令失败: "+err.Error())
+		writeError(w, CommandErrorStatus(err, http.StatusInternalServerError), "创建指令失败: "+err.Error())
 		return
 	}
 
@@ -222,7 +232,7 @@ func (h *OpsHandler) DeploymentScale(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.svc.CreateCommand(&model.CreateCommandRequest{
+	resp, err := h.svc.CreateCommand(r.Context(), &model.CreateCommandRequest{
 		ClusterID:       req.ClusterID,
 		Action:          command.ActionScale,
 		TargetKind:      "Deployment",
@@ -234,7 +244,7 @@ func (h *OpsHandler) DeploymentScale(w http.ResponseWriter, r *http.Request) {
 		Source: "web",
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "创建指令失败: "+err.Error())
+		writeError(w, CommandErrorStatus(err, http.StatusInternalServerError), "创建指令失败: "+err.Error())
 		return
 	}
 
@@ -264,7 +274,7 @@ func (h *OpsHandler) DeploymentRestart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.svc.CreateCommand(&model.CreateCommandRequest{
+	resp, err := h.svc.CreateCommand(r.Context(), &model.CreateCommandRequest{
 		ClusterID:       req.ClusterID,
 		Action:          command.ActionRestart,
 		TargetKind:      "Deployment",
@@ -273,7 +283,7 @@ func (h *OpsHandler) DeploymentRestart(w http.ResponseWriter, r *http.Request) {
 		Source:          "web",
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "创建指令失败: "+err.Error())
+		writeError(w, CommandErrorStatus(err, http.StatusInternalServerError), "创建指令失败: "+err.Error())
 		return
 	}
 
@@ -308,7 +318,7 @@ func (h *OpsHandler) DeploymentImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.svc.CreateCommand(&model.CreateCommandRequest{
+	resp, err := h.svc.CreateCommand(r.Context(), &model.CreateCommandRequest{
 		ClusterID:       req.ClusterID,
 		Action:          command.ActionUpdateImage,
 		TargetKind:      "Deployment",
@@ -321,7 +331,7 @@ func (h *OpsHandler) DeploymentImage(w http.ResponseWriter, r *http.Request) {
 		Source: "web",
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "创建指令失败: "+err.Error())
+		writeError(w, CommandErrorStatus(err, http.StatusInternalServerError), "创建指令失败: "+err.Error())
 		return
 	}
 
@@ -351,7 +361,7 @@ func (h *OpsHandler) NodeCordon(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.svc.CreateCommand(&model.CreateCommandRequest{
+	resp, err := h.svc.CreateCommand(r.Context(), &model.CreateCommandRequest{
 		ClusterID:  req.ClusterID,
 		Action:     command.ActionCordon,
 		TargetKind: "Node",
@@ -359,7 +369,7 @@ func (h *OpsHandler) NodeCordon(w http.ResponseWriter, r *http.Request) {
 		Source:     "web",
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "创建指令失败: "+err.Error())
+		writeError(w, CommandErrorStatus(err, http.StatusInternalServerError), "创建指令失败: "+err.Error())
 		return
 	}
 
@@ -389,7 +399,7 @@ func (h *OpsHandler) NodeUncordon(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.svc.CreateCommand(&model.CreateCommandRequest{
+	resp, err := h.svc.CreateCommand(r.Context(), &model.CreateCommandRequest{
 		ClusterID:  req.ClusterID,
 		Action:     command.ActionUncordon,
 		TargetKind: "Node",
@@ -397,7 +407,7 @@ func (h *OpsHandler) NodeUncordon(w http.ResponseWriter, r *http.Request) {
 		Source:     "web",
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "创建指令失败: "+err.Error())
+		writeError(w, CommandErrorStatus(err, http.StatusInternalServerError), "创建指令失败: "+err.Error())
 		return
 	}
 
@@ -408,6 +418,56 @@ func (h *OpsHandler) NodeUncordon(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// NodeDrain 排空 Node（异步，进度通过指令状态查询）
+// POST /api/v2/ops/nodes/drain
+func (h *OpsHandler) NodeDrain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req NodeDrainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "请求参数无效")
+		return
+	}
+
+	if req.ClusterID == "" || req.Name == "" {
+		writeError(w, http.StatusBadRequest, "cluster_id, name 不能为空")
+		return
+	}
+
+	params := map[string]interface{}{
+		"deleteEmptyDirData": req.DeleteEmptyDirData,
+		"force":              req.Force,
+	}
+	if req.GracePeriodSeconds != nil {
+		params["gracePeriodSeconds"] = *req.GracePeriodSeconds
+	}
+	if req.TimeoutSeconds > 0 {
+		params["timeoutSeconds"] = req.TimeoutSeconds
+	}
+
+	resp, err := h.svc.CreateCommand(r.Context(), &model.CreateCommandRequest{
+		ClusterID:  req.ClusterID,
+		Action:     command.ActionDrain,
+		TargetKind: "Node",
+		TargetName: req.Name,
+		Params:     params,
+		Source:     "web",
+	})
+	if err != nil {
+		writeError(w, CommandErrorStatus(err, http.StatusInternalServerError), "创建指令失败: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":    "Node 排空指令已下发",
+		"command_id": resp.CommandID,
+		"status":     resp.Status,
+	})
+}
+
 // ConfigMapData 获取 ConfigMap 数据（同步等待）
 // POST /api/v2/ops/configmaps/data
 func (h *OpsHandler) ConfigMapData(w http.ResponseWriter, r *http.Request) {
@@ -438,7 +498,7 @@ func (h *OpsHandler) ConfigMapData(w http.ResponseWriter, r *http.Request) {
 	}, 30*time.Second)
 	if err != nil {
 		if strings.Contains(err.Error(), "create command:") {
-			writeError(w, http.StatusInternalServerError, "创建指令失败: "+err.Error())
+			writeError(w, CommandErrorStatus(err, http.StatusInternalServerError), "创建指令失败: "+err.Error())
 		} else {
 			writeError(w, http.StatusGatewayTimeout, "获取数据超时")
 		}
@@ -492,7 +552,7 @@ func (h *OpsHandler) SecretData(w http.ResponseWriter, r *http.Request) {
 	}, 30*time.Second)
 	if err != nil {
 		if strings.Contains(err.Error(), "create command:") {
-			writeError(w, http.StatusInternalServerError, "创建指令失败: "+err.Error())
+			writeError(w, CommandErrorStatus(err, http.StatusInternalServerError), "创建指令失败: "+err.Error())
 		} else {
 			writeError(w, http.StatusGatewayTimeout, "获取数据超时")
 		}
@@ -0,0 +1,28 @@
+// atlhyper_master_v2/gateway/handler/slo_alerts.go
+// SLO 燃烧率告警 Handler 方法
+package slo
+
+import (
+	"net/http"
+
+	"AtlHyper/atlhyper_master_v2/gateway/handler"
+)
+
+// Alerts 处理 GET /api/v2/slo/alerts
+// 返回触发中的多窗口燃烧率告警；cluster_id 为空时返回全部授权集群
+func (h *SLOHandler) Alerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		handler.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	clusterID := r.URL.Query().Get("cluster_id")
+	alerts, err := h.querySvc.GetSLOBurnAlerts(r.Context(), clusterID)
+	if err != nil {
+		sloLog.Error("获取燃烧率告警失败", "err", err)
+		handler.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	handler.WriteJSON(w, http.StatusOK, alerts)
+}
@@ -0,0 +1,164 @@
+// atlhyper_master_v2/gateway/handler/snapshot_history.go
+// 快照历史 Handler（时间回溯 + 状态对比）
+// 单个时间点的资源查询直接在各 /api/v2/* 查询上携带 ?at=（见 middleware.SnapshotAt）
+package handler
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops/replay"
+	"AtlHyper/atlhyper_master_v2/gateway/middleware"
+	"AtlHyper/atlhyper_master_v2/service"
+)
+
+// SnapshotHistoryHandler 快照历史 Handler
+type SnapshotHistoryHandler struct {
+	svc service.Query
+}
+
+// NewSnapshotHistoryHandler 创建 SnapshotHistoryHandler
+func NewSnapshotHistoryHandler(svc service.Query) *SnapshotHistoryHandler {
+	return &SnapshotHistoryHandler{svc: svc}
+}
+
+// History 列出可回溯的时间点
+// GET /api/v2/snapshots/history?cluster_id=xxx
+func (h *SnapshotHistoryHandler) History(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	clusterID := r.URL.Query().Get("cluster_id")
+	if clusterID == "" {
+		writeError(w, http.StatusBadRequest, "cluster_id is required")
+		return
+	}
+
+	times, err := h.svc.GetSnapshotHistory(r.Context(), clusterID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "查询快照历史失败: "+err.Error())
+		return
+	}
+	if times == nil {
+		times = []time.Time{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "获取成功",
+		"data":    times,
+		"total":   len(times),
+	})
+}
+
+// Diff 对比两个时间点之间变更的对象
+// GET /api/v2/snapshots/diff?cluster_id=xxx&from=xxx&to=xxx&objects=true
+//
+// from/to 支持 RFC3339 或 Unix 时间戳，to 省略时为当前。
+// 默认只返回变更列表，objects=true 时附带变更后的完整对象。
+func (h *SnapshotHistoryHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	clusterID := q.Get("cluster_id")
+	if clusterID == "" || q.Get("from") == "" {
+		writeError(w, http.StatusBadRequest, "cluster_id 和 from 不能为空")
+		return
+	}
+
+	from, err := middleware.ParseSnapshotTime(q.Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "from 参数格式错误")
+		return
+	}
+	to := time.Now()
+	if raw := q.Get("to"); raw != "" {
+		if to, err = middleware.ParseSnapshotTime(raw); err != nil {
+			writeError(w, http.StatusBadRequest, "to 参数格式错误")
+			return
+		}
+	}
+	if to.Before(from) {
+		writeError(w, http.StatusBadRequest, "to 不能早于 from")
+		return
+	}
+
+	diff, err := h.svc.DiffSnapshots(r.Context(), clusterID, from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "快照对比失败: "+err.Error())
+		return
+	}
+	if diff == nil {
+		writeError(w, http.StatusNotFound, "该时间点没有可用快照（超出历史保留范围）")
+		return
+	}
+
+	if q.Get("objects") != "true" {
+		for i := range diff.Changes {
+			diff.Changes[i].Object = nil
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "获取成功",
+		"data":    diff,
+	})
+}
+
+// Export 导出历史快照为回放录制文件（Gzip 压缩的 JSON Lines，不含 OTel）
+// GET /api/v2/snapshots/export?cluster_id=xxx&from=xxx&to=xxx
+//
+// from 省略时为最早历史，to 省略时为当前；输出可直接用于 `atlhyper_master_v2 replay`。
+func (h *SnapshotHistoryHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	clusterID := q.Get("cluster_id")
+	if clusterID == "" {
+		writeError(w, http.StatusBadRequest, "cluster_id is required")
+		return
+	}
+
+	var from time.Time
+	to := time.Now()
+	var err error
+	if raw := q.Get("from"); raw != "" {
+		if from, err = middleware.ParseSnapshotTime(raw); err != nil {
+			writeError(w, http.StatusBadRequest, "from 参数格式错误")
+			return
+		}
+	}
+	if raw := q.Get("to"); raw != "" {
+		if to, err = middleware.ParseSnapshotTime(raw); err != nil {
+			writeError(w, http.StatusBadRequest, "to 参数格式错误")
+			return
+		}
+	}
+
+	snapshots, err := h.svc.ListHistorySnapshots(r.Context(), clusterID, from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "导出快照历史失败: "+err.Error())
+		return
+	}
+	if len(snapshots) == 0 {
+		writeError(w, http.StatusNotFound, "该时间范围内没有历史快照")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+		fmt.Sprintf("%s-%s.jsonl.gz", clusterID, snapshots[0].FetchedAt.UTC().Format("20060102T150405Z"))))
+	// 响应头已写出，写入失败（客户端断开）时无法再返回错误
+	zw := gzip.NewWriter(w)
+	_ = replay.WriteSnapshots(zw, snapshots...)
+	_ = zw.Close()
+}
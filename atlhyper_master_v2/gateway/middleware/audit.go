@@ -42,6 +42,24 @@ func (rw *auditResponseWriter) Flush() {
 	}
 }
 
+// Unwrap 返回底层 ResponseWriter（http.ResponseController 据此支持 Hijack，WebSocket 升级需要）
+func (rw *auditResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// auditDetailKey 审计详情的 context key
+type auditDetailKey struct{}
+
+// SetAuditDetail 由 Handler 补充审计详情
+//
+// 用于无请求体的操作（如 WebSocket 会话），详情替代请求体写入审计日志。
+// 未经过 Audit 中间件时为空操作。
+func SetAuditDetail(ctx context.Context, detail string) {
+	if holder, ok := ctx.Value(auditDetailKey{}).(*string); ok {
+		*holder = detail
+	}
+}
+
 // Audit 审计中间件
 // 记录敏感操作到审计日志
 func Audit(repo AuditRepository, config AuditConfig) func(http.HandlerFunc) http.HandlerFunc {
@@ -59,6 +77,10 @@ func Audit(repo AuditRepository, config AuditConfig) func(http.HandlerFunc) http
 			// 包装 ResponseWriter
 			rw := &auditResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
+			// 注入审计详情容器
+			detail := new(string)
+			r = r.WithContext(context.WithValue(r.Context(), auditDetailKey{}, detail))
+
 			// 处理请求
 			next.ServeHTTP(rw, r)
 
@@ -80,6 +102,9 @@ func Audit(repo AuditRepository, config AuditConfig) func(http.HandlerFunc) http
 
 				// 脱敏请求体
 				sanitizedBody := sanitizeRequestBody(string(bodyBytes))
+				if sanitizedBody == "" && *detail != "" {
+					sanitizedBody = sanitizeRequestBody(*detail)
+				}
 
 				log := &database.AuditLog{
 					Timestamp:   start,
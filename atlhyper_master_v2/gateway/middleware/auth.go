@@ -9,15 +9,16 @@ import (
 	"time"
 
 	"AtlHyper/atlhyper_master_v2/config"
+	"AtlHyper/atlhyper_master_v2/rbac"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 // 角色常量（数值越大权限越高）
 const (
-	RoleViewer   = 1
-	RoleOperator = 2
-	RoleAdmin    = 3
+	RoleViewer   = rbac.RoleViewer
+	RoleOperator = rbac.RoleOperator
+	RoleAdmin    = rbac.RoleAdmin
 )
 
 // 上下文 key 类型（避免与其他包冲突）
@@ -70,6 +71,13 @@ func AuthRequired(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 
+		// 浏览器 WebSocket / EventSource 无法设置请求头，允许通过 token 查询参数传递
+		if authHeader == "" && allowsQueryToken(r) {
+			if token := r.URL.Query().Get("token"); token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
+
 		if authHeader == "" {
 			http.Error(w, `{"error": "未登录，请先登录获取 Token"}`, http.StatusUnauthorized)
 			return
@@ -97,12 +105,43 @@ func AuthRequired(next http.Handler) http.Handler {
 	})
 }
 
+// OptionalAuth 公开只读路由的认证
+// 携带 Token 时按 AuthRequired 校验并注入用户信息（用于按授权范围过滤结果）；
+// 未携带时，MASTER_JWT_REQUIRE_READ_AUTH 开启则要求登录，否则匿名放行
+func OptionalAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hasToken := r.Header.Get("Authorization") != "" ||
+			(allowsQueryToken(r) && r.URL.Query().Get("token") != "")
+		if hasToken || config.GlobalConfig.JWT.RequireReadAuth {
+			AuthRequired(next).ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowsQueryToken 判断是否为允许查询参数认证的流式请求（WebSocket 升级或 SSE）
+func allowsQueryToken(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
 // Auth 是 AuthRequired 的别名（保持向后兼容）
 var Auth = AuthRequired
 
-// RequireMinRole 检查最低角色权限
+// RequireMinRole 检查最低角色权限（按 JWT 中的全局角色）
 // 必须在 AuthRequired 之后使用
 func RequireMinRole(minRole int, next http.HandlerFunc) http.HandlerFunc {
+	return requireRole(minRole, false, next)
+}
+
+// RequireScopedRole 检查最低角色权限，按范围授权的用户取绑定中的最高角色
+// 仅用于 Handler / Service 通过 rbac.ScopeFrom(ctx).Allows 校验具体目标的路由
+func RequireScopedRole(minRole int, next http.HandlerFunc) http.HandlerFunc {
+	return requireRole(minRole, true, next)
+}
+
+func requireRole(minRole int, scoped bool, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		roleValue := r.Context().Value(CtxRole)
 		if roleValue == nil {
@@ -112,7 +151,17 @@ func RequireMinRole(minRole int, next http.HandlerFunc) http.HandlerFunc {
 
 		// JWT 中的数字会被解析为 float64
 		roleFloat, ok := roleValue.(float64)
-		if !ok || int(roleFloat) < minRole {
+		if !ok {
+			http.Error(w, `{"error": "权限不足"}`, http.StatusForbidden)
+			return
+		}
+		role := int(roleFloat)
+		if scoped {
+			if bound := rbac.ScopeFrom(r.Context()).MaxRole(); bound > role {
+				role = bound
+			}
+		}
+		if role < minRole {
 			http.Error(w, `{"error": "权限不足"}`, http.StatusForbidden)
 			return
 		}
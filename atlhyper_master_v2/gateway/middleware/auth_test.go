@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"AtlHyper/atlhyper_master_v2/rbac"
+)
+
+// doRole 以全局角色 + 角色绑定发起请求，返回状态码
+func doRole(wrap func(int, http.HandlerFunc) http.HandlerFunc, globalRole int, scope *rbac.Scope) int {
+	h := wrap(RoleOperator, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	ctx := context.WithValue(context.Background(), CtxRole, float64(globalRole))
+	if scope != nil {
+		ctx = rbac.WithScope(ctx, scope)
+	}
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	return rec.Code
+}
+
+func TestRequireRole_BindingRoles(t *testing.T) {
+	// 全局 Viewer + 单个命名空间的 Operator 绑定
+	scope := &rbac.Scope{Bindings: []rbac.Binding{{ClusterID: "c1", Namespace: "prod", Role: rbac.RoleOperator}}}
+
+	if code := doRole(RequireMinRole, RoleViewer, scope); code != http.StatusForbidden {
+		t.Errorf("RequireMinRole with namespace binding = %d, want 403", code)
+	}
+	if code := doRole(RequireScopedRole, RoleViewer, scope); code != http.StatusOK {
+		t.Errorf("RequireScopedRole with namespace binding = %d, want 200", code)
+	}
+	if code := doRole(RequireMinRole, RoleOperator, nil); code != http.StatusOK {
+		t.Errorf("RequireMinRole with global Operator = %d, want 200", code)
+	}
+}
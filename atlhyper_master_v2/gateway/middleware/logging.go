@@ -32,6 +32,11 @@ func (rw *responseWriter) Flush() {
 	}
 }
 
+// Unwrap 返回底层 ResponseWriter（http.ResponseController 据此支持 Hijack，WebSocket 升级需要）
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Logging 日志中间件
 // 智能日志级别：
 // - 非 2xx 响应 → INFO
@@ -0,0 +1,33 @@
+// atlhyper_master_v2/gateway/middleware/scope.go
+// 授权范围中间件
+// 必须在 AuthRequired / OptionalAuth 之后使用，将登录用户的角色绑定注入 context
+package middleware
+
+import (
+	"net/http"
+
+	"AtlHyper/atlhyper_master_v2/rbac"
+)
+
+// Scope 解析登录用户的授权范围并写入 context
+// 匿名访问、Admin 和无绑定的用户不设置范围（不受限）
+func Scope(resolver *rbac.Resolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := GetUserID(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		role, _ := GetRole(r.Context())
+
+		scope, err := resolver.Resolve(r.Context(), userID, role)
+		if err != nil {
+			http.Error(w, `{"error": "权限加载失败"}`, http.StatusInternalServerError)
+			return
+		}
+		if scope != nil {
+			r = r.WithContext(rbac.WithScope(r.Context(), scope))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,49 @@
+// atlhyper_master_v2/gateway/middleware/snapshot_at.go
+// 时间回溯中间件
+// /api/v2/* 的 GET 查询可携带 ?at=<时间点>，Query 层据此返回该时间点的集群快照
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/datahub"
+)
+
+// SnapshotAt 解析 ?at= 参数并写入 context
+// 格式非法时返回 400
+func SnapshotAt(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := r.URL.Query().Get("at")
+		if raw == "" || r.Method != http.MethodGet || !strings.HasPrefix(r.URL.Path, "/api/v2/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		at, err := ParseSnapshotTime(raw)
+		if err != nil {
+			http.Error(w, `{"error": "at 参数格式错误，需为 RFC3339 或 Unix 时间戳"}`, http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(datahub.WithSnapshotTime(r.Context(), at)))
+	})
+}
+
+// ParseSnapshotTime 解析时间点参数
+// 支持 RFC3339 和 Unix 时间戳（秒或毫秒，按位数区分）
+func ParseSnapshotTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n <= 0 {
+		return time.Time{}, fmt.Errorf("invalid time %q: expect RFC3339 or unix timestamp", raw)
+	}
+	if n >= 1e12 {
+		return time.UnixMilli(n), nil
+	}
+	return time.Unix(n, 0), nil
+}
@@ -3,7 +3,8 @@
 // 所有 API 在此集中管理，便于查看和维护
 //
 // 认证策略（开源项目，展示优先）：
-//   - Public: 所有只读查询，无需登录
+//   - Public: 所有只读查询，默认无需登录（MASTER_JWT_REQUIRE_READ_AUTH 开启后需要登录）
+//     携带 Token 时按用户的集群/命名空间授权范围过滤结果
 //   - Operator (2): 敏感信息查看、指令下发
 //   - Admin (3): 用户管理、系统配置
 //   - Viewer (1): 等同于游客，无额外权限
@@ -24,6 +25,8 @@ import (
 	sloHandler "AtlHyper/atlhyper_master_v2/gateway/handler/slo"
 	"AtlHyper/atlhyper_master_v2/gateway/middleware"
 	"AtlHyper/atlhyper_master_v2/github"
+	"AtlHyper/atlhyper_master_v2/oidc"
+	"AtlHyper/atlhyper_master_v2/rbac"
 	"AtlHyper/atlhyper_master_v2/service"
 )
 
@@ -37,10 +40,12 @@ type Router struct {
 	analyzeTrigger aiopsHandler.AnalyzeTrigger
 	ghClient       github.Client
 	deployer       deployer.Deployer
+	oidc           *oidc.Provider
+	rbac           *rbac.Resolver
 }
 
 // NewRouter 创建路由管理器
-func NewRouter(svc service.Service, db *database.DB, aiSvc ai.AIService, trigger aiopsHandler.AnalyzeTrigger, ghClient github.Client, dep deployer.Deployer) *Router {
+func NewRouter(svc service.Service, db *database.DB, aiSvc ai.AIService, trigger aiopsHandler.AnalyzeTrigger, ghClient github.Client, dep deployer.Deployer, oidcProvider *oidc.Provider) *Router {
 	return &Router{
 		mux:            http.NewServeMux(),
 		publicMux:      http.NewServeMux(),
@@ -50,6 +55,8 @@ func NewRouter(svc service.Service, db *database.DB, aiSvc ai.AIService, trigger
 		analyzeTrigger: trigger,
 		ghClient:       ghClient,
 		deployer:       dep,
+		oidc:           oidcProvider,
+		rbac:           rbac.NewResolver(db.RoleBinding),
 	}
 }
 
@@ -58,15 +65,15 @@ func (r *Router) Handler() http.Handler {
 	r.registerRoutes()
 
 	// 组合路由器：先检查公开路由，再检查需要认证的路由
-	return middleware.Logging(middleware.CORS(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		// 尝试公开路由
+	return middleware.Logging(middleware.CORS(middleware.SnapshotAt(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// 尝试公开路由（可选认证见 r.public）
 		if h, pattern := r.publicMux.Handler(req); pattern != "" {
 			h.ServeHTTP(w, req)
 			return
 		}
 		// 需要认证的路由
-		middleware.AuthRequired(r.mux).ServeHTTP(w, req)
-	})))
+		middleware.AuthRequired(middleware.Scope(r.rbac, r.mux)).ServeHTTP(w, req)
+	}))))
 }
 
 // registerRoutes 注册所有路由
@@ -76,6 +83,9 @@ func (r *Router) registerRoutes() {
 	overviewH := handler.NewOverviewHandler(r.service)
 	eventH := handler.NewEventHandler(r.service)
 	opsH := handler.NewOpsHandler(r.service)
+	execH := handler.NewExecHandler(r.service)
+	customResourceH := handler.NewCustomResourceHandler(r.service)
+	snapshotHistoryH := handler.NewSnapshotHistoryHandler(r.service)
 
 	// 创建 Handlers — K8s 资源 (package k8s)
 	podH := k8sHandler.NewPodHandler(r.service)
@@ -90,6 +100,7 @@ func (r *Router) registerRoutes() {
 	namespaceH := k8sHandler.NewNamespaceHandler(r.service)
 	jobH := k8sHandler.NewJobHandler(r.service)
 	cronjobH := k8sHandler.NewCronJobHandler(r.service)
+	hpaH := k8sHandler.NewHPAHandler(r.service)
 	pvH := k8sHandler.NewPVHandler(r.service)
 	pvcH := k8sHandler.NewPVCHandler(r.service)
 	networkPolicyH := k8sHandler.NewNetworkPolicyHandler(r.service)
@@ -109,19 +120,30 @@ func (r *Router) registerRoutes() {
 	aiopsGraphH := aiopsHandler.NewAIOpsGraphHandler(r.service)
 	aiopsBaselineH := aiopsHandler.NewAIOpsBaselineHandler(r.service)
 	aiopsRiskH := aiopsHandler.NewAIOpsRiskHandler(r.service)
-	aiopsIncidentH := aiopsHandler.NewAIOpsIncidentHandler(r.service)
+	aiopsIncidentH := aiopsHandler.NewAIOpsIncidentHandler(r.service, r.database.User)
+	// 事件人工处理与公开详情共用 /incidents/ 前缀，POST 需 Operator 权限并审计
+	aiopsIncidentH.SetActionHandler(r.audit("update", "aiops_incident")(middleware.RequireScopedRole(middleware.RoleOperator, aiopsIncidentH.Action)))
+	aiopsPostmortemH := aiopsHandler.NewAIOpsPostmortemHandler(r.service)
+	aiopsRunbookH := aiopsHandler.NewAIOpsRunbookHandler(r.service)
+	aiopsPolicyH := aiopsHandler.NewAIOpsPolicyHandler(r.service)
+	aiopsMaintenanceH := aiopsHandler.NewAIOpsMaintenanceHandler(r.service)
 	aiopsAIH := aiopsHandler.NewAIOpsAIHandler(r.service)
 	if r.analyzeTrigger != nil {
 		aiopsAIH.SetAnalyzeTrigger(r.analyzeTrigger)
 	}
 
 	// 创建 Handlers — 管理 (package admin)
-	userH := adminHandler.NewUserHandler(r.database.User)
+	userH := adminHandler.NewUserHandler(r.database.User, r.database.RoleBinding, r.database.UserIdentity)
 	commandH := adminHandler.NewCommandHandler(r.service)
 	notifyH := adminHandler.NewNotifyHandler(r.service)
+	oncallH := adminHandler.NewOncallHandler(r.service, r.database.User)
 	settingsH := adminHandler.NewSettingsHandler(r.service)
 	aiProviderH := adminHandler.NewAIProviderHandler(r.service)
 	auditH := adminHandler.NewAuditHandler(r.service)
+	agentTokenH := adminHandler.NewAgentTokenHandler(r.service)
+	execSessionH := adminHandler.NewExecSessionHandler(r.service)
+	roleBindingH := adminHandler.NewRoleBindingHandler(r.database.RoleBinding, r.database.User, r.rbac)
+	oidcH := adminHandler.NewOIDCHandler(r.oidc, r.database.User)
 
 	// ================================================================
 	// 公开路由（无需认证）
@@ -131,10 +153,18 @@ func (r *Router) registerRoutes() {
 	// 登录需要审计（记录成功/失败的登录尝试）
 	r.publicAudited("/api/v2/user/login", "login", "user", userH.Login)
 
-	r.public(func(register func(pattern string, h http.HandlerFunc)) {
-		// 健康检查
-		register("/health", healthCheck)
+	// SSO 登录（OIDC 授权码 + PKCE；本地密码登录保留为应急入口）
+	r.publicMux.HandleFunc("/api/v2/auth/oidc/config", oidcH.Config)
+	r.publicMux.HandleFunc("/api/v2/auth/oidc/login", oidcH.Login)
+	r.publicAudited("/api/v2/auth/oidc/callback", "login", "user", oidcH.Callback)
+
+	// 告警确认链接（邮件 / Slack 消息中附带，令牌即凭证）
+	r.publicAudited("/api/v2/oncall/ack", "update", "escalation", oncallH.AckLink)
 
+	// 健康检查（始终公开）
+	r.publicMux.HandleFunc("/health", healthCheck)
+
+	r.public(func(register func(pattern string, h http.HandlerFunc)) {
 		// ---------- 集群概览 ----------
 		register("/api/v2/overview", overviewH.Get)
 
@@ -142,6 +172,10 @@ func (r *Router) registerRoutes() {
 		register("/api/v2/clusters", clusterH.List)
 		register("/api/v2/clusters/", clusterH.Get)
 
+		// ---------- 快照历史（时间回溯） ----------
+		register("/api/v2/snapshots/history", snapshotHistoryH.History)
+		register("/api/v2/snapshots/diff", snapshotHistoryH.Diff)
+
 		// ---------- 工作负载查询 ----------
 		// Pod
 		register("/api/v2/pods", podH.List)
@@ -181,6 +215,11 @@ func (r *Router) registerRoutes() {
 		register("/api/v2/cronjobs", cronjobH.List)
 		register("/api/v2/cronjobs/", cronjobH.Get)
 
+		// ---------- 自动扩缩容查询 ----------
+		// HorizontalPodAutoscaler
+		register("/api/v2/hpas", hpaH.List)
+		register("/api/v2/hpas/", hpaH.Get)
+
 		// ---------- 存储查询 ----------
 		// PersistentVolume
 		register("/api/v2/pvs", pvH.List)
@@ -229,6 +268,7 @@ func (r *Router) registerRoutes() {
 		register("/api/v2/slo/domains/history", sloH.DomainHistory)
 		register("/api/v2/slo/domains/latency", sloH.LatencyDistribution)
 		register("/api/v2/slo/targets", sloH.Targets)
+		register("/api/v2/slo/alerts", sloH.Alerts)
 		register("/api/v2/slo/status-history", sloH.StatusHistory)
 
 		// ---------- SLO 服务网格查询（只读） ----------
@@ -270,6 +310,11 @@ func (r *Router) registerRoutes() {
 		register("/api/v2/aiops/incidents/stats", aiopsIncidentH.Stats)
 		register("/api/v2/aiops/incidents/patterns", aiopsIncidentH.Patterns)
 		register("/api/v2/aiops/incidents/", aiopsIncidentH.Detail)
+		register("/api/v2/aiops/runbooks", aiopsRunbookH.List)
+		register("/api/v2/aiops/runbook-runs", aiopsRunbookH.Runs)
+		register("/api/v2/aiops/policies", aiopsPolicyH.List)
+		register("/api/v2/aiops/effective-policy", aiopsPolicyH.Effective)
+		register("/api/v2/aiops/maintenance-windows", aiopsMaintenanceH.List)
 	})
 
 	// ================================================================
@@ -285,11 +330,22 @@ func (r *Router) registerRoutes() {
 		register("/api/v2/aiops/ai/reports", aiopsAIH.ReportsHandler)
 	})
 
-	// ConfigMap 详情、通知渠道、审计日志、AI 配置查询（不审计，只是查看）
+	// 自定义资源类型列表（每次请求经 Agent 实时查询 API Server）
+	r.operatorScoped(func(register func(pattern string, h http.HandlerFunc)) {
+		register("/api/v2/custom-resources/kinds", customResourceH.Kinds)
+	})
+
+	// ConfigMap 详情、通知渠道/路由/静默/抑制、值班、审计日志、AI 配置查询（不审计，只是查看）
 	r.operator(func(register func(pattern string, h http.HandlerFunc)) {
 		register("/api/v2/configmaps/", configmapH.Get)
 		register("/api/v2/secrets", secretH.List)
 		register("/api/v2/notify/channels", notifyH.ListChannels)
+		register("/api/v2/notify/routes", notifyH.ListRoutes)
+		register("/api/v2/notify/silences", notifyH.ListSilences)
+		register("/api/v2/notify/inhibit-rules", notifyH.ListInhibitRules)
+		register("/api/v2/oncall/schedules", oncallH.ListSchedules)
+		register("/api/v2/oncall/policies", oncallH.ListPolicies)
+		register("/api/v2/oncall/escalations", oncallH.ListEscalations)
 		register("/api/v2/audit/logs", auditH.List)
 		register("/api/v2/settings/ai", settingsH.AIConfigHandler)
 		register("/api/v2/ai/providers", aiProviderH.ProvidersHandler)
@@ -301,24 +357,29 @@ func (r *Router) registerRoutes() {
 
 	// ---------- 需要审计的敏感操作 ----------
 	// 指令下发
-	r.operatorAudited("/api/v2/commands", "execute", "command", commandH.Create)
+	r.operatorScopedAudited("/api/v2/commands", "execute", "command", commandH.Create)
 
 	// Pod 操作
-	r.operatorAudited("/api/v2/ops/pods/logs", "read", "pod", opsH.PodLogs)
-	r.operatorAudited("/api/v2/ops/pods/restart", "execute", "pod", opsH.PodRestart)
+	r.operatorScopedAudited("/api/v2/ops/pods/logs", "read", "pod", opsH.PodLogs)
+	r.operatorScopedAudited("/api/v2/ops/pods/restart", "execute", "pod", opsH.PodRestart)
+	// 交互式终端（WebSocket，会话结束后写入审计摘要，完整记录见 exec_sessions）
+	r.operatorScopedAudited("/api/v2/ops/pods/exec", "execute", "pod_exec", execH.PodExec)
+	// 日志跟踪（SSE，Pod 或 Deployment 全部 Pod 合并）
+	r.operatorScopedAudited("/api/v2/ops/logs/stream", "read", "pod_logs", execH.LogStream)
 
 	// Deployment 操作
-	r.operatorAudited("/api/v2/ops/deployments/scale", "execute", "deployment", opsH.DeploymentScale)
-	r.operatorAudited("/api/v2/ops/deployments/restart", "execute", "deployment", opsH.DeploymentRestart)
-	r.operatorAudited("/api/v2/ops/deployments/image", "execute", "deployment", opsH.DeploymentImage)
+	r.operatorScopedAudited("/api/v2/ops/deployments/scale", "execute", "deployment", opsH.DeploymentScale)
+	r.operatorScopedAudited("/api/v2/ops/deployments/restart", "execute", "deployment", opsH.DeploymentRestart)
+	r.operatorScopedAudited("/api/v2/ops/deployments/image", "execute", "deployment", opsH.DeploymentImage)
 
 	// Node 操作
-	r.operatorAudited("/api/v2/ops/nodes/cordon", "execute", "node", opsH.NodeCordon)
-	r.operatorAudited("/api/v2/ops/nodes/uncordon", "execute", "node", opsH.NodeUncordon)
+	r.operatorScopedAudited("/api/v2/ops/nodes/cordon", "execute", "node", opsH.NodeCordon)
+	r.operatorScopedAudited("/api/v2/ops/nodes/uncordon", "execute", "node", opsH.NodeUncordon)
+	r.operatorScopedAudited("/api/v2/ops/nodes/drain", "execute", "node", opsH.NodeDrain)
 
 	// ConfigMap/Secret 数据获取（敏感数据读取需要审计）
-	r.operatorAudited("/api/v2/ops/configmaps/data", "read", "configmap", opsH.ConfigMapData)
-	r.operatorAudited("/api/v2/ops/secrets/data", "read", "secret", opsH.SecretData)
+	r.operatorScopedAudited("/api/v2/ops/configmaps/data", "read", "configmap", opsH.ConfigMapData)
+	r.operatorScopedAudited("/api/v2/ops/secrets/data", "read", "secret", opsH.SecretData)
 
 	// ================================================================
 	// AI 对话（需要认证，Viewer+ 即可使用）
@@ -385,6 +446,13 @@ func (r *Router) registerRoutes() {
 	// 用户列表查询（不审计，只是查看）
 	r.admin(func(register func(pattern string, h http.HandlerFunc)) {
 		register("/api/v2/user/list", userH.List)
+		register("/api/v2/agent-tokens", agentTokenH.List)
+		register("/api/v2/exec/sessions", execSessionH.List)
+		register("/api/v2/exec/sessions/", execSessionH.Get)
+		register("/api/v2/role-bindings", roleBindingH.List)
+		register("/api/v2/notify/deliveries", notifyH.ListDeliveries)
+		register("/api/v2/notify/templates", notifyH.ListTemplates)
+		register("/api/v2/notify/templates/preview", notifyH.PreviewTemplate)
 	})
 
 	// ---------- 需要审计的管理操作 ----------
@@ -394,8 +462,28 @@ func (r *Router) registerRoutes() {
 	r.adminAudited("/api/v2/user/update-status", "update", "user", userH.UpdateStatus)
 	r.adminAudited("/api/v2/user/delete", "delete", "user", userH.Delete)
 
-	// 通知渠道管理（Operator 可管理）
+	// 角色绑定（按集群/命名空间授权）
+	r.adminAudited("/api/v2/role-bindings/", "update", "role_binding", roleBindingH.BindingHandler)
+
+	// Agent 凭证签发/轮换/吊销（需要 Admin 权限）
+	r.adminAudited("/api/v2/agent-tokens/", "update", "agent_token", agentTokenH.TokenHandler)
+
+	// 通知渠道、告警路由、静默、抑制规则管理（Operator 可管理）
 	r.operatorAudited("/api/v2/notify/channels/", "update", "notify", notifyH.ChannelHandler)
+	r.operatorAudited("/api/v2/notify/routes/", "update", "notify_route", notifyH.RouteHandler)
+	r.operatorAudited("/api/v2/notify/silences/", "update", "notify_silence", notifyH.SilenceHandler)
+	r.operatorAudited("/api/v2/notify/inhibit-rules/", "update", "notify_inhibit", notifyH.InhibitRuleHandler)
+
+	// 值班表、替班、升级策略管理与告警确认（Operator 可管理）
+	r.operatorAudited("/api/v2/oncall/schedules/", "update", "oncall_schedule", oncallH.ScheduleHandler)
+	r.operatorAudited("/api/v2/oncall/policies/", "update", "escalation_policy", oncallH.PolicyHandler)
+	r.operatorAudited("/api/v2/oncall/escalations/", "update", "escalation", oncallH.EscalationHandler)
+
+	// 通知投递记录详情与死信重发（需要 Admin 权限）
+	r.adminAudited("/api/v2/notify/deliveries/", "update", "notify_delivery", notifyH.DeliveryHandler)
+
+	// 自定义通知模板保存与恢复默认（需要 Admin 权限，保存时校验）
+	r.adminAudited("/api/v2/notify/templates/", "update", "notify_template", notifyH.TemplateHandler)
 
 	// AI 配置管理（需要 Admin 权限）
 	r.adminAudited("/api/v2/settings/ai/", "update", "ai_config", settingsH.AIConfigHandler)
@@ -408,16 +496,36 @@ func (r *Router) registerRoutes() {
 	// AI 报告详情 + 深度分析触发（Operator 权限，审计）
 	r.operatorAudited("/api/v2/aiops/ai/reports/", "read", "ai_report", aiopsAIH.ReportDetailHandler)
 	r.operatorAudited("/api/v2/aiops/ai/analyze", "execute", "ai_analysis", aiopsAIH.AnalyzeHandler)
+
+	// 事件复盘文档查看 / 生成 / 编辑（Operator 权限，审计）
+	r.operatorScopedAudited("/api/v2/aiops/postmortems/", "update", "aiops_postmortem", aiopsPostmortemH.Handler)
+
+	// Runbook 手动触发 / 审批 / 拒绝（Operator 权限，审计）
+	r.operatorScopedAudited("/api/v2/aiops/runbooks/", "execute", "aiops_runbook", aiopsRunbookH.Trigger)
+	r.operatorScopedAudited("/api/v2/aiops/runbook-runs/", "update", "aiops_runbook_run", aiopsRunbookH.RunAction)
+
+	// AIOps 策略版本历史 / 保存 / 删除 / 回滚（需要 Admin 权限，保存后引擎热加载）
+	r.adminAudited("/api/v2/aiops/policies/", "update", "aiops_policy", aiopsPolicyH.Handler)
+
+	// AIOps 维护窗口创建 / 修改 / 立即结束（Operator 权限，审计；窗口范围须在授权范围内）
+	r.operatorScopedAudited("/api/v2/aiops/maintenance-windows/", "update", "aiops_maintenance", aiopsMaintenanceH.Handler)
+
+	// 自定义资源 (CRD) 实例查询（Operator 权限，审计；内置资源由 Agent 拒绝）
+	r.operatorScopedAudited("/api/v2/custom-resources", "read", "custom_resource", customResourceH.Resources)
+
+	// 快照历史导出（离线回放录制文件）
+	r.operatorAudited("/api/v2/snapshots/export", "read", "snapshot_history", snapshotHistoryH.Export)
 }
 
 // ================================================================
 // 路由注册辅助函数
 // ================================================================
 
-// public 注册公开路由（无需认证）
+// public 注册公开只读路由（可选认证）
+// 携带 Token 时注入用户与授权范围；MASTER_JWT_REQUIRE_READ_AUTH 开启时要求登录
 func (r *Router) public(fn func(register func(pattern string, h http.HandlerFunc))) {
 	fn(func(pattern string, h http.HandlerFunc) {
-		r.publicMux.HandleFunc(pattern, h)
+		r.publicMux.Handle(pattern, middleware.OptionalAuth(middleware.Scope(r.rbac, h)))
 	})
 }
 
@@ -428,6 +536,13 @@ func (r *Router) operator(fn func(register func(pattern string, h http.HandlerFu
 	})
 }
 
+// operatorScoped 注册 Operator 权限路由，角色绑定中的 Operator 也可访问（具体目标由 Service 按范围校验）
+func (r *Router) operatorScoped(fn func(register func(pattern string, h http.HandlerFunc))) {
+	fn(func(pattern string, h http.HandlerFunc) {
+		r.mux.HandleFunc(pattern, middleware.RequireScopedRole(middleware.RoleOperator, h))
+	})
+}
+
 // admin 注册 Admin 权限路由
 func (r *Router) admin(fn func(register func(pattern string, h http.HandlerFunc))) {
 	fn(func(pattern string, h http.HandlerFunc) {
@@ -456,6 +571,14 @@ func (r *Router) operatorAudited(pattern, action, resource string, h http.Handle
 	r.mux.HandleFunc(pattern, wrapped)
 }
 
+// operatorScopedAudited 注册带审计的 Operator 权限路由，角色绑定中的 Operator 也可访问
+// 仅用于 Handler / Service 按 rbac.ScopeFrom(ctx).Allows 校验具体目标的路由
+// 顺序: Audit -> RequireScopedRole(Operator) -> Handler
+func (r *Router) operatorScopedAudited(pattern, action, resource string, h http.HandlerFunc) {
+	wrapped := r.audit(action, resource)(middleware.RequireScopedRole(middleware.RoleOperator, h))
+	r.mux.HandleFunc(pattern, wrapped)
+}
+
 // adminAudited 注册带审计的 Admin 权限路由
 // 顺序: Audit -> RequireMinRole(Admin) -> Handler
 func (r *Router) adminAudited(pattern, action, resource string, h http.HandlerFunc) {
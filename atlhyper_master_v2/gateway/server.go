@@ -17,6 +17,7 @@ import (
 	"AtlHyper/atlhyper_master_v2/deployer"
 	aiopsHandler "AtlHyper/atlhyper_master_v2/gateway/handler/aiops"
 	"AtlHyper/atlhyper_master_v2/github"
+	"AtlHyper/atlhyper_master_v2/oidc"
 	"AtlHyper/atlhyper_master_v2/service"
 	"AtlHyper/common/logger"
 )
@@ -32,6 +33,7 @@ type Server struct {
 	analyzeTrigger  aiopsHandler.AnalyzeTrigger
 	ghClient        github.Client
 	deployer        deployer.Deployer
+	oidc            *oidc.Provider
 	httpServer      *http.Server
 }
 
@@ -44,6 +46,7 @@ type Config struct {
 	AnalyzeTrigger aiopsHandler.AnalyzeTrigger  // 可选，nil 表示深度分析未启用
 	GitHubClient   github.Client               // 可选，nil 表示 GitHub 集成未配置
 	Deployer       deployer.Deployer           // 可选，nil 表示 Deployer 未启用
+	OIDC           *oidc.Provider              // 可选，nil 表示 SSO 登录未启用
 }
 
 // NewServer 创建 Server
@@ -56,13 +59,14 @@ func NewServer(cfg Config) *Server {
 		analyzeTrigger: cfg.AnalyzeTrigger,
 		ghClient:       cfg.GitHubClient,
 		deployer:       cfg.Deployer,
+		oidc:           cfg.OIDC,
 	}
 }
 
 // Start 启动 Server
 func (s *Server) Start() error {
 	// 使用 Router 统一管理路由（见 routes.go）
-	router := NewRouter(s.service, s.database, s.aiService, s.analyzeTrigger, s.ghClient, s.deployer)
+	router := NewRouter(s.service, s.database, s.aiService, s.analyzeTrigger, s.ghClient, s.deployer, s.oidc)
 
 	s.httpServer = &http.Server{
 		Addr:         fmt.Sprintf(":%d", s.port),
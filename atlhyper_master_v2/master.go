@@ -30,6 +30,11 @@ import (
 	"AtlHyper/atlhyper_master_v2/ai"
 	"AtlHyper/atlhyper_master_v2/aiops"
 	"AtlHyper/atlhyper_master_v2/aiops/enricher"
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+	"AtlHyper/atlhyper_master_v2/aiops/policy"
+	"AtlHyper/atlhyper_master_v2/aiops/postmortem"
+	"AtlHyper/atlhyper_master_v2/aiops/replay"
+	"AtlHyper/atlhyper_master_v2/aiops/runbook"
 	aiopscore "AtlHyper/atlhyper_master_v2/aiops/core"
 	"AtlHyper/atlhyper_master_v2/config"
 	"AtlHyper/atlhyper_master_v2/database"
@@ -44,12 +49,14 @@ import (
 	"AtlHyper/atlhyper_master_v2/mq"
 	"AtlHyper/atlhyper_master_v2/notifier"
 	"AtlHyper/atlhyper_master_v2/notifier/trigger"
+	"AtlHyper/atlhyper_master_v2/oidc"
 	"AtlHyper/atlhyper_master_v2/processor"
 	"AtlHyper/atlhyper_master_v2/service"
 	"AtlHyper/atlhyper_master_v2/service/operations"
 	"AtlHyper/atlhyper_master_v2/service/query"
 	"AtlHyper/atlhyper_master_v2/service/sync"
 	"AtlHyper/atlhyper_master_v2/slo"
+	"AtlHyper/atlhyper_master_v2/stream"
 	"AtlHyper/atlhyper_master_v2/tester"
 	"AtlHyper/common/logger"
 )
@@ -70,8 +77,15 @@ type Master struct {
 	alertManager notifier.AlertManager
 	heartbeat    *trigger.HeartbeatTrigger
 	eventTrigger *trigger.EventTrigger
+	// AIOps 事件告警触发器（可选）
+	incidentTrigger *trigger.IncidentTrigger
+	// SLO 燃烧率告警
+	sloBurnAlerter *slo.BurnAlerter
+	sloBurnTrigger *trigger.SLOBurnTrigger
 	// AIOps 引擎
 	aiopsEngine aiops.Engine
+	// AIOps Runbook 管理器（可选）
+	runbooks *runbook.Manager
 	// Deployer（GitOps CD）
 	deployer deployer.Deployer
 }
@@ -86,6 +100,8 @@ func NewMaster() (*Master, error) {
 		EventRetention:    cfg.DataHub.EventRetention,
 		HeartbeatExpire:   cfg.DataHub.HeartbeatExpire,
 		SnapshotRetention: cfg.DataHub.SnapshotRetention,
+		HistoryRetention:  cfg.DataHub.HistoryRetention,
+		HistoryInterval:   cfg.DataHub.HistoryInterval,
 		RedisAddr:         cfg.Redis.Addr,
 		RedisPassword:     cfg.Redis.Password,
 		RedisDB:           cfg.Redis.DB,
@@ -145,6 +161,24 @@ func NewMaster() (*Master, error) {
 	sloRouteUpdater := slo.NewRouteUpdater(db.SLO)
 	log.Info("SLO 路由映射更新器初始化完成")
 
+	// 4.2 初始化 SLO 燃烧率告警评估器（多窗口多燃烧率，通知在 11.3 订阅）
+	sloBurnAlerter := slo.NewBurnAlerter(store, db.SLO, slo.BurnAlerterConfig{
+		Interval: cfg.SLO.BurnRateInterval,
+	})
+	log.Info("SLO 燃烧率告警评估器初始化完成")
+
+	// 4.3 加载 AIOps 策略（阈值 / 风险配置按集群、命名空间、实体类型覆盖，修改后热加载）
+	aiopsPolicies := policy.NewManager(db.AIOpsPolicy, nil)
+	if err := aiopsPolicies.Load(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load aiops policies: %w", err)
+	}
+
+	// 加载维护窗口（窗口内状态机不创建事故，告警触发器丢弃匹配对象的告警）
+	aiopsMaintenance := maintenance.NewManager(db.AIOpsMaintenance)
+	if err := aiopsMaintenance.Load(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load aiops maintenance windows: %w", err)
+	}
+
 	// 4.4 初始化 AIOps 引擎
 	var aiopsEngine aiops.Engine
 	aiopsEngine = aiopscore.NewEngine(aiopscore.EngineConfig{
@@ -152,11 +186,24 @@ func NewMaster() (*Master, error) {
 		GraphRepo:     db.AIOpsGraph,
 		BaselineRepo:  db.AIOpsBaseline,
 		IncidentRepo:  db.AIOpsIncident,
+		FeedbackRepo:  db.AIOpsFeedback,
 		SLORepo:       db.SLO,
+		Policies:      aiopsPolicies,
+		Maintenance:   aiopsMaintenance,
 		FlushInterval: cfg.AIOps.FlushInterval,
 	})
 	log.Info("AIOps 引擎初始化完成")
 
+	// 4.5 快照录制（供 replay 子命令离线回放，可选）
+	var snapshotRecorder *replay.Recorder
+	if cfg.AIOps.RecordDir != "" {
+		snapshotRecorder, err = replay.NewRecorder(cfg.AIOps.RecordDir, cfg.AIOps.RecordInterval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init snapshot recorder: %w", err)
+		}
+		log.Info("快照录制已启用", "dir", cfg.AIOps.RecordDir, "interval", cfg.AIOps.RecordInterval)
+	}
+
 	// 5. 初始化 Processor（写入路径）
 	proc := processor.NewProcessor(processor.Config{
 		Store: store,
@@ -171,13 +218,23 @@ func NewMaster() (*Master, error) {
 			}
 			// AIOps 引擎处理
 			aiopsEngine.OnSnapshot(clusterID)
+			// 录制快照（离线回放用）
+			if snapshotRecorder != nil {
+				if snap, err := store.GetSnapshot(clusterID); err == nil {
+					snapshotRecorder.Record(snap)
+				}
+			}
 		},
 	})
 	log.Info("数据处理器初始化完成")
 
 	// 6. 初始化 Operations（写入路径，AI Service 依赖 cmdOps）
 	cmdOps := operations.NewCommandService(bus, db.Command)
-	adminOps := operations.NewAdminService(db.Notify, db.Settings, db.AIProvider, db.AISettings, db.AIRoleBudget)
+	adminOps := operations.NewAdminService(db.Notify, db.NotifyRoute, db.NotifySilence, db.NotifyInhibit, db.NotifyDelivery, db.NotifyTemplate, db.Settings, db.AIProvider, db.AISettings, db.AIRoleBudget, db.AgentToken)
+	adminOps.SetAgentTokenGrace(cfg.AgentSDK.TokenGrace)
+	execHub := stream.NewHub()
+	execOps := operations.NewExecService(cmdOps, execHub, db.ExecSession)
+	execOps.SetLimits(cfg.Exec.MaxDuration, cfg.Exec.AttachTimeout)
 	log.Info("操作服务初始化完成")
 
 	// 7. 初始化 AI Service（Enricher 依赖 AIService）
@@ -198,6 +255,26 @@ func NewMaster() (*Master, error) {
 	aiopsEngine.SetIncidentNotify(aiopsEnricher.NotifyIncidentEvent)
 	log.Info("AIOps Enricher 初始化完成（后台自动分析已启用）")
 
+	// 7.2 初始化 Runbook 管理器（可选，未配置目录则跳过）
+	var runbooks *runbook.Manager
+	if cfg.Runbook.Dir != "" {
+		defs, err := runbook.LoadDir(cfg.Runbook.Dir)
+		if err != nil {
+			log.Error("Runbook 加载存在错误", "dir", cfg.Runbook.Dir, "err", err)
+		}
+		runbooks = runbook.NewManager(runbook.Config{
+			Runbooks:       defs,
+			Source:         aiopsEngine,
+			Commands:       cmdOps,
+			Snapshots:      store,
+			Repo:           db.AIOpsRunbookRun,
+			DryRun:         cfg.Runbook.DryRun,
+			MaxRunsPerHour: cfg.Runbook.MaxRunsPerHour,
+		})
+		aiopsEngine.AddTransitionListener(runbooks.OnTransition)
+		log.Info("Runbook 管理器初始化完成", "dir", cfg.Runbook.Dir, "runbooks", len(defs), "dryRun", cfg.Runbook.DryRun)
+	}
+
 	// 8. 初始化 Query（读取路径）— 全部依赖通过构造函数注入
 	q := query.NewQueryService(query.QueryServiceDeps{
 		Store:       store,
@@ -206,25 +283,58 @@ func NewMaster() (*Master, error) {
 		SLORepo:     db.SLO,
 		AIOpsEngine: aiopsEngine,
 		AIOpsAI:     aiopsEnricher,
+		SLOBurn:     sloBurnAlerter,
+		Runbooks:    runbooks,
+		Policies:    aiopsPolicies,
+		Maintenance: aiopsMaintenance,
 		AdminRepos: query.AdminRepos{
-			Audit:      db.Audit,
-			Command:    db.Command,
-			Notify:     db.Notify,
-			Settings:   db.Settings,
-			AIProvider: db.AIProvider,
-			AISettings: db.AISettings,
-			AIModel:    db.AIModel,
-			AIBudget:   db.AIRoleBudget,
-			AIReport:   db.AIReport,
+			Audit:          db.Audit,
+			Command:        db.Command,
+			Notify:         db.Notify,
+			NotifyRoute:    db.NotifyRoute,
+			NotifySilence:  db.NotifySilence,
+			NotifyInhibit:  db.NotifyInhibit,
+			NotifyDelivery: db.NotifyDelivery,
+			NotifyTemplate: db.NotifyTemplate,
+			Settings:       db.Settings,
+			AIProvider:     db.AIProvider,
+			AISettings:     db.AISettings,
+			AIModel:        db.AIModel,
+			AIBudget:       db.AIRoleBudget,
+			AIReport:       db.AIReport,
+			Postmortem:     db.AIOpsPostmortem,
+			AgentToken:     db.AgentToken,
+			ExecSession:    db.ExecSession,
+			Oncall:         db.Oncall,
+			OncallOverride: db.OncallOverride,
+			Escalation:     db.Escalation,
+			EscalationLog:  db.EscalationLog,
 		},
 	})
 	log.Info("查询层初始化完成")
 
 	// 初始化 SLO 写入服务
 	sloOps := operations.NewSLOService(db.SLO)
+	oncallOps := operations.NewOncallService(db.Oncall, db.OncallOverride, db.Escalation, db.EscalationLog)
+	aiopsOps := operations.NewAIOpsService(aiopsEngine)
+	aiopsOps.SetPostmortem(postmortem.NewGenerator(postmortem.Config{
+		Engine:      aiopsEngine,
+		Repo:        db.AIOpsPostmortem,
+		ReportRepo:  db.AIReport,
+		DeployRepo:  db.DeployHistory,
+		CommandRepo: db.Command,
+		SLORepo:     db.SLO,
+		Store:       store,
+		AIService:   aiService,
+	}), db.AIOpsPostmortem)
+	if runbooks != nil {
+		aiopsOps.SetRunbooks(runbooks)
+	}
+	aiopsOps.SetPolicies(aiopsPolicies)
+	aiopsOps.SetMaintenance(aiopsMaintenance)
 
 	// 组合统一 Service
-	svc := service.NewService(q, cmdOps, adminOps, sloOps)
+	svc := service.NewService(q, cmdOps, adminOps, sloOps, execOps, oncallOps, aiopsOps)
 
 	// 8. 初始化 AgentSDK
 	agentServer := agentsdk.NewServer(agentsdk.Config{
@@ -233,8 +343,17 @@ func NewMaster() (*Master, error) {
 		Bus:            bus,
 		Processor:      proc,
 		CmdRepo:        db.Command,
+		TokenRepo:      db.AgentToken,
+		RequireToken:   cfg.AgentSDK.RequireToken,
+		TLS: agentsdk.TLSConfig{
+			CertFile:     cfg.AgentSDK.TLSCertFile,
+			KeyFile:      cfg.AgentSDK.TLSKeyFile,
+			ClientCAFile: cfg.AgentSDK.TLSClientCAFile,
+		},
+		ExecHub: execHub,
 	})
-	log.Info("AgentSDK 初始化完成", "port", cfg.Server.AgentSDKPort)
+	log.Info("AgentSDK 初始化完成", "port", cfg.Server.AgentSDKPort,
+		"requireToken", cfg.AgentSDK.RequireToken, "tls", cfg.AgentSDK.TLSCertFile != "")
 
 	// 9. 注册 AIOps Tool（AI Chat 中使用）
 	aiService.RegisterTool("analyze_incident", func(ctx context.Context, clusterID string, params map[string]interface{}) (string, error) {
@@ -309,7 +428,7 @@ func NewMaster() (*Master, error) {
 		}
 		cmdParams["since"] = since
 
-		resp, err := cmdOps.CreateCommand(&model.CreateCommandRequest{
+		resp, err := cmdOps.CreateCommand(ctx, &model.CreateCommandRequest{
 			ClusterID: clusterID,
 			Action:    command.ActionQueryTraces,
 			Source:    "ai",
@@ -353,7 +472,7 @@ func NewMaster() (*Master, error) {
 		}
 		cmdParams["since"] = since
 
-		resp, err := cmdOps.CreateCommand(&model.CreateCommandRequest{
+		resp, err := cmdOps.CreateCommand(ctx, &model.CreateCommandRequest{
 			ClusterID: clusterID,
 			Action:    command.ActionQueryLogs,
 			Source:    "ai",
@@ -502,10 +621,30 @@ func NewMaster() (*Master, error) {
 	log.Info("AI Tool 注册完成 (8 个基础)")
 
 	// 10. 初始化 AlertManager（告警管理器）
-	alertMgr, err := notifier.NewManager(db.Notify)
+	alertMgr, err := notifier.NewManager(notifier.ManagerRepos{
+		Channels:   db.Notify,
+		Routes:     db.NotifyRoute,
+		Silences:   db.NotifySilence,
+		Inhibits:   db.NotifyInhibit,
+		Deliveries: db.NotifyDelivery,
+		Templates:  db.NotifyTemplate,
+
+		Users:       db.User,
+		Schedules:   db.Oncall,
+		Overrides:   db.OncallOverride,
+		Policies:    db.Escalation,
+		Escalations: db.EscalationLog,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to init alert manager: %w", err)
 	}
+	alertMgr.SetAckURL(cfg.Oncall.AckURL)
+	// 自定义模板保存/删除后立即生效
+	adminOps.SetTemplateListener(func() {
+		if err := alertMgr.ReloadTemplates(context.Background()); err != nil {
+			log.Warn("重新加载通知模板失败", "err", err)
+		}
+	})
 	log.Info("告警管理器初始化完成")
 
 	// 11. 初始化 HeartbeatTrigger（心跳检测触发器）
@@ -513,6 +652,7 @@ func NewMaster() (*Master, error) {
 		CheckInterval: cfg.DataHub.HeartbeatExpire / 2,
 		OfflineAfter:  cfg.DataHub.HeartbeatExpire,
 	})
+	heartbeat.SetMaintenance(aiopsMaintenance)
 	log.Info("心跳检测触发器初始化完成")
 
 	// 11.1 初始化 EventTrigger（事件告警触发器，可选）
@@ -526,9 +666,33 @@ func NewMaster() (*Master, error) {
 				CheckInterval: cfg.EventAlert.CheckInterval,
 			},
 		)
+		eventTrigger.SetMaintenance(aiopsMaintenance)
 		log.Info("事件告警触发器初始化完成")
 	}
 
+	// 11.2 初始化 IncidentTrigger（AIOps 事件告警，订阅状态机转换，可选）
+	var incidentTrigger *trigger.IncidentTrigger
+	if cfg.IncidentAlert.Enabled {
+		incidentTrigger = trigger.NewIncidentTrigger(
+			aiopsEngine,
+			db.AIReport,
+			alertMgr,
+			trigger.IncidentConfig{
+				NotifyWarning: cfg.IncidentAlert.NotifyWarning,
+				WebURL:        cfg.IncidentAlert.WebURL,
+			},
+		)
+		incidentTrigger.SetMaintenance(aiopsMaintenance)
+		aiopsEngine.AddTransitionListener(incidentTrigger.OnTransition)
+		log.Info("AIOps 事件告警触发器初始化完成", "warning", cfg.IncidentAlert.NotifyWarning)
+	}
+
+	// 11.3 初始化 SLOBurnTrigger（SLO 燃烧率告警通知，订阅评估器状态变化）
+	sloBurnTrigger := trigger.NewSLOBurnTrigger(alertMgr)
+	sloBurnTrigger.SetMaintenance(aiopsMaintenance)
+	sloBurnAlerter.AddListener(sloBurnTrigger.OnBurnAlert)
+	log.Info("SLO 燃烧率告警触发器初始化完成")
+
 	// 11.5 初始化 GitHub Client（可选，未配置则跳过）
 	var ghClient github.Client
 	if cfg.GitHub.AppID > 0 && cfg.GitHub.PrivateKeyPath != "" {
@@ -565,13 +729,32 @@ func NewMaster() (*Master, error) {
 		log.Info("GitHub + CD Tool 注册完成 (5 个)")
 	}
 
-	// 11.7 初始化 Deployer（GitOps CD，仅在 GitHub Client 可用时启用）
+	// 11.7 初始化 Deployer（GitOps CD，仅在 GitHub Client 可用时启用；部署期间自动开启维护窗口）
 	var deployerService deployer.Deployer
 	if ghClient != nil {
-		deployerService = deployer.NewService(ghClient, db, bus)
+		deployerService = deployer.NewService(ghClient, db, bus, aiopsMaintenance)
 		log.Info("Deployer 初始化完成")
 	}
 
+	// 11.8 初始化 OIDC 单点登录（可选）
+	var oidcProvider *oidc.Provider
+	if cfg.OIDC.Enabled {
+		oidcProvider = oidc.NewProvider(oidc.Config{
+			IssuerURL:      cfg.OIDC.IssuerURL,
+			ClientID:       cfg.OIDC.ClientID,
+			ClientSecret:   cfg.OIDC.ClientSecret,
+			RedirectURL:    cfg.OIDC.RedirectURL,
+			Scopes:         cfg.OIDC.Scopes,
+			GroupsClaim:    cfg.OIDC.GroupsClaim,
+			AdminGroups:    cfg.OIDC.AdminGroups,
+			OperatorGroups: cfg.OIDC.OperatorGroups,
+			ViewerGroups:   cfg.OIDC.ViewerGroups,
+			DefaultRole:    cfg.OIDC.DefaultRole,
+			StateTTL:       cfg.OIDC.StateTTL,
+		}, db.User, db.UserIdentity)
+		log.Info("OIDC 单点登录已启用", "issuer", cfg.OIDC.IssuerURL)
+	}
+
 	// 12. 初始化 Gateway
 	gw := gateway.NewServer(gateway.Config{
 		Port:           cfg.Server.GatewayPort,
@@ -581,6 +764,7 @@ func NewMaster() (*Master, error) {
 		AnalyzeTrigger: aiopsEnricher,
 		GitHubClient:   ghClient,
 		Deployer:       deployerService,
+		OIDC:           oidcProvider,
 	})
 	log.Info("Gateway 初始化完成", "port", cfg.Server.GatewayPort)
 
@@ -604,7 +788,11 @@ func NewMaster() (*Master, error) {
 		alertManager: alertMgr,
 		heartbeat:      heartbeat,
 		eventTrigger:   eventTrigger,
+		incidentTrigger: incidentTrigger,
+		sloBurnAlerter:  sloBurnAlerter,
+		sloBurnTrigger:  sloBurnTrigger,
 		aiopsEngine:    aiopsEngine,
+		runbooks:       runbooks,
 		deployer:       deployerService,
 	}, nil
 }
@@ -643,6 +831,11 @@ func (m *Master) Run(ctx context.Context) error {
 		}
 	}
 
+	// 启动 SLO 燃烧率告警评估
+	if err := m.sloBurnAlerter.Start(); err != nil {
+		return fmt.Errorf("failed to start slo burn alerter: %w", err)
+	}
+
 	// 启动 AIOps 引擎
 	if m.aiopsEngine != nil {
 		if err := m.aiopsEngine.Start(ctx); err != nil {
@@ -650,6 +843,13 @@ func (m *Master) Run(ctx context.Context) error {
 		}
 	}
 
+	// 启动 Runbook 管理器
+	if m.runbooks != nil {
+		if err := m.runbooks.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start runbook manager: %w", err)
+		}
+	}
+
 	// 启动 Deployer
 	if m.deployer != nil {
 		if err := m.deployer.Start(ctx); err != nil {
@@ -723,6 +923,28 @@ func (m *Master) Stop() error {
 		}
 	}
 
+	// 停止 Runbook 管理器（引擎停止后不再有新的匹配，取消进行中的执行）
+	if m.runbooks != nil {
+		if err := m.runbooks.Stop(); err != nil {
+			log.Error("停止 Runbook 管理器失败", "err", err)
+		}
+	}
+
+	// 停止 SLO 燃烧率告警评估与通知
+	if err := m.sloBurnAlerter.Stop(); err != nil {
+		log.Error("停止 SLO 燃烧率告警评估失败", "err", err)
+	}
+	if err := m.sloBurnTrigger.Stop(); err != nil {
+		log.Error("停止 SLO 燃烧率告警触发器失败", "err", err)
+	}
+
+	// 停止 IncidentTrigger（引擎停止后，等待进行中的通知发送完成）
+	if m.incidentTrigger != nil {
+		if err := m.incidentTrigger.Stop(); err != nil {
+			log.Error("停止 AIOps 事件告警触发器失败", "err", err)
+		}
+	}
+
 	// 停止 Deployer
 	if m.deployer != nil {
 		if err := m.deployer.Stop(); err != nil {
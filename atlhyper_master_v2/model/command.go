@@ -18,3 +18,29 @@ type CreateCommandResponse struct {
 	CommandID string `json:"commandId"`
 	Status    string `json:"status"`
 }
+
+// ExecSessionRequest 交互式 exec 会话请求（用户信息由 Gateway 从认证上下文填充）
+type ExecSessionRequest struct {
+	ClusterID string
+	Namespace string
+	Pod       string
+	Container string
+	Command   []string
+	TTY       bool
+	UserID    int64
+	Username  string
+}
+
+// LogStreamRequest 日志跟踪会话请求
+// Kind 为 Pod 或 Deployment；Container 为空时跟踪全部容器
+type LogStreamRequest struct {
+	ClusterID    string
+	Kind         string
+	Namespace    string
+	Name         string
+	Container    string
+	TailLines    int64
+	SinceSeconds int64
+	UserID       int64
+	Username     string
+}
@@ -0,0 +1,89 @@
+// atlhyper_master_v2/model/convert/hpa.go
+// cluster.HorizontalPodAutoscaler → model.HPAItem 转换函数
+package convert
+
+import (
+	"strings"
+
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/model_v3/cluster"
+)
+
+// HPAItem 转换为列表项
+func HPAItem(src *cluster.HorizontalPodAutoscaler) model.HPAItem {
+	return model.HPAItem{
+		Name:            src.Name,
+		Namespace:       src.Namespace,
+		Target:          src.ScaleTargetKind + "/" + src.ScaleTargetName,
+		MinReplicas:     src.MinReplicas,
+		MaxReplicas:     src.MaxReplicas,
+		CurrentReplicas: src.CurrentReplicas,
+		DesiredReplicas: src.DesiredReplicas,
+		Metrics:         hpaMetricsSummary(src.Metrics),
+		AtMaxReplicas:   src.IsAtMaxReplicas(),
+		CreatedAt:       src.CreatedAt.Format(timeFormat),
+		Age:             formatAge(src.CreatedAt),
+	}
+}
+
+// HPAItems 转换多个 HPA 为列表项
+func HPAItems(src []cluster.HorizontalPodAutoscaler) []model.HPAItem {
+	if src == nil {
+		return []model.HPAItem{}
+	}
+	result := make([]model.HPAItem, len(src))
+	for i := range src {
+		result[i] = HPAItem(&src[i])
+	}
+	return result
+}
+
+// HPADetail 转换为详情
+func HPADetail(src *cluster.HorizontalPodAutoscaler) model.HPADetail {
+	detail := model.HPADetail{
+		Name:      src.Name,
+		Namespace: src.Namespace,
+		UID:       src.UID,
+		CreatedAt: src.CreatedAt.Format(timeFormat),
+		Age:       formatAge(src.CreatedAt),
+
+		ScaleTargetKind: src.ScaleTargetKind,
+		ScaleTargetName: src.ScaleTargetName,
+
+		MinReplicas:     src.MinReplicas,
+		MaxReplicas:     src.MaxReplicas,
+		CurrentReplicas: src.CurrentReplicas,
+		DesiredReplicas: src.DesiredReplicas,
+
+		AtMaxReplicas:  src.IsAtMaxReplicas(),
+		ScalingLimited: src.IsScalingLimited(),
+
+		LastScaleTime: formatTimePtr(src.LastScaleTime),
+		LastScaleAgo:  formatTimeAgo(src.LastScaleTime),
+
+		Labels: src.Labels,
+	}
+
+	if len(src.Metrics) > 0 {
+		detail.Metrics = src.Metrics
+	}
+	if len(src.Conditions) > 0 {
+		detail.Conditions = src.Conditions
+	}
+
+	return detail
+}
+
+// hpaMetricsSummary 生成列表展示用的指标摘要（与 kubectl get hpa 的 TARGETS 列一致）
+// 当前值未知时显示 <unknown>
+func hpaMetricsSummary(metrics []cluster.HPAMetric) string {
+	parts := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		current := m.Current
+		if current == "" {
+			current = "<unknown>"
+		}
+		parts = append(parts, m.Name+": "+current+"/"+m.Target)
+	}
+	return strings.Join(parts, ", ")
+}
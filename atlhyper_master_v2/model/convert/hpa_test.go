@@ -0,0 +1,105 @@
+package convert
+
+import (
+	"testing"
+	"time"
+
+	model_v3 "AtlHyper/model_v3"
+	"AtlHyper/model_v3/cluster"
+)
+
+func testHPA() *cluster.HorizontalPodAutoscaler {
+	scaled := time.Date(2026, 2, 13, 10, 30, 0, 0, time.UTC)
+	return &cluster.HorizontalPodAutoscaler{
+		CommonMeta: model_v3.CommonMeta{
+			UID:       "hpa-uid-1",
+			Name:      "web",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "web"},
+			CreatedAt: time.Date(2026, 2, 13, 10, 0, 0, 0, time.UTC),
+		},
+		ScaleTargetKind: "Deployment",
+		ScaleTargetName: "web",
+		MinReplicas:     2,
+		MaxReplicas:     5,
+		CurrentReplicas: 5,
+		DesiredReplicas: 5,
+		Metrics: []cluster.HPAMetric{
+			{Type: "Resource", Name: "cpu", TargetType: "Utilization", Target: "80%", Current: "95%"},
+			{Type: "Pods", Name: "rps", TargetType: "AverageValue", Target: "100"},
+		},
+		Conditions: []cluster.WorkloadCondition{
+			{Type: "ScalingLimited", Status: "True", Reason: "TooManyReplicas"},
+		},
+		LastScaleTime: &scaled,
+	}
+}
+
+func TestHPAItem_FieldMapping(t *testing.T) {
+	item := HPAItem(testHPA())
+
+	if item.Name != "web" || item.Namespace != "default" {
+		t.Errorf("Name/Namespace = %q/%q", item.Name, item.Namespace)
+	}
+	if item.Target != "Deployment/web" {
+		t.Errorf("Target = %q, want %q", item.Target, "Deployment/web")
+	}
+	if item.MinReplicas != 2 || item.MaxReplicas != 5 || item.CurrentReplicas != 5 || item.DesiredReplicas != 5 {
+		t.Errorf("replicas = %d/%d/%d/%d", item.MinReplicas, item.MaxReplicas, item.CurrentReplicas, item.DesiredReplicas)
+	}
+	if item.Metrics != "cpu: 95%/80%, rps: <unknown>/100" {
+		t.Errorf("Metrics = %q", item.Metrics)
+	}
+	if !item.AtMaxReplicas {
+		t.Error("AtMaxReplicas = false, want true")
+	}
+	if item.CreatedAt != "2026-02-13T10:00:00Z" {
+		t.Errorf("CreatedAt = %q, want %q", item.CreatedAt, "2026-02-13T10:00:00Z")
+	}
+	if item.Age == "" {
+		t.Error("Age should not be empty")
+	}
+}
+
+func TestHPAItems_NilReturnsEmpty(t *testing.T) {
+	items := HPAItems(nil)
+	if items == nil || len(items) != 0 {
+		t.Errorf("HPAItems(nil) = %v, want empty slice", items)
+	}
+}
+
+func TestHPADetail_FieldMapping(t *testing.T) {
+	detail := HPADetail(testHPA())
+
+	if detail.UID != "hpa-uid-1" {
+		t.Errorf("UID = %q, want %q", detail.UID, "hpa-uid-1")
+	}
+	if detail.ScaleTargetKind != "Deployment" || detail.ScaleTargetName != "web" {
+		t.Errorf("ScaleTarget = %s/%s", detail.ScaleTargetKind, detail.ScaleTargetName)
+	}
+	if !detail.AtMaxReplicas || !detail.ScalingLimited {
+		t.Errorf("AtMaxReplicas/ScalingLimited = %v/%v, want true/true", detail.AtMaxReplicas, detail.ScalingLimited)
+	}
+	if detail.LastScaleTime != "2026-02-13T10:30:00Z" {
+		t.Errorf("LastScaleTime = %q", detail.LastScaleTime)
+	}
+	if detail.Metrics == nil || detail.Conditions == nil {
+		t.Error("Metrics and Conditions should be populated")
+	}
+	if detail.Labels["app"] != "web" {
+		t.Errorf("Labels = %v", detail.Labels)
+	}
+}
+
+func TestHPADetail_EmptyOptionalFields(t *testing.T) {
+	detail := HPADetail(&cluster.HorizontalPodAutoscaler{
+		CommonMeta:  model_v3.CommonMeta{Name: "api", Namespace: "default"},
+		MaxReplicas: 3,
+	})
+	if detail.Metrics != nil || detail.Conditions != nil {
+		t.Error("empty Metrics/Conditions should be omitted")
+	}
+	if detail.LastScaleTime != "" || detail.AtMaxReplicas {
+		t.Errorf("unexpected detail: %+v", detail)
+	}
+}
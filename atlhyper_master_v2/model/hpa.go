@@ -0,0 +1,52 @@
+// atlhyper_master_v2/model/hpa.go
+// HorizontalPodAutoscaler Web API 响应类型（camelCase JSON tag，扁平结构）
+package model
+
+// HPAItem HPA 列表项
+type HPAItem struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	Target          string `json:"target"` // Kind/Name
+	MinReplicas     int32  `json:"minReplicas"`
+	MaxReplicas     int32  `json:"maxReplicas"`
+	CurrentReplicas int32  `json:"currentReplicas"`
+	DesiredReplicas int32  `json:"desiredReplicas"`
+	Metrics         string `json:"metrics"` // 如 "cpu: 95%/80%"
+	AtMaxReplicas   bool   `json:"atMaxReplicas"`
+	CreatedAt       string `json:"createdAt"`
+	Age             string `json:"age"`
+}
+
+// HPADetail HPA 详情
+type HPADetail struct {
+	// 基本信息
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	UID       string `json:"uid"`
+	CreatedAt string `json:"createdAt"`
+	Age       string `json:"age"`
+
+	// 扩缩容目标
+	ScaleTargetKind string `json:"scaleTargetKind"`
+	ScaleTargetName string `json:"scaleTargetName"`
+
+	// 副本
+	MinReplicas     int32 `json:"minReplicas"`
+	MaxReplicas     int32 `json:"maxReplicas"`
+	CurrentReplicas int32 `json:"currentReplicas"`
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// 饱和状态
+	AtMaxReplicas  bool `json:"atMaxReplicas"`
+	ScalingLimited bool `json:"scalingLimited"`
+
+	LastScaleTime string `json:"lastScaleTime"`
+	LastScaleAgo  string `json:"lastScaleAgo"`
+
+	// 指标与条件
+	Metrics    interface{} `json:"metrics,omitempty"`
+	Conditions interface{} `json:"conditions,omitempty"`
+
+	// 元数据
+	Labels map[string]string `json:"labels,omitempty"`
+}
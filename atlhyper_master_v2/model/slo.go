@@ -165,6 +165,27 @@ type SLOTargetResponse struct {
 	UpdatedAt          string `json:"updatedAt"`
 }
 
+// ==================== SLO 燃烧率告警 API 响应类型 ====================
+
+// SLOBurnAlert 触发中的 SLO 燃烧率告警（API 响应，camelCase）
+type SLOBurnAlert struct {
+	ClusterID          string  `json:"clusterId"`
+	ServiceKey         string  `json:"serviceKey"`
+	Namespace          string  `json:"namespace,omitempty"` // ServiceKey 对应的 Service（无法解析为空）
+	ServiceName        string  `json:"serviceName,omitempty"`
+	Rule               string  `json:"rule"`     // fast / slow
+	Severity           string  `json:"severity"` // critical / warning
+	LongWindow         string  `json:"longWindow"`
+	ShortWindow        string  `json:"shortWindow"`
+	Threshold          float64 `json:"threshold"`
+	LongBurnRate       float64 `json:"longBurnRate"`
+	ShortBurnRate      float64 `json:"shortBurnRate"`
+	AvailabilityTarget float64 `json:"availabilityTarget"`
+	BudgetWindow       string  `json:"budgetWindow"` // 目标所属时间范围: 30d / 7d / 1d
+	FiringSince        string  `json:"firingSince"`
+	LastEvaluated      string  `json:"lastEvaluated"`
+}
+
 // ==================== API 请求类型 ====================
 
 // UpdateSLOTargetRequest 更新 SLO 目标请求
@@ -0,0 +1,24 @@
+// atlhyper_master_v2/model/snapshot_history.go
+// 快照历史（时间回溯）Web API 响应类型
+package model
+
+import (
+	"time"
+
+	"AtlHyper/model_v3/cluster"
+)
+
+// SnapshotDiff 两个时间点之间的集群状态差异
+type SnapshotDiff struct {
+	ClusterID string `json:"clusterId"`
+
+	// 请求的时间点与实际命中的快照时间
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	FromFetchedAt time.Time `json:"fromFetchedAt"`
+	ToFetchedAt   time.Time `json:"toFetchedAt"`
+
+	// Summary 按资源类型统计变更数量: kind -> op -> count
+	Summary map[string]map[cluster.ChangeOp]int `json:"summary"`
+	Changes []cluster.ResourceChange            `json:"changes"`
+}
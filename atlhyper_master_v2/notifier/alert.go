@@ -17,5 +17,7 @@ type Source string
 const (
 	SourceAgentHeartbeat Source = "agent_heartbeat"
 	SourceK8sEvent       Source = "k8s_event"
+	SourceAIOps          Source = "aiops"
+	SourceSLO            Source = "slo"
 	SourceManual         Source = "manual"
 )
@@ -0,0 +1,335 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// recorder 记录 httptest 服务端收到的请求
+type recorder struct {
+	mu       sync.Mutex
+	requests []*recordedRequest
+}
+
+type recordedRequest struct {
+	header http.Header
+	query  map[string]string
+	body   []byte
+}
+
+func (r *recorder) record(req *http.Request) *recordedRequest {
+	body, _ := io.ReadAll(req.Body)
+	rec := &recordedRequest{header: req.Header.Clone(), query: map[string]string{}, body: body}
+	for k := range req.URL.Query() {
+		rec.query[k] = req.URL.Query().Get(k)
+	}
+	r.mu.Lock()
+	r.requests = append(r.requests, rec)
+	r.mu.Unlock()
+	return rec
+}
+
+func (r *recorder) all() []*recordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*recordedRequest(nil), r.requests...)
+}
+
+// newServer 按请求序号返回状态码与响应体
+func newServer(t *testing.T, respond func(n int) (int, string)) (*httptest.Server, *recorder) {
+	t.Helper()
+	rec := &recorder{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rec.record(req)
+		status, body := respond(len(rec.all()))
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, rec
+}
+
+func ok(int) (int, string) { return http.StatusOK, "" }
+
+func testMessage() *Message {
+	return &Message{
+		Subject:  "Agent 离线",
+		Body:     "**集群**: prod\nAgent 心跳超时",
+		Format:   "markdown",
+		Severity: "critical",
+		Status:   "firing",
+		DedupKey: "atlhyper-abc",
+		Labels:   map[string]string{"cluster": "prod", "alertname": "heartbeat_offline"},
+	}
+}
+
+func TestWebhook_SignedPayload(t *testing.T) {
+	srv, rec := newServer(t, ok)
+	n := NewWebhookNotifier(WebhookConfig{
+		URL:     srv.URL,
+		Secret:  "s3cret",
+		Headers: map[string]string{"X-Team": "sre"},
+	})
+	if err := n.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	req := rec.all()[0]
+	ts := req.header.Get(webhookTimestampHeader)
+	if ts == "" {
+		t.Fatal("missing timestamp header")
+	}
+	if got, want := req.header.Get(webhookSignatureHeader), SignWebhook("s3cret", ts, req.body); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if req.header.Get("X-Team") != "sre" {
+		t.Errorf("custom header missing: %v", req.header)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload.Status != "firing" || len(payload.Alerts) != 1 || payload.Alerts[0].DedupKey != "atlhyper-abc" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestWebhook_RetriesServerErrors(t *testing.T) {
+	srv, rec := newServer(t, func(n int) (int, string) {
+		if n < 3 {
+			return http.StatusBadGateway, "upstream down"
+		}
+		return http.StatusOK, ""
+	})
+	n := NewWebhookNotifier(WebhookConfig{URL: srv.URL, MaxRetries: 3})
+	n.backoff = time.Millisecond
+	if err := n.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := len(rec.all()); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestWebhook_ClientErrorNotRetried(t *testing.T) {
+	srv, rec := newServer(t, func(int) (int, string) { return http.StatusBadRequest, "bad" })
+	n := NewWebhookNotifier(WebhookConfig{URL: srv.URL, MaxRetries: 3})
+	n.backoff = time.Millisecond
+	if err := n.Send(context.Background(), testMessage()); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := len(rec.all()); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestWebhook_GivesUpAfterMaxRetries(t *testing.T) {
+	srv, rec := newServer(t, func(int) (int, string) { return http.StatusServiceUnavailable, "" })
+	n := NewWebhookNotifier(WebhookConfig{URL: srv.URL, MaxRetries: 2})
+	n.backoff = time.Millisecond
+	if err := n.Send(context.Background(), testMessage()); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := len(rec.all()); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestTeams_MessageCard(t *testing.T) {
+	srv, rec := newServer(t, ok)
+	if err := NewTeamsNotifier(srv.URL).Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	var card teamsPayload
+	if err := json.Unmarshal(rec.all()[0].body, &card); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if card.Type != "MessageCard" || card.Title != "Agent 离线" || card.ThemeColor != "E01E5A" {
+		t.Errorf("unexpected card: %+v", card)
+	}
+	if !strings.Contains(card.Text, "prod\n\nAgent") {
+		t.Errorf("text line breaks not preserved: %q", card.Text)
+	}
+}
+
+func TestDingTalk_SignedMarkdown(t *testing.T) {
+	srv, rec := newServer(t, func(int) (int, string) { return http.StatusOK, `{"errcode":0,"errmsg":"ok"}` })
+	if err := NewDingTalkNotifier(srv.URL+"/robot/send?access_token=tok", "SEC123").Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	req := rec.all()[0]
+	if req.query["access_token"] != "tok" {
+		t.Errorf("access_token lost: %v", req.query)
+	}
+	ts := req.query["timestamp"]
+	if ts == "" || req.query["sign"] != timestampSign(ts, "SEC123", false) {
+		t.Errorf("bad signature params: %v", req.query)
+	}
+	var payload dingTalkPayload
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload.MsgType != "markdown" || payload.Markdown.Title != "Agent 离线" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestDingTalk_ErrCode(t *testing.T) {
+	srv, _ := newServer(t, func(int) (int, string) { return http.StatusOK, `{"errcode":310000,"errmsg":"sign not match"}` })
+	err := NewDingTalkNotifier(srv.URL, "").Send(context.Background(), testMessage())
+	if err == nil || !strings.Contains(err.Error(), "310000") {
+		t.Errorf("err = %v, want errcode 310000", err)
+	}
+}
+
+func TestFeishu_SignedCard(t *testing.T) {
+	srv, rec := newServer(t, func(int) (int, string) { return http.StatusOK, `{"code":0,"msg":"success"}` })
+	if err := NewFeishuNotifier(srv.URL, "fs-secret").Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	var payload feishuPayload
+	if err := json.Unmarshal(rec.all()[0].body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload.MsgType != "interactive" || payload.Card.Header.Template != "red" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	if payload.Sign == "" || payload.Sign != timestampSign(payload.Timestamp, "fs-secret", true) {
+		t.Errorf("bad signature: %+v", payload)
+	}
+}
+
+func TestFeishu_ErrorCode(t *testing.T) {
+	srv, _ := newServer(t, func(int) (int, string) { return http.StatusOK, `{"code":19021,"msg":"sign match fail"}` })
+	if err := NewFeishuNotifier(srv.URL, "x").Send(context.Background(), testMessage()); err == nil {
+		t.Error("expected error for non-zero code")
+	}
+}
+
+func TestPagerDuty_TriggerThenResolve(t *testing.T) {
+	srv, rec := newServer(t, func(int) (int, string) { return http.StatusAccepted, `{"status":"success"}` })
+	n := NewPagerDutyNotifier("rk", srv.URL)
+
+	firing := testMessage()
+	resolved := testMessage()
+	resolved.Status = "resolved"
+	for _, msg := range []*Message{firing, resolved} {
+		if err := n.Send(context.Background(), msg); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	var events []pagerDutyEvent
+	for _, req := range rec.all() {
+		var ev pagerDutyEvent
+		if err := json.Unmarshal(req.body, &ev); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		events = append(events, ev)
+	}
+	if len(events) != 2 {
+		t.Fatalf("events = %d, want 2", len(events))
+	}
+	if events[0].EventAction != "trigger" || events[0].Payload == nil || events[0].Payload.Severity != "critical" || events[0].Payload.Source != "prod" {
+		t.Errorf("unexpected trigger event: %+v", events[0])
+	}
+	if events[1].EventAction != "resolve" || events[1].DedupKey != events[0].DedupKey || events[0].RoutingKey != "rk" {
+		t.Errorf("resolve event does not match trigger: %+v", events[1])
+	}
+}
+
+func TestPagerDuty_SplitsCombinedMessage(t *testing.T) {
+	srv, rec := newServer(t, func(int) (int, string) { return http.StatusAccepted, "" })
+	a, b := testMessage(), testMessage()
+	b.DedupKey = "atlhyper-def"
+	combined := &Message{Subject: "[2 条告警] Agent 离线", Parts: []*Message{a, b}}
+	if err := NewPagerDutyNotifier("rk", srv.URL).Send(context.Background(), combined); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := len(rec.all()); got != 2 {
+		t.Errorf("events = %d, want 2", got)
+	}
+}
+
+func TestFactory(t *testing.T) {
+	f := NewFactory()
+	cases := map[string]string{
+		"webhook":   `{"url":"http://example.com","secret":"s"}`,
+		"teams":     `{"webhookUrl":"http://example.com"}`,
+		"dingtalk":  `{"webhookUrl":"http://example.com","secret":"s"}`,
+		"feishu":    `{"webhookUrl":"http://example.com"}`,
+		"pagerduty": `{"routingKey":"rk"}`,
+	}
+	for typ, cfg := range cases {
+		n, err := f.Create(&database.NotifyChannel{Type: typ, Config: cfg})
+		if err != nil {
+			t.Errorf("Create(%s): %v", typ, err)
+			continue
+		}
+		if n.Name() != typ {
+			t.Errorf("Name() = %q, want %q", n.Name(), typ)
+		}
+	}
+	if _, err := f.Create(&database.NotifyChannel{Type: "carrier-pigeon", Config: `{}`}); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+}
+
+func TestEmail_MultipartAlternative(t *testing.T) {
+	e := NewEmailNotifier(EmailConfig{FromAddress: "alerts@example.com", ToAddresses: []string{"ops@example.com"}})
+
+	plain, err := e.buildMailMessage("告警", "plain body", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(plain, "Content-Type: text/plain; charset=UTF-8\r\n\r\nplain body") {
+		t.Errorf("plain message = %q", plain)
+	}
+
+	raw, err := e.buildMailMessage("告警", "plain body", "<p>html body</p>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); subject != "告警" {
+		t.Errorf("subject = %q", subject)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q, %v", mediaType, err)
+	}
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(p)
+		parts = append(parts, p.Header.Get("Content-Type")+"|"+string(body))
+	}
+	want := []string{"text/plain; charset=UTF-8|plain body", "text/html; charset=UTF-8|<p>html body</p>"}
+	if len(parts) != 2 || parts[0] != want[0] || parts[1] != want[1] {
+		t.Errorf("parts = %q", parts)
+	}
+}
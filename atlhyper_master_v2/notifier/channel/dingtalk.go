@@ -0,0 +1,92 @@
+// atlhyper_master_v2/notifier/channel/dingtalk.go
+// 钉钉机器人通知器（Markdown 消息，支持加签）
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DingTalkNotifier 钉钉通知器
+type DingTalkNotifier struct {
+	webhookURL string
+	secret     string
+	client     *http.Client
+}
+
+// dingTalkPayload 钉钉 Markdown 请求体
+type dingTalkPayload struct {
+	MsgType  string `json:"msgtype"`
+	Markdown struct {
+		Title string `json:"title"`
+		Text  string `json:"text"`
+	} `json:"markdown"`
+}
+
+// dingTalkResponse 钉钉响应（HTTP 200 时以 errcode 判断结果）
+type dingTalkResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// NewDingTalkNotifier 创建钉钉通知器
+func NewDingTalkNotifier(webhookURL, secret string) *DingTalkNotifier {
+	return &DingTalkNotifier{webhookURL: webhookURL, secret: secret, client: newHTTPClient()}
+}
+
+// Name 返回通知器名称
+func (d *DingTalkNotifier) Name() string {
+	return "dingtalk"
+}
+
+// Send 发送消息到钉钉
+func (d *DingTalkNotifier) Send(ctx context.Context, msg *Message) error {
+	payload := &dingTalkPayload{MsgType: "markdown"}
+	payload.Markdown.Title = msg.Subject
+	payload.Markdown.Text = hardLineBreaks(msg.Body)
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal dingtalk payload: %w", err)
+	}
+
+	target, err := d.signedURL(time.Now())
+	if err != nil {
+		return err
+	}
+
+	status, respBody, err := postJSON(ctx, d.client, target, data, nil)
+	if err != nil {
+		return err
+	}
+	if !isSuccess(status) {
+		return fmt.Errorf("dingtalk returned status %d: %s", status, respBody)
+	}
+	var resp dingTalkResponse
+	if err := json.Unmarshal(respBody, &resp); err == nil && resp.ErrCode != 0 {
+		return fmt.Errorf("dingtalk error %d: %s", resp.ErrCode, resp.ErrMsg)
+	}
+	return nil
+}
+
+// signedURL 配置 secret 时附加 timestamp（毫秒）与 sign 参数
+func (d *DingTalkNotifier) signedURL(now time.Time) (string, error) {
+	if d.secret == "" {
+		return d.webhookURL, nil
+	}
+	u, err := url.Parse(d.webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid dingtalk webhook url: %w", err)
+	}
+	ts := strconv.FormatInt(now.UnixMilli(), 10)
+	q := u.Query()
+	q.Set("timestamp", ts)
+	q.Set("sign", timestampSign(ts, d.secret, false))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
@@ -3,10 +3,14 @@
 package channel
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
+	"mime"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 )
 
@@ -39,7 +43,10 @@ func (e *EmailNotifier) Name() string {
 // Send 发送邮件
 func (e *EmailNotifier) Send(ctx context.Context, msg *Message) error {
 	// 构建邮件
-	mailMsg := e.buildMailMessage(msg.Subject, msg.Body)
+	mailMsg, err := e.buildMailMessage(msg.Subject, msg.Body, msg.HTML)
+	if err != nil {
+		return fmt.Errorf("build mail: %w", err)
+	}
 
 	// 发送
 	addr := fmt.Sprintf("%s:%d", e.config.SMTPHost, e.config.SMTPPort)
@@ -56,16 +63,43 @@ func (e *EmailNotifier) Send(ctx context.Context, msg *Message) error {
 }
 
 // buildMailMessage 构建邮件消息
-func (e *EmailNotifier) buildMailMessage(subject, body string) string {
+// html 非空时构建 multipart/alternative（纯文本在前，HTML 在后，客户端优先展示最后一个可识别的部分）
+func (e *EmailNotifier) buildMailMessage(subject, body, html string) (string, error) {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("From: %s\r\n", e.config.FromAddress))
 	sb.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(e.config.ToAddresses, ",")))
-	sb.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	sb.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject)))
 	sb.WriteString("MIME-Version: 1.0\r\n")
-	sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+
+	if html == "" {
+		sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		sb.WriteString("\r\n")
+		sb.WriteString(body)
+		return sb.String(), nil
+	}
+
+	var parts bytes.Buffer
+	mw := multipart.NewWriter(&parts)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", body},
+		{"text/html; charset=UTF-8", html},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return "", err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	sb.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q\r\n", mw.Boundary()))
 	sb.WriteString("\r\n")
-	sb.WriteString(body)
-	return sb.String()
+	sb.Write(parts.Bytes())
+	return sb.String(), nil
 }
 
 // sendSTARTTLS 使用 STARTTLS 发送邮件（端口 587）
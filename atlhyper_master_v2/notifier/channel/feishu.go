@@ -0,0 +1,116 @@
+// atlhyper_master_v2/notifier/channel/feishu.go
+// 飞书 / Lark 机器人通知器（消息卡片，支持签名校验）
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// FeishuNotifier 飞书通知器
+type FeishuNotifier struct {
+	webhookURL string
+	secret     string
+	client     *http.Client
+}
+
+// feishuPayload 飞书消息卡片请求体
+type feishuPayload struct {
+	Timestamp string     `json:"timestamp,omitempty"`
+	Sign      string     `json:"sign,omitempty"`
+	MsgType   string     `json:"msg_type"`
+	Card      feishuCard `json:"card"`
+}
+
+type feishuCard struct {
+	Header   feishuHeader    `json:"header"`
+	Elements []feishuElement `json:"elements"`
+}
+
+type feishuHeader struct {
+	Title    feishuText `json:"title"`
+	Template string     `json:"template"`
+}
+
+type feishuElement struct {
+	Tag  string     `json:"tag"`
+	Text feishuText `json:"text"`
+}
+
+type feishuText struct {
+	Tag     string `json:"tag"`
+	Content string `json:"content"`
+}
+
+// feishuResponse 飞书响应（HTTP 200 时以 code 判断结果）
+type feishuResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// NewFeishuNotifier 创建飞书通知器
+func NewFeishuNotifier(webhookURL, secret string) *FeishuNotifier {
+	return &FeishuNotifier{webhookURL: webhookURL, secret: secret, client: newHTTPClient()}
+}
+
+// Name 返回通知器名称
+func (f *FeishuNotifier) Name() string {
+	return "feishu"
+}
+
+// Send 发送消息到飞书
+func (f *FeishuNotifier) Send(ctx context.Context, msg *Message) error {
+	payload := &feishuPayload{
+		MsgType: "interactive",
+		Card: feishuCard{
+			Header: feishuHeader{
+				Title:    feishuText{Tag: "plain_text", Content: msg.Subject},
+				Template: feishuTemplate(msg.Severity, msg.Status),
+			},
+			Elements: []feishuElement{
+				{Tag: "div", Text: feishuText{Tag: "lark_md", Content: msg.Body}},
+			},
+		},
+	}
+	if f.secret != "" {
+		payload.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+		payload.Sign = timestampSign(payload.Timestamp, f.secret, true)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal feishu payload: %w", err)
+	}
+
+	status, respBody, err := postJSON(ctx, f.client, f.webhookURL, data, nil)
+	if err != nil {
+		return err
+	}
+	if !isSuccess(status) {
+		return fmt.Errorf("feishu returned status %d: %s", status, respBody)
+	}
+	var resp feishuResponse
+	if err := json.Unmarshal(respBody, &resp); err == nil && resp.Code != 0 {
+		return fmt.Errorf("feishu error %d: %s", resp.Code, resp.Msg)
+	}
+	return nil
+}
+
+// feishuTemplate 卡片标题颜色
+func feishuTemplate(severity, status string) string {
+	if status == "resolved" {
+		return "green"
+	}
+	switch severity {
+	case "critical":
+		return "red"
+	case "warning":
+		return "orange"
+	default:
+		return "blue"
+	}
+}
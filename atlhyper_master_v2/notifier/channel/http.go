@@ -0,0 +1,105 @@
+// atlhyper_master_v2/notifier/channel/http.go
+// HTTP 渠道公共方法
+package channel
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultHTTPTimeout HTTP 渠道请求超时
+const defaultHTTPTimeout = 10 * time.Second
+
+// maxResponseBody 读取响应体上限（仅用于错误信息与结果校验）
+const maxResponseBody = 64 << 10
+
+// newHTTPClient 创建 HTTP 客户端
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: defaultHTTPTimeout}
+}
+
+// postJSON 发送 JSON POST 请求，返回状态码与响应体
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	return resp.StatusCode, respBody, nil
+}
+
+// isSuccess 2xx 状态码
+func isSuccess(status int) bool {
+	return status >= 200 && status < 300
+}
+
+// hmacSHA256 计算 HMAC-SHA256
+func hmacSHA256(key, msg []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(msg)
+	return mac.Sum(nil)
+}
+
+// timestampSign 钉钉 / 飞书加签：base64(HMAC-SHA256(timestamp + "\n" + secret))
+// 钉钉以拼接串为消息、secret 为密钥；飞书以拼接串为密钥、空串为消息
+func timestampSign(timestamp, secret string, asKey bool) string {
+	stringToSign := timestamp + "\n" + secret
+	var sum []byte
+	if asKey {
+		sum = hmacSHA256([]byte(stringToSign), nil)
+	} else {
+		sum = hmacSHA256([]byte(secret), []byte(stringToSign))
+	}
+	return base64.StdEncoding.EncodeToString(sum)
+}
+
+// hardLineBreaks 单换行转为段落换行（Teams / 钉钉 Markdown 会合并单换行）
+func hardLineBreaks(body string) string {
+	lines := strings.Split(body, "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			if line == "" || lines[i-1] == "" {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// severityColor 告警级别对应颜色（十六进制，无 #）
+func severityColor(severity, status string) string {
+	if status == "resolved" {
+		return "2EB67D"
+	}
+	switch severity {
+	case "critical":
+		return "E01E5A"
+	case "warning":
+		return "ECB22E"
+	default:
+		return "2EB67D"
+	}
+}
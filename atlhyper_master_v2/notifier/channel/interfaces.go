@@ -6,15 +6,34 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sort"
 
 	"AtlHyper/atlhyper_master_v2/database"
 )
 
 // Message 渲染后的消息
 type Message struct {
-	Subject string // 主题（Email 用）
-	Body    string // 正文
-	Format  string // 格式：text, markdown, html
+	Subject string `json:"subject"`        // 主题（Email 用）
+	Body    string `json:"body"`           // 正文
+	Format  string `json:"format"`         // 格式：text, markdown, html
+	HTML    string `json:"html,omitempty"` // HTML 正文（Email 使用，Body 作为纯文本备选）
+
+	// 告警元数据（Webhook、PagerDuty 等结构化渠道使用）
+	Severity string            `json:"severity,omitempty"` // critical / warning / info
+	Status   string            `json:"status,omitempty"`   // firing / resolved
+	DedupKey string            `json:"dedupKey,omitempty"` // 告警去重键（同一告警的触发与恢复相同）
+	Labels   map[string]string `json:"labels,omitempty"`   // 告警标签
+
+	// Parts 分组合并前的单条消息（非合并消息为空）
+	Parts []*Message `json:"parts,omitempty"`
+}
+
+// Alerts 返回消息包含的单条告警（合并消息返回 Parts，否则返回自身）
+func (m *Message) Alerts() []*Message {
+	if len(m.Parts) > 0 {
+		return m.Parts
+	}
+	return []*Message{m}
 }
 
 // Notifier 通知器接口
@@ -23,47 +42,149 @@ type Notifier interface {
 	Send(ctx context.Context, msg *Message) error
 }
 
-// Factory 通知器工厂
-type Factory struct{}
+// Builder 根据渠道配置（JSON）创建 Notifier
+type Builder func(config string) (Notifier, error)
 
-// NewFactory 创建工厂
+// Factory 通知器工厂（按渠道类型注册 Builder）
+type Factory struct {
+	builders map[string]Builder
+}
+
+// NewFactory 创建工厂并注册内置渠道
 func NewFactory() *Factory {
-	return &Factory{}
+	f := &Factory{builders: make(map[string]Builder)}
+	f.Register("slack", buildSlack)
+	f.Register("email", buildEmail)
+	f.Register("webhook", buildWebhook)
+	f.Register("teams", buildTeams)
+	f.Register("dingtalk", buildDingTalk)
+	f.Register("feishu", buildFeishu)
+	f.Register("pagerduty", buildPagerDuty)
+	return f
+}
+
+// Register 注册渠道类型（同名覆盖）
+func (f *Factory) Register(channelType string, builder Builder) {
+	f.builders[channelType] = builder
+}
+
+// Types 已注册的渠道类型
+func (f *Factory) Types() []string {
+	types := make([]string, 0, len(f.builders))
+	for t := range f.builders {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
 }
 
 // Create 根据渠道配置创建 Notifier
 func (f *Factory) Create(ch *database.NotifyChannel) (Notifier, error) {
-	switch ch.Type {
-	case "slack":
-		var cfg database.SlackConfig
-		if err := json.Unmarshal([]byte(ch.Config), &cfg); err != nil {
-			return nil, errors.New("invalid slack config")
-		}
-		if cfg.WebhookURL == "" {
-			return nil, errors.New("slack webhook url required")
-		}
-		return NewSlackNotifier(cfg.WebhookURL), nil
-
-	case "email":
-		var cfg database.EmailConfig
-		if err := json.Unmarshal([]byte(ch.Config), &cfg); err != nil {
-			return nil, errors.New("invalid email config")
-		}
-		// 完整性验证：host、user、password、recipients 都必须配置
-		if cfg.SMTPHost == "" || cfg.SMTPUser == "" || cfg.SMTPPassword == "" || len(cfg.ToAddresses) == 0 {
-			return nil, errors.New("email config incomplete: smtp_host, smtp_user, smtp_password, to_addresses required")
-		}
-		return NewEmailNotifier(EmailConfig{
-			SMTPHost:     cfg.SMTPHost,
-			SMTPPort:     cfg.SMTPPort,
-			SMTPUser:     cfg.SMTPUser,
-			SMTPPassword: cfg.SMTPPassword,
-			UseTLS:       cfg.SMTPTLS,
-			FromAddress:  cfg.FromAddress,
-			ToAddresses:  cfg.ToAddresses,
-		}), nil
-
-	default:
+	builder, ok := f.builders[ch.Type]
+	if !ok {
 		return nil, errors.New("unsupported channel type: " + ch.Type)
 	}
+	return builder(ch.Config)
+}
+
+// buildSlack 创建 Slack 通知器
+func buildSlack(config string) (Notifier, error) {
+	var cfg database.SlackConfig
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		return nil, errors.New("invalid slack config")
+	}
+	if cfg.WebhookURL == "" {
+		return nil, errors.New("slack webhook url required")
+	}
+	return NewSlackNotifier(cfg.WebhookURL), nil
+}
+
+// buildEmail 创建 Email 通知器
+func buildEmail(config string) (Notifier, error) {
+	var cfg database.EmailConfig
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		return nil, errors.New("invalid email config")
+	}
+	// 完整性验证：host、user、password、recipients 都必须配置
+	if cfg.SMTPHost == "" || cfg.SMTPUser == "" || cfg.SMTPPassword == "" || len(cfg.ToAddresses) == 0 {
+		return nil, errors.New("email config incomplete: smtp_host, smtp_user, smtp_password, to_addresses required")
+	}
+	return NewEmailNotifier(EmailConfig{
+		SMTPHost:     cfg.SMTPHost,
+		SMTPPort:     cfg.SMTPPort,
+		SMTPUser:     cfg.SMTPUser,
+		SMTPPassword: cfg.SMTPPassword,
+		UseTLS:       cfg.SMTPTLS,
+		FromAddress:  cfg.FromAddress,
+		ToAddresses:  cfg.ToAddresses,
+	}), nil
+}
+
+// buildWebhook 创建通用 Webhook 通知器
+func buildWebhook(config string) (Notifier, error) {
+	var cfg database.WebhookConfig
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		return nil, errors.New("invalid webhook config")
+	}
+	if cfg.URL == "" {
+		return nil, errors.New("webhook url required")
+	}
+	retries := defaultWebhookRetries
+	if cfg.MaxRetries != nil {
+		retries = *cfg.MaxRetries
+	}
+	return NewWebhookNotifier(WebhookConfig{
+		URL:        cfg.URL,
+		Secret:     cfg.Secret,
+		Headers:    cfg.Headers,
+		MaxRetries: retries,
+	}), nil
+}
+
+// buildTeams 创建 Teams 通知器
+func buildTeams(config string) (Notifier, error) {
+	var cfg database.TeamsConfig
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		return nil, errors.New("invalid teams config")
+	}
+	if cfg.WebhookURL == "" {
+		return nil, errors.New("teams webhook url required")
+	}
+	return NewTeamsNotifier(cfg.WebhookURL), nil
+}
+
+// buildDingTalk 创建钉钉通知器
+func buildDingTalk(config string) (Notifier, error) {
+	var cfg database.DingTalkConfig
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		return nil, errors.New("invalid dingtalk config")
+	}
+	if cfg.WebhookURL == "" {
+		return nil, errors.New("dingtalk webhook url required")
+	}
+	return NewDingTalkNotifier(cfg.WebhookURL, cfg.Secret), nil
+}
+
+// buildFeishu 创建飞书通知器
+func buildFeishu(config string) (Notifier, error) {
+	var cfg database.FeishuConfig
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		return nil, errors.New("invalid feishu config")
+	}
+	if cfg.WebhookURL == "" {
+		return nil, errors.New("feishu webhook url required")
+	}
+	return NewFeishuNotifier(cfg.WebhookURL, cfg.Secret), nil
+}
+
+// buildPagerDuty 创建 PagerDuty 通知器
+func buildPagerDuty(config string) (Notifier, error) {
+	var cfg database.PagerDutyConfig
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		return nil, errors.New("invalid pagerduty config")
+	}
+	if cfg.RoutingKey == "" {
+		return nil, errors.New("pagerduty routing key required")
+	}
+	return NewPagerDutyNotifier(cfg.RoutingKey, cfg.EventsURL), nil
 }
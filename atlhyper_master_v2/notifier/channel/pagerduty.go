@@ -0,0 +1,131 @@
+// atlhyper_master_v2/notifier/channel/pagerduty.go
+// PagerDuty 通知器（Events API v2）
+//
+// 每条告警单独发送一个事件（合并消息拆分发送），dedup_key 使用告警去重键:
+// 触发告警 → trigger，恢复告警 → resolve，同一告警的恢复会自动关闭对应 Incident。
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// defaultPagerDutyEventsURL Events API v2 地址
+const defaultPagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyNotifier PagerDuty 通知器
+type PagerDutyNotifier struct {
+	routingKey string
+	eventsURL  string
+	client     *http.Client
+}
+
+// pagerDutyEvent Events API v2 请求体
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"` // trigger / resolve
+	DedupKey    string            `json:"dedup_key,omitempty"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Component     string         `json:"component,omitempty"`
+	Group         string         `json:"group,omitempty"`
+	Class         string         `json:"class,omitempty"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+// pagerDutySummaryLimit summary 长度上限
+const pagerDutySummaryLimit = 1024
+
+// NewPagerDutyNotifier 创建 PagerDuty 通知器
+func NewPagerDutyNotifier(routingKey, eventsURL string) *PagerDutyNotifier {
+	if eventsURL == "" {
+		eventsURL = defaultPagerDutyEventsURL
+	}
+	return &PagerDutyNotifier{routingKey: routingKey, eventsURL: eventsURL, client: newHTTPClient()}
+}
+
+// Name 返回通知器名称
+func (p *PagerDutyNotifier) Name() string {
+	return "pagerduty"
+}
+
+// Send 发送事件到 PagerDuty（合并消息逐条发送）
+func (p *PagerDutyNotifier) Send(ctx context.Context, msg *Message) error {
+	var errs []error
+	for _, alert := range msg.Alerts() {
+		if err := p.sendEvent(ctx, p.buildEvent(alert)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// buildEvent 构建事件
+func (p *PagerDutyNotifier) buildEvent(msg *Message) *pagerDutyEvent {
+	ev := &pagerDutyEvent{
+		RoutingKey:  p.routingKey,
+		EventAction: "trigger",
+		DedupKey:    msg.DedupKey,
+	}
+	if msg.Status == "resolved" && msg.DedupKey != "" {
+		ev.EventAction = "resolve"
+		return ev
+	}
+
+	summary := msg.Subject
+	if len(summary) > pagerDutySummaryLimit {
+		summary = summary[:pagerDutySummaryLimit]
+	}
+	source := msg.Labels["cluster"]
+	if source == "" {
+		source = "atlhyper"
+	}
+	details := map[string]any{"body": msg.Body}
+	if len(msg.Labels) > 0 {
+		details["labels"] = msg.Labels
+	}
+	ev.Payload = &pagerDutyPayload{
+		Summary:       summary,
+		Source:        source,
+		Severity:      pagerDutySeverity(msg.Severity),
+		Component:     msg.Labels["resource"],
+		Group:         msg.Labels["namespace"],
+		Class:         msg.Labels["alertname"],
+		CustomDetails: details,
+	}
+	return ev
+}
+
+// sendEvent 发送单个事件（成功返回 202）
+func (p *PagerDutyNotifier) sendEvent(ctx context.Context, ev *pagerDutyEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("marshal pagerduty event: %w", err)
+	}
+	status, respBody, err := postJSON(ctx, p.client, p.eventsURL, data, nil)
+	if err != nil {
+		return err
+	}
+	if !isSuccess(status) {
+		return fmt.Errorf("pagerduty returned status %d: %s", status, respBody)
+	}
+	return nil
+}
+
+// pagerDutySeverity 映射告警级别（PagerDuty 仅接受 critical / error / warning / info）
+func pagerDutySeverity(severity string) string {
+	switch severity {
+	case "critical", "error", "warning", "info":
+		return severity
+	default:
+		return "error"
+	}
+}
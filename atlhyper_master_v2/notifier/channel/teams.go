@@ -0,0 +1,62 @@
+// atlhyper_master_v2/notifier/channel/teams.go
+// Microsoft Teams 通知器（Incoming Webhook，MessageCard 格式）
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// TeamsNotifier Teams 通知器
+type TeamsNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// teamsPayload MessageCard 请求体
+type teamsPayload struct {
+	Type       string `json:"@type"`
+	Context    string `json:"@context"`
+	Summary    string `json:"summary"`
+	ThemeColor string `json:"themeColor"`
+	Title      string `json:"title"`
+	Text       string `json:"text"`
+}
+
+// NewTeamsNotifier 创建 Teams 通知器
+func NewTeamsNotifier(webhookURL string) *TeamsNotifier {
+	return &TeamsNotifier{webhookURL: webhookURL, client: newHTTPClient()}
+}
+
+// Name 返回通知器名称
+func (t *TeamsNotifier) Name() string {
+	return "teams"
+}
+
+// Send 发送消息到 Teams
+func (t *TeamsNotifier) Send(ctx context.Context, msg *Message) error {
+	payload := &teamsPayload{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		Summary:    msg.Subject,
+		ThemeColor: severityColor(msg.Severity, msg.Status),
+		Title:      msg.Subject,
+		Text:       hardLineBreaks(msg.Body),
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal teams payload: %w", err)
+	}
+
+	status, respBody, err := postJSON(ctx, t.client, t.webhookURL, data, nil)
+	if err != nil {
+		return err
+	}
+	if !isSuccess(status) {
+		return fmt.Errorf("teams returned status %d: %s", status, respBody)
+	}
+	return nil
+}
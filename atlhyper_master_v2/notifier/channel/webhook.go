@@ -0,0 +1,164 @@
+// atlhyper_master_v2/notifier/channel/webhook.go
+// 通用 JSON Webhook 通知器
+//
+// 请求体为结构化 JSON（含每条告警的状态、级别、去重键与标签）。
+// 配置 secret 时附带签名头:
+//
+//	X-AtlHyper-Timestamp: Unix 秒
+//	X-AtlHyper-Signature: sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+//
+// 网络错误、5xx 与 429 按指数退避重试，其余 4xx 不重试。
+package channel
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultWebhookRetries = 3
+	defaultWebhookBackoff = time.Second
+
+	webhookTimestampHeader = "X-AtlHyper-Timestamp"
+	webhookSignatureHeader = "X-AtlHyper-Signature"
+)
+
+// WebhookConfig Webhook 配置
+type WebhookConfig struct {
+	URL        string
+	Secret     string
+	Headers    map[string]string
+	MaxRetries int
+}
+
+// WebhookNotifier 通用 Webhook 通知器
+type WebhookNotifier struct {
+	config  WebhookConfig
+	client  *http.Client
+	backoff time.Duration // 首次重试等待，之后翻倍
+}
+
+// webhookPayload Webhook 请求体
+type webhookPayload struct {
+	Version   string         `json:"version"`
+	Source    string         `json:"source"`
+	Status    string         `json:"status"` // 任一告警触发中即为 firing
+	Subject   string         `json:"subject"`
+	Timestamp string         `json:"timestamp"`
+	Alerts    []webhookAlert `json:"alerts"`
+}
+
+type webhookAlert struct {
+	Status   string            `json:"status"`
+	Severity string            `json:"severity"`
+	DedupKey string            `json:"dedupKey,omitempty"`
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+// NewWebhookNotifier 创建 Webhook 通知器
+func NewWebhookNotifier(config WebhookConfig) *WebhookNotifier {
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	return &WebhookNotifier{
+		config:  config,
+		client:  newHTTPClient(),
+		backoff: defaultWebhookBackoff,
+	}
+}
+
+// Name 返回通知器名称
+func (w *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Send 发送消息到 Webhook
+func (w *WebhookNotifier) Send(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(buildWebhookPayload(msg, time.Now()))
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	wait := w.backoff
+	var lastErr error
+	for attempt := 0; attempt <= w.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("webhook canceled after %d attempts: %w", attempt, lastErr)
+			case <-time.After(wait):
+			}
+			wait *= 2
+		}
+
+		status, respBody, err := postJSON(ctx, w.client, w.config.URL, body, w.headers(body))
+		if err == nil && isSuccess(status) {
+			return nil
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		lastErr = fmt.Errorf("webhook returned status %d: %s", status, respBody)
+		if status != http.StatusTooManyRequests && status < 500 {
+			return lastErr
+		}
+	}
+	return fmt.Errorf("webhook failed after %d attempts: %w", w.config.MaxRetries+1, lastErr)
+}
+
+// headers 构建请求头（每次尝试重新签名）
+func (w *WebhookNotifier) headers(body []byte) map[string]string {
+	headers := make(map[string]string, len(w.config.Headers)+2)
+	for k, v := range w.config.Headers {
+		headers[k] = v
+	}
+	if w.config.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		headers[webhookTimestampHeader] = ts
+		headers[webhookSignatureHeader] = SignWebhook(w.config.Secret, ts, body)
+	}
+	return headers
+}
+
+// SignWebhook 计算 Webhook 签名（接收方可用同一方法校验）
+func SignWebhook(secret, timestamp string, body []byte) string {
+	msg := append([]byte(timestamp+"."), body...)
+	return "sha256=" + hex.EncodeToString(hmacSHA256([]byte(secret), msg))
+}
+
+// buildWebhookPayload 构建请求体
+func buildWebhookPayload(msg *Message, now time.Time) *webhookPayload {
+	payload := &webhookPayload{
+		Version:   "1",
+		Source:    "atlhyper",
+		Status:    "resolved",
+		Subject:   msg.Subject,
+		Timestamp: now.UTC().Format(time.RFC3339),
+	}
+	for _, part := range msg.Alerts() {
+		status := part.Status
+		if status == "" {
+			status = "firing"
+		}
+		if status == "firing" {
+			payload.Status = "firing"
+		}
+		payload.Alerts = append(payload.Alerts, webhookAlert{
+			Status:   status,
+			Severity: part.Severity,
+			DedupKey: part.DedupKey,
+			Title:    part.Subject,
+			Body:     part.Body,
+			Labels:   part.Labels,
+		})
+	}
+	return payload
+}
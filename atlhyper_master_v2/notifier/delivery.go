@@ -0,0 +1,166 @@
+// atlhyper_master_v2/notifier/delivery.go
+// 通知投递记录与失败重试
+//
+// 每次渠道发送都会持久化一条投递记录（渲染后的消息、哈希、状态、尝试次数、最后错误）:
+//   - 发送成功 → sent
+//   - 发送失败 → failed，按指数退避计算 next_attempt_at，由 retryLoop 重试
+//   - 重试耗尽 → dead（死信），可通过 API 手动重发
+//
+// 新记录以 pending 状态写入，next_attempt_at 设为 now + deliveryLease:
+// 进程在发送过程中退出时，重启后由 retryLoop 接管。
+package notifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/notifier/channel"
+)
+
+const (
+	deliveryMaxAttempts  = 6                // 最大尝试次数（含首次发送）
+	deliveryBaseBackoff  = 30 * time.Second // 首次重试等待，之后翻倍
+	deliveryMaxBackoff   = 30 * time.Minute // 单次重试等待上限
+	deliveryLease        = 2 * time.Minute  // pending 记录被重试 Worker 接管前的等待
+	deliveryPollInterval = 15 * time.Second // 重试 Worker 轮询间隔
+	deliveryBatchSize    = 50               // 每轮最多重试条数
+	deliveryRetention    = 30 * 24 * time.Hour
+)
+
+// retryBackoff 第 attempts 次失败后的等待时长
+func retryBackoff(attempts int) time.Duration {
+	wait := deliveryBaseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= deliveryMaxBackoff {
+			return deliveryMaxBackoff
+		}
+	}
+	return wait
+}
+
+// dispatch 记录投递并发送（未配置投递仓库时直接发送）
+func (m *Manager) dispatch(ctx context.Context, ch *database.NotifyChannel, templateName string, msg *channel.Message) {
+	if m.repos.Deliveries == nil {
+		if err := m.send(ctx, ch, msg); err != nil {
+			log.Error("发送失败", "channel", ch.Type, "name", ch.Name, "err", err)
+			return
+		}
+		log.Info("发送成功", "channel", ch.Type, "name", ch.Name, "title", msg.Subject)
+		return
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		log.Error("序列化消息失败", "channel", ch.Type, "err", err)
+		return
+	}
+	sum := sha256.Sum256(payload)
+	d := &database.NotifyDelivery{
+		ChannelID:     ch.ID,
+		ChannelType:   ch.Type,
+		ChannelName:   ch.Name,
+		TemplateName:  templateName,
+		Subject:       msg.Subject,
+		Payload:       string(payload),
+		PayloadHash:   hex.EncodeToString(sum[:]),
+		Status:        database.DeliveryPending,
+		NextAttemptAt: time.Now().Add(deliveryLease),
+	}
+	if err := m.repos.Deliveries.Create(ctx, d); err != nil {
+		log.Warn("记录投递失败", "channel", ch.Type, "err", err)
+	}
+	m.attempt(ctx, ch, d, msg)
+}
+
+// attempt 发送一次并记录结果
+func (m *Manager) attempt(ctx context.Context, ch *database.NotifyChannel, d *database.NotifyDelivery, msg *channel.Message) {
+	err := m.send(ctx, ch, msg)
+	now := time.Now()
+	d.Attempts++
+	if err == nil {
+		d.Status = database.DeliverySent
+		d.LastError = ""
+		d.SentAt = &now
+		log.Info("发送成功", "channel", ch.Type, "name", ch.Name, "title", msg.Subject, "attempts", d.Attempts)
+	} else {
+		d.LastError = err.Error()
+		if d.Attempts >= deliveryMaxAttempts {
+			d.Status = database.DeliveryDead
+			log.Error("发送失败，已转入死信", "channel", ch.Type, "name", ch.Name, "attempts", d.Attempts, "err", err)
+		} else {
+			d.Status = database.DeliveryFailed
+			d.NextAttemptAt = now.Add(retryBackoff(d.Attempts))
+			log.Warn("发送失败，等待重试", "channel", ch.Type, "name", ch.Name, "attempts", d.Attempts, "next", d.NextAttemptAt, "err", err)
+		}
+	}
+	if d.ID == 0 {
+		return
+	}
+	if err := m.repos.Deliveries.UpdateAttempt(ctx, d); err != nil {
+		log.Warn("更新投递记录失败", "id", d.ID, "err", err)
+	}
+}
+
+// retryLoop 定期重试到期的投递
+func (m *Manager) retryLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(deliveryPollInterval)
+	defer ticker.Stop()
+
+	// 启动时立即处理上次进程遗留的记录
+	m.retryDue(context.Background(), time.Now())
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.retryDue(context.Background(), time.Now())
+		}
+	}
+}
+
+// retryDue 重试到期的投递记录
+func (m *Manager) retryDue(ctx context.Context, now time.Time) {
+	due, err := m.repos.Deliveries.ListDue(ctx, now, deliveryBatchSize)
+	if err != nil {
+		log.Warn("查询待重试投递失败", "err", err)
+		return
+	}
+	for _, d := range due {
+		ch, msg, err := m.loadDelivery(ctx, d)
+		if err != nil {
+			// 渠道已删除 / 禁用或记录损坏：无法重试，直接转入死信
+			d.Attempts++
+			d.Status = database.DeliveryDead
+			d.LastError = err.Error()
+			if err := m.repos.Deliveries.UpdateAttempt(ctx, d); err != nil {
+				log.Warn("更新投递记录失败", "id", d.ID, "err", err)
+			}
+			continue
+		}
+		m.attempt(ctx, ch, d, msg)
+	}
+}
+
+// loadDelivery 加载投递记录对应的渠道与消息
+func (m *Manager) loadDelivery(ctx context.Context, d *database.NotifyDelivery) (*database.NotifyChannel, *channel.Message, error) {
+	ch, err := m.repos.Channels.GetByID(ctx, d.ChannelID)
+	if err != nil || ch == nil {
+		return nil, nil, fmt.Errorf("channel %d not found", d.ChannelID)
+	}
+	if !ch.Enabled {
+		return nil, nil, fmt.Errorf("channel %d is disabled", d.ChannelID)
+	}
+	var msg channel.Message
+	if err := json.Unmarshal([]byte(d.Payload), &msg); err != nil {
+		return nil, nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	return ch, &msg, nil
+}
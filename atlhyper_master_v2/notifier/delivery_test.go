@@ -0,0 +1,210 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/notifier/channel"
+)
+
+type fakeDeliveryRepo struct {
+	database.NotifyDeliveryRepository
+	mu     sync.Mutex
+	nextID int64
+	rows   map[int64]*database.NotifyDelivery
+}
+
+func newFakeDeliveryRepo() *fakeDeliveryRepo {
+	return &fakeDeliveryRepo{rows: make(map[int64]*database.NotifyDelivery)}
+}
+
+func (r *fakeDeliveryRepo) Create(ctx context.Context, d *database.NotifyDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	d.ID = r.nextID
+	cp := *d
+	r.rows[d.ID] = &cp
+	return nil
+}
+
+func (r *fakeDeliveryRepo) UpdateAttempt(ctx context.Context, d *database.NotifyDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *d
+	r.rows[d.ID] = &cp
+	return nil
+}
+
+func (r *fakeDeliveryRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]*database.NotifyDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*database.NotifyDelivery
+	for _, d := range r.rows {
+		if (d.Status == database.DeliveryPending || d.Status == database.DeliveryFailed) && !d.NextAttemptAt.After(now) {
+			cp := *d
+			due = append(due, &cp)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeDeliveryRepo) get(id int64) database.NotifyDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.rows[id]
+}
+
+type fakeChannelByID struct {
+	*fakeChannelRepo
+}
+
+func (r *fakeChannelByID) GetByID(ctx context.Context, id int64) (*database.NotifyChannel, error) {
+	for _, ch := range r.channels {
+		if ch.ID == id {
+			return ch, nil
+		}
+	}
+	return nil, nil
+}
+
+// flakySender 前 failures 次发送失败
+type flakySender struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	subjects []string
+}
+
+func (s *flakySender) send(ctx context.Context, ch *database.NotifyChannel, msg *channel.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("connection refused")
+	}
+	s.subjects = append(s.subjects, msg.Subject)
+	return nil
+}
+
+func newDeliveryManager(t *testing.T, sender *flakySender) (*Manager, *fakeDeliveryRepo) {
+	t.Helper()
+	deliveries := newFakeDeliveryRepo()
+	channels := &fakeChannelByID{&fakeChannelRepo{channels: []*database.NotifyChannel{{ID: 1, Type: "slack", Name: "ops", Enabled: true}}}}
+	m, err := NewManager(ManagerRepos{Channels: channels, Deliveries: deliveries})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	m.send = sender.send
+	return m, deliveries
+}
+
+func TestRetryBackoff(t *testing.T) {
+	cases := map[int]time.Duration{1: 30 * time.Second, 2: time.Minute, 3: 2 * time.Minute, 10: deliveryMaxBackoff}
+	for attempts, want := range cases {
+		if got := retryBackoff(attempts); got != want {
+			t.Errorf("retryBackoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestDelivery_SuccessRecorded(t *testing.T) {
+	sender := &flakySender{}
+	m, deliveries := newDeliveryManager(t, sender)
+
+	m.SendWithTemplate("heartbeat_offline", heartbeat("prod"))
+	d := deliveries.get(1)
+	if d.Status != database.DeliverySent || d.Attempts != 1 || d.SentAt == nil {
+		t.Fatalf("delivery = %+v, want sent after 1 attempt", d)
+	}
+	if d.PayloadHash == "" || d.TemplateName != "heartbeat_offline" || d.ChannelType != "slack" {
+		t.Errorf("delivery metadata not recorded: %+v", d)
+	}
+}
+
+func TestDelivery_FailedThenRetried(t *testing.T) {
+	sender := &flakySender{failures: 1}
+	m, deliveries := newDeliveryManager(t, sender)
+
+	m.SendWithTemplate("heartbeat_offline", heartbeat("prod"))
+	d := deliveries.get(1)
+	if d.Status != database.DeliveryFailed || d.Attempts != 1 || d.LastError == "" {
+		t.Fatalf("delivery = %+v, want failed after 1 attempt", d)
+	}
+
+	// 未到重试时间不重试
+	m.retryDue(context.Background(), time.Now())
+	if sender.calls != 1 {
+		t.Fatalf("calls = %d before backoff elapsed, want 1", sender.calls)
+	}
+
+	// 重试时直接发送已渲染的消息
+	m.retryDue(context.Background(), d.NextAttemptAt.Add(time.Second))
+	d = deliveries.get(1)
+	if d.Status != database.DeliverySent || d.Attempts != 2 {
+		t.Fatalf("delivery = %+v, want sent after retry", d)
+	}
+	if len(sender.subjects) != 1 || sender.subjects[0] != d.Subject {
+		t.Errorf("resent subjects = %v, want [%q]", sender.subjects, d.Subject)
+	}
+}
+
+func TestDelivery_DeadLetterAfterMaxAttempts(t *testing.T) {
+	sender := &flakySender{failures: 100}
+	m, deliveries := newDeliveryManager(t, sender)
+
+	m.SendWithTemplate("heartbeat_offline", heartbeat("prod"))
+	now := time.Now()
+	for i := 0; i < deliveryMaxAttempts; i++ {
+		now = now.Add(deliveryMaxBackoff + time.Second)
+		m.retryDue(context.Background(), now)
+	}
+	d := deliveries.get(1)
+	if d.Status != database.DeliveryDead || d.Attempts != deliveryMaxAttempts {
+		t.Fatalf("delivery = %+v, want dead after %d attempts", d, deliveryMaxAttempts)
+	}
+	if sender.calls != deliveryMaxAttempts {
+		t.Errorf("calls = %d, want %d", sender.calls, deliveryMaxAttempts)
+	}
+}
+
+func TestDelivery_PendingRecoveredAfterRestart(t *testing.T) {
+	sender := &flakySender{}
+	m, deliveries := newDeliveryManager(t, sender)
+
+	// 模拟上次进程写入记录后未完成发送
+	deliveries.Create(context.Background(), &database.NotifyDelivery{
+		ChannelID:     1,
+		ChannelType:   "slack",
+		Payload:       `{"subject":"Agent 离线","body":"prod","format":"markdown"}`,
+		Status:        database.DeliveryPending,
+		NextAttemptAt: time.Now().Add(-time.Minute),
+	})
+	m.retryDue(context.Background(), time.Now())
+	if d := deliveries.get(1); d.Status != database.DeliverySent {
+		t.Fatalf("delivery = %+v, want sent", d)
+	}
+}
+
+func TestDelivery_MissingChannelDeadLettered(t *testing.T) {
+	sender := &flakySender{}
+	m, deliveries := newDeliveryManager(t, sender)
+
+	deliveries.Create(context.Background(), &database.NotifyDelivery{
+		ChannelID:     99,
+		Payload:       `{}`,
+		Status:        database.DeliveryFailed,
+		NextAttemptAt: time.Now().Add(-time.Minute),
+	})
+	m.retryDue(context.Background(), time.Now())
+	if d := deliveries.get(1); d.Status != database.DeliveryDead || d.LastError == "" {
+		t.Fatalf("delivery = %+v, want dead with error", d)
+	}
+	if sender.calls != 0 {
+		t.Errorf("calls = %d, want 0", sender.calls)
+	}
+}
@@ -0,0 +1,331 @@
+// atlhyper_master_v2/notifier/escalation.go
+// 值班升级：匹配升级策略的告警按步骤通知值班人员，直到被确认或恢复
+//
+// 流程:
+//   - SendWithTemplate 中，触发告警匹配已启用的策略时创建升级记录（同一策略同一告警只保留一条未结束的记录）
+//   - escalationLoop 执行到期步骤：按值班表计算目标用户，通过 Email 渠道发送到个人邮箱
+//   - 确认（API 或消息中的确认链接）/ 告警恢复后停止升级
+//
+// 个人通知直接发送，不写入投递记录：失败时由后续升级步骤兜底
+package notifier
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/notifier/template"
+)
+
+const (
+	escalationPollInterval = 30 * time.Second
+	escalationBatchSize    = 50
+	escalationRetention    = 30 * 24 * time.Hour // 已确认 / 已恢复记录保留时长
+	overrideRetention      = 30 * 24 * time.Hour // 已结束替班保留时长
+)
+
+// validEscalationTargets 升级目标
+var validEscalationTargets = map[string]bool{
+	database.EscalatePrimary:   true,
+	database.EscalateSecondary: true,
+	database.EscalateAll:       true,
+}
+
+// ParseEscalationSteps 解析并校验升级步骤
+// 至少一步；DelayMinutes 非负且不递减
+func ParseEscalationSteps(raw string) ([]database.EscalationStep, error) {
+	var steps []database.EscalationStep
+	if err := json.Unmarshal([]byte(raw), &steps); err != nil {
+		return nil, fmt.Errorf("invalid steps: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("at least one step is required")
+	}
+	for i, s := range steps {
+		if !validEscalationTargets[s.Target] {
+			return nil, fmt.Errorf("step %d: invalid target %q", i+1, s.Target)
+		}
+		if s.DelayMinutes < 0 || (i > 0 && s.DelayMinutes < steps[i-1].DelayMinutes) {
+			return nil, fmt.Errorf("step %d: delayMinutes must be non-negative and non-decreasing", i+1)
+		}
+	}
+	return steps, nil
+}
+
+// SetAckURL 设置确认链接地址（Master 对外地址，为空则消息中不附确认链接）
+func (m *Manager) SetAckURL(baseURL string) {
+	m.ackURL = strings.TrimRight(baseURL, "/")
+}
+
+// ackLink 确认链接
+func (m *Manager) ackLink(token string) string {
+	if m.ackURL == "" {
+		return ""
+	}
+	return m.ackURL + "/api/v2/oncall/ack?token=" + token
+}
+
+// resolveEscalations 恢复告警结束对应触发告警的所有升级
+// 先于抑制 / 静默执行：被静默或抑制的恢复告警同样需要停止升级
+func (m *Manager) resolveEscalations(ctx context.Context, templateName string, data *template.AlertData, labels Labels, now time.Time) {
+	if m.repos.Escalations == nil {
+		return
+	}
+	firing, ok := resolvedBy[templateName]
+	if !ok {
+		return
+	}
+	n, err := m.repos.Escalations.ResolveAlert(ctx, alertKey(firing, labels), now)
+	if err != nil {
+		log.Warn("结束值班升级失败", "err", err)
+	} else if n > 0 {
+		log.Info("告警恢复，已结束值班升级", "title", data.Title, "count", n)
+	}
+}
+
+// escalate 触发告警匹配升级策略时开启升级（已有未结束的升级则复用并附带确认链接）
+// 恢复告警由 resolveEscalations 处理
+func (m *Manager) escalate(ctx context.Context, templateName string, data *template.AlertData, labels Labels, now time.Time) {
+	if m.repos.Policies == nil || m.repos.Escalations == nil {
+		return
+	}
+	if _, ok := resolvedBy[templateName]; ok {
+		return
+	}
+
+	policies, err := m.repos.Policies.List(ctx)
+	if err != nil {
+		log.Warn("获取升级策略失败", "err", err)
+		return
+	}
+
+	key := alertKey(templateName, labels)
+	created := false
+	for _, p := range policies {
+		if !p.Enabled {
+			continue
+		}
+		matchers, err := ParseMatchers(p.Matchers)
+		if err != nil || !matchers.Matches(labels) {
+			continue
+		}
+		steps, err := ParseEscalationSteps(p.Steps)
+		if err != nil {
+			log.Warn("升级策略步骤无效，已跳过", "policy", p.Name, "err", err)
+			continue
+		}
+
+		existing, err := m.repos.Escalations.GetOpen(ctx, p.ID, key)
+		if err != nil {
+			log.Warn("获取值班升级失败", "policy", p.Name, "err", err)
+			continue
+		}
+		if existing != nil {
+			if data.AckURL == "" {
+				data.AckURL = m.ackLink(existing.AckToken)
+			}
+			continue
+		}
+
+		payload, err := json.Marshal(data)
+		if err != nil {
+			log.Warn("序列化告警数据失败", "err", err)
+			return
+		}
+		next := now.Add(time.Duration(steps[0].DelayMinutes) * time.Minute)
+		e := &database.Escalation{
+			PolicyID:     p.ID,
+			AlertKey:     key,
+			TemplateName: templateName,
+			Title:        data.Title,
+			Severity:     data.Severity,
+			Data:         string(payload),
+			Status:       database.EscalationOpen,
+			AckToken:     newAckToken(),
+			NextAt:       &next,
+		}
+		if err := m.repos.Escalations.Create(ctx, e); err != nil {
+			log.Error("创建值班升级失败", "policy", p.Name, "err", err)
+			continue
+		}
+		log.Info("已开启值班升级", "policy", p.Name, "title", data.Title, "id", e.ID)
+		if data.AckURL == "" {
+			data.AckURL = m.ackLink(e.AckToken)
+		}
+		created = true
+	}
+
+	// 首步无延迟时立即执行，无需等待下一次轮询
+	if created {
+		select {
+		case m.escalateNow <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// escalationLoop 定期执行到期的升级步骤
+func (m *Manager) escalationLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(escalationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+		case <-m.escalateNow:
+		}
+		m.escalateDue(context.Background(), time.Now())
+	}
+}
+
+// escalateDue 执行到期的升级步骤
+func (m *Manager) escalateDue(ctx context.Context, now time.Time) {
+	due, err := m.repos.Escalations.ListDue(ctx, now, escalationBatchSize)
+	if err != nil {
+		log.Warn("获取待升级告警失败", "err", err)
+		return
+	}
+	for _, e := range due {
+		m.advance(ctx, e, now)
+	}
+}
+
+// advance 执行当前步骤并安排下一步骤
+func (m *Manager) advance(ctx context.Context, e *database.Escalation, now time.Time) {
+	var steps []database.EscalationStep
+	p, err := m.repos.Policies.GetByID(ctx, e.PolicyID)
+	if err != nil {
+		log.Warn("获取升级策略失败", "policy", e.PolicyID, "err", err)
+		return
+	}
+	if p != nil && p.Enabled {
+		steps, _ = ParseEscalationSteps(p.Steps)
+	}
+
+	if e.Level < len(steps) {
+		m.page(ctx, e, p, steps[e.Level], now)
+		e.Level++
+	}
+
+	// 下一步骤时间以告警触发时间为基准；已错过的步骤在下一次轮询执行
+	e.NextAt = nil
+	if e.Level < len(steps) {
+		next := e.CreatedAt.Add(time.Duration(steps[e.Level].DelayMinutes) * time.Minute)
+		if next.Before(now) {
+			next = now
+		}
+		e.NextAt = &next
+	}
+	if err := m.repos.Escalations.UpdateProgress(ctx, e); err != nil {
+		log.Error("更新值班升级失败", "id", e.ID, "err", err)
+	}
+}
+
+// page 通知步骤目标用户（通过已启用的 Email 渠道发送到个人邮箱）
+func (m *Manager) page(ctx context.Context, e *database.Escalation, p *database.EscalationPolicy, step database.EscalationStep, now time.Time) {
+	emails := m.stepRecipients(ctx, p.ScheduleID, step.Target, now)
+	if len(emails) == 0 {
+		log.Warn("升级步骤无可通知的值班人员", "policy", p.Name, "target", step.Target, "level", e.Level+1)
+		return
+	}
+
+	ch := m.pagingChannel(ctx, emails)
+	if ch == nil {
+		log.Warn("无可用 Email 渠道，值班通知未发送", "policy", p.Name, "level", e.Level+1)
+		return
+	}
+
+	data := &template.AlertData{}
+	if err := json.Unmarshal([]byte(e.Data), data); err != nil {
+		log.Warn("解析告警数据失败", "id", e.ID, "err", err)
+		return
+	}
+	data.AckURL = m.ackLink(e.AckToken)
+	msg, err := m.renderer.Render(e.TemplateName, ch.Type, data)
+	if err != nil {
+		log.Error("渲染值班通知失败", "id", e.ID, "err", err)
+		return
+	}
+	annotate(msg, e.TemplateName, data)
+	msg.Subject = fmt.Sprintf("[值班升级 L%d] %s", e.Level+1, msg.Subject)
+
+	if err := m.send(ctx, ch, msg); err != nil {
+		log.Error("值班通知发送失败", "policy", p.Name, "level", e.Level+1, "err", err)
+		return
+	}
+	log.Info("已通知值班人员", "policy", p.Name, "level", e.Level+1, "target", step.Target, "recipients", len(emails))
+}
+
+// stepRecipients 步骤目标用户的邮箱（跳过已禁用或未设置邮箱的用户）
+func (m *Manager) stepRecipients(ctx context.Context, scheduleID int64, target string, now time.Time) []string {
+	if m.repos.Schedules == nil || m.repos.Users == nil {
+		return nil
+	}
+	s, err := m.repos.Schedules.GetByID(ctx, scheduleID)
+	if err != nil || s == nil {
+		log.Warn("获取值班表失败", "schedule", scheduleID, "err", err)
+		return nil
+	}
+	var overrides []*database.OncallOverride
+	if m.repos.Overrides != nil {
+		overrides, err = m.repos.Overrides.ListActive(ctx, s.ID, now)
+		if err != nil {
+			log.Warn("获取替班失败", "schedule", s.Name, "err", err)
+		}
+	}
+	shift, err := ResolveOncall(s, overrides, now)
+	if err != nil {
+		log.Warn("计算值班失败", "schedule", s.Name, "err", err)
+		return nil
+	}
+
+	var emails []string
+	for _, id := range shift.Targets(target) {
+		u, err := m.repos.Users.GetByID(ctx, id)
+		if err != nil || u == nil || u.Status != 1 || u.Email == "" {
+			continue
+		}
+		emails = append(emails, u.Email)
+	}
+	return emails
+}
+
+// pagingChannel 使用首个已启用的 Email 渠道，收件人替换为值班人员
+func (m *Manager) pagingChannel(ctx context.Context, emails []string) *database.NotifyChannel {
+	channels, err := m.repos.Channels.ListEnabled(ctx)
+	if err != nil {
+		log.Warn("获取渠道列表失败", "err", err)
+		return nil
+	}
+	for _, ch := range channels {
+		if ch.Type != "email" {
+			continue
+		}
+		var cfg database.EmailConfig
+		if err := json.Unmarshal([]byte(ch.Config), &cfg); err != nil {
+			continue
+		}
+		cfg.ToAddresses = emails
+		raw, _ := json.Marshal(cfg)
+		paging := *ch
+		paging.Config = string(raw)
+		return &paging
+	}
+	return nil
+}
+
+// newAckToken 生成确认链接令牌
+func newAckToken() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,373 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/notifier/channel"
+)
+
+type fakeScheduleRepo struct {
+	database.OncallScheduleRepository
+	schedule *database.OncallSchedule
+}
+
+func (r *fakeScheduleRepo) GetByID(ctx context.Context, id int64) (*database.OncallSchedule, error) {
+	if r.schedule != nil && r.schedule.ID == id {
+		return r.schedule, nil
+	}
+	return nil, nil
+}
+
+type fakeUserRepo struct {
+	database.UserRepository
+	users map[int64]*database.User
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id int64) (*database.User, error) {
+	return r.users[id], nil
+}
+
+type fakePolicyRepo struct {
+	database.EscalationPolicyRepository
+	policies []*database.EscalationPolicy
+}
+
+func (r *fakePolicyRepo) List(ctx context.Context) ([]*database.EscalationPolicy, error) {
+	return r.policies, nil
+}
+
+func (r *fakePolicyRepo) GetByID(ctx context.Context, id int64) (*database.EscalationPolicy, error) {
+	for _, p := range r.policies {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+type fakeEscalationRepo struct {
+	database.EscalationRepository
+	mu    sync.Mutex
+	items []*database.Escalation
+}
+
+func (r *fakeEscalationRepo) Create(ctx context.Context, e *database.Escalation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.ID = int64(len(r.items) + 1)
+	e.CreatedAt = time.Now()
+	cp := *e
+	r.items = append(r.items, &cp)
+	return nil
+}
+
+func (r *fakeEscalationRepo) GetOpen(ctx context.Context, policyID int64, alertKey string) (*database.Escalation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.items {
+		if e.PolicyID == policyID && e.AlertKey == alertKey && e.Status == database.EscalationOpen {
+			cp := *e
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeEscalationRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]*database.Escalation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*database.Escalation
+	for _, e := range r.items {
+		if e.Status == database.EscalationOpen && e.NextAt != nil && !e.NextAt.After(now) {
+			cp := *e
+			due = append(due, &cp)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeEscalationRepo) UpdateProgress(ctx context.Context, e *database.Escalation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, item := range r.items {
+		if item.ID == e.ID && item.Status == database.EscalationOpen {
+			item.Level = e.Level
+			item.NextAt = e.NextAt
+		}
+	}
+	return nil
+}
+
+func (r *fakeEscalationRepo) Ack(ctx context.Context, id int64, by string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.items {
+		if e.ID == id && e.Status == database.EscalationOpen {
+			e.Status, e.AckedBy, e.NextAt = database.EscalationAcked, by, nil
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeEscalationRepo) ResolveAlert(ctx context.Context, alertKey string, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for _, e := range r.items {
+		if e.AlertKey == alertKey && e.Status == database.EscalationOpen {
+			e.Status, e.NextAt = database.EscalationResolved, nil
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeEscalationRepo) get(id int64) database.Escalation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.items[id-1]
+}
+
+// page 记录的值班通知
+type page struct {
+	to      []string
+	subject string
+	body    string
+}
+
+func newEscalationManager(t *testing.T) (*Manager, *fakeEscalationRepo, func() []page) {
+	t.Helper()
+	escalations := &fakeEscalationRepo{}
+	channels := &fakeChannelRepo{channels: []*database.NotifyChannel{
+		{ID: 1, Type: "slack", Name: "ops", Enabled: true},
+		{ID: 2, Type: "email", Name: "mail", Enabled: true, Config: `{"smtpHost":"smtp.example.com","toAddresses":["team@example.com"]}`},
+	}}
+	m, err := NewManager(ManagerRepos{
+		Channels: channels,
+		Users: &fakeUserRepo{users: map[int64]*database.User{
+			1: {ID: 1, Username: "alice", Email: "alice@example.com", Status: 1},
+			2: {ID: 2, Username: "bob", Email: "bob@example.com", Status: 1},
+			3: {ID: 3, Username: "carol", Email: "carol@example.com", Status: 1},
+		}},
+		Schedules: &fakeScheduleRepo{schedule: &database.OncallSchedule{
+			ID: 1, Name: "sre", Rotation: database.RotationWeekly,
+			StartAt: time.Now().Add(-time.Hour), Members: "[1,2,3]",
+		}},
+		Policies: &fakePolicyRepo{policies: []*database.EscalationPolicy{{
+			ID: 1, Name: "critical", Enabled: true, ScheduleID: 1,
+			Matchers: `{"severity":"critical"}`,
+			Steps:    `[{"delayMinutes":0,"target":"primary"},{"delayMinutes":15,"target":"secondary"},{"delayMinutes":30,"target":"all"}]`,
+		}}},
+		Escalations: escalations,
+	})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	m.SetAckURL("https://atlhyper.example.com/")
+
+	var mu sync.Mutex
+	var pages []page
+	m.send = func(ctx context.Context, ch *database.NotifyChannel, msg *channel.Message) error {
+		if !strings.HasPrefix(msg.Subject, "[值班升级") {
+			return nil // 常规渠道发送
+		}
+		var cfg database.EmailConfig
+		_ = json.Unmarshal([]byte(ch.Config), &cfg)
+		mu.Lock()
+		defer mu.Unlock()
+		pages = append(pages, page{to: cfg.ToAddresses, subject: msg.Subject, body: msg.Body})
+		return nil
+	}
+	return m, escalations, func() []page {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]page(nil), pages...)
+	}
+}
+
+func TestResolveOncall_Rotation(t *testing.T) {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	s := &database.OncallSchedule{ID: 1, Rotation: database.RotationWeekly, StartAt: start, Members: "[1,2,3]"}
+
+	cases := []struct {
+		at                 time.Time
+		primary, secondary int64
+	}{
+		{start.Add(-time.Hour), 1, 2},
+		{start.Add(time.Hour), 1, 2},
+		{start.Add(7 * 24 * time.Hour), 2, 3},
+		{start.Add(15 * 24 * time.Hour), 3, 1},
+		{start.Add(21 * 24 * time.Hour), 1, 2},
+	}
+	for _, c := range cases {
+		shift, err := ResolveOncall(s, nil, c.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shift.Primary != c.primary || shift.Secondary != c.secondary {
+			t.Errorf("at %s: primary=%d secondary=%d, want %d/%d", c.at, shift.Primary, shift.Secondary, c.primary, c.secondary)
+		}
+	}
+
+	shift, _ := ResolveOncall(s, nil, start.Add(8*24*time.Hour))
+	if !shift.StartsAt.Equal(start.Add(7*24*time.Hour)) || !shift.EndsAt.Equal(start.Add(14*24*time.Hour)) {
+		t.Errorf("shift bounds = %s - %s", shift.StartsAt, shift.EndsAt)
+	}
+}
+
+func TestResolveOncall_Override(t *testing.T) {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	s := &database.OncallSchedule{ID: 1, Rotation: database.RotationDaily, StartAt: start, Members: "[1,2,3]"}
+	overrides := []*database.OncallOverride{
+		{ID: 7, ScheduleID: 1, UserID: 9, StartsAt: start, EndsAt: start.Add(2 * time.Hour)},
+		{ID: 8, ScheduleID: 1, UserID: 2, StartsAt: start.Add(3 * time.Hour), EndsAt: start.Add(4 * time.Hour)},
+	}
+
+	shift, _ := ResolveOncall(s, overrides, start.Add(time.Hour))
+	if shift.Primary != 9 || shift.Secondary != 2 || shift.Scheduled != 1 || shift.OverrideID != 7 {
+		t.Errorf("override: %+v", shift)
+	}
+
+	// 替班人恰好是副值班：原主值班兜底
+	shift, _ = ResolveOncall(s, overrides, start.Add(3*time.Hour+time.Minute))
+	if shift.Primary != 2 || shift.Secondary != 1 {
+		t.Errorf("override by secondary: %+v", shift)
+	}
+
+	// 替班结束后恢复轮换
+	shift, _ = ResolveOncall(s, overrides, start.Add(2*time.Hour))
+	if shift.Primary != 1 || shift.OverrideID != 0 {
+		t.Errorf("after override: %+v", shift)
+	}
+
+	if got := shift.Targets(database.EscalateAll); len(got) != 3 {
+		t.Errorf("all targets = %v", got)
+	}
+}
+
+func TestParseEscalationSteps(t *testing.T) {
+	bad := []string{
+		`[]`,
+		`[{"delayMinutes":0,"target":"boss"}]`,
+		`[{"delayMinutes":10,"target":"primary"},{"delayMinutes":5,"target":"secondary"}]`,
+		`[{"delayMinutes":-1,"target":"primary"}]`,
+		`not json`,
+	}
+	for _, raw := range bad {
+		if _, err := ParseEscalationSteps(raw); err == nil {
+			t.Errorf("ParseEscalationSteps(%s) should fail", raw)
+		}
+	}
+	steps, err := ParseEscalationSteps(`[{"delayMinutes":0,"target":"primary"},{"delayMinutes":0,"target":"all"}]`)
+	if err != nil || len(steps) != 2 {
+		t.Errorf("steps = %v, %v", steps, err)
+	}
+}
+
+func TestEscalation_PrimarySecondaryAll(t *testing.T) {
+	m, escalations, pages := newEscalationManager(t)
+	ctx := context.Background()
+
+	data := heartbeat("prod")
+	if err := m.SendWithTemplate("heartbeat_offline", data); err != nil {
+		t.Fatal(err)
+	}
+	e := escalations.get(1)
+	if e.Status != database.EscalationOpen || e.AckToken == "" {
+		t.Fatalf("escalation = %+v", e)
+	}
+	if data.AckURL != "https://atlhyper.example.com/api/v2/oncall/ack?token="+e.AckToken {
+		t.Errorf("AckURL = %q", data.AckURL)
+	}
+
+	// 重复告警复用同一升级
+	_ = m.SendWithTemplate("heartbeat_offline", heartbeat("prod"))
+	if len(escalations.items) != 1 {
+		t.Fatalf("escalations = %d, want 1", len(escalations.items))
+	}
+
+	now := time.Now()
+	m.escalateDue(ctx, now)
+	m.escalateDue(ctx, now.Add(16*time.Minute))
+	m.escalateDue(ctx, now.Add(31*time.Minute))
+	m.escalateDue(ctx, now.Add(time.Hour)) // 无后续步骤
+
+	got := pages()
+	if len(got) != 3 {
+		t.Fatalf("pages = %d, want 3: %+v", len(got), got)
+	}
+	want := [][]string{
+		{"alice@example.com"},
+		{"bob@example.com"},
+		{"alice@example.com", "bob@example.com", "carol@example.com"},
+	}
+	for i, p := range got {
+		if strings.Join(p.to, ",") != strings.Join(want[i], ",") {
+			t.Errorf("page %d to = %v, want %v", i, p.to, want[i])
+		}
+		if !strings.Contains(p.body, "token="+e.AckToken) {
+			t.Errorf("page %d = %q / %q", i, p.subject, p.body)
+		}
+	}
+	if e := escalations.get(1); e.Level != 3 || e.NextAt != nil {
+		t.Errorf("final escalation = %+v", e)
+	}
+}
+
+func TestEscalation_AckStopsAndRecoveryResolves(t *testing.T) {
+	m, escalations, pages := newEscalationManager(t)
+	ctx := context.Background()
+
+	_ = m.SendWithTemplate("heartbeat_offline", heartbeat("prod"))
+	now := time.Now()
+	m.escalateDue(ctx, now)
+	if _, err := escalations.Ack(ctx, 1, "alice", now); err != nil {
+		t.Fatal(err)
+	}
+	m.escalateDue(ctx, now.Add(time.Hour))
+	if len(pages()) != 1 {
+		t.Errorf("pages after ack = %d, want 1", len(pages()))
+	}
+
+	// 非 critical 告警不匹配策略
+	_ = m.SendWithTemplate("k8s_event", k8sEvent("prod", "default", "warning"))
+	if len(escalations.items) != 1 {
+		t.Errorf("warning alert should not escalate")
+	}
+
+	// 确认后再次触发开启新的升级，恢复告警结束升级
+	_ = m.SendWithTemplate("heartbeat_offline", heartbeat("prod"))
+	if len(escalations.items) != 2 {
+		t.Fatalf("escalations = %d, want 2", len(escalations.items))
+	}
+	_ = m.SendWithTemplate("heartbeat_recovery", heartbeat("prod"))
+	if e := escalations.get(2); e.Status != database.EscalationResolved {
+		t.Errorf("status after recovery = %s", e.Status)
+	}
+}
+
+func TestEscalation_SilencedRecoveryResolves(t *testing.T) {
+	m, escalations, _ := newEscalationManager(t)
+
+	_ = m.SendWithTemplate("heartbeat_offline", heartbeat("prod"))
+	if e := escalations.get(1); e.Status != database.EscalationOpen {
+		t.Fatalf("escalation = %+v", e)
+	}
+
+	// 告警触发后才创建的静默覆盖了恢复告警
+	now := time.Now()
+	m.repos.Silences = &fakeSilenceRepo{silences: []*database.NotifySilence{
+		{ID: 1, Matchers: `{"cluster":"prod"}`, StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)},
+	}}
+	_ = m.SendWithTemplate("heartbeat_recovery", heartbeat("prod"))
+	if e := escalations.get(1); e.Status != database.EscalationResolved {
+		t.Errorf("status after silenced recovery = %s, want resolved", e.Status)
+	}
+}
@@ -0,0 +1,176 @@
+// atlhyper_master_v2/notifier/group.go
+// 告警分组：同一路由、同一分组标签值的告警合并发送
+//
+// 新分组等待 group_wait 后首次发送，之后新到达的告警按 group_interval 批量发送。
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/notifier/channel"
+	"AtlHyper/atlhyper_master_v2/notifier/template"
+)
+
+// batchSeparator 合并消息正文分隔符
+const batchSeparator = "\n\n────────────\n\n"
+
+// pendingAlert 待发送告警
+type pendingAlert struct {
+	templateName string
+	data         *template.AlertData
+}
+
+// alertGroup 告警分组
+type alertGroup struct {
+	channels  []*database.NotifyChannel
+	interval  time.Duration
+	alerts    []pendingAlert
+	timer     *time.Timer // 非空表示已安排发送
+	lastFlush time.Time
+}
+
+// flushFunc 分组发送回调
+type flushFunc func(channels []*database.NotifyChannel, alerts []pendingAlert)
+
+// grouper 告警分组器
+type grouper struct {
+	flush flushFunc
+
+	mu      sync.Mutex
+	groups  map[string]*alertGroup
+	stopped bool
+}
+
+func newGrouper(flush flushFunc) *grouper {
+	return &grouper{flush: flush, groups: make(map[string]*alertGroup)}
+}
+
+// add 加入分组；分组器已停止时返回 false（由调用方直接发送）
+func (g *grouper) add(key string, channels []*database.NotifyChannel, wait, interval time.Duration, alert pendingAlert, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopped {
+		return false
+	}
+
+	g.prune(now)
+
+	grp, ok := g.groups[key]
+	if !ok {
+		grp = &alertGroup{}
+		g.groups[key] = grp
+	}
+	grp.channels = channels
+	grp.interval = interval
+	grp.alerts = append(grp.alerts, alert)
+	if grp.timer != nil {
+		return true
+	}
+
+	delay := wait
+	if !grp.lastFlush.IsZero() {
+		delay = grp.lastFlush.Add(interval).Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+	}
+	grp.timer = time.AfterFunc(delay, func() { g.fire(key) })
+	return true
+}
+
+// fire 发送分组内累积的告警
+func (g *grouper) fire(key string) {
+	g.mu.Lock()
+	grp, ok := g.groups[key]
+	if !ok || grp.timer == nil {
+		g.mu.Unlock()
+		return
+	}
+	alerts := grp.alerts
+	channels := grp.channels
+	grp.alerts = nil
+	grp.timer = nil
+	grp.lastFlush = time.Now()
+	g.mu.Unlock()
+
+	if len(alerts) > 0 {
+		g.flush(channels, alerts)
+	}
+}
+
+// prune 清理超过 group_interval 仍无新告警的空闲分组（调用方持锁）
+func (g *grouper) prune(now time.Time) {
+	for key, grp := range g.groups {
+		if grp.timer == nil && now.Sub(grp.lastFlush) > grp.interval {
+			delete(g.groups, key)
+		}
+	}
+}
+
+// stop 停止分组器，立即发送所有待发送告警
+func (g *grouper) stop() {
+	g.mu.Lock()
+	g.stopped = true
+	type batch struct {
+		channels []*database.NotifyChannel
+		alerts   []pendingAlert
+	}
+	var pending []batch
+	for key, grp := range g.groups {
+		if grp.timer != nil {
+			grp.timer.Stop()
+			if len(grp.alerts) > 0 {
+				pending = append(pending, batch{grp.channels, grp.alerts})
+			}
+		}
+		delete(g.groups, key)
+	}
+	g.mu.Unlock()
+
+	for _, b := range pending {
+		g.flush(b.channels, b.alerts)
+	}
+}
+
+// severityRank 告警级别排序（合并消息取最高级别）
+var severityRank = map[string]int{"info": 1, "warning": 2, "critical": 3}
+
+// combineMessages 合并多条消息
+func combineMessages(msgs []*channel.Message) *channel.Message {
+	if len(msgs) == 1 {
+		return msgs[0]
+	}
+	bodies := make([]string, 0, len(msgs))
+	htmls := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		bodies = append(bodies, msg.Body)
+		if msg.HTML != "" {
+			htmls = append(htmls, msg.HTML)
+		}
+	}
+	combined := &channel.Message{
+		Subject:  fmt.Sprintf("[%d 条告警] %s", len(msgs), msgs[0].Subject),
+		Body:     strings.Join(bodies, batchSeparator),
+		Format:   msgs[0].Format,
+		Severity: msgs[0].Severity,
+		Status:   "resolved",
+		Parts:    msgs,
+	}
+	// 仅当每条消息都有 HTML 时合并 HTML 部分，避免 HTML 与纯文本内容不一致
+	if len(htmls) == len(msgs) {
+		combined.HTML = strings.Join(htmls, "<hr>")
+	}
+	for _, msg := range msgs {
+		if severityRank[msg.Severity] > severityRank[combined.Severity] {
+			combined.Severity = msg.Severity
+		}
+		if msg.Status != "resolved" {
+			combined.Status = "firing"
+		}
+	}
+	return combined
+}
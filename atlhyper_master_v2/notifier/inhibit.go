@@ -0,0 +1,175 @@
+// atlhyper_master_v2/notifier/inhibit.go
+// 告警抑制：记录活跃告警，源告警活跃时抑制满足条件的目标告警
+//
+// 活跃告警来自 SendWithTemplate 的每次调用:
+//   - 有对应恢复模板的告警（如 heartbeat_offline）保持活跃直到收到恢复告警
+//   - 其余告警（如 k8s_event）在 activeAlertTTL 后过期
+package notifier
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/notifier/channel"
+	"AtlHyper/atlhyper_master_v2/notifier/template"
+)
+
+// activeAlertTTL 无恢复模板的告警活跃时长
+const activeAlertTTL = 15 * time.Minute
+
+// resolvedBy 恢复模板 → 被恢复的告警模板
+var resolvedBy = map[string]string{
+	"heartbeat_recovery":      "heartbeat_offline",
+	"aiops_incident_resolved": "aiops_incident",
+	"slo_burn_rate_resolved":  "slo_burn_rate",
+}
+
+// activeAlert 活跃告警
+type activeAlert struct {
+	labels    Labels
+	expiresAt time.Time // 零值表示直到恢复
+}
+
+// inhibitRule 解析后的抑制规则
+type inhibitRule struct {
+	name   string
+	source Matchers
+	target Matchers
+	equal  []string
+}
+
+// alertTracker 活跃告警跟踪
+type alertTracker struct {
+	mu     sync.Mutex
+	active map[string]*activeAlert // alertKey -> alert
+}
+
+func newAlertTracker() *alertTracker {
+	return &alertTracker{active: make(map[string]*activeAlert)}
+}
+
+// alertKey 告警标识（同名同对象视为同一告警）
+func alertKey(alertname string, labels Labels) string {
+	return alertname + "|" + labels["cluster"] + "|" + labels["namespace"] + "|" + labels["resource"]
+}
+
+// annotate 为渲染后的消息附加状态、标签与去重键
+// 恢复告警使用其对应触发告警的去重键，以便 PagerDuty 等渠道自动关闭事件
+func annotate(msg *channel.Message, templateName string, data *template.AlertData) {
+	labels := AlertLabels(templateName, data)
+	firing := templateName
+	msg.Status = "firing"
+	if name, ok := resolvedBy[templateName]; ok {
+		firing = name
+		msg.Status = "resolved"
+	}
+	sum := sha1.Sum([]byte(alertKey(firing, labels)))
+	msg.DedupKey = "atlhyper-" + hex.EncodeToString(sum[:])
+	msg.Labels = labels
+}
+
+// observe 记录告警：恢复告警移除对应活跃告警，其余告警标记为活跃
+func (t *alertTracker) observe(labels Labels, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	name := labels["alertname"]
+	if firing, ok := resolvedBy[name]; ok {
+		delete(t.active, alertKey(firing, labels))
+		return
+	}
+
+	a := &activeAlert{labels: labels}
+	if !isResolvable(name) {
+		a.expiresAt = now.Add(activeAlertTTL)
+	}
+	t.active[alertKey(name, labels)] = a
+}
+
+// inhibitedBy 返回抑制该告警的规则名（未被抑制返回空）
+func (t *alertTracker) inhibitedBy(rules []inhibitRule, labels Labels, now time.Time) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	self := alertKey(labels["alertname"], labels)
+	for key, a := range t.active {
+		if !a.expiresAt.IsZero() && now.After(a.expiresAt) {
+			delete(t.active, key)
+		}
+	}
+
+	for _, rule := range rules {
+		if !rule.target.Matches(labels) {
+			continue
+		}
+		for key, a := range t.active {
+			if key == self || !rule.source.Matches(a.labels) {
+				continue
+			}
+			if equalLabels(rule.equal, a.labels, labels) {
+				return rule.name
+			}
+		}
+	}
+	return ""
+}
+
+// isResolvable 是否存在对应的恢复模板
+func isResolvable(name string) bool {
+	for _, firing := range resolvedBy {
+		if firing == name {
+			return true
+		}
+	}
+	return false
+}
+
+// equalLabels 两组标签在指定标签上取值相同
+func equalLabels(names []string, a, b Labels) bool {
+	for _, name := range names {
+		if a[name] != b[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseInhibitRules 解析已启用的抑制规则（格式错误的规则跳过）
+func parseInhibitRules(rows []*database.NotifyInhibitRule) []inhibitRule {
+	rules := make([]inhibitRule, 0, len(rows))
+	for _, r := range rows {
+		if !r.Enabled {
+			continue
+		}
+		source, err1 := ParseMatchers(r.SourceMatchers)
+		target, err2 := ParseMatchers(r.TargetMatchers)
+		equal, err3 := parseStringList(r.Equal)
+		if err1 != nil || err2 != nil || err3 != nil {
+			log.Warn("抑制规则格式错误，已跳过", "rule", r.Name)
+			continue
+		}
+		rules = append(rules, inhibitRule{name: r.Name, source: source, target: target, equal: equal})
+	}
+	return rules
+}
+
+// silencedBy 返回匹配该告警的静默 ID（未被静默返回 0）
+func silencedBy(silences []*database.NotifySilence, labels Labels, now time.Time) int64 {
+	for _, s := range silences {
+		if now.Before(s.StartsAt) || !now.Before(s.EndsAt) {
+			continue
+		}
+		m, err := ParseMatchers(s.Matchers)
+		if err != nil || len(m) == 0 {
+			// 空匹配器的静默不生效，避免误静默所有告警
+			continue
+		}
+		if m.Matches(labels) {
+			return s.ID
+		}
+	}
+	return 0
+}
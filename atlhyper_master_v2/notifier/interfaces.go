@@ -12,11 +12,11 @@ import (
 // AlertManager 告警管理器接口
 type AlertManager interface {
 	// SendWithTemplate 使用模板发送告警
-	// templateName: heartbeat_offline, heartbeat_recovery, k8s_event
+	// templateName: heartbeat_offline, heartbeat_recovery, k8s_event, aiops_incident, aiops_incident_resolved, slo_burn_rate, slo_burn_rate_resolved
 	SendWithTemplate(templateName string, data *template.AlertData) error
 
-	// Test 测试指定渠道
-	Test(ctx context.Context, channelType string) error
+	// Test 测试指定渠道（按渠道 ID）
+	Test(ctx context.Context, channelID int64) error
 
 	// Start 启动
 	Start() error
@@ -1,11 +1,12 @@
 // atlhyper_master_v2/notifier/manager.go
 // 告警管理器实现
-// 编排 template 和 channel 模块
+// 编排 template 和 channel 模块，负责抑制、静默、路由与分组
 package notifier
 
 import (
 	"context"
 	"sync"
+	"time"
 
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/notifier/channel"
@@ -15,46 +16,202 @@ import (
 
 var log = logger.Module("Notifier")
 
+// silenceRetention 过期静默保留时长
+const silenceRetention = 7 * 24 * time.Hour
+
+// ManagerRepos 告警管理器依赖的仓库
+// Channels 以外的仓库为空时对应功能不生效
+type ManagerRepos struct {
+	Channels   database.NotifyChannelRepository
+	Routes     database.NotifyRouteRepository
+	Silences   database.NotifySilenceRepository
+	Inhibits   database.NotifyInhibitRuleRepository
+	Deliveries database.NotifyDeliveryRepository
+	Templates  database.NotifyTemplateRepository
+
+	// 值班升级（Policies 与 Escalations 均非空时启用）
+	Users       database.UserRepository
+	Schedules   database.OncallScheduleRepository
+	Overrides   database.OncallOverrideRepository
+	Policies    database.EscalationPolicyRepository
+	Escalations database.EscalationRepository
+}
+
 // Manager 告警管理器
+// 发送流程: 抑制 → 静默 → 值班升级 → 路由匹配 → 分组 → 渲染并发送到渠道（记录投递，失败重试）
 type Manager struct {
-	notifyRepo database.NotifyChannelRepository
-	factory    *channel.Factory
-	renderer   *template.Renderer
+	repos    ManagerRepos
+	factory  *channel.Factory
+	renderer *template.Renderer
+	tracker  *alertTracker
+	grouper  *grouper
+
+	// send 发送到单个渠道（测试可替换）
+	send func(ctx context.Context, ch *database.NotifyChannel, msg *channel.Message) error
+
+	ackURL      string        // 确认链接地址
+	escalateNow chan struct{} // 新建升级后立即执行首步
 
-	mu sync.RWMutex
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// matchedRoute 匹配的路由
+type matchedRoute struct {
+	route    *database.NotifyRoute
+	channels []*database.NotifyChannel
+	groupBy  []string
 }
 
 // NewManager 创建告警管理器
-func NewManager(notifyRepo database.NotifyChannelRepository) (*Manager, error) {
+func NewManager(repos ManagerRepos) (*Manager, error) {
 	renderer, err := template.NewRenderer()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Manager{
-		notifyRepo: notifyRepo,
-		factory:    channel.NewFactory(),
-		renderer:   renderer,
-	}, nil
+	m := &Manager{
+		repos:    repos,
+		factory:  channel.NewFactory(),
+		renderer: renderer,
+		tracker:  newAlertTracker(),
+		stopCh:   make(chan struct{}),
+
+		escalateNow: make(chan struct{}, 1),
+	}
+	m.send = m.sendToChannel
+	m.grouper = newGrouper(func(channels []*database.NotifyChannel, alerts []pendingAlert) {
+		m.deliver(context.Background(), channels, alerts)
+	})
+	return m, nil
 }
 
 // Start 启动
 func (m *Manager) Start() error {
+	if err := m.ReloadTemplates(context.Background()); err != nil {
+		log.Warn("加载自定义通知模板失败，使用内置模板", "err", err)
+	}
+	if m.repos.Silences != nil || m.repos.Deliveries != nil || m.repos.Escalations != nil {
+		m.wg.Add(1)
+		go m.cleanupLoop()
+	}
+	if m.repos.Deliveries != nil {
+		m.wg.Add(1)
+		go m.retryLoop()
+	}
+	if m.repos.Policies != nil && m.repos.Escalations != nil {
+		m.wg.Add(1)
+		go m.escalationLoop()
+	}
 	log.Info("已启动")
 	return nil
 }
 
-// Stop 停止
+// Stop 停止（分组中待发送的告警立即发送）
 func (m *Manager) Stop() {
+	close(m.stopCh)
+	m.wg.Wait()
+	m.grouper.stop()
 	log.Info("已停止")
 }
 
+// cleanupLoop 定期清理过期静默、已结束的投递记录、升级记录与替班
+func (m *Manager) cleanupLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.cleanup(context.Background(), time.Now())
+		}
+	}
+}
+
+// cleanup 清理过期静默、已结束的投递记录、升级记录与替班
+func (m *Manager) cleanup(ctx context.Context, now time.Time) {
+	if m.repos.Silences != nil {
+		n, err := m.repos.Silences.DeleteExpiredBefore(ctx, now.Add(-silenceRetention))
+		if err != nil {
+			log.Warn("清理过期静默失败", "err", err)
+		} else if n > 0 {
+			log.Info("已清理过期静默", "count", n)
+		}
+	}
+	if m.repos.Deliveries != nil {
+		n, err := m.repos.Deliveries.DeleteFinishedBefore(ctx, now.Add(-deliveryRetention))
+		if err != nil {
+			log.Warn("清理投递记录失败", "err", err)
+		} else if n > 0 {
+			log.Info("已清理投递记录", "count", n)
+		}
+	}
+	if m.repos.Escalations != nil {
+		n, err := m.repos.Escalations.DeleteFinishedBefore(ctx, now.Add(-escalationRetention))
+		if err != nil {
+			log.Warn("清理值班升级记录失败", "err", err)
+		} else if n > 0 {
+			log.Info("已清理值班升级记录", "count", n)
+		}
+	}
+	if m.repos.Overrides != nil {
+		if _, err := m.repos.Overrides.DeleteEndedBefore(ctx, now.Add(-overrideRetention)); err != nil {
+			log.Warn("清理已结束替班失败", "err", err)
+		}
+	}
+}
+
+// ReloadTemplates 从数据库重新加载自定义模板
+// 无法解析的模板被跳过（对应告警继续使用内置模板）
+func (m *Manager) ReloadTemplates(ctx context.Context) error {
+	if m.repos.Templates == nil {
+		return nil
+	}
+	items, err := m.repos.Templates.List(ctx)
+	if err != nil {
+		return err
+	}
+	bodies := make(map[string]string, len(items))
+	for _, t := range items {
+		bodies[t.Name+"_"+t.Variant] = t.Body
+	}
+	errs := m.renderer.SetOverrides(bodies)
+	for key, err := range errs {
+		log.Warn("自定义通知模板无效，使用内置模板", "template", key, "err", err)
+	}
+	log.Info("通知模板已加载", "custom", len(bodies)-len(errs))
+	return nil
+}
+
 // SendWithTemplate 使用模板发送告警
 func (m *Manager) SendWithTemplate(templateName string, data *template.AlertData) error {
 	ctx := context.Background()
+	now := time.Now()
+	labels := AlertLabels(templateName, data)
+
+	// 1. 记录活跃告警并检查抑制（恢复告警先结束值班升级，不受抑制 / 静默影响）
+	m.tracker.observe(labels, now)
+	m.resolveEscalations(ctx, templateName, data, labels, now)
+	if rule := m.inhibitedBy(ctx, labels, now); rule != "" {
+		log.Info("告警已抑制", "title", data.Title, "rule", rule)
+		return nil
+	}
 
-	// 1. 获取所有已启用的渠道
-	channels, err := m.notifyRepo.ListEnabled(ctx)
+	// 2. 检查静默
+	if id := m.silencedBy(ctx, labels, now); id != 0 {
+		log.Info("告警已静默", "title", data.Title, "silence", id)
+		return nil
+	}
+
+	// 3. 值班升级（匹配升级策略时附带确认链接）
+	m.escalate(ctx, templateName, data, labels, now)
+
+	// 4. 获取所有已启用的渠道
+	channels, err := m.repos.Channels.ListEnabled(ctx)
 	if err != nil {
 		log.Error("获取渠道列表失败", "err", err)
 		return err
@@ -65,7 +222,107 @@ func (m *Manager) SendWithTemplate(templateName string, data *template.AlertData
 		return nil
 	}
 
-	// 2. 并发发送到各渠道
+	// 5. 路由匹配；无匹配路由时发送到所有已启用渠道
+	alert := pendingAlert{templateName: templateName, data: data}
+	routes := m.matchRoutes(ctx, labels, channels)
+	if len(routes) == 0 {
+		m.deliver(ctx, channels, []pendingAlert{alert})
+		return nil
+	}
+
+	for _, r := range routes {
+		if len(r.channels) == 0 {
+			log.Warn("路由无可用渠道，告警未发送", "route", r.route.Name, "title", data.Title)
+			continue
+		}
+		wait := time.Duration(r.route.GroupWaitS) * time.Second
+		interval := time.Duration(r.route.GroupIntervalS) * time.Second
+		if wait <= 0 && interval <= 0 {
+			m.deliver(ctx, r.channels, []pendingAlert{alert})
+			continue
+		}
+		key := groupKey(r.route.ID, r.groupBy, labels)
+		if !m.grouper.add(key, r.channels, wait, interval, alert, now) {
+			m.deliver(ctx, r.channels, []pendingAlert{alert})
+		}
+	}
+	return nil
+}
+
+// inhibitedBy 返回抑制该告警的规则名（读取失败时不抑制）
+func (m *Manager) inhibitedBy(ctx context.Context, labels Labels, now time.Time) string {
+	if m.repos.Inhibits == nil {
+		return ""
+	}
+	rows, err := m.repos.Inhibits.List(ctx)
+	if err != nil {
+		log.Warn("获取抑制规则失败", "err", err)
+		return ""
+	}
+	return m.tracker.inhibitedBy(parseInhibitRules(rows), labels, now)
+}
+
+// silencedBy 返回匹配该告警的静默 ID（读取失败时不静默）
+func (m *Manager) silencedBy(ctx context.Context, labels Labels, now time.Time) int64 {
+	if m.repos.Silences == nil {
+		return 0
+	}
+	silences, err := m.repos.Silences.ListActive(ctx, now)
+	if err != nil {
+		log.Warn("获取静默列表失败", "err", err)
+		return 0
+	}
+	return silencedBy(silences, labels, now)
+}
+
+// matchRoutes 按优先级匹配路由，continue=false 的路由匹配后停止
+func (m *Manager) matchRoutes(ctx context.Context, labels Labels, enabled []*database.NotifyChannel) []matchedRoute {
+	if m.repos.Routes == nil {
+		return nil
+	}
+	routes, err := m.repos.Routes.List(ctx)
+	if err != nil {
+		log.Warn("获取告警路由失败", "err", err)
+		return nil
+	}
+
+	byID := make(map[int64]*database.NotifyChannel, len(enabled))
+	for _, ch := range enabled {
+		byID[ch.ID] = ch
+	}
+
+	var result []matchedRoute
+	for _, r := range routes {
+		if !r.Enabled {
+			continue
+		}
+		matchers, err := ParseMatchers(r.Matchers)
+		if err != nil {
+			log.Warn("路由匹配器格式错误，已跳过", "route", r.Name)
+			continue
+		}
+		if !matchers.Matches(labels) {
+			continue
+		}
+
+		ids, _ := parseIDList(r.ChannelIDs)
+		groupBy, _ := parseStringList(r.GroupBy)
+		mr := matchedRoute{route: r, groupBy: groupBy}
+		for _, id := range ids {
+			if ch, ok := byID[id]; ok {
+				mr.channels = append(mr.channels, ch)
+			}
+		}
+		result = append(result, mr)
+		if !r.Continue {
+			break
+		}
+	}
+	return result
+}
+
+// deliver 渲染并并发发送到各渠道（多条告警合并为一条消息）
+func (m *Manager) deliver(ctx context.Context, channels []*database.NotifyChannel, alerts []pendingAlert) {
 	var wg sync.WaitGroup
 	for _, ch := range channels {
 		wg.Add(1)
@@ -73,38 +330,42 @@ func (m *Manager) SendWithTemplate(templateName string, data *template.AlertData
 			defer wg.Done()
 
 			// 渲染模板
-			msg, err := m.renderer.Render(templateName, ch.Type, data)
-			if err != nil {
-				log.Error("渲染模板失败", "channel", ch.Type, "err", err)
-				return
+			msgs := make([]*channel.Message, 0, len(alerts))
+			for _, a := range alerts {
+				msg, err := m.renderer.Render(a.templateName, ch.Type, a.data)
+				if err != nil {
+					log.Error("渲染模板失败", "channel", ch.Type, "err", err)
+					continue
+				}
+				annotate(msg, a.templateName, a.data)
+				msgs = append(msgs, msg)
 			}
-
-			// 创建 notifier
-			notifier, err := m.factory.Create(ch)
-			if err != nil {
-				log.Error("创建通知器失败", "channel", ch.Type, "err", err)
-				return
-			}
-
-			// 发送
-			if err := notifier.Send(ctx, msg); err != nil {
-				log.Error("发送失败", "channel", ch.Type, "err", err)
+			if len(msgs) == 0 {
 				return
 			}
 
-			log.Info("发送成功", "channel", ch.Type, "title", data.Title)
+			// 发送（记录投递，失败由 retryLoop 重试）
+			m.dispatch(ctx, ch, alerts[0].templateName, combineMessages(msgs))
 		}(ch)
 	}
 	wg.Wait()
+}
 
-	return nil
+// sendToChannel 创建通知器并发送
+func (m *Manager) sendToChannel(ctx context.Context, ch *database.NotifyChannel, msg *channel.Message) error {
+	notifier, err := m.factory.Create(ch)
+	if err != nil {
+		return err
+	}
+	return notifier.Send(ctx, msg)
 }
 
 // Test 测试指定渠道
-func (m *Manager) Test(ctx context.Context, channelType string) error {
+// 按渠道 ID 查找：同一类型可配置多个渠道，按类型查找只能测到第一个
+func (m *Manager) Test(ctx context.Context, channelID int64) error {
 	// 获取渠道配置
-	ch, err := m.notifyRepo.GetByType(ctx, channelType)
-	if err != nil {
+	ch, err := m.repos.Channels.GetByID(ctx, channelID)
+	if err != nil || ch == nil {
 		return ErrChannelNotFound
 	}
 
@@ -112,9 +373,8 @@ func (m *Manager) Test(ctx context.Context, channelType string) error {
 		return ErrChannelDisabled
 	}
 
-	// 创建 notifier
-	notifier, err := m.factory.Create(ch)
-	if err != nil {
+	// 校验渠道配置
+	if _, err := m.factory.Create(ch); err != nil {
 		return ErrInvalidConfig
 	}
 
@@ -129,10 +389,10 @@ func (m *Manager) Test(ctx context.Context, channelType string) error {
 		Reason:    "Test",
 	}
 
-	msg, err := m.renderer.Render("heartbeat_recovery", channelType, testData)
+	msg, err := m.renderer.Render("heartbeat_recovery", ch.Type, testData)
 	if err != nil {
 		return err
 	}
 
-	return notifier.Send(ctx, msg)
+	return m.send(ctx, ch, msg)
 }
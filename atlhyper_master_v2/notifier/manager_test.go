@@ -0,0 +1,352 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/notifier/channel"
+	"AtlHyper/atlhyper_master_v2/notifier/template"
+)
+
+type fakeChannelRepo struct {
+	database.NotifyChannelRepository
+	channels []*database.NotifyChannel
+}
+
+func (r *fakeChannelRepo) ListEnabled(ctx context.Context) ([]*database.NotifyChannel, error) {
+	return r.channels, nil
+}
+
+func (r *fakeChannelRepo) GetByID(ctx context.Context, id int64) (*database.NotifyChannel, error) {
+	for _, ch := range r.channels {
+		if ch.ID == id {
+			return ch, nil
+		}
+	}
+	return nil, nil
+}
+
+type fakeRouteRepo struct {
+	database.NotifyRouteRepository
+	routes []*database.NotifyRoute
+}
+
+func (r *fakeRouteRepo) List(ctx context.Context) ([]*database.NotifyRoute, error) {
+	return r.routes, nil
+}
+
+type fakeSilenceRepo struct {
+	database.NotifySilenceRepository
+	silences []*database.NotifySilence
+}
+
+func (r *fakeSilenceRepo) ListActive(ctx context.Context, now time.Time) ([]*database.NotifySilence, error) {
+	return r.silences, nil
+}
+
+type fakeInhibitRepo struct {
+	database.NotifyInhibitRuleRepository
+	rules []*database.NotifyInhibitRule
+}
+
+func (r *fakeInhibitRepo) List(ctx context.Context) ([]*database.NotifyInhibitRule, error) {
+	return r.rules, nil
+}
+
+type sentMessage struct {
+	channelID int64
+	msg       *channel.Message
+}
+
+// newTestManager 创建测试用管理器（记录发送而不真正发送）
+func newTestManager(t *testing.T, repos ManagerRepos) (*Manager, func() []sentMessage) {
+	t.Helper()
+	m, err := NewManager(repos)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	var mu sync.Mutex
+	var sent []sentMessage
+	m.send = func(ctx context.Context, ch *database.NotifyChannel, msg *channel.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, sentMessage{channelID: ch.ID, msg: msg})
+		return nil
+	}
+	return m, func() []sentMessage {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]sentMessage(nil), sent...)
+	}
+}
+
+func testChannels() *fakeChannelRepo {
+	return &fakeChannelRepo{channels: []*database.NotifyChannel{
+		{ID: 1, Type: "slack", Name: "ops", Enabled: true},
+		{ID: 2, Type: "slack", Name: "dev", Enabled: true},
+		{ID: 3, Type: "email", Name: "oncall", Enabled: true},
+	}}
+}
+
+func k8sEvent(cluster, namespace, severity string) *template.AlertData {
+	return &template.AlertData{
+		Title:     "[" + cluster + "] BackOff",
+		Message:   "Back-off restarting failed container",
+		Severity:  severity,
+		Source:    string(SourceK8sEvent),
+		ClusterID: cluster,
+		Namespace: namespace,
+		Resource:  "Pod/" + namespace + "/api",
+		Reason:    "BackOff",
+		Timestamp: time.Now(),
+	}
+}
+
+func heartbeat(cluster string) *template.AlertData {
+	return &template.AlertData{
+		Title:     "Agent 离线",
+		Severity:  "critical",
+		Source:    string(SourceAgentHeartbeat),
+		ClusterID: cluster,
+		Resource:  "Agent/" + cluster,
+		Timestamp: time.Now(),
+	}
+}
+
+func sentChannels(sent []sentMessage) map[int64]int {
+	result := make(map[int64]int)
+	for _, s := range sent {
+		result[s.channelID]++
+	}
+	return result
+}
+
+func TestMatchers(t *testing.T) {
+	labels := Labels{"cluster": "prod", "severity": "critical", "namespace": ""}
+	cases := []struct {
+		matchers Matchers
+		want     bool
+	}{
+		{Matchers{}, true},
+		{Matchers{"cluster": "prod"}, true},
+		{Matchers{"cluster": "staging"}, false},
+		{Matchers{"severity": "warning, critical"}, true},
+		{Matchers{"cluster": "prod", "severity": "warning"}, false},
+		{Matchers{"namespace": "default"}, false},
+	}
+	for _, c := range cases {
+		if got := c.matchers.Matches(labels); got != c.want {
+			t.Errorf("%v.Matches = %v, want %v", c.matchers, got, c.want)
+		}
+	}
+}
+
+func TestManager_RoutingByPriorityAndContinue(t *testing.T) {
+	routes := &fakeRouteRepo{routes: []*database.NotifyRoute{
+		{ID: 1, Name: "prod-critical", Enabled: true, Matchers: `{"cluster":"prod","severity":"critical"}`, ChannelIDs: `[3]`, Continue: true},
+		{ID: 2, Name: "prod", Enabled: true, Matchers: `{"cluster":"prod"}`, ChannelIDs: `[1]`},
+		{ID: 3, Name: "catch-all", Enabled: true, Matchers: `{}`, ChannelIDs: `[2]`},
+	}}
+	m, sent := newTestManager(t, ManagerRepos{Channels: testChannels(), Routes: routes})
+
+	m.SendWithTemplate("k8s_event", k8sEvent("prod", "default", "critical"))
+	got := sentChannels(sent())
+	if got[3] != 1 || got[1] != 1 || got[2] != 0 {
+		t.Errorf("prod critical sent = %v, want email + ops", got)
+	}
+
+	m.SendWithTemplate("k8s_event", k8sEvent("staging", "default", "warning"))
+	got = sentChannels(sent())
+	if got[2] != 1 {
+		t.Errorf("staging sent = %v, want catch-all dev", got)
+	}
+}
+
+func TestManager_NoRouteFallsBackToAllChannels(t *testing.T) {
+	routes := &fakeRouteRepo{routes: []*database.NotifyRoute{
+		{ID: 1, Name: "prod", Enabled: true, Matchers: `{"cluster":"prod"}`, ChannelIDs: `[1]`},
+		{ID: 2, Name: "disabled", Enabled: false, Matchers: `{}`, ChannelIDs: `[2]`},
+	}}
+	m, sent := newTestManager(t, ManagerRepos{Channels: testChannels(), Routes: routes})
+
+	m.SendWithTemplate("k8s_event", k8sEvent("staging", "default", "warning"))
+	if got := len(sent()); got != 3 {
+		t.Errorf("sent = %d, want 3 (all enabled channels)", got)
+	}
+}
+
+func TestManager_Silence(t *testing.T) {
+	now := time.Now()
+	silences := &fakeSilenceRepo{silences: []*database.NotifySilence{
+		{ID: 7, Matchers: `{"cluster":"prod","namespace":"batch"}`, StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)},
+		{ID: 8, Matchers: `{}`, StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)},
+	}}
+	m, sent := newTestManager(t, ManagerRepos{Channels: testChannels(), Silences: silences})
+
+	m.SendWithTemplate("k8s_event", k8sEvent("prod", "batch", "warning"))
+	if got := len(sent()); got != 0 {
+		t.Fatalf("silenced alert sent to %d channels", got)
+	}
+
+	m.SendWithTemplate("k8s_event", k8sEvent("prod", "default", "warning"))
+	if got := len(sent()); got != 3 {
+		t.Errorf("unsilenced alert sent = %d, want 3", got)
+	}
+}
+
+func TestManager_InhibitHeartbeatOffline(t *testing.T) {
+	inhibits := &fakeInhibitRepo{rules: []*database.NotifyInhibitRule{{
+		Name:           "heartbeat-offline-inhibits-k8s-event",
+		Enabled:        true,
+		SourceMatchers: `{"alertname":"heartbeat_offline"}`,
+		TargetMatchers: `{"source":"k8s_event"}`,
+		Equal:          `["cluster"]`,
+	}}}
+	channels := &fakeChannelRepo{channels: []*database.NotifyChannel{{ID: 1, Type: "slack", Enabled: true}}}
+	m, sent := newTestManager(t, ManagerRepos{Channels: channels, Inhibits: inhibits})
+
+	m.SendWithTemplate("heartbeat_offline", heartbeat("prod"))
+	m.SendWithTemplate("k8s_event", k8sEvent("prod", "default", "warning"))
+	m.SendWithTemplate("k8s_event", k8sEvent("staging", "default", "warning"))
+	if got := len(sent()); got != 2 {
+		t.Fatalf("sent = %d, want 2 (offline + staging event)", got)
+	}
+
+	// 恢复后不再抑制
+	m.SendWithTemplate("heartbeat_recovery", heartbeat("prod"))
+	m.SendWithTemplate("k8s_event", k8sEvent("prod", "default", "warning"))
+	if got := len(sent()); got != 4 {
+		t.Errorf("sent = %d after recovery, want 4", got)
+	}
+}
+
+func TestManager_ResolvedAlertSharesDedupKey(t *testing.T) {
+	channels := &fakeChannelRepo{channels: []*database.NotifyChannel{{ID: 1, Type: "pagerduty", Enabled: true}}}
+	m, sent := newTestManager(t, ManagerRepos{Channels: channels})
+
+	m.SendWithTemplate("heartbeat_offline", heartbeat("prod"))
+	m.SendWithTemplate("heartbeat_recovery", heartbeat("prod"))
+	m.SendWithTemplate("heartbeat_offline", heartbeat("staging"))
+	msgs := sent()
+	if len(msgs) != 3 {
+		t.Fatalf("sent = %d, want 3", len(msgs))
+	}
+	firing, resolved, other := msgs[0].msg, msgs[1].msg, msgs[2].msg
+	if firing.Status != "firing" || resolved.Status != "resolved" {
+		t.Errorf("status = %q/%q, want firing/resolved", firing.Status, resolved.Status)
+	}
+	if firing.DedupKey == "" || firing.DedupKey != resolved.DedupKey {
+		t.Errorf("dedup key mismatch: %q vs %q", firing.DedupKey, resolved.DedupKey)
+	}
+	if other.DedupKey == firing.DedupKey {
+		t.Errorf("different clusters share dedup key %q", other.DedupKey)
+	}
+	if firing.Labels["cluster"] != "prod" {
+		t.Errorf("labels = %v, want cluster=prod", firing.Labels)
+	}
+}
+
+func TestManager_GroupingBatchesAlerts(t *testing.T) {
+	routes := &fakeRouteRepo{routes: []*database.NotifyRoute{
+		{ID: 1, Name: "grouped", Enabled: true, Matchers: `{}`, ChannelIDs: `[1]`, GroupBy: `["cluster"]`, GroupWaitS: 3600, GroupIntervalS: 3600},
+	}}
+	m, sent := newTestManager(t, ManagerRepos{Channels: testChannels(), Routes: routes})
+
+	m.SendWithTemplate("k8s_event", k8sEvent("prod", "a", "warning"))
+	m.SendWithTemplate("k8s_event", k8sEvent("prod", "b", "warning"))
+	m.SendWithTemplate("k8s_event", k8sEvent("staging", "a", "warning"))
+	if got := len(sent()); got != 0 {
+		t.Fatalf("sent = %d before group_wait, want 0", got)
+	}
+
+	// Stop 立即发送待发送分组
+	m.Stop()
+	msgs := sent()
+	if len(msgs) != 2 {
+		t.Fatalf("sent = %d after Stop, want 2 groups", len(msgs))
+	}
+	var batched *channel.Message
+	for _, s := range msgs {
+		if strings.HasPrefix(s.msg.Subject, "[2 条告警]") {
+			batched = s.msg
+		}
+	}
+	if batched == nil || strings.Count(batched.Body, batchSeparator) != 1 {
+		t.Errorf("expected one batched message of 2 alerts, got %+v", msgs)
+	}
+}
+
+func TestManager_TestByChannelID(t *testing.T) {
+	channels := &fakeChannelRepo{channels: []*database.NotifyChannel{
+		{ID: 1, Type: "slack", Name: "ops", Enabled: true, Config: `{"webhookUrl":"https://hooks.example.com/ops"}`},
+		{ID: 2, Type: "slack", Name: "dev", Enabled: true, Config: `{"webhookUrl":"https://hooks.example.com/dev"}`},
+		{ID: 3, Type: "slack", Name: "old", Enabled: false, Config: `{"webhookUrl":"https://hooks.example.com/old"}`},
+	}}
+	m, sent := newTestManager(t, ManagerRepos{Channels: channels})
+
+	// 同类型的第二个渠道按 ID 命中，而不是该类型的第一个渠道
+	if err := m.Test(context.Background(), 2); err != nil {
+		t.Fatalf("Test(2): %v", err)
+	}
+	if got := sentChannels(sent()); len(got) != 1 || got[2] != 1 {
+		t.Errorf("sent = %v, want only channel 2", got)
+	}
+
+	if err := m.Test(context.Background(), 3); !errors.Is(err, ErrChannelDisabled) {
+		t.Errorf("Test(3) = %v, want ErrChannelDisabled", err)
+	}
+	if err := m.Test(context.Background(), 9); !errors.Is(err, ErrChannelNotFound) {
+		t.Errorf("Test(9) = %v, want ErrChannelNotFound", err)
+	}
+}
+
+func TestGrouper_IntervalAfterFirstFlush(t *testing.T) {
+	var mu sync.Mutex
+	var flushed [][]pendingAlert
+	g := newGrouper(func(channels []*database.NotifyChannel, alerts []pendingAlert) {
+		mu.Lock()
+		flushed = append(flushed, alerts)
+		mu.Unlock()
+	})
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(flushed)
+	}
+
+	alert := pendingAlert{templateName: "k8s_event", data: k8sEvent("prod", "a", "warning")}
+	g.add("k", nil, 10*time.Millisecond, 50*time.Millisecond, alert, time.Now())
+	waitFor(t, func() bool { return count() == 1 })
+
+	// 首次发送后新告警按 group_interval 发送
+	g.add("k", nil, 10*time.Millisecond, 50*time.Millisecond, alert, time.Now())
+	g.add("k", nil, 10*time.Millisecond, 50*time.Millisecond, alert, time.Now())
+	time.Sleep(20 * time.Millisecond)
+	if got := count(); got != 1 {
+		t.Fatalf("flushed = %d before group_interval, want 1", got)
+	}
+	waitFor(t, func() bool { return count() == 2 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(flushed[1]) != 2 {
+		t.Errorf("second flush = %d alerts, want 2", len(flushed[1]))
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("condition not met in time")
+}
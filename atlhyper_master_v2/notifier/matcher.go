@@ -0,0 +1,105 @@
+// atlhyper_master_v2/notifier/matcher.go
+// 告警标签与匹配器
+//
+// 路由、静默、抑制规则统一使用标签匹配：
+//   - 标签: alertname（模板名）、cluster、namespace、severity、source、resource、reason
+//   - 匹配器: {"label": "value"}，value 可用逗号分隔多个候选值（任一相等即匹配）
+//   - 空匹配器匹配所有告警
+package notifier
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"AtlHyper/atlhyper_master_v2/notifier/template"
+)
+
+// Labels 告警标签
+type Labels map[string]string
+
+// Matchers 标签匹配器
+type Matchers map[string]string
+
+// AlertLabels 从模板名与告警数据提取标签
+func AlertLabels(templateName string, data *template.AlertData) Labels {
+	return Labels{
+		"alertname": templateName,
+		"cluster":   data.ClusterID,
+		"namespace": data.Namespace,
+		"severity":  data.Severity,
+		"source":    data.Source,
+		"resource":  data.Resource,
+		"reason":    data.Reason,
+	}
+}
+
+// ParseMatchers 解析 JSON 匹配器（空字符串视为匹配所有）
+func ParseMatchers(raw string) (Matchers, error) {
+	m := Matchers{}
+	if strings.TrimSpace(raw) == "" {
+		return m, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Matches 判断标签是否满足所有匹配条件
+func (m Matchers) Matches(labels Labels) bool {
+	for name, want := range m {
+		got := labels[name]
+		matched := false
+		for _, candidate := range strings.Split(want, ",") {
+			if strings.TrimSpace(candidate) == got {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// parseStringList 解析 JSON 字符串数组（空字符串视为空数组）
+func parseStringList(raw string) ([]string, error) {
+	var list []string
+	if strings.TrimSpace(raw) == "" {
+		return list, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// parseIDList 解析 JSON ID 数组（空字符串视为空数组）
+func parseIDList(raw string) ([]int64, error) {
+	var list []int64
+	if strings.TrimSpace(raw) == "" {
+		return list, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// groupKey 分组键：路由 ID + 分组标签值
+func groupKey(routeID int64, groupBy []string, labels Labels) string {
+	names := append([]string(nil), groupBy...)
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(strconv.FormatInt(routeID, 10))
+	for _, name := range names {
+		b.WriteString("|")
+		b.WriteString(name)
+		b.WriteString("=")
+		b.WriteString(labels[name])
+	}
+	return b.String()
+}
@@ -0,0 +1,118 @@
+// atlhyper_master_v2/notifier/oncall.go
+// 值班轮换：根据值班表与替班计算某一时刻的主 / 副值班
+//
+// 轮换规则:
+//   - 从 StartAt 起每个周期（daily 24h / weekly 7d）交接一次，按 Members 顺序轮换
+//   - StartAt 之前视为第一位成员值班
+//   - 生效中的替班（最近开始的一条）替换主值班；副值班为轮换中的下一位
+package notifier
+
+import (
+	"fmt"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// OncallShift 某一时刻的值班结果
+type OncallShift struct {
+	Primary    int64     // 主值班用户 ID（0 表示无成员）
+	Secondary  int64     // 副值班用户 ID（0 表示无）
+	Scheduled  int64     // 轮换排定的主值班（替班生效时与 Primary 不同）
+	OverrideID int64     // 生效中的替班 ID（0 表示无）
+	StartsAt   time.Time // 当前轮换班次开始时间
+	EndsAt     time.Time // 当前轮换班次结束时间（下次交接）
+	Members    []int64   // 值班表全部成员（轮换顺序）
+}
+
+// RotationPeriod 轮换周期
+func RotationPeriod(rotation string) (time.Duration, error) {
+	switch rotation {
+	case database.RotationDaily:
+		return 24 * time.Hour, nil
+	case database.RotationWeekly:
+		return 7 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown rotation: %s", rotation)
+	}
+}
+
+// ResolveOncall 计算 at 时刻的值班（overrides 为该值班表的替班，可包含未生效的记录）
+func ResolveOncall(s *database.OncallSchedule, overrides []*database.OncallOverride, at time.Time) (*OncallShift, error) {
+	period, err := RotationPeriod(s.Rotation)
+	if err != nil {
+		return nil, err
+	}
+	members, err := parseIDList(s.Members)
+	if err != nil {
+		return nil, fmt.Errorf("invalid members: %w", err)
+	}
+
+	shift := &OncallShift{Members: members}
+	idx := 0
+	if at.Before(s.StartAt) {
+		shift.StartsAt = s.StartAt.Add(-period)
+	} else {
+		n := int64(at.Sub(s.StartAt) / period)
+		shift.StartsAt = s.StartAt.Add(time.Duration(n) * period)
+		if len(members) > 0 {
+			idx = int(n % int64(len(members)))
+		}
+	}
+	shift.EndsAt = shift.StartsAt.Add(period)
+
+	if len(members) > 0 {
+		shift.Scheduled = members[idx]
+		shift.Primary = shift.Scheduled
+		if len(members) > 1 {
+			shift.Secondary = members[(idx+1)%len(members)]
+		}
+	}
+
+	// 替班：同一时刻多条生效时以最近开始的为准
+	var active *database.OncallOverride
+	for _, o := range overrides {
+		if o.ScheduleID != s.ID || at.Before(o.StartsAt) || !at.Before(o.EndsAt) {
+			continue
+		}
+		if active == nil || o.StartsAt.After(active.StartsAt) {
+			active = o
+		}
+	}
+	if active != nil {
+		shift.Primary = active.UserID
+		shift.OverrideID = active.ID
+		// 替班人恰好是副值班时，由原排定的主值班兜底
+		if shift.Secondary == active.UserID || shift.Secondary == 0 {
+			shift.Secondary = shift.Scheduled
+		}
+		if shift.Secondary == shift.Primary {
+			shift.Secondary = 0
+		}
+	}
+	return shift, nil
+}
+
+// Targets 升级目标对应的用户 ID（去重，保持顺序）
+func (s *OncallShift) Targets(target string) []int64 {
+	var ids []int64
+	switch target {
+	case database.EscalatePrimary:
+		ids = []int64{s.Primary}
+	case database.EscalateSecondary:
+		ids = []int64{s.Secondary}
+	case database.EscalateAll:
+		ids = append([]int64{s.Primary}, s.Members...)
+	}
+
+	seen := make(map[int64]bool, len(ids))
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
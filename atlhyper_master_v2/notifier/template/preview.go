@@ -0,0 +1,158 @@
+// atlhyper_master_v2/notifier/template/preview.go
+// 自定义模板校验与预览
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/notifier/enrich"
+)
+
+// ErrInvalidTemplate 自定义模板未知、解析失败或试渲染失败
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// IsKnown 模板名称与变体是否存在
+func IsKnown(name, variant string) bool {
+	return contains(TemplateNames, name) && contains(TemplateVariants, variant)
+}
+
+// Validate 校验自定义模板：解析后使用示例数据试渲染
+// 引用不存在的字段、语法错误都会在保存前被拒绝
+func Validate(name, variant, body string) error {
+	_, err := Preview(name, variant, body, nil)
+	return err
+}
+
+// Preview 使用给定数据渲染模板源码（data 为 nil 时使用 SampleData）
+func Preview(name, variant, body string, data *AlertData) (string, error) {
+	if !IsKnown(name, variant) {
+		return "", fmt.Errorf("%w: unknown template %s_%s", ErrInvalidTemplate, name, variant)
+	}
+	key := templateKey(name, variant)
+	tmpl, err := parse(key, variant, body)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	if data == nil {
+		data = SampleData(name)
+	}
+	prepare(data)
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return buf.String(), nil
+}
+
+// DefaultSource 内置模板源码
+func DefaultSource(name, variant string) (string, bool) {
+	if !IsKnown(name, variant) {
+		return "", false
+	}
+	src, err := templateFS.ReadFile("templates/" + templateKey(name, variant) + ".tmpl")
+	if err != nil {
+		return "", false
+	}
+	return string(src), true
+}
+
+// SampleData 模板示例数据（各模板触发器实际填充的字段，含 Enriched 丰富数据）
+func SampleData(name string) *AlertData {
+	data := &AlertData{
+		Title:     "[prod] 示例告警",
+		Message:   "这是一条用于预览模板的示例告警。",
+		Severity:  "critical",
+		ClusterID: "prod",
+		Timestamp: time.Now(),
+		Fields:    map[string]string{},
+	}
+
+	// 触发类告警匹配升级策略时附带值班确认链接
+	if !strings.HasSuffix(name, "_resolved") && !strings.HasSuffix(name, "_recovery") {
+		data.AckURL = "https://atlhyper.example.com/api/v2/oncall/ack?token=sample"
+	}
+
+	switch name {
+	case "heartbeat_offline", "heartbeat_recovery":
+		data.Title = "[prod] Agent 离线"
+		data.Source = "agent_heartbeat"
+		data.Resource = "Agent/prod"
+		data.Fields["last_heartbeat"] = time.Now().Add(-5 * time.Minute).Format("2006-01-02 15:04:05")
+		data.Fields["offline_after"] = "3m0s"
+		if name == "heartbeat_recovery" {
+			data.Title = "[prod] Agent 恢复"
+			data.Severity = "info"
+			data.Fields["downtime"] = "5m12s"
+		}
+
+	case "k8s_event":
+		data.Title = "[prod] BackOff: Pod/default/api-7d9f8c6b5-x2k4p"
+		data.Message = "Back-off restarting failed container api in pod api-7d9f8c6b5-x2k4p"
+		data.Severity = "warning"
+		data.Source = "k8s_event"
+		data.Namespace = "default"
+		data.Resource = "Pod/default/api-7d9f8c6b5-x2k4p"
+		data.Reason = "BackOff"
+		data.Fields["count"] = "12"
+		data.Enriched = &enrich.EnrichedData{
+			Pod:        &enrich.PodInfo{Phase: "Running", Restarts: 12, Ready: "0/1", NodeName: "node-1"},
+			Node:       &enrich.NodeInfo{Ready: true, Conditions: "MemoryPressure=False, DiskPressure=False"},
+			Deployment: &enrich.DeploymentInfo{Name: "api", Namespace: "default", Replicas: "2/3"},
+		}
+
+	case "aiops_incident", "aiops_incident_resolved":
+		data.Title = "[prod] AIOps 事件: default/api"
+		data.Source = "aiops"
+		data.Namespace = "default"
+		data.Resource = "service/default/api"
+		data.Incident = &IncidentData{
+			ID:          "inc-20250120-001",
+			State:       "incident",
+			RootCause:   "service/default/api",
+			PeakRisk:    "87.5",
+			CurrentRisk: "72.0",
+			Duration:    "12m",
+			Recurrence:  1,
+			CausalChain: []string{"pod/default/api-7d9f8c6b5-x2k4p", "service/default/api", "ingress/default/web"},
+			AISummary:   "api 服务错误率上升，与最近一次部署时间吻合。",
+			URL:         "https://atlhyper.example.com/aiops/incidents/inc-20250120-001",
+		}
+		if name == "aiops_incident_resolved" {
+			data.Severity = "info"
+			data.Incident.State = "recovery"
+			data.Incident.CurrentRisk = ""
+		}
+
+	case "slo_burn_rate", "slo_burn_rate_resolved":
+		// 规则取值与 slo.DefaultBurnRateRules 中的 fast 规则一致，字段格式与 SLO 燃烧率触发器一致
+		data.Title = "[prod] SLO 燃烧率告警: default/api"
+		data.Source = "slo"
+		data.Namespace = "default"
+		data.Resource = "default/api/fast"
+		data.Fields["rule"] = "fast"
+		data.Fields["windows"] = "1h / 5m"
+		data.Fields["long_burn_rate"] = "15.2x"
+		data.Fields["short_burn_rate"] = "16.8x"
+		data.Fields["threshold"] = "14.4x"
+		data.Fields["target"] = "99.90% (30d)"
+		data.Fields["firing_since"] = time.Now().Add(-20 * time.Minute).Format("2006-01-02 15:04:05")
+		if name == "slo_burn_rate_resolved" {
+			data.Severity = "info"
+		}
+	}
+	return data
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
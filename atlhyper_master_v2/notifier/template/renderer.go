@@ -6,6 +6,10 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -24,6 +28,7 @@ type AlertData struct {
 	Severity  string
 	Source    string
 	ClusterID string
+	Namespace string // 命名空间（集群级告警为空）
 	Resource  string
 	Reason    string
 	Timestamp time.Time
@@ -35,82 +40,201 @@ type AlertData struct {
 	// 丰富数据
 	Enriched *enrich.EnrichedData
 
+	// AIOps 事件数据（仅 aiops_incident_* 模板）
+	Incident *IncidentData
+
+	// 值班确认链接（告警匹配升级策略时填充）
+	AckURL string
+
 	// 渠道特定
 	SeverityEmoji string
 }
 
+// IncidentData AIOps 事件模板数据
+type IncidentData struct {
+	ID          string
+	State       string   // warning / incident / recovery / stable
+	RootCause   string   // 根因实体
+	PeakRisk    string   // 峰值风险（已格式化）
+	CurrentRisk string   // 转换时风险（已格式化，关闭时为空）
+	Duration    string   // 持续时间
+	Recurrence  int      // 复发次数
+	CausalChain []string // 因果链（按时间排序）
+	AISummary   string   // AI 分析报告摘要（可为空）
+	URL         string   // 事件详情链接
+}
+
+// TemplateNames 所有告警模板名称
+var TemplateNames = []string{
+	"heartbeat_offline",
+	"heartbeat_recovery",
+	"k8s_event",
+	"aiops_incident",
+	"aiops_incident_resolved",
+	"slo_burn_rate",
+	"slo_burn_rate_resolved",
+}
+
+// TemplateVariants 模板变体（文件名后缀）
+//   - slack: Slack mrkdwn
+//   - email: 纯文本（Email 纯文本部分、Webhook、PagerDuty 共用）
+//   - markdown: 标准 Markdown（Teams、钉钉、飞书共用）
+//   - html: HTML（Email HTML 部分，使用 html/template 自动转义）
+var TemplateVariants = []string{"slack", "email", "markdown", "html"}
+
+// channelVariants 渠道类型 → 模板变体与消息格式
+var channelVariants = map[string]struct{ variant, format string }{
+	"slack":     {"slack", "markdown"},
+	"email":     {"email", "text"},
+	"webhook":   {"email", "text"},
+	"pagerduty": {"email", "text"},
+	"teams":     {"markdown", "markdown"},
+	"dingtalk":  {"markdown", "markdown"},
+	"feishu":    {"markdown", "markdown"},
+}
+
+// executor text/template 与 html/template 共同的执行接口
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
 // Renderer 模板渲染器
+// 内置模板来自嵌入的 .tmpl 文件，数据库中的自定义模板（覆盖）优先
 type Renderer struct {
-	templates map[string]*template.Template
+	defaults map[string]executor // name_variant → 内置模板
+
+	mu        sync.RWMutex
+	overrides map[string]executor // name_variant → 自定义模板
 }
 
 // NewRenderer 创建渲染器
 func NewRenderer() (*Renderer, error) {
 	r := &Renderer{
-		templates: make(map[string]*template.Template),
+		defaults:  make(map[string]executor),
+		overrides: make(map[string]executor),
 	}
 
-	// 加载所有模板
-	templateNames := []string{
-		"heartbeat_offline",
-		"heartbeat_recovery",
-		"k8s_event",
+	// 加载所有内置模板
+	for _, name := range TemplateNames {
+		for _, variant := range TemplateVariants {
+			key := templateKey(name, variant)
+			src, err := templateFS.ReadFile("templates/" + key + ".tmpl")
+			if err != nil {
+				return nil, fmt.Errorf("read %s.tmpl: %w", key, err)
+			}
+			tmpl, err := parse(key, variant, string(src))
+			if err != nil {
+				return nil, fmt.Errorf("parse %s.tmpl: %w", key, err)
+			}
+			r.defaults[key] = tmpl
+		}
 	}
 
-	for _, name := range templateNames {
-		// Slack 模板
-		slackTmpl, err := template.ParseFS(templateFS, fmt.Sprintf("templates/%s_slack.tmpl", name))
-		if err != nil {
-			return nil, fmt.Errorf("parse %s_slack.tmpl: %w", name, err)
-		}
-		r.templates[name+"_slack"] = slackTmpl
+	return r, nil
+}
 
-		// Email 模板
-		emailTmpl, err := template.ParseFS(templateFS, fmt.Sprintf("templates/%s_email.tmpl", name))
+// SetOverrides 替换全部自定义模板（key 为 name_variant）
+// 解析失败的模板被跳过（继续使用内置模板），返回各自的错误
+func (r *Renderer) SetOverrides(bodies map[string]string) map[string]error {
+	overrides := make(map[string]executor, len(bodies))
+	errs := make(map[string]error)
+	for key, body := range bodies {
+		variant := key[strings.LastIndex(key, "_")+1:]
+		if _, ok := r.defaults[key]; !ok {
+			errs[key] = fmt.Errorf("unknown template: %s", key)
+			continue
+		}
+		tmpl, err := parse(key, variant, body)
 		if err != nil {
-			return nil, fmt.Errorf("parse %s_email.tmpl: %w", name, err)
+			errs[key] = err
+			continue
 		}
-		r.templates[name+"_email"] = emailTmpl
+		overrides[key] = tmpl
 	}
 
-	return r, nil
+	r.mu.Lock()
+	r.overrides = overrides
+	r.mu.Unlock()
+	return errs
 }
 
 // Render 渲染告警消息
-// templateName: heartbeat_offline, heartbeat_recovery, k8s_event
-// channelType: slack, email
+// templateName: heartbeat_offline, heartbeat_recovery, k8s_event, aiops_incident, aiops_incident_resolved, slo_burn_rate, slo_burn_rate_resolved
+// channelType: slack, email, webhook, pagerduty, teams, dingtalk, feishu
+// Email 额外渲染 HTML 部分（纯文本作为 multipart/alternative 备选）
 func (r *Renderer) Render(templateName, channelType string, data *AlertData) (*channel.Message, error) {
 	// 补充数据
-	data.TimeStr = data.Timestamp.Format("2006-01-02 15:04:05 MST")
-	data.SeverityEmoji = severityEmoji(data.Severity)
+	prepare(data)
 
 	// 查找模板
-	key := templateName + "_" + channelType
-	tmpl, ok := r.templates[key]
+	cv, ok := channelVariants[channelType]
 	if !ok {
-		return nil, fmt.Errorf("template not found: %s", key)
+		return nil, fmt.Errorf("unsupported channel type: %s", channelType)
 	}
-
-	// 渲染
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return nil, fmt.Errorf("execute template: %w", err)
+	body, err := r.execute(templateKey(templateName, cv.variant), data)
+	if err != nil {
+		return nil, err
 	}
 
 	// 构建消息
 	msg := &channel.Message{
-		Subject: data.Title,
-		Body:    buf.String(),
-		Format:  "markdown",
+		Subject:  data.Title,
+		Body:     body,
+		Format:   cv.format,
+		Severity: data.Severity,
 	}
-
 	if channelType == "email" {
-		msg.Format = "text"
+		if html, err := r.execute(templateKey(templateName, "html"), data); err == nil {
+			msg.HTML = html
+		}
 	}
-
 	return msg, nil
 }
 
+// execute 执行模板：优先自定义模板，执行失败时回退到内置模板
+func (r *Renderer) execute(key string, data *AlertData) (string, error) {
+	tmpl, ok := r.defaults[key]
+	if !ok {
+		return "", fmt.Errorf("template not found: %s", key)
+	}
+
+	r.mu.RLock()
+	override := r.overrides[key]
+	r.mu.RUnlock()
+
+	var buf bytes.Buffer
+	if override != nil {
+		if err := override.Execute(&buf, data); err == nil {
+			return buf.String(), nil
+		}
+		// 自定义模板可能引用了该告警没有的数据，回退到内置模板
+		buf.Reset()
+	}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// templateKey 模板键
+func templateKey(name, variant string) string {
+	return name + "_" + variant
+}
+
+// parse 解析模板（html 变体使用 html/template）
+func parse(key, variant, body string) (executor, error) {
+	if variant == "html" {
+		return htmltemplate.New(key).Parse(body)
+	}
+	return template.New(key).Parse(body)
+}
+
+// prepare 补充派生字段
+func prepare(data *AlertData) {
+	data.TimeStr = data.Timestamp.Format("2006-01-02 15:04:05 MST")
+	data.SeverityEmoji = severityEmoji(data.Severity)
+}
+
 // severityEmoji 获取级别对应的 emoji
 func severityEmoji(severity string) string {
 	switch severity {
@@ -0,0 +1,152 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"AtlHyper/atlhyper_master_v2/slo"
+)
+
+func TestRenderer_AllTemplatesAllChannels(t *testing.T) {
+	r, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+	for _, name := range TemplateNames {
+		for channelType := range channelVariants {
+			msg, err := r.Render(name, channelType, SampleData(name))
+			if err != nil {
+				t.Fatalf("Render(%s, %s): %v", name, channelType, err)
+			}
+			if strings.TrimSpace(msg.Body) == "" {
+				t.Errorf("Render(%s, %s): empty body", name, channelType)
+			}
+			if (channelType == "email") != (msg.HTML != "") {
+				t.Errorf("Render(%s, %s): HTML=%q", name, channelType, msg.HTML)
+			}
+		}
+	}
+}
+
+func TestRenderer_OverrideAndFallback(t *testing.T) {
+	r, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+
+	errs := r.SetOverrides(map[string]string{
+		"k8s_event_slack":        "custom {{.Reason}} runbook: https://runbooks.example.com/{{.Reason}}",
+		"k8s_event_markdown":     "{{.Incident.ID}}", // k8s_event 无 Incident，执行失败回退内置模板
+		"heartbeat_offline_html": "{{if}}",
+		"unknown_slack":          "x",
+	})
+	if len(errs) != 2 || errs["heartbeat_offline_html"] == nil || errs["unknown_slack"] == nil {
+		t.Fatalf("SetOverrides errs = %v", errs)
+	}
+
+	msg, err := r.Render("k8s_event", "slack", SampleData("k8s_event"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Body != "custom BackOff runbook: https://runbooks.example.com/BackOff" {
+		t.Errorf("override not used: %q", msg.Body)
+	}
+
+	msg, err = r.Render("k8s_event", "teams", SampleData("k8s_event"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(msg.Body, "BackOff") {
+		t.Errorf("expected fallback to default template, got %q", msg.Body)
+	}
+
+	// 清空覆盖后恢复内置模板
+	r.SetOverrides(nil)
+	msg, _ = r.Render("k8s_event", "slack", SampleData("k8s_event"))
+	if strings.HasPrefix(msg.Body, "custom") {
+		t.Errorf("override not cleared: %q", msg.Body)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name, variant, body string
+		ok                  bool
+	}{
+		{"k8s_event", "email", "{{.Title}} {{.Enriched.Pod.Restarts}}", true},
+		{"aiops_incident", "markdown", "{{range .Incident.CausalChain}}{{.}}{{end}}", true},
+		{"k8s_event", "email", "{{.Title", false},         // 语法错误
+		{"k8s_event", "email", "{{.NoSuchField}}", false}, // 字段不存在
+		{"k8s_event", "pdf", "{{.Title}}", false},
+		{"no_such_template", "email", "{{.Title}}", false},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.name, tt.variant, tt.body)
+		if tt.ok && err != nil {
+			t.Errorf("Validate(%s_%s, %q) = %v", tt.name, tt.variant, tt.body, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidTemplate) {
+			t.Errorf("Validate(%s_%s, %q) = %v, want ErrInvalidTemplate", tt.name, tt.variant, tt.body, err)
+		}
+	}
+}
+
+func TestPreview_HTMLEscapes(t *testing.T) {
+	data := SampleData("k8s_event")
+	data.Message = "<script>alert(1)</script>"
+
+	html, err := Preview("k8s_event", "html", "<p>{{.Message}}</p>", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html, "<script>") {
+		t.Errorf("html variant not escaped: %s", html)
+	}
+
+	text, err := Preview("k8s_event", "email", "{{.Message}}", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != data.Message {
+		t.Errorf("text variant = %q", text)
+	}
+}
+
+func TestDefaultSource(t *testing.T) {
+	src, ok := DefaultSource("slo_burn_rate", "html")
+	if !ok || !strings.Contains(src, "<table") {
+		t.Errorf("DefaultSource = %q, %v", src, ok)
+	}
+	if _, ok := DefaultSource("slo_burn_rate", "pdf"); ok {
+		t.Error("unknown variant should not have a default")
+	}
+}
+
+func TestSampleData_SLOBurnRateMatchesDefaultRule(t *testing.T) {
+	var fast slo.BurnRateRule
+	for _, r := range slo.DefaultBurnRateRules {
+		if r.Name == "fast" {
+			fast = r
+		}
+	}
+	if fast.Name == "" {
+		t.Fatal("slo.DefaultBurnRateRules has no fast rule")
+	}
+
+	data := SampleData("slo_burn_rate")
+	if data.Fields["rule"] != fast.Name || data.Resource != "default/api/"+fast.Name {
+		t.Errorf("rule = %q, resource = %q", data.Fields["rule"], data.Resource)
+	}
+	windows := fmt.Sprintf("%dh / %dm", int(fast.LongWindow.Hours()), int(fast.ShortWindow.Minutes()))
+	if data.Fields["windows"] != windows {
+		t.Errorf("windows = %q, want %q", data.Fields["windows"], windows)
+	}
+	if threshold := fmt.Sprintf("%.1fx", fast.Threshold); data.Fields["threshold"] != threshold {
+		t.Errorf("threshold = %q, want %q", data.Fields["threshold"], threshold)
+	}
+	if data.Severity != fast.Severity {
+		t.Errorf("severity = %q, want %q", data.Severity, fast.Severity)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/notifier"
 	"AtlHyper/atlhyper_master_v2/notifier/enrich"
@@ -25,6 +26,7 @@ type EventTrigger struct {
 	enricher   *enrich.Enricher
 	manager    notifier.AlertManager
 	config     EventConfig
+	windows    MaintenanceWindows // 可选，nil = 不按维护窗口过滤
 	lastSeenID int64
 
 	stopCh chan struct{}
@@ -48,6 +50,11 @@ func NewEventTrigger(
 	}
 }
 
+// SetMaintenance 设置维护窗口（窗口覆盖的对象的事件不发送告警），须在 Start 前调用
+func (t *EventTrigger) SetMaintenance(windows MaintenanceWindows) {
+	t.windows = windows
+}
+
 // Start 启动触发器
 func (t *EventTrigger) Start() error {
 	// 获取最新事件 ID
@@ -127,6 +134,22 @@ func (t *EventTrigger) triggerEvent(ctx context.Context, event *database.Cluster
 	// 丰富数据
 	enriched := t.enricher.EnrichByResource(ctx, event.ClusterID, event.InvolvedKind, event.InvolvedNamespace, event.InvolvedName)
 
+	// 维护窗口内的对象不告警（Pod 按所在节点匹配节点窗口）
+	target := maintenance.Target{
+		ClusterID: event.ClusterID,
+		Namespace: event.InvolvedNamespace,
+		Kind:      event.InvolvedKind,
+		Name:      event.InvolvedName,
+	}
+	if enriched != nil && enriched.Pod != nil {
+		target.Node = enriched.Pod.NodeName
+	}
+	if w := inMaintenance(t.windows, target, time.Now()); w != nil {
+		log.Debug("维护窗口内，跳过事件告警", "cluster", event.ClusterID, "reason", event.Reason,
+			"kind", event.InvolvedKind, "name", event.InvolvedName, "window", w.Name)
+		return nil
+	}
+
 	// 构建模板数据
 	data := &template.AlertData{
 		Title:     fmt.Sprintf("[%s] %s", event.ClusterID, event.Reason),
@@ -134,6 +157,7 @@ func (t *EventTrigger) triggerEvent(ctx context.Context, event *database.Cluster
 		Severity:  "warning",
 		Source:    "k8s_event",
 		ClusterID: event.ClusterID,
+		Namespace: event.InvolvedNamespace,
 		Resource:  fmt.Sprintf("%s/%s/%s", event.InvolvedKind, event.InvolvedNamespace, event.InvolvedName),
 		Reason:    event.Reason,
 		Timestamp: event.LastTimestamp,
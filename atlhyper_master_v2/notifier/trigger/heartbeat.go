@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
 	"AtlHyper/atlhyper_master_v2/datahub"
 	"AtlHyper/atlhyper_master_v2/notifier"
 	"AtlHyper/atlhyper_master_v2/notifier/template"
@@ -26,6 +27,7 @@ type HeartbeatTrigger struct {
 	store   datahub.Store
 	manager notifier.AlertManager
 	config  HeartbeatConfig
+	windows MaintenanceWindows // 可选，nil = 不按维护窗口过滤
 
 	running   bool
 	stopCh    chan struct{}
@@ -52,6 +54,11 @@ func NewHeartbeatTrigger(store datahub.Store, manager notifier.AlertManager, cfg
 	}
 }
 
+// SetMaintenance 设置维护窗口（整集群维护期间 Agent 离线不告警），须在 Start 前调用
+func (t *HeartbeatTrigger) SetMaintenance(windows MaintenanceWindows) {
+	t.windows = windows
+}
+
 // Start 启动触发器
 func (t *HeartbeatTrigger) Start() error {
 	t.mu.Lock()
@@ -115,6 +122,11 @@ func (t *HeartbeatTrigger) check() {
 		wasAlerted := t.isAlerted(agent.ClusterID)
 
 		if offline && !wasAlerted {
+			// 整集群维护窗口内不告警；窗口结束后仍离线再告警
+			if w := inMaintenance(t.windows, maintenance.Target{ClusterID: agent.ClusterID}, now); w != nil {
+				log.Debug("维护窗口内，跳过离线告警", "cluster", agent.ClusterID, "window", w.Name)
+				continue
+			}
 			t.triggerOffline(agent, now)
 		} else if !offline && wasAlerted {
 			t.triggerRecovery(agent)
@@ -0,0 +1,279 @@
+// atlhyper_master_v2/notifier/trigger/incident.go
+// AIOps 事件告警触发器（订阅状态机转换）
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/notifier"
+	"AtlHyper/atlhyper_master_v2/notifier/template"
+)
+
+// 因果链最多展示条数
+const maxCausalChain = 5
+
+// IncidentSource 事件数据来源（由 aiops.Engine 实现）
+type IncidentSource interface {
+	GetIncidentDetail(ctx context.Context, incidentID string) *aiops.IncidentDetail
+	GetEntityRisk(clusterID, entityKey string) *aiops.EntityRiskDetail
+}
+
+// IncidentConfig 事件告警配置
+type IncidentConfig struct {
+	NotifyWarning bool   // Warning 阶段也通知（默认仅升级为 Incident 后通知）
+	WebURL        string // Web 地址，用于生成事件详情链接（为空则不附链接）
+}
+
+// IncidentTrigger AIOps 事件告警触发器
+// 由 aiops.Engine 的状态机转换驱动，无需轮询
+type IncidentTrigger struct {
+	source     IncidentSource
+	reportRepo database.AIReportRepository
+	manager    notifier.AlertManager
+	config     IncidentConfig
+	windows    MaintenanceWindows // 可选，nil = 不按维护窗口过滤
+
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	stopped bool
+}
+
+// NewIncidentTrigger 创建事件告警触发器
+// reportRepo 可为 nil（不附 AI 报告摘要）
+func NewIncidentTrigger(
+	source IncidentSource,
+	reportRepo database.AIReportRepository,
+	manager notifier.AlertManager,
+	cfg IncidentConfig,
+) *IncidentTrigger {
+	cfg.WebURL = strings.TrimRight(cfg.WebURL, "/")
+	return &IncidentTrigger{
+		source:     source,
+		reportRepo: reportRepo,
+		manager:    manager,
+		config:     cfg,
+	}
+}
+
+// SetMaintenance 设置维护窗口（根因实体处于窗口内时不发送事件告警，恢复 / 关闭通知照常发送）
+func (t *IncidentTrigger) SetMaintenance(windows MaintenanceWindows) {
+	t.windows = windows
+}
+
+// OnTransition 状态机转换回调（aiops.TransitionListener）
+// 在状态机评估路径上调用，发送异步进行
+func (t *IncidentTrigger) OnTransition(ev aiops.TransitionEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		t.handle(ev)
+	}()
+}
+
+// Stop 停止触发器，等待进行中的通知发送完成
+func (t *IncidentTrigger) Stop() error {
+	t.mu.Lock()
+	t.stopped = true
+	t.mu.Unlock()
+
+	t.wg.Wait()
+	log.Info("IncidentTrigger 已停止")
+	return nil
+}
+
+// handle 处理单次转换
+func (t *IncidentTrigger) handle(ev aiops.TransitionEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	detail := t.source.GetIncidentDetail(ctx, ev.IncidentID)
+	if detail == nil {
+		log.Warn("事件不存在，跳过通知", "incident", ev.IncidentID)
+		return
+	}
+	if !t.shouldNotify(ev, detail) {
+		return
+	}
+	if firing(ev.Kind) {
+		if w := inMaintenance(t.windows, maintenance.EntityTarget(detail.ClusterID, detail.RootCause), ev.At); w != nil {
+			log.Info("维护窗口内，跳过 AIOps 事件告警", "incident", ev.IncidentID, "kind", ev.Kind, "window", w.Name)
+			return
+		}
+	}
+
+	name, data := t.buildAlert(ctx, ev, detail)
+	if err := t.manager.SendWithTemplate(name, data); err != nil {
+		log.Error("发送 AIOps 事件告警失败", "incident", ev.IncidentID, "kind", ev.Kind, "err", err)
+		return
+	}
+	log.Info("AIOps 事件告警已发送", "incident", ev.IncidentID, "kind", ev.Kind)
+}
+
+// shouldNotify 判断是否需要通知
+// Warning 默认不通知（避免抖动噪音）；恢复 / 关闭仅对曾升级为 Incident 的事件通知，
+// 依据时间线判断，Master 重启后仍然成立
+func (t *IncidentTrigger) shouldNotify(ev aiops.TransitionEvent, detail *aiops.IncidentDetail) bool {
+	switch ev.Kind {
+	case aiops.TransitionWarningCreated:
+		return t.config.NotifyWarning
+	case aiops.TransitionEscalated:
+		return ev.State == aiops.StateIncident || t.config.NotifyWarning
+	case aiops.TransitionRecoveryStarted, aiops.TransitionStable:
+		return t.config.NotifyWarning || reachedIncident(detail)
+	}
+	return false
+}
+
+// firing 是否为触发类通知（恢复 / 关闭通知不受维护窗口影响，保证已发出的告警能被关闭）
+func firing(kind aiops.TransitionKind) bool {
+	return kind == aiops.TransitionWarningCreated || kind == aiops.TransitionEscalated
+}
+
+// reachedIncident 事件是否曾升级为 Incident
+func reachedIncident(detail *aiops.IncidentDetail) bool {
+	marker := "→ " + string(aiops.StateIncident)
+	for _, tl := range detail.Timeline {
+		if tl.EventType == aiops.TimelineStateChange && strings.Contains(tl.Detail, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildAlert 构建模板名与模板数据
+func (t *IncidentTrigger) buildAlert(ctx context.Context, ev aiops.TransitionEvent, detail *aiops.IncidentDetail) (string, *template.AlertData) {
+	inc := &detail.Incident
+
+	info := &template.IncidentData{
+		ID:          inc.ID,
+		State:       string(ev.State),
+		RootCause:   inc.RootCause,
+		PeakRisk:    formatRisk(inc.PeakRisk),
+		Recurrence:  inc.Recurrence,
+		CausalChain: t.causalChain(inc.ClusterID, detail),
+		AISummary:   t.aiSummary(ctx, inc.ID),
+	}
+	if ev.Kind != aiops.TransitionStable {
+		info.CurrentRisk = formatRisk(ev.RFinal)
+	}
+	end := ev.At
+	if inc.ResolvedAt != nil {
+		end = *inc.ResolvedAt
+	}
+	if d := end.Sub(inc.StartedAt); d > 0 {
+		info.Duration = d.Round(time.Second).String()
+	}
+	if t.config.WebURL != "" {
+		info.URL = t.config.WebURL + "/aiops/incidents?id=" + inc.ID
+	}
+
+	data := &template.AlertData{
+		Severity:  inc.Severity,
+		Source:    string(notifier.SourceAIOps),
+		ClusterID: inc.ClusterID,
+		Namespace: entityNamespace(inc.RootCause),
+		Resource:  inc.RootCause,
+		Reason:    string(ev.Kind),
+		Timestamp: ev.At,
+		Incident:  info,
+	}
+
+	name := "aiops_incident"
+	switch ev.Kind {
+	case aiops.TransitionWarningCreated:
+		data.Title = "AIOps 预警: " + inc.RootCause
+		data.Message = "检测到实体风险升高，已创建 Warning 事件"
+	case aiops.TransitionEscalated:
+		data.Title = "AIOps 事件: " + inc.RootCause
+		data.Message = fmt.Sprintf("事件已升级为 %s，请及时处理", ev.State)
+	case aiops.TransitionRecoveryStarted:
+		data.Title = "AIOps 事件恢复中: " + inc.RootCause
+		data.Message = "风险已回落，事件进入恢复观察期"
+		data.Severity = "info"
+	case aiops.TransitionStable:
+		name = "aiops_incident_resolved"
+		data.Title = "AIOps 事件已解决: " + inc.RootCause
+		data.Message = "实体已稳定，事件已关闭"
+		data.Severity = "info"
+	}
+	if data.Severity == "" {
+		data.Severity = aiops.SeverityFromRisk(ev.RFinal)
+	}
+	return name, data
+}
+
+// causalChain 构建因果链描述
+// 优先使用实时风险详情中的因果链（事件进行中），否则按受影响实体风险降序
+func (t *IncidentTrigger) causalChain(clusterID string, detail *aiops.IncidentDetail) []string {
+	var chain []string
+
+	if rd := t.source.GetEntityRisk(clusterID, detail.RootCause); rd != nil {
+		for _, c := range rd.CausalChain {
+			chain = append(chain, fmt.Sprintf("%s %s (偏离 %.1fσ)", c.EntityKey, c.MetricName, c.Deviation))
+			if len(chain) >= maxCausalChain {
+				return chain
+			}
+		}
+	}
+	if len(chain) > 0 {
+		return chain
+	}
+
+	entities := make([]*aiops.IncidentEntity, len(detail.Entities))
+	copy(entities, detail.Entities)
+	sort.SliceStable(entities, func(i, j int) bool { return entities[i].RFinal > entities[j].RFinal })
+	for _, e := range entities {
+		chain = append(chain, fmt.Sprintf("%s [%s] R=%s", e.EntityKey, e.Role, formatRisk(e.RFinal)))
+		if len(chain) >= maxCausalChain {
+			break
+		}
+	}
+	return chain
+}
+
+// aiSummary 获取事件最新 AI 报告摘要
+func (t *IncidentTrigger) aiSummary(ctx context.Context, incidentID string) string {
+	if t.reportRepo == nil {
+		return ""
+	}
+	reports, err := t.reportRepo.ListByIncident(ctx, incidentID)
+	if err != nil {
+		log.Warn("获取 AI 报告失败", "incident", incidentID, "err", err)
+		return ""
+	}
+	// 按创建时间倒序，取第一个有摘要的报告
+	for _, r := range reports {
+		if r.Summary != "" {
+			return r.Summary
+		}
+	}
+	return ""
+}
+
+// formatRisk 风险值格式化为百分比
+func formatRisk(r float64) string {
+	return fmt.Sprintf("%.0f%%", r*100)
+}
+
+// entityNamespace 实体键（namespace/type/name）中的命名空间，集群级实体返回空
+func entityNamespace(entityKey string) string {
+	ns, _, ok := strings.Cut(entityKey, "/")
+	if !ok || ns == "_cluster" {
+		return ""
+	}
+	return ns
+}
@@ -0,0 +1,194 @@
+package trigger
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+	"AtlHyper/atlhyper_master_v2/notifier/template"
+)
+
+type fakeIncidentSource struct {
+	detail *aiops.IncidentDetail
+	risk   *aiops.EntityRiskDetail
+}
+
+func (f *fakeIncidentSource) GetIncidentDetail(ctx context.Context, incidentID string) *aiops.IncidentDetail {
+	return f.detail
+}
+
+func (f *fakeIncidentSource) GetEntityRisk(clusterID, entityKey string) *aiops.EntityRiskDetail {
+	return f.risk
+}
+
+type sentAlert struct {
+	name string
+	data *template.AlertData
+}
+
+type fakeAlertManager struct {
+	mu   sync.Mutex
+	sent []sentAlert
+}
+
+func (m *fakeAlertManager) SendWithTemplate(name string, data *template.AlertData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, sentAlert{name: name, data: data})
+	return nil
+}
+func (m *fakeAlertManager) Test(ctx context.Context, channelID int64) error { return nil }
+func (m *fakeAlertManager) Start() error                                    { return nil }
+func (m *fakeAlertManager) Stop()                                           {}
+
+func testIncidentDetail(escalated bool) *aiops.IncidentDetail {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	d := &aiops.IncidentDetail{
+		Incident: aiops.Incident{
+			ID:        "inc-1",
+			ClusterID: "c1",
+			State:     aiops.StateIncident,
+			Severity:  "critical",
+			RootCause: "default/service/api",
+			PeakRisk:  0.87,
+			StartedAt: start,
+		},
+		Entities: []*aiops.IncidentEntity{
+			{IncidentID: "inc-1", EntityKey: "default/pod/api-1", Role: "affected", RFinal: 0.5},
+			{IncidentID: "inc-1", EntityKey: "default/service/api", Role: "root_cause", RFinal: 0.87},
+		},
+		Timeline: []*aiops.IncidentTimeline{
+			{EventType: aiops.TimelineStateChange, Detail: "状态变更: healthy → warning"},
+		},
+	}
+	if escalated {
+		d.Timeline = append(d.Timeline, &aiops.IncidentTimeline{
+			EventType: aiops.TimelineStateChange, Detail: "状态变更 → incident (R_final=0.870)",
+		})
+	}
+	return d
+}
+
+func TestIncidentTrigger_ShouldNotify(t *testing.T) {
+	tr := NewIncidentTrigger(&fakeIncidentSource{}, nil, &fakeAlertManager{}, IncidentConfig{})
+
+	cases := []struct {
+		kind      aiops.TransitionKind
+		state     aiops.EntityState
+		escalated bool
+		want      bool
+	}{
+		{aiops.TransitionWarningCreated, aiops.StateWarning, false, false},
+		{aiops.TransitionEscalated, aiops.StateIncident, true, true},
+		{aiops.TransitionRecoveryStarted, aiops.StateRecovery, true, true},
+		{aiops.TransitionStable, aiops.StateStable, true, true},
+		// 仅停留在 Warning 的事件关闭时不通知
+		{aiops.TransitionStable, aiops.StateStable, false, false},
+	}
+	for _, c := range cases {
+		ev := aiops.TransitionEvent{Kind: c.kind, IncidentID: "inc-1", State: c.state}
+		if got := tr.shouldNotify(ev, testIncidentDetail(c.escalated)); got != c.want {
+			t.Errorf("shouldNotify(%s, escalated=%v) = %v, want %v", c.kind, c.escalated, got, c.want)
+		}
+	}
+
+	tr.config.NotifyWarning = true
+	ev := aiops.TransitionEvent{Kind: aiops.TransitionWarningCreated, State: aiops.StateWarning}
+	if !tr.shouldNotify(ev, testIncidentDetail(false)) {
+		t.Error("NotifyWarning=true 时 Warning 应通知")
+	}
+}
+
+func TestIncidentTrigger_OnTransitionRendersTemplate(t *testing.T) {
+	source := &fakeIncidentSource{
+		detail: testIncidentDetail(true),
+		risk: &aiops.EntityRiskDetail{
+			CausalChain: []*aiops.CausalEntry{
+				{EntityKey: "default/pod/api-1", MetricName: "restart_count", Deviation: 4.2},
+				{EntityKey: "default/service/api", MetricName: "error_rate", Deviation: 3.1},
+			},
+		},
+	}
+	mgr := &fakeAlertManager{}
+	tr := NewIncidentTrigger(source, nil, mgr, IncidentConfig{WebURL: "https://atlhyper.example.com/"})
+
+	tr.OnTransition(aiops.TransitionEvent{
+		Kind:       aiops.TransitionEscalated,
+		IncidentID: "inc-1",
+		State:      aiops.StateIncident,
+		RFinal:     0.8,
+		At:         time.Date(2026, 1, 1, 10, 5, 0, 0, time.UTC),
+	})
+	tr.Stop()
+
+	if len(mgr.sent) != 1 {
+		t.Fatalf("sent = %d, want 1", len(mgr.sent))
+	}
+	alert := mgr.sent[0]
+	if alert.name != "aiops_incident" {
+		t.Errorf("template = %q, want aiops_incident", alert.name)
+	}
+	if alert.data.Incident.URL != "https://atlhyper.example.com/aiops/incidents?id=inc-1" {
+		t.Errorf("URL = %q", alert.data.Incident.URL)
+	}
+
+	renderer, err := template.NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+	for _, ch := range []string{"slack", "email"} {
+		msg, err := renderer.Render(alert.name, ch, alert.data)
+		if err != nil {
+			t.Fatalf("Render %s: %v", ch, err)
+		}
+		for _, want := range []string{"default/service/api", "87%", "restart_count", "5m0s", alert.data.Incident.URL} {
+			if !strings.Contains(msg.Body, want) {
+				t.Errorf("%s body missing %q:\n%s", ch, want, msg.Body)
+			}
+		}
+	}
+}
+
+func TestIncidentTrigger_StoppedIgnoresTransitions(t *testing.T) {
+	mgr := &fakeAlertManager{}
+	tr := NewIncidentTrigger(&fakeIncidentSource{detail: testIncidentDetail(true)}, nil, mgr, IncidentConfig{})
+	tr.Stop()
+
+	tr.OnTransition(aiops.TransitionEvent{Kind: aiops.TransitionEscalated, IncidentID: "inc-1", State: aiops.StateIncident})
+	tr.Stop()
+
+	if len(mgr.sent) != 0 {
+		t.Errorf("sent = %d after Stop, want 0", len(mgr.sent))
+	}
+}
+
+type fakeWindows struct {
+	clusterID string
+}
+
+func (f *fakeWindows) Covers(t maintenance.Target, at time.Time) *maintenance.Window {
+	if t.ClusterID != f.clusterID {
+		return nil
+	}
+	return &maintenance.Window{ID: 1, Name: "deploy", ClusterID: f.clusterID}
+}
+
+func TestIncidentTrigger_Maintenance(t *testing.T) {
+	mgr := &fakeAlertManager{}
+	tr := NewIncidentTrigger(&fakeIncidentSource{detail: testIncidentDetail(true)}, nil, mgr, IncidentConfig{})
+	tr.SetMaintenance(&fakeWindows{clusterID: "c1"})
+
+	at := time.Date(2026, 1, 1, 10, 5, 0, 0, time.UTC)
+	tr.OnTransition(aiops.TransitionEvent{Kind: aiops.TransitionEscalated, IncidentID: "inc-1", State: aiops.StateIncident, At: at})
+	// 恢复通知不受维护窗口影响
+	tr.OnTransition(aiops.TransitionEvent{Kind: aiops.TransitionStable, IncidentID: "inc-1", State: aiops.StateStable, At: at})
+	tr.Stop()
+
+	if len(mgr.sent) != 1 {
+		t.Fatalf("sent = %d, want 1 (only the resolved notification)", len(mgr.sent))
+	}
+}
@@ -0,0 +1,22 @@
+// atlhyper_master_v2/notifier/trigger/maintenance.go
+// 维护窗口: 窗口覆盖的对象不发送告警
+package trigger
+
+import (
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+)
+
+// MaintenanceWindows 维护窗口判定（由 aiops/maintenance.Manager 实现）
+type MaintenanceWindows interface {
+	Covers(t maintenance.Target, at time.Time) *maintenance.Window
+}
+
+// inMaintenance 返回在 at 时刻覆盖目标的窗口（未设置维护窗口或不在窗口内返回 nil）
+func inMaintenance(windows MaintenanceWindows, t maintenance.Target, at time.Time) *maintenance.Window {
+	if windows == nil {
+		return nil
+	}
+	return windows.Covers(t, at)
+}
@@ -0,0 +1,121 @@
+// atlhyper_master_v2/notifier/trigger/slo_burn.go
+// SLO 燃烧率告警触发器（订阅 slo.BurnAlerter 状态变化）
+package trigger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/notifier"
+	"AtlHyper/atlhyper_master_v2/notifier/template"
+)
+
+// SLOBurnTrigger SLO 燃烧率告警触发器
+// 去重由 BurnAlerter 保证：同一告警只在触发 / 解除时回调一次
+type SLOBurnTrigger struct {
+	manager notifier.AlertManager
+	windows MaintenanceWindows // 可选，nil = 不按维护窗口过滤
+
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	stopped bool
+}
+
+// NewSLOBurnTrigger 创建 SLO 燃烧率告警触发器
+func NewSLOBurnTrigger(manager notifier.AlertManager) *SLOBurnTrigger {
+	return &SLOBurnTrigger{manager: manager}
+}
+
+// SetMaintenance 设置维护窗口（服务处于窗口内时不发送燃烧率告警，恢复通知照常发送），须在注册监听前调用
+func (t *SLOBurnTrigger) SetMaintenance(windows MaintenanceWindows) {
+	t.windows = windows
+}
+
+// OnBurnAlert 告警状态变化回调（slo.BurnAlertListener），发送异步进行
+func (t *SLOBurnTrigger) OnBurnAlert(alert model.SLOBurnAlert, firing bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		t.send(alert, firing)
+	}()
+}
+
+// Stop 停止触发器，等待进行中的通知发送完成
+func (t *SLOBurnTrigger) Stop() error {
+	t.mu.Lock()
+	t.stopped = true
+	t.mu.Unlock()
+
+	t.wg.Wait()
+	log.Info("SLOBurnTrigger 已停止")
+	return nil
+}
+
+// send 构建并发送告警
+func (t *SLOBurnTrigger) send(alert model.SLOBurnAlert, firing bool) {
+	now := time.Now()
+	if firing {
+		if w := inMaintenance(t.windows, burnTarget(alert), now); w != nil {
+			log.Info("维护窗口内，跳过 SLO 燃烧率告警", "service", alert.ServiceKey, "rule", alert.Rule, "window", w.Name)
+			return
+		}
+	}
+
+	name, data := buildBurnAlert(alert, firing, now)
+	if err := t.manager.SendWithTemplate(name, data); err != nil {
+		log.Error("发送 SLO 燃烧率告警失败", "service", alert.ServiceKey, "rule", alert.Rule, "err", err)
+	}
+}
+
+// burnTarget 维护窗口判定对象（ServiceKey 无法解析为 Service 时仅整集群窗口生效）
+func burnTarget(alert model.SLOBurnAlert) maintenance.Target {
+	t := maintenance.Target{ClusterID: alert.ClusterID}
+	if alert.ServiceName != "" {
+		t.Namespace, t.Kind, t.Name = alert.Namespace, "service", alert.ServiceName
+	}
+	return t
+}
+
+// buildBurnAlert 构建模板名与模板数据
+// Resource 含规则名：同一服务的 fast / slow 告警各自去重、升级与恢复
+func buildBurnAlert(alert model.SLOBurnAlert, firing bool, now time.Time) (string, *template.AlertData) {
+	data := &template.AlertData{
+		Severity:  alert.Severity,
+		Source:    string(notifier.SourceSLO),
+		ClusterID: alert.ClusterID,
+		Namespace: alert.Namespace,
+		Resource:  alert.ServiceKey + "/" + alert.Rule,
+		Reason:    "SLOBurnRate",
+		Timestamp: now,
+		Fields: map[string]string{
+			"rule":            alert.Rule,
+			"windows":         alert.LongWindow + " / " + alert.ShortWindow,
+			"threshold":       fmt.Sprintf("%.1fx", alert.Threshold),
+			"long_burn_rate":  fmt.Sprintf("%.1fx", alert.LongBurnRate),
+			"short_burn_rate": fmt.Sprintf("%.1fx", alert.ShortBurnRate),
+			"target":          fmt.Sprintf("%.2f%% (%s)", alert.AvailabilityTarget, alert.BudgetWindow),
+			"firing_since":    alert.FiringSince,
+		},
+	}
+
+	if !firing {
+		data.Title = "SLO 燃烧率恢复: " + alert.ServiceKey
+		data.Message = fmt.Sprintf("错误预算消耗速度已回落到 %s 阈值以下", alert.Rule)
+		data.Severity = "info"
+		return "slo_burn_rate_resolved", data
+	}
+
+	data.Title = "SLO 燃烧率告警: " + alert.ServiceKey
+	data.Message = fmt.Sprintf("错误预算正以 %.1f 倍速度消耗（%s 窗口），超过 %.1fx 阈值",
+		alert.LongBurnRate, alert.LongWindow, alert.Threshold)
+	return "slo_burn_rate", data
+}
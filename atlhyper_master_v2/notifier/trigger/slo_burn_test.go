@@ -0,0 +1,119 @@
+package trigger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/notifier/template"
+)
+
+func TestBuildBurnAlert_Renders(t *testing.T) {
+	alert := model.SLOBurnAlert{
+		ClusterID:          "c1",
+		ServiceKey:         "default-api-80@kubernetes",
+		Rule:               "fast",
+		Severity:           "critical",
+		LongWindow:         "1h",
+		ShortWindow:        "5m",
+		Threshold:          14.4,
+		LongBurnRate:       20,
+		ShortBurnRate:      25,
+		AvailabilityTarget: 99.9,
+		BudgetWindow:       "30d",
+		FiringSince:        "2026-01-01T12:30:00Z",
+	}
+
+	renderer, err := template.NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+
+	cases := []struct {
+		firing   bool
+		name     string
+		severity string
+		want     []string
+	}{
+		{true, "slo_burn_rate", "critical", []string{"20.0x", "25.0x", "14.4x", "99.90% (30d)", "1h / 5m"}},
+		{false, "slo_burn_rate_resolved", "info", []string{"2026-01-01T12:30:00Z", "99.90% (30d)"}},
+	}
+	for _, c := range cases {
+		name, data := buildBurnAlert(alert, c.firing, time.Now())
+		if name != c.name || data.Severity != c.severity {
+			t.Errorf("firing=%v: template=%s severity=%s, want %s/%s", c.firing, name, data.Severity, c.name, c.severity)
+		}
+		for _, ch := range []string{"slack", "email"} {
+			msg, err := renderer.Render(name, ch, data)
+			if err != nil {
+				t.Fatalf("Render %s/%s: %v", name, ch, err)
+			}
+			for _, want := range append(c.want, alert.ServiceKey) {
+				if !strings.Contains(msg.Body, want) {
+					t.Errorf("%s/%s body missing %q:\n%s", name, ch, want, msg.Body)
+				}
+			}
+		}
+	}
+}
+
+func TestBuildBurnAlert_RuleInIdentity(t *testing.T) {
+	fast := model.SLOBurnAlert{ClusterID: "c1", ServiceKey: "default-api-80@kubernetes", Rule: "fast"}
+	slow := fast
+	slow.Rule = "slow"
+
+	now := time.Now()
+	for _, firing := range []bool{true, false} {
+		_, a := buildBurnAlert(fast, firing, now)
+		_, b := buildBurnAlert(slow, firing, now)
+		if a.Resource == b.Resource {
+			t.Errorf("firing=%v: fast and slow share resource %q", firing, a.Resource)
+		}
+	}
+
+	// 恢复告警与其触发告警须为同一对象
+	_, firing := buildBurnAlert(fast, true, now)
+	_, resolved := buildBurnAlert(fast, false, now)
+	if firing.Resource != resolved.Resource {
+		t.Errorf("resolved resource = %q, want %q", resolved.Resource, firing.Resource)
+	}
+}
+
+type windowSet []*maintenance.Window
+
+func (ws windowSet) Covers(t maintenance.Target, at time.Time) *maintenance.Window {
+	for _, w := range ws {
+		if w.Covers(t) {
+			return w
+		}
+	}
+	return nil
+}
+
+func TestSLOBurnTrigger_Maintenance(t *testing.T) {
+	mgr := &fakeAlertManager{}
+	tr := NewSLOBurnTrigger(mgr)
+	tr.SetMaintenance(windowSet{{Name: "deploy-api", ClusterID: "c1", Namespace: "default", Workload: "api"}})
+
+	api := model.SLOBurnAlert{ClusterID: "c1", ServiceKey: "default-api-80@kubernetes", Namespace: "default", ServiceName: "api", Rule: "fast"}
+	web := model.SLOBurnAlert{ClusterID: "c1", ServiceKey: "default/web", Namespace: "default", ServiceName: "web", Rule: "fast"}
+	tr.OnBurnAlert(api, true)
+	tr.OnBurnAlert(web, true)
+	// 恢复通知不受维护窗口影响
+	tr.OnBurnAlert(api, false)
+	tr.Stop()
+
+	if len(mgr.sent) != 2 {
+		t.Fatalf("sent = %d, want 2 (web firing + api resolved)", len(mgr.sent))
+	}
+	for _, s := range mgr.sent {
+		if s.name == "slo_burn_rate" && s.data.Resource != "default/web/fast" {
+			t.Errorf("firing alert for %s sent during maintenance", s.data.Resource)
+		}
+		if s.data.Namespace != "default" {
+			t.Errorf("namespace = %q, want default", s.data.Namespace)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// atlhyper_master_v2/oidc/jwks.go
+// OIDC 单点登录 — JWKS 签名公钥
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// jwksRefreshInterval kid 未命中时两次刷新 JWKS 的最小间隔（防止伪造 kid 放大请求）
+const jwksRefreshInterval = time.Minute
+
+// keySet 已加载的签名公钥
+type keySet struct {
+	fetchedAt time.Time
+	keys      map[string]interface{} // kid → *rsa.PublicKey / *ecdsa.PublicKey
+}
+
+// jwk JSON Web Key（只解析签名用的 RSA / EC 公钥）
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// signingKey 按 kid 查找签名公钥，未命中时刷新 JWKS（IdP 轮换密钥）
+func (p *Provider) signingKey(ctx context.Context, meta *discovery, kid string) (interface{}, error) {
+	p.metaMu.Lock()
+	defer p.metaMu.Unlock()
+
+	if key := p.keys.lookup(kid); key != nil {
+		return key, nil
+	}
+	if p.keys != nil && time.Since(p.keys.fetchedAt) < jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := p.getJSON(ctx, meta.JWKSURI, &doc); err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	ks := &keySet{fetchedAt: time.Now(), keys: make(map[string]interface{}, len(doc.Keys))}
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Warn("忽略无法解析的 JWK", "kid", k.Kid, "err", err)
+			continue
+		}
+		ks.keys[k.Kid] = key
+	}
+	p.keys = ks
+
+	if key := ks.lookup(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookup 按 kid 查找；Token 未携带 kid 且只有一把公钥时直接使用
+func (ks *keySet) lookup(kid string) interface{} {
+	if ks == nil {
+		return nil
+	}
+	if key, ok := ks.keys[kid]; ok {
+		return key
+	}
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key
+		}
+	}
+	return nil
+}
+
+// publicKey 解析 JWK 为公钥
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// decodeBigInt 解码 base64url 大整数
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,169 @@
+// atlhyper_master_v2/oidc/login.go
+// OIDC 单点登录 — 角色映射与用户即时创建（JIT）
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/rbac"
+)
+
+// Authenticate 完成登录回调：交换授权码、校验 ID Token，返回对应的本地用户
+//
+// 首次登录的 IdP 账号自动创建本地用户（无密码，只能通过 SSO 登录）；
+// 每次登录按组映射同步角色，IdP 是角色的唯一来源。
+func (p *Provider) Authenticate(ctx context.Context, code, state string) (*database.User, error) {
+	claims, err := p.exchange(ctx, code, state)
+	if err != nil {
+		return nil, err
+	}
+
+	role := p.MapRole(claims.Groups)
+	if role == 0 {
+		return nil, ErrNoRole
+	}
+
+	identity, err := p.identities.GetBySubject(ctx, claims.Issuer, claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("get identity: %w", err)
+	}
+	if identity == nil {
+		return p.provision(ctx, claims, role)
+	}
+
+	user, err := p.users.GetByID(ctx, identity.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if user == nil {
+		// 本地用户已被删除：视为新账号重新创建
+		if err := p.identities.DeleteByUser(ctx, identity.UserID); err != nil {
+			return nil, fmt.Errorf("delete stale identity: %w", err)
+		}
+		return p.provision(ctx, claims, role)
+	}
+	if user.Status != 1 {
+		return nil, ErrUserDisabled
+	}
+
+	// 同步 IdP 资料与角色
+	changed := false
+	if user.Role != role {
+		log.Info("OIDC 用户角色变更", "user", user.Username, "from", user.Role, "to", role)
+		user.Role, changed = role, true
+	}
+	if claims.Email != "" && user.Email != claims.Email {
+		user.Email, changed = claims.Email, true
+	}
+	if claims.Name != "" && user.DisplayName != claims.Name {
+		user.DisplayName, changed = claims.Name, true
+	}
+	if changed {
+		if err := p.users.Update(ctx, user); err != nil {
+			return nil, fmt.Errorf("update user: %w", err)
+		}
+	}
+	if err := p.identities.Touch(ctx, identity.ID, claims.Email); err != nil {
+		log.Warn("更新外部身份登录时间失败", "user", user.Username, "err", err)
+	}
+	return user, nil
+}
+
+// provision 为首次登录的 IdP 账号创建本地用户并关联身份
+func (p *Provider) provision(ctx context.Context, claims *Claims, role int) (*database.User, error) {
+	username, err := p.uniqueUsername(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := claims.Name
+	if displayName == "" {
+		displayName = username
+	}
+	user := &database.User{
+		Username:    username,
+		DisplayName: displayName,
+		Email:       claims.Email,
+		Role:        role,
+		Status:      1,
+	}
+	if err := p.users.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("create user: %w", err)
+	}
+
+	now := time.Now()
+	if err := p.identities.Create(ctx, &database.UserIdentity{
+		UserID:    user.ID,
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		CreatedAt: now,
+		LastLogin: now,
+	}); err != nil {
+		_ = p.users.Delete(ctx, user.ID)
+		return nil, fmt.Errorf("create identity: %w", err)
+	}
+
+	log.Info("OIDC 用户已创建", "user", username, "role", role, "issuer", claims.Issuer)
+	return user, nil
+}
+
+// uniqueUsername 生成不与现有用户冲突的用户名
+// 同名本地账号不会被自动关联（避免 IdP 账号接管本地 admin 等账号），改用带序号的用户名
+func (p *Provider) uniqueUsername(ctx context.Context, claims *Claims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base = claims.Email
+	}
+	if base == "" {
+		base = "oidc-" + claims.Subject
+	}
+
+	for i := 1; i <= 100; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, i)
+		}
+		existing, err := p.users.GetByUsername(ctx, candidate)
+		if err != nil {
+			return "", fmt.Errorf("check username: %w", err)
+		}
+		if existing == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no available username for %q", base)
+}
+
+// MapRole 按组映射角色，命中多个组时取最高角色；未命中返回 DefaultRole
+func (p *Provider) MapRole(groups []string) int {
+	role := 0
+	for _, g := range groups {
+		switch {
+		case containsFold(p.cfg.AdminGroups, g):
+			role = max(role, rbac.RoleAdmin)
+		case containsFold(p.cfg.OperatorGroups, g):
+			role = max(role, rbac.RoleOperator)
+		case containsFold(p.cfg.ViewerGroups, g):
+			role = max(role, rbac.RoleViewer)
+		}
+	}
+	if role == 0 {
+		return p.cfg.DefaultRole
+	}
+	return role
+}
+
+// containsFold 大小写不敏感查找（IdP 组名大小写常不一致）
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
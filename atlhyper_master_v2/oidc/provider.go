@@ -0,0 +1,260 @@
+// atlhyper_master_v2/oidc/provider.go
+// OIDC 单点登录 — Provider 发现、授权码交换与 ID Token 校验
+//
+// 流程（授权码 + PKCE）:
+//
+//	Web 请求 AuthURL → 浏览器跳转 IdP → IdP 回调 Web（code + state）
+//	→ Web 将 code/state 提交 Master → Authenticate 交换 Token、校验 ID Token、JIT 创建用户
+//
+// code_verifier 与 nonce 只保存在 Master 内存中，不经过浏览器；
+// state 另由 Handler 写入发起登录的浏览器 Cookie，回调时须一致（防登录 CSRF）。
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/common/logger"
+)
+
+var log = logger.Module("OIDC")
+
+// Provider OIDC 登录提供方
+type Provider struct {
+	cfg        Config
+	users      database.UserRepository
+	identities database.UserIdentityRepository
+	httpClient *http.Client
+
+	// Provider 元数据与签名公钥（懒加载，kid 未命中时刷新）
+	metaMu sync.Mutex
+	meta   *discovery
+	keys   *keySet
+
+	// 等待回调的登录请求（state → pendingLogin）
+	pendingMu sync.Mutex
+	pending   map[string]pendingLogin
+}
+
+// NewProvider 创建 OIDC Provider
+// 元数据在首次登录时获取，IdP 暂不可用不影响 Master 启动
+func NewProvider(cfg Config, users database.UserRepository, identities database.UserIdentityRepository) *Provider {
+	if cfg.StateTTL <= 0 {
+		cfg.StateTTL = 10 * time.Minute
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	return &Provider{
+		cfg:        cfg,
+		users:      users,
+		identities: identities,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		pending:    make(map[string]pendingLogin),
+	}
+}
+
+// AuthURL 发起登录，返回 IdP 授权地址与 state
+func (p *Provider) AuthURL(ctx context.Context) (string, string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	p.pendingMu.Lock()
+	now := time.Now()
+	for s, pl := range p.pending {
+		if now.After(pl.expiresAt) {
+			delete(p.pending, s)
+		}
+	}
+	p.pending[state] = pendingLogin{verifier: verifier, nonce: nonce, expiresAt: now.Add(p.cfg.StateTTL)}
+	p.pendingMu.Unlock()
+
+	return p.oauth2Config(meta).AuthCodeURL(state,
+		oauth2.S256ChallengeOption(verifier),
+		oauth2.SetAuthURLParam("nonce", nonce),
+	), state, nil
+}
+
+// StateTTL 登录请求有效期
+func (p *Provider) StateTTL() time.Duration {
+	return p.cfg.StateTTL
+}
+
+// exchange 校验 state 并用授权码换取 ID Token 声明
+func (p *Provider) exchange(ctx context.Context, code, state string) (*Claims, error) {
+	p.pendingMu.Lock()
+	pl, ok := p.pending[state]
+	delete(p.pending, state) // state 只能使用一次
+	p.pendingMu.Unlock()
+	if !ok || time.Now().After(pl.expiresAt) {
+		return nil, ErrInvalidState
+	}
+
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.httpClient)
+	token, err := p.oauth2Config(meta).Exchange(ctx, code, oauth2.VerifierOption(pl.verifier))
+	if err != nil {
+		return nil, fmt.Errorf("exchange code: %w", err)
+	}
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, fmt.Errorf("token response missing id_token")
+	}
+	return p.verifyIDToken(ctx, meta, rawIDToken, pl.nonce)
+}
+
+// oauth2Config 构建 OAuth2 客户端配置
+func (p *Provider) oauth2Config(meta *discovery) *oauth2.Config {
+	scopes := []string{"openid"}
+	for _, s := range p.cfg.Scopes {
+		if s != "openid" {
+			scopes = append(scopes, s)
+		}
+	}
+	return &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Scopes:       scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  meta.AuthorizationEndpoint,
+			TokenURL: meta.TokenEndpoint,
+		},
+	}
+}
+
+// verifyIDToken 校验 ID Token 签名、issuer、audience、有效期与 nonce
+func (p *Provider) verifyIDToken(ctx context.Context, meta *discovery, raw, nonce string) (*Claims, error) {
+	token, err := jwt.Parse(raw, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.signingKey(ctx, meta, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("verify id_token: %w", err)
+	}
+
+	mc, _ := token.Claims.(jwt.MapClaims)
+	if got, _ := mc["nonce"].(string); got != nonce {
+		return nil, fmt.Errorf("verify id_token: nonce mismatch")
+	}
+
+	claims := &Claims{Issuer: meta.Issuer}
+	claims.Subject, _ = mc["sub"].(string)
+	claims.Email, _ = mc["email"].(string)
+	claims.Name, _ = mc["name"].(string)
+	claims.PreferredUsername, _ = mc["preferred_username"].(string)
+	claims.Groups = lookupStrings(mc, p.cfg.GroupsClaim)
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("verify id_token: missing sub")
+	}
+	return claims, nil
+}
+
+// discover 获取（并缓存）Provider 元数据
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.metaMu.Lock()
+	defer p.metaMu.Unlock()
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	var meta discovery
+	wellKnown := strings.TrimSuffix(p.cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, wellKnown, &meta); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if strings.TrimSuffix(meta.Issuer, "/") != strings.TrimSuffix(p.cfg.IssuerURL, "/") {
+		return nil, fmt.Errorf("oidc discovery: issuer mismatch: %q != %q", meta.Issuer, p.cfg.IssuerURL)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery: incomplete provider metadata")
+	}
+	p.meta = &meta
+	log.Info("OIDC Provider 元数据已加载", "issuer", meta.Issuer)
+	return p.meta, nil
+}
+
+// getJSON GET 并解析 JSON
+func (p *Provider) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// lookupStrings 按点分路径读取字符串数组声明（兼容单个字符串）
+func lookupStrings(claims map[string]interface{}, path string) []string {
+	var cur interface{} = claims
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[part]
+	}
+
+	switch v := cur.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// randomString 生成 URL 安全的随机串
+func randomString() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
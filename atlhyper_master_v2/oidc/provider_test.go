@@ -0,0 +1,352 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/rbac"
+)
+
+// ==================== Mock: OIDC Provider ====================
+
+// mockIdP 本地 OIDC 服务：发现文档、JWKS、授权码换 Token（校验 PKCE）
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	codes  map[string]issuedCode
+	claims jwt.MapClaims // 下一次签发的 ID Token 附加声明
+}
+
+type issuedCode struct {
+	challenge string
+	nonce     string
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{key: key, codes: make(map[string]issuedCode)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		idp.mu.Lock()
+		issued, ok := idp.codes[r.Form.Get("code")]
+		delete(idp.codes, r.Form.Get("code"))
+		extra := idp.claims
+		idp.mu.Unlock()
+
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != issued.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		claims := jwt.MapClaims{
+			"iss":   idp.server.URL,
+			"aud":   "atlhyper",
+			"sub":   "user-123",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": issued.nonce,
+		}
+		for k, v := range extra {
+			claims[k] = v
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "test-key"
+		signed, _ := token.SignedString(key)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     signed,
+		})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// authorize 模拟用户在 IdP 完成登录：记录 PKCE challenge 并返回授权码
+func (idp *mockIdP) authorize(t *testing.T, authURL string) (code, state string) {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		t.Fatalf("auth URL missing PKCE challenge: %s", authURL)
+	}
+	code = "code-" + q.Get("state")
+	idp.mu.Lock()
+	idp.codes[code] = issuedCode{challenge: q.Get("code_challenge"), nonce: q.Get("nonce")}
+	idp.mu.Unlock()
+	return code, q.Get("state")
+}
+
+// ==================== Mock: Repositories ====================
+
+type memUsers struct {
+	nextID int64
+	users  map[int64]*database.User
+}
+
+func (m *memUsers) Create(ctx context.Context, u *database.User) error {
+	m.nextID++
+	u.ID = m.nextID
+	copied := *u
+	m.users[u.ID] = &copied
+	return nil
+}
+func (m *memUsers) Update(ctx context.Context, u *database.User) error {
+	copied := *u
+	m.users[u.ID] = &copied
+	return nil
+}
+func (m *memUsers) Delete(ctx context.Context, id int64) error { delete(m.users, id); return nil }
+func (m *memUsers) GetByID(ctx context.Context, id int64) (*database.User, error) {
+	if u, ok := m.users[id]; ok {
+		copied := *u
+		return &copied, nil
+	}
+	return nil, nil
+}
+func (m *memUsers) GetByUsername(ctx context.Context, name string) (*database.User, error) {
+	for _, u := range m.users {
+		if u.Username == name {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+func (m *memUsers) List(ctx context.Context) ([]*database.User, error)             { return nil, nil }
+func (m *memUsers) UpdateLastLogin(ctx context.Context, id int64, ip string) error { return nil }
+
+type memIdentities struct {
+	items []*database.UserIdentity
+}
+
+func (m *memIdentities) Create(ctx context.Context, i *database.UserIdentity) error {
+	i.ID = int64(len(m.items) + 1)
+	m.items = append(m.items, i)
+	return nil
+}
+func (m *memIdentities) Touch(ctx context.Context, id int64, email string) error { return nil }
+func (m *memIdentities) DeleteByUser(ctx context.Context, userID int64) error {
+	kept := m.items[:0]
+	for _, i := range m.items {
+		if i.UserID != userID {
+			kept = append(kept, i)
+		}
+	}
+	m.items = kept
+	return nil
+}
+func (m *memIdentities) GetBySubject(ctx context.Context, issuer, subject string) (*database.UserIdentity, error) {
+	for _, i := range m.items {
+		if i.Issuer == issuer && i.Subject == subject {
+			return i, nil
+		}
+	}
+	return nil, nil
+}
+
+// ==================== 辅助函数 ====================
+
+func newTestProvider(t *testing.T, idp *mockIdP, cfg Config) (*Provider, *memUsers) {
+	t.Helper()
+	cfg.IssuerURL = idp.server.URL
+	cfg.ClientID = "atlhyper"
+	cfg.RedirectURL = "http://localhost:3000/auth/oidc/callback"
+	users := &memUsers{users: map[int64]*database.User{
+		1: {ID: 1, Username: "alice", Role: rbac.RoleAdmin, Status: 1}, // 同名本地账号
+	}}
+	users.nextID = 1
+	return NewProvider(cfg, users, &memIdentities{}), users
+}
+
+func login(t *testing.T, p *Provider, idp *mockIdP) (*database.User, error) {
+	t.Helper()
+	authURL, _, err := p.AuthURL(context.Background())
+	if err != nil {
+		t.Fatalf("AuthURL: %v", err)
+	}
+	code, state := idp.authorize(t, authURL)
+	return p.Authenticate(context.Background(), code, state)
+}
+
+// ==================== Tests ====================
+
+func TestAuthenticate_ProvisionsUserWithMappedRole(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims = jwt.MapClaims{
+		"preferred_username": "alice",
+		"email":              "alice@example.com",
+		"name":               "Alice",
+		"groups":             []string{"dev", "platform-ops"},
+	}
+	p, users := newTestProvider(t, idp, Config{OperatorGroups: []string{"Platform-Ops"}, DefaultRole: rbac.RoleViewer})
+
+	user, err := login(t, p, idp)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	// 同名本地账号不被接管
+	if user.ID == 1 || user.Username != "alice-2" {
+		t.Fatalf("expected new user alice-2, got id=%d username=%q", user.ID, user.Username)
+	}
+	if user.Role != rbac.RoleOperator {
+		t.Errorf("role = %d, want %d", user.Role, rbac.RoleOperator)
+	}
+
+	// 再次登录复用同一用户，并按最新组同步角色
+	idp.claims["groups"] = []string{"dev"}
+	again, err := login(t, p, idp)
+	if err != nil {
+		t.Fatalf("second Authenticate: %v", err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("expected same user %d, got %d", user.ID, again.ID)
+	}
+	if users.users[user.ID].Role != rbac.RoleViewer {
+		t.Errorf("role after resync = %d, want %d", users.users[user.ID].Role, rbac.RoleViewer)
+	}
+}
+
+func TestAuthenticate_NestedGroupsClaim(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims = jwt.MapClaims{
+		"preferred_username": "bob",
+		"realm_access":       map[string]interface{}{"roles": []string{"atlhyper-admin"}},
+	}
+	p, _ := newTestProvider(t, idp, Config{GroupsClaim: "realm_access.roles", AdminGroups: []string{"atlhyper-admin"}})
+
+	user, err := login(t, p, idp)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if user.Role != rbac.RoleAdmin {
+		t.Errorf("role = %d, want %d", user.Role, rbac.RoleAdmin)
+	}
+}
+
+func TestAuthenticate_NoMappedGroup(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims = jwt.MapClaims{"groups": []string{"marketing"}}
+	p, _ := newTestProvider(t, idp, Config{ViewerGroups: []string{"engineering"}})
+
+	if _, err := login(t, p, idp); !errors.Is(err, ErrNoRole) {
+		t.Fatalf("expected ErrNoRole, got %v", err)
+	}
+}
+
+func TestAuthenticate_RejectsUnknownState(t *testing.T) {
+	idp := newMockIdP(t)
+	p, _ := newTestProvider(t, idp, Config{DefaultRole: rbac.RoleViewer})
+
+	authURL, _, err := p.AuthURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := idp.authorize(t, authURL)
+
+	if _, err := p.Authenticate(context.Background(), code, "forged"); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState for forged state, got %v", err)
+	}
+	if _, err := p.Authenticate(context.Background(), code, state); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	// state 只能使用一次
+	if _, err := p.Authenticate(context.Background(), code, state); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState on replay, got %v", err)
+	}
+}
+
+func TestAuthenticate_RejectsWrongVerifier(t *testing.T) {
+	idp := newMockIdP(t)
+	p, _ := newTestProvider(t, idp, Config{DefaultRole: rbac.RoleViewer})
+
+	authURL, _, err := p.AuthURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := idp.authorize(t, authURL)
+
+	// 篡改服务端保存的 verifier，模拟授权码被截获后由他人兑换
+	p.pendingMu.Lock()
+	pl := p.pending[state]
+	pl.verifier = "attacker-verifier-attacker-verifier-attacker"
+	p.pending[state] = pl
+	p.pendingMu.Unlock()
+
+	if _, err := p.Authenticate(context.Background(), code, state); err == nil {
+		t.Fatal("expected PKCE verification failure")
+	}
+}
+
+func TestMapRole(t *testing.T) {
+	p := NewProvider(Config{
+		AdminGroups:    []string{"admins"},
+		OperatorGroups: []string{"ops"},
+		ViewerGroups:   []string{"devs"},
+	}, nil, nil)
+
+	tests := []struct {
+		groups []string
+		want   int
+	}{
+		{[]string{"devs"}, rbac.RoleViewer},
+		{[]string{"devs", "ops"}, rbac.RoleOperator},
+		{[]string{"OPS", "Admins"}, rbac.RoleAdmin},
+		{[]string{"other"}, 0},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		if got := p.MapRole(tt.groups); got != tt.want {
+			t.Errorf("MapRole(%v) = %d, want %d", tt.groups, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// atlhyper_master_v2/oidc/types.go
+// OIDC 单点登录 — 配置与类型定义
+package oidc
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidState state 不存在或已过期（登录请求不是由本服务发起，或超时）
+	ErrInvalidState = errors.New("oidc: invalid or expired state")
+	// ErrNoRole IdP 账号不属于任何已映射的组，且未配置默认角色
+	ErrNoRole = errors.New("oidc: user is not a member of any mapped group")
+	// ErrUserDisabled 关联的本地账号已被禁用
+	ErrUserDisabled = errors.New("oidc: user disabled")
+)
+
+// Config OIDC 配置
+type Config struct {
+	IssuerURL    string   // IdP Issuer（用于发现 /.well-known/openid-configuration）
+	ClientID     string   // 客户端 ID
+	ClientSecret string   // 客户端密钥（公共客户端可为空，依赖 PKCE）
+	RedirectURL  string   // 回调地址（Web 回调页，由前端将 code/state 交给 Master）
+	Scopes       []string // 请求的 scope（openid 自动补齐）
+
+	// 组 → 角色映射
+	GroupsClaim    string   // 组声明名称，支持点分路径（如 realm_access.roles）
+	AdminGroups    []string // 映射为 Admin 的组
+	OperatorGroups []string // 映射为 Operator 的组
+	ViewerGroups   []string // 映射为 Viewer 的组
+	DefaultRole    int      // 未匹配任何组时的角色（0 = 拒绝登录）
+
+	StateTTL time.Duration // 登录请求有效期
+}
+
+// Claims ID Token 中登录所需的声明
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	Name              string
+	PreferredUsername string
+	Groups            []string
+}
+
+// discovery OpenID Provider 元数据（只取用到的字段）
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// pendingLogin 已发起、等待回调的登录请求
+type pendingLogin struct {
+	verifier  string // PKCE code_verifier
+	nonce     string
+	expiresAt time.Time
+}
@@ -20,6 +20,10 @@ type Processor interface {
 	// 接收 Agent 上报的快照，校验后写入 DataHub
 	ProcessSnapshot(clusterID string, snapshot *cluster.ClusterSnapshot) error
 
+	// ProcessSnapshotDelta 处理增量快照
+	// 在 DataHub 当前快照上应用增量；基准版本不一致时返回 cluster.ErrDeltaBaseMismatch
+	ProcessSnapshotDelta(clusterID string, delta *cluster.SnapshotDelta) error
+
 	// ProcessHeartbeat 处理心跳
 	ProcessHeartbeat(clusterID string) error
 }
@@ -67,7 +71,39 @@ func (p *processorImpl) ProcessSnapshot(clusterID string, snapshot *cluster.Clus
 		return fmt.Errorf("set snapshot: %w", err)
 	}
 
-	// 3. 检测资源数量变化，决定日志级别
+	p.afterStore(clusterID, snapshot)
+	return nil
+}
+
+// ProcessSnapshotDelta 处理增量快照
+func (p *processorImpl) ProcessSnapshotDelta(clusterID string, delta *cluster.SnapshotDelta) error {
+	if clusterID == "" {
+		return fmt.Errorf("cluster_id required")
+	}
+	if delta == nil {
+		return fmt.Errorf("delta required")
+	}
+	if delta.ClusterID != "" && delta.ClusterID != clusterID {
+		return fmt.Errorf("cluster_id mismatch: path=%s, body=%s", clusterID, delta.ClusterID)
+	}
+
+	snapshot, err := p.store.ApplySnapshotDelta(clusterID, delta)
+	if err != nil {
+		return fmt.Errorf("apply snapshot delta: %w", err)
+	}
+
+	log.Debug("增量快照已应用",
+		"cluster", clusterID,
+		"version", delta.Version,
+		"changes", len(delta.Changes),
+	)
+	p.afterStore(clusterID, snapshot)
+	return nil
+}
+
+// afterStore 快照写入后的通用处理：变化日志 + 持久化回调
+func (p *processorImpl) afterStore(clusterID string, snapshot *cluster.ClusterSnapshot) {
+	// 检测资源数量变化，决定日志级别
 	current := snapshotCounts{
 		pods:   len(snapshot.Pods),
 		nodes:  len(snapshot.Nodes),
@@ -99,12 +135,10 @@ func (p *processorImpl) ProcessSnapshot(clusterID string, snapshot *cluster.Clus
 		)
 	}
 
-	// 4. 触发回调（Event/Metrics/SLO 持久化）
+	// 触发回调（Event/Metrics/SLO 持久化）
 	if p.onSnapshotReceived != nil {
 		p.onSnapshotReceived(clusterID)
 	}
-
-	return nil
 }
 
 // ProcessHeartbeat 处理心跳
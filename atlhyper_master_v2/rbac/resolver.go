@@ -0,0 +1,78 @@
+// atlhyper_master_v2/rbac/resolver.go
+// 授权范围解析：从数据库加载用户的角色绑定（带短时缓存）
+package rbac
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// resolverCacheTTL 绑定缓存时长（绑定变更时主动失效，TTL 兜底多实例场景）
+const resolverCacheTTL = 30 * time.Second
+
+type cachedScope struct {
+	scope    *Scope
+	loadedAt time.Time
+}
+
+// Resolver 授权范围解析器
+type Resolver struct {
+	repo database.RoleBindingRepository
+
+	mu    sync.Mutex
+	cache map[int64]cachedScope
+}
+
+// NewResolver 创建 Resolver
+func NewResolver(repo database.RoleBindingRepository) *Resolver {
+	return &Resolver{
+		repo:  repo,
+		cache: make(map[int64]cachedScope),
+	}
+}
+
+// Resolve 解析用户的授权范围
+// Admin 和无绑定的用户返回 nil（不受限）
+func (r *Resolver) Resolve(ctx context.Context, userID int64, globalRole int) (*Scope, error) {
+	if r == nil || r.repo == nil || globalRole >= RoleAdmin {
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	if c, ok := r.cache[userID]; ok && time.Since(c.loadedAt) < resolverCacheTTL {
+		r.mu.Unlock()
+		return c.scope, nil
+	}
+	r.mu.Unlock()
+
+	rows, err := r.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var scope *Scope
+	if len(rows) > 0 {
+		scope = &Scope{UserID: userID, Bindings: make([]Binding, 0, len(rows))}
+		for _, b := range rows {
+			scope.Bindings = append(scope.Bindings, Binding{ClusterID: b.ClusterID, Namespace: b.Namespace, Role: b.Role})
+		}
+	}
+
+	r.mu.Lock()
+	r.cache[userID] = cachedScope{scope: scope, loadedAt: time.Now()}
+	r.mu.Unlock()
+	return scope, nil
+}
+
+// Invalidate 使用户的缓存失效（绑定变更后调用）
+func (r *Resolver) Invalidate(userID int64) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	delete(r.cache, userID)
+	r.mu.Unlock()
+}
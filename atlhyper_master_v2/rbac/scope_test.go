@@ -0,0 +1,78 @@
+package rbac
+
+import (
+	"context"
+	"testing"
+)
+
+func TestScope_Nil(t *testing.T) {
+	var s *Scope
+	if got := s.RoleFor("c1", "default"); got != RoleAdmin {
+		t.Errorf("nil scope RoleFor = %d, want %d", got, RoleAdmin)
+	}
+	if !s.AllowsCluster("any") {
+		t.Error("nil scope should allow every cluster")
+	}
+	if s.MaxRole() != 0 {
+		t.Errorf("nil scope MaxRole = %d, want 0", s.MaxRole())
+	}
+	if ScopeFrom(context.Background()) != nil {
+		t.Error("ScopeFrom on empty context should return nil")
+	}
+}
+
+func TestScope_RoleFor(t *testing.T) {
+	s := &Scope{Bindings: []Binding{
+		{ClusterID: "c1", Namespace: "team-a", Role: RoleOperator},
+		{ClusterID: "c1", Namespace: "team-b", Role: RoleViewer},
+		{ClusterID: "c2", Role: RoleViewer},
+	}}
+
+	tests := []struct {
+		cluster, namespace string
+		want               int
+	}{
+		{"c1", "team-a", RoleOperator},
+		{"c1", "team-b", RoleViewer},
+		{"c1", "team-c", 0},
+		{"c1", "", 0}, // 命名空间级绑定不覆盖集群级资源
+		{"c2", "anything", RoleViewer},
+		{"c2", "", RoleViewer},
+		{"c3", "team-a", 0},
+	}
+	for _, tt := range tests {
+		if got := s.RoleFor(tt.cluster, tt.namespace); got != tt.want {
+			t.Errorf("RoleFor(%q, %q) = %d, want %d", tt.cluster, tt.namespace, got, tt.want)
+		}
+	}
+
+	if !s.Allows("c1", "team-a", RoleOperator) {
+		t.Error("expected operator on c1/team-a")
+	}
+	if s.Allows("c1", "team-b", RoleOperator) {
+		t.Error("viewer binding must not allow operator actions")
+	}
+	if s.MaxRole() != RoleOperator {
+		t.Errorf("MaxRole = %d, want %d", s.MaxRole(), RoleOperator)
+	}
+}
+
+func TestScope_AllowsCluster(t *testing.T) {
+	s := &Scope{Bindings: []Binding{{ClusterID: "c1", Namespace: "team-a", Role: RoleViewer}}}
+	if !s.AllowsCluster("c1") {
+		t.Error("expected c1 allowed")
+	}
+	if s.AllowsCluster("c2") {
+		t.Error("expected c2 denied")
+	}
+
+	all := &Scope{Bindings: []Binding{{Role: RoleViewer}}}
+	if !all.AllowsCluster("c2") {
+		t.Error("binding without cluster should cover every cluster")
+	}
+
+	filter := s.NamespaceFilter("c1")
+	if !filter("team-a") || filter("team-b") || filter("") {
+		t.Error("NamespaceFilter should only allow team-a")
+	}
+}
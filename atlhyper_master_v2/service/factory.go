@@ -7,15 +7,18 @@ import (
 	"AtlHyper/atlhyper_master_v2/service/query"
 )
 
-// serviceImpl 组合 QueryService + CommandService + AdminService + SLOService
+// serviceImpl 组合 QueryService + CommandService + AdminService + SLOService + ExecService + OncallService + AIOpsService
 type serviceImpl struct {
 	*query.QueryService
 	*operations.CommandService
 	*operations.AdminService
 	*operations.SLOService
+	*operations.ExecService
+	*operations.OncallService
+	*operations.AIOpsService
 }
 
 // NewService 创建统一 Service 实例
-func NewService(q *query.QueryService, cmd *operations.CommandService, admin *operations.AdminService, slo *operations.SLOService) Service {
-	return &serviceImpl{QueryService: q, CommandService: cmd, AdminService: admin, SLOService: slo}
+func NewService(q *query.QueryService, cmd *operations.CommandService, admin *operations.AdminService, slo *operations.SLOService, exec *operations.ExecService, oncall *operations.OncallService, aiopsOps *operations.AIOpsService) Service {
+	return &serviceImpl{QueryService: q, CommandService: cmd, AdminService: admin, SLOService: slo, ExecService: exec, OncallService: oncall, AIOpsService: aiopsOps}
 }
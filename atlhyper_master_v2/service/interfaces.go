@@ -10,8 +10,13 @@ import (
 
 	"AtlHyper/atlhyper_master_v2/aiops"
 	"AtlHyper/atlhyper_master_v2/aiops/enricher"
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+	"AtlHyper/atlhyper_master_v2/aiops/policy"
+	"AtlHyper/atlhyper_master_v2/aiops/runbook"
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/notifier"
+	"AtlHyper/atlhyper_master_v2/stream"
 	"AtlHyper/model_v3/agent"
 	"AtlHyper/model_v3/cluster"
 	"AtlHyper/model_v3/command"
@@ -36,12 +41,18 @@ type QueryK8s interface {
 	GetStatefulSets(ctx context.Context, clusterID string, namespace string) ([]cluster.StatefulSet, error)
 	GetJobs(ctx context.Context, clusterID string, namespace string) ([]cluster.Job, error)
 	GetCronJobs(ctx context.Context, clusterID string, namespace string) ([]cluster.CronJob, error)
+	GetHPAs(ctx context.Context, clusterID string, namespace string) ([]cluster.HorizontalPodAutoscaler, error)
 	GetPersistentVolumes(ctx context.Context, clusterID string) ([]cluster.PersistentVolume, error)
 	GetPersistentVolumeClaims(ctx context.Context, clusterID string, namespace string) ([]cluster.PersistentVolumeClaim, error)
 	GetNetworkPolicies(ctx context.Context, clusterID string, namespace string) ([]cluster.NetworkPolicy, error)
 	GetResourceQuotas(ctx context.Context, clusterID string, namespace string) ([]cluster.ResourceQuota, error)
 	GetLimitRanges(ctx context.Context, clusterID string, namespace string) ([]cluster.LimitRange, error)
 	GetServiceAccounts(ctx context.Context, clusterID string, namespace string) ([]cluster.ServiceAccount, error)
+	// 快照历史（时间回溯）
+	GetSnapshotHistory(ctx context.Context, clusterID string) ([]time.Time, error)
+	DiffSnapshots(ctx context.Context, clusterID string, from, to time.Time) (*model.SnapshotDiff, error)
+	// ListHistorySnapshots 按时间升序返回 [from, to] 内保存的历史快照（离线回放导出用）
+	ListHistorySnapshots(ctx context.Context, clusterID string, from, to time.Time) ([]*cluster.ClusterSnapshot, error)
 }
 
 // QueryOTel OTel 快照/时间线查询
@@ -60,6 +71,8 @@ type QuerySLO interface {
 	GetSLORouteMappingByServiceKey(ctx context.Context, clusterID, serviceKey string) (*model.SLORouteMapping, error)
 	GetSLORouteMappingsByDomain(ctx context.Context, clusterID, domain string) ([]*model.SLORouteMapping, error)
 	GetSLOAllDomains(ctx context.Context, clusterID string) ([]string, error)
+	// SLO 燃烧率告警（触发中）
+	GetSLOBurnAlerts(ctx context.Context, clusterID string) ([]model.SLOBurnAlert, error)
 }
 
 // QueryAIOps AIOps 查询与 AI 增强
@@ -78,6 +91,17 @@ type QueryAIOps interface {
 	// AI 报告查询
 	ListAIReports(ctx context.Context, incidentID string) ([]*database.AIReport, error)
 	GetAIReport(ctx context.Context, id int64) (*database.AIReport, error)
+	// 事件复盘文档（未生成返回 nil）
+	GetAIOpsPostmortem(ctx context.Context, incidentID string) (*database.AIOpsPostmortem, error)
+	// Runbook 定义与执行记录（未配置 Runbook 时返回空）
+	ListAIOpsRunbooks(ctx context.Context) ([]*runbook.Runbook, error)
+	ListAIOpsRunbookRuns(ctx context.Context, opts database.RunbookRunQueryOpts) ([]*database.AIOpsRunbookRun, int64, error)
+	// AIOps 策略（当前版本 / 版本历史 / 叠加后的生效配置）
+	ListAIOpsPolicies(ctx context.Context) ([]*policy.Policy, error)
+	GetAIOpsPolicyHistory(ctx context.Context, name string) ([]*policy.Policy, error)
+	GetAIOpsEffectivePolicy(ctx context.Context, clusterID, entityKey string) (*policy.Effective, error)
+	// 维护窗口（含已结束，标注当前是否生效）
+	ListAIOpsMaintenanceWindows(ctx context.Context) ([]*maintenance.Window, error)
 }
 
 // QueryOverview 集群概览、Agent 状态、事件、单资源查询
@@ -110,6 +134,21 @@ type QueryAdmin interface {
 	// Notify
 	ListNotifyChannels(ctx context.Context) ([]*database.NotifyChannel, error)
 	GetNotifyChannelByType(ctx context.Context, channelType string) (*database.NotifyChannel, error)
+	GetNotifyChannel(ctx context.Context, id int64) (*database.NotifyChannel, error)
+	// Notify 路由 / 静默 / 抑制
+	ListNotifyRoutes(ctx context.Context) ([]*database.NotifyRoute, error)
+	GetNotifyRoute(ctx context.Context, id int64) (*database.NotifyRoute, error)
+	ListNotifySilences(ctx context.Context) ([]*database.NotifySilence, error)
+	GetNotifySilence(ctx context.Context, id int64) (*database.NotifySilence, error)
+	ListNotifyInhibitRules(ctx context.Context) ([]*database.NotifyInhibitRule, error)
+	GetNotifyInhibitRule(ctx context.Context, id int64) (*database.NotifyInhibitRule, error)
+	// Notify 投递记录
+	ListNotifyDeliveries(ctx context.Context, opts database.NotifyDeliveryQueryOpts) ([]*database.NotifyDelivery, error)
+	CountNotifyDeliveries(ctx context.Context, opts database.NotifyDeliveryQueryOpts) (int64, error)
+	GetNotifyDelivery(ctx context.Context, id int64) (*database.NotifyDelivery, error)
+	// Notify 自定义模板（覆盖内置模板）
+	ListNotifyTemplates(ctx context.Context) ([]*database.NotifyTemplate, error)
+	GetNotifyTemplate(ctx context.Context, name, variant string) (*database.NotifyTemplate, error)
 	// Settings
 	GetSetting(ctx context.Context, key string) (*database.Setting, error)
 	// AI Provider
@@ -121,12 +160,49 @@ type QueryAdmin interface {
 	ListAIRoleBudgets(ctx context.Context) ([]*database.AIRoleBudget, error)
 	// AI Reports (调用历史)
 	ListRecentAIReports(ctx context.Context, role string, limit, offset int) ([]*database.AIReport, int, error)
+	// Agent 凭证（只返回摘要信息，不含明文）
+	ListAgentTokens(ctx context.Context) ([]*database.AgentToken, error)
+	GetAgentToken(ctx context.Context, clusterID string) (*database.AgentToken, error)
+	// Exec 会话记录
+	ListExecSessions(ctx context.Context, opts database.ExecSessionQueryOpts) ([]*database.ExecSession, error)
+	GetExecSession(ctx context.Context, sessionID string) (*database.ExecSession, error)
+}
+
+// QueryOncall 值班表、升级策略与告警升级记录查询
+type QueryOncall interface {
+	ListOncallSchedules(ctx context.Context) ([]*database.OncallSchedule, error)
+	GetOncallSchedule(ctx context.Context, id int64) (*database.OncallSchedule, error)
+	ListOncallOverrides(ctx context.Context, scheduleID int64) ([]*database.OncallOverride, error)
+	GetOncallOverride(ctx context.Context, id int64) (*database.OncallOverride, error)
+	// CurrentOncall 计算 at 时刻的主 / 副值班（含替班）
+	CurrentOncall(ctx context.Context, scheduleID int64, at time.Time) (*notifier.OncallShift, error)
+	ListEscalationPolicies(ctx context.Context) ([]*database.EscalationPolicy, error)
+	GetEscalationPolicy(ctx context.Context, id int64) (*database.EscalationPolicy, error)
+	ListEscalations(ctx context.Context, opts database.EscalationQueryOpts) ([]*database.Escalation, error)
+	CountEscalations(ctx context.Context, opts database.EscalationQueryOpts) (int64, error)
+	GetEscalation(ctx context.Context, id int64) (*database.Escalation, error)
+	GetEscalationByToken(ctx context.Context, token string) (*database.Escalation, error)
 }
 
 // OpsAdmin 管理写入操作（通知渠道、设置、AI Provider）
 type OpsAdmin interface {
 	CreateNotifyChannel(ctx context.Context, ch *database.NotifyChannel) error
 	UpdateNotifyChannel(ctx context.Context, ch *database.NotifyChannel) error
+	DeleteNotifyChannel(ctx context.Context, id int64) error
+	CreateNotifyRoute(ctx context.Context, route *database.NotifyRoute) error
+	UpdateNotifyRoute(ctx context.Context, route *database.NotifyRoute) error
+	DeleteNotifyRoute(ctx context.Context, id int64) error
+	CreateNotifySilence(ctx context.Context, silence *database.NotifySilence) error
+	ExpireNotifySilence(ctx context.Context, id int64) error
+	CreateNotifyInhibitRule(ctx context.Context, rule *database.NotifyInhibitRule) error
+	UpdateNotifyInhibitRule(ctx context.Context, rule *database.NotifyInhibitRule) error
+	DeleteNotifyInhibitRule(ctx context.Context, id int64) error
+	// ResendNotifyDelivery 重新排队投递（由通知重试 Worker 发送）
+	ResendNotifyDelivery(ctx context.Context, id int64) error
+	// SaveNotifyTemplate 校验并保存自定义模板（校验失败返回 template.ErrInvalidTemplate）
+	SaveNotifyTemplate(ctx context.Context, t *database.NotifyTemplate) error
+	// DeleteNotifyTemplate 删除自定义模板，恢复内置模板
+	DeleteNotifyTemplate(ctx context.Context, name, variant string) error
 	SetSetting(ctx context.Context, setting *database.Setting) error
 	CreateAIProvider(ctx context.Context, p *database.AIProvider) error
 	UpdateAIProvider(ctx context.Context, p *database.AIProvider) error
@@ -134,6 +210,9 @@ type OpsAdmin interface {
 	UpdateAISettings(ctx context.Context, cfg *database.AISettings) error
 	UpdateAIProviderRoles(ctx context.Context, id int64, roles []string) error
 	UpdateAIRoleBudget(ctx context.Context, budget *database.AIRoleBudget) error
+	// Agent 凭证签发/轮换（明文 Token 仅在此返回一次）与吊销
+	IssueAgentToken(ctx context.Context, clusterID string, userID int64) (string, *database.AgentToken, error)
+	RevokeAgentToken(ctx context.Context, clusterID string) error
 }
 
 // ================================================================
@@ -148,6 +227,7 @@ type Query interface {
 	QueryAIOps
 	QueryOverview
 	QueryAdmin
+	QueryOncall
 }
 
 // OpsSLO SLO 写入操作
@@ -155,13 +235,68 @@ type OpsSLO interface {
 	UpsertSLOTarget(ctx context.Context, req *model.UpdateSLOTargetRequest) error
 }
 
+// OpsExec 交互式 exec 会话与日志跟踪
+type OpsExec interface {
+	// RunExecSession 阻塞运行会话直到结束，返回会话记录
+	RunExecSession(ctx context.Context, req *model.ExecSessionRequest, client stream.Conn) (*database.ExecSession, error)
+	// StreamLogs 阻塞跟踪容器日志直到客户端断开、来源结束或超时
+	StreamLogs(ctx context.Context, req *model.LogStreamRequest, client stream.Conn) (*stream.RelayResult, error)
+}
+
+// OpsOncall 值班表、升级策略写入与告警确认
+type OpsOncall interface {
+	CreateOncallSchedule(ctx context.Context, s *database.OncallSchedule) error
+	UpdateOncallSchedule(ctx context.Context, s *database.OncallSchedule) error
+	DeleteOncallSchedule(ctx context.Context, id int64) error
+	CreateOncallOverride(ctx context.Context, o *database.OncallOverride) error
+	DeleteOncallOverride(ctx context.Context, id int64) error
+	CreateEscalationPolicy(ctx context.Context, p *database.EscalationPolicy) error
+	UpdateEscalationPolicy(ctx context.Context, p *database.EscalationPolicy) error
+	DeleteEscalationPolicy(ctx context.Context, id int64) error
+	// AckEscalation 确认告警升级（仅未结束的升级生效，返回是否确认成功）
+	AckEscalation(ctx context.Context, id int64, by string) (bool, error)
+	// AckEscalationByToken 通过确认链接令牌确认（令牌无效返回 nil）
+	AckEscalationByToken(ctx context.Context, token string) (*database.Escalation, bool, error)
+}
+
+// OpsAIOps AIOps 事件人工处理、复盘、Runbook 与策略
+type OpsAIOps interface {
+	// ApplyAIOpsIncidentAction 执行确认/指派/评论/解决/误报动作（事件不存在返回 aiops.ErrIncidentNotFound）
+	ApplyAIOpsIncidentAction(ctx context.Context, incidentID string, action *aiops.IncidentAction) (*aiops.IncidentDetail, error)
+	// GenerateAIOpsPostmortem 生成事件复盘文档（覆盖已有文档）
+	GenerateAIOpsPostmortem(ctx context.Context, incidentID, by string) (*database.AIOpsPostmortem, error)
+	// UpdateAIOpsPostmortem 保存人工编辑的复盘文档（文档不存在返回 aiops.ErrPostmortemNotFound）
+	UpdateAIOpsPostmortem(ctx context.Context, incidentID, content, by string) (*database.AIOpsPostmortem, error)
+	// TriggerAIOpsRunbook 手动对事件执行 Runbook（dryRun 只解析步骤不下发指令）
+	TriggerAIOpsRunbook(ctx context.Context, name, incidentID, by string, dryRun bool) (*database.AIOpsRunbookRun, error)
+	// ApproveAIOpsRunbookRun 审批通过待执行的 Runbook（非待审批返回 runbook.ErrRunNotPending）
+	ApproveAIOpsRunbookRun(ctx context.Context, runID int64, by string) (*database.AIOpsRunbookRun, error)
+	// RejectAIOpsRunbookRun 拒绝待执行的 Runbook
+	RejectAIOpsRunbookRun(ctx context.Context, runID int64, by, reason string) (*database.AIOpsRunbookRun, error)
+	// SaveAIOpsPolicy 保存策略为新版本并热加载（基于的版本已过期返回 policy.ErrVersionConflict）
+	SaveAIOpsPolicy(ctx context.Context, p *policy.Policy, by string) (*policy.Policy, error)
+	// DeleteAIOpsPolicy 删除策略（保留历史，可回滚）
+	DeleteAIOpsPolicy(ctx context.Context, name, by string) error
+	// RollbackAIOpsPolicy 以历史版本内容创建新版本
+	RollbackAIOpsPolicy(ctx context.Context, name string, version int, by string) (*policy.Policy, error)
+	// CreateAIOpsMaintenanceWindow 创建维护窗口（校验失败返回 maintenance.ErrInvalidWindow）
+	CreateAIOpsMaintenanceWindow(ctx context.Context, w *maintenance.Window, by string) (*maintenance.Window, error)
+	// UpdateAIOpsMaintenanceWindow 修改维护窗口（不存在返回 maintenance.ErrWindowNotFound）
+	UpdateAIOpsMaintenanceWindow(ctx context.Context, w *maintenance.Window, by string) (*maintenance.Window, error)
+	// CloseAIOpsMaintenanceWindow 立即结束维护窗口（保留记录）
+	CloseAIOpsMaintenanceWindow(ctx context.Context, id int64) error
+}
+
 // Ops 写入操作接口
 type Ops interface {
-	CreateCommand(req *model.CreateCommandRequest) (*model.CreateCommandResponse, error)
+	CreateCommand(ctx context.Context, req *model.CreateCommandRequest) (*model.CreateCommandResponse, error)
 	// ExecuteCommandSync 同步执行指令（创建 + 等待 Agent 结果）
 	ExecuteCommandSync(ctx context.Context, req *model.CreateCommandRequest, timeout time.Duration) (*command.Result, error)
 	OpsAdmin
 	OpsSLO
+	OpsExec
+	OpsOncall
+	OpsAIOps
 }
 
 // Service 组合接口 (master.go 持有)
@@ -4,36 +4,76 @@ package operations
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/notifier/template"
+	"AtlHyper/common/crypto"
 )
 
+// defaultAgentTokenGrace Token 轮换后旧 Token 的默认宽限期
+const defaultAgentTokenGrace = time.Hour
+
 // AdminService 管理写入服务
 type AdminService struct {
 	notifyRepo     database.NotifyChannelRepository
+	routeRepo      database.NotifyRouteRepository
+	silenceRepo    database.NotifySilenceRepository
+	inhibitRepo    database.NotifyInhibitRuleRepository
+	deliveryRepo   database.NotifyDeliveryRepository
+	templateRepo   database.NotifyTemplateRepository
 	settingsRepo   database.SettingsRepository
 	aiProviderRepo database.AIProviderRepository
 	aiSettingsRepo database.AISettingsRepository
 	aiBudgetRepo   database.AIRoleBudgetRepository
+	agentTokenRepo database.AgentTokenRepository
+	tokenGrace     time.Duration
+
+	// onTemplateChange 自定义模板变更回调（通知告警管理器重新加载）
+	onTemplateChange func()
 }
 
 // NewAdminService 创建 AdminService
 func NewAdminService(
 	notifyRepo database.NotifyChannelRepository,
+	routeRepo database.NotifyRouteRepository,
+	silenceRepo database.NotifySilenceRepository,
+	inhibitRepo database.NotifyInhibitRuleRepository,
+	deliveryRepo database.NotifyDeliveryRepository,
+	templateRepo database.NotifyTemplateRepository,
 	settingsRepo database.SettingsRepository,
 	aiProviderRepo database.AIProviderRepository,
 	aiSettingsRepo database.AISettingsRepository,
 	aiBudgetRepo database.AIRoleBudgetRepository,
+	agentTokenRepo database.AgentTokenRepository,
 ) *AdminService {
 	return &AdminService{
 		notifyRepo:     notifyRepo,
+		routeRepo:      routeRepo,
+		silenceRepo:    silenceRepo,
+		inhibitRepo:    inhibitRepo,
+		deliveryRepo:   deliveryRepo,
+		templateRepo:   templateRepo,
 		settingsRepo:   settingsRepo,
 		aiProviderRepo: aiProviderRepo,
 		aiSettingsRepo: aiSettingsRepo,
 		aiBudgetRepo:   aiBudgetRepo,
+		agentTokenRepo: agentTokenRepo,
+		tokenGrace:     defaultAgentTokenGrace,
 	}
 }
 
+// SetAgentTokenGrace 设置 Token 轮换宽限期（<=0 表示轮换后旧 Token 立即失效）
+func (s *AdminService) SetAgentTokenGrace(grace time.Duration) {
+	s.tokenGrace = grace
+}
+
+// SetTemplateListener 设置自定义模板变更回调
+func (s *AdminService) SetTemplateListener(fn func()) {
+	s.onTemplateChange = fn
+}
+
 // ==================== Notify ====================
 
 func (s *AdminService) CreateNotifyChannel(ctx context.Context, ch *database.NotifyChannel) error {
@@ -44,6 +84,75 @@ func (s *AdminService) UpdateNotifyChannel(ctx context.Context, ch *database.Not
 	return s.notifyRepo.Update(ctx, ch)
 }
 
+func (s *AdminService) DeleteNotifyChannel(ctx context.Context, id int64) error {
+	return s.notifyRepo.Delete(ctx, id)
+}
+
+func (s *AdminService) CreateNotifyRoute(ctx context.Context, route *database.NotifyRoute) error {
+	return s.routeRepo.Create(ctx, route)
+}
+
+func (s *AdminService) UpdateNotifyRoute(ctx context.Context, route *database.NotifyRoute) error {
+	return s.routeRepo.Update(ctx, route)
+}
+
+func (s *AdminService) DeleteNotifyRoute(ctx context.Context, id int64) error {
+	return s.routeRepo.Delete(ctx, id)
+}
+
+func (s *AdminService) CreateNotifySilence(ctx context.Context, silence *database.NotifySilence) error {
+	return s.silenceRepo.Create(ctx, silence)
+}
+
+// ExpireNotifySilence 立即结束静默（保留记录）
+func (s *AdminService) ExpireNotifySilence(ctx context.Context, id int64) error {
+	return s.silenceRepo.Expire(ctx, id, time.Now())
+}
+
+func (s *AdminService) CreateNotifyInhibitRule(ctx context.Context, rule *database.NotifyInhibitRule) error {
+	return s.inhibitRepo.Create(ctx, rule)
+}
+
+func (s *AdminService) UpdateNotifyInhibitRule(ctx context.Context, rule *database.NotifyInhibitRule) error {
+	return s.inhibitRepo.Update(ctx, rule)
+}
+
+func (s *AdminService) DeleteNotifyInhibitRule(ctx context.Context, id int64) error {
+	return s.inhibitRepo.Delete(ctx, id)
+}
+
+// ResendNotifyDelivery 重置尝试次数并立即排队，由通知重试 Worker 发送
+func (s *AdminService) ResendNotifyDelivery(ctx context.Context, id int64) error {
+	return s.deliveryRepo.Requeue(ctx, id, time.Now())
+}
+
+// SaveNotifyTemplate 校验（解析 + 示例数据试渲染）后保存自定义模板
+func (s *AdminService) SaveNotifyTemplate(ctx context.Context, t *database.NotifyTemplate) error {
+	if err := template.Validate(t.Name, t.Variant, t.Body); err != nil {
+		return err
+	}
+	if err := s.templateRepo.Upsert(ctx, t); err != nil {
+		return err
+	}
+	s.templateChanged()
+	return nil
+}
+
+// DeleteNotifyTemplate 删除自定义模板，恢复内置模板
+func (s *AdminService) DeleteNotifyTemplate(ctx context.Context, name, variant string) error {
+	if err := s.templateRepo.Delete(ctx, name, variant); err != nil {
+		return err
+	}
+	s.templateChanged()
+	return nil
+}
+
+func (s *AdminService) templateChanged() {
+	if s.onTemplateChange != nil {
+		s.onTemplateChange()
+	}
+}
+
 // ==================== Settings ====================
 
 func (s *AdminService) SetSetting(ctx context.Context, setting *database.Setting) error {
@@ -77,3 +186,51 @@ func (s *AdminService) UpdateAIProviderRoles(ctx context.Context, id int64, role
 func (s *AdminService) UpdateAIRoleBudget(ctx context.Context, budget *database.AIRoleBudget) error {
 	return s.aiBudgetRepo.Upsert(ctx, budget)
 }
+
+// ==================== Agent Token ====================
+
+// IssueAgentToken 签发或轮换集群 Agent Token
+// 已有 Token 时，旧 Token 保留为 previous，在宽限期内仍可使用
+func (s *AdminService) IssueAgentToken(ctx context.Context, clusterID string, userID int64) (string, *database.AgentToken, error) {
+	if clusterID == "" {
+		return "", nil, fmt.Errorf("cluster_id is required")
+	}
+
+	existing, err := s.agentTokenRepo.GetByCluster(ctx, clusterID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	plain, err := crypto.GenerateAgentToken()
+	if err != nil {
+		return "", nil, fmt.Errorf("generate token: %w", err)
+	}
+
+	now := time.Now()
+	rec := &database.AgentToken{
+		ClusterID:   clusterID,
+		TokenHash:   crypto.HashToken(plain),
+		TokenPrefix: plain[:len(crypto.AgentTokenPrefix)+8],
+		CreatedBy:   userID,
+		CreatedAt:   now,
+		RotatedAt:   now,
+	}
+	if existing != nil {
+		rec.CreatedAt = existing.CreatedAt
+		if s.tokenGrace > 0 {
+			expires := now.Add(s.tokenGrace)
+			rec.PreviousHash = existing.TokenHash
+			rec.PreviousExpiresAt = &expires
+		}
+	}
+
+	if err := s.agentTokenRepo.Upsert(ctx, rec); err != nil {
+		return "", nil, err
+	}
+	return plain, rec, nil
+}
+
+// RevokeAgentToken 吊销集群 Agent Token（当前与宽限期内的旧 Token 同时失效）
+func (s *AdminService) RevokeAgentToken(ctx context.Context, clusterID string) error {
+	return s.agentTokenRepo.Delete(ctx, clusterID)
+}
@@ -0,0 +1,269 @@
+// atlhyper_master_v2/service/operations/aiops.go
+// AIOps 事件人工处理、复盘、Runbook、策略与维护窗口服务 — 校验授权范围后委托 AIOps 引擎 / 复盘生成器 / Runbook 管理器 / 策略管理器 / 维护窗口管理器执行
+package operations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+	"AtlHyper/atlhyper_master_v2/aiops/policy"
+	"AtlHyper/atlhyper_master_v2/aiops/runbook"
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/rbac"
+)
+
+// PostmortemGenerator 事件复盘文档生成器（由 aiops/postmortem 实现）
+type PostmortemGenerator interface {
+	Generate(ctx context.Context, incidentID, by string) (*database.AIOpsPostmortem, error)
+}
+
+// RunbookRunner Runbook 执行管理（由 aiops/runbook.Manager 实现）
+type RunbookRunner interface {
+	Trigger(ctx context.Context, name, incidentID, by string, dryRun bool) (*database.AIOpsRunbookRun, error)
+	GetRun(ctx context.Context, id int64) (*database.AIOpsRunbookRun, error)
+	Approve(ctx context.Context, id int64, by string) (*database.AIOpsRunbookRun, error)
+	Reject(ctx context.Context, id int64, by, reason string) (*database.AIOpsRunbookRun, error)
+}
+
+// PolicyStore AIOps 策略版本管理（由 aiops/policy.Manager 实现）
+type PolicyStore interface {
+	Save(ctx context.Context, p *policy.Policy, by string) (*policy.Policy, error)
+	Delete(ctx context.Context, name, by string) error
+	Rollback(ctx context.Context, name string, version int, by string) (*policy.Policy, error)
+}
+
+// MaintenanceStore 维护窗口管理（由 aiops/maintenance.Manager 实现）
+type MaintenanceStore interface {
+	Get(ctx context.Context, id int64) (*maintenance.Window, error)
+	Create(ctx context.Context, w *maintenance.Window, by string) (*maintenance.Window, error)
+	Update(ctx context.Context, w *maintenance.Window, by string) (*maintenance.Window, error)
+	Close(ctx context.Context, id int64, at time.Time) error
+}
+
+// AIOpsService AIOps 事件人工处理服务
+type AIOpsService struct {
+	engine aiops.Engine // 可选，nil = AIOps 未启用
+
+	// 复盘文档（可选，未设置时生成返回事件不存在）
+	postmortemGen  PostmortemGenerator
+	postmortemRepo database.AIOpsPostmortemRepository
+
+	runbooks RunbookRunner // 可选，nil = 未配置 Runbook
+	policies PolicyStore   // 可选，nil = 策略不可编辑
+
+	maintenance MaintenanceStore // 可选，nil = 维护窗口不可编辑
+}
+
+// NewAIOpsService 创建 AIOpsService
+func NewAIOpsService(engine aiops.Engine) *AIOpsService {
+	return &AIOpsService{engine: engine}
+}
+
+// SetPostmortem 设置复盘文档生成器与存储
+func (s *AIOpsService) SetPostmortem(gen PostmortemGenerator, repo database.AIOpsPostmortemRepository) {
+	s.postmortemGen = gen
+	s.postmortemRepo = repo
+}
+
+// SetRunbooks 设置 Runbook 执行管理器
+func (s *AIOpsService) SetRunbooks(r RunbookRunner) {
+	s.runbooks = r
+}
+
+// SetPolicies 设置 AIOps 策略管理器
+func (s *AIOpsService) SetPolicies(p PolicyStore) {
+	s.policies = p
+}
+
+// SetMaintenance 设置维护窗口管理器
+func (s *AIOpsService) SetMaintenance(m MaintenanceStore) {
+	s.maintenance = m
+}
+
+// ApplyAIOpsIncidentAction 执行事件人工处理动作
+// context 携带角色绑定范围时，根因实体超出 Operator 范围返回 rbac.ErrForbidden
+func (s *AIOpsService) ApplyAIOpsIncidentAction(ctx context.Context, incidentID string, action *aiops.IncidentAction) (*aiops.IncidentDetail, error) {
+	if err := s.checkIncidentScope(ctx, incidentID); err != nil {
+		return nil, err
+	}
+	return s.engine.ApplyIncidentAction(ctx, incidentID, action)
+}
+
+// GenerateAIOpsPostmortem 生成事件复盘文档
+func (s *AIOpsService) GenerateAIOpsPostmortem(ctx context.Context, incidentID, by string) (*database.AIOpsPostmortem, error) {
+	if s.postmortemGen == nil {
+		return nil, aiops.ErrIncidentNotFound
+	}
+	if err := s.checkIncidentScope(ctx, incidentID); err != nil {
+		return nil, err
+	}
+	return s.postmortemGen.Generate(ctx, incidentID, by)
+}
+
+// UpdateAIOpsPostmortem 保存人工编辑的复盘文档
+func (s *AIOpsService) UpdateAIOpsPostmortem(ctx context.Context, incidentID, content, by string) (*database.AIOpsPostmortem, error) {
+	if s.postmortemRepo == nil {
+		return nil, aiops.ErrPostmortemNotFound
+	}
+	if err := s.checkIncidentScope(ctx, incidentID); err != nil {
+		return nil, err
+	}
+	updated, err := s.postmortemRepo.UpdateContent(ctx, incidentID, content, by, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, aiops.ErrPostmortemNotFound
+	}
+	return s.postmortemRepo.Get(ctx, incidentID)
+}
+
+// TriggerAIOpsRunbook 手动对事件执行 Runbook
+func (s *AIOpsService) TriggerAIOpsRunbook(ctx context.Context, name, incidentID, by string, dryRun bool) (*database.AIOpsRunbookRun, error) {
+	if s.runbooks == nil {
+		return nil, runbook.ErrRunbookNotFound
+	}
+	if err := s.checkIncidentScope(ctx, incidentID); err != nil {
+		return nil, err
+	}
+	return s.runbooks.Trigger(ctx, name, incidentID, by, dryRun)
+}
+
+// ApproveAIOpsRunbookRun 审批通过待执行的 Runbook
+func (s *AIOpsService) ApproveAIOpsRunbookRun(ctx context.Context, runID int64, by string) (*database.AIOpsRunbookRun, error) {
+	if err := s.checkRunScope(ctx, runID); err != nil {
+		return nil, err
+	}
+	return s.runbooks.Approve(ctx, runID, by)
+}
+
+// RejectAIOpsRunbookRun 拒绝待执行的 Runbook
+func (s *AIOpsService) RejectAIOpsRunbookRun(ctx context.Context, runID int64, by, reason string) (*database.AIOpsRunbookRun, error) {
+	if err := s.checkRunScope(ctx, runID); err != nil {
+		return nil, err
+	}
+	return s.runbooks.Reject(ctx, runID, by, reason)
+}
+
+// SaveAIOpsPolicy 保存 AIOps 策略（新版本，保存后引擎即时生效）
+func (s *AIOpsService) SaveAIOpsPolicy(ctx context.Context, p *policy.Policy, by string) (*policy.Policy, error) {
+	if s.policies == nil {
+		return nil, policy.ErrPolicyNotFound
+	}
+	return s.policies.Save(ctx, p, by)
+}
+
+// DeleteAIOpsPolicy 删除 AIOps 策略
+func (s *AIOpsService) DeleteAIOpsPolicy(ctx context.Context, name, by string) error {
+	if s.policies == nil {
+		return policy.ErrPolicyNotFound
+	}
+	return s.policies.Delete(ctx, name, by)
+}
+
+// RollbackAIOpsPolicy 回滚 AIOps 策略到历史版本
+func (s *AIOpsService) RollbackAIOpsPolicy(ctx context.Context, name string, version int, by string) (*policy.Policy, error) {
+	if s.policies == nil {
+		return nil, policy.ErrPolicyNotFound
+	}
+	return s.policies.Rollback(ctx, name, version, by)
+}
+
+// CreateAIOpsMaintenanceWindow 创建维护窗口（窗口范围须在调用者的 Operator 范围内）
+func (s *AIOpsService) CreateAIOpsMaintenanceWindow(ctx context.Context, w *maintenance.Window, by string) (*maintenance.Window, error) {
+	if s.maintenance == nil {
+		return nil, maintenance.ErrWindowNotFound
+	}
+	if err := checkWindowScope(ctx, w); err != nil {
+		return nil, err
+	}
+	w.Source = maintenance.SourceManual
+	return s.maintenance.Create(ctx, w, by)
+}
+
+// UpdateAIOpsMaintenanceWindow 修改维护窗口（原范围与新范围均须在调用者的 Operator 范围内）
+func (s *AIOpsService) UpdateAIOpsMaintenanceWindow(ctx context.Context, w *maintenance.Window, by string) (*maintenance.Window, error) {
+	if s.maintenance == nil {
+		return nil, maintenance.ErrWindowNotFound
+	}
+	existing, err := s.maintenance.Get(ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWindowScope(ctx, existing); err != nil {
+		return nil, err
+	}
+	if err := checkWindowScope(ctx, w); err != nil {
+		return nil, err
+	}
+	return s.maintenance.Update(ctx, w, by)
+}
+
+// CloseAIOpsMaintenanceWindow 立即结束维护窗口（保留记录）
+func (s *AIOpsService) CloseAIOpsMaintenanceWindow(ctx context.Context, id int64) error {
+	if s.maintenance == nil {
+		return maintenance.ErrWindowNotFound
+	}
+	existing, err := s.maintenance.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkWindowScope(ctx, existing); err != nil {
+		return err
+	}
+	return s.maintenance.Close(ctx, id, time.Now())
+}
+
+// checkWindowScope 校验窗口范围在调用者的 Operator 范围内
+// 集群级 / 节点窗口需要集群级授权；命名空间通配须有同名绑定或集群级授权
+func checkWindowScope(ctx context.Context, w *maintenance.Window) error {
+	if !rbac.ScopeFrom(ctx).Allows(w.ClusterID, w.Namespace, rbac.RoleOperator) {
+		return fmt.Errorf("%w: maintenance window in %s/%s", rbac.ErrForbidden, w.ClusterID, w.Namespace)
+	}
+	return nil
+}
+
+// checkRunScope 校验执行记录存在且所属事件在调用者的 Operator 范围内
+func (s *AIOpsService) checkRunScope(ctx context.Context, runID int64) error {
+	if s.runbooks == nil {
+		return runbook.ErrRunNotFound
+	}
+	run, err := s.runbooks.GetRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	namespace := incidentNamespace(run.EntityKey)
+	if !rbac.ScopeFrom(ctx).Allows(run.ClusterID, namespace, rbac.RoleOperator) {
+		return fmt.Errorf("%w: runbook run %d in %s/%s", rbac.ErrForbidden, runID, run.ClusterID, namespace)
+	}
+	return nil
+}
+
+// checkIncidentScope 校验事件存在且根因实体在调用者的 Operator 范围内
+func (s *AIOpsService) checkIncidentScope(ctx context.Context, incidentID string) error {
+	if s.engine == nil {
+		return aiops.ErrIncidentNotFound
+	}
+	inc := s.engine.GetIncidentDetail(ctx, incidentID)
+	if inc == nil {
+		return aiops.ErrIncidentNotFound
+	}
+	namespace := incidentNamespace(inc.RootCause)
+	if !rbac.ScopeFrom(ctx).Allows(inc.ClusterID, namespace, rbac.RoleOperator) {
+		return fmt.Errorf("%w: incident %s in %s/%s", rbac.ErrForbidden, incidentID, inc.ClusterID, namespace)
+	}
+	return nil
+}
+
+// incidentNamespace 从根因实体 key 提取命名空间（集群级实体返回空）
+func incidentNamespace(entityKey string) string {
+	ns, _, _ := strings.Cut(entityKey, "/")
+	if ns == "_cluster" {
+		return ""
+	}
+	return ns
+}
@@ -14,6 +14,7 @@ import (
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/model"
 	"AtlHyper/atlhyper_master_v2/mq"
+	"AtlHyper/atlhyper_master_v2/rbac"
 	"AtlHyper/common/logger"
 	"AtlHyper/model_v3/command"
 )
@@ -35,11 +36,25 @@ func NewCommandService(bus mq.Producer, cmdRepo database.CommandHistoryRepositor
 }
 
 // CreateCommand 创建指令
-func (s *CommandService) CreateCommand(req *model.CreateCommandRequest) (*model.CreateCommandResponse, error) {
+// context 携带角色绑定范围时，目标集群 / 命名空间超出范围的指令返回 rbac.ErrForbidden
+func (s *CommandService) CreateCommand(ctx context.Context, req *model.CreateCommandRequest) (*model.CreateCommandResponse, error) {
 	// 1. 校验
 	if err := s.validateRequest(req); err != nil {
 		return nil, fmt.Errorf("validate request: %w", err)
 	}
+	scope, role := rbac.ScopeFrom(ctx), requiredRole(req.Action)
+	if !scope.Allows(req.ClusterID, scopeNamespace(req), role) {
+		return nil, fmt.Errorf("%w: %s %s/%s", rbac.ErrForbidden, req.Action, req.ClusterID, req.TargetNamespace)
+	}
+	// 动态查询的 Kind 是否为集群级仅 Agent 可知（CRD），无集群级授权时由 Agent 限定在目标命名空间内
+	if req.Action == command.ActionDynamic && !scope.Allows(req.ClusterID, "", role) {
+		params := make(map[string]interface{}, len(req.Params)+1)
+		for k, v := range req.Params {
+			params[k] = v
+		}
+		params["namespaced_only"] = true
+		req.Params = params
+	}
 
 	// 2. 生成指令 ID
 	commandID := uuid.New().String()
@@ -57,16 +72,7 @@ func (s *CommandService) CreateCommand(req *model.CreateCommandRequest) (*model.
 		CreatedAt: time.Now(),
 	}
 
-	// 4. 写入 MQ（按来源路由 topic）
-	topic := mq.TopicOps
-	if req.Source == "ai" {
-		topic = mq.TopicAI
-	}
-	if err := s.bus.EnqueueCommand(req.ClusterID, topic, cmd); err != nil {
-		return nil, fmt.Errorf("enqueue command: %w", err)
-	}
-
-	// 5. 持久化指令历史
+	// 4. 持久化指令历史（先于入队，Agent 上报结果时据此校验指令归属集群）
 	paramsJSON, _ := json.Marshal(req.Params)
 	history := &database.CommandHistory{
 		CommandID:       commandID,
@@ -84,12 +90,74 @@ func (s *CommandService) CreateCommand(req *model.CreateCommandRequest) (*model.
 		log.Error("指令历史持久化失败", "err", err)
 	}
 
+	// 5. 写入 MQ（按来源路由 topic）
+	topic := mq.TopicOps
+	if req.Source == "ai" {
+		topic = mq.TopicAI
+	}
+	if err := s.bus.EnqueueCommand(req.ClusterID, topic, cmd); err != nil {
+		history.Status = command.StatusFailed
+		history.ErrorMessage = err.Error()
+		_ = s.cmdRepo.Update(context.Background(), history)
+		return nil, fmt.Errorf("enqueue command: %w", err)
+	}
+
 	return &model.CreateCommandResponse{
 		CommandID: commandID,
 		Status:    "pending",
 	}, nil
 }
 
+// queryActions 只读查询类指令（Viewer 即可执行）
+var queryActions = map[string]bool{
+	command.ActionQueryTraces:      true,
+	command.ActionQueryTraceDetail: true,
+	command.ActionQueryLogs:        true,
+	command.ActionQueryMetrics:     true,
+	command.ActionQuerySLO:         true,
+}
+
+// nodeActions 作用于节点的指令（Agent 忽略命名空间）
+var nodeActions = map[string]bool{
+	command.ActionCordon:   true,
+	command.ActionUncordon: true,
+	command.ActionDrain:    true,
+}
+
+// clusterScopedKinds 集群级资源（命名空间不参与定位）
+var clusterScopedKinds = map[string]bool{
+	"Node":                           true,
+	"Namespace":                      true,
+	"PersistentVolume":               true,
+	"StorageClass":                   true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CustomResourceDefinition":       true,
+	"IngressClass":                   true,
+	"PriorityClass":                  true,
+	"RuntimeClass":                   true,
+	"MutatingWebhookConfiguration":   true,
+	"ValidatingWebhookConfiguration": true,
+	"APIService":                     true,
+}
+
+// scopeNamespace 权限校验使用的命名空间
+// 节点指令与集群级资源的 TargetNamespace 由调用方填写且不约束实际目标，按 "" 校验（仅集群级绑定可通过）
+func scopeNamespace(req *model.CreateCommandRequest) string {
+	if nodeActions[req.Action] || clusterScopedKinds[req.TargetKind] {
+		return ""
+	}
+	return req.TargetNamespace
+}
+
+// requiredRole 返回执行指令所需的最低角色
+func requiredRole(action string) int {
+	if queryActions[action] {
+		return rbac.RoleViewer
+	}
+	return rbac.RoleOperator
+}
+
 // validateRequest 校验请求
 func (s *CommandService) validateRequest(req *model.CreateCommandRequest) error {
 	if req.ClusterID == "" {
@@ -110,10 +178,13 @@ func (s *CommandService) validateRequest(req *model.CreateCommandRequest) error
 		command.ActionRestart:     true,
 		command.ActionDelete:      true,
 		command.ActionDeletePod:   true,
+		command.ActionExec:        true,
 		command.ActionCordon:      true,
 		command.ActionUncordon:    true,
+		command.ActionDrain:       true,
 		command.ActionUpdateImage: true,
 		command.ActionGetLogs:     true,
+		command.ActionStreamLogs:  true,
 	}
 	if needsTarget[req.Action] {
 		if req.TargetKind == "" || req.TargetName == "" {
@@ -126,7 +197,7 @@ func (s *CommandService) validateRequest(req *model.CreateCommandRequest) error
 
 // ExecuteCommandSync 创建指令并同步等待 Agent 执行结果
 func (s *CommandService) ExecuteCommandSync(ctx context.Context, req *model.CreateCommandRequest, timeout time.Duration) (*command.Result, error) {
-	resp, err := s.CreateCommand(req)
+	resp, err := s.CreateCommand(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("create command: %w", err)
 	}
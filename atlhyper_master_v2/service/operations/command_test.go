@@ -2,12 +2,14 @@ package operations
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/rbac"
 	"AtlHyper/model_v3/command"
 )
 
@@ -214,3 +216,95 @@ func TestExecuteCommandSync_WaitError(t *testing.T) {
 		t.Error("expected WaitCommandResult to be called")
 	}
 }
+
+func TestCreateCommand_OutOfScope(t *testing.T) {
+	// Arrange: 用户只在 test-cluster/team-a 拥有 Operator 绑定
+	producer := &mockProducer{}
+	svc := &CommandService{
+		bus:     producer,
+		cmdRepo: &mockCommandRepo{},
+	}
+	ctx := rbac.WithScope(context.Background(), &rbac.Scope{
+		UserID:   7,
+		Bindings: []rbac.Binding{{ClusterID: "test-cluster", Namespace: "team-a", Role: rbac.RoleOperator}},
+	})
+
+	// Act: 目标命名空间 default 不在授权范围内
+	_, err := svc.CreateCommand(ctx, validRequest())
+
+	// Assert
+	if !errors.Is(err, rbac.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got: %v", err)
+	}
+	if producer.enqueuedCmd != nil {
+		t.Error("out-of-scope command must not be enqueued")
+	}
+
+	// 授权命名空间内的指令正常下发
+	req := validRequest()
+	req.TargetNamespace = "team-a"
+	if _, err := svc.CreateCommand(ctx, req); err != nil {
+		t.Fatalf("expected in-scope command to succeed, got: %v", err)
+	}
+	if producer.enqueuedCmd == nil {
+		t.Error("expected in-scope command to be enqueued")
+	}
+}
+
+func TestCreateCommand_ClusterScopedTargets(t *testing.T) {
+	// Arrange: 用户只在 test-cluster/team-a 拥有 Operator 绑定
+	producer := &mockProducer{}
+	svc := &CommandService{
+		bus:     producer,
+		cmdRepo: &mockCommandRepo{},
+	}
+	nsCtx := rbac.WithScope(context.Background(), &rbac.Scope{
+		UserID:   7,
+		Bindings: []rbac.Binding{{ClusterID: "test-cluster", Namespace: "team-a", Role: rbac.RoleOperator}},
+	})
+
+	// Act & Assert: 填写授权命名空间也不能操作节点或集群级资源
+	forbidden := []*model.CreateCommandRequest{
+		{ClusterID: "test-cluster", Action: command.ActionDrain, TargetKind: "Node", TargetNamespace: "team-a", TargetName: "node-1"},
+		{ClusterID: "test-cluster", Action: command.ActionCordon, TargetKind: "Node", TargetNamespace: "team-a", TargetName: "node-1"},
+		{ClusterID: "test-cluster", Action: command.ActionDelete, TargetKind: "Namespace", TargetNamespace: "team-a", TargetName: "kube-system"},
+		{ClusterID: "test-cluster", Action: command.ActionDynamic, TargetNamespace: "",
+			Params: map[string]interface{}{"command": "list", "kind": "IngressRoute"}},
+	}
+	for _, req := range forbidden {
+		if _, err := svc.CreateCommand(nsCtx, req); !errors.Is(err, rbac.ErrForbidden) {
+			t.Errorf("%s %s: expected ErrForbidden, got: %v", req.Action, req.TargetKind, err)
+		}
+	}
+	if producer.enqueuedCmd != nil {
+		t.Fatal("out-of-scope command must not be enqueued")
+	}
+
+	// 命名空间内的动态查询下发时由 Agent 限定为命名空间级资源
+	params := map[string]interface{}{"command": "list", "kind": "IngressRoute"}
+	req := &model.CreateCommandRequest{ClusterID: "test-cluster", Action: command.ActionDynamic, TargetNamespace: "team-a", Params: params}
+	if _, err := svc.CreateCommand(nsCtx, req); err != nil {
+		t.Fatalf("expected namespaced dynamic query to succeed, got: %v", err)
+	}
+	if producer.enqueuedCmd.Params["namespaced_only"] != true {
+		t.Errorf("params = %v, want namespaced_only", producer.enqueuedCmd.Params)
+	}
+	if _, ok := params["namespaced_only"]; ok {
+		t.Error("caller params must not be modified")
+	}
+
+	// 集群级绑定可操作节点，动态查询不附加限制
+	clusterCtx := rbac.WithScope(context.Background(), &rbac.Scope{
+		UserID:   8,
+		Bindings: []rbac.Binding{{ClusterID: "test-cluster", Role: rbac.RoleOperator}},
+	})
+	if _, err := svc.CreateCommand(clusterCtx, forbidden[0]); err != nil {
+		t.Fatalf("cluster operator drain: %v", err)
+	}
+	if _, err := svc.CreateCommand(clusterCtx, forbidden[3]); err != nil {
+		t.Fatalf("cluster operator dynamic: %v", err)
+	}
+	if _, ok := producer.enqueuedCmd.Params["namespaced_only"]; ok {
+		t.Error("cluster-wide binding should not restrict dynamic queries")
+	}
+}
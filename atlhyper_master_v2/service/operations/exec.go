@@ -0,0 +1,195 @@
+// atlhyper_master_v2/service/operations/exec.go
+// ExecService 交互式 exec 会话与日志跟踪
+// 下发 ActionExec / ActionStreamLogs 指令 → 等待 Agent 回拨接入 → 转发帧 → 持久化 exec 会话记录
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/stream"
+	"AtlHyper/model_v3/command"
+)
+
+// exec 会话限制默认值
+const (
+	defaultExecMaxDuration   = 30 * time.Minute
+	defaultExecAttachTimeout = 30 * time.Second
+)
+
+// ExecService 交互式 exec 会话服务
+type ExecService struct {
+	cmd           *CommandService
+	hub           *stream.Hub
+	sessionRepo   database.ExecSessionRepository
+	maxDuration   time.Duration
+	attachTimeout time.Duration
+}
+
+// NewExecService 创建 ExecService
+func NewExecService(cmd *CommandService, hub *stream.Hub, sessionRepo database.ExecSessionRepository) *ExecService {
+	return &ExecService{
+		cmd:           cmd,
+		hub:           hub,
+		sessionRepo:   sessionRepo,
+		maxDuration:   defaultExecMaxDuration,
+		attachTimeout: defaultExecAttachTimeout,
+	}
+}
+
+// SetLimits 设置会话最长时长与等待 Agent 接入的超时（<=0 保持默认）
+func (s *ExecService) SetLimits(maxDuration, attachTimeout time.Duration) {
+	if maxDuration > 0 {
+		s.maxDuration = maxDuration
+	}
+	if attachTimeout > 0 {
+		s.attachTimeout = attachTimeout
+	}
+}
+
+// RunExecSession 运行一个交互式会话，阻塞直到会话结束
+//
+// client 为浏览器侧连接，会话结束时由 Relay 关闭。
+// Agent 接入前失败时向 client 发送 error 帧并返回错误（不产生会话记录）。
+func (s *ExecService) RunExecSession(ctx context.Context, req *model.ExecSessionRequest, client stream.Conn) (*database.ExecSession, error) {
+	if req.ClusterID == "" || req.Namespace == "" || req.Pod == "" {
+		return nil, s.fail(client, fmt.Errorf("cluster_id, namespace and pod required"))
+	}
+
+	// 1-2. 下发指令并等待 Agent 接入
+	sessionID := uuid.New().String()
+	agentConn, done, err := s.openAgentStream(ctx, sessionID, &model.CreateCommandRequest{
+		ClusterID:       req.ClusterID,
+		Action:          command.ActionExec,
+		TargetKind:      "Pod",
+		TargetNamespace: req.Namespace,
+		TargetName:      req.Pod,
+	}, command.ExecParams{
+		SessionID:      sessionID,
+		Container:      req.Container,
+		Command:        req.Command,
+		TTY:            req.TTY,
+		TimeoutSeconds: int(s.maxDuration / time.Second),
+	})
+	if err != nil {
+		return nil, s.fail(client, err)
+	}
+	defer done()
+
+	// 3. 转发直到会话结束
+	startedAt := time.Now()
+	runCtx, cancel := context.WithTimeout(ctx, s.maxDuration)
+	defer cancel()
+	transcript := stream.NewTranscript(stream.DefaultTranscriptLimit)
+	result := stream.Relay(runCtx, client, agentConn, transcript)
+
+	// 4. 持久化会话记录
+	commandJSON, _ := json.Marshal(req.Command)
+	transcriptJSON, _ := json.Marshal(transcript.Entries())
+	session := &database.ExecSession{
+		SessionID:  sessionID,
+		ClusterID:  req.ClusterID,
+		Namespace:  req.Namespace,
+		Pod:        req.Pod,
+		Container:  req.Container,
+		Command:    string(commandJSON),
+		UserID:     req.UserID,
+		Username:   req.Username,
+		StartedAt:  startedAt,
+		EndedAt:    time.Now(),
+		ExitCode:   result.ExitCode,
+		EndReason:  result.EndReason,
+		Transcript: string(transcriptJSON),
+		Truncated:  transcript.Truncated(),
+	}
+	if err := s.sessionRepo.Create(context.Background(), session); err != nil {
+		log.Error("exec 会话记录持久化失败", "session", sessionID, "err", err)
+	}
+
+	log.Info("exec 会话结束", "session", sessionID, "cluster", req.ClusterID,
+		"pod", req.Namespace+"/"+req.Pod, "user", req.Username, "reason", result.EndReason)
+	return session, nil
+}
+
+// StreamLogs 运行一个日志跟踪会话，阻塞直到会话结束
+//
+// 与 exec 共用回拨流通道，但只读、不录制也不持久化会话记录
+// （操作审计由 Gateway 审计中间件记录）。
+func (s *ExecService) StreamLogs(ctx context.Context, req *model.LogStreamRequest, client stream.Conn) (*stream.RelayResult, error) {
+	if req.ClusterID == "" || req.Namespace == "" || req.Name == "" {
+		return nil, s.fail(client, fmt.Errorf("cluster_id, namespace and name required"))
+	}
+	kind := req.Kind
+	if kind == "" {
+		kind = "Pod"
+	}
+	if kind != "Pod" && kind != "Deployment" {
+		return nil, s.fail(client, fmt.Errorf("unsupported kind for log streaming: %s", kind))
+	}
+
+	sessionID := uuid.New().String()
+	agentConn, done, err := s.openAgentStream(ctx, sessionID, &model.CreateCommandRequest{
+		ClusterID:       req.ClusterID,
+		Action:          command.ActionStreamLogs,
+		TargetKind:      kind,
+		TargetNamespace: req.Namespace,
+		TargetName:      req.Name,
+	}, command.LogStreamParams{
+		SessionID:      sessionID,
+		Container:      req.Container,
+		TailLines:      req.TailLines,
+		SinceSeconds:   req.SinceSeconds,
+		TimeoutSeconds: int(s.maxDuration / time.Second),
+	})
+	if err != nil {
+		return nil, s.fail(client, err)
+	}
+	defer done()
+
+	runCtx, cancel := context.WithTimeout(ctx, s.maxDuration)
+	defer cancel()
+	result := stream.Relay(runCtx, client, agentConn, nil)
+
+	log.Info("日志跟踪结束", "session", sessionID, "cluster", req.ClusterID,
+		"target", kind+"/"+req.Namespace+"/"+req.Name, "user", req.Username, "reason", result.EndReason)
+	return result, nil
+}
+
+// openAgentStream 下发流式指令并等待 Agent 回拨接入
+//
+// params 序列化后作为指令参数；返回的 done 在会话结束后调用，释放 Agent 侧连接。
+func (s *ExecService) openAgentStream(ctx context.Context, sessionID string, req *model.CreateCommandRequest, params any) (stream.Conn, func(), error) {
+	waiter := s.hub.Expect(sessionID, req.ClusterID)
+
+	paramsJSON, _ := json.Marshal(params)
+	var paramsMap map[string]interface{}
+	_ = json.Unmarshal(paramsJSON, &paramsMap)
+	req.Params = paramsMap
+	req.Source = "web"
+
+	if _, err := s.cmd.CreateCommand(ctx, req); err != nil {
+		waiter.Done()
+		return nil, nil, err
+	}
+
+	attachCtx, cancel := context.WithTimeout(ctx, s.attachTimeout)
+	defer cancel()
+	conn, err := waiter.Wait(attachCtx)
+	if err != nil {
+		waiter.Done()
+		return nil, nil, fmt.Errorf("agent did not attach to session: %w", err)
+	}
+	return conn, waiter.Done, nil
+}
+
+// fail 通知客户端会话建立失败
+func (s *ExecService) fail(client stream.Conn, err error) error {
+	client.WriteFrame(&command.ExecFrame{Type: command.FrameError, Error: err.Error()})
+	return err
+}
@@ -0,0 +1,238 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/stream"
+	"AtlHyper/model_v3/command"
+)
+
+// ==================== Mock: stream.Conn ====================
+
+type memConn struct {
+	in     chan *command.ExecFrame
+	mu     sync.Mutex
+	out    []*command.ExecFrame
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newMemConn() *memConn {
+	return &memConn{in: make(chan *command.ExecFrame, 16), closed: make(chan struct{})}
+}
+
+func (c *memConn) ReadFrame() (*command.ExecFrame, error) {
+	select {
+	case f := <-c.in:
+		return f, nil
+	case <-c.closed:
+		return nil, io.EOF
+	}
+}
+
+func (c *memConn) WriteFrame(f *command.ExecFrame) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.out = append(c.out, f)
+	return nil
+}
+
+func (c *memConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *memConn) written() []*command.ExecFrame {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*command.ExecFrame(nil), c.out...)
+}
+
+// ==================== Mock: database.ExecSessionRepository ====================
+
+type mockExecSessionRepo struct {
+	created []*database.ExecSession
+}
+
+func (m *mockExecSessionRepo) Create(ctx context.Context, s *database.ExecSession) error {
+	m.created = append(m.created, s)
+	return nil
+}
+
+func (m *mockExecSessionRepo) GetBySessionID(ctx context.Context, sessionID string) (*database.ExecSession, error) {
+	return nil, nil
+}
+
+func (m *mockExecSessionRepo) List(ctx context.Context, opts database.ExecSessionQueryOpts) ([]*database.ExecSession, error) {
+	return nil, nil
+}
+
+// agentProducer 入队 exec 指令时模拟 Agent 回拨接入
+type agentProducer struct {
+	mockProducer
+	hub   *stream.Hub
+	agent *memConn
+}
+
+func (p *agentProducer) EnqueueCommand(clusterID, topic string, cmd *command.Command) error {
+	p.mockProducer.EnqueueCommand(clusterID, topic, cmd)
+	if p.agent != nil {
+		sessionID, _ := cmd.Params["sessionId"].(string)
+		go p.hub.Attach(context.Background(), sessionID, clusterID, p.agent)
+	}
+	return nil
+}
+
+func execRequest() *model.ExecSessionRequest {
+	return &model.ExecSessionRequest{
+		ClusterID: "cluster-1",
+		Namespace: "default",
+		Pod:       "web-1",
+		Container: "app",
+		Command:   []string{"/bin/bash"},
+		TTY:       true,
+		UserID:    7,
+		Username:  "alice",
+	}
+}
+
+func TestRunExecSession_Success(t *testing.T) {
+	hub := stream.NewHub()
+	agent := newMemConn()
+	agent.in <- &command.ExecFrame{Type: command.FrameStdout, Data: "root@web-1:/# "}
+	agent.in <- &command.ExecFrame{Type: command.FrameExit, Code: 0}
+
+	producer := &agentProducer{hub: hub, agent: agent}
+	repo := &mockExecSessionRepo{}
+	svc := NewExecService(NewCommandService(producer, &mockCommandRepo{}), hub, repo)
+	svc.SetLimits(time.Minute, time.Second)
+
+	client := newMemConn()
+	session, err := svc.RunExecSession(context.Background(), execRequest(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cmd := producer.enqueuedCmd
+	if cmd == nil || cmd.Action != command.ActionExec || cmd.Kind != "Pod" || cmd.Name != "web-1" {
+		t.Fatalf("unexpected command: %+v", cmd)
+	}
+	if cmd.Params["container"] != "app" || cmd.Params["tty"] != true {
+		t.Errorf("unexpected params: %v", cmd.Params)
+	}
+	if secs, _ := cmd.Params["timeoutSeconds"].(float64); secs != 60 {
+		t.Errorf("timeoutSeconds: got %v, want 60", cmd.Params["timeoutSeconds"])
+	}
+
+	if session.EndReason != stream.EndExit || session.ExitCode == nil || *session.ExitCode != 0 {
+		t.Errorf("unexpected session end: %+v", session)
+	}
+	if len(repo.created) != 1 || repo.created[0].Username != "alice" {
+		t.Fatalf("expected session persisted, got %+v", repo.created)
+	}
+	var transcript []stream.TranscriptEntry
+	if err := json.Unmarshal([]byte(repo.created[0].Transcript), &transcript); err != nil || len(transcript) != 1 {
+		t.Errorf("unexpected transcript %q: %v", repo.created[0].Transcript, err)
+	}
+	if w := client.written(); len(w) != 2 || w[1].Type != command.FrameExit {
+		t.Errorf("client frames: %+v", w)
+	}
+}
+
+func TestRunExecSession_AgentNeverAttaches(t *testing.T) {
+	hub := stream.NewHub()
+	repo := &mockExecSessionRepo{}
+	svc := NewExecService(NewCommandService(&agentProducer{hub: hub}, &mockCommandRepo{}), hub, repo)
+	svc.SetLimits(time.Minute, 20*time.Millisecond)
+
+	client := newMemConn()
+	if _, err := svc.RunExecSession(context.Background(), execRequest(), client); err == nil {
+		t.Fatal("expected error when agent does not attach")
+	}
+	if len(repo.created) != 0 {
+		t.Error("no session should be persisted before agent attaches")
+	}
+	w := client.written()
+	if len(w) != 1 || w[0].Type != command.FrameError || !strings.Contains(w[0].Error, "attach") {
+		t.Errorf("client should receive an error frame, got %+v", w)
+	}
+}
+
+func TestRunExecSession_EnqueueFailure(t *testing.T) {
+	hub := stream.NewHub()
+	producer := &mockProducer{enqueueErr: errors.New("queue full")}
+	svc := NewExecService(NewCommandService(producer, &mockCommandRepo{}), hub, &mockExecSessionRepo{})
+
+	client := newMemConn()
+	if _, err := svc.RunExecSession(context.Background(), execRequest(), client); err == nil {
+		t.Fatal("expected error on enqueue failure")
+	}
+	if w := client.written(); len(w) != 1 || w[0].Type != command.FrameError {
+		t.Errorf("client should receive an error frame, got %+v", w)
+	}
+}
+
+func TestStreamLogs_Deployment(t *testing.T) {
+	hub := stream.NewHub()
+	agent := newMemConn()
+	agent.in <- &command.ExecFrame{Type: command.FrameLog, Source: "web-1/app", Data: "hello"}
+	agent.in <- &command.ExecFrame{Type: command.FrameExit}
+
+	producer := &agentProducer{hub: hub, agent: agent}
+	repo := &mockExecSessionRepo{}
+	svc := NewExecService(NewCommandService(producer, &mockCommandRepo{}), hub, repo)
+	svc.SetLimits(time.Minute, time.Second)
+
+	client := newMemConn()
+	result, err := svc.StreamLogs(context.Background(), &model.LogStreamRequest{
+		ClusterID: "cluster-1",
+		Kind:      "Deployment",
+		Namespace: "default",
+		Name:      "web",
+		TailLines: 20,
+	}, client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cmd := producer.enqueuedCmd
+	if cmd == nil || cmd.Action != command.ActionStreamLogs || cmd.Kind != "Deployment" || cmd.Name != "web" {
+		t.Fatalf("unexpected command: %+v", cmd)
+	}
+	if lines, _ := cmd.Params["tailLines"].(float64); lines != 20 {
+		t.Errorf("tailLines: got %v, want 20", cmd.Params["tailLines"])
+	}
+	if result.EndReason != stream.EndExit {
+		t.Errorf("end reason: got %s, want exit", result.EndReason)
+	}
+	if len(repo.created) != 0 {
+		t.Error("log streams should not be persisted as exec sessions")
+	}
+	if w := client.written(); len(w) != 2 || w[0].Type != command.FrameLog || w[0].Data != "hello" {
+		t.Errorf("client frames: %+v", w)
+	}
+}
+
+func TestStreamLogs_InvalidKind(t *testing.T) {
+	hub := stream.NewHub()
+	producer := &agentProducer{hub: hub}
+	svc := NewExecService(NewCommandService(producer, &mockCommandRepo{}), hub, &mockExecSessionRepo{})
+
+	client := newMemConn()
+	req := &model.LogStreamRequest{ClusterID: "c", Kind: "Service", Namespace: "default", Name: "web"}
+	if _, err := svc.StreamLogs(context.Background(), req, client); err == nil {
+		t.Fatal("expected error for unsupported kind")
+	}
+	if producer.enqueuedCmd != nil {
+		t.Error("no command should be enqueued for unsupported kind")
+	}
+}
@@ -0,0 +1,93 @@
+// atlhyper_master_v2/service/operations/oncall.go
+// 值班写入服务 — 值班表、替班、升级策略与告警确认
+package operations
+
+import (
+	"context"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// ackByLink 通过确认链接确认时记录的确认人
+const ackByLink = "link"
+
+// OncallService 值班写入服务
+type OncallService struct {
+	scheduleRepo   database.OncallScheduleRepository
+	overrideRepo   database.OncallOverrideRepository
+	policyRepo     database.EscalationPolicyRepository
+	escalationRepo database.EscalationRepository
+}
+
+// NewOncallService 创建 OncallService
+func NewOncallService(
+	scheduleRepo database.OncallScheduleRepository,
+	overrideRepo database.OncallOverrideRepository,
+	policyRepo database.EscalationPolicyRepository,
+	escalationRepo database.EscalationRepository,
+) *OncallService {
+	return &OncallService{
+		scheduleRepo:   scheduleRepo,
+		overrideRepo:   overrideRepo,
+		policyRepo:     policyRepo,
+		escalationRepo: escalationRepo,
+	}
+}
+
+func (s *OncallService) CreateOncallSchedule(ctx context.Context, schedule *database.OncallSchedule) error {
+	return s.scheduleRepo.Create(ctx, schedule)
+}
+
+func (s *OncallService) UpdateOncallSchedule(ctx context.Context, schedule *database.OncallSchedule) error {
+	return s.scheduleRepo.Update(ctx, schedule)
+}
+
+func (s *OncallService) DeleteOncallSchedule(ctx context.Context, id int64) error {
+	return s.scheduleRepo.Delete(ctx, id)
+}
+
+func (s *OncallService) CreateOncallOverride(ctx context.Context, o *database.OncallOverride) error {
+	return s.overrideRepo.Create(ctx, o)
+}
+
+func (s *OncallService) DeleteOncallOverride(ctx context.Context, id int64) error {
+	return s.overrideRepo.Delete(ctx, id)
+}
+
+func (s *OncallService) CreateEscalationPolicy(ctx context.Context, p *database.EscalationPolicy) error {
+	return s.policyRepo.Create(ctx, p)
+}
+
+func (s *OncallService) UpdateEscalationPolicy(ctx context.Context, p *database.EscalationPolicy) error {
+	return s.policyRepo.Update(ctx, p)
+}
+
+func (s *OncallService) DeleteEscalationPolicy(ctx context.Context, id int64) error {
+	return s.policyRepo.Delete(ctx, id)
+}
+
+// AckEscalation 确认告警升级，停止后续升级步骤
+func (s *OncallService) AckEscalation(ctx context.Context, id int64, by string) (bool, error) {
+	return s.escalationRepo.Ack(ctx, id, by, time.Now())
+}
+
+// AckEscalationByToken 通过确认链接确认
+// 返回升级记录（令牌无效为 nil）与本次是否确认成功（已确认 / 已恢复时为 false）
+func (s *OncallService) AckEscalationByToken(ctx context.Context, token string) (*database.Escalation, bool, error) {
+	e, err := s.escalationRepo.GetByToken(ctx, token)
+	if err != nil || e == nil {
+		return nil, false, err
+	}
+	acked, err := s.escalationRepo.Ack(ctx, e.ID, ackByLink, time.Now())
+	if err != nil {
+		return nil, false, err
+	}
+	if acked {
+		e, err = s.escalationRepo.GetByID(ctx, e.ID)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return e, acked, nil
+}
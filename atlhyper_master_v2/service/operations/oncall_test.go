@@ -0,0 +1,116 @@
+package operations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+)
+
+// ==================== Mock: database.EscalationRepository ====================
+
+type mockEscalationRepo struct {
+	byID map[int64]*database.Escalation
+}
+
+func (m *mockEscalationRepo) Create(ctx context.Context, e *database.Escalation) error { return nil }
+func (m *mockEscalationRepo) UpdateProgress(ctx context.Context, e *database.Escalation) error {
+	return nil
+}
+func (m *mockEscalationRepo) GetByID(ctx context.Context, id int64) (*database.Escalation, error) {
+	if e, ok := m.byID[id]; ok {
+		cp := *e
+		return &cp, nil
+	}
+	return nil, nil
+}
+func (m *mockEscalationRepo) GetByToken(ctx context.Context, token string) (*database.Escalation, error) {
+	for _, e := range m.byID {
+		if e.AckToken == token {
+			cp := *e
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockEscalationRepo) GetOpen(ctx context.Context, policyID int64, alertKey string) (*database.Escalation, error) {
+	return nil, nil
+}
+func (m *mockEscalationRepo) List(ctx context.Context, opts database.EscalationQueryOpts) ([]*database.Escalation, error) {
+	return nil, nil
+}
+func (m *mockEscalationRepo) Count(ctx context.Context, opts database.EscalationQueryOpts) (int64, error) {
+	return 0, nil
+}
+func (m *mockEscalationRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]*database.Escalation, error) {
+	return nil, nil
+}
+func (m *mockEscalationRepo) Ack(ctx context.Context, id int64, by string, at time.Time) (bool, error) {
+	e, ok := m.byID[id]
+	if !ok || e.Status != database.EscalationOpen {
+		return false, nil
+	}
+	e.Status = database.EscalationAcked
+	e.AckedBy = by
+	e.AckedAt = &at
+	e.NextAt = nil
+	return true, nil
+}
+func (m *mockEscalationRepo) ResolveAlert(ctx context.Context, alertKey string, at time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockEscalationRepo) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// ==================== 测试用例 ====================
+
+func TestAckEscalationByToken(t *testing.T) {
+	repo := &mockEscalationRepo{byID: map[int64]*database.Escalation{
+		1: {ID: 1, Status: database.EscalationOpen, AckToken: "tok-1"},
+		2: {ID: 2, Status: database.EscalationResolved, AckToken: "tok-2"},
+	}}
+	svc := NewOncallService(nil, nil, nil, repo)
+	ctx := context.Background()
+
+	e, acked, err := svc.AckEscalationByToken(ctx, "tok-1")
+	if err != nil || !acked {
+		t.Fatalf("expected ack, got acked=%v err=%v", acked, err)
+	}
+	if e.Status != database.EscalationAcked || e.AckedBy != ackByLink || e.AckedAt == nil {
+		t.Errorf("unexpected escalation after ack: %+v", e)
+	}
+
+	// 重复确认不再更新
+	if _, acked, _ := svc.AckEscalationByToken(ctx, "tok-1"); acked {
+		t.Error("second ack should not update")
+	}
+
+	// 已恢复的升级不可确认
+	e, acked, _ = svc.AckEscalationByToken(ctx, "tok-2")
+	if acked || e == nil || e.Status != database.EscalationResolved {
+		t.Errorf("resolved escalation: acked=%v e=%+v", acked, e)
+	}
+
+	// 无效令牌
+	e, acked, err = svc.AckEscalationByToken(ctx, "missing")
+	if e != nil || acked || err != nil {
+		t.Errorf("invalid token: e=%+v acked=%v err=%v", e, acked, err)
+	}
+}
+
+func TestAckEscalation_ByUser(t *testing.T) {
+	repo := &mockEscalationRepo{byID: map[int64]*database.Escalation{
+		1: {ID: 1, Status: database.EscalationOpen},
+	}}
+	svc := NewOncallService(nil, nil, nil, repo)
+
+	acked, err := svc.AckEscalation(context.Background(), 1, "alice")
+	if err != nil || !acked {
+		t.Fatalf("expected ack, got acked=%v err=%v", acked, err)
+	}
+	if repo.byID[1].AckedBy != "alice" {
+		t.Errorf("AckedBy = %q, want alice", repo.byID[1].AckedBy)
+	}
+}
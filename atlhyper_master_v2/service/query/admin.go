@@ -48,6 +48,54 @@ func (q *QueryService) GetNotifyChannelByType(ctx context.Context, channelType s
 	return q.notifyRepo.GetByType(ctx, channelType)
 }
 
+func (q *QueryService) GetNotifyChannel(ctx context.Context, id int64) (*database.NotifyChannel, error) {
+	return q.notifyRepo.GetByID(ctx, id)
+}
+
+func (q *QueryService) ListNotifyRoutes(ctx context.Context) ([]*database.NotifyRoute, error) {
+	return q.notifyRouteRepo.List(ctx)
+}
+
+func (q *QueryService) GetNotifyRoute(ctx context.Context, id int64) (*database.NotifyRoute, error) {
+	return q.notifyRouteRepo.GetByID(ctx, id)
+}
+
+func (q *QueryService) ListNotifySilences(ctx context.Context) ([]*database.NotifySilence, error) {
+	return q.notifySilenceRepo.List(ctx)
+}
+
+func (q *QueryService) GetNotifySilence(ctx context.Context, id int64) (*database.NotifySilence, error) {
+	return q.notifySilenceRepo.GetByID(ctx, id)
+}
+
+func (q *QueryService) ListNotifyInhibitRules(ctx context.Context) ([]*database.NotifyInhibitRule, error) {
+	return q.notifyInhibitRepo.List(ctx)
+}
+
+func (q *QueryService) GetNotifyInhibitRule(ctx context.Context, id int64) (*database.NotifyInhibitRule, error) {
+	return q.notifyInhibitRepo.GetByID(ctx, id)
+}
+
+func (q *QueryService) ListNotifyDeliveries(ctx context.Context, opts database.NotifyDeliveryQueryOpts) ([]*database.NotifyDelivery, error) {
+	return q.notifyDeliveryRepo.List(ctx, opts)
+}
+
+func (q *QueryService) CountNotifyDeliveries(ctx context.Context, opts database.NotifyDeliveryQueryOpts) (int64, error) {
+	return q.notifyDeliveryRepo.Count(ctx, opts)
+}
+
+func (q *QueryService) GetNotifyDelivery(ctx context.Context, id int64) (*database.NotifyDelivery, error) {
+	return q.notifyDeliveryRepo.GetByID(ctx, id)
+}
+
+func (q *QueryService) ListNotifyTemplates(ctx context.Context) ([]*database.NotifyTemplate, error) {
+	return q.notifyTemplateRepo.List(ctx)
+}
+
+func (q *QueryService) GetNotifyTemplate(ctx context.Context, name, variant string) (*database.NotifyTemplate, error) {
+	return q.notifyTemplateRepo.Get(ctx, name, variant)
+}
+
 // ==================== Settings ====================
 
 func (q *QueryService) GetSetting(ctx context.Context, key string) (*database.Setting, error) {
@@ -83,3 +131,23 @@ func (q *QueryService) ListAIRoleBudgets(ctx context.Context) ([]*database.AIRol
 func (q *QueryService) ListRecentAIReports(ctx context.Context, role string, limit, offset int) ([]*database.AIReport, int, error) {
 	return q.aiReportRepo.ListRecent(ctx, role, limit, offset)
 }
+
+// ==================== Agent Token ====================
+
+func (q *QueryService) ListAgentTokens(ctx context.Context) ([]*database.AgentToken, error) {
+	return q.agentTokenRepo.List(ctx)
+}
+
+func (q *QueryService) GetAgentToken(ctx context.Context, clusterID string) (*database.AgentToken, error) {
+	return q.agentTokenRepo.GetByCluster(ctx, clusterID)
+}
+
+// ==================== Exec Session ====================
+
+func (q *QueryService) ListExecSessions(ctx context.Context, opts database.ExecSessionQueryOpts) ([]*database.ExecSession, error) {
+	return q.execSessionRepo.List(ctx, opts)
+}
+
+func (q *QueryService) GetExecSession(ctx context.Context, sessionID string) (*database.ExecSession, error) {
+	return q.execSessionRepo.GetBySessionID(ctx, sessionID)
+}
@@ -5,31 +5,88 @@ package query
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"AtlHyper/atlhyper_master_v2/aiops"
 	"AtlHyper/atlhyper_master_v2/aiops/enricher"
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+	"AtlHyper/atlhyper_master_v2/aiops/policy"
+	"AtlHyper/atlhyper_master_v2/aiops/runbook"
 	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/rbac"
 )
 
 // GetAIOpsGraph 获取指定集群的依赖图
+// 请求带有角色绑定范围时只保留授权命名空间内的节点，以及两端均可见的边
 func (q *QueryService) GetAIOpsGraph(ctx context.Context, clusterID string) (*aiops.DependencyGraph, error) {
+	scope := rbac.ScopeFrom(ctx)
+	if err := checkClusterScope(scope, clusterID); err != nil {
+		return nil, err
+	}
 	if q.aiopsEngine == nil {
 		return nil, nil
 	}
-	return q.aiopsEngine.GetGraph(clusterID), nil
+	graph := q.aiopsEngine.GetGraph(clusterID)
+	if graph == nil || scope == nil {
+		return graph, nil
+	}
+
+	filtered := &aiops.DependencyGraph{
+		ClusterID: graph.ClusterID,
+		Nodes:     make(map[string]*aiops.GraphNode),
+		UpdatedAt: graph.UpdatedAt,
+	}
+	for key, node := range graph.Nodes {
+		if entityVisible(scope, clusterID, key) {
+			filtered.Nodes[key] = node
+		}
+	}
+	for _, edge := range graph.Edges {
+		if filtered.Nodes[edge.From] != nil && filtered.Nodes[edge.To] != nil {
+			filtered.Edges = append(filtered.Edges, edge)
+		}
+	}
+	return filtered, nil
 }
 
 // GetAIOpsGraphTrace 追踪指定实体的上下游链路
+// 起点实体不在授权范围内时返回 ErrForbidden，链路中越界的节点及其边被裁剪
 func (q *QueryService) GetAIOpsGraphTrace(ctx context.Context, clusterID, fromKey, direction string, maxDepth int) (*aiops.TraceResult, error) {
+	scope := rbac.ScopeFrom(ctx)
+	if err := checkEntityScope(scope, clusterID, fromKey); err != nil {
+		return nil, err
+	}
 	if q.aiopsEngine == nil {
 		return &aiops.TraceResult{}, nil
 	}
-	return q.aiopsEngine.GetGraphTrace(clusterID, fromKey, direction, maxDepth), nil
+	result := q.aiopsEngine.GetGraphTrace(clusterID, fromKey, direction, maxDepth)
+	if result == nil || scope == nil {
+		return result, nil
+	}
+
+	filtered := &aiops.TraceResult{Depth: result.Depth}
+	visible := make(map[string]bool, len(result.Nodes))
+	for _, node := range result.Nodes {
+		if entityVisible(scope, clusterID, node.Key) {
+			filtered.Nodes = append(filtered.Nodes, node)
+			visible[node.Key] = true
+		}
+	}
+	for _, edge := range result.Edges {
+		if visible[edge.From] && visible[edge.To] {
+			filtered.Edges = append(filtered.Edges, edge)
+		}
+	}
+	return filtered, nil
 }
 
-// GetAIOpsBaseline 获取指定实体的基线状态
+// GetAIOpsBaseline 获取指定实体的基线状态（实体不在授权范围内时返回 ErrForbidden）
 func (q *QueryService) GetAIOpsBaseline(ctx context.Context, clusterID, entityKey string) (*aiops.EntityBaseline, error) {
+	if err := checkEntityScope(rbac.ScopeFrom(ctx), clusterID, entityKey); err != nil {
+		return nil, err
+	}
 	if q.aiopsEngine == nil {
 		return nil, nil
 	}
@@ -37,7 +94,11 @@ func (q *QueryService) GetAIOpsBaseline(ctx context.Context, clusterID, entityKe
 }
 
 // GetAIOpsClusterRisk 获取集群风险评分
+// 集群风险是全集群聚合数据，请求带有角色绑定范围时需要集群级绑定
 func (q *QueryService) GetAIOpsClusterRisk(ctx context.Context, clusterID string) (*aiops.ClusterRisk, error) {
+	if scope := rbac.ScopeFrom(ctx); scope != nil && !scope.Allows(clusterID, "", rbac.RoleViewer) {
+		return nil, fmt.Errorf("%w: cluster risk of %s requires a cluster-wide binding", rbac.ErrForbidden, clusterID)
+	}
 	if q.aiopsEngine == nil {
 		return nil, nil
 	}
@@ -45,43 +106,166 @@ func (q *QueryService) GetAIOpsClusterRisk(ctx context.Context, clusterID string
 }
 
 // GetAIOpsEntityRisks 获取实体风险列表
+// 请求带有角色绑定范围时先按授权命名空间过滤再截取 limit
 func (q *QueryService) GetAIOpsEntityRisks(ctx context.Context, clusterID, sortBy string, limit int) ([]*aiops.EntityRisk, error) {
+	scope := rbac.ScopeFrom(ctx)
+	if err := checkClusterScope(scope, clusterID); err != nil {
+		return nil, err
+	}
 	if q.aiopsEngine == nil {
 		return nil, nil
 	}
-	return q.aiopsEngine.GetEntityRisks(clusterID, sortBy, limit), nil
+	if scope == nil {
+		return q.aiopsEngine.GetEntityRisks(clusterID, sortBy, limit), nil
+	}
+
+	all := q.aiopsEngine.GetEntityRisks(clusterID, sortBy, 0)
+	risks := make([]*aiops.EntityRisk, 0, len(all))
+	for _, r := range all {
+		if entityVisible(scope, clusterID, r.EntityKey) {
+			risks = append(risks, r)
+		}
+	}
+	if limit > 0 && limit < len(risks) {
+		risks = risks[:limit]
+	}
+	return risks, nil
 }
 
 // GetAIOpsEntityRisk 获取单个实体的风险详情
+// 实体不在授权范围内时返回 ErrForbidden，传播路径与因果链中越界的实体被裁剪
 func (q *QueryService) GetAIOpsEntityRisk(ctx context.Context, clusterID, entityKey string) (*aiops.EntityRiskDetail, error) {
+	scope := rbac.ScopeFrom(ctx)
+	if err := checkEntityScope(scope, clusterID, entityKey); err != nil {
+		return nil, err
+	}
 	if q.aiopsEngine == nil {
 		return nil, nil
 	}
-	return q.aiopsEngine.GetEntityRisk(clusterID, entityKey), nil
+	detail := q.aiopsEngine.GetEntityRisk(clusterID, entityKey)
+	if detail == nil || scope == nil {
+		return detail, nil
+	}
+
+	filtered := *detail
+	filtered.Propagation = nil
+	for _, p := range detail.Propagation {
+		if entityVisible(scope, clusterID, p.From) && entityVisible(scope, clusterID, p.To) {
+			filtered.Propagation = append(filtered.Propagation, p)
+		}
+	}
+	filtered.CausalChain = nil
+	for _, c := range detail.CausalChain {
+		if entityVisible(scope, clusterID, c.EntityKey) {
+			filtered.CausalChain = append(filtered.CausalChain, c)
+		}
+	}
+	filtered.CausalTree = visibleCausalTree(scope, clusterID, detail.CausalTree)
+	return &filtered, nil
+}
+
+// checkClusterScope 请求带有角色绑定范围且无权访问集群时返回 ErrForbidden
+func checkClusterScope(scope *rbac.Scope, clusterID string) error {
+	if scope != nil && !scope.AllowsCluster(clusterID) {
+		return fmt.Errorf("%w: cluster %s", rbac.ErrForbidden, clusterID)
+	}
+	return nil
+}
+
+// checkEntityScope 请求带有角色绑定范围且实体不在授权范围内时返回 ErrForbidden
+func checkEntityScope(scope *rbac.Scope, clusterID, entityKey string) error {
+	if scope != nil && !entityVisible(scope, clusterID, entityKey) {
+		return fmt.Errorf("%w: entity %s in cluster %s", rbac.ErrForbidden, entityKey, clusterID)
+	}
+	return nil
+}
+
+// entityNamespace 从实体键 "namespace/type/name" 中取出命名空间，集群级实体（_cluster）返回空串
+func entityNamespace(entityKey string) string {
+	namespace, _, _ := strings.Cut(entityKey, "/")
+	if namespace == "_cluster" {
+		return ""
+	}
+	return namespace
+}
+
+// entityVisible 判断实体是否在角色绑定范围内
+func entityVisible(scope *rbac.Scope, clusterID, entityKey string) bool {
+	return scope.Allows(clusterID, entityNamespace(entityKey), rbac.RoleViewer)
+}
+
+// visibleCausalTree 裁剪因果树中越界的实体（连同其子树）
+func visibleCausalTree(scope *rbac.Scope, clusterID string, nodes []*aiops.CausalTreeNode) []*aiops.CausalTreeNode {
+	var result []*aiops.CausalTreeNode
+	for _, n := range nodes {
+		if !entityVisible(scope, clusterID, n.EntityKey) {
+			continue
+		}
+		node := *n
+		node.Children = visibleCausalTree(scope, clusterID, n.Children)
+		result = append(result, &node)
+	}
+	return result
 }
 
 // GetAIOpsIncidents 查询事件列表
+// context 携带角色绑定范围时只返回根因实体在授权范围内的事件（全量过滤后再分页）
 func (q *QueryService) GetAIOpsIncidents(ctx context.Context, opts aiops.IncidentQueryOpts) ([]*aiops.Incident, int, error) {
 	if q.aiopsEngine == nil {
 		return nil, 0, nil
 	}
-	return q.aiopsEngine.GetIncidents(ctx, opts)
+	scope := rbac.ScopeFrom(ctx)
+	if scope == nil {
+		return q.aiopsEngine.GetIncidents(ctx, opts)
+	}
+
+	all := opts
+	all.Limit, all.Offset = 0, 0
+	incidents, _, err := q.aiopsEngine.GetIncidents(ctx, all)
+	if err != nil {
+		return nil, 0, err
+	}
+	result := visibleIncidents(scope, incidents)
+	total := len(result)
+	if opts.Offset > 0 {
+		if opts.Offset >= len(result) {
+			return []*aiops.Incident{}, total, nil
+		}
+		result = result[opts.Offset:]
+	}
+	if opts.Limit > 0 && opts.Limit < len(result) {
+		result = result[:opts.Limit]
+	}
+	return result, total, nil
 }
 
-// GetAIOpsIncidentDetail 获取事件详情
+// GetAIOpsIncidentDetail 获取事件详情（超出授权范围视为不存在）
 func (q *QueryService) GetAIOpsIncidentDetail(ctx context.Context, incidentID string) (*aiops.IncidentDetail, error) {
 	if q.aiopsEngine == nil {
 		return nil, nil
 	}
-	return q.aiopsEngine.GetIncidentDetail(ctx, incidentID), nil
+	detail := q.aiopsEngine.GetIncidentDetail(ctx, incidentID)
+	if detail == nil || !incidentVisible(rbac.ScopeFrom(ctx), &detail.Incident) {
+		return nil, nil
+	}
+	return detail, nil
 }
 
 // GetAIOpsIncidentStats 获取事件统计
+// context 携带角色绑定范围时按授权范围内的事件重新统计
 func (q *QueryService) GetAIOpsIncidentStats(ctx context.Context, clusterID string, since time.Time) (*aiops.IncidentStats, error) {
 	if q.aiopsEngine == nil {
 		return nil, nil
 	}
-	return q.aiopsEngine.GetIncidentStats(ctx, clusterID, since), nil
+	scope := rbac.ScopeFrom(ctx)
+	if scope == nil {
+		return q.aiopsEngine.GetIncidentStats(ctx, clusterID, since), nil
+	}
+	incidents, _, err := q.aiopsEngine.GetIncidents(ctx, aiops.IncidentQueryOpts{ClusterID: clusterID, From: since})
+	if err != nil {
+		return nil, err
+	}
+	return incidentStats(visibleIncidents(scope, incidents)), nil
 }
 
 // GetAIOpsIncidentPatterns 获取历史事件模式
@@ -92,6 +276,70 @@ func (q *QueryService) GetAIOpsIncidentPatterns(ctx context.Context, entityKey s
 	return q.aiopsEngine.GetIncidentPatterns(ctx, entityKey, since), nil
 }
 
+// incidentVisible 事件根因实体是否在授权范围内（集群级实体需要集群级授权）
+func incidentVisible(scope *rbac.Scope, inc *aiops.Incident) bool {
+	return entityVisible(scope, inc.ClusterID, inc.RootCause)
+}
+
+// visibleIncidents 过滤出授权范围内的事件
+func visibleIncidents(scope *rbac.Scope, incidents []*aiops.Incident) []*aiops.Incident {
+	result := make([]*aiops.Incident, 0, len(incidents))
+	for _, inc := range incidents {
+		if incidentVisible(scope, inc) {
+			result = append(result, inc)
+		}
+	}
+	return result
+}
+
+// incidentStats 按事件列表统计（口径与 AIOpsIncidentRepository.GetIncidentStats 一致）
+func incidentStats(incidents []*aiops.Incident) *aiops.IncidentStats {
+	stats := &aiops.IncidentStats{
+		TotalIncidents: len(incidents),
+		BySeverity:     make(map[string]int),
+		ByState:        make(map[string]int),
+		TopRootCauses:  []aiops.RootCauseCount{},
+	}
+	var resolvedSum float64
+	var resolved, recurring int
+	rootCauses := make(map[string]int)
+	for _, inc := range incidents {
+		if inc.State != aiops.StateStable {
+			stats.ActiveIncidents++
+		} else if inc.DurationS > 0 {
+			resolvedSum += float64(inc.DurationS)
+			resolved++
+		}
+		if inc.Recurrence > 0 {
+			recurring++
+		}
+		stats.BySeverity[inc.Severity]++
+		stats.ByState[string(inc.State)]++
+		rootCauses[inc.RootCause]++
+	}
+	if resolved > 0 {
+		stats.MTTR = resolvedSum / float64(resolved)
+	}
+	if len(incidents) > 0 {
+		stats.RecurrenceRate = float64(recurring) / float64(len(incidents)) * 100
+	}
+
+	for key, count := range rootCauses {
+		stats.TopRootCauses = append(stats.TopRootCauses, aiops.RootCauseCount{EntityKey: key, Count: count})
+	}
+	sort.Slice(stats.TopRootCauses, func(i, j int) bool {
+		a, b := stats.TopRootCauses[i], stats.TopRootCauses[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.EntityKey < b.EntityKey
+	})
+	if len(stats.TopRootCauses) > 10 {
+		stats.TopRootCauses = stats.TopRootCauses[:10]
+	}
+	return stats
+}
+
 // SummarizeIncident AI 增强：生成事件摘要
 func (q *QueryService) SummarizeIncident(ctx context.Context, incidentID string) (*enricher.SummarizeResponse, error) {
 	if q.aiopsAI == nil {
@@ -111,3 +359,81 @@ func (q *QueryService) ListAIReports(ctx context.Context, incidentID string) ([]
 func (q *QueryService) GetAIReport(ctx context.Context, id int64) (*database.AIReport, error) {
 	return q.aiReportRepo.GetByID(ctx, id)
 }
+
+// GetAIOpsPostmortem 获取事件复盘文档（未生成或事件超出授权范围返回 nil）
+func (q *QueryService) GetAIOpsPostmortem(ctx context.Context, incidentID string) (*database.AIOpsPostmortem, error) {
+	if q.postmortemRepo == nil {
+		return nil, nil
+	}
+	if scope := rbac.ScopeFrom(ctx); scope != nil {
+		if detail, _ := q.GetAIOpsIncidentDetail(ctx, incidentID); detail == nil {
+			return nil, nil
+		}
+	}
+	return q.postmortemRepo.Get(ctx, incidentID)
+}
+
+// ==================== Runbook ====================
+
+// ListAIOpsRunbooks 获取已加载的 Runbook
+func (q *QueryService) ListAIOpsRunbooks(ctx context.Context) ([]*runbook.Runbook, error) {
+	if q.runbooks == nil {
+		return nil, nil
+	}
+	return q.runbooks.Runbooks(), nil
+}
+
+// ListAIOpsRunbookRuns 查询 Runbook 执行记录
+func (q *QueryService) ListAIOpsRunbookRuns(ctx context.Context, opts database.RunbookRunQueryOpts) ([]*database.AIOpsRunbookRun, int64, error) {
+	if q.runbooks == nil {
+		return nil, 0, nil
+	}
+	return q.runbooks.ListRuns(ctx, opts)
+}
+
+// ==================== 策略 ====================
+
+// ListAIOpsPolicies 获取各策略当前版本
+func (q *QueryService) ListAIOpsPolicies(ctx context.Context) ([]*policy.Policy, error) {
+	if q.policies == nil {
+		return nil, nil
+	}
+	return q.policies.Policies(), nil
+}
+
+// GetAIOpsPolicyHistory 获取策略版本历史
+func (q *QueryService) GetAIOpsPolicyHistory(ctx context.Context, name string) ([]*policy.Policy, error) {
+	if q.policies == nil {
+		return nil, policy.ErrPolicyNotFound
+	}
+	return q.policies.History(ctx, name)
+}
+
+// GetAIOpsEffectivePolicy 获取集群 / 实体叠加后的生效配置
+func (q *QueryService) GetAIOpsEffectivePolicy(ctx context.Context, clusterID, entityKey string) (*policy.Effective, error) {
+	if q.policies == nil {
+		return policy.NewManager(nil, nil).Effective(clusterID, entityKey), nil
+	}
+	return q.policies.Effective(clusterID, entityKey), nil
+}
+
+// ==================== 维护窗口 ====================
+
+// ListAIOpsMaintenanceWindows 获取维护窗口（按调用者授权范围过滤）
+func (q *QueryService) ListAIOpsMaintenanceWindows(ctx context.Context) ([]*maintenance.Window, error) {
+	if q.maintenance == nil {
+		return nil, nil
+	}
+	windows, err := q.maintenance.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scope := rbac.ScopeFrom(ctx)
+	result := make([]*maintenance.Window, 0, len(windows))
+	for _, w := range windows {
+		if scope.AllowsCluster(w.ClusterID) {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
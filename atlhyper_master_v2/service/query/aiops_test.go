@@ -0,0 +1,231 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/aiops"
+	"AtlHyper/atlhyper_master_v2/rbac"
+)
+
+// fakeIncidentEngine 仅实现事件查询的 aiops.Engine
+type fakeIncidentEngine struct {
+	aiops.Engine
+	incidents []*aiops.Incident
+}
+
+func (f *fakeIncidentEngine) GetIncidents(ctx context.Context, opts aiops.IncidentQueryOpts) ([]*aiops.Incident, int, error) {
+	result := f.incidents
+	if opts.Limit > 0 && opts.Limit < len(result) {
+		result = result[:opts.Limit]
+	}
+	return result, len(f.incidents), nil
+}
+
+func (f *fakeIncidentEngine) GetIncidentDetail(ctx context.Context, incidentID string) *aiops.IncidentDetail {
+	for _, inc := range f.incidents {
+		if inc.ID == incidentID {
+			return &aiops.IncidentDetail{Incident: *inc}
+		}
+	}
+	return nil
+}
+
+func TestGetAIOpsIncidents_Scope(t *testing.T) {
+	engine := &fakeIncidentEngine{incidents: []*aiops.Incident{
+		{ID: "inc-1", ClusterID: "c1", RootCause: "prod/service/api", State: aiops.StateIncident, Severity: "high"},
+		{ID: "inc-2", ClusterID: "c1", RootCause: "dev/pod/web-1", State: aiops.StateWarning, Severity: "low"},
+		{ID: "inc-3", ClusterID: "c1", RootCause: "_cluster/node/worker-1", State: aiops.StateStable, Severity: "medium"},
+		{ID: "inc-4", ClusterID: "c1", RootCause: "prod/pod/api-1", State: aiops.StateStable, Severity: "high", DurationS: 600, Recurrence: 1},
+	}}
+	svc := &QueryService{aiopsEngine: engine}
+	ctx := rbac.WithScope(context.Background(), &rbac.Scope{
+		Bindings: []rbac.Binding{{ClusterID: "c1", Namespace: "prod", Role: rbac.RoleViewer}},
+	})
+
+	incidents, total, err := svc.GetAIOpsIncidents(ctx, aiops.IncidentQueryOpts{ClusterID: "c1", Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 2 || len(incidents) != 1 || incidents[0].ID != "inc-1" {
+		t.Fatalf("got total=%d incidents=%v, want total=2 [inc-1]", total, incidents)
+	}
+
+	if detail, _ := svc.GetAIOpsIncidentDetail(ctx, "inc-2"); detail != nil {
+		t.Error("incident outside scope should not be visible")
+	}
+	if detail, _ := svc.GetAIOpsIncidentDetail(ctx, "inc-3"); detail != nil {
+		t.Error("cluster-level incident should require cluster-level binding")
+	}
+	if detail, _ := svc.GetAIOpsIncidentDetail(ctx, "inc-4"); detail == nil {
+		t.Error("incident in scope should be visible")
+	}
+
+	stats, err := svc.GetAIOpsIncidentStats(ctx, "c1", time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.TotalIncidents != 2 || stats.ActiveIncidents != 1 || stats.MTTR != 600 || stats.RecurrenceRate != 50 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if stats.BySeverity["high"] != 2 || len(stats.TopRootCauses) != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// 不受限时原样返回
+	_, total, _ = svc.GetAIOpsIncidents(context.Background(), aiops.IncidentQueryOpts{})
+	if total != 4 {
+		t.Errorf("unscoped total = %d, want 4", total)
+	}
+}
+
+// fakeRiskEngine 仅实现依赖图与风险查询的 aiops.Engine
+type fakeRiskEngine struct {
+	aiops.Engine
+	graph *aiops.DependencyGraph
+	risks []*aiops.EntityRisk
+}
+
+func (f *fakeRiskEngine) GetGraph(clusterID string) *aiops.DependencyGraph { return f.graph }
+
+func (f *fakeRiskEngine) GetGraphTrace(clusterID, fromKey, direction string, maxDepth int) *aiops.TraceResult {
+	result := &aiops.TraceResult{Edges: f.graph.Edges, Depth: maxDepth}
+	for _, n := range f.graph.Nodes {
+		result.Nodes = append(result.Nodes, n)
+	}
+	return result
+}
+
+func (f *fakeRiskEngine) GetBaseline(entityKey string) *aiops.EntityBaseline {
+	return &aiops.EntityBaseline{EntityKey: entityKey}
+}
+
+func (f *fakeRiskEngine) GetClusterRisk(clusterID string) *aiops.ClusterRisk {
+	return &aiops.ClusterRisk{ClusterID: clusterID, TopEntities: f.risks}
+}
+
+func (f *fakeRiskEngine) GetEntityRisks(clusterID, sortBy string, limit int) []*aiops.EntityRisk {
+	if limit > 0 && limit < len(f.risks) {
+		return f.risks[:limit]
+	}
+	return f.risks
+}
+
+func (f *fakeRiskEngine) GetEntityRisk(clusterID, entityKey string) *aiops.EntityRiskDetail {
+	return &aiops.EntityRiskDetail{
+		EntityRisk: aiops.EntityRisk{EntityKey: entityKey},
+		Propagation: []*aiops.PropagationPath{
+			{From: "prod/pod/api-1", To: entityKey},
+			{From: "_cluster/node/worker-1", To: entityKey},
+		},
+		CausalChain: []*aiops.CausalEntry{{EntityKey: entityKey}, {EntityKey: "dev/pod/web-1"}},
+		CausalTree: []*aiops.CausalTreeNode{
+			{EntityKey: "prod/pod/api-1", Children: []*aiops.CausalTreeNode{{EntityKey: "_cluster/node/worker-1"}}},
+			{EntityKey: "dev/service/web"},
+		},
+	}
+}
+
+func newRiskEngine() *fakeRiskEngine {
+	graph := aiops.NewDependencyGraph("c1")
+	for _, key := range []string{"prod/service/api", "prod/pod/api-1", "dev/service/web", "_cluster/node/worker-1"} {
+		graph.Nodes[key] = &aiops.GraphNode{Key: key}
+	}
+	graph.Edges = []*aiops.GraphEdge{
+		{From: "prod/service/api", To: "prod/pod/api-1"},
+		{From: "prod/pod/api-1", To: "_cluster/node/worker-1"},
+		{From: "dev/service/web", To: "prod/service/api"},
+	}
+	return &fakeRiskEngine{graph: graph, risks: []*aiops.EntityRisk{
+		{EntityKey: "_cluster/node/worker-1", RFinal: 0.9},
+		{EntityKey: "dev/service/web", RFinal: 0.8},
+		{EntityKey: "prod/pod/api-1", RFinal: 0.7},
+		{EntityKey: "prod/service/api", RFinal: 0.6},
+	}}
+}
+
+func TestAIOpsGraphAndRisk_Scope(t *testing.T) {
+	svc := &QueryService{aiopsEngine: newRiskEngine()}
+	ctx := rbac.WithScope(context.Background(), &rbac.Scope{
+		Bindings: []rbac.Binding{{ClusterID: "c1", Namespace: "prod", Role: rbac.RoleViewer}},
+	})
+
+	graph, err := svc.GetAIOpsGraph(ctx, "c1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(graph.Nodes) != 2 || graph.Nodes["prod/service/api"] == nil || graph.Nodes["prod/pod/api-1"] == nil {
+		t.Errorf("graph nodes = %v", graph.Nodes)
+	}
+	if len(graph.Edges) != 1 || graph.Edges[0].To != "prod/pod/api-1" {
+		t.Errorf("graph edges = %v", graph.Edges)
+	}
+
+	trace, err := svc.GetAIOpsGraphTrace(ctx, "c1", "prod/service/api", "downstream", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trace.Nodes) != 2 || len(trace.Edges) != 1 {
+		t.Errorf("trace = %d nodes, %d edges, want 2/1", len(trace.Nodes), len(trace.Edges))
+	}
+	if _, err := svc.GetAIOpsGraphTrace(ctx, "c1", "dev/service/web", "downstream", 3); !errors.Is(err, rbac.ErrForbidden) {
+		t.Errorf("trace from out-of-scope entity: expected ErrForbidden, got %v", err)
+	}
+
+	if _, err := svc.GetAIOpsBaseline(ctx, "c1", "prod/pod/api-1"); err != nil {
+		t.Errorf("baseline in scope: %v", err)
+	}
+	if _, err := svc.GetAIOpsBaseline(ctx, "c1", "_cluster/node/worker-1"); !errors.Is(err, rbac.ErrForbidden) {
+		t.Errorf("baseline of node: expected ErrForbidden, got %v", err)
+	}
+
+	// 先过滤再截取 limit
+	risks, err := svc.GetAIOpsEntityRisks(ctx, "c1", "r_final", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(risks) != 1 || risks[0].EntityKey != "prod/pod/api-1" {
+		t.Errorf("risks = %v, want [prod/pod/api-1]", risks)
+	}
+
+	detail, err := svc.GetAIOpsEntityRisk(ctx, "c1", "prod/service/api")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(detail.Propagation) != 1 || len(detail.CausalChain) != 1 {
+		t.Errorf("detail propagation=%v causalChain=%v", detail.Propagation, detail.CausalChain)
+	}
+	if len(detail.CausalTree) != 1 || len(detail.CausalTree[0].Children) != 0 {
+		t.Errorf("detail causalTree = %v", detail.CausalTree)
+	}
+	if _, err := svc.GetAIOpsEntityRisk(ctx, "c1", "dev/service/web"); !errors.Is(err, rbac.ErrForbidden) {
+		t.Errorf("entity risk out of scope: expected ErrForbidden, got %v", err)
+	}
+
+	// 集群风险需要集群级绑定
+	if _, err := svc.GetAIOpsClusterRisk(ctx, "c1"); !errors.Is(err, rbac.ErrForbidden) {
+		t.Errorf("cluster risk: expected ErrForbidden, got %v", err)
+	}
+	clusterCtx := rbac.WithScope(context.Background(), &rbac.Scope{
+		Bindings: []rbac.Binding{{ClusterID: "c1", Role: rbac.RoleViewer}},
+	})
+	if risk, err := svc.GetAIOpsClusterRisk(clusterCtx, "c1"); err != nil || risk == nil {
+		t.Errorf("cluster risk with cluster-wide binding: %v (err=%v)", risk, err)
+	}
+	if graph, _ := svc.GetAIOpsGraph(clusterCtx, "c1"); len(graph.Nodes) != 4 {
+		t.Errorf("cluster-wide binding should see full graph, got %d nodes", len(graph.Nodes))
+	}
+
+	// 未授权集群
+	other := rbac.WithScope(context.Background(), &rbac.Scope{
+		Bindings: []rbac.Binding{{ClusterID: "c2", Role: rbac.RoleViewer}},
+	})
+	if _, err := svc.GetAIOpsGraph(other, "c1"); !errors.Is(err, rbac.ErrForbidden) {
+		t.Errorf("graph: expected ErrForbidden, got %v", err)
+	}
+	if _, err := svc.GetAIOpsEntityRisks(other, "c1", "", 0); !errors.Is(err, rbac.ErrForbidden) {
+		t.Errorf("entity risks: expected ErrForbidden, got %v", err)
+	}
+}
@@ -12,9 +12,13 @@ package query
 import (
 	"AtlHyper/atlhyper_master_v2/aiops"
 	"AtlHyper/atlhyper_master_v2/aiops/enricher"
+	"AtlHyper/atlhyper_master_v2/aiops/maintenance"
+	"AtlHyper/atlhyper_master_v2/aiops/policy"
+	"AtlHyper/atlhyper_master_v2/aiops/runbook"
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/datahub"
 	"AtlHyper/atlhyper_master_v2/mq"
+	"AtlHyper/atlhyper_master_v2/slo"
 )
 
 // QueryService Query 层实现
@@ -25,31 +29,59 @@ type QueryService struct {
 	sloRepo     database.SLORepository
 	aiopsEngine aiops.Engine
 	aiopsAI     *enricher.Enricher
+	sloBurn     *slo.BurnAlerter
+	runbooks    *runbook.Manager
+	policies    *policy.Manager
+	maintenance *maintenance.Manager
 
 	// Admin repositories（管理查询）
-	auditRepo      database.AuditRepository
-	commandRepo    database.CommandHistoryRepository
-	notifyRepo     database.NotifyChannelRepository
-	settingsRepo   database.SettingsRepository
-	aiProviderRepo database.AIProviderRepository
-	aiSettingsRepo database.AISettingsRepository
-	aiModelRepo    database.AIProviderModelRepository
-	aiBudgetRepo   database.AIRoleBudgetRepository
-	aiReportRepo   database.AIReportRepository
+	auditRepo          database.AuditRepository
+	commandRepo        database.CommandHistoryRepository
+	notifyRepo         database.NotifyChannelRepository
+	notifyRouteRepo    database.NotifyRouteRepository
+	notifySilenceRepo  database.NotifySilenceRepository
+	notifyInhibitRepo  database.NotifyInhibitRuleRepository
+	notifyDeliveryRepo database.NotifyDeliveryRepository
+	notifyTemplateRepo database.NotifyTemplateRepository
+	settingsRepo       database.SettingsRepository
+	aiProviderRepo     database.AIProviderRepository
+	aiSettingsRepo     database.AISettingsRepository
+	aiModelRepo        database.AIProviderModelRepository
+	aiBudgetRepo       database.AIRoleBudgetRepository
+	aiReportRepo       database.AIReportRepository
+	postmortemRepo     database.AIOpsPostmortemRepository
+	agentTokenRepo     database.AgentTokenRepository
+	execSessionRepo    database.ExecSessionRepository
+	oncallRepo         database.OncallScheduleRepository
+	oncallOverrideRepo database.OncallOverrideRepository
+	escalationPolicy   database.EscalationPolicyRepository
+	escalationRepo     database.EscalationRepository
 }
 
 // AdminRepos 管理查询所需的 Repository 集合
 // 对应 QueryAdmin 接口的所有方法所需依赖
 type AdminRepos struct {
-	Audit      database.AuditRepository
-	Command    database.CommandHistoryRepository
-	Notify     database.NotifyChannelRepository
-	Settings   database.SettingsRepository
-	AIProvider database.AIProviderRepository
-	AISettings database.AISettingsRepository
-	AIModel    database.AIProviderModelRepository
-	AIBudget   database.AIRoleBudgetRepository
-	AIReport   database.AIReportRepository
+	Audit          database.AuditRepository
+	Command        database.CommandHistoryRepository
+	Notify         database.NotifyChannelRepository
+	NotifyRoute    database.NotifyRouteRepository
+	NotifySilence  database.NotifySilenceRepository
+	NotifyInhibit  database.NotifyInhibitRuleRepository
+	NotifyDelivery database.NotifyDeliveryRepository
+	NotifyTemplate database.NotifyTemplateRepository
+	Settings       database.SettingsRepository
+	AIProvider     database.AIProviderRepository
+	AISettings     database.AISettingsRepository
+	AIModel        database.AIProviderModelRepository
+	AIBudget       database.AIRoleBudgetRepository
+	AIReport       database.AIReportRepository
+	Postmortem     database.AIOpsPostmortemRepository
+	AgentToken     database.AgentTokenRepository
+	ExecSession    database.ExecSessionRepository
+	Oncall         database.OncallScheduleRepository
+	OncallOverride database.OncallOverrideRepository
+	Escalation     database.EscalationPolicyRepository
+	EscalationLog  database.EscalationRepository
 }
 
 // QueryServiceDeps QueryService 全部依赖
@@ -61,26 +93,46 @@ type QueryServiceDeps struct {
 	SLORepo     database.SLORepository          // 必需（Phase 2 新增）
 	AIOpsEngine aiops.Engine                    // 可选，nil = AIOps 查询返回空
 	AIOpsAI     *enricher.Enricher              // 可选，nil = AI 增强禁用
+	SLOBurn     *slo.BurnAlerter                // 可选，nil = 燃烧率告警查询返回空
+	Runbooks    *runbook.Manager                // 可选，nil = 未配置 Runbook
+	Policies    *policy.Manager                 // 可选，nil = AIOps 策略查询返回空
+	Maintenance *maintenance.Manager            // 可选，nil = 维护窗口查询返回空
 	AdminRepos  AdminRepos                      // 必需（管理查询）
 }
 
 // NewQueryService 创建 QueryService（全部依赖通过构造函数注入）
 func NewQueryService(deps QueryServiceDeps) *QueryService {
 	return &QueryService{
-		store:          deps.Store,
-		bus:            deps.Bus,
-		eventRepo:      deps.EventRepo,
-		sloRepo:        deps.SLORepo,
-		aiopsEngine:    deps.AIOpsEngine,
-		aiopsAI:        deps.AIOpsAI,
-		auditRepo:      deps.AdminRepos.Audit,
-		commandRepo:    deps.AdminRepos.Command,
-		notifyRepo:     deps.AdminRepos.Notify,
-		settingsRepo:   deps.AdminRepos.Settings,
-		aiProviderRepo: deps.AdminRepos.AIProvider,
-		aiSettingsRepo: deps.AdminRepos.AISettings,
-		aiModelRepo:    deps.AdminRepos.AIModel,
-		aiBudgetRepo:   deps.AdminRepos.AIBudget,
-		aiReportRepo:   deps.AdminRepos.AIReport,
+		store:              deps.Store,
+		bus:                deps.Bus,
+		eventRepo:          deps.EventRepo,
+		sloRepo:            deps.SLORepo,
+		aiopsEngine:        deps.AIOpsEngine,
+		aiopsAI:            deps.AIOpsAI,
+		sloBurn:            deps.SLOBurn,
+		runbooks:           deps.Runbooks,
+		policies:           deps.Policies,
+		maintenance:        deps.Maintenance,
+		auditRepo:          deps.AdminRepos.Audit,
+		commandRepo:        deps.AdminRepos.Command,
+		notifyRepo:         deps.AdminRepos.Notify,
+		notifyRouteRepo:    deps.AdminRepos.NotifyRoute,
+		notifySilenceRepo:  deps.AdminRepos.NotifySilence,
+		notifyInhibitRepo:  deps.AdminRepos.NotifyInhibit,
+		notifyDeliveryRepo: deps.AdminRepos.NotifyDelivery,
+		notifyTemplateRepo: deps.AdminRepos.NotifyTemplate,
+		settingsRepo:       deps.AdminRepos.Settings,
+		aiProviderRepo:     deps.AdminRepos.AIProvider,
+		aiSettingsRepo:     deps.AdminRepos.AISettings,
+		aiModelRepo:        deps.AdminRepos.AIModel,
+		aiBudgetRepo:       deps.AdminRepos.AIBudget,
+		aiReportRepo:       deps.AdminRepos.AIReport,
+		postmortemRepo:     deps.AdminRepos.Postmortem,
+		agentTokenRepo:     deps.AdminRepos.AgentToken,
+		execSessionRepo:    deps.AdminRepos.ExecSession,
+		oncallRepo:         deps.AdminRepos.Oncall,
+		oncallOverrideRepo: deps.AdminRepos.OncallOverride,
+		escalationPolicy:   deps.AdminRepos.Escalation,
+		escalationRepo:     deps.AdminRepos.EscalationLog,
 	}
 }
@@ -5,20 +5,46 @@ package query
 import (
 	"context"
 
+	"AtlHyper/atlhyper_master_v2/datahub"
 	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/rbac"
 	"AtlHyper/model_v3/cluster"
 )
 
 // ==================== 快照查询 ====================
 
-// GetSnapshot 获取集群快照
+// GetSnapshot 获取集群快照（context 携带时间点时返回该时间点的历史快照）
 func (q *QueryService) GetSnapshot(ctx context.Context, clusterID string) (*cluster.ClusterSnapshot, error) {
-	return q.store.GetSnapshot(clusterID)
+	return q.snapshotAt(ctx, clusterID)
+}
+
+// snapshotAt 按 context 中的时间点读取快照，未指定时间点时读取当前快照
+// 请求带有角色绑定范围时，返回裁剪后的快照
+func (q *QueryService) snapshotAt(ctx context.Context, clusterID string) (*cluster.ClusterSnapshot, error) {
+	var snapshot *cluster.ClusterSnapshot
+	var err error
+	if at, ok := datahub.SnapshotTimeFrom(ctx); ok {
+		snapshot, err = q.store.GetSnapshotAt(clusterID, at)
+	} else {
+		snapshot, err = q.store.GetSnapshot(clusterID)
+	}
+	if err != nil || snapshot == nil {
+		return snapshot, err
+	}
+
+	// 按角色绑定裁剪：无权访问的集群视为不存在，命名空间级绑定只保留授权命名空间
+	if scope := rbac.ScopeFrom(ctx); scope != nil {
+		if !scope.AllowsCluster(clusterID) {
+			return nil, nil
+		}
+		return snapshot.FilterNamespaces(scope.NamespaceFilter(clusterID)), nil
+	}
+	return snapshot, nil
 }
 
 // GetPods 获取 Pod 列表
 func (q *QueryService) GetPods(ctx context.Context, clusterID string, opts model.PodQueryOpts) ([]cluster.Pod, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -56,7 +82,7 @@ func (q *QueryService) GetPods(ctx context.Context, clusterID string, opts model
 
 // GetNodes 获取 Node 列表
 func (q *QueryService) GetNodes(ctx context.Context, clusterID string) ([]cluster.Node, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -65,7 +91,7 @@ func (q *QueryService) GetNodes(ctx context.Context, clusterID string) ([]cluste
 
 // GetDeployments 获取 Deployment 列表
 func (q *QueryService) GetDeployments(ctx context.Context, clusterID string, namespace string) ([]cluster.Deployment, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -85,7 +111,7 @@ func (q *QueryService) GetDeployments(ctx context.Context, clusterID string, nam
 
 // GetServices 获取 Service 列表
 func (q *QueryService) GetServices(ctx context.Context, clusterID string, namespace string) ([]cluster.Service, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -105,7 +131,7 @@ func (q *QueryService) GetServices(ctx context.Context, clusterID string, namesp
 
 // GetIngresses 获取 Ingress 列表
 func (q *QueryService) GetIngresses(ctx context.Context, clusterID string, namespace string) ([]cluster.Ingress, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -125,7 +151,7 @@ func (q *QueryService) GetIngresses(ctx context.Context, clusterID string, names
 
 // GetConfigMaps 获取 ConfigMap 列表
 func (q *QueryService) GetConfigMaps(ctx context.Context, clusterID string, namespace string) ([]cluster.ConfigMap, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -145,7 +171,7 @@ func (q *QueryService) GetConfigMaps(ctx context.Context, clusterID string, name
 
 // GetSecrets 获取 Secret 列表
 func (q *QueryService) GetSecrets(ctx context.Context, clusterID string, namespace string) ([]cluster.Secret, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -165,7 +191,7 @@ func (q *QueryService) GetSecrets(ctx context.Context, clusterID string, namespa
 
 // GetNamespaces 获取 Namespace 列表
 func (q *QueryService) GetNamespaces(ctx context.Context, clusterID string) ([]cluster.Namespace, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -174,7 +200,7 @@ func (q *QueryService) GetNamespaces(ctx context.Context, clusterID string) ([]c
 
 // GetDaemonSets 获取 DaemonSet 列表
 func (q *QueryService) GetDaemonSets(ctx context.Context, clusterID string, namespace string) ([]cluster.DaemonSet, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -194,7 +220,7 @@ func (q *QueryService) GetDaemonSets(ctx context.Context, clusterID string, name
 
 // GetStatefulSets 获取 StatefulSet 列表
 func (q *QueryService) GetStatefulSets(ctx context.Context, clusterID string, namespace string) ([]cluster.StatefulSet, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -214,7 +240,7 @@ func (q *QueryService) GetStatefulSets(ctx context.Context, clusterID string, na
 
 // GetJobs 获取 Job 列表
 func (q *QueryService) GetJobs(ctx context.Context, clusterID string, namespace string) ([]cluster.Job, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -234,7 +260,7 @@ func (q *QueryService) GetJobs(ctx context.Context, clusterID string, namespace
 
 // GetCronJobs 获取 CronJob 列表
 func (q *QueryService) GetCronJobs(ctx context.Context, clusterID string, namespace string) ([]cluster.CronJob, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -252,9 +278,29 @@ func (q *QueryService) GetCronJobs(ctx context.Context, clusterID string, namesp
 	return result, nil
 }
 
+// GetHPAs 获取 HorizontalPodAutoscaler 列表
+func (q *QueryService) GetHPAs(ctx context.Context, clusterID string, namespace string) ([]cluster.HorizontalPodAutoscaler, error) {
+	snapshot, err := q.snapshotAt(ctx, clusterID)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+
+	if namespace == "" {
+		return snapshot.HPAs, nil
+	}
+
+	result := make([]cluster.HorizontalPodAutoscaler, 0)
+	for _, h := range snapshot.HPAs {
+		if h.Namespace == namespace {
+			result = append(result, h)
+		}
+	}
+	return result, nil
+}
+
 // GetPersistentVolumes 获取 PV 列表（集群级，无 namespace）
 func (q *QueryService) GetPersistentVolumes(ctx context.Context, clusterID string) ([]cluster.PersistentVolume, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -263,7 +309,7 @@ func (q *QueryService) GetPersistentVolumes(ctx context.Context, clusterID strin
 
 // GetPersistentVolumeClaims 获取 PVC 列表
 func (q *QueryService) GetPersistentVolumeClaims(ctx context.Context, clusterID string, namespace string) ([]cluster.PersistentVolumeClaim, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -283,7 +329,7 @@ func (q *QueryService) GetPersistentVolumeClaims(ctx context.Context, clusterID
 
 // GetNetworkPolicies 获取 NetworkPolicy 列表
 func (q *QueryService) GetNetworkPolicies(ctx context.Context, clusterID string, namespace string) ([]cluster.NetworkPolicy, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -303,7 +349,7 @@ func (q *QueryService) GetNetworkPolicies(ctx context.Context, clusterID string,
 
 // GetResourceQuotas 获取 ResourceQuota 列表
 func (q *QueryService) GetResourceQuotas(ctx context.Context, clusterID string, namespace string) ([]cluster.ResourceQuota, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -323,7 +369,7 @@ func (q *QueryService) GetResourceQuotas(ctx context.Context, clusterID string,
 
 // GetLimitRanges 获取 LimitRange 列表
 func (q *QueryService) GetLimitRanges(ctx context.Context, clusterID string, namespace string) ([]cluster.LimitRange, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -343,7 +389,7 @@ func (q *QueryService) GetLimitRanges(ctx context.Context, clusterID string, nam
 
 // GetServiceAccounts 获取 ServiceAccount 列表
 func (q *QueryService) GetServiceAccounts(ctx context.Context, clusterID string, namespace string) ([]cluster.ServiceAccount, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
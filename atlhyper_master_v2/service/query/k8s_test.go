@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/rbac"
 	model_v3 "AtlHyper/model_v3"
 	agentmodel "AtlHyper/model_v3/agent"
 	"AtlHyper/model_v3/cluster"
@@ -20,6 +21,13 @@ type mockStoreForK8s struct {
 func (m *mockStoreForK8s) SetSnapshot(clusterID string, snapshot *cluster.ClusterSnapshot) error {
 	return nil
 }
+func (m *mockStoreForK8s) ApplySnapshotDelta(clusterID string, delta *cluster.SnapshotDelta) (*cluster.ClusterSnapshot, error) {
+	return nil, nil
+}
+func (m *mockStoreForK8s) GetSnapshotAt(clusterID string, at time.Time) (*cluster.ClusterSnapshot, error) {
+	return m.GetSnapshot(clusterID)
+}
+func (m *mockStoreForK8s) ListSnapshotHistory(clusterID string) ([]time.Time, error) { return nil, nil }
 func (m *mockStoreForK8s) GetSnapshot(clusterID string) (*cluster.ClusterSnapshot, error) {
 	if m.snapshots != nil {
 		return m.snapshots[clusterID], nil
@@ -252,6 +260,35 @@ func TestGetPods_NamespaceFilter(t *testing.T) {
 	}
 }
 
+func TestGetPods_ScopeFilter(t *testing.T) {
+	svc := newK8sSvcWithPods()
+	ctx := rbac.WithScope(context.Background(), &rbac.Scope{
+		Bindings: []rbac.Binding{{ClusterID: "cluster-1", Namespace: "kube-system", Role: rbac.RoleViewer}},
+	})
+
+	result, err := svc.GetPods(ctx, "cluster-1", model.PodQueryOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 pods in granted namespace, got %d", len(result))
+	}
+	for _, p := range result {
+		if p.GetNamespace() != "kube-system" {
+			t.Errorf("pod %s from namespace %s should be hidden", p.Summary.Name, p.GetNamespace())
+		}
+	}
+
+	// 未授权的集群视为无数据
+	other := rbac.WithScope(context.Background(), &rbac.Scope{
+		Bindings: []rbac.Binding{{ClusterID: "cluster-2", Role: rbac.RoleViewer}},
+	})
+	result, err = svc.GetPods(other, "cluster-1", model.PodQueryOpts{})
+	if err != nil || result != nil {
+		t.Fatalf("expected nil result for unauthorized cluster, got %v (err=%v)", result, err)
+	}
+}
+
 func TestGetPods_NodeNameFilter(t *testing.T) {
 	svc := newK8sSvcWithPods()
 
@@ -589,6 +626,11 @@ func testSnapshotForSmoke() *cluster.ClusterSnapshot {
 			{CommonMeta: model_v3.CommonMeta{Name: "cj-2", Namespace: "kube-system"}},
 			{CommonMeta: model_v3.CommonMeta{Name: "cj-3", Namespace: "default"}},
 		},
+		HPAs: []cluster.HorizontalPodAutoscaler{
+			{CommonMeta: model_v3.CommonMeta{Name: "hpa-1", Namespace: "default"}},
+			{CommonMeta: model_v3.CommonMeta{Name: "hpa-2", Namespace: "kube-system"}},
+			{CommonMeta: model_v3.CommonMeta{Name: "hpa-3", Namespace: "default"}},
+		},
 		PersistentVolumeClaims: []cluster.PersistentVolumeClaim{
 			{CommonMeta: model_v3.CommonMeta{Name: "pvc-1", Namespace: "default"}},
 			{CommonMeta: model_v3.CommonMeta{Name: "pvc-2", Namespace: "kube-system"}},
@@ -657,6 +699,10 @@ func TestNamespaceFilter_SmokeAll(t *testing.T) {
 			r, e := svc.GetCronJobs(ctx, "cluster-1", "")
 			return len(r), e
 		}},
+		{"HPAs", func() (int, error) {
+			r, e := svc.GetHPAs(ctx, "cluster-1", "")
+			return len(r), e
+		}},
 		{"PersistentVolumeClaims", func() (int, error) {
 			r, e := svc.GetPersistentVolumeClaims(ctx, "cluster-1", "")
 			return len(r), e
@@ -732,6 +778,10 @@ func TestNamespaceFilter_SmokeFiltered(t *testing.T) {
 			r, e := svc.GetCronJobs(ctx, "cluster-1", "default")
 			return len(r), e
 		}, 2},
+		{"HPAs", func() (int, error) {
+			r, e := svc.GetHPAs(ctx, "cluster-1", "default")
+			return len(r), e
+		}, 2},
 		{"PersistentVolumeClaims", func() (int, error) {
 			r, e := svc.GetPersistentVolumeClaims(ctx, "cluster-1", "default")
 			return len(r), e
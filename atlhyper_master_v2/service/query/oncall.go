@@ -0,0 +1,65 @@
+// atlhyper_master_v2/service/query/oncall.go
+// 值班表、升级策略与告警升级记录查询
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/notifier"
+)
+
+func (q *QueryService) ListOncallSchedules(ctx context.Context) ([]*database.OncallSchedule, error) {
+	return q.oncallRepo.List(ctx)
+}
+
+func (q *QueryService) GetOncallSchedule(ctx context.Context, id int64) (*database.OncallSchedule, error) {
+	return q.oncallRepo.GetByID(ctx, id)
+}
+
+func (q *QueryService) ListOncallOverrides(ctx context.Context, scheduleID int64) ([]*database.OncallOverride, error) {
+	return q.oncallOverrideRepo.ListBySchedule(ctx, scheduleID)
+}
+
+func (q *QueryService) GetOncallOverride(ctx context.Context, id int64) (*database.OncallOverride, error) {
+	return q.oncallOverrideRepo.GetByID(ctx, id)
+}
+
+// CurrentOncall 计算 at 时刻的主 / 副值班（值班表不存在返回 nil）
+func (q *QueryService) CurrentOncall(ctx context.Context, scheduleID int64, at time.Time) (*notifier.OncallShift, error) {
+	s, err := q.oncallRepo.GetByID(ctx, scheduleID)
+	if err != nil || s == nil {
+		return nil, err
+	}
+	overrides, err := q.oncallOverrideRepo.ListActive(ctx, scheduleID, at)
+	if err != nil {
+		return nil, fmt.Errorf("list overrides: %w", err)
+	}
+	return notifier.ResolveOncall(s, overrides, at)
+}
+
+func (q *QueryService) ListEscalationPolicies(ctx context.Context) ([]*database.EscalationPolicy, error) {
+	return q.escalationPolicy.List(ctx)
+}
+
+func (q *QueryService) GetEscalationPolicy(ctx context.Context, id int64) (*database.EscalationPolicy, error) {
+	return q.escalationPolicy.GetByID(ctx, id)
+}
+
+func (q *QueryService) ListEscalations(ctx context.Context, opts database.EscalationQueryOpts) ([]*database.Escalation, error) {
+	return q.escalationRepo.List(ctx, opts)
+}
+
+func (q *QueryService) CountEscalations(ctx context.Context, opts database.EscalationQueryOpts) (int64, error) {
+	return q.escalationRepo.Count(ctx, opts)
+}
+
+func (q *QueryService) GetEscalation(ctx context.Context, id int64) (*database.Escalation, error) {
+	return q.escalationRepo.GetByID(ctx, id)
+}
+
+func (q *QueryService) GetEscalationByToken(ctx context.Context, token string) (*database.Escalation, error) {
+	return q.escalationRepo.GetByToken(ctx, token)
+}
@@ -4,21 +4,44 @@ package query
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"AtlHyper/atlhyper_master_v2/rbac"
 	"AtlHyper/model_v3/cluster"
 )
 
 // GetOTelSnapshot 从内存快照中读取 OTel 数据
+// 请求带有角色绑定范围时，无权访问的集群返回 ErrForbidden，命名空间级绑定只保留授权命名空间的数据
 func (q *QueryService) GetOTelSnapshot(ctx context.Context, clusterID string) (*cluster.OTelSnapshot, error) {
+	scope := rbac.ScopeFrom(ctx)
+	if scope != nil && !scope.AllowsCluster(clusterID) {
+		return nil, fmt.Errorf("%w: cluster %s", rbac.ErrForbidden, clusterID)
+	}
 	snapshot, err := q.store.GetSnapshot(clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
+	if scope != nil {
+		return snapshot.OTel.FilterNamespaces(scope.NamespaceFilter(clusterID)), nil
+	}
 	return snapshot.OTel, nil
 }
 
-// GetOTelTimeline 获取 OTel 时间线数据
+// GetOTelTimeline 获取 OTel 时间线数据（按角色绑定范围裁剪，规则同 GetOTelSnapshot）
 func (q *QueryService) GetOTelTimeline(ctx context.Context, clusterID string, since time.Time) ([]cluster.OTelEntry, error) {
-	return q.store.GetOTelTimeline(clusterID, since)
+	scope := rbac.ScopeFrom(ctx)
+	if scope != nil && !scope.AllowsCluster(clusterID) {
+		return nil, fmt.Errorf("%w: cluster %s", rbac.ErrForbidden, clusterID)
+	}
+	entries, err := q.store.GetOTelTimeline(clusterID, since)
+	if err != nil || scope == nil {
+		return entries, err
+	}
+	allow := scope.NamespaceFilter(clusterID)
+	filtered := make([]cluster.OTelEntry, len(entries))
+	for i, e := range entries {
+		filtered[i] = cluster.OTelEntry{Snapshot: e.Snapshot.FilterNamespaces(allow), Timestamp: e.Timestamp}
+	}
+	return filtered, nil
 }
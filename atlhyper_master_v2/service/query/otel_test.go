@@ -0,0 +1,168 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/rbac"
+	"AtlHyper/model_v3/apm"
+	"AtlHyper/model_v3/cluster"
+	"AtlHyper/model_v3/log"
+	"AtlHyper/model_v3/metrics"
+	"AtlHyper/model_v3/slo"
+)
+
+// otelTimelineStore 在 mockStoreForK8s 基础上返回固定的 OTel 时间线
+type otelTimelineStore struct {
+	*mockStoreForK8s
+	entries []cluster.OTelEntry
+}
+
+func (m *otelTimelineStore) GetOTelTimeline(clusterID string, since time.Time) ([]cluster.OTelEntry, error) {
+	return m.entries, nil
+}
+
+func newOTelSvc() *QueryService {
+	otel := &cluster.OTelSnapshot{
+		TotalServices:  3,
+		MetricsSummary: &metrics.Summary{},
+		MetricsNodes:   []metrics.NodeMetrics{{}},
+		APMServices: []apm.APMService{
+			{Name: "api", Namespace: "prod"},
+			{Name: "web", Namespace: "dev"},
+			{Name: "gateway", Namespace: "prod"},
+			{Name: "gateway", Namespace: "infra"},
+		},
+		APMTopology: &apm.Topology{
+			Nodes: []apm.TopologyNode{
+				{Id: "prod/api", Namespace: "prod"},
+				{Id: "dev/web", Namespace: "dev"},
+			},
+			Edges: []apm.TopologyEdge{{Source: "dev/web", Target: "prod/api"}},
+		},
+		SLOIngress:  []slo.IngressSLO{{ServiceKey: "prod-api-80@kubernetes"}},
+		SLOServices: []slo.ServiceSLO{{Name: "api", Namespace: "prod"}, {Name: "web", Namespace: "dev"}},
+		SLOEdges: []slo.ServiceEdge{
+			{SrcNamespace: "prod", SrcName: "api", DstNamespace: "prod", DstName: "db"},
+			{SrcNamespace: "dev", SrcName: "web", DstNamespace: "prod", DstName: "api"},
+		},
+		APMOperations: []apm.OperationStats{{ServiceName: "api"}, {ServiceName: "web"}, {ServiceName: "gateway"}},
+		RecentTraces: []apm.TraceSummary{
+			{TraceId: "t1", RootService: "api", Services: []string{"api"}},
+			{TraceId: "t2", RootService: "api", Services: []string{"api", "web"}},
+		},
+		RecentLogs: []log.Entry{
+			{ServiceName: "web", Resource: map[string]string{"k8s.namespace.name": "prod"}},
+			{ServiceName: "web"},
+			{ServiceName: "unknown"},
+		},
+		SLOWindows: map[string]*slo.SLOWindowData{
+			"1d": {
+				Current:      []slo.IngressSLO{{ServiceKey: "dev-web-80@kubernetes"}},
+				MeshServices: []slo.ServiceSLO{{Name: "api", Namespace: "prod"}, {Name: "web", Namespace: "dev"}},
+			},
+		},
+		NodeMetricsSeries: []cluster.NodeMetricsTimeSeries{{NodeName: "node-1"}},
+		SLOTimeSeries:     []cluster.SLOServiceTimeSeries{{ServiceName: "api"}, {ServiceName: "web"}},
+		APMTimeSeries:     []cluster.APMServiceTimeSeries{{ServiceName: "api", Namespace: "prod"}, {ServiceName: "web", Namespace: "dev"}},
+	}
+	return &QueryService{store: &otelTimelineStore{
+		mockStoreForK8s: &mockStoreForK8s{
+			snapshots: map[string]*cluster.ClusterSnapshot{"cluster-1": {ClusterID: "cluster-1", OTel: otel}},
+		},
+		entries: []cluster.OTelEntry{{Snapshot: otel, Timestamp: time.Unix(100, 0)}},
+	}}
+}
+
+func TestGetOTelSnapshot_Scope(t *testing.T) {
+	svc := newOTelSvc()
+	ctx := rbac.WithScope(context.Background(), &rbac.Scope{
+		Bindings: []rbac.Binding{{ClusterID: "cluster-1", Namespace: "prod", Role: rbac.RoleViewer}},
+	})
+
+	otel, err := svc.GetOTelSnapshot(ctx, "cluster-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 按命名空间过滤的服务与拓扑
+	if len(otel.APMServices) != 2 || otel.APMServices[0].Name != "api" || otel.APMServices[1].Name != "gateway" {
+		t.Errorf("APMServices = %+v", otel.APMServices)
+	}
+	if len(otel.APMTopology.Nodes) != 1 || len(otel.APMTopology.Edges) != 0 {
+		t.Errorf("APMTopology = %+v", otel.APMTopology)
+	}
+	if len(otel.SLOServices) != 1 || len(otel.SLOEdges) != 1 || otel.SLOEdges[0].DstName != "db" {
+		t.Errorf("SLOServices = %+v, SLOEdges = %+v", otel.SLOServices, otel.SLOEdges)
+	}
+	if len(otel.APMTimeSeries) != 1 || otel.APMTimeSeries[0].ServiceName != "api" {
+		t.Errorf("APMTimeSeries = %+v", otel.APMTimeSeries)
+	}
+
+	// 按服务名解析命名空间：gateway 同时存在于未授权的 infra，被去除
+	if len(otel.APMOperations) != 1 || otel.APMOperations[0].ServiceName != "api" {
+		t.Errorf("APMOperations = %+v", otel.APMOperations)
+	}
+	if len(otel.RecentTraces) != 1 || otel.RecentTraces[0].TraceId != "t1" {
+		t.Errorf("RecentTraces = %+v", otel.RecentTraces)
+	}
+	if len(otel.RecentLogs) != 1 || otel.RecentLogs[0].Resource["k8s.namespace.name"] != "prod" {
+		t.Errorf("RecentLogs = %+v", otel.RecentLogs)
+	}
+	if len(otel.SLOTimeSeries) != 1 || otel.SLOTimeSeries[0].ServiceName != "api" {
+		t.Errorf("SLOTimeSeries = %+v", otel.SLOTimeSeries)
+	}
+
+	// 多窗口只保留 Mesh 数据
+	w := otel.SLOWindows["1d"]
+	if w == nil || len(w.Current) != 0 || len(w.MeshServices) != 1 {
+		t.Errorf("SLOWindows[1d] = %+v", w)
+	}
+
+	// 集群级数据去除
+	if otel.TotalServices != 0 || otel.MetricsSummary != nil || len(otel.MetricsNodes) != 0 ||
+		len(otel.NodeMetricsSeries) != 0 || len(otel.SLOIngress) != 0 {
+		t.Errorf("cluster-level data should be dropped: %+v", otel)
+	}
+
+	// 时间线中的每个快照按同样规则裁剪
+	entries, err := svc.GetOTelTimeline(ctx, "cluster-1", time.Time{})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("GetOTelTimeline = %v (err=%v)", entries, err)
+	}
+	if entries[0].Timestamp.Unix() != 100 || len(entries[0].Snapshot.APMServices) != 2 || len(entries[0].Snapshot.MetricsNodes) != 0 {
+		t.Errorf("timeline entry = %+v", entries[0])
+	}
+
+	// 原快照不被修改
+	snapshot, _ := svc.store.GetSnapshot("cluster-1")
+	if len(snapshot.OTel.APMServices) != 4 || len(snapshot.OTel.SLOWindows["1d"].Current) != 1 {
+		t.Error("source snapshot must not be modified")
+	}
+}
+
+func TestGetOTelSnapshot_ScopeCluster(t *testing.T) {
+	svc := newOTelSvc()
+
+	// 集群级绑定原样返回
+	clusterCtx := rbac.WithScope(context.Background(), &rbac.Scope{
+		Bindings: []rbac.Binding{{ClusterID: "cluster-1", Role: rbac.RoleViewer}},
+	})
+	otel, err := svc.GetOTelSnapshot(clusterCtx, "cluster-1")
+	if err != nil || otel == nil || len(otel.APMServices) != 4 || otel.MetricsSummary == nil {
+		t.Fatalf("cluster-wide binding should see full snapshot, got %+v (err=%v)", otel, err)
+	}
+
+	// 未授权集群返回 ErrForbidden
+	other := rbac.WithScope(context.Background(), &rbac.Scope{
+		Bindings: []rbac.Binding{{ClusterID: "cluster-2", Role: rbac.RoleViewer}},
+	})
+	if _, err := svc.GetOTelSnapshot(other, "cluster-1"); !errors.Is(err, rbac.ErrForbidden) {
+		t.Errorf("GetOTelSnapshot: expected ErrForbidden, got %v", err)
+	}
+	if _, err := svc.GetOTelTimeline(other, "cluster-1", time.Time{}); !errors.Is(err, rbac.ErrForbidden) {
+		t.Errorf("GetOTelTimeline: expected ErrForbidden, got %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/rbac"
 	model_v3 "AtlHyper/model_v3"
 	"AtlHyper/model_v3/agent"
 	"AtlHyper/model_v3/cluster"
@@ -23,8 +24,12 @@ func (q *QueryService) ListClusters(ctx context.Context) ([]agent.ClusterInfo, e
 		return nil, err
 	}
 
+	scope := rbac.ScopeFrom(ctx)
 	result := make([]agent.ClusterInfo, 0, len(agents))
 	for _, a := range agents {
+		if !scope.AllowsCluster(a.ClusterID) {
+			continue
+		}
 		info := agent.ClusterInfo{
 			ClusterID: a.ClusterID,
 			Status:    a.Status,
@@ -76,16 +81,20 @@ func (q *QueryService) GetCommandStatus(ctx context.Context, commandID string) (
 
 // ==================== Event 查询 ====================
 
-// GetEvents 获取实时 Events
+// GetEvents 获取实时 Events（按角色绑定范围过滤涉及对象所在命名空间）
 func (q *QueryService) GetEvents(ctx context.Context, clusterID string, opts model.EventQueryOpts) ([]cluster.Event, error) {
 	events, err := q.store.GetEvents(clusterID)
 	if err != nil {
 		return nil, err
 	}
 
+	scope := rbac.ScopeFrom(ctx)
 	result := make([]cluster.Event, 0, len(events))
 	for _, e := range events {
 		// 过滤
+		if !scope.Allows(clusterID, e.InvolvedObject.Namespace, rbac.RoleViewer) {
+			continue
+		}
 		if opts.Type != "" && e.Type != opts.Type {
 			continue
 		}
@@ -109,8 +118,11 @@ func (q *QueryService) GetEvents(ctx context.Context, clusterID string, opts mod
 	return result, nil
 }
 
-// GetEventsByResource 按资源查询 Events
+// GetEventsByResource 按资源查询 Events（资源超出角色绑定范围时返回空）
 func (q *QueryService) GetEventsByResource(ctx context.Context, clusterID, kind, namespace, name string) ([]cluster.Event, error) {
+	if !rbac.ScopeFrom(ctx).Allows(clusterID, namespace, rbac.RoleViewer) {
+		return []cluster.Event{}, nil
+	}
 	events, err := q.store.GetEvents(clusterID)
 	if err != nil {
 		return nil, err
@@ -131,7 +143,7 @@ func (q *QueryService) GetEventsByResource(ctx context.Context, clusterID, kind,
 // GetOverview 获取集群概览
 func (q *QueryService) GetOverview(ctx context.Context, clusterID string) (*cluster.ClusterOverview, error) {
 	// 获取快照
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil {
 		return nil, err
 	}
@@ -419,7 +431,7 @@ func (q *QueryService) GetOverview(ctx context.Context, clusterID string) (*clus
 
 // GetPod 获取单个 Pod
 func (q *QueryService) GetPod(ctx context.Context, clusterID, namespace, name string) (*cluster.Pod, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -435,7 +447,7 @@ func (q *QueryService) GetPod(ctx context.Context, clusterID, namespace, name st
 
 // GetNode 获取单个 Node
 func (q *QueryService) GetNode(ctx context.Context, clusterID, name string) (*cluster.Node, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -451,7 +463,7 @@ func (q *QueryService) GetNode(ctx context.Context, clusterID, name string) (*cl
 
 // GetDeployment 获取单个 Deployment
 func (q *QueryService) GetDeployment(ctx context.Context, clusterID, namespace, name string) (*cluster.Deployment, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -468,7 +480,7 @@ func (q *QueryService) GetDeployment(ctx context.Context, clusterID, namespace,
 // GetDeploymentByReplicaSet 通过 ReplicaSet 名称查找所属 Deployment
 // ReplicaSet 名称格式: {deployment-name}-{hash}
 func (q *QueryService) GetDeploymentByReplicaSet(ctx context.Context, clusterID, namespace, rsName string) (*cluster.Deployment, error) {
-	snapshot, err := q.store.GetSnapshot(clusterID)
+	snapshot, err := q.snapshotAt(ctx, clusterID)
 	if err != nil || snapshot == nil {
 		return nil, err
 	}
@@ -12,6 +12,7 @@ import (
 
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/rbac"
 )
 
 // ==================== Mock: datahub.Store (overview 专用) ====================
@@ -27,6 +28,13 @@ type mockStoreForOverview struct {
 func (m *mockStoreForOverview) SetSnapshot(clusterID string, snapshot *cluster.ClusterSnapshot) error {
 	return nil
 }
+func (m *mockStoreForOverview) ApplySnapshotDelta(clusterID string, delta *cluster.SnapshotDelta) (*cluster.ClusterSnapshot, error) {
+	return nil, nil
+}
+func (m *mockStoreForOverview) GetSnapshotAt(clusterID string, at time.Time) (*cluster.ClusterSnapshot, error) {
+	return m.GetSnapshot(clusterID)
+}
+func (m *mockStoreForOverview) ListSnapshotHistory(clusterID string) ([]time.Time, error) { return nil, nil }
 func (m *mockStoreForOverview) GetSnapshot(clusterID string) (*cluster.ClusterSnapshot, error) {
 	if m.snapshots != nil {
 		return m.snapshots[clusterID], nil
@@ -466,6 +474,32 @@ func TestGetEvents_NoSnapshot(t *testing.T) {
 
 // --- GetEventsByResource ---
 
+func TestGetEvents_Scope(t *testing.T) {
+	store := &mockStoreForOverview{
+		events: map[string][]cluster.Event{"cluster-1": makeTestEvents()},
+	}
+	svc := &QueryService{store: store}
+	ctx := rbac.WithScope(context.Background(), &rbac.Scope{
+		Bindings: []rbac.Binding{{ClusterID: "cluster-1", Namespace: "kube-system", Role: rbac.RoleViewer}},
+	})
+
+	result, err := svc.GetEvents(ctx, "cluster-1", model.EventQueryOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 1 || result[0].Name != "evt-3" {
+		t.Fatalf("expected only kube-system event evt-3, got %+v", result)
+	}
+
+	byResource, err := svc.GetEventsByResource(ctx, "cluster-1", "Pod", "default", "nginx")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(byResource) != 0 {
+		t.Errorf("expected no events outside scope, got %d", len(byResource))
+	}
+}
+
 func TestGetEventsByResource_Found(t *testing.T) {
 	events := makeTestEvents()
 	store := &mockStoreForOverview{
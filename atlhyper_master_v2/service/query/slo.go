@@ -11,6 +11,7 @@ import (
 
 	"AtlHyper/atlhyper_master_v2/database"
 	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/atlhyper_master_v2/rbac"
 	slomodel "AtlHyper/model_v3/slo"
 )
 
@@ -217,6 +218,22 @@ func (q *QueryService) GetSLOTargets(ctx context.Context, clusterID string) ([]m
 	return result, nil
 }
 
+// GetSLOBurnAlerts 获取触发中的 SLO 燃烧率告警（clusterID 为空返回全部授权集群）
+func (q *QueryService) GetSLOBurnAlerts(ctx context.Context, clusterID string) ([]model.SLOBurnAlert, error) {
+	if q.sloBurn == nil {
+		return []model.SLOBurnAlert{}, nil
+	}
+	scope := rbac.ScopeFrom(ctx)
+	alerts := q.sloBurn.Alerts(clusterID)
+	result := alerts[:0]
+	for _, a := range alerts {
+		if scope.AllowsCluster(a.ClusterID) {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
 // GetSLORouteMappingByServiceKey 按 ServiceKey 查询路由映射
 func (q *QueryService) GetSLORouteMappingByServiceKey(ctx context.Context, clusterID, serviceKey string) (*model.SLORouteMapping, error) {
 	m, err := q.sloRepo.GetRouteMappingByServiceKey(ctx, clusterID, serviceKey)
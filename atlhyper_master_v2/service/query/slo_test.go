@@ -397,6 +397,13 @@ type mockStoreForSLO struct {
 func (m *mockStoreForSLO) SetSnapshot(clusterID string, snapshot *cluster.ClusterSnapshot) error {
 	return nil
 }
+func (m *mockStoreForSLO) ApplySnapshotDelta(clusterID string, delta *cluster.SnapshotDelta) (*cluster.ClusterSnapshot, error) {
+	return nil, nil
+}
+func (m *mockStoreForSLO) GetSnapshotAt(clusterID string, at time.Time) (*cluster.ClusterSnapshot, error) {
+	return m.GetSnapshot(clusterID)
+}
+func (m *mockStoreForSLO) ListSnapshotHistory(clusterID string) ([]time.Time, error) { return nil, nil }
 func (m *mockStoreForSLO) GetSnapshot(clusterID string) (*cluster.ClusterSnapshot, error) {
 	return m.snapshot, nil
 }
@@ -0,0 +1,85 @@
+// atlhyper_master_v2/service/query/snapshot_history.go
+// 快照历史查询实现（时间回溯 + 状态对比）
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/model_v3/cluster"
+)
+
+// GetSnapshotHistory 列出已保存的历史快照时间点
+func (q *QueryService) GetSnapshotHistory(ctx context.Context, clusterID string) ([]time.Time, error) {
+	return q.store.ListSnapshotHistory(clusterID)
+}
+
+// ListHistorySnapshots 按时间升序返回 [from, to] 内保存的历史快照
+func (q *QueryService) ListHistorySnapshots(ctx context.Context, clusterID string, from, to time.Time) ([]*cluster.ClusterSnapshot, error) {
+	times, err := q.store.ListSnapshotHistory(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []*cluster.ClusterSnapshot
+	for _, at := range times {
+		if at.Before(from) || at.After(to) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		snap, err := q.store.GetSnapshotAt(clusterID, at)
+		if err != nil {
+			return nil, fmt.Errorf("load snapshot at %s: %w", at.Format(time.RFC3339), err)
+		}
+		if snap != nil {
+			snapshots = append(snapshots, snap)
+		}
+	}
+	return snapshots, nil
+}
+
+// DiffSnapshots 对比两个时间点的集群状态
+// 任一时间点超出历史保留范围时返回 nil
+func (q *QueryService) DiffSnapshots(ctx context.Context, clusterID string, from, to time.Time) (*model.SnapshotDiff, error) {
+	before, err := q.store.GetSnapshotAt(clusterID, from)
+	if err != nil || before == nil {
+		return nil, err
+	}
+	after, err := q.store.GetSnapshotAt(clusterID, to)
+	if err != nil || after == nil {
+		return nil, err
+	}
+
+	index, err := cluster.IndexSnapshot(before)
+	if err != nil {
+		return nil, fmt.Errorf("index snapshot: %w", err)
+	}
+	changes, _, err := cluster.DiffSnapshot(index, after)
+	if err != nil {
+		return nil, fmt.Errorf("diff snapshot: %w", err)
+	}
+
+	summary := make(map[string]map[cluster.ChangeOp]int)
+	for _, c := range changes {
+		if summary[c.Kind] == nil {
+			summary[c.Kind] = make(map[cluster.ChangeOp]int)
+		}
+		summary[c.Kind][c.Op]++
+	}
+	if changes == nil {
+		changes = []cluster.ResourceChange{}
+	}
+
+	return &model.SnapshotDiff{
+		ClusterID:     clusterID,
+		From:          from,
+		To:            to,
+		FromFetchedAt: before.FetchedAt,
+		ToFetchedAt:   after.FetchedAt,
+		Summary:       summary,
+		Changes:       changes,
+	}, nil
+}
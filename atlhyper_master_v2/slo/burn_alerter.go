@@ -0,0 +1,330 @@
+// Package slo SLO 燃烧率告警评估器
+//
+// burn_alerter.go - 周期性评估所有配置了 SLO 目标的服务，维护触发中的告警
+//
+// 同一 (集群, 服务, 规则) 只在状态变化时通知监听器（触发 / 解除），实现去重。
+// 触发中的告警保存在内存中，供 /api/v2/slo/alerts 查询。
+package slo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/datahub"
+	"AtlHyper/atlhyper_master_v2/model"
+	"AtlHyper/common/logger"
+)
+
+var burnLog = logger.Module("SLOBurn")
+
+// 目标时间范围优先级：错误预算按最长周期计算
+var budgetWindows = []string{"30d", "7d", "1d"}
+
+// BurnAlertListener 告警状态变化监听器（firing=false 表示解除）
+type BurnAlertListener func(alert model.SLOBurnAlert, firing bool)
+
+// BurnAlerterConfig 燃烧率评估配置
+type BurnAlerterConfig struct {
+	Interval time.Duration  // 评估间隔（默认 1min）
+	Rules    []BurnRateRule // 规则（默认 DefaultBurnRateRules）
+}
+
+// burnAlert 触发中的告警（内部状态）
+type burnAlert struct {
+	clusterID     string
+	serviceKey    string
+	namespace     string // ServiceKey 对应的 Service（首次触发时解析）
+	serviceName   string
+	rule          BurnRateRule
+	target        float64
+	budgetWindow  string
+	longBurnRate  float64
+	shortBurnRate float64
+	firingSince   time.Time
+	lastEvaluated time.Time
+}
+
+// BurnAlerter SLO 燃烧率告警评估器
+type BurnAlerter struct {
+	store   datahub.Store
+	sloRepo database.SLORepository
+	config  BurnAlerterConfig
+
+	firing   map[string]*burnAlert // clusterID|serviceKey|rule -> alert
+	firingMu sync.RWMutex
+
+	listeners   []BurnAlertListener
+	listenersMu sync.RWMutex
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewBurnAlerter 创建燃烧率评估器
+func NewBurnAlerter(store datahub.Store, sloRepo database.SLORepository, cfg BurnAlerterConfig) *BurnAlerter {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if len(cfg.Rules) == 0 {
+		cfg.Rules = DefaultBurnRateRules
+	}
+	return &BurnAlerter{
+		store:   store,
+		sloRepo: sloRepo,
+		config:  cfg,
+		firing:  make(map[string]*burnAlert),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// AddListener 注册告警状态变化监听器（告警通知等）
+func (a *BurnAlerter) AddListener(fn BurnAlertListener) {
+	a.listenersMu.Lock()
+	a.listeners = append(a.listeners, fn)
+	a.listenersMu.Unlock()
+}
+
+// Start 启动周期评估
+func (a *BurnAlerter) Start() error {
+	a.wg.Add(1)
+	go a.loop()
+	burnLog.Info("SLO 燃烧率评估启动", "间隔", a.config.Interval, "规则", len(a.config.Rules))
+	return nil
+}
+
+// Stop 停止评估
+func (a *BurnAlerter) Stop() error {
+	close(a.stopCh)
+	a.wg.Wait()
+	burnLog.Info("SLO 燃烧率评估已停止")
+	return nil
+}
+
+// loop 评估循环
+func (a *BurnAlerter) loop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stopCh:
+			return
+		case <-ticker.C:
+			a.Evaluate(context.Background(), time.Now())
+		}
+	}
+}
+
+// Alerts 获取触发中的告警（clusterID 为空返回全部），按严重程度、集群、服务排序
+func (a *BurnAlerter) Alerts(clusterID string) []model.SLOBurnAlert {
+	a.firingMu.RLock()
+	result := make([]model.SLOBurnAlert, 0, len(a.firing))
+	for _, fa := range a.firing {
+		if clusterID == "" || fa.clusterID == clusterID {
+			result = append(result, fa.toModel())
+		}
+	}
+	a.firingMu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Severity != result[j].Severity {
+			return result[i].Severity == "critical"
+		}
+		if result[i].ClusterID != result[j].ClusterID {
+			return result[i].ClusterID < result[j].ClusterID
+		}
+		if result[i].ServiceKey != result[j].ServiceKey {
+			return result[i].ServiceKey < result[j].ServiceKey
+		}
+		return result[i].Rule < result[j].Rule
+	})
+	return result
+}
+
+// Evaluate 评估所有在线集群
+func (a *BurnAlerter) Evaluate(ctx context.Context, now time.Time) {
+	agents, err := a.store.ListAgents()
+	if err != nil {
+		burnLog.Error("获取 Agent 列表失败", "err", err)
+		return
+	}
+	for _, agent := range agents {
+		a.evaluateCluster(ctx, agent.ClusterID, now)
+	}
+}
+
+// evaluateCluster 评估单个集群
+// 仅对成功获取数据的集群更新告警状态，避免快照短暂缺失导致误解除
+func (a *BurnAlerter) evaluateCluster(ctx context.Context, clusterID string, now time.Time) {
+	snapshot, err := a.store.GetSnapshot(clusterID)
+	if err != nil || snapshot == nil || snapshot.OTel == nil {
+		return
+	}
+	targets, err := a.sloRepo.GetTargets(ctx, clusterID)
+	if err != nil {
+		burnLog.Error("获取 SLO 目标失败", "cluster", clusterID, "err", err)
+		return
+	}
+
+	active := make(map[string]bool)
+	for serviceKey, t := range pickBudgetTargets(targets) {
+		for _, rule := range a.config.Rules {
+			longRatio, okLong := WindowErrorRatio(snapshot.OTel, serviceKey, now, rule.LongWindow)
+			shortRatio, okShort := WindowErrorRatio(snapshot.OTel, serviceKey, now, rule.ShortWindow)
+			if !okLong || !okShort {
+				continue
+			}
+			longRate := BurnRate(longRatio, t.AvailabilityTarget)
+			shortRate := BurnRate(shortRatio, t.AvailabilityTarget)
+			if longRate < rule.Threshold || shortRate < rule.Threshold {
+				continue
+			}
+
+			key := burnKey(clusterID, serviceKey, rule.Name)
+			active[key] = true
+			a.fire(ctx, key, &burnAlert{
+				clusterID:     clusterID,
+				serviceKey:    serviceKey,
+				rule:          rule,
+				target:        t.AvailabilityTarget,
+				budgetWindow:  t.TimeRange,
+				longBurnRate:  longRate,
+				shortBurnRate: shortRate,
+				firingSince:   now,
+				lastEvaluated: now,
+			})
+		}
+	}
+
+	// 解除本集群中不再满足条件的告警
+	a.firingMu.Lock()
+	var resolved []*burnAlert
+	for key, fa := range a.firing {
+		if fa.clusterID == clusterID && !active[key] {
+			fa.lastEvaluated = now
+			resolved = append(resolved, fa)
+			delete(a.firing, key)
+		}
+	}
+	a.firingMu.Unlock()
+
+	for _, fa := range resolved {
+		burnLog.Info("SLO 燃烧率告警解除", "cluster", clusterID, "service", fa.serviceKey, "rule", fa.rule.Name)
+		a.notify(fa.toModel(), false)
+	}
+}
+
+// fire 记录触发中的告警，首次触发时解析所属 Service 并通知
+func (a *BurnAlerter) fire(ctx context.Context, key string, alert *burnAlert) {
+	a.firingMu.Lock()
+	if existing, ok := a.firing[key]; ok {
+		existing.target = alert.target
+		existing.budgetWindow = alert.budgetWindow
+		existing.longBurnRate = alert.longBurnRate
+		existing.shortBurnRate = alert.shortBurnRate
+		existing.lastEvaluated = alert.lastEvaluated
+		a.firingMu.Unlock()
+		return
+	}
+	a.firingMu.Unlock()
+
+	// 路由映射查询不持锁（评估串行执行，期间不会有同 key 的告警写入）
+	alert.namespace, alert.serviceName = a.serviceOf(ctx, alert.clusterID, alert.serviceKey)
+	a.firingMu.Lock()
+	a.firing[key] = alert
+	a.firingMu.Unlock()
+
+	burnLog.Warn("SLO 燃烧率告警触发", "cluster", alert.clusterID, "service", alert.serviceKey,
+		"rule", alert.rule.Name, "long", alert.longBurnRate, "short", alert.shortBurnRate)
+	a.notify(alert.toModel(), true)
+}
+
+// serviceOf 解析 ServiceKey 对应的 Service
+// Linkerd 服务为 "namespace/name"；Traefik 服务（"namespace-service-port@kubernetes"）查询 Ingress 路由映射
+func (a *BurnAlerter) serviceOf(ctx context.Context, clusterID, serviceKey string) (namespace, name string) {
+	if !strings.Contains(serviceKey, "@") {
+		if ns, svc, ok := strings.Cut(serviceKey, "/"); ok {
+			return ns, svc
+		}
+		return "", ""
+	}
+	m, err := a.sloRepo.GetRouteMappingByServiceKey(ctx, clusterID, serviceKey)
+	if err != nil || m == nil {
+		burnLog.Debug("ServiceKey 无路由映射", "cluster", clusterID, "service", serviceKey, "err", err)
+		return "", ""
+	}
+	return m.Namespace, m.ServiceName
+}
+
+// notify 通知所有监听器
+func (a *BurnAlerter) notify(alert model.SLOBurnAlert, firing bool) {
+	a.listenersMu.RLock()
+	defer a.listenersMu.RUnlock()
+	for _, fn := range a.listeners {
+		fn(alert, firing)
+	}
+}
+
+// pickBudgetTargets 每个服务选取一个目标（按 budgetWindows 优先级）
+func pickBudgetTargets(targets []*database.SLOTarget) map[string]*database.SLOTarget {
+	rank := func(tr string) int {
+		for i, w := range budgetWindows {
+			if w == tr {
+				return i
+			}
+		}
+		return len(budgetWindows)
+	}
+
+	result := make(map[string]*database.SLOTarget)
+	for _, t := range targets {
+		if t.AvailabilityTarget <= 0 || t.AvailabilityTarget >= 100 {
+			continue
+		}
+		if cur, ok := result[t.Host]; !ok || rank(t.TimeRange) < rank(cur.TimeRange) {
+			result[t.Host] = t
+		}
+	}
+	return result
+}
+
+// burnKey 告警去重键
+func burnKey(clusterID, serviceKey, rule string) string {
+	return clusterID + "|" + serviceKey + "|" + rule
+}
+
+// toModel 转换为 API 响应类型
+func (fa *burnAlert) toModel() model.SLOBurnAlert {
+	return model.SLOBurnAlert{
+		ClusterID:          fa.clusterID,
+		ServiceKey:         fa.serviceKey,
+		Namespace:          fa.namespace,
+		ServiceName:        fa.serviceName,
+		Rule:               fa.rule.Name,
+		Severity:           fa.rule.Severity,
+		LongWindow:         formatWindow(fa.rule.LongWindow),
+		ShortWindow:        formatWindow(fa.rule.ShortWindow),
+		Threshold:          fa.rule.Threshold,
+		LongBurnRate:       fa.longBurnRate,
+		ShortBurnRate:      fa.shortBurnRate,
+		AvailabilityTarget: fa.target,
+		BudgetWindow:       fa.budgetWindow,
+		FiringSince:        fa.firingSince.Format(time.RFC3339),
+		LastEvaluated:      fa.lastEvaluated.Format(time.RFC3339),
+	}
+}
+
+// formatWindow 窗口格式化（1h / 30m）
+func formatWindow(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%dm", int(d/time.Minute))
+}
@@ -0,0 +1,169 @@
+// Package slo SLO 燃烧率计算
+//
+// burnrate.go - 多窗口多燃烧率（Google SRE Workbook）告警规则与误差率计算
+//
+// 燃烧率 = 窗口内错误率 / 错误预算率（1 - 可用性目标）。
+// 长窗口与短窗口同时超过阈值才触发：长窗口保证显著性，短窗口保证恢复后及时解除。
+// 数据来源:
+//   - 窗口 ≤ 1h: OTelSnapshot.SLOTimeSeries（1 分钟粒度原始时序，最近 1h）
+//   - 窗口 > 1h: OTelSnapshot.SLOWindows["1d"].History（1 小时粒度聚合）
+package slo
+
+import (
+	"sort"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/model_v3/cluster"
+	slomodel "AtlHyper/model_v3/slo"
+)
+
+// 原始时序覆盖范围与小时聚合桶大小（与 Agent Concentrator / sloWindowConfigs 一致）
+const (
+	rawSeriesSpan = time.Hour
+	historyBucket = time.Hour
+	historyWindow = "1d"
+)
+
+// BurnRateRule 燃烧率告警规则（长短窗口对）
+type BurnRateRule struct {
+	Name        string        // fast / slow
+	LongWindow  time.Duration // 长窗口
+	ShortWindow time.Duration // 短窗口
+	Threshold   float64       // 燃烧率阈值
+	Severity    string        // critical / warning
+}
+
+// DefaultBurnRateRules 默认规则（30 天预算）
+// fast: 1h 内消耗 2% 预算；slow: 6h 内消耗 5% 预算
+var DefaultBurnRateRules = []BurnRateRule{
+	{Name: "fast", LongWindow: time.Hour, ShortWindow: 5 * time.Minute, Threshold: 14.4, Severity: "critical"},
+	{Name: "slow", LongWindow: 6 * time.Hour, ShortWindow: 30 * time.Minute, Threshold: 6, Severity: "warning"},
+}
+
+// BurnRate 计算燃烧率
+// errorRatio: 错误率 [0, 1]；availabilityTarget: 可用性目标（百分比，如 99.9）
+// 目标 ≥ 100 时错误预算为 0，返回 0（不告警）
+func BurnRate(errorRatio, availabilityTarget float64) float64 {
+	budget := 1 - availabilityTarget/100
+	if budget <= 0 {
+		return 0
+	}
+	return errorRatio / budget
+}
+
+// BudgetImpact 时间窗口内单个服务的错误预算消耗
+type BudgetImpact struct {
+	ServiceKey  string
+	Target      float64 // 可用性目标（百分比）
+	TimeRange   string  // 预算周期（"30d" / "7d" / "1d"）
+	ErrorRatio  float64 // 窗口内错误率 [0, 1]
+	BurnRate    float64 // 窗口内平均燃烧率
+	ConsumedPct float64 // 消耗的错误预算（占整个预算周期的百分比）
+}
+
+// BudgetPeriod 解析预算周期，无法识别时按 30d
+func BudgetPeriod(timeRange string) time.Duration {
+	switch timeRange {
+	case "1d":
+		return 24 * time.Hour
+	case "7d":
+		return 7 * 24 * time.Hour
+	default:
+		return 30 * 24 * time.Hour
+	}
+}
+
+// WindowBudgetImpact 计算 [start, end] 内各服务的错误预算消耗
+// 假设预算周期内流量均匀：消耗 = 燃烧率 × 窗口时长 / 预算周期。
+// 仅返回窗口内有数据且有消耗的服务，按消耗降序
+func WindowBudgetImpact(otel *cluster.OTelSnapshot, targets []*database.SLOTarget, start, end time.Time) []BudgetImpact {
+	window := end.Sub(start)
+	if otel == nil || window <= 0 {
+		return nil
+	}
+
+	var result []BudgetImpact
+	for serviceKey, t := range pickBudgetTargets(targets) {
+		ratio, ok := WindowErrorRatio(otel, serviceKey, end, window)
+		if !ok || ratio <= 0 {
+			continue
+		}
+		rate := BurnRate(ratio, t.AvailabilityTarget)
+		result = append(result, BudgetImpact{
+			ServiceKey:  serviceKey,
+			Target:      t.AvailabilityTarget,
+			TimeRange:   t.TimeRange,
+			ErrorRatio:  ratio,
+			BurnRate:    rate,
+			ConsumedPct: rate * window.Hours() / BudgetPeriod(t.TimeRange).Hours() * 100,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ConsumedPct != result[j].ConsumedPct {
+			return result[i].ConsumedPct > result[j].ConsumedPct
+		}
+		return result[i].ServiceKey < result[j].ServiceKey
+	})
+	return result
+}
+
+// WindowErrorRatio 计算指定服务在 [now-window, now] 内的错误率
+// 返回 ok=false 表示窗口内无请求或无数据
+func WindowErrorRatio(otel *cluster.OTelSnapshot, serviceKey string, now time.Time, window time.Duration) (ratio float64, ok bool) {
+	if otel == nil {
+		return 0, false
+	}
+	if window <= rawSeriesSpan {
+		for _, s := range otel.SLOTimeSeries {
+			if s.ServiceName == serviceKey {
+				return rawErrorRatio(s.Points, now, window)
+			}
+		}
+		return 0, false
+	}
+	w, exists := otel.SLOWindows[historyWindow]
+	if !exists || w == nil {
+		return 0, false
+	}
+	return historyErrorRatio(w.History, serviceKey, now, window)
+}
+
+// rawErrorRatio 1 分钟粒度时序的请求加权错误率
+func rawErrorRatio(points []cluster.SLOTimePoint, now time.Time, window time.Duration) (float64, bool) {
+	start := now.Add(-window)
+	var total, errors float64
+	for _, p := range points {
+		if !p.Timestamp.After(start) || p.Timestamp.After(now) {
+			continue
+		}
+		reqs := p.RPS * 60
+		total += reqs
+		errors += reqs * p.ErrorRate / 100
+	}
+	if total <= 0 {
+		return 0, false
+	}
+	return errors / total, true
+}
+
+// historyErrorRatio 小时聚合桶的请求加权错误率（与窗口有重叠的桶均计入）
+func historyErrorRatio(history []slomodel.SLOHistoryPoint, serviceKey string, now time.Time, window time.Duration) (float64, bool) {
+	start := now.Add(-window)
+	var total, errors float64
+	for _, p := range history {
+		if p.ServiceKey != serviceKey {
+			continue
+		}
+		if !p.Timestamp.Add(historyBucket).After(start) || p.Timestamp.After(now) {
+			continue
+		}
+		reqs := float64(p.TotalRequests)
+		total += reqs
+		errors += reqs * p.ErrorRate / 100
+	}
+	if total <= 0 {
+		return 0, false
+	}
+	return errors / total, true
+}
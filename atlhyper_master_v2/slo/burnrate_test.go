@@ -0,0 +1,213 @@
+package slo
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"AtlHyper/atlhyper_master_v2/database"
+	"AtlHyper/atlhyper_master_v2/datahub"
+	"AtlHyper/atlhyper_master_v2/model"
+	agentmodel "AtlHyper/model_v3/agent"
+	"AtlHyper/model_v3/cluster"
+	slomodel "AtlHyper/model_v3/slo"
+)
+
+const testServiceKey = "default-api-80@kubernetes"
+
+// burnStore 只实现评估器用到的方法
+type burnStore struct {
+	datahub.Store
+	snapshot *cluster.ClusterSnapshot
+}
+
+func (s *burnStore) ListAgents() ([]agentmodel.AgentInfo, error) {
+	return []agentmodel.AgentInfo{{ClusterID: "c1"}}, nil
+}
+
+func (s *burnStore) GetSnapshot(clusterID string) (*cluster.ClusterSnapshot, error) {
+	return s.snapshot, nil
+}
+
+type burnSLORepo struct {
+	database.SLORepository
+	targets []*database.SLOTarget
+}
+
+func (r *burnSLORepo) GetTargets(ctx context.Context, clusterID string) ([]*database.SLOTarget, error) {
+	return r.targets, nil
+}
+
+func (r *burnSLORepo) GetRouteMappingByServiceKey(ctx context.Context, clusterID, serviceKey string) (*database.SLORouteMapping, error) {
+	if serviceKey != testServiceKey {
+		return nil, nil
+	}
+	return &database.SLORouteMapping{ClusterID: clusterID, Namespace: "default", ServiceKey: serviceKey, ServiceName: "api"}, nil
+}
+
+// buildOTel 构建最近 1h 的分钟时序（shortErr 作用于最近 5 分钟）与最近 6h 的小时聚合
+func buildOTel(now time.Time, longErr, shortErr, hourlyErr float64) *cluster.OTelSnapshot {
+	points := make([]cluster.SLOTimePoint, 0, 60)
+	for i := 59; i >= 0; i-- {
+		errRate := longErr
+		if i < 5 {
+			errRate = shortErr
+		}
+		points = append(points, cluster.SLOTimePoint{
+			Timestamp: now.Add(-time.Duration(i) * time.Minute).Truncate(time.Minute),
+			RPS:       10,
+			ErrorRate: errRate,
+		})
+	}
+
+	var history []slomodel.SLOHistoryPoint
+	for i := 0; i < 24; i++ {
+		history = append(history, slomodel.SLOHistoryPoint{
+			Timestamp:     now.Truncate(time.Hour).Add(-time.Duration(i) * time.Hour),
+			ServiceKey:    testServiceKey,
+			TotalRequests: 36000,
+			ErrorRate:     hourlyErr,
+		})
+	}
+
+	return &cluster.OTelSnapshot{
+		SLOTimeSeries: []cluster.SLOServiceTimeSeries{{ServiceName: testServiceKey, Points: points}},
+		SLOWindows:    map[string]*slomodel.SLOWindowData{"1d": {History: history}},
+	}
+}
+
+func TestBurnRate(t *testing.T) {
+	// 99.9% 目标，1.44% 错误率 → 14.4x
+	if got := BurnRate(0.0144, 99.9); math.Abs(got-14.4) > 1e-6 {
+		t.Errorf("BurnRate = %v, want 14.4", got)
+	}
+	if got := BurnRate(0.5, 100); got != 0 {
+		t.Errorf("BurnRate with 100%% target = %v, want 0", got)
+	}
+}
+
+func TestWindowErrorRatio(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 30, 0, 0, time.UTC)
+	otel := buildOTel(now, 1, 10, 2)
+
+	ratio, ok := WindowErrorRatio(otel, testServiceKey, now, 5*time.Minute)
+	if !ok || math.Abs(ratio-0.1) > 1e-9 {
+		t.Errorf("5m ratio = %v, %v; want 0.1", ratio, ok)
+	}
+
+	// 1h: 55 分钟 1% + 5 分钟 10%
+	want := (55*0.01 + 5*0.1) / 60
+	ratio, ok = WindowErrorRatio(otel, testServiceKey, now, time.Hour)
+	if !ok || math.Abs(ratio-want) > 1e-9 {
+		t.Errorf("1h ratio = %v, %v; want %v", ratio, ok, want)
+	}
+
+	// 6h 走小时聚合
+	ratio, ok = WindowErrorRatio(otel, testServiceKey, now, 6*time.Hour)
+	if !ok || math.Abs(ratio-0.02) > 1e-9 {
+		t.Errorf("6h ratio = %v, %v; want 0.02", ratio, ok)
+	}
+
+	if _, ok := WindowErrorRatio(otel, "unknown", now, 5*time.Minute); ok {
+		t.Error("unknown service should have no data")
+	}
+}
+
+func TestWindowBudgetImpact(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 30, 0, 0, time.UTC)
+	otel := buildOTel(now, 1, 10, 2)
+	targets := []*database.SLOTarget{
+		{Host: testServiceKey, TimeRange: "7d", AvailabilityTarget: 99},
+		{Host: testServiceKey, TimeRange: "30d", AvailabilityTarget: 99.9},
+		{Host: "other-svc-80@kubernetes", TimeRange: "30d", AvailabilityTarget: 99.9},
+	}
+
+	// 事件窗口 30 分钟，均为 1% 错误率；按 30d / 99.9% 计：燃烧率 10，消耗 10 × 0.5h / 720h
+	end := now.Add(-5 * time.Minute)
+	impacts := WindowBudgetImpact(otel, targets, end.Add(-30*time.Minute), end)
+	if len(impacts) != 1 {
+		t.Fatalf("impacts = %+v, want 1 service with data", impacts)
+	}
+	got := impacts[0]
+	if got.TimeRange != "30d" || math.Abs(got.BurnRate-10) > 1e-6 {
+		t.Errorf("impact = %+v, want 30d target with burn rate 10", got)
+	}
+	if want := 10 * 0.5 / 720 * 100; math.Abs(got.ConsumedPct-want) > 1e-6 {
+		t.Errorf("ConsumedPct = %v, want %v", got.ConsumedPct, want)
+	}
+
+	if impacts := WindowBudgetImpact(otel, targets, end, end); impacts != nil {
+		t.Errorf("empty window should yield nil, got %+v", impacts)
+	}
+}
+
+func TestBurnAlerter_FireAndResolveOnce(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 30, 0, 0, time.UTC)
+	store := &burnStore{snapshot: &cluster.ClusterSnapshot{OTel: buildOTel(now, 2, 2, 0)}}
+	repo := &burnSLORepo{targets: []*database.SLOTarget{
+		{Host: testServiceKey, TimeRange: "1d", AvailabilityTarget: 99},
+		{Host: testServiceKey, TimeRange: "30d", AvailabilityTarget: 99.9},
+	}}
+
+	a := NewBurnAlerter(store, repo, BurnAlerterConfig{})
+	type event struct {
+		alert  model.SLOBurnAlert
+		firing bool
+	}
+	var events []event
+	a.AddListener(func(alert model.SLOBurnAlert, firing bool) {
+		events = append(events, event{alert, firing})
+	})
+
+	// 2% 错误率 / 0.1% 预算 = 20x：fast 触发；小时聚合无错误，slow 不触发
+	a.Evaluate(context.Background(), now)
+	a.Evaluate(context.Background(), now.Add(time.Minute))
+
+	if len(events) != 1 || !events[0].firing || events[0].alert.Rule != "fast" {
+		t.Fatalf("events = %+v, want single fast firing", events)
+	}
+	if events[0].alert.BudgetWindow != "30d" || events[0].alert.AvailabilityTarget != 99.9 {
+		t.Errorf("target = %v (%s), want 99.9 (30d)", events[0].alert.AvailabilityTarget, events[0].alert.BudgetWindow)
+	}
+	alerts := a.Alerts("c1")
+	if len(alerts) != 1 || alerts[0].Severity != "critical" {
+		t.Fatalf("Alerts = %+v, want one critical", alerts)
+	}
+	if alerts[0].Namespace != "default" || alerts[0].ServiceName != "api" {
+		t.Errorf("service = %s/%s, want default/api", alerts[0].Namespace, alerts[0].ServiceName)
+	}
+	if alerts[0].FiringSince != now.Format(time.RFC3339) {
+		t.Errorf("FiringSince = %s, want first evaluation time", alerts[0].FiringSince)
+	}
+
+	// 短窗口恢复 → 解除一次
+	store.snapshot = &cluster.ClusterSnapshot{OTel: buildOTel(now, 2, 0, 0)}
+	a.Evaluate(context.Background(), now.Add(2*time.Minute))
+	a.Evaluate(context.Background(), now.Add(3*time.Minute))
+
+	if len(events) != 2 || events[1].firing {
+		t.Fatalf("events = %+v, want fire then resolve", events)
+	}
+	if len(a.Alerts("")) != 0 {
+		t.Error("expected no firing alerts after resolve")
+	}
+}
+
+func TestBurnAlerter_MissingSnapshotKeepsState(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 30, 0, 0, time.UTC)
+	store := &burnStore{snapshot: &cluster.ClusterSnapshot{OTel: buildOTel(now, 2, 2, 2)}}
+	repo := &burnSLORepo{targets: []*database.SLOTarget{{Host: testServiceKey, TimeRange: "30d", AvailabilityTarget: 99.9}}}
+
+	a := NewBurnAlerter(store, repo, BurnAlerterConfig{})
+	a.Evaluate(context.Background(), now)
+	if got := len(a.Alerts("c1")); got != 2 {
+		t.Fatalf("firing = %d, want 2 (fast + slow)", got)
+	}
+
+	store.snapshot = nil
+	a.Evaluate(context.Background(), now.Add(time.Minute))
+	if got := len(a.Alerts("c1")); got != 2 {
+		t.Errorf("firing after missing snapshot = %d, want 2", got)
+	}
+}
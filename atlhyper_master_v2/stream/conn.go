@@ -0,0 +1,70 @@
+// atlhyper_master_v2/stream/conn.go
+// 会话流连接抽象
+// Gateway (浏览器) 与 AgentSDK (Agent) 两端的 WebSocket 都适配为 Conn，由 Relay 转发
+package stream
+
+import (
+	"sync"
+	"time"
+
+	"AtlHyper/model_v3/command"
+
+	"github.com/gorilla/websocket"
+)
+
+// 连接参数
+const (
+	writeTimeout  = 10 * time.Second
+	maxFrameBytes = 1 << 20 // 单帧上限 1MB
+)
+
+// Conn 双向帧连接
+//
+// ReadFrame 只允许单个 goroutine 调用；WriteFrame / Close 可并发调用。
+type Conn interface {
+	ReadFrame() (*command.ExecFrame, error)
+	WriteFrame(frame *command.ExecFrame) error
+	Close() error
+}
+
+// wsConn 基于 WebSocket 的 Conn 实现（JSON 文本消息）
+type wsConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+	once sync.Once
+}
+
+// NewWSConn 将已升级的 WebSocket 连接适配为 Conn
+func NewWSConn(conn *websocket.Conn) Conn {
+	conn.SetReadLimit(maxFrameBytes)
+	return &wsConn{conn: conn}
+}
+
+func (c *wsConn) ReadFrame() (*command.ExecFrame, error) {
+	var frame command.ExecFrame
+	if err := c.conn.ReadJSON(&frame); err != nil {
+		return nil, err
+	}
+	return &frame, nil
+}
+
+func (c *wsConn) WriteFrame(frame *command.ExecFrame) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return c.conn.WriteJSON(frame)
+}
+
+// Close 发送关闭控制帧后关闭底层连接（可重复调用）
+func (c *wsConn) Close() error {
+	var err error
+	c.once.Do(func() {
+		c.mu.Lock()
+		_ = c.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(time.Second))
+		c.mu.Unlock()
+		err = c.conn.Close()
+	})
+	return err
+}
@@ -0,0 +1,112 @@
+// atlhyper_master_v2/stream/hub.go
+// 会话配对中心
+//
+// 交互式会话采用回拨模型:
+//  1. Service 创建会话 ID 并调用 Expect 登记，随后通过指令队列通知 Agent
+//  2. Agent 收到指令后主动连接 AgentSDK，AgentSDK 调用 Attach 交付连接
+//  3. Service 通过 Waiter.Wait 取得 Agent 连接后开始转发
+//
+// Agent 只能连接到下发给自己集群的会话。
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// 错误定义
+var (
+	ErrSessionNotFound = errors.New("stream session not found")
+	ErrClusterMismatch = errors.New("stream session belongs to another cluster")
+)
+
+// Hub 会话配对中心
+type Hub struct {
+	mu      sync.Mutex
+	pending map[string]*Waiter
+}
+
+// NewHub 创建 Hub
+func NewHub() *Hub {
+	return &Hub{pending: make(map[string]*Waiter)}
+}
+
+// Waiter 等待 Agent 连接的会话
+type Waiter struct {
+	hub       *Hub
+	sessionID string
+	clusterID string
+	ch        chan Conn
+	done      chan struct{}
+	once      sync.Once
+}
+
+// Expect 登记等待中的会话
+func (h *Hub) Expect(sessionID, clusterID string) *Waiter {
+	w := &Waiter{
+		hub:       h,
+		sessionID: sessionID,
+		clusterID: clusterID,
+		ch:        make(chan Conn, 1),
+		done:      make(chan struct{}),
+	}
+	h.mu.Lock()
+	h.pending[sessionID] = w
+	h.mu.Unlock()
+	return w
+}
+
+// Attach 交付 Agent 连接，阻塞直到会话结束（Waiter.Done）或 ctx 取消
+//
+// 每个会话只接受一次 Attach。
+func (h *Hub) Attach(ctx context.Context, sessionID, clusterID string, conn Conn) error {
+	h.mu.Lock()
+	w, ok := h.pending[sessionID]
+	if !ok {
+		h.mu.Unlock()
+		return ErrSessionNotFound
+	}
+	if w.clusterID != clusterID {
+		h.mu.Unlock()
+		return ErrClusterMismatch
+	}
+	delete(h.pending, sessionID)
+	h.mu.Unlock()
+
+	w.ch <- conn
+
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+// Wait 等待 Agent 连接
+func (w *Waiter) Wait(ctx context.Context) (Conn, error) {
+	select {
+	case conn := <-w.ch:
+		return conn, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Done 结束会话：注销登记并释放 Attach；未被取走的连接直接关闭
+func (w *Waiter) Done() {
+	w.once.Do(func() {
+		w.hub.mu.Lock()
+		if w.hub.pending[w.sessionID] == w {
+			delete(w.hub.pending, w.sessionID)
+		}
+		w.hub.mu.Unlock()
+		close(w.done)
+
+		select {
+		case conn := <-w.ch:
+			conn.Close()
+		default:
+		}
+	})
+}
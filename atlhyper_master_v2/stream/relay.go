@@ -0,0 +1,142 @@
+// atlhyper_master_v2/stream/relay.go
+// 会话帧转发
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"AtlHyper/model_v3/command"
+)
+
+// 会话结束原因
+const (
+	EndExit              = "exit"               // 容器进程退出
+	EndClientClosed      = "client_closed"      // 客户端关闭
+	EndAgentDisconnected = "agent_disconnected" // Agent 连接断开
+	EndTimeout           = "timeout"            // 超过会话时长上限
+	EndError             = "error"              // Agent 报告错误
+)
+
+// RelayResult 转发结果
+type RelayResult struct {
+	EndReason string
+	ExitCode  *int
+	Error     string
+}
+
+// 各方向允许转发的帧类型
+var (
+	clientFrames = map[string]bool{
+		command.FrameStdin:  true,
+		command.FrameResize: true,
+		command.FrameClose:  true,
+	}
+	agentFrames = map[string]bool{
+		command.FrameStdout: true,
+		command.FrameStderr: true,
+		command.FrameLog:    true,
+		command.FrameExit:   true,
+		command.FrameError:  true,
+	}
+)
+
+// Relay 在客户端与 Agent 之间双向转发帧，直到任一方结束或 ctx 取消
+//
+// 超过 ctx 截止时间时向客户端发送 error 帧、向 Agent 发送 close 帧。
+// 返回前关闭两端连接。transcript 可为 nil。
+func Relay(ctx context.Context, client, agent Conn, transcript *Transcript) *RelayResult {
+	var (
+		once   sync.Once
+		result *RelayResult
+		ended  = make(chan struct{})
+	)
+	finish := func(r *RelayResult) {
+		once.Do(func() {
+			result = r
+			close(ended)
+		})
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// 客户端 → Agent
+	go func() {
+		defer wg.Done()
+		for {
+			frame, err := client.ReadFrame()
+			if err != nil {
+				agent.WriteFrame(&command.ExecFrame{Type: command.FrameClose})
+				finish(&RelayResult{EndReason: EndClientClosed})
+				return
+			}
+			if !clientFrames[frame.Type] {
+				continue
+			}
+			if frame.Type == command.FrameStdin {
+				transcript.Record(frame.Type, frame.Data)
+			}
+			if err := agent.WriteFrame(frame); err != nil {
+				finish(&RelayResult{EndReason: EndAgentDisconnected})
+				return
+			}
+			if frame.Type == command.FrameClose {
+				finish(&RelayResult{EndReason: EndClientClosed})
+				return
+			}
+		}
+	}()
+
+	// Agent → 客户端
+	go func() {
+		defer wg.Done()
+		for {
+			frame, err := agent.ReadFrame()
+			if err != nil {
+				client.WriteFrame(&command.ExecFrame{Type: command.FrameError, Error: "agent disconnected"})
+				finish(&RelayResult{EndReason: EndAgentDisconnected})
+				return
+			}
+			if !agentFrames[frame.Type] {
+				continue
+			}
+			switch frame.Type {
+			case command.FrameStdout, command.FrameStderr:
+				transcript.Record(frame.Type, frame.Data)
+			}
+			// 客户端写失败时继续读取，由客户端读取侧结束会话
+			client.WriteFrame(frame)
+
+			switch frame.Type {
+			case command.FrameExit:
+				code := frame.Code
+				finish(&RelayResult{EndReason: EndExit, ExitCode: &code, Error: frame.Error})
+				return
+			case command.FrameError:
+				finish(&RelayResult{EndReason: EndError, Error: frame.Error})
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-ended:
+	case <-ctx.Done():
+		reason := EndClientClosed
+		msg := ""
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			reason = EndTimeout
+			msg = "session time limit reached"
+			client.WriteFrame(&command.ExecFrame{Type: command.FrameError, Error: msg})
+		}
+		agent.WriteFrame(&command.ExecFrame{Type: command.FrameClose})
+		finish(&RelayResult{EndReason: reason, Error: msg})
+	}
+
+	client.Close()
+	agent.Close()
+	wg.Wait()
+	return result
+}
@@ -0,0 +1,179 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"AtlHyper/model_v3/command"
+)
+
+// pipeConn 内存 Conn：in 为对端写入的帧，out 记录本端收到的写入
+type pipeConn struct {
+	in     chan *command.ExecFrame
+	mu     sync.Mutex
+	out    []*command.ExecFrame
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newPipeConn() *pipeConn {
+	return &pipeConn{in: make(chan *command.ExecFrame, 16), closed: make(chan struct{})}
+}
+
+func (c *pipeConn) ReadFrame() (*command.ExecFrame, error) {
+	select {
+	case f := <-c.in:
+		return f, nil
+	case <-c.closed:
+		return nil, io.EOF
+	}
+}
+
+func (c *pipeConn) WriteFrame(f *command.ExecFrame) error {
+	select {
+	case <-c.closed:
+		return errors.New("closed")
+	default:
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.out = append(c.out, f)
+	return nil
+}
+
+func (c *pipeConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *pipeConn) written() []*command.ExecFrame {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*command.ExecFrame(nil), c.out...)
+}
+
+func TestRelay_ForwardsAndRecords(t *testing.T) {
+	client, agent := newPipeConn(), newPipeConn()
+	transcript := NewTranscript(0)
+
+	client.in <- &command.ExecFrame{Type: command.FrameStdin, Data: "ls\n"}
+	client.in <- &command.ExecFrame{Type: command.FrameStdout, Data: "spoofed"} // 客户端不允许发送 stdout
+
+	done := make(chan *RelayResult, 1)
+	go func() { done <- Relay(context.Background(), client, agent, transcript) }()
+
+	time.Sleep(20 * time.Millisecond)
+	agent.in <- &command.ExecFrame{Type: command.FrameStdout, Data: "a.txt\n"}
+	agent.in <- &command.ExecFrame{Type: command.FrameExit, Code: 3}
+
+	result := <-done
+	if result.EndReason != EndExit || result.ExitCode == nil || *result.ExitCode != 3 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	toAgent := agent.written()
+	if len(toAgent) != 1 || toAgent[0].Type != command.FrameStdin {
+		t.Errorf("agent should only receive the stdin frame, got %+v", toAgent)
+	}
+	toClient := client.written()
+	if len(toClient) != 2 || toClient[1].Type != command.FrameExit {
+		t.Errorf("client should receive stdout and exit, got %+v", toClient)
+	}
+
+	entries := transcript.Entries()
+	if len(entries) != 2 || entries[0].Stream != "stdin" || entries[1].Data != "a.txt\n" {
+		t.Errorf("unexpected transcript: %+v", entries)
+	}
+}
+
+func TestRelay_Timeout(t *testing.T) {
+	client, agent := newPipeConn(), newPipeConn()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result := Relay(ctx, client, agent, nil)
+	if result.EndReason != EndTimeout {
+		t.Fatalf("expected timeout, got %+v", result)
+	}
+	if w := client.written(); len(w) != 1 || w[0].Type != command.FrameError {
+		t.Errorf("client should be told about the time limit, got %+v", w)
+	}
+	if w := agent.written(); len(w) != 1 || w[0].Type != command.FrameClose {
+		t.Errorf("agent should receive close, got %+v", w)
+	}
+}
+
+func TestRelay_ClientDisconnect(t *testing.T) {
+	client, agent := newPipeConn(), newPipeConn()
+	client.Close()
+
+	result := Relay(context.Background(), client, agent, nil)
+	if result.EndReason != EndClientClosed {
+		t.Fatalf("expected client_closed, got %+v", result)
+	}
+}
+
+func TestTranscript_Truncates(t *testing.T) {
+	tr := NewTranscript(8)
+	tr.Record("stdout", "12345")
+	tr.Record("stdout", "67890")
+	tr.Record("stdout", "")
+
+	if len(tr.Entries()) != 1 || !tr.Truncated() {
+		t.Errorf("expected one entry and truncated flag, got %d entries truncated=%v", len(tr.Entries()), tr.Truncated())
+	}
+}
+
+func TestHub_AttachPairsSession(t *testing.T) {
+	hub := NewHub()
+	w := hub.Expect("s1", "cluster-a")
+
+	agent := newPipeConn()
+	if err := hub.Attach(context.Background(), "s1", "cluster-b", agent); err != ErrClusterMismatch {
+		t.Fatalf("expected cluster mismatch, got %v", err)
+	}
+	if err := hub.Attach(context.Background(), "missing", "cluster-a", agent); err != ErrSessionNotFound {
+		t.Fatalf("expected session not found, got %v", err)
+	}
+
+	attached := make(chan error, 1)
+	go func() { attached <- hub.Attach(context.Background(), "s1", "cluster-a", agent) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, err := w.Wait(ctx)
+	if err != nil || conn != agent {
+		t.Fatalf("wait: conn=%v err=%v", conn, err)
+	}
+
+	// 会话已配对，不能再次 Attach
+	if err := hub.Attach(context.Background(), "s1", "cluster-a", newPipeConn()); err != ErrSessionNotFound {
+		t.Errorf("expected second attach rejected, got %v", err)
+	}
+
+	w.Done()
+	select {
+	case err := <-attached:
+		if err != nil {
+			t.Errorf("attach returned %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("attach did not return after Done")
+	}
+}
+
+func TestHub_WaitTimeout(t *testing.T) {
+	hub := NewHub()
+	w := hub.Expect("s1", "cluster-a")
+	defer w.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := w.Wait(ctx); err == nil {
+		t.Error("expected wait to time out")
+	}
+}
@@ -0,0 +1,122 @@
+// atlhyper_master_v2/stream/sse.go
+// Server-Sent Events 单向连接
+// 日志跟踪等只读会话以 SSE 推送给浏览器；客户端断开 (请求 ctx 结束) 视为关闭会话
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"AtlHyper/model_v3/command"
+)
+
+// sseKeepAlive 空闲时发送注释行，避免反向代理因空闲断开连接
+var sseKeepAlive = 15 * time.Second
+
+// ErrClientGone 客户端已断开
+var ErrClientGone = errors.New("client disconnected")
+
+// SSEEvent 推送给浏览器的事件数据
+//
+// Color 为日志来源的颜色序号（按来源首次出现顺序从 0 递增），
+// 前端按序号取调色板，同一来源在整个会话中颜色不变。
+type SSEEvent struct {
+	*command.ExecFrame
+	Color *int `json:"color,omitempty"`
+}
+
+// sseConn 基于 SSE 的 Conn 实现
+type sseConn struct {
+	ctx    context.Context
+	w      http.ResponseWriter
+	rc     *http.ResponseController
+	mu     sync.Mutex
+	colors map[string]int
+	closed chan struct{}
+	once   sync.Once
+}
+
+// NewSSEConn 将 HTTP 响应适配为只写 Conn 并写出 SSE 响应头
+//
+// ReadFrame 不读取任何数据，阻塞到客户端断开或 Close 后返回错误，
+// Relay 据此向 Agent 发送 close 帧。
+func NewSSEConn(w http.ResponseWriter, r *http.Request) Conn {
+	rc := http.NewResponseController(w)
+	// 会话时长由 Relay 控制，取消 Server 级写超时
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx 禁用缓冲
+	w.WriteHeader(http.StatusOK)
+	_ = rc.Flush()
+
+	return &sseConn{
+		ctx:    r.Context(),
+		w:      w,
+		rc:     rc,
+		colors: make(map[string]int),
+		closed: make(chan struct{}),
+	}
+}
+
+func (c *sseConn) ReadFrame() (*command.ExecFrame, error) {
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return nil, ErrClientGone
+		case <-c.closed:
+			return nil, ErrClientGone
+		case <-ticker.C:
+			if err := c.write(": ping\n\n"); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (c *sseConn) WriteFrame(frame *command.ExecFrame) error {
+	event := SSEEvent{ExecFrame: frame}
+	if frame.Type == command.FrameLog && frame.Source != "" {
+		c.mu.Lock()
+		idx, ok := c.colors[frame.Source]
+		if !ok {
+			idx = len(c.colors)
+			c.colors[frame.Source] = idx
+		}
+		c.mu.Unlock()
+		event.Color = &idx
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return c.write(fmt.Sprintf("event: %s\ndata: %s\n\n", frame.Type, data))
+}
+
+func (c *sseConn) write(s string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-c.closed:
+		return ErrClientGone
+	default:
+	}
+	if _, err := c.w.Write([]byte(s)); err != nil {
+		return err
+	}
+	return c.rc.Flush()
+}
+
+func (c *sseConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
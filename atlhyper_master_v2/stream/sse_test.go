@@ -0,0 +1,64 @@
+package stream
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"AtlHyper/model_v3/command"
+)
+
+func TestSSEConn_WritesEventsWithColors(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v2/ops/logs/stream", nil)
+	conn := NewSSEConn(rec, req)
+
+	conn.WriteFrame(&command.ExecFrame{Type: command.FrameLog, Source: "web-1/app", Data: "a"})
+	conn.WriteFrame(&command.ExecFrame{Type: command.FrameLog, Source: "web-2/app", Data: "b"})
+	conn.WriteFrame(&command.ExecFrame{Type: command.FrameLog, Source: "web-1/app", Data: "c"})
+	conn.WriteFrame(&command.ExecFrame{Type: command.FrameExit})
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("content type: got %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`"source":"web-1/app","color":0`,
+		`"source":"web-2/app","color":1`,
+		`"data":"c","source":"web-1/app","color":0`,
+		"event: exit\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+	if n := strings.Count(body, "event: log\n"); n != 3 {
+		t.Errorf("expected 3 log events, got %d", n)
+	}
+}
+
+func TestSSEConn_ClientDisconnectEndsRelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v2/ops/logs/stream", nil).WithContext(ctx)
+	client := NewSSEConn(rec, req)
+	agent := newPipeConn()
+
+	done := make(chan *RelayResult, 1)
+	go func() { done <- Relay(context.Background(), client, agent, nil) }()
+
+	cancel()
+	select {
+	case result := <-done:
+		if result.EndReason != EndClientClosed {
+			t.Errorf("end reason: got %s, want client_closed", result.EndReason)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("relay did not end after client disconnect")
+	}
+	if w := agent.written(); len(w) == 0 || w[len(w)-1].Type != command.FrameClose {
+		t.Errorf("agent should receive close frame, got %+v", w)
+	}
+}
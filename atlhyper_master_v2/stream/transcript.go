@@ -0,0 +1,66 @@
+// atlhyper_master_v2/stream/transcript.go
+// 会话记录
+package stream
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultTranscriptLimit 默认记录上限（字节）
+const DefaultTranscriptLimit = 1 << 20
+
+// TranscriptEntry 单条记录
+type TranscriptEntry struct {
+	Time   time.Time `json:"time"`
+	Stream string    `json:"stream"` // stdin / stdout / stderr
+	Data   string    `json:"data"`
+}
+
+// Transcript 按时间顺序记录会话的输入输出，超过上限后丢弃并标记截断
+type Transcript struct {
+	mu        sync.Mutex
+	entries   []TranscriptEntry
+	size      int
+	limit     int
+	truncated bool
+}
+
+// NewTranscript 创建 Transcript，limit <= 0 时使用 DefaultTranscriptLimit
+func NewTranscript(limit int) *Transcript {
+	if limit <= 0 {
+		limit = DefaultTranscriptLimit
+	}
+	return &Transcript{limit: limit}
+}
+
+// Record 追加一条记录
+func (t *Transcript) Record(stream, data string) {
+	if t == nil || data == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.size+len(data) > t.limit {
+		t.truncated = true
+		return
+	}
+	t.size += len(data)
+	t.entries = append(t.entries, TranscriptEntry{Time: time.Now(), Stream: stream, Data: data})
+}
+
+// Entries 返回记录副本
+func (t *Transcript) Entries() []TranscriptEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]TranscriptEntry, len(t.entries))
+	copy(out, t.entries)
+	return out
+}
+
+// Truncated 是否因超过上限而截断
+func (t *Transcript) Truncated() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.truncated
+}
@@ -38,7 +38,7 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 
 // Test 执行测试
 // 路由: POST /test/{tester}/{target}
-// 例如: POST /test/notifier/slack
+// 例如: POST /test/notifier/1（渠道 ID）
 func (h *Handler) Test(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		h.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
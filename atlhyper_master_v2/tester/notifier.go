@@ -5,6 +5,7 @@ package tester
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	"AtlHyper/atlhyper_master_v2/notifier"
 )
@@ -27,13 +28,14 @@ func (t *NotifierTester) Name() string {
 }
 
 // Test 测试通知渠道
-// target 是渠道类型：slack, email 等
+// target 是渠道 ID（同类型可配置多个渠道）
 func (t *NotifierTester) Test(ctx context.Context, target string) Result {
-	if target == "" {
-		return NewFailureResult("channel type required")
+	channelID, err := strconv.ParseInt(target, 10, 64)
+	if err != nil {
+		return NewFailureResult("channel id required")
 	}
 
-	err := t.alertManager.Test(ctx, target)
+	err = t.alertManager.Test(ctx, channelID)
 	if err != nil {
 		// 处理特定错误
 		if errors.Is(err, notifier.ErrChannelNotFound) {
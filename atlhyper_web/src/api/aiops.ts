@@ -4,7 +4,7 @@
  * 类型定义对齐设计文档: docs/design/active/aiops-phase3-frontend.md §2
  */
 
-import { get, post } from "./request";
+import request, { get, post, put } from "./request";
 
 // ==================== 类型定义 ====================
 
@@ -72,6 +72,8 @@ export interface AnomalyResult {
   score: number;
   isAnomaly: boolean;
   detectedAt: number;
+  detector?: string; // ema / mad / cusum / bocpd / deterministic
+  reason?: string;
 }
 
 export interface PropagationPath {
@@ -124,11 +126,24 @@ export interface Incident {
   durationS: number;
   recurrence: number;
   createdAt: string;
+  /** 人工处理状态 */
+  ackedBy?: string;
+  ackedAt?: string;
+  assignee?: string;
+  falsePositive: boolean;
 }
 
 export interface IncidentDetail extends Incident {
   entities: IncidentEntity[];
   timeline: IncidentTimeline[];
+  clusterState?: IncidentClusterState;
+}
+
+/** 事件开始时的集群状态（快照历史回溯） */
+export interface IncidentClusterState {
+  at: string;
+  overviewUrl: string;
+  diffUrl: string;
 }
 
 export interface IncidentEntity {
@@ -149,6 +164,16 @@ export interface IncidentTimeline {
   eventType: string;
   entityKey: string;
   detail: string;
+  /** 人工操作的执行用户（引擎写入为空） */
+  actor?: string;
+}
+
+/** 事件人工处理动作 */
+export type IncidentActionName = "ack" | "assign" | "comment" | "resolve" | "false-positive";
+
+export interface IncidentActionRequest {
+  assignee?: string;
+  comment?: string;
 }
 
 export interface IncidentStats {
@@ -204,6 +229,71 @@ export interface AIReport {
   createdAt: string;
 }
 
+/** 事件复盘文档（Markdown） */
+export interface Postmortem {
+  incidentId: string;
+  clusterId: string;
+  content: string;
+  source: "ai" | "template";
+  providerName?: string;
+  model?: string;
+  generatedBy: string;
+  updatedBy: string;
+  createdAt: string;
+  updatedAt: string;
+}
+
+/** Runbook 定义（时长为 Go duration 字符串） */
+export interface Runbook {
+  name: string;
+  description?: string;
+  file: string;
+  mode: "auto" | "approval";
+  dryRun: boolean;
+  cooldown: string;
+  match: {
+    entityType?: string;
+    metric?: string;
+    reason?: string;
+    ownerKind?: string;
+    namespaces?: string[];
+    state: "warning" | "incident";
+  };
+  steps: { name: string; kind: "command" | "wait" | "verify"; action?: string; target?: string }[];
+}
+
+/** Runbook 执行步骤结果 */
+export interface RunbookStepResult {
+  name: string;
+  kind: "command" | "wait" | "verify";
+  status: "pending" | "running" | "succeeded" | "failed" | "skipped";
+  command?: string;
+  targetKind?: string;
+  targetNamespace?: string;
+  targetName?: string;
+  commandId?: string;
+  message?: string;
+}
+
+/** Runbook 执行记录 */
+export interface RunbookRun {
+  id: number;
+  runbook: string;
+  incidentId: string;
+  clusterId: string;
+  entityKey: string;
+  status: "pending_approval" | "running" | "succeeded" | "failed" | "rejected";
+  dryRun: boolean;
+  trigger: "auto" | "manual";
+  steps: RunbookStepResult[] | null;
+  error?: string;
+  requestedBy: string;
+  approvedBy?: string;
+  createdAt: string;
+  startedAt?: string;
+  finishedAt?: string;
+}
+
 export interface AIReportDetail extends AIReport {
   rootCauseAnalysis: string;
   recommendations: string;
@@ -261,6 +351,63 @@ export async function getIncidentDetail(id: string): Promise<IncidentDetail> {
   return (await get<IncidentDetail>(`/api/v2/aiops/incidents/${encodeURIComponent(id)}`)).data;
 }
 
+export async function applyIncidentAction(
+  id: string,
+  action: IncidentActionName,
+  body: IncidentActionRequest = {}
+): Promise<IncidentDetail> {
+  return (
+    await post<{ message: string; data: IncidentDetail }>(
+      `/api/v2/aiops/incidents/${encodeURIComponent(id)}/${action}`,
+      body
+    )
+  ).data.data;
+}
+
+export async function getPostmortem(incidentId: string): Promise<Postmortem> {
+  return (await get<{ data: Postmortem }>(`/api/v2/aiops/postmortems/${encodeURIComponent(incidentId)}`)).data.data;
+}
+
+/** 生成复盘（AI 撰写可能耗时较长，超时与后端生成上限 2 分钟对齐） */
+export async function generatePostmortem(incidentId: string): Promise<Postmortem> {
+  return (
+    await request.post<{ data: Postmortem }>(
+      `/api/v2/aiops/postmortems/${encodeURIComponent(incidentId)}`,
+      undefined,
+      { timeout: 130000 }
+    )
+  ).data.data;
+}
+
+export async function updatePostmortem(incidentId: string, content: string): Promise<Postmortem> {
+  return (
+    await put<{ data: Postmortem }>(`/api/v2/aiops/postmortems/${encodeURIComponent(incidentId)}`, { content })
+  ).data.data;
+}
+
+// Runbook
+export async function getRunbooks(): Promise<Runbook[]> {
+  return (await get<{ data: Runbook[] }>("/api/v2/aiops/runbooks")).data?.data ?? [];
+}
+
+export async function getRunbookRuns(incidentId: string): Promise<RunbookRun[]> {
+  return (await get<{ data: RunbookRun[]; total: number }>("/api/v2/aiops/runbook-runs", { incidentId })).data?.data ?? [];
+}
+
+export async function triggerRunbook(name: string, incidentId: string, dryRun: boolean): Promise<RunbookRun> {
+  return (
+    await post<{ data: RunbookRun }>(`/api/v2/aiops/runbooks/${encodeURIComponent(name)}/run`, { incidentId, dryRun })
+  ).data.data;
+}
+
+export async function approveRunbookRun(id: number): Promise<RunbookRun> {
+  return (await post<{ data: RunbookRun }>(`/api/v2/aiops/runbook-runs/${id}/approve`)).data.data;
+}
+
+export async function rejectRunbookRun(id: number, reason: string): Promise<RunbookRun> {
+  return (await post<{ data: RunbookRun }>(`/api/v2/aiops/runbook-runs/${id}/reject`, { reason })).data.data;
+}
+
 export async function getIncidentStats(cluster: string, period = "7d"): Promise<IncidentStats> {
   return (await get<IncidentStats>("/api/v2/aiops/incidents/stats", { cluster, period })).data;
 }
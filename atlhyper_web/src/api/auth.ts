@@ -17,6 +17,21 @@ interface LoginApiResponse {
   data: LoginResponse;
 }
 
+// SSO 配置响应
+interface OIDCConfigResponse {
+  data: {
+    enabled: boolean;
+    localAdminOnly: boolean;
+  };
+}
+
+// SSO 发起登录响应
+interface OIDCLoginResponse {
+  data: {
+    authUrl: string;
+  };
+}
+
 // 用户列表响应
 interface UserListApiResponse {
   message: string;
@@ -47,6 +62,30 @@ export function login(data: LoginRequest) {
   return post<LoginApiResponse>("/api/v2/user/login", data);
 }
 
+/**
+ * 获取 SSO 登录配置
+ * GET /api/v2/auth/oidc/config
+ */
+export function getOIDCConfig() {
+  return get<OIDCConfigResponse>("/api/v2/auth/oidc/config");
+}
+
+/**
+ * 发起 SSO 登录，返回身份提供方授权地址
+ * GET /api/v2/auth/oidc/login
+ */
+export function startOIDCLogin() {
+  return get<OIDCLoginResponse>("/api/v2/auth/oidc/login");
+}
+
+/**
+ * SSO 登录回调（授权码换取 Token，响应与密码登录一致）
+ * POST /api/v2/auth/oidc/callback
+ */
+export function completeOIDCLogin(code: string, state: string) {
+  return post<LoginApiResponse>("/api/v2/auth/oidc/callback", { code, state });
+}
+
 /**
  * 获取用户列表（需要 Admin 权限）
  * GET /api/v2/user/list
@@ -0,0 +1,81 @@
+/**
+ * 自定义资源 (CRD) API
+ *
+ * 快照不包含 CRD，每次请求经 Agent 通过 API 发现实时查询 API Server。
+ * 列表/详情直接返回 K8s 原始 JSON（已去除 managedFields）。
+ */
+
+import { get } from "./request";
+
+// ============================================================
+// 类型定义（匹配后端响应）
+// ============================================================
+
+export interface APIResourceKind {
+  group: string;
+  version: string;
+  kind: string;
+  resource: string;
+  namespaced: boolean;
+  shortNames?: string[];
+}
+
+export interface CustomResourceObject {
+  apiVersion: string;
+  kind: string;
+  metadata: {
+    name: string;
+    namespace?: string;
+    uid?: string;
+    creationTimestamp?: string;
+    labels?: Record<string, string>;
+    annotations?: Record<string, string>;
+  };
+  spec?: Record<string, unknown>;
+  status?: Record<string, unknown>;
+  [key: string]: unknown;
+}
+
+export interface CustomResourceList {
+  apiVersion: string;
+  kind: string;
+  items: CustomResourceObject[];
+}
+
+interface DataResponse<T> {
+  message: string;
+  data: T;
+}
+
+// ============================================================
+// API 查询参数
+// ============================================================
+
+export interface CustomResourceParams {
+  cluster_id: string;
+  kind: string;
+  group?: string;
+  version?: string;
+  namespace?: string;
+  label_selector?: string;
+}
+
+// ============================================================
+// API Functions
+// ============================================================
+
+export function getCustomResourceKinds(params: { cluster_id: string; group?: string; all?: boolean }) {
+  return get<DataResponse<APIResourceKind[]>>("/api/v2/custom-resources/kinds", {
+    cluster_id: params.cluster_id,
+    group: params.group,
+    all: params.all ? "true" : undefined,
+  });
+}
+
+export function listCustomResources(params: CustomResourceParams) {
+  return get<DataResponse<CustomResourceList>>("/api/v2/custom-resources", params);
+}
+
+export function getCustomResource(params: CustomResourceParams & { name: string }) {
+  return get<DataResponse<CustomResourceObject>>("/api/v2/custom-resources", params);
+}
@@ -0,0 +1,102 @@
+/**
+ * HorizontalPodAutoscaler API
+ *
+ * 后端已完成 model_v2 → model 扁平化转换，前端直接使用
+ */
+
+import { get } from "./request";
+
+// ============================================================
+// 类型定义（匹配后端 model 响应）
+// ============================================================
+
+export interface HPAItem {
+  name: string;
+  namespace: string;
+  target: string;
+  minReplicas: number;
+  maxReplicas: number;
+  currentReplicas: number;
+  desiredReplicas: number;
+  metrics: string;
+  atMaxReplicas: boolean;
+  createdAt: string;
+  age: string;
+}
+
+export interface HPAMetric {
+  type: string;
+  name: string;
+  container?: string;
+  targetType: string;
+  target: string;
+  current?: string;
+}
+
+export interface HPACondition {
+  type: string;
+  status: string;
+  reason?: string;
+  message?: string;
+  lastTransitionTime?: string;
+}
+
+export interface HPADetail {
+  name: string;
+  namespace: string;
+  uid: string;
+  createdAt: string;
+  age: string;
+  scaleTargetKind: string;
+  scaleTargetName: string;
+  minReplicas: number;
+  maxReplicas: number;
+  currentReplicas: number;
+  desiredReplicas: number;
+  atMaxReplicas: boolean;
+  scalingLimited: boolean;
+  lastScaleTime: string;
+  lastScaleAgo: string;
+  metrics?: HPAMetric[];
+  conditions?: HPACondition[];
+  labels?: Record<string, string>;
+}
+
+// ============================================================
+// 响应类型（内部使用）
+// ============================================================
+
+interface ListResponse<T> {
+  message: string;
+  data: T[];
+  total: number;
+}
+
+interface DetailResponse<T> {
+  message: string;
+  data: T;
+}
+
+// ============================================================
+// API 查询参数（内部使用）
+// ============================================================
+
+interface ClusterResourceParams {
+  cluster_id: string;
+  namespace?: string;
+}
+
+// ============================================================
+// API Functions
+// ============================================================
+
+export function getHPAList(params: ClusterResourceParams) {
+  return get<ListResponse<HPAItem>>("/api/v2/hpas", params);
+}
+
+export function getHPADetail(params: { ClusterID: string; Namespace: string; Name: string }) {
+  return get<DetailResponse<HPADetail>>(
+    `/api/v2/hpas/${encodeURIComponent(params.Name)}`,
+    { cluster_id: params.ClusterID, namespace: params.Namespace }
+  );
+}
@@ -83,6 +83,30 @@ export function uncordonNode(data: { ClusterID: string; Node: string }) {
   });
 }
 
+/**
+ * 排空 Node（需要 Operator 权限）
+ * POST /api/v2/ops/nodes/drain
+ *
+ * 异步执行：通过指令状态查询逐 Pod 进度
+ */
+export function drainNode(data: {
+  ClusterID: string;
+  Node: string;
+  GracePeriodSeconds?: number;
+  TimeoutSeconds?: number;
+  DeleteEmptyDirData?: boolean;
+  Force?: boolean;
+}) {
+  return post<CommandResponse>("/api/v2/ops/nodes/drain", {
+    clusterId: data.ClusterID,
+    name: data.Node,
+    gracePeriodSeconds: data.GracePeriodSeconds,
+    timeoutSeconds: data.TimeoutSeconds,
+    deleteEmptyDirData: data.DeleteEmptyDirData,
+    force: data.Force,
+  });
+}
+
 // ============================================================
 // 概览聚合（前端从扁平列表计算统计卡片）
 // ============================================================
@@ -4,14 +4,14 @@
  * 适配 Master V2 API
  */
 
-import { get, put } from "./request";
+import { get, post, put, del } from "./request";
 
 // ============================================================
 // 类型定义
 // ============================================================
 
 // 渠道类型
-export type ChannelType = "slack" | "email";
+export type ChannelType = "slack" | "email" | "webhook" | "teams" | "dingtalk" | "feishu" | "pagerduty";
 
 // Slack 配置
 export interface SlackConfig {
@@ -131,13 +131,13 @@ export function updateEmail(data: EmailUpdateData) {
 
 /**
  * 测试通知渠道
- * 调用 tester 模块 (端口 9080)
+ * 调用 tester 模块 (端口 9080)，按渠道 ID 测试（同类型可配置多个渠道）
  */
-export async function testChannel(type: ChannelType): Promise<{ success: boolean; message: string }> {
+export async function testChannel(id: number): Promise<{ success: boolean; message: string }> {
   const testerUrl = process.env.NEXT_PUBLIC_TESTER_URL || "http://localhost:9080";
 
   try {
-    const response = await fetch(`${testerUrl}/test/notifier/${type}`, {
+    const response = await fetch(`${testerUrl}/test/notifier/${id}`, {
       method: "POST",
       headers: {
         "Content-Type": "application/json",
@@ -157,6 +157,160 @@ export async function testChannel(type: ChannelType): Promise<{ success: boolean
   }
 }
 
+// ============================================================
+// 告警静默
+// ============================================================
+
+// 标签匹配器：label -> value（逗号分隔表示多选）
+// 可用标签: alertname, cluster, namespace, severity, source, resource, reason
+export type Matchers = Record<string, string>;
+
+// 告警静默
+export interface NotifySilence {
+  id: number;
+  matchers: Matchers;
+  startsAt: string;
+  endsAt: string;
+  comment: string;
+  createdBy: string;
+  createdAt: string;
+  active: boolean;
+}
+
+// 创建静默请求（endsAt 与 durationMinutes 二选一）
+export interface CreateSilenceRequest {
+  matchers: Matchers;
+  startsAt?: string;
+  endsAt?: string;
+  durationMinutes?: number;
+  comment: string;
+}
+
+/**
+ * 获取所有静默（含已过期）
+ * GET /api/v2/notify/silences
+ */
+export function listSilences() {
+  return get<{ silences: NotifySilence[]; total: number }>("/api/v2/notify/silences");
+}
+
+/**
+ * 创建静默
+ * POST /api/v2/notify/silences/
+ */
+export function createSilence(data: CreateSilenceRequest) {
+  return post<NotifySilence>("/api/v2/notify/silences/", data);
+}
+
+/**
+ * 立即结束静默
+ * DELETE /api/v2/notify/silences/{id}
+ */
+export function expireSilence(id: number) {
+  return del<{ message: string }>(`/api/v2/notify/silences/${id}`);
+}
+
+// ============================================================
+// 投递记录（失败重试 / 死信）
+// ============================================================
+
+export type DeliveryStatus = "pending" | "sent" | "failed" | "dead";
+
+// 通知投递记录
+export interface NotifyDelivery {
+  id: number;
+  channelId: number;
+  channelType: ChannelType;
+  channelName: string;
+  templateName: string;
+  subject: string;
+  payloadHash: string;
+  status: DeliveryStatus;
+  attempts: number;
+  lastError?: string;
+  nextAttemptAt?: string;
+  sentAt?: string;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface DeliveryQueryParams {
+  status?: DeliveryStatus;
+  channelId?: number;
+  limit?: number;
+  offset?: number;
+}
+
+/**
+ * 获取投递记录（需要 Admin 权限）
+ * GET /api/v2/notify/deliveries
+ */
+export function listDeliveries(params?: DeliveryQueryParams) {
+  return get<{ deliveries: NotifyDelivery[]; total: number }>("/api/v2/notify/deliveries", params);
+}
+
+/**
+ * 手动重发（仅 failed / dead）
+ * POST /api/v2/notify/deliveries/{id}/resend
+ */
+export function resendDelivery(id: number) {
+  return post<{ message: string }>(`/api/v2/notify/deliveries/${id}/resend`);
+}
+
+// ============================================================
+// 通知模板（自定义模板覆盖内置模板）
+// ============================================================
+
+export type TemplateVariant = "slack" | "email" | "markdown" | "html";
+
+export interface NotifyTemplate {
+  name: string;
+  variant: TemplateVariant;
+  body: string;
+  defaultBody: string;
+  customized: boolean;
+  updatedBy?: string;
+  updatedAt?: string;
+}
+
+export interface TemplatePreview {
+  subject: string;
+  body: string;
+  html: boolean;
+}
+
+/**
+ * 获取所有模板（需要 Admin 权限）
+ * GET /api/v2/notify/templates
+ */
+export function listTemplates() {
+  return get<{ templates: NotifyTemplate[]; total: number }>("/api/v2/notify/templates");
+}
+
+/**
+ * 保存自定义模板（服务端解析并使用示例数据试渲染，失败返回 400）
+ * PUT /api/v2/notify/templates/{name}/{variant}
+ */
+export function saveTemplate(name: string, variant: TemplateVariant, body: string) {
+  return put<NotifyTemplate>(`/api/v2/notify/templates/${name}/${variant}`, { body });
+}
+
+/**
+ * 恢复内置模板
+ * DELETE /api/v2/notify/templates/{name}/{variant}
+ */
+export function resetTemplate(name: string, variant: TemplateVariant) {
+  return del<NotifyTemplate>(`/api/v2/notify/templates/${name}/${variant}`);
+}
+
+/**
+ * 使用示例数据预览模板（data 可覆盖示例 AlertData 字段）
+ * POST /api/v2/notify/templates/preview
+ */
+export function previewTemplate(name: string, variant: TemplateVariant, body?: string, data?: Record<string, unknown>) {
+  return post<TemplatePreview>("/api/v2/notify/templates/preview", { name, variant, body, data });
+}
+
 // ============================================================
 // Mock 数据（Guest 用户使用）
 // ============================================================
@@ -193,3 +347,33 @@ export const mockChannels: NotifyChannel[] = [
     updatedAt: "2025-01-15T14:20:00Z",
   },
 ];
+
+export const mockSilences: NotifySilence[] = [
+  {
+    id: 1,
+    matchers: { cluster: "staging", source: "k8s_event" },
+    startsAt: "2025-01-20T08:00:00Z",
+    endsAt: "2025-01-20T10:00:00Z",
+    comment: "Staging 集群升级",
+    createdBy: "admin",
+    createdAt: "2025-01-20T07:55:00Z",
+    active: false,
+  },
+];
+
+export const mockDeliveries: NotifyDelivery[] = [
+  {
+    id: 1,
+    channelId: 1,
+    channelType: "slack",
+    channelName: "Slack",
+    templateName: "heartbeat_offline",
+    subject: "[prod] Agent 离线",
+    payloadHash: "3f2a9c1e",
+    status: "dead",
+    attempts: 6,
+    lastError: "slack returned status 503",
+    createdAt: "2025-01-20T08:00:00Z",
+    updatedAt: "2025-01-20T09:02:00Z",
+  },
+];
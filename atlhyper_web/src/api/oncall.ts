@@ -0,0 +1,163 @@
+/**
+ * 值班与告警升级 API
+ *
+ * 值班表（daily / weekly 轮换 + 替班）、升级策略、告警升级记录与确认
+ */
+
+import { get, post, put, del } from "./request";
+
+// ============================================================
+// 类型定义
+// ============================================================
+
+export type Rotation = "daily" | "weekly";
+export type EscalationTarget = "primary" | "secondary" | "all";
+export type EscalationStatus = "open" | "acked" | "resolved";
+
+export interface OncallUser {
+  id: number;
+  username: string;
+  displayName?: string;
+}
+
+// 当前值班（替班生效时 primary 与 scheduled 不同）
+export interface OncallShift {
+  primary?: OncallUser;
+  secondary?: OncallUser;
+  scheduled?: OncallUser;
+  overrideId?: number;
+  shiftStartsAt: string;
+  shiftEndsAt: string;
+}
+
+export interface OncallSchedule {
+  id: number;
+  name: string;
+  description: string;
+  rotation: Rotation;
+  startAt: string;
+  members: number[];
+  current?: OncallShift;
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+export interface OncallOverride {
+  id: number;
+  scheduleId: number;
+  userId: number;
+  startsAt: string;
+  endsAt: string;
+  comment: string;
+  createdBy: string;
+  createdAt?: string;
+  active: boolean;
+}
+
+export interface EscalationStep {
+  delayMinutes: number;
+  target: EscalationTarget;
+}
+
+export interface EscalationPolicy {
+  id: number;
+  name: string;
+  enabled: boolean;
+  matchers: Record<string, string>;
+  scheduleId: number;
+  steps: EscalationStep[];
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+export interface Escalation {
+  id: number;
+  policyId: number;
+  alertKey: string;
+  templateName: string;
+  title: string;
+  severity: string;
+  level: number;
+  status: EscalationStatus;
+  ackedBy?: string;
+  ackedAt?: string;
+  nextAt?: string;
+  createdAt: string;
+  updatedAt: string;
+}
+
+// ============================================================
+// 值班表
+// ============================================================
+
+/**
+ * 获取值班表（含当前值班）
+ * GET /api/v2/oncall/schedules
+ */
+export function listSchedules() {
+  return get<{ schedules: OncallSchedule[]; total: number }>("/api/v2/oncall/schedules");
+}
+
+export function createSchedule(data: Omit<OncallSchedule, "id" | "current" | "createdAt" | "updatedAt">) {
+  return post<OncallSchedule>("/api/v2/oncall/schedules/", data);
+}
+
+export function updateSchedule(id: number, data: Omit<OncallSchedule, "id" | "current" | "createdAt" | "updatedAt">) {
+  return put<OncallSchedule>(`/api/v2/oncall/schedules/${id}`, data);
+}
+
+export function deleteSchedule(id: number) {
+  return del<{ message: string }>(`/api/v2/oncall/schedules/${id}`);
+}
+
+export function listOverrides(scheduleId: number) {
+  return get<{ overrides: OncallOverride[]; total: number }>(`/api/v2/oncall/schedules/${scheduleId}/overrides`);
+}
+
+export function createOverride(scheduleId: number, data: { userId: number; startsAt: string; endsAt: string; comment?: string }) {
+  return post<OncallOverride>(`/api/v2/oncall/schedules/${scheduleId}/overrides`, data);
+}
+
+export function deleteOverride(scheduleId: number, overrideId: number) {
+  return del<{ message: string }>(`/api/v2/oncall/schedules/${scheduleId}/overrides/${overrideId}`);
+}
+
+// ============================================================
+// 升级策略
+// ============================================================
+
+export function listPolicies() {
+  return get<{ policies: EscalationPolicy[]; total: number }>("/api/v2/oncall/policies");
+}
+
+export function createPolicy(data: Omit<EscalationPolicy, "id" | "createdAt" | "updatedAt">) {
+  return post<EscalationPolicy>("/api/v2/oncall/policies/", data);
+}
+
+export function updatePolicy(id: number, data: Omit<EscalationPolicy, "id" | "createdAt" | "updatedAt">) {
+  return put<EscalationPolicy>(`/api/v2/oncall/policies/${id}`, data);
+}
+
+export function deletePolicy(id: number) {
+  return del<{ message: string }>(`/api/v2/oncall/policies/${id}`);
+}
+
+// ============================================================
+// 告警升级记录
+// ============================================================
+
+/**
+ * 获取告警升级记录
+ * GET /api/v2/oncall/escalations
+ */
+export function listEscalations(params?: { status?: EscalationStatus; limit?: number; offset?: number }) {
+  return get<{ escalations: Escalation[]; total: number }>("/api/v2/oncall/escalations", params);
+}
+
+/**
+ * 确认告警（停止后续升级通知）
+ * POST /api/v2/oncall/escalations/{id}/ack
+ */
+export function ackEscalation(id: number) {
+  return post<Escalation>(`/api/v2/oncall/escalations/${id}/ack`);
+}
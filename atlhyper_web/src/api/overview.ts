@@ -15,7 +15,7 @@ import type { ClusterOverview } from "@/types/overview";
  * 获取集群概览
  * GET /api/v2/overview?cluster_id=xxx
  */
-export async function getClusterOverview(params: { cluster_id: string }) {
+export async function getClusterOverview(params: { cluster_id: string; at?: string }) {
   const response = await get<ClusterOverview>("/api/v2/overview", params);
   return {
     ...response,
@@ -115,6 +115,86 @@ export function restartPod(data: {
   });
 }
 
+/**
+ * 打开 Pod 交互式终端（需要 Operator 权限）
+ * WebSocket /api/v2/ops/pods/exec
+ *
+ * 浏览器 WebSocket 无法设置 Authorization 头，Token 通过查询参数传递。
+ * 消息为 JSON 帧：发送 stdin / resize / close，接收 stdout / stderr / exit / error。
+ */
+export interface ExecFrame {
+  type: "stdin" | "resize" | "stdout" | "stderr" | "exit" | "error" | "close";
+  data?: string;
+  cols?: number;
+  rows?: number;
+  code?: number;
+  error?: string;
+}
+
+export function openPodExec(data: {
+  ClusterID: string;
+  Namespace: string;
+  Pod: string;
+  Container?: string;
+  Command?: string[];
+  TTY?: boolean;
+}): WebSocket {
+  const params = new URLSearchParams({
+    cluster_id: data.ClusterID,
+    namespace: data.Namespace,
+    pod: data.Pod,
+    tty: String(data.TTY ?? true),
+  });
+  if (data.Container) params.set("container", data.Container);
+  for (const arg of data.Command ?? []) params.append("command", arg);
+  const token = typeof window !== "undefined" ? localStorage.getItem("token") : null;
+  if (token) params.set("token", token);
+
+  const protocol = window.location.protocol === "https:" ? "wss:" : "ws:";
+  return new WebSocket(`${protocol}//${window.location.host}/api/v2/ops/pods/exec?${params.toString()}`);
+}
+
+/**
+ * 跟踪 Pod / Deployment 日志（需要 Operator 权限）
+ * SSE /api/v2/ops/logs/stream
+ *
+ * EventSource 无法设置 Authorization 头，Token 通过查询参数传递。
+ * 事件: log（一行日志）、exit（全部来源结束）、error（会话异常结束）。
+ * 关闭 EventSource 即结束会话。color 为来源颜色序号，按首次出现顺序递增。
+ */
+export interface LogStreamEvent {
+  type: "log" | "exit" | "error";
+  data?: string;
+  source?: string;
+  time?: string;
+  color?: number;
+  error?: string;
+}
+
+export function openLogStream(data: {
+  ClusterID: string;
+  Namespace: string;
+  Name: string;
+  Kind?: "Pod" | "Deployment";
+  Container?: string;
+  TailLines?: number;
+  SinceSeconds?: number;
+}): EventSource {
+  const params = new URLSearchParams({
+    cluster_id: data.ClusterID,
+    namespace: data.Namespace,
+    name: data.Name,
+    kind: data.Kind ?? "Pod",
+  });
+  if (data.Container) params.set("container", data.Container);
+  if (data.TailLines) params.set("tail_lines", String(data.TailLines));
+  if (data.SinceSeconds) params.set("since_seconds", String(data.SinceSeconds));
+  const token = typeof window !== "undefined" ? localStorage.getItem("token") : null;
+  if (token) params.set("token", token);
+
+  return new EventSource(`/api/v2/ops/logs/stream?${params.toString()}`);
+}
+
 // ============================================================
 // 概览聚合（前端从扁平列表计算统计卡片）
 // ============================================================
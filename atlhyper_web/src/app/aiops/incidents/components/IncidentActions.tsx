@@ -0,0 +1,136 @@
+"use client";
+
+import { useState } from "react";
+import { UserCheck, UserPlus, MessageSquare, CircleCheckBig, Ban, Loader2 } from "lucide-react";
+import { useI18n } from "@/i18n/context";
+import { useAuthStore } from "@/store/authStore";
+import { applyIncidentAction } from "@/api/aiops";
+import type { IncidentDetail, IncidentActionName, IncidentActionRequest } from "@/api/aiops";
+
+interface IncidentActionsProps {
+  detail: IncidentDetail;
+  onUpdated: (detail: IncidentDetail) => void;
+}
+
+export function IncidentActions({ detail, onUpdated }: IncidentActionsProps) {
+  const { t } = useI18n();
+  const { isAuthenticated } = useAuthStore();
+  const [pending, setPending] = useState<IncidentActionName | null>(null);
+  const [assignee, setAssignee] = useState(detail.assignee ?? "");
+  const [comment, setComment] = useState("");
+  const [error, setError] = useState<string | null>(null);
+
+  const resolved = detail.state === "stable";
+
+  const run = async (action: IncidentActionName, body?: IncidentActionRequest) => {
+    setPending(action);
+    setError(null);
+    try {
+      const updated = await applyIncidentAction(detail.id, action, body);
+      onUpdated(updated);
+      if (action === "comment") setComment("");
+    } catch (err) {
+      const msg = (err as { response?: { data?: { error?: string } } })?.response?.data?.error;
+      setError(msg || t.aiops.incidentActionFailed);
+    } finally {
+      setPending(null);
+    }
+  };
+
+  const btn =
+    "inline-flex items-center gap-1.5 px-2.5 py-1.5 rounded-lg text-xs font-medium border border-[var(--border-color)] hover:bg-[var(--hover-bg)] text-default transition-colors disabled:opacity-50 disabled:cursor-not-allowed";
+  const input =
+    "flex-1 min-w-0 px-2.5 py-1.5 rounded-lg text-xs bg-transparent border border-[var(--border-color)] text-default focus:outline-none focus:border-blue-500";
+
+  const icon = (action: IncidentActionName, Icon: typeof UserCheck) =>
+    pending === action ? <Loader2 className="w-3.5 h-3.5 animate-spin" /> : <Icon className="w-3.5 h-3.5" />;
+
+  return (
+    <div className="space-y-2">
+      {/* 当前人工处理状态 */}
+      {(detail.ackedBy || detail.assignee || detail.falsePositive) && (
+        <div className="flex flex-wrap items-center gap-3 text-xs text-muted">
+          {detail.ackedBy && (
+            <span>
+              {t.aiops.incidentAckedBy}: <span className="text-default font-medium">{detail.ackedBy}</span>
+            </span>
+          )}
+          {detail.assignee && (
+            <span>
+              {t.aiops.incidentAssignedTo}: <span className="text-default font-medium">{detail.assignee}</span>
+            </span>
+          )}
+          {detail.falsePositive && (
+            <span className="px-2 py-0.5 rounded-full bg-gray-500/15 text-gray-600 dark:text-gray-400 font-medium">
+              {t.aiops.incidentFalsePositive}
+            </span>
+          )}
+        </div>
+      )}
+
+      {isAuthenticated && (
+        <>
+          <div className="flex flex-wrap items-center gap-2">
+            <button
+              className={btn}
+              disabled={pending !== null || resolved || !!detail.ackedBy}
+              onClick={() => run("ack")}
+            >
+              {icon("ack", UserCheck)}
+              {t.aiops.incidentAck}
+            </button>
+            <button className={btn} disabled={pending !== null || resolved} onClick={() => run("resolve")}>
+              {icon("resolve", CircleCheckBig)}
+              {t.aiops.incidentResolve}
+            </button>
+            <button
+              className={btn}
+              disabled={pending !== null || detail.falsePositive}
+              onClick={() => run("false-positive")}
+            >
+              {icon("false-positive", Ban)}
+              {t.aiops.incidentFalsePositive}
+            </button>
+          </div>
+
+          <div className="flex items-center gap-2">
+            <input
+              className={input}
+              value={assignee}
+              placeholder={t.aiops.incidentAssigneePlaceholder}
+              onChange={(e) => setAssignee(e.target.value)}
+            />
+            <button
+              className={btn}
+              disabled={pending !== null || !assignee.trim()}
+              onClick={() => run("assign", { assignee: assignee.trim() })}
+            >
+              {icon("assign", UserPlus)}
+              {t.aiops.incidentAssign}
+            </button>
+          </div>
+
+          <div className="flex items-center gap-2">
+            <input
+              className={input}
+              value={comment}
+              placeholder={t.aiops.incidentCommentPlaceholder}
+              maxLength={2000}
+              onChange={(e) => setComment(e.target.value)}
+            />
+            <button
+              className={btn}
+              disabled={pending !== null || !comment.trim()}
+              onClick={() => run("comment", { comment: comment.trim() })}
+            >
+              {icon("comment", MessageSquare)}
+              {t.aiops.incidentComment}
+            </button>
+          </div>
+
+          {error && <p className="text-xs text-red-500">{error}</p>}
+        </>
+      )}
+    </div>
+  );
+}
@@ -1,13 +1,17 @@
 "use client";
 
 import { useState, useEffect } from "react";
-import { X, Loader2 } from "lucide-react";
+import Link from "next/link";
+import { X, Loader2, History } from "lucide-react";
 import { useI18n } from "@/i18n/context";
 import { RiskBadge } from "@/components/aiops/RiskBadge";
 import { EntityLink } from "@/components/aiops/EntityLink";
 import { AIAnalysisSection } from "@/components/aiops/AIAnalysisSection";
 import { RootCauseCard } from "./RootCauseCard";
 import { TimelineView } from "./TimelineView";
+import { IncidentActions } from "./IncidentActions";
+import { RunbookSection } from "./RunbookSection";
+import { PostmortemSection } from "./PostmortemSection";
 import { getIncidentDetail } from "@/api/aiops";
 import type { IncidentDetail } from "@/api/aiops";
 import { formatRiskScore } from "@/lib/risk";
@@ -103,6 +107,20 @@ export function IncidentDetailModal({ incidentId, open, onClose }: IncidentDetai
                 </span>
               </div>
 
+              {/* 人工处理 */}
+              <IncidentActions detail={detail} onUpdated={setDetail} />
+
+              {/* 事件开始时的集群状态（快照历史） */}
+              {detail.clusterState && (
+                <Link
+                  href={`/overview?at=${encodeURIComponent(detail.clusterState.at)}`}
+                  className="inline-flex items-center gap-1.5 text-xs text-blue-500 hover:underline"
+                >
+                  <History className="w-3.5 h-3.5" />
+                  {t.aiops.clusterStateAtStart}
+                </Link>
+              )}
+
               {/* 根因卡片 */}
               <RootCauseCard entity={rootCauseEntity} />
 
@@ -131,6 +149,12 @@ export function IncidentDetailModal({ incidentId, open, onClose }: IncidentDetai
 
               {/* AI 分析报告 */}
               <AIAnalysisSection incidentId={detail.id} incidentState={detail.state} />
+
+              {/* Runbook 执行 */}
+              <RunbookSection incidentId={detail.id} resolved={!!detail.resolvedAt} />
+
+              {/* 复盘文档 */}
+              <PostmortemSection incidentId={detail.id} />
             </>
           ) : (
             <div className="py-8 text-center text-sm text-muted">{t.aiops.noData}</div>
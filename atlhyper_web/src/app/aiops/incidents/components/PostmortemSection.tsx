@@ -0,0 +1,148 @@
+"use client";
+
+import { useState, useEffect } from "react";
+import { FileText, Loader2, Pencil, RefreshCw } from "lucide-react";
+import { useI18n } from "@/i18n/context";
+import { useAuthStore } from "@/store/authStore";
+import { getPostmortem, generatePostmortem, updatePostmortem } from "@/api/aiops";
+import type { Postmortem } from "@/api/aiops";
+
+interface PostmortemSectionProps {
+  incidentId: string;
+}
+
+function errorStatus(err: unknown): number | undefined {
+  return (err as { response?: { status?: number } })?.response?.status;
+}
+
+function errorMessage(err: unknown): string | undefined {
+  return (err as { response?: { data?: { error?: string } } })?.response?.data?.error;
+}
+
+export function PostmortemSection({ incidentId }: PostmortemSectionProps) {
+  const { t } = useI18n();
+  const { isAuthenticated } = useAuthStore();
+  const [doc, setDoc] = useState<Postmortem | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [generating, setGenerating] = useState(false);
+  const [editing, setEditing] = useState(false);
+  const [draft, setDraft] = useState("");
+  const [saving, setSaving] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    if (!isAuthenticated) return;
+    setLoading(true);
+    setError(null);
+    getPostmortem(incidentId)
+      .then(setDoc)
+      .catch((err) => {
+        setDoc(null);
+        // 404 = 尚未生成
+        if (errorStatus(err) !== 404) setError(errorMessage(err) || t.aiops.postmortemFailed);
+      })
+      .finally(() => setLoading(false));
+  }, [incidentId, isAuthenticated, t.aiops.postmortemFailed]);
+
+  if (!isAuthenticated) return null;
+
+  const handleGenerate = async () => {
+    if (doc && !window.confirm(t.aiops.postmortemRegenerateConfirm)) return;
+    setGenerating(true);
+    setError(null);
+    try {
+      setDoc(await generatePostmortem(incidentId));
+      setEditing(false);
+    } catch (err) {
+      setError(errorMessage(err) || t.aiops.postmortemFailed);
+    } finally {
+      setGenerating(false);
+    }
+  };
+
+  const handleSave = async () => {
+    setSaving(true);
+    setError(null);
+    try {
+      setDoc(await updatePostmortem(incidentId, draft));
+      setEditing(false);
+    } catch (err) {
+      setError(errorMessage(err) || t.aiops.postmortemFailed);
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const btn =
+    "inline-flex items-center gap-1.5 px-2.5 py-1.5 rounded-lg text-xs font-medium border border-[var(--border-color)] hover:bg-[var(--hover-bg)] text-default transition-colors disabled:opacity-50 disabled:cursor-not-allowed";
+
+  return (
+    <div>
+      <div className="flex items-center justify-between mb-2">
+        <h4 className="text-xs font-semibold text-muted uppercase tracking-wider flex items-center gap-1.5">
+          <FileText className="w-3.5 h-3.5" />
+          {t.aiops.postmortem}
+        </h4>
+        <div className="flex items-center gap-2">
+          {doc && !editing && (
+            <button
+              className={btn}
+              disabled={generating}
+              onClick={() => {
+                setDraft(doc.content);
+                setEditing(true);
+              }}
+            >
+              <Pencil className="w-3.5 h-3.5" />
+              {t.aiops.postmortemEdit}
+            </button>
+          )}
+          {!editing && (
+            <button className={btn} disabled={generating || loading} onClick={handleGenerate}>
+              {generating ? <Loader2 className="w-3.5 h-3.5 animate-spin" /> : <RefreshCw className="w-3.5 h-3.5" />}
+              {doc ? t.aiops.postmortemRegenerate : t.aiops.postmortemGenerate}
+            </button>
+          )}
+        </div>
+      </div>
+
+      {loading ? (
+        <div className="flex items-center justify-center py-4">
+          <Loader2 className="w-4 h-4 animate-spin text-blue-500" />
+        </div>
+      ) : editing ? (
+        <div className="space-y-2">
+          <textarea
+            className="w-full h-80 px-3 py-2 rounded-lg text-xs font-mono bg-transparent border border-[var(--border-color)] text-default focus:outline-none focus:border-blue-500"
+            value={draft}
+            onChange={(e) => setDraft(e.target.value)}
+          />
+          <div className="flex justify-end gap-2">
+            <button className={btn} disabled={saving} onClick={() => setEditing(false)}>
+              {t.aiops.postmortemCancel}
+            </button>
+            <button className={btn} disabled={saving || !draft.trim()} onClick={handleSave}>
+              {saving && <Loader2 className="w-3.5 h-3.5 animate-spin" />}
+              {t.aiops.postmortemSave}
+            </button>
+          </div>
+        </div>
+      ) : doc ? (
+        <div className="space-y-1.5">
+          <p className="text-xs text-muted">
+            {doc.source === "ai" ? t.aiops.postmortemSourceAI : t.aiops.postmortemSourceTemplate}
+            {doc.updatedBy && ` · ${t.aiops.postmortemUpdatedBy}: ${doc.updatedBy}`}
+            {` · ${new Date(doc.updatedAt).toLocaleString()}`}
+          </p>
+          <pre className="max-h-96 overflow-y-auto whitespace-pre-wrap break-words rounded-lg border border-[var(--border-color)] p-3 text-xs text-default font-mono">
+            {doc.content}
+          </pre>
+        </div>
+      ) : (
+        <div className="text-sm text-muted py-2">{t.aiops.postmortemEmpty}</div>
+      )}
+
+      {error && <p className="text-xs text-red-500 mt-2">{error}</p>}
+    </div>
+  );
+}
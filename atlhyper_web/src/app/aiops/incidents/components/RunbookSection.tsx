@@ -0,0 +1,196 @@
+"use client";
+
+import { useState, useEffect, useCallback } from "react";
+import { Check, Loader2, Play, RefreshCw, Wrench, X } from "lucide-react";
+import { useI18n } from "@/i18n/context";
+import { useAuthStore } from "@/store/authStore";
+import { getRunbooks, getRunbookRuns, triggerRunbook, approveRunbookRun, rejectRunbookRun } from "@/api/aiops";
+import type { Runbook, RunbookRun, RunbookStepResult } from "@/api/aiops";
+
+interface RunbookSectionProps {
+  incidentId: string;
+  resolved: boolean;
+}
+
+const RUN_STATUS_COLORS: Record<RunbookRun["status"], string> = {
+  pending_approval: "bg-amber-500/10 text-amber-600 dark:text-amber-400",
+  running: "bg-blue-500/10 text-blue-600 dark:text-blue-400",
+  succeeded: "bg-green-500/10 text-green-600 dark:text-green-400",
+  failed: "bg-red-500/10 text-red-600 dark:text-red-400",
+  rejected: "bg-gray-500/10 text-muted",
+};
+
+const STEP_STATUS_COLORS: Record<RunbookStepResult["status"], string> = {
+  pending: "text-muted",
+  running: "text-blue-500",
+  succeeded: "text-green-500",
+  failed: "text-red-500",
+  skipped: "text-muted",
+};
+
+function errorMessage(err: unknown): string | undefined {
+  return (err as { response?: { data?: { error?: string } } })?.response?.data?.error;
+}
+
+export function RunbookSection({ incidentId, resolved }: RunbookSectionProps) {
+  const { t } = useI18n();
+  const { isAuthenticated } = useAuthStore();
+  const [runbooks, setRunbooks] = useState<Runbook[]>([]);
+  const [runs, setRuns] = useState<RunbookRun[]>([]);
+  const [selected, setSelected] = useState("");
+  const [dryRun, setDryRun] = useState(true);
+  const [loading, setLoading] = useState(false);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  const statusLabels: Record<RunbookRun["status"], string> = {
+    pending_approval: t.aiops.runbookStatusPending,
+    running: t.aiops.runbookStatusRunning,
+    succeeded: t.aiops.runbookStatusSucceeded,
+    failed: t.aiops.runbookStatusFailed,
+    rejected: t.aiops.runbookStatusRejected,
+  };
+
+  const loadRuns = useCallback(() => {
+    setLoading(true);
+    getRunbookRuns(incidentId)
+      .then(setRuns)
+      .catch((err) => setError(errorMessage(err) || t.aiops.runbookFailed))
+      .finally(() => setLoading(false));
+  }, [incidentId, t.aiops.runbookFailed]);
+
+  useEffect(() => {
+    if (!isAuthenticated) return;
+    setError(null);
+    loadRuns();
+    getRunbooks()
+      .then((list) => {
+        setRunbooks(list);
+        setSelected((cur) => cur || list[0]?.name || "");
+      })
+      .catch(() => setRunbooks([]));
+  }, [isAuthenticated, loadRuns]);
+
+  // 未配置 Runbook 且无执行记录时不展示
+  if (!isAuthenticated || (runbooks.length === 0 && runs.length === 0)) return null;
+
+  const act = async (fn: () => Promise<unknown>) => {
+    setBusy(true);
+    setError(null);
+    try {
+      await fn();
+      loadRuns();
+    } catch (err) {
+      setError(errorMessage(err) || t.aiops.runbookFailed);
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleTrigger = () => {
+    if (!selected) return;
+    if (!dryRun && !window.confirm(t.aiops.runbookRunConfirm)) return;
+    act(() => triggerRunbook(selected, incidentId, dryRun));
+  };
+
+  const handleReject = (id: number) => {
+    const reason = window.prompt(t.aiops.runbookRejectReason);
+    if (reason === null) return;
+    act(() => rejectRunbookRun(id, reason));
+  };
+
+  const btn =
+    "inline-flex items-center gap-1.5 px-2.5 py-1.5 rounded-lg text-xs font-medium border border-[var(--border-color)] hover:bg-[var(--hover-bg)] text-default transition-colors disabled:opacity-50 disabled:cursor-not-allowed";
+
+  return (
+    <div>
+      <div className="flex items-center justify-between mb-2">
+        <h4 className="text-xs font-semibold text-muted uppercase tracking-wider flex items-center gap-1.5">
+          <Wrench className="w-3.5 h-3.5" />
+          {t.aiops.runbook}
+        </h4>
+        <button className={btn} disabled={loading} onClick={loadRuns}>
+          <RefreshCw className={`w-3.5 h-3.5 ${loading ? "animate-spin" : ""}`} />
+          {t.aiops.runbookRefresh}
+        </button>
+      </div>
+
+      {runbooks.length > 0 && (
+        <div className="flex flex-wrap items-center gap-2 mb-3">
+          <select
+            className="px-2 py-1.5 rounded-lg text-xs bg-transparent border border-[var(--border-color)] text-default focus:outline-none focus:border-blue-500"
+            value={selected}
+            onChange={(e) => setSelected(e.target.value)}
+            aria-label={t.aiops.runbookSelect}
+          >
+            {runbooks.map((rb) => (
+              <option key={rb.name} value={rb.name}>
+                {rb.name}
+              </option>
+            ))}
+          </select>
+          <label className="flex items-center gap-1 text-xs text-muted">
+            <input type="checkbox" checked={dryRun || resolved} disabled={resolved} onChange={(e) => setDryRun(e.target.checked)} />
+            {t.aiops.runbookDryRun}
+          </label>
+          <button className={btn} disabled={busy || !selected} onClick={handleTrigger}>
+            {busy ? <Loader2 className="w-3.5 h-3.5 animate-spin" /> : <Play className="w-3.5 h-3.5" />}
+            {t.aiops.runbookRun}
+          </button>
+        </div>
+      )}
+
+      {runs.length === 0 ? (
+        <div className="text-sm text-muted py-2">{t.aiops.runbookEmpty}</div>
+      ) : (
+        <div className="space-y-2">
+          {runs.map((run) => (
+            <div key={run.id} className="rounded-lg border border-[var(--border-color)] p-3 space-y-2">
+              <div className="flex items-center justify-between gap-2">
+                <div className="flex items-center gap-2 text-sm min-w-0">
+                  <span className="font-medium text-default truncate">{run.runbook}</span>
+                  <span className={`px-1.5 py-0.5 rounded text-[10px] font-medium ${RUN_STATUS_COLORS[run.status]}`}>
+                    {statusLabels[run.status]}
+                  </span>
+                  {run.dryRun && <span className="text-[10px] text-muted">{t.aiops.runbookDryRun}</span>}
+                </div>
+                {run.status === "pending_approval" && (
+                  <div className="flex items-center gap-2">
+                    <button className={btn} disabled={busy} onClick={() => act(() => approveRunbookRun(run.id))}>
+                      <Check className="w-3.5 h-3.5" />
+                      {t.aiops.runbookApprove}
+                    </button>
+                    <button className={btn} disabled={busy} onClick={() => handleReject(run.id)}>
+                      <X className="w-3.5 h-3.5" />
+                      {t.aiops.runbookReject}
+                    </button>
+                  </div>
+                )}
+              </div>
+              <p className="text-xs text-muted">
+                {run.trigger === "auto" ? t.aiops.runbookTriggerAuto : t.aiops.runbookTriggerManual}
+                {run.requestedBy && ` · ${run.requestedBy}`}
+                {run.approvedBy && ` · ${t.aiops.runbookApprovedBy}: ${run.approvedBy}`}
+                {` · ${new Date(run.createdAt).toLocaleString()}`}
+              </p>
+              {(run.steps ?? []).length > 0 && (
+                <ol className="space-y-1">
+                  {(run.steps ?? []).map((step, i) => (
+                    <li key={i} className="flex items-start gap-2 text-xs">
+                      <span className={`shrink-0 font-mono ${STEP_STATUS_COLORS[step.status]}`}>{step.status}</span>
+                      <span className="text-default">{step.name}</span>
+                      {step.message && <span className="text-muted break-all">{step.message}</span>}
+                    </li>
+                  ))}
+                </ol>
+              )}
+              {run.error && <p className="text-xs text-red-500 break-all">{run.error}</p>}
+            </div>
+          ))}
+        </div>
+      )}
+
+      {error && <p className="text-xs text-red-500 mt-2">{error}</p>}
+    </div>
+  );
+}
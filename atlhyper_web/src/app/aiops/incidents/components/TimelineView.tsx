@@ -1,6 +1,18 @@
 "use client";
 
-import { AlertTriangle, ArrowRight, TrendingUp, Target, CheckCircle, RotateCcw } from "lucide-react";
+import {
+  AlertTriangle,
+  ArrowRight,
+  TrendingUp,
+  Target,
+  CheckCircle,
+  RotateCcw,
+  UserCheck,
+  UserPlus,
+  MessageSquare,
+  CircleCheckBig,
+  Ban,
+} from "lucide-react";
 import { useI18n } from "@/i18n/context";
 import { EntityLink } from "@/components/aiops/EntityLink";
 import type { IncidentTimeline } from "@/api/aiops";
@@ -12,6 +24,11 @@ const EVENT_ICONS: Record<string, { icon: typeof AlertTriangle; color: string }>
   root_cause_identified: { icon: Target, color: "text-purple-500 bg-purple-500/10" },
   recovery_started: { icon: CheckCircle, color: "text-emerald-500 bg-emerald-500/10" },
   recurrence: { icon: RotateCcw, color: "text-orange-500 bg-orange-500/10" },
+  acknowledged: { icon: UserCheck, color: "text-sky-500 bg-sky-500/10" },
+  assigned: { icon: UserPlus, color: "text-sky-500 bg-sky-500/10" },
+  comment: { icon: MessageSquare, color: "text-gray-500 bg-gray-500/10" },
+  manual_resolved: { icon: CircleCheckBig, color: "text-emerald-500 bg-emerald-500/10" },
+  false_positive: { icon: Ban, color: "text-gray-500 bg-gray-500/10" },
 };
 
 interface TimelineViewProps {
@@ -60,7 +77,8 @@ export function TimelineView({ timeline }: TimelineViewProps) {
                 <div className="flex-1 min-w-0 pt-0.5">
                   <div className="flex items-center gap-2">
                     <span className="text-xs font-medium text-default">{eventLabel}</span>
-                    <EntityLink entityKey={event.entityKey} showType={false} />
+                    {event.entityKey && <EntityLink entityKey={event.entityKey} showType={false} />}
+                    {event.actor && <span className="text-xs text-muted">@{event.actor}</span>}
                   </div>
                   <p className="text-xs text-muted mt-0.5">{event.detail}</p>
                 </div>
@@ -1,6 +1,7 @@
 "use client";
 
 import { useState, useEffect, useCallback } from "react";
+import { useSearchParams } from "next/navigation";
 import { Layout } from "@/components/layout/Layout";
 import { useI18n } from "@/i18n/context";
 import { useClusterStore } from "@/store/clusterStore";
@@ -27,7 +28,9 @@ export default function IncidentsPage() {
   const [stats, setStats] = useState<IncidentStatsType | null>(null);
   const [incidents, setIncidents] = useState<Incident[]>([]);
   const [stateFilter, setStateFilter] = useState("");
-  const [selectedIncidentId, setSelectedIncidentId] = useState<string | null>(null);
+  // 告警通知中的事件链接：/aiops/incidents?id=xxx 直接打开事件详情
+  const searchParams = useSearchParams();
+  const [selectedIncidentId, setSelectedIncidentId] = useState<string | null>(searchParams.get("id"));
 
   const loadData = useCallback(
     async (showLoading = true) => {
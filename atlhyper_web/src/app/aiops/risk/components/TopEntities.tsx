@@ -137,7 +137,7 @@ export function TopEntities({ entities, clusterId, limit, onLimitChange }: TopEn
                           <h4 className="text-xs font-medium text-muted mb-2">{t.aiops.metricName}</h4>
                           <div className="space-y-1">
                             {detail.metrics.map((m, i) => (
-                              <div key={i} className="flex items-center gap-3 text-xs">
+                              <div key={i} className="flex items-center gap-3 text-xs" title={m.reason}>
                                 <span className="font-mono text-default w-40 truncate">{m.metricName}</span>
                                 <span className="text-muted">
                                   {t.aiops.currentValue}: <span className="text-default">{m.currentValue.toFixed(2)}</span>
@@ -148,6 +148,9 @@ export function TopEntities({ entities, clusterId, limit, onLimitChange }: TopEn
                                 <span className="text-muted">
                                   {t.aiops.deviation}: <span className="text-default">{m.deviation.toFixed(1)}σ</span>
                                 </span>
+                                {m.detector && (
+                                  <span className="text-muted text-[10px] font-mono">{m.detector}</span>
+                                )}
                                 {m.isAnomaly && (
                                   <span className="text-red-500 text-[10px] font-medium">{t.aiops.isAnomaly}</span>
                                 )}
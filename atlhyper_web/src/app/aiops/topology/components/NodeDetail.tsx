@@ -98,7 +98,9 @@ export function NodeDetail({ entityKey, clusterId }: NodeDetailProps) {
                   <span>
                     {t.aiops.deviation}: <span className="text-default">{m.deviation.toFixed(1)}σ</span>
                   </span>
+                  {m.detector && <span className="font-mono">{m.detector}</span>}
                 </div>
+                {m.reason && <p className="text-muted break-all">{m.reason}</p>}
               </div>
             ))}
           </div>
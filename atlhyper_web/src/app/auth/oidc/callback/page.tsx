@@ -0,0 +1,70 @@
+"use client";
+
+import { useEffect, useRef, useState } from "react";
+import { useRouter, useSearchParams } from "next/navigation";
+import { completeOIDCLogin } from "@/api/auth";
+import { useAuthStore } from "@/store/authStore";
+import { useClusterStore } from "@/store/clusterStore";
+import { useI18n } from "@/i18n/context";
+
+export default function OIDCCallbackPage() {
+  const router = useRouter();
+  const searchParams = useSearchParams();
+  const { t } = useI18n();
+  const { setLoginData } = useAuthStore();
+  const { setClusterIds } = useClusterStore();
+  const calledRef = useRef(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    if (calledRef.current) return;
+    calledRef.current = true;
+
+    const code = searchParams.get("code");
+    const state = searchParams.get("state");
+    const idpError = searchParams.get("error_description") || searchParams.get("error");
+
+    if (idpError) {
+      setError(idpError);
+      return;
+    }
+    if (!code || !state) {
+      router.replace("/");
+      return;
+    }
+
+    completeOIDCLogin(code, state)
+      .then((res) => {
+        setLoginData(res.data.data);
+        if (res.data.data.cluster_ids?.length > 0) {
+          setClusterIds(res.data.data.cluster_ids);
+        }
+        router.replace("/");
+      })
+      .catch((err) => {
+        const msg = err?.response?.data?.error || err?.message || String(err);
+        console.error("OIDC login callback failed:", msg, err);
+        setError(msg);
+      });
+  }, [searchParams, router, setLoginData, setClusterIds]);
+
+  if (error) {
+    return (
+      <div style={{ display: "flex", flexDirection: "column", justifyContent: "center", alignItems: "center", height: "60vh", gap: "16px" }}>
+        <p style={{ color: "#ef4444" }}>{t.login.ssoFailed}: {error}</p>
+        <button
+          onClick={() => router.replace("/")}
+          style={{ padding: "8px 16px", borderRadius: "8px", border: "1px solid #666", cursor: "pointer" }}
+        >
+          {t.common.back}
+        </button>
+      </div>
+    );
+  }
+
+  return (
+    <div style={{ display: "flex", justifyContent: "center", alignItems: "center", height: "60vh" }}>
+      <p>{t.login.ssoRedirecting}</p>
+    </div>
+  );
+}
@@ -1,6 +1,8 @@
 "use client";
 
 import { useState, useEffect, useRef } from "react";
+import Link from "next/link";
+import { useSearchParams } from "next/navigation";
 import { Layout } from "@/components/layout/Layout";
 import { useI18n } from "@/i18n/context";
 import { getClusterOverview } from "@/datasource/overview";
@@ -8,7 +10,7 @@ import { getSLODomainsV2 } from "@/datasource/slo";
 import { getDataSourceMode } from "@/config/data-source";
 import { useClusterStore } from "@/store/clusterStore";
 import { LoadingSpinner, PageHeader } from "@/components/common";
-import { Server, Cpu, HardDrive, AlertTriangle } from "lucide-react";
+import { Server, Cpu, HardDrive, AlertTriangle, History } from "lucide-react";
 import type { TransformedOverview } from "@/types/overview";
 import type { DomainSLOListResponseV2 } from "@/types/slo";
 
@@ -43,6 +45,10 @@ export default function OverviewPage() {
   // 告警详情弹窗状态
   const [selectedAlert, setSelectedAlert] = useState<AlertItem | null>(null);
 
+  // 时间回溯：URL ?at=xxx 查看历史快照（如 AIOps 事件开始时的集群状态）
+  const searchParams = useSearchParams();
+  const at = searchParams.get("at");
+
   // 异步获取数据（静默刷新，不影响 UI）
   useEffect(() => {
     isMountedRef.current = true;
@@ -57,7 +63,7 @@ export default function OverviewPage() {
         }
 
         const [res, sloRes] = await Promise.all([
-          getClusterOverview({ cluster_id: clusterId }),
+          getClusterOverview(at ? { cluster_id: clusterId, at } : { cluster_id: clusterId }),
           getSLODomainsV2({ clusterId }).catch(() => null),
         ]);
         if (isMountedRef.current) {
@@ -85,14 +91,14 @@ export default function OverviewPage() {
     // 立即执行一次
     fetchData();
 
-    // 设置 10s 定时刷新
-    const intervalId = setInterval(fetchData, REFRESH_INTERVAL);
+    // 设置 10s 定时刷新（历史快照不刷新）
+    const intervalId = at ? undefined : setInterval(fetchData, REFRESH_INTERVAL);
 
     return () => {
       isMountedRef.current = false;
       clearInterval(intervalId);
     };
-  }, [clusterId]);
+  }, [clusterId, at]);
 
   if (loading) {
     return (
@@ -115,6 +121,18 @@ export default function OverviewPage() {
       <div className="space-y-6">
         <PageHeader title={t.nav.overview} />
 
+        {at && (
+          <div className="flex items-center justify-between gap-3 px-4 py-2.5 rounded-xl border border-blue-500/30 bg-blue-500/10 text-sm text-blue-600 dark:text-blue-400">
+            <span className="flex items-center gap-2">
+              <History className="w-4 h-4" />
+              {t.overview.viewingAt.replace("{time}", new Date(at).toLocaleString())}
+            </span>
+            <Link href="/overview" className="text-xs font-medium hover:underline">
+              {t.overview.backToLive}
+            </Link>
+          </div>
+        )}
+
         {/* Top Stats Cards */}
         <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-5 gap-4">
           <HealthCard data={data.healthCard} t={t} />
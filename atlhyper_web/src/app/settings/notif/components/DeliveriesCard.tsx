@@ -0,0 +1,142 @@
+"use client";
+
+import { useState, useEffect, useCallback } from "react";
+import { Send, Loader2 } from "lucide-react";
+import { useI18n } from "@/i18n/context";
+import { toast } from "@/components/common/Toast";
+import {
+  listDeliveries,
+  resendDelivery,
+  mockDeliveries,
+  type DeliveryStatus,
+  type NotifyDelivery,
+} from "@/api/notify";
+
+interface DeliveriesCardProps {
+  readOnly: boolean;
+}
+
+const STATUS_COLORS: Record<DeliveryStatus, string> = {
+  sent: "text-green-600",
+  pending: "text-blue-600",
+  failed: "text-yellow-600",
+  dead: "text-red-600",
+};
+
+export function DeliveriesCard({ readOnly }: DeliveriesCardProps) {
+  const { t } = useI18n();
+  const nt = t.notifications;
+
+  const [deliveries, setDeliveries] = useState<NotifyDelivery[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [status, setStatus] = useState<DeliveryStatus | "">("dead");
+  const [resending, setResending] = useState<number | null>(null);
+
+  const statusLabels: Record<DeliveryStatus, string> = {
+    sent: nt.deliveryStatusSent,
+    pending: nt.deliveryStatusPending,
+    failed: nt.deliveryStatusFailed,
+    dead: nt.deliveryStatusDead,
+  };
+
+  const load = useCallback(() => {
+    if (readOnly) {
+      setDeliveries(mockDeliveries.filter((d) => !status || d.status === status));
+      setLoading(false);
+      return;
+    }
+    listDeliveries({ status: status || undefined, limit: 50 })
+      .then((res) => setDeliveries(res.data.deliveries || []))
+      .catch((err) => {
+        console.error("Failed to load deliveries:", err);
+        toast.error(nt.loadFailed);
+      })
+      .finally(() => setLoading(false));
+  }, [readOnly, status, nt.loadFailed]);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const handleResend = async (id: number) => {
+    if (readOnly) return;
+    setResending(id);
+    try {
+      await resendDelivery(id);
+      toast.success(nt.deliveryResent);
+      load();
+    } catch (err) {
+      console.error("Failed to resend delivery:", err);
+      toast.error(nt.saveFailed);
+    } finally {
+      setResending(null);
+    }
+  };
+
+  return (
+    <div className="bg-card rounded-xl border border-[var(--border-color)] overflow-hidden">
+      {/* 头部 */}
+      <div className="flex items-center justify-between gap-3 px-6 py-4 border-b border-[var(--border-color)]">
+        <div className="flex items-center gap-3">
+          <div className="w-10 h-10 rounded-lg bg-gray-100 dark:bg-gray-800 flex items-center justify-center">
+            <Send className="w-5 h-5 text-gray-600 dark:text-gray-400" />
+          </div>
+          <div>
+            <h3 className="font-medium text-default">{nt.deliveries}</h3>
+            <p className="text-sm text-muted">{nt.deliveriesHint}</p>
+          </div>
+        </div>
+        <select
+          value={status}
+          onChange={(e) => setStatus(e.target.value as DeliveryStatus | "")}
+          className="px-3 py-2 rounded-lg border text-sm bg-[var(--bg-primary)] text-default border-[var(--border-color)]"
+        >
+          <option value="">{nt.deliveryStatusAll}</option>
+          {(Object.keys(statusLabels) as DeliveryStatus[]).map((s) => (
+            <option key={s} value={s}>
+              {statusLabels[s]}
+            </option>
+          ))}
+        </select>
+      </div>
+
+      {/* 列表 */}
+      <div className="px-6 py-4">
+        {loading ? (
+          <div className="flex justify-center py-4">
+            <Loader2 className="w-5 h-5 animate-spin text-muted" />
+          </div>
+        ) : deliveries.length === 0 ? (
+          <p className="text-sm text-muted text-center py-4">{nt.deliveryEmpty}</p>
+        ) : (
+          <ul className="divide-y divide-[var(--border-color)]">
+            {deliveries.map((d) => (
+              <li key={d.id} className="flex items-center justify-between gap-4 py-3">
+                <div className="min-w-0">
+                  <p className="text-sm text-default truncate">{d.subject}</p>
+                  <p className="text-xs text-muted truncate">
+                    {d.channelName || d.channelType} · {new Date(d.createdAt).toLocaleString()} · {nt.deliveryAttempts}: {d.attempts}
+                    {d.lastError && ` · ${d.lastError}`}
+                  </p>
+                </div>
+                <div className="flex items-center gap-3 flex-shrink-0">
+                  <span className={`text-xs ${STATUS_COLORS[d.status]}`}>{statusLabels[d.status]}</span>
+                  {(d.status === "dead" || d.status === "failed") && !readOnly && (
+                    <button
+                      onClick={() => handleResend(d.id)}
+                      disabled={resending === d.id}
+                      className="px-3 py-1 text-xs rounded-lg border border-[var(--border-color)] text-default hover:bg-[var(--bg-secondary)] disabled:opacity-50 transition-colors flex items-center gap-1"
+                    >
+                      {resending === d.id && <Loader2 className="w-3 h-3 animate-spin" />}
+                      {nt.deliveryResend}
+                    </button>
+                  )}
+                </div>
+              </li>
+            ))}
+          </ul>
+        )}
+      </div>
+    </div>
+  );
+}
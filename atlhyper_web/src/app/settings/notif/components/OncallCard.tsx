@@ -0,0 +1,141 @@
+"use client";
+
+import { useState, useEffect, useCallback } from "react";
+import { PhoneCall, Loader2 } from "lucide-react";
+import { useI18n } from "@/i18n/context";
+import { toast } from "@/components/common/Toast";
+import {
+  listSchedules,
+  listEscalations,
+  ackEscalation,
+  type OncallSchedule,
+  type OncallUser,
+  type Escalation,
+} from "@/api/oncall";
+
+function userLabel(u?: OncallUser): string {
+  if (!u) return "-";
+  return u.displayName || u.username || `#${u.id}`;
+}
+
+export function OncallCard() {
+  const { t } = useI18n();
+  const nt = t.notifications;
+
+  const [schedules, setSchedules] = useState<OncallSchedule[]>([]);
+  const [escalations, setEscalations] = useState<Escalation[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [acking, setAcking] = useState<number | null>(null);
+
+  const load = useCallback(() => {
+    Promise.all([listSchedules(), listEscalations({ status: "open", limit: 50 })])
+      .then(([s, e]) => {
+        setSchedules(s.data.schedules || []);
+        setEscalations(e.data.escalations || []);
+      })
+      .catch((err) => {
+        console.error("Failed to load on-call:", err);
+        toast.error(nt.loadFailed);
+      })
+      .finally(() => setLoading(false));
+  }, [nt.loadFailed]);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const handleAck = async (id: number) => {
+    setAcking(id);
+    try {
+      await ackEscalation(id);
+      toast.success(nt.escalationAcked);
+      load();
+    } catch (err) {
+      console.error("Failed to ack escalation:", err);
+      toast.error(nt.saveFailed);
+    } finally {
+      setAcking(null);
+    }
+  };
+
+  return (
+    <div className="bg-card rounded-xl border border-[var(--border-color)] overflow-hidden">
+      {/* 头部 */}
+      <div className="flex items-center gap-3 px-6 py-4 border-b border-[var(--border-color)]">
+        <div className="w-10 h-10 rounded-lg bg-gray-100 dark:bg-gray-800 flex items-center justify-center">
+          <PhoneCall className="w-5 h-5 text-gray-600 dark:text-gray-400" />
+        </div>
+        <div>
+          <h3 className="font-medium text-default">{nt.oncall}</h3>
+          <p className="text-sm text-muted">{nt.oncallHint}</p>
+        </div>
+      </div>
+
+      <div className="px-6 py-4 space-y-4">
+        {loading ? (
+          <div className="flex justify-center py-4">
+            <Loader2 className="w-5 h-5 animate-spin text-muted" />
+          </div>
+        ) : (
+          <>
+            {/* 当前值班 */}
+            {schedules.length === 0 ? (
+              <p className="text-sm text-muted text-center py-2">{nt.oncallEmpty}</p>
+            ) : (
+              <ul className="divide-y divide-[var(--border-color)]">
+                {schedules.map((s) => (
+                  <li key={s.id} className="flex items-center justify-between gap-4 py-3">
+                    <div className="min-w-0">
+                      <p className="text-sm text-default truncate">
+                        {s.name} <span className="text-xs text-muted">({s.rotation})</span>
+                      </p>
+                      <p className="text-xs text-muted truncate">
+                        {nt.oncallPrimary}: {userLabel(s.current?.primary)}
+                        {s.current?.overrideId ? ` (${nt.oncallOverride})` : ""} · {nt.oncallSecondary}:{" "}
+                        {userLabel(s.current?.secondary)}
+                      </p>
+                    </div>
+                    {s.current?.shiftEndsAt && (
+                      <span className="text-xs text-muted flex-shrink-0">
+                        {nt.oncallShiftEnds}: {new Date(s.current.shiftEndsAt).toLocaleString()}
+                      </span>
+                    )}
+                  </li>
+                ))}
+              </ul>
+            )}
+
+            {/* 待确认告警 */}
+            <div>
+              <p className="text-sm font-medium text-default mb-1">{nt.escalationsOpen}</p>
+              {escalations.length === 0 ? (
+                <p className="text-sm text-muted text-center py-2">{nt.escalationEmpty}</p>
+              ) : (
+                <ul className="divide-y divide-[var(--border-color)]">
+                  {escalations.map((e) => (
+                    <li key={e.id} className="flex items-center justify-between gap-4 py-3">
+                      <div className="min-w-0">
+                        <p className="text-sm text-default truncate">{e.title}</p>
+                        <p className="text-xs text-muted truncate">
+                          {e.severity} · {new Date(e.createdAt).toLocaleString()} · {nt.escalationLevel}: {e.level}
+                        </p>
+                      </div>
+                      <button
+                        onClick={() => handleAck(e.id)}
+                        disabled={acking === e.id}
+                        className="px-3 py-1 text-xs rounded-lg bg-purple-600 text-white hover:bg-purple-700 disabled:opacity-50 transition-colors flex items-center gap-1 flex-shrink-0"
+                      >
+                        {acking === e.id && <Loader2 className="w-3 h-3 animate-spin" />}
+                        {nt.escalationAck}
+                      </button>
+                    </li>
+                  ))}
+                </ul>
+              )}
+            </div>
+          </>
+        )}
+      </div>
+    </div>
+  );
+}
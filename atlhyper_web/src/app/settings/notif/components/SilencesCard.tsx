@@ -0,0 +1,209 @@
+"use client";
+
+import { useState, useEffect, useCallback } from "react";
+import { BellOff, Loader2 } from "lucide-react";
+import { useI18n } from "@/i18n/context";
+import { toast } from "@/components/common/Toast";
+import {
+  listSilences,
+  createSilence,
+  expireSilence,
+  mockSilences,
+  type Matchers,
+  type NotifySilence,
+} from "@/api/notify";
+
+interface SilencesCardProps {
+  readOnly: boolean;
+}
+
+// 时长选项（分钟）
+const DURATION_OPTIONS = [30, 60, 120, 240, 480, 1440];
+
+// 解析 "cluster=prod namespace=batch" 为匹配器
+function parseMatchers(input: string): Matchers | null {
+  const matchers: Matchers = {};
+  for (const part of input.trim().split(/\s+/)) {
+    if (!part) continue;
+    const idx = part.indexOf("=");
+    if (idx <= 0 || idx === part.length - 1) return null;
+    matchers[part.slice(0, idx)] = part.slice(idx + 1);
+  }
+  return Object.keys(matchers).length > 0 ? matchers : null;
+}
+
+function formatMatchers(matchers: Matchers): string {
+  return Object.entries(matchers)
+    .map(([k, v]) => `${k}=${v}`)
+    .join(" ");
+}
+
+function formatDuration(minutes: number): string {
+  return minutes >= 60 ? `${minutes / 60}h` : `${minutes}m`;
+}
+
+export function SilencesCard({ readOnly }: SilencesCardProps) {
+  const { t } = useI18n();
+  const nt = t.notifications;
+
+  const [silences, setSilences] = useState<NotifySilence[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [matchersInput, setMatchersInput] = useState("");
+  const [duration, setDuration] = useState(60);
+  const [comment, setComment] = useState("");
+  const [creating, setCreating] = useState(false);
+
+  const load = useCallback(() => {
+    if (readOnly) {
+      setSilences(mockSilences);
+      setLoading(false);
+      return;
+    }
+    listSilences()
+      .then((res) => setSilences(res.data.silences || []))
+      .catch((err) => {
+        console.error("Failed to load silences:", err);
+        toast.error(nt.loadFailed);
+      })
+      .finally(() => setLoading(false));
+  }, [readOnly, nt.loadFailed]);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const handleCreate = async () => {
+    if (readOnly) return;
+    const matchers = parseMatchers(matchersInput);
+    if (!matchers) {
+      toast.error(nt.silenceInvalidMatchers);
+      return;
+    }
+    setCreating(true);
+    try {
+      await createSilence({ matchers, durationMinutes: duration, comment });
+      toast.success(nt.silenceCreated);
+      setMatchersInput("");
+      setComment("");
+      load();
+    } catch (err) {
+      console.error("Failed to create silence:", err);
+      toast.error(nt.saveFailed);
+    } finally {
+      setCreating(false);
+    }
+  };
+
+  const handleExpire = async (id: number) => {
+    if (readOnly) return;
+    try {
+      await expireSilence(id);
+      toast.success(nt.silenceExpired);
+      load();
+    } catch (err) {
+      console.error("Failed to expire silence:", err);
+      toast.error(nt.saveFailed);
+    }
+  };
+
+  return (
+    <div className="bg-card rounded-xl border border-[var(--border-color)] overflow-hidden">
+      {/* 头部 */}
+      <div className="flex items-center gap-3 px-6 py-4 border-b border-[var(--border-color)]">
+        <div className="w-10 h-10 rounded-lg bg-gray-100 dark:bg-gray-800 flex items-center justify-center">
+          <BellOff className="w-5 h-5 text-gray-600 dark:text-gray-400" />
+        </div>
+        <div>
+          <h3 className="font-medium text-default">{nt.silences}</h3>
+          <p className="text-sm text-muted">{nt.silencesHint}</p>
+        </div>
+      </div>
+
+      {/* 创建表单 */}
+      {!readOnly && (
+        <div className="px-6 py-4 grid gap-3 md:grid-cols-[2fr_auto_1fr_auto] items-end border-b border-[var(--border-color)]">
+          <div>
+            <label className="block text-sm font-medium text-default mb-2">{nt.silenceMatchers}</label>
+            <input
+              type="text"
+              value={matchersInput}
+              onChange={(e) => setMatchersInput(e.target.value)}
+              placeholder={nt.silenceMatchersPlaceholder}
+              className="w-full px-3 py-2 rounded-lg border text-sm font-mono bg-[var(--bg-primary)] text-default border-[var(--border-color)] focus:outline-none focus:ring-2 focus:ring-purple-500/50"
+            />
+          </div>
+          <div>
+            <label className="block text-sm font-medium text-default mb-2">{nt.silenceDuration}</label>
+            <select
+              value={duration}
+              onChange={(e) => setDuration(Number(e.target.value))}
+              className="px-3 py-2 rounded-lg border text-sm bg-[var(--bg-primary)] text-default border-[var(--border-color)]"
+            >
+              {DURATION_OPTIONS.map((m) => (
+                <option key={m} value={m}>
+                  {formatDuration(m)}
+                </option>
+              ))}
+            </select>
+          </div>
+          <div>
+            <label className="block text-sm font-medium text-default mb-2">{nt.silenceComment}</label>
+            <input
+              type="text"
+              value={comment}
+              onChange={(e) => setComment(e.target.value)}
+              className="w-full px-3 py-2 rounded-lg border text-sm bg-[var(--bg-primary)] text-default border-[var(--border-color)] focus:outline-none focus:ring-2 focus:ring-purple-500/50"
+            />
+          </div>
+          <button
+            onClick={handleCreate}
+            disabled={creating || !matchersInput.trim()}
+            className="px-4 py-2 text-sm rounded-lg bg-purple-600 text-white hover:bg-purple-700 disabled:opacity-50 disabled:cursor-not-allowed transition-colors flex items-center gap-2"
+          >
+            {creating && <Loader2 className="w-4 h-4 animate-spin" />}
+            {nt.silenceCreate}
+          </button>
+        </div>
+      )}
+
+      {/* 列表 */}
+      <div className="px-6 py-4">
+        {loading ? (
+          <div className="flex justify-center py-4">
+            <Loader2 className="w-5 h-5 animate-spin text-muted" />
+          </div>
+        ) : silences.length === 0 ? (
+          <p className="text-sm text-muted text-center py-4">{nt.silenceEmpty}</p>
+        ) : (
+          <ul className="divide-y divide-[var(--border-color)]">
+            {silences.map((s) => (
+              <li key={s.id} className="flex items-center justify-between gap-4 py-3">
+                <div className="min-w-0">
+                  <p className="text-sm font-mono text-default truncate">{formatMatchers(s.matchers)}</p>
+                  <p className="text-xs text-muted">
+                    {nt.silenceEndsAt}: {new Date(s.endsAt).toLocaleString()}
+                    {s.createdBy && ` · ${s.createdBy}`}
+                    {s.comment && ` · ${s.comment}`}
+                  </p>
+                </div>
+                <div className="flex items-center gap-3 flex-shrink-0">
+                  <span className={`text-xs ${s.active ? "text-green-600" : "text-muted"}`}>
+                    {s.active ? nt.silenceActive : nt.silenceInactive}
+                  </span>
+                  {s.active && !readOnly && (
+                    <button
+                      onClick={() => handleExpire(s.id)}
+                      className="px-3 py-1 text-xs rounded-lg border border-[var(--border-color)] text-default hover:bg-[var(--bg-secondary)] transition-colors"
+                    >
+                      {nt.silenceExpire}
+                    </button>
+                  )}
+                </div>
+              </li>
+            ))}
+          </ul>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,196 @@
+"use client";
+
+import { useState, useEffect, useCallback } from "react";
+import { FileText, Loader2 } from "lucide-react";
+import { useI18n } from "@/i18n/context";
+import { toast } from "@/components/common/Toast";
+import {
+  listTemplates,
+  saveTemplate,
+  resetTemplate,
+  previewTemplate,
+  type NotifyTemplate,
+  type TemplatePreview,
+} from "@/api/notify";
+
+// 提取服务端返回的错误信息（模板校验错误）
+function errorMessage(err: unknown): string {
+  const e = err as { response?: { data?: { error?: string } }; message?: string };
+  return e?.response?.data?.error || e?.message || String(err);
+}
+
+function templateKey(t: NotifyTemplate): string {
+  return `${t.name}/${t.variant}`;
+}
+
+export function TemplatesCard() {
+  const { t } = useI18n();
+  const nt = t.notifications;
+
+  const [templates, setTemplates] = useState<NotifyTemplate[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [selected, setSelected] = useState("");
+  const [body, setBody] = useState("");
+  const [preview, setPreview] = useState<TemplatePreview | null>(null);
+  const [busy, setBusy] = useState(false);
+
+  const current = templates.find((tpl) => templateKey(tpl) === selected);
+
+  const load = useCallback(() => {
+    listTemplates()
+      .then((res) => {
+        const items = res.data.templates || [];
+        setTemplates(items);
+        setSelected((prev) => prev || (items[0] ? templateKey(items[0]) : ""));
+      })
+      .catch((err) => {
+        console.error("Failed to load templates:", err);
+        toast.error(nt.loadFailed);
+      })
+      .finally(() => setLoading(false));
+  }, [nt.loadFailed]);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  useEffect(() => {
+    setBody(current?.body || "");
+    setPreview(null);
+  }, [current?.name, current?.variant, current?.body]);
+
+  const handlePreview = async () => {
+    if (!current) return;
+    setBusy(true);
+    try {
+      const res = await previewTemplate(current.name, current.variant, body);
+      setPreview(res.data);
+    } catch (err) {
+      toast.error(`${nt.templateInvalid}: ${errorMessage(err)}`);
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleSave = async () => {
+    if (!current) return;
+    setBusy(true);
+    try {
+      await saveTemplate(current.name, current.variant, body);
+      toast.success(nt.templateSaved);
+      load();
+    } catch (err) {
+      toast.error(`${nt.templateInvalid}: ${errorMessage(err)}`);
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleReset = async () => {
+    if (!current) return;
+    setBusy(true);
+    try {
+      await resetTemplate(current.name, current.variant);
+      toast.success(nt.templateResetDone);
+      load();
+    } catch (err) {
+      console.error("Failed to reset template:", err);
+      toast.error(nt.saveFailed);
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <div className="bg-card rounded-xl border border-[var(--border-color)] overflow-hidden">
+      {/* 头部 */}
+      <div className="flex items-center justify-between gap-3 px-6 py-4 border-b border-[var(--border-color)]">
+        <div className="flex items-center gap-3">
+          <div className="w-10 h-10 rounded-lg bg-gray-100 dark:bg-gray-800 flex items-center justify-center">
+            <FileText className="w-5 h-5 text-gray-600 dark:text-gray-400" />
+          </div>
+          <div>
+            <h3 className="font-medium text-default">{nt.templates}</h3>
+            <p className="text-sm text-muted">{nt.templatesHint}</p>
+          </div>
+        </div>
+        <select
+          value={selected}
+          onChange={(e) => setSelected(e.target.value)}
+          className="px-3 py-2 rounded-lg border text-sm font-mono bg-[var(--bg-primary)] text-default border-[var(--border-color)]"
+        >
+          {templates.map((tpl) => (
+            <option key={templateKey(tpl)} value={templateKey(tpl)}>
+              {templateKey(tpl)}
+              {tpl.customized ? ` (${nt.templateCustomized})` : ""}
+            </option>
+          ))}
+        </select>
+      </div>
+
+      {/* 编辑器 */}
+      <div className="px-6 py-4 space-y-3">
+        {loading ? (
+          <div className="flex justify-center py-4">
+            <Loader2 className="w-5 h-5 animate-spin text-muted" />
+          </div>
+        ) : (
+          <>
+            <textarea
+              value={body}
+              onChange={(e) => setBody(e.target.value)}
+              rows={14}
+              spellCheck={false}
+              className="w-full px-3 py-2 rounded-lg border text-xs font-mono bg-[var(--bg-primary)] text-default border-[var(--border-color)] focus:outline-none focus:ring-2 focus:ring-purple-500/50"
+            />
+            <div className="flex items-center justify-between gap-3">
+              <p className="text-xs text-muted">
+                {current?.customized && current.updatedAt
+                  ? `${nt.templateCustomized} · ${current.updatedBy || "-"} · ${new Date(current.updatedAt).toLocaleString()}`
+                  : ""}
+              </p>
+              <div className="flex items-center gap-2">
+                {busy && <Loader2 className="w-4 h-4 animate-spin text-muted" />}
+                <button
+                  onClick={handlePreview}
+                  disabled={busy || !current}
+                  className="px-3 py-1.5 text-sm rounded-lg border border-[var(--border-color)] text-default hover:bg-[var(--bg-secondary)] disabled:opacity-50 transition-colors"
+                >
+                  {nt.templatePreview}
+                </button>
+                {current?.customized && (
+                  <button
+                    onClick={handleReset}
+                    disabled={busy}
+                    className="px-3 py-1.5 text-sm rounded-lg border border-[var(--border-color)] text-default hover:bg-[var(--bg-secondary)] disabled:opacity-50 transition-colors"
+                  >
+                    {nt.templateReset}
+                  </button>
+                )}
+                <button
+                  onClick={handleSave}
+                  disabled={busy || !current || !body.trim() || body === current.body}
+                  className="px-3 py-1.5 text-sm rounded-lg bg-purple-600 text-white hover:bg-purple-700 disabled:opacity-50 disabled:cursor-not-allowed transition-colors"
+                >
+                  {nt.templateSave}
+                </button>
+              </div>
+            </div>
+
+            {/* 预览（HTML 变体在沙箱 iframe 中展示） */}
+            {preview && (
+              <div className="rounded-lg border border-[var(--border-color)] p-3">
+                <p className="text-sm font-medium text-default mb-2">{preview.subject}</p>
+                {preview.html ? (
+                  <iframe sandbox="" srcDoc={preview.body} className="w-full h-80 bg-white rounded" />
+                ) : (
+                  <pre className="text-xs whitespace-pre-wrap font-mono text-default">{preview.body}</pre>
+                )}
+              </div>
+            )}
+          </>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -1,3 +1,7 @@
 export { SlackCard } from "./SlackCard";
 export { EmailCard } from "./EmailCard";
 export { TagInput, emailValidator } from "./TagInput";
+export { SilencesCard } from "./SilencesCard";
+export { DeliveriesCard } from "./DeliveriesCard";
+export { TemplatesCard } from "./TemplatesCard";
+export { OncallCard } from "./OncallCard";
@@ -9,7 +9,7 @@ import { useAuthStore } from "@/store/authStore";
 import { AlertTriangle, Eye } from "lucide-react";
 import { UserRole } from "@/types/auth";
 
-import { SlackCard, EmailCard } from "./components";
+import { SlackCard, EmailCard, SilencesCard, DeliveriesCard, TemplatesCard, OncallCard } from "./components";
 import {
   listChannels,
   updateSlack,
@@ -29,6 +29,8 @@ export default function NotificationsPage() {
   // 权限判断：Operator 即可查看和修改
   const hasPermission = isAuthenticated && user && user.role >= UserRole.OPERATOR;
   const isDemo = !hasPermission;
+  // 投递记录与死信重发需要 Admin 权限
+  const isAdmin = isAuthenticated && user && user.role >= UserRole.ADMIN;
 
   // 状态
   const [loading, setLoading] = useState(true);
@@ -103,25 +105,31 @@ export default function NotificationsPage() {
 
   // 测试 Slack
   const handleTestSlack = useCallback(async () => {
-    const result = await testChannel("slack");
+    const channel = getSlackChannel();
+    const result = channel
+      ? await testChannel(channel.id)
+      : { success: false, message: "channel not found" };
     if (result.success) {
       toast.success(result.message);
     } else {
       toast.error(result.message);
     }
     return result;
-  }, []);
+  }, [getSlackChannel]);
 
   // 测试 Email
   const handleTestEmail = useCallback(async () => {
-    const result = await testChannel("email");
+    const channel = getEmailChannel();
+    const result = channel
+      ? await testChannel(channel.id)
+      : { success: false, message: "channel not found" };
     if (result.success) {
       toast.success(result.message);
     } else {
       toast.error(result.message);
     }
     return result;
-  }, []);
+  }, [getEmailChannel]);
 
   // 渲染
   const slackChannel = getSlackChannel();
@@ -189,6 +197,18 @@ export default function NotificationsPage() {
             />
           </div>
         )}
+
+        {/* 告警静默 */}
+        {!loading && <SilencesCard readOnly={isDemo} />}
+
+        {/* 值班与待确认告警 */}
+        {!loading && !isDemo && <OncallCard />}
+
+        {/* 投递记录（失败重试 / 死信） */}
+        {!loading && (isDemo || isAdmin) && <DeliveriesCard readOnly={isDemo} />}
+
+        {/* 通知模板（需要 Admin 权限） */}
+        {!loading && isAdmin && <TemplatesCard />}
       </div>
     </Layout>
   );
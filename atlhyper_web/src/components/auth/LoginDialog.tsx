@@ -1,11 +1,11 @@
 "use client";
 
-import { useState } from "react";
-import { X, LogIn, Loader2 } from "lucide-react";
+import { useEffect, useState } from "react";
+import { X, LogIn, Loader2, KeyRound } from "lucide-react";
 import { useI18n } from "@/i18n/context";
 import { useAuthStore } from "@/store/authStore";
 import { useClusterStore } from "@/store/clusterStore";
-import { login } from "@/api/auth";
+import { login, getOIDCConfig, startOIDCLogin } from "@/api/auth";
 
 export function LoginDialog() {
   const { t } = useI18n();
@@ -16,6 +16,28 @@ export function LoginDialog() {
   const [password, setPassword] = useState("");
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState("");
+  const [sso, setSso] = useState<{ enabled: boolean; localAdminOnly: boolean }>({ enabled: false, localAdminOnly: false });
+  const [ssoLoading, setSsoLoading] = useState(false);
+
+  useEffect(() => {
+    if (!isLoginDialogOpen) return;
+    getOIDCConfig()
+      .then((res) => setSso(res.data.data))
+      .catch(() => setSso({ enabled: false, localAdminOnly: false }));
+  }, [isLoginDialogOpen]);
+
+  // SSO 登录：跳转身份提供方，回调页 /auth/oidc/callback 完成登录
+  const handleSSO = async () => {
+    setError("");
+    setSsoLoading(true);
+    try {
+      const res = await startOIDCLogin();
+      window.location.href = res.data.data.authUrl;
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t.login.ssoFailed);
+      setSsoLoading(false);
+    }
+  };
 
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
@@ -83,6 +105,32 @@ export function LoginDialog() {
               </div>
             )}
 
+            {sso.enabled && (
+              <>
+                <button
+                  type="button"
+                  onClick={handleSSO}
+                  disabled={ssoLoading}
+                  className="w-full py-3 bg-primary hover:bg-primary-hover text-white font-medium rounded-lg transition-colors flex items-center justify-center gap-2 disabled:opacity-50 disabled:cursor-not-allowed"
+                >
+                  {ssoLoading ? (
+                    <Loader2 className="w-5 h-5 animate-spin" />
+                  ) : (
+                    <KeyRound className="w-5 h-5" />
+                  )}
+                  {t.login.ssoLogin}
+                </button>
+                <div className="flex items-center gap-3 text-xs text-muted">
+                  <div className="flex-1 border-t border-[var(--border-color)]" />
+                  {t.login.orLocalLogin}
+                  <div className="flex-1 border-t border-[var(--border-color)]" />
+                </div>
+                {sso.localAdminOnly && (
+                  <p className="text-xs text-muted">{t.login.localAdminOnly}</p>
+                )}
+              </>
+            )}
+
             <div>
               <label className="block text-sm font-medium text-secondary mb-1">
                 {t.common.username}
@@ -387,5 +387,5 @@ export function getServiceAccountDetail(params: { ClusterID: string; Namespace:
 // ============================================================
 
 export { restartPod } from "@/api/pod";
-export { cordonNode, uncordonNode } from "@/api/node";
+export { cordonNode, uncordonNode, drainNode } from "@/api/node";
 export { scaleDeployment, restartDeployment, updateDeploymentImage } from "@/api/deployment";
@@ -8,7 +8,7 @@ import { getDataSourceMode } from "@/config/data-source";
 import * as mock from "@/mock/overview";
 import * as api from "@/api/overview";
 
-export async function getClusterOverview(params: { cluster_id: string }) {
+export async function getClusterOverview(params: { cluster_id: string; at?: string }) {
   if (getDataSourceMode("overview") === "mock") {
     return mock.mockGetClusterOverview();
   }
@@ -619,6 +619,8 @@ export const ja: Translations = {
     nodeReady: "ノード準備完了",
     podHealthy: "Pod 健全性",
     nodes: "ノード",
+    viewingAt: "{time} 時点のクラスタ状態を表示中（履歴スナップショット）",
+    backToLive: "リアルタイムに戻る",
     clusterAvgCpu: "クラスタ平均 CPU",
     clusterAvgMem: "クラスタ平均メモリ",
     alerts: "アラート",
@@ -820,6 +822,54 @@ export const ja: Translations = {
     tagInputPlaceholder: "入力して Enter で追加",
     tagInputDuplicate: "既に存在します",
     tagInputInvalidFormat: "形式が無効です",
+    // 告警静默
+    silences: "アラートサイレンス",
+    silencesHint: "サイレンス中は一致するアラートを送信しません",
+    silenceMatchers: "マッチ条件",
+    silenceMatchersPlaceholder: "cluster=prod namespace=batch severity=warning,critical",
+    silenceDuration: "期間",
+    silenceComment: "コメント",
+    silenceCreate: "サイレンスを作成",
+    silenceCreated: "サイレンスを作成しました",
+    silenceExpire: "終了",
+    silenceExpired: "サイレンスを終了しました",
+    silenceActive: "有効",
+    silenceInactive: "終了済み",
+    silenceEndsAt: "終了時刻",
+    silenceEmpty: "サイレンスはありません",
+    silenceInvalidMatchers: "マッチ条件は label=value 形式でスペース区切りで入力してください",
+    deliveries: "配信履歴",
+    deliveriesHint: "送信に失敗した通知は自動で再試行され、上限に達するとデッドレターになります（手動で再送できます）",
+    deliveryEmpty: "配信履歴はありません",
+    deliveryAttempts: "試行回数",
+    deliveryResend: "再送",
+    deliveryResent: "再送キューに追加しました",
+    deliveryStatusAll: "すべて",
+    deliveryStatusSent: "送信済み",
+    deliveryStatusPending: "送信中",
+    deliveryStatusFailed: "再試行待ち",
+    deliveryStatusDead: "デッドレター",
+    templates: "通知テンプレート",
+    templatesHint: "カスタムテンプレートは組み込みテンプレートを上書きします。保存時にサンプルデータで検証されます。Email は HTML とプレーンテキストの両方を送信します",
+    templateCustomized: "カスタム",
+    templateSave: "保存",
+    templateSaved: "テンプレートを保存しました",
+    templateReset: "デフォルトに戻す",
+    templateResetDone: "組み込みテンプレートに戻しました",
+    templatePreview: "プレビュー",
+    templateInvalid: "テンプレートの検証に失敗しました",
+    oncall: "オンコールとエスカレーション",
+    oncallHint: "現在のオンコール担当。未確認のアラートはポリシーに従って順にエスカレーションされます",
+    oncallEmpty: "オンコールスケジュールはありません",
+    oncallPrimary: "プライマリ",
+    oncallSecondary: "セカンダリ",
+    oncallOverride: "代理",
+    oncallShiftEnds: "交代時刻",
+    escalationsOpen: "未確認のアラート",
+    escalationEmpty: "未確認のアラートはありません",
+    escalationLevel: "通知済みレベル",
+    escalationAck: "確認",
+    escalationAcked: "確認しました。エスカレーション通知を停止しました",
   },
   login: {
     title: "ログイン",
@@ -833,6 +883,11 @@ export const ja: Translations = {
     invalidCredentials: "ユーザー名またはパスワードが間違っています",
     sessionExpired: "セッションが期限切れです。再度ログインしてください",
     pleaseLogin: "ログインしてください",
+    ssoLogin: "SSO でログイン",
+    ssoRedirecting: "SSO ログインを完了しています...",
+    ssoFailed: "SSO ログイン失敗",
+    orLocalLogin: "またはローカルアカウント",
+    localAdminOnly: "ローカルアカウントでのログインは管理者のみ（緊急用）",
   },
   confirm: {
     defaultTitle: "操作の確認",
@@ -1859,6 +1914,48 @@ Deployment が更新され、新しい Pod が起動中です
     duration: "継続時間",
     recurrence: "再発回数",
     affectedEntities: "影響エンティティ",
+    clusterStateAtStart: "インシデント開始時のクラスタ状態を表示",
+    incidentAck: "確認",
+    incidentAssign: "アサイン",
+    incidentAssigneePlaceholder: "ユーザー名を入力",
+    incidentComment: "コメント",
+    incidentCommentPlaceholder: "コメントまたはメモを追加…",
+    incidentResolve: "解決",
+    incidentFalsePositive: "誤検知としてマーク",
+    incidentAckedBy: "確認者",
+    incidentAssignedTo: "担当者",
+    incidentActionFailed: "操作に失敗しました",
+    postmortem: "事後レビュー",
+    postmortemGenerate: "レビューを生成",
+    postmortemRegenerate: "再生成",
+    postmortemRegenerateConfirm: "再生成すると現在の内容（手動編集を含む）が上書きされます。続行しますか？",
+    postmortemEdit: "編集",
+    postmortemSave: "保存",
+    postmortemCancel: "キャンセル",
+    postmortemEmpty: "事後レビューはまだ生成されていません",
+    postmortemSourceAI: "AI 支援",
+    postmortemSourceTemplate: "テンプレート",
+    postmortemUpdatedBy: "最終更新",
+    postmortemFailed: "事後レビューの操作に失敗しました",
+    runbook: "Runbook",
+    runbookRun: "実行",
+    runbookRunConfirm: "選択した Runbook を実行しますか？コマンドがクラスタに送信されます",
+    runbookDryRun: "ドライラン",
+    runbookSelect: "Runbook を選択",
+    runbookRefresh: "更新",
+    runbookEmpty: "実行履歴はありません",
+    runbookApprove: "承認",
+    runbookReject: "却下",
+    runbookRejectReason: "却下理由（任意）",
+    runbookApprovedBy: "承認者",
+    runbookTriggerAuto: "自動マッチ",
+    runbookTriggerManual: "手動実行",
+    runbookStatusPending: "承認待ち",
+    runbookStatusRunning: "実行中",
+    runbookStatusSucceeded: "成功",
+    runbookStatusFailed: "失敗",
+    runbookStatusRejected: "却下済み",
+    runbookFailed: "Runbook の操作に失敗しました",
     timeline: "タイムライン",
     role: "役割",
     state: {
@@ -1902,6 +1999,11 @@ Deployment が更新され、新しい Pod が起動中です
       root_cause_identified: "根本原因特定",
       recovery_started: "復旧開始",
       recurrence: "再発",
+      acknowledged: "確認",
+      assigned: "アサイン",
+      comment: "コメント",
+      manual_resolved: "手動解決",
+      false_positive: "誤検知",
     },
     dependencyGraph: "依存関係グラフ",
     nodeDetail: "ノード詳細",
@@ -619,6 +619,8 @@ export const zh: Translations = {
     nodeReady: "节点就绪",
     podHealthy: "Pod 健康",
     nodes: "节点",
+    viewingAt: "正在查看 {time} 的集群状态（历史快照）",
+    backToLive: "返回实时",
     clusterAvgCpu: "集群平均 CPU",
     clusterAvgMem: "集群平均内存",
     alerts: "告警",
@@ -820,6 +822,54 @@ export const zh: Translations = {
     tagInputPlaceholder: "输入后按 Enter 添加",
     tagInputDuplicate: "已存在",
     tagInputInvalidFormat: "格式无效",
+    // 告警静默
+    silences: "告警静默",
+    silencesHint: "静默期间匹配的告警不会发送",
+    silenceMatchers: "匹配条件",
+    silenceMatchersPlaceholder: "cluster=prod namespace=batch severity=warning,critical",
+    silenceDuration: "时长",
+    silenceComment: "备注",
+    silenceCreate: "创建静默",
+    silenceCreated: "静默已创建",
+    silenceExpire: "结束",
+    silenceExpired: "静默已结束",
+    silenceActive: "生效中",
+    silenceInactive: "已结束",
+    silenceEndsAt: "结束时间",
+    silenceEmpty: "暂无静默",
+    silenceInvalidMatchers: "匹配条件格式应为 label=value，多个条件用空格分隔",
+    deliveries: "投递记录",
+    deliveriesHint: "发送失败的通知会自动重试，重试耗尽后进入死信，可手动重发",
+    deliveryEmpty: "暂无投递记录",
+    deliveryAttempts: "尝试次数",
+    deliveryResend: "重发",
+    deliveryResent: "已加入重发队列",
+    deliveryStatusAll: "全部",
+    deliveryStatusSent: "已发送",
+    deliveryStatusPending: "发送中",
+    deliveryStatusFailed: "等待重试",
+    deliveryStatusDead: "死信",
+    templates: "通知模板",
+    templatesHint: "自定义模板覆盖内置模板，保存时使用示例数据校验；Email 同时发送 HTML 与纯文本",
+    templateCustomized: "已自定义",
+    templateSave: "保存",
+    templateSaved: "模板已保存",
+    templateReset: "恢复默认",
+    templateResetDone: "已恢复内置模板",
+    templatePreview: "预览",
+    templateInvalid: "模板校验失败",
+    oncall: "值班与告警升级",
+    oncallHint: "当前值班人员；未确认的告警按升级策略依次通知",
+    oncallEmpty: "暂无值班表",
+    oncallPrimary: "主值班",
+    oncallSecondary: "副值班",
+    oncallOverride: "替班",
+    oncallShiftEnds: "交接时间",
+    escalationsOpen: "待确认告警",
+    escalationEmpty: "暂无待确认告警",
+    escalationLevel: "已通知层级",
+    escalationAck: "确认",
+    escalationAcked: "已确认，升级通知已停止",
   },
   login: {
     title: "登录",
@@ -833,6 +883,11 @@ export const zh: Translations = {
     invalidCredentials: "用户名或密码错误",
     sessionExpired: "会话已过期，请重新登录",
     pleaseLogin: "请先登录",
+    ssoLogin: "使用 SSO 登录",
+    ssoRedirecting: "正在完成 SSO 登录...",
+    ssoFailed: "SSO 登录失败",
+    orLocalLogin: "或使用本地账号",
+    localAdminOnly: "本地账号登录仅限管理员（应急入口）",
   },
   confirm: {
     defaultTitle: "确认操作",
@@ -1859,6 +1914,48 @@ Deployment 已更新，新的 Pod 正在启动中
     duration: "持续时间",
     recurrence: "复发次数",
     affectedEntities: "受影响实体",
+    clusterStateAtStart: "查看事件开始时的集群状态",
+    incidentAck: "确认",
+    incidentAssign: "指派",
+    incidentAssigneePlaceholder: "输入用户名",
+    incidentComment: "评论",
+    incidentCommentPlaceholder: "添加评论或备注…",
+    incidentResolve: "解决",
+    incidentFalsePositive: "标记为误报",
+    incidentAckedBy: "确认人",
+    incidentAssignedTo: "处理人",
+    incidentActionFailed: "操作失败",
+    postmortem: "复盘文档",
+    postmortemGenerate: "生成复盘",
+    postmortemRegenerate: "重新生成",
+    postmortemRegenerateConfirm: "重新生成将覆盖当前内容（包括人工编辑），是否继续？",
+    postmortemEdit: "编辑",
+    postmortemSave: "保存",
+    postmortemCancel: "取消",
+    postmortemEmpty: "尚未生成复盘文档",
+    postmortemSourceAI: "AI 辅助撰写",
+    postmortemSourceTemplate: "模板生成",
+    postmortemUpdatedBy: "最后编辑",
+    postmortemFailed: "复盘操作失败",
+    runbook: "Runbook",
+    runbookRun: "执行",
+    runbookRunConfirm: "确认执行所选 Runbook？指令将下发到集群",
+    runbookDryRun: "演练",
+    runbookSelect: "选择 Runbook",
+    runbookRefresh: "刷新",
+    runbookEmpty: "暂无执行记录",
+    runbookApprove: "批准",
+    runbookReject: "拒绝",
+    runbookRejectReason: "拒绝原因（可选）",
+    runbookApprovedBy: "审批人",
+    runbookTriggerAuto: "自动匹配",
+    runbookTriggerManual: "手动触发",
+    runbookStatusPending: "待审批",
+    runbookStatusRunning: "执行中",
+    runbookStatusSucceeded: "成功",
+    runbookStatusFailed: "失败",
+    runbookStatusRejected: "已拒绝",
+    runbookFailed: "Runbook 操作失败",
     timeline: "时间线",
     role: "角色",
     state: {
@@ -1902,6 +1999,11 @@ Deployment 已更新，新的 Pod 正在启动中
       root_cause_identified: "根因识别",
       recovery_started: "开始恢复",
       recurrence: "复发",
+      acknowledged: "确认",
+      assigned: "指派",
+      comment: "评论",
+      manual_resolved: "人工解决",
+      false_positive: "标记误报",
     },
     dependencyGraph: "依赖关系图",
     nodeDetail: "节点详情",
@@ -648,6 +648,8 @@ export interface OverviewTranslations {
   nodeReady: string;
   podHealthy: string;
   nodes: string;
+  viewingAt: string;
+  backToLive: string;
   clusterAvgCpu: string;
   clusterAvgMem: string;
   alerts: string;
@@ -861,6 +863,54 @@ export interface NotificationsTranslations {
   tagInputPlaceholder: string;
   tagInputDuplicate: string;
   tagInputInvalidFormat: string;
+  // 告警静默
+  silences: string;
+  silencesHint: string;
+  silenceMatchers: string;
+  silenceMatchersPlaceholder: string;
+  silenceDuration: string;
+  silenceComment: string;
+  silenceCreate: string;
+  silenceCreated: string;
+  silenceExpire: string;
+  silenceExpired: string;
+  silenceActive: string;
+  silenceInactive: string;
+  silenceEndsAt: string;
+  silenceEmpty: string;
+  silenceInvalidMatchers: string;
+  deliveries: string;
+  deliveriesHint: string;
+  deliveryEmpty: string;
+  deliveryAttempts: string;
+  deliveryResend: string;
+  deliveryResent: string;
+  deliveryStatusAll: string;
+  deliveryStatusSent: string;
+  deliveryStatusPending: string;
+  deliveryStatusFailed: string;
+  deliveryStatusDead: string;
+  templates: string;
+  templatesHint: string;
+  templateCustomized: string;
+  templateSave: string;
+  templateSaved: string;
+  templateReset: string;
+  templateResetDone: string;
+  templatePreview: string;
+  templateInvalid: string;
+  oncall: string;
+  oncallHint: string;
+  oncallEmpty: string;
+  oncallPrimary: string;
+  oncallSecondary: string;
+  oncallOverride: string;
+  oncallShiftEnds: string;
+  escalationsOpen: string;
+  escalationEmpty: string;
+  escalationLevel: string;
+  escalationAck: string;
+  escalationAcked: string;
 }
 
 // Login 页面翻译
@@ -876,6 +926,11 @@ export interface LoginTranslations {
   invalidCredentials: string;
   sessionExpired: string;
   pleaseLogin: string;
+  ssoLogin: string;
+  ssoRedirecting: string;
+  ssoFailed: string;
+  orLocalLogin: string;
+  localAdminOnly: string;
 }
 
 // Confirm Dialog 翻译
@@ -1914,6 +1969,50 @@ export interface AIOpsTranslations {
   duration: string;
   recurrence: string;
   affectedEntities: string;
+  clusterStateAtStart: string;
+  // 事件人工处理
+  incidentAck: string;
+  incidentAssign: string;
+  incidentAssigneePlaceholder: string;
+  incidentComment: string;
+  incidentCommentPlaceholder: string;
+  incidentResolve: string;
+  incidentFalsePositive: string;
+  incidentAckedBy: string;
+  incidentAssignedTo: string;
+  incidentActionFailed: string;
+  // 事件复盘
+  postmortem: string;
+  postmortemGenerate: string;
+  postmortemRegenerate: string;
+  postmortemRegenerateConfirm: string;
+  postmortemEdit: string;
+  postmortemSave: string;
+  postmortemCancel: string;
+  postmortemEmpty: string;
+  postmortemSourceAI: string;
+  postmortemSourceTemplate: string;
+  postmortemUpdatedBy: string;
+  postmortemFailed: string;
+  runbook: string;
+  runbookRun: string;
+  runbookRunConfirm: string;
+  runbookDryRun: string;
+  runbookSelect: string;
+  runbookRefresh: string;
+  runbookEmpty: string;
+  runbookApprove: string;
+  runbookReject: string;
+  runbookRejectReason: string;
+  runbookApprovedBy: string;
+  runbookTriggerAuto: string;
+  runbookTriggerManual: string;
+  runbookStatusPending: string;
+  runbookStatusRunning: string;
+  runbookStatusSucceeded: string;
+  runbookStatusFailed: string;
+  runbookStatusRejected: string;
+  runbookFailed: string;
   timeline: string;
   role: string;
 
@@ -1965,6 +2064,11 @@ export interface AIOpsTranslations {
     root_cause_identified: string;
     recovery_started: string;
     recurrence: string;
+    acknowledged: string;
+    assigned: string;
+    comment: string;
+    manual_resolved: string;
+    false_positive: string;
   };
 
   // 拓扑图
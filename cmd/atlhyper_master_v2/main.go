@@ -4,9 +4,13 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
 
 	"AtlHyper/atlhyper_master_v2"
+	"AtlHyper/atlhyper_master_v2/aiops/replay"
 	"AtlHyper/atlhyper_master_v2/config"
 	"AtlHyper/common/logger"
 )
@@ -14,6 +18,12 @@ import (
 var log = logger.Module("Master")
 
 func main() {
+	// 子命令: 离线回放（不加载配置、不启动服务）
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		runReplay(os.Args[2:])
+		return
+	}
+
 	// 加载配置（优先，日志配置也在其中）
 	config.LoadConfig()
 
@@ -38,3 +48,16 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runReplay 执行 AIOps 离线回放子命令
+func runReplay(args []string) {
+	// 回放期间的状态转换日志写到 stderr，仅保留警告以上
+	logger.Init(logger.Config{Level: "warn", Output: os.Stderr})
+
+	if err := replay.RunCLI(args, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "replay:", err)
+		}
+		os.Exit(2)
+	}
+}
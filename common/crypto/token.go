@@ -0,0 +1,38 @@
+// common/crypto/token.go
+// Agent 凭证生成与校验工具
+// Master 签发明文 Token（仅返回一次），数据库只保存 SHA-256 摘要
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// AgentTokenPrefix Agent Token 前缀，便于在日志/配置中识别
+const AgentTokenPrefix = "ath_"
+
+// GenerateAgentToken 生成随机 Agent Token
+// 格式: "ath_" + 64 位十六进制（32 字节随机数）
+func GenerateAgentToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return AgentTokenPrefix + hex.EncodeToString(buf), nil
+}
+
+// HashToken 计算 Token 的 SHA-256 摘要（十六进制）
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyTokenHash 常量时间比较 Token 与已存储的摘要
+func VerifyTokenHash(token, hash string) bool {
+	if token == "" || hash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(HashToken(token)), []byte(hash)) == 1
+}
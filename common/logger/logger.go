@@ -9,11 +9,12 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var (
-	defaultLogger *slog.Logger
+	defaultLogger atomic.Pointer[slog.Logger]
 	once          sync.Once
 )
 
@@ -33,6 +34,13 @@ func Init(cfg Config) {
 }
 
 func initLogger(cfg Config) {
+	l := newLogger(cfg)
+	defaultLogger.Store(l)
+	slog.SetDefault(l)
+}
+
+// newLogger 按配置创建日志器
+func newLogger(cfg Config) *slog.Logger {
 	// 解析日志级别
 	level := parseLevel(cfg.Level)
 
@@ -62,8 +70,7 @@ func initLogger(cfg Config) {
 		handler = slog.NewTextHandler(output, opts)
 	}
 
-	defaultLogger = slog.New(handler)
-	slog.SetDefault(defaultLogger)
+	return slog.New(handler)
 }
 
 func parseLevel(s string) slog.Level {
@@ -79,73 +86,96 @@ func parseLevel(s string) slog.Level {
 	}
 }
 
-// 确保日志器已初始化
-func ensureInit() {
-	if defaultLogger == nil {
-		Init(Config{})
+// current 返回当前全局日志器（未调用 Init 时使用默认配置，之后的 Init 仍会生效）
+func current() *slog.Logger {
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+	if l := newLogger(Config{}); defaultLogger.CompareAndSwap(nil, l) {
+		slog.SetDefault(l)
 	}
+	return defaultLogger.Load()
 }
 
 // Module 创建带模块标签的日志器
+// 模块日志器通常在包初始化时创建，早于 main() 中的 Init，因此按需绑定到当前全局日志器
 func Module(name string) *ModuleLogger {
-	ensureInit()
-	return &ModuleLogger{
-		name:   name,
-		logger: defaultLogger.With("module", name),
-	}
+	return &ModuleLogger{name: name}
 }
 
 // ModuleLogger 模块日志器
 type ModuleLogger struct {
-	name   string
+	name  string
+	attrs []any // With / WithContext 附加的字段
+
+	bound atomic.Pointer[boundLogger]
+}
+
+// boundLogger 绑定到某个全局日志器的模块日志器
+type boundLogger struct {
+	base   *slog.Logger
 	logger *slog.Logger
 }
 
+// logger 返回绑定到当前全局日志器的 slog.Logger（全局日志器变化时重新绑定）
+func (m *ModuleLogger) logger() *slog.Logger {
+	base := current()
+	if b := m.bound.Load(); b != nil && b.base == base {
+		return b.logger
+	}
+	l := base.With("module", m.name)
+	if len(m.attrs) > 0 {
+		l = l.With(m.attrs...)
+	}
+	m.bound.Store(&boundLogger{base: base, logger: l})
+	return l
+}
+
 // Debug 调试日志（周期性任务成功、详细追踪）
 func (m *ModuleLogger) Debug(msg string, args ...any) {
-	m.logger.Debug(msg, args...)
+	m.logger().Debug(msg, args...)
 }
 
 // Info 信息日志（关键业务事件、状态变化）
 func (m *ModuleLogger) Info(msg string, args ...any) {
-	m.logger.Info(msg, args...)
+	m.logger().Info(msg, args...)
 }
 
 // Warn 警告日志（可恢复的异常）
 func (m *ModuleLogger) Warn(msg string, args ...any) {
-	m.logger.Warn(msg, args...)
+	m.logger().Warn(msg, args...)
 }
 
 // Error 错误日志（需要关注的错误）
 func (m *ModuleLogger) Error(msg string, args ...any) {
-	m.logger.Error(msg, args...)
+	m.logger().Error(msg, args...)
 }
 
 // With 添加上下文字段
 func (m *ModuleLogger) With(args ...any) *ModuleLogger {
 	return &ModuleLogger{
-		name:   m.name,
-		logger: m.logger.With(args...),
+		name:  m.name,
+		attrs: append(append([]any(nil), m.attrs...), args...),
 	}
 }
 
 // WithContext 从 context 中提取追踪信息
 func (m *ModuleLogger) WithContext(ctx context.Context) *ModuleLogger {
-	newLogger := m.logger
+	attrs := append([]any(nil), m.attrs...)
 
 	// 提取 request_id
 	if reqID := ctx.Value(CtxKeyRequestID); reqID != nil {
-		newLogger = newLogger.With("request_id", reqID)
+		attrs = append(attrs, "request_id", reqID)
 	}
 
 	// 提取 user_id
 	if userID := ctx.Value(CtxKeyUserID); userID != nil {
-		newLogger = newLogger.With("user_id", userID)
+		attrs = append(attrs, "user_id", userID)
 	}
 
 	return &ModuleLogger{
-		name:   m.name,
-		logger: newLogger,
+		name:  m.name,
+		attrs: attrs,
 	}
 }
 
@@ -171,26 +201,22 @@ func ContextWithUserID(ctx context.Context, userID int64) context.Context {
 
 // Debug 全局调试日志
 func Debug(msg string, args ...any) {
-	ensureInit()
-	defaultLogger.Debug(msg, args...)
+	current().Debug(msg, args...)
 }
 
 // Info 全局信息日志
 func Info(msg string, args ...any) {
-	ensureInit()
-	defaultLogger.Info(msg, args...)
+	current().Info(msg, args...)
 }
 
 // Warn 全局警告日志
 func Warn(msg string, args ...any) {
-	ensureInit()
-	defaultLogger.Warn(msg, args...)
+	current().Warn(msg, args...)
 }
 
 // Error 全局错误日志
 func Error(msg string, args ...any) {
-	ensureInit()
-	defaultLogger.Error(msg, args...)
+	current().Error(msg, args...)
 }
 
 // ----- 辅助函数 -----
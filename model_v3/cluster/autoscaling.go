@@ -0,0 +1,47 @@
+package cluster
+
+import (
+	"time"
+
+	model_v3 "AtlHyper/model_v3"
+)
+
+// HorizontalPodAutoscaler K8s HPA 资源模型 (autoscaling/v2)
+type HorizontalPodAutoscaler struct {
+	model_v3.CommonMeta
+	ScaleTargetKind string              `json:"scaleTargetKind"`
+	ScaleTargetName string              `json:"scaleTargetName"`
+	MinReplicas     int32               `json:"minReplicas"`
+	MaxReplicas     int32               `json:"maxReplicas"`
+	CurrentReplicas int32               `json:"currentReplicas"`
+	DesiredReplicas int32               `json:"desiredReplicas"`
+	Metrics         []HPAMetric         `json:"metrics,omitempty"`
+	Conditions      []WorkloadCondition `json:"conditions,omitempty"`
+	LastScaleTime   *time.Time          `json:"lastScaleTime,omitempty"`
+}
+
+// HPAMetric HPA 扩缩容指标（目标值与当前值）
+type HPAMetric struct {
+	Type       string `json:"type"`                // Resource / ContainerResource / Pods / Object / External
+	Name       string `json:"name"`                // cpu / memory / 自定义指标名
+	Container  string `json:"container,omitempty"` // ContainerResource 的容器名
+	TargetType string `json:"targetType"`          // Utilization / AverageValue / Value
+	Target     string `json:"target"`              // 如 "80%"、"500m"
+	Current    string `json:"current,omitempty"`   // 当前值，格式同 Target
+}
+
+// IsAtMaxReplicas 当前副本数已达 maxReplicas 上限
+func (h *HorizontalPodAutoscaler) IsAtMaxReplicas() bool {
+	return h.MaxReplicas > 0 && h.CurrentReplicas >= h.MaxReplicas
+}
+
+// IsScalingLimited HPA 想继续扩容但被 maxReplicas 限制
+// 对应 condition ScalingLimited=True, reason=TooManyReplicas
+func (h *HorizontalPodAutoscaler) IsScalingLimited() bool {
+	for _, c := range h.Conditions {
+		if c.Type == "ScalingLimited" && c.Status == "True" && c.Reason == "TooManyReplicas" {
+			return true
+		}
+	}
+	return false
+}
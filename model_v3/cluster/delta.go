@@ -0,0 +1,339 @@
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================================
+// 增量快照
+// ============================================================
+//
+// Agent 首次上报及定期全量同步时推送完整 ClusterSnapshot，
+// 其余周期只推送与上一次已推送快照之间的差异 (SnapshotDelta)。
+//
+// 版本链:
+//   - 每次推送（全量或增量）Agent 都递增快照版本号 Version
+//   - 增量携带 BaseVersion，Master 仅在当前快照版本等于 BaseVersion 时应用
+//   - 版本不连续（Master 重启、推送丢失）返回 ErrDeltaBaseMismatch，Agent 改为全量推送
+
+// ErrDeltaBaseMismatch 增量的基准版本与当前快照不一致
+var ErrDeltaBaseMismatch = errors.New("snapshot delta base version mismatch")
+
+// ChangeOp 资源变更类型
+type ChangeOp string
+
+const (
+	ChangeAdded   ChangeOp = "added"
+	ChangeUpdated ChangeOp = "updated"
+	ChangeDeleted ChangeOp = "deleted"
+)
+
+// ResourceChange 单个资源对象的变更
+type ResourceChange struct {
+	Kind      string          `json:"kind"`
+	Op        ChangeOp        `json:"op"`
+	Namespace string          `json:"namespace,omitempty"`
+	Name      string          `json:"name"`
+	Object    json.RawMessage `json:"object,omitempty"` // added/updated 时为完整对象
+}
+
+// SnapshotDelta 增量快照
+type SnapshotDelta struct {
+	ClusterID   string           `json:"clusterId"`
+	BaseVersion uint64           `json:"baseVersion"`
+	Version     uint64           `json:"version"`
+	FetchedAt   time.Time        `json:"fetchedAt"`
+	Changes     []ResourceChange `json:"changes,omitempty"`
+
+	// OTel 为周期聚合数据，每次整体替换
+	OTel *OTelSnapshot `json:"otel,omitempty"`
+}
+
+// SnapshotIndex 已推送快照的内容索引: kind → "namespace/name" → 对象内容哈希
+// Agent 只保留哈希，无需持有上一份完整快照
+type SnapshotIndex map[string]map[string]uint64
+
+// deltaKind 单一资源类型的索引 / 差异 / 应用实现
+type deltaKind struct {
+	kind  string
+	index func(s *ClusterSnapshot) (map[string]uint64, error)
+	diff  func(prev map[string]uint64, s *ClusterSnapshot) ([]ResourceChange, map[string]uint64, error)
+	apply func(s *ClusterSnapshot, changes []ResourceChange) error
+
+	// filter 按对象键保留对象（FilterNamespaces 使用）
+	filter func(s *ClusterSnapshot, keep func(namespace, name string) bool)
+}
+
+// deltaKinds 参与增量同步的资源类型（顺序即 Changes 输出顺序）
+var deltaKinds = []deltaKind{
+	newDeltaKind("Pod", func(s *ClusterSnapshot) *[]Pod { return &s.Pods },
+		func(o *Pod) (string, string) { return o.Summary.Namespace, o.Summary.Name }),
+	newDeltaKind("Node", func(s *ClusterSnapshot) *[]Node { return &s.Nodes },
+		func(o *Node) (string, string) { return "", o.Summary.Name }),
+	newDeltaKind("Deployment", func(s *ClusterSnapshot) *[]Deployment { return &s.Deployments },
+		func(o *Deployment) (string, string) { return o.Summary.Namespace, o.Summary.Name }),
+	newDeltaKind("StatefulSet", func(s *ClusterSnapshot) *[]StatefulSet { return &s.StatefulSets },
+		func(o *StatefulSet) (string, string) { return o.Summary.Namespace, o.Summary.Name }),
+	newDeltaKind("DaemonSet", func(s *ClusterSnapshot) *[]DaemonSet { return &s.DaemonSets },
+		func(o *DaemonSet) (string, string) { return o.Summary.Namespace, o.Summary.Name }),
+	newDeltaKind("ReplicaSet", func(s *ClusterSnapshot) *[]ReplicaSet { return &s.ReplicaSets },
+		func(o *ReplicaSet) (string, string) { return o.Namespace, o.Name }),
+	newDeltaKind("Job", func(s *ClusterSnapshot) *[]Job { return &s.Jobs },
+		func(o *Job) (string, string) { return o.Namespace, o.Name }),
+	newDeltaKind("CronJob", func(s *ClusterSnapshot) *[]CronJob { return &s.CronJobs },
+		func(o *CronJob) (string, string) { return o.Namespace, o.Name }),
+	newDeltaKind("HorizontalPodAutoscaler", func(s *ClusterSnapshot) *[]HorizontalPodAutoscaler { return &s.HPAs },
+		func(o *HorizontalPodAutoscaler) (string, string) { return o.Namespace, o.Name }),
+	newDeltaKind("Service", func(s *ClusterSnapshot) *[]Service { return &s.Services },
+		func(o *Service) (string, string) { return o.Summary.Namespace, o.Summary.Name }),
+	newDeltaKind("Ingress", func(s *ClusterSnapshot) *[]Ingress { return &s.Ingresses },
+		func(o *Ingress) (string, string) { return o.Summary.Namespace, o.Summary.Name }),
+	newDeltaKind("Namespace", func(s *ClusterSnapshot) *[]Namespace { return &s.Namespaces },
+		func(o *Namespace) (string, string) { return "", o.Summary.Name }),
+	newDeltaKind("ConfigMap", func(s *ClusterSnapshot) *[]ConfigMap { return &s.ConfigMaps },
+		func(o *ConfigMap) (string, string) { return o.Namespace, o.Name }),
+	newDeltaKind("Secret", func(s *ClusterSnapshot) *[]Secret { return &s.Secrets },
+		func(o *Secret) (string, string) { return o.Namespace, o.Name }),
+	newDeltaKind("ResourceQuota", func(s *ClusterSnapshot) *[]ResourceQuota { return &s.ResourceQuotas },
+		func(o *ResourceQuota) (string, string) { return o.Namespace, o.Name }),
+	newDeltaKind("LimitRange", func(s *ClusterSnapshot) *[]LimitRange { return &s.LimitRanges },
+		func(o *LimitRange) (string, string) { return o.Namespace, o.Name }),
+	newDeltaKind("NetworkPolicy", func(s *ClusterSnapshot) *[]NetworkPolicy { return &s.NetworkPolicies },
+		func(o *NetworkPolicy) (string, string) { return o.Namespace, o.Name }),
+	newDeltaKind("ServiceAccount", func(s *ClusterSnapshot) *[]ServiceAccount { return &s.ServiceAccounts },
+		func(o *ServiceAccount) (string, string) { return o.Namespace, o.Name }),
+	newDeltaKind("PersistentVolume", func(s *ClusterSnapshot) *[]PersistentVolume { return &s.PersistentVolumes },
+		func(o *PersistentVolume) (string, string) { return "", o.Name }),
+	newDeltaKind("PersistentVolumeClaim", func(s *ClusterSnapshot) *[]PersistentVolumeClaim { return &s.PersistentVolumeClaims },
+		func(o *PersistentVolumeClaim) (string, string) { return o.Namespace, o.Name }),
+	newDeltaKind("Event", func(s *ClusterSnapshot) *[]Event { return &s.Events },
+		func(o *Event) (string, string) { return o.Namespace, o.Name }),
+}
+
+// IndexSnapshot 为快照建立内容索引（全量推送后调用）
+func IndexSnapshot(s *ClusterSnapshot) (SnapshotIndex, error) {
+	idx := make(SnapshotIndex, len(deltaKinds))
+	for _, k := range deltaKinds {
+		m, err := k.index(s)
+		if err != nil {
+			return nil, err
+		}
+		idx[k.kind] = m
+	}
+	return idx, nil
+}
+
+// DiffSnapshot 计算当前快照相对已推送索引的变更，同时返回当前快照的索引
+//
+// 对象按 "namespace/name" 匹配，序列化内容不同即视为更新。
+// 内容比较（而非 K8s resourceVersion）可以覆盖 Agent 侧补全的字段，
+// 如 Node 的 Pod 数、Namespace 的资源统计。
+func DiffSnapshot(prev SnapshotIndex, cur *ClusterSnapshot) ([]ResourceChange, SnapshotIndex, error) {
+	var changes []ResourceChange
+	idx := make(SnapshotIndex, len(deltaKinds))
+	for _, k := range deltaKinds {
+		c, m, err := k.diff(prev[k.kind], cur)
+		if err != nil {
+			return nil, nil, err
+		}
+		changes = append(changes, c...)
+		idx[k.kind] = m
+	}
+	return changes, idx, nil
+}
+
+// ApplyDelta 将增量应用到快照，返回新快照（不修改原快照，读者可继续安全使用旧快照）
+//
+// 基准版本不一致时返回 ErrDeltaBaseMismatch。
+func (s *ClusterSnapshot) ApplyDelta(d *SnapshotDelta) (*ClusterSnapshot, error) {
+	if s.Version != d.BaseVersion {
+		return nil, fmt.Errorf("%w: current=%d, base=%d", ErrDeltaBaseMismatch, s.Version, d.BaseVersion)
+	}
+
+	byKind := make(map[string][]ResourceChange)
+	for _, c := range d.Changes {
+		byKind[c.Kind] = append(byKind[c.Kind], c)
+	}
+
+	next := *s
+	for _, k := range deltaKinds {
+		if changes, ok := byKind[k.kind]; ok {
+			if err := k.apply(&next, changes); err != nil {
+				return nil, err
+			}
+			delete(byKind, k.kind)
+		}
+	}
+	for kind := range byKind {
+		return nil, fmt.Errorf("unknown resource kind in delta: %s", kind)
+	}
+
+	next.Version = d.Version
+	next.FetchedAt = d.FetchedAt
+	if d.OTel != nil {
+		next.OTel = d.OTel
+	}
+	next.Summary = next.GenerateSummary()
+	return &next, nil
+}
+
+// FilterNamespaces 返回只包含允许命名空间对象的快照副本（不修改原快照）
+//
+// 集群级资源（Node / PersistentVolume）以 allow("") 判断；
+// Namespace 对象按其自身名称判断；OTel 为集群级数据，allow("") 为 false 时去除。
+func (s *ClusterSnapshot) FilterNamespaces(allow func(namespace string) bool) *ClusterSnapshot {
+	next := *s
+	for _, k := range deltaKinds {
+		keep := func(ns, _ string) bool { return allow(ns) }
+		if k.kind == "Namespace" {
+			keep = func(_, name string) bool { return allow(name) }
+		}
+		k.filter(&next, keep)
+	}
+	if !allow("") {
+		next.OTel = nil
+	}
+	next.Summary = next.GenerateSummary()
+	return &next
+}
+
+// newDeltaKind 基于切片字段和对象键构建 deltaKind
+func newDeltaKind[T any](kind string, field func(*ClusterSnapshot) *[]T, key func(*T) (string, string)) deltaKind {
+	objectKey := func(o *T) string {
+		ns, name := key(o)
+		return ns + "/" + name
+	}
+
+	index := func(s *ClusterSnapshot) (map[string]uint64, error) {
+		items := *field(s)
+		m := make(map[string]uint64, len(items))
+		for i := range items {
+			data, err := json.Marshal(&items[i])
+			if err != nil {
+				return nil, fmt.Errorf("marshal %s: %w", kind, err)
+			}
+			m[objectKey(&items[i])] = hashBytes(data)
+		}
+		return m, nil
+	}
+
+	diff := func(prev map[string]uint64, s *ClusterSnapshot) ([]ResourceChange, map[string]uint64, error) {
+		items := *field(s)
+		m := make(map[string]uint64, len(items))
+		var changes []ResourceChange
+		for i := range items {
+			data, err := json.Marshal(&items[i])
+			if err != nil {
+				return nil, nil, fmt.Errorf("marshal %s: %w", kind, err)
+			}
+			k := objectKey(&items[i])
+			h := hashBytes(data)
+			m[k] = h
+
+			old, existed := prev[k]
+			if existed && old == h {
+				continue
+			}
+			op := ChangeUpdated
+			if !existed {
+				op = ChangeAdded
+			}
+			ns, name := key(&items[i])
+			changes = append(changes, ResourceChange{Kind: kind, Op: op, Namespace: ns, Name: name, Object: data})
+		}
+
+		var deleted []string
+		for k := range prev {
+			if _, ok := m[k]; !ok {
+				deleted = append(deleted, k)
+			}
+		}
+		sort.Strings(deleted)
+		for _, k := range deleted {
+			ns, name := splitObjectKey(k)
+			changes = append(changes, ResourceChange{Kind: kind, Op: ChangeDeleted, Namespace: ns, Name: name})
+		}
+		return changes, m, nil
+	}
+
+	apply := func(s *ClusterSnapshot, changes []ResourceChange) error {
+		ptr := field(s)
+		old := *ptr
+		pos := make(map[string]int, len(old))
+		for i := range old {
+			pos[objectKey(&old[i])] = i
+		}
+
+		removed := make(map[int]bool)
+		updated := make(map[int]T)
+		var added []T
+		for _, c := range changes {
+			k := c.Namespace + "/" + c.Name
+			i, exists := pos[k]
+			if c.Op == ChangeDeleted {
+				if exists {
+					removed[i] = true
+				}
+				continue
+			}
+			var obj T
+			if err := json.Unmarshal(c.Object, &obj); err != nil {
+				return fmt.Errorf("unmarshal %s %s: %w", kind, k, err)
+			}
+			if exists {
+				updated[i] = obj
+				delete(removed, i)
+			} else {
+				added = append(added, obj)
+			}
+		}
+
+		// 复制切片，保持原快照不变
+		items := make([]T, 0, len(old)+len(added)-len(removed))
+		for i := range old {
+			if removed[i] {
+				continue
+			}
+			if obj, ok := updated[i]; ok {
+				items = append(items, obj)
+			} else {
+				items = append(items, old[i])
+			}
+		}
+		items = append(items, added...)
+		*ptr = items
+		return nil
+	}
+
+	filter := func(s *ClusterSnapshot, keep func(namespace, name string) bool) {
+		ptr := field(s)
+		items := make([]T, 0, len(*ptr))
+		for i := range *ptr {
+			if ns, name := key(&(*ptr)[i]); keep(ns, name) {
+				items = append(items, (*ptr)[i])
+			}
+		}
+		*ptr = items
+	}
+
+	return deltaKind{kind: kind, index: index, diff: diff, apply: apply, filter: filter}
+}
+
+// splitObjectKey 拆分 "namespace/name"
+func splitObjectKey(k string) (string, string) {
+	ns, name, _ := strings.Cut(k, "/")
+	return ns, name
+}
+
+// hashBytes 计算内容哈希
+func hashBytes(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
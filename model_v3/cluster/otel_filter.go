@@ -0,0 +1,103 @@
+package cluster
+
+import (
+	"AtlHyper/model_v3/apm"
+	"AtlHyper/model_v3/log"
+	"AtlHyper/model_v3/slo"
+)
+
+// FilterNamespaces 返回只包含允许命名空间数据的 OTel 快照副本（不修改原快照）
+//
+// allow("") 为 true（集群级授权）时原样返回。否则：
+//   - 标量摘要、节点指标、Ingress SLO（serviceKey 无法可靠拆出命名空间，含多窗口中的 Ingress 数据）为集群级数据，全部去除
+//   - APM 服务、拓扑节点、Mesh 服务（含多窗口）、APM 时序按自身命名空间过滤；拓扑边与 Mesh 边要求两端均可见
+//   - Trace、操作统计、SLO 时序、日志只带服务名，按 APM/Mesh 服务列表解析命名空间；
+//     服务名未知或同名服务出现在未授权命名空间时去除
+func (o *OTelSnapshot) FilterNamespaces(allow func(namespace string) bool) *OTelSnapshot {
+	if o == nil || allow("") {
+		return o
+	}
+
+	// 服务名 → 是否可见（同名服务跨命名空间时，任一不可见即不可见）
+	visible := make(map[string]bool)
+	mark := func(name, ns string) {
+		ok, seen := visible[name]
+		visible[name] = allow(ns) && (ok || !seen)
+	}
+	for _, s := range o.APMServices {
+		mark(s.Name, s.Namespace)
+	}
+	for _, s := range o.SLOServices {
+		mark(s.Name, s.Namespace)
+	}
+	serviceVisible := func(name string) bool { return visible[name] }
+
+	next := &OTelSnapshot{
+		APMServices:   filterItems(o.APMServices, func(s *apm.APMService) bool { return allow(s.Namespace) }),
+		SLOServices:   filterItems(o.SLOServices, func(s *slo.ServiceSLO) bool { return allow(s.Namespace) }),
+		APMOperations: filterItems(o.APMOperations, func(op *apm.OperationStats) bool { return serviceVisible(op.ServiceName) }),
+		RecentTraces: filterItems(o.RecentTraces, func(t *apm.TraceSummary) bool {
+			if !serviceVisible(t.RootService) {
+				return false
+			}
+			for _, s := range t.Services {
+				if !serviceVisible(s) {
+					return false
+				}
+			}
+			return true
+		}),
+		RecentLogs: filterItems(o.RecentLogs, func(l *log.Entry) bool {
+			if ns := l.Resource["k8s.namespace.name"]; ns != "" {
+				return allow(ns)
+			}
+			return serviceVisible(l.ServiceName)
+		}),
+		SLOTimeSeries: filterItems(o.SLOTimeSeries, func(s *SLOServiceTimeSeries) bool { return serviceVisible(s.ServiceName) }),
+		APMTimeSeries: filterItems(o.APMTimeSeries, func(s *APMServiceTimeSeries) bool { return allow(s.Namespace) }),
+	}
+
+	meshEdge := func(e *slo.ServiceEdge) bool { return allow(e.SrcNamespace) && allow(e.DstNamespace) }
+	next.SLOEdges = filterItems(o.SLOEdges, meshEdge)
+	if len(o.SLOWindows) > 0 {
+		next.SLOWindows = make(map[string]*slo.SLOWindowData, len(o.SLOWindows))
+		for key, w := range o.SLOWindows {
+			if w == nil {
+				continue
+			}
+			next.SLOWindows[key] = &slo.SLOWindowData{
+				MeshServices: filterItems(w.MeshServices, func(s *slo.ServiceSLO) bool { return allow(s.Namespace) }),
+				MeshEdges:    filterItems(w.MeshEdges, meshEdge),
+			}
+		}
+	}
+
+	if o.APMTopology != nil {
+		topo := &apm.Topology{}
+		ids := make(map[string]bool)
+		for _, n := range o.APMTopology.Nodes {
+			if allow(n.Namespace) {
+				topo.Nodes = append(topo.Nodes, n)
+				ids[n.Id] = true
+			}
+		}
+		for _, e := range o.APMTopology.Edges {
+			if ids[e.Source] && ids[e.Target] {
+				topo.Edges = append(topo.Edges, e)
+			}
+		}
+		next.APMTopology = topo
+	}
+	return next
+}
+
+// filterItems 返回满足 keep 的元素组成的新切片
+func filterItems[T any](items []T, keep func(*T) bool) []T {
+	var out []T
+	for i := range items {
+		if keep(&items[i]) {
+			out = append(out, items[i])
+		}
+	}
+	return out
+}
@@ -16,6 +16,9 @@ type ClusterSnapshot struct {
 	ClusterID string    `json:"clusterId"`
 	FetchedAt time.Time `json:"fetchedAt"`
 
+	// Version 快照版本号（Agent 每次推送递增，增量同步的基准，见 delta.go）
+	Version uint64 `json:"version,omitempty"`
+
 	// 工作负载
 	Pods         []Pod         `json:"pods"`
 	Deployments  []Deployment  `json:"deployments"`
@@ -25,6 +28,9 @@ type ClusterSnapshot struct {
 	Jobs         []Job         `json:"jobs,omitempty"`
 	CronJobs     []CronJob     `json:"cronJobs,omitempty"`
 
+	// 自动扩缩容
+	HPAs []HorizontalPodAutoscaler `json:"hpas,omitempty"`
+
 	// 网络
 	Services  []Service `json:"services"`
 	Ingresses []Ingress `json:"ingresses"`
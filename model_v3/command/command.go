@@ -25,8 +25,31 @@ type Result struct {
 	Error      string        `json:"error,omitempty"`
 	ExecTime   time.Duration `json:"execTime,omitempty"`
 	ExecutedAt time.Time     `json:"executedAt"`
+
+	// 长耗时指令（如 drain）的进度
+	// Partial=true 表示中间上报，指令仍在执行，Master 不据此结束指令
+	Partial  bool            `json:"partial,omitempty"`
+	Progress []ProgressEntry `json:"progress,omitempty"`
+}
+
+// ProgressEntry 单个操作对象的执行进度（如 drain 中的每个 Pod）
+type ProgressEntry struct {
+	Target  string    `json:"target"`            // 对象标识，如 "namespace/pod"
+	Status  string    `json:"status"`            // 见 Progress* 常量
+	Message string    `json:"message,omitempty"` // 附加说明（跳过原因、错误信息等）
+	Time    time.Time `json:"time"`
 }
 
+// 进度状态常量
+const (
+	ProgressPending  = "pending"  // 等待处理
+	ProgressEvicting = "evicting" // 驱逐中（含因 PDB 限制而重试）
+	ProgressEvicted  = "evicted"  // 已驱逐并完成退出
+	ProgressSkipped  = "skipped"  // 已跳过（DaemonSet / Mirror Pod）
+	ProgressBlocked  = "blocked"  // 被安全检查阻止（emptyDir / 无控制器）
+	ProgressFailed   = "failed"   // 失败或超时
+)
+
 // Status 指令状态查询
 type Status struct {
 	CommandID  string     `json:"commandId"`
@@ -58,6 +81,7 @@ const (
 	ActionDrain        = "drain"
 	ActionUpdateImage  = "update_image"
 	ActionGetLogs      = "get_logs"
+	ActionStreamLogs   = "stream_logs"
 	ActionGetConfigMap = "get_configmap"
 	ActionGetSecret    = "get_secret"
 	ActionDynamic        = "dynamic"
@@ -76,7 +100,7 @@ var ValidActions = map[string]bool{
 	ActionScale: true, ActionRestart: true, ActionDelete: true,
 	ActionDeletePod: true, ActionExec: true, ActionCordon: true,
 	ActionUncordon: true, ActionDrain: true, ActionUpdateImage: true,
-	ActionGetLogs: true, ActionGetConfigMap: true, ActionGetSecret: true,
+	ActionGetLogs: true, ActionStreamLogs: true, ActionGetConfigMap: true, ActionGetSecret: true,
 	ActionDynamic: true, ActionApplyManifests: true,
 	ActionQueryTraces: true, ActionQueryTraceDetail: true,
 	ActionQueryLogs: true, ActionQueryMetrics: true, ActionQuerySLO: true,
@@ -0,0 +1,52 @@
+package command
+
+// ExecFrame 交互式 exec 会话的消息帧
+//
+// 链路: Web UI ⇄ Gateway (WebSocket) ⇄ Master ⇄ AgentSDK (WebSocket) ⇄ Agent ⇄ remotecommand
+// 所有链路上都使用同一帧格式（JSON 文本消息）。
+type ExecFrame struct {
+	Type  string `json:"type"`           // 见 Frame* 常量
+	Data  string `json:"data,omitempty"` // stdin/stdout/stderr 内容
+	Cols  uint16 `json:"cols,omitempty"` // resize: 终端列数
+	Rows  uint16 `json:"rows,omitempty"` // resize: 终端行数
+	Code  int    `json:"code,omitempty"` // exit: 退出码
+	Error string `json:"error,omitempty"`
+
+	// 日志跟踪 (log 帧)
+	Source string `json:"source,omitempty"` // 日志来源，格式 "pod/container"
+	Time   string `json:"time,omitempty"`   // 日志时间戳 (RFC3339Nano)
+}
+
+// 帧类型常量
+const (
+	FrameStdin  = "stdin"  // 客户端 → 容器
+	FrameResize = "resize" // 客户端 → 容器：终端尺寸变化
+	FrameStdout = "stdout" // 容器 → 客户端
+	FrameStderr = "stderr" // 容器 → 客户端
+	FrameExit   = "exit"   // 容器进程退出（会话结束）
+	FrameError  = "error"  // 链路错误（会话结束）
+	FrameClose  = "close"  // 任一方主动关闭会话
+	FrameLog    = "log"    // Agent → 客户端：一行容器日志（日志跟踪会话）
+)
+
+// ExecParams ActionExec 指令参数（Master 下发给 Agent）
+type ExecParams struct {
+	SessionID      string   `json:"sessionId"`
+	Container      string   `json:"container,omitempty"`
+	Command        []string `json:"command,omitempty"`
+	TTY            bool     `json:"tty,omitempty"`
+	TimeoutSeconds int      `json:"timeoutSeconds,omitempty"`
+}
+
+// LogStreamParams ActionStreamLogs 指令参数（Master 下发给 Agent）
+//
+// 目标为 Pod 时跟踪其容器（Container 为空表示全部容器），
+// 目标为 Deployment 时跟踪其所有 Pod 的全部容器。
+// 多个来源按时间戳合并为一条 log 帧流，复用 exec 会话的流通道。
+type LogStreamParams struct {
+	SessionID      string `json:"sessionId"`
+	Container      string `json:"container,omitempty"`
+	TailLines      int64  `json:"tailLines,omitempty"`
+	SinceSeconds   int64  `json:"sinceSeconds,omitempty"`
+	TimeoutSeconds int    `json:"timeoutSeconds,omitempty"`
+}